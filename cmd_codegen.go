@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/codegen"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/workspace"
+)
+
+func init() {
+	summary := "Generate Go model structs from the filesystem representation of schemas"
+	desc := "Generates Go source code defining a struct for each table, for each schema " +
+		"dir that configures the codegen-dir option. Structs include db, gorm, and/or " +
+		"json struct tags, as configured by codegen-tags; nullable columns use " +
+		"database/sql Null* types or pointers, as configured by codegen-nullable; and " +
+		"enum columns get a string type with a constant for each value.\n\n" +
+		"The codegen-dir option is relative to each schema dir, and should typically be " +
+		"placed in a .skeema file alongside the schema option. Once configured, " +
+		"`skeema pull` also regenerates the Go code from the live schema, so that models " +
+		"never drift from the DDL.\n\n" +
+		"This command relies on accessing database instances to test the SQL DDL in a " +
+		"temporary location. See the --workspace option for more information.\n\n" +
+		"You may optionally pass an environment name as a CLI arg. This will affect " +
+		"which section of .skeema config files is used for workspace selection. If no " +
+		"environment name is supplied, the default is \"production\"."
+
+	cmd := mybase.NewCommand("codegen", summary, desc, CodegenHandler)
+	codegen.AddCommandOptions(cmd)
+	workspace.AddCommandOptions(cmd)
+	cmd.AddArg("environment", "production", false)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// CodegenHandler is the handler method for `skeema codegen`
+func CodegenHandler(cfg *mybase.Config) error {
+	dir, err := fs.ParseDir(".", cfg)
+	if err != nil {
+		return err
+	}
+	var count int
+	if err := codegenWalker(dir, &count, 5); err != nil {
+		return err
+	}
+	if count == 0 {
+		log.Warn("No Go code generated: the codegen-dir option is not configured for any schema dir")
+	}
+	return nil
+}
+
+func codegenWalker(dir *fs.Dir, count *int, maxDepth int) error {
+	if dir.ParseError != nil {
+		return NewExitValue(CodeBadConfig, "Unable to process %s: %s", dir.RelPath(), dir.ParseError)
+	}
+	if dir.Config.Get("codegen-dir") != "" && len(dir.LogicalSchemas) > 0 {
+		schemas, err := workspaceSchemas(dir)
+		if err != nil {
+			return err
+		}
+		for _, schema := range schemas {
+			if err := generateCode(dir, schema); err != nil {
+				return err
+			}
+			*count++
+		}
+	}
+
+	subdirs, err := dir.Subdirs()
+	if err != nil {
+		return NewExitValue(CodeFatalError, "Cannot list subdirs of %s: %s", dir, err)
+	} else if len(subdirs) > 0 && maxDepth <= 0 {
+		return NewExitValue(CodeFatalError, "Not walking subdirs of %s: max depth reached", dir)
+	}
+	for _, sub := range subdirs {
+		if err := codegenWalker(sub, count, maxDepth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateCode writes Go model structs for schema, if the codegen-dir option is
+// configured for dir. Otherwise, it does nothing.
+func generateCode(dir *fs.Dir, schema *tengo.Schema) error {
+	opts, err := codegen.OptionsForDir(dir)
+	if err != nil {
+		return NewExitValue(CodeBadConfig, err.Error())
+	} else if opts.Dir == "" {
+		return nil
+	}
+	src, err := codegen.Generate(schema, opts)
+	if err != nil {
+		return NewExitValue(CodeFatalError, "Unable to generate Go code for %s: %s", schema.Name, err)
+	}
+	if err := os.MkdirAll(opts.Dir, 0777); err != nil {
+		return NewExitValue(CodeCantCreate, "Unable to create directory %s: %s", opts.Dir, err)
+	}
+	path := filepath.Join(opts.Dir, codegen.FileName(schema.Name))
+	if err := os.WriteFile(path, src, 0666); err != nil {
+		return NewExitValue(CodeCantCreate, "Unable to write %s: %s", path, err)
+	}
+	log.Infof("Wrote %s -- Go models for %s", path, schema.Name)
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func init() {
+	summary := "Compare schemas between two environments' database servers"
+	desc := "Compares the live schemas of one environment's database instance(s) to " +
+		"those of another environment, for example production vs staging. The " +
+		"filesystem's *.sql files are not used; only the .skeema option files are " +
+		"read, to determine which instances and schemas to compare. The output is a " +
+		"series of DDL commands that, if run on the first environment's instance, " +
+		"would cause its schemas to match the second environment's.\n\n" +
+		"With --json, the differences are instead output as a JSON array, with one " +
+		"element per differing object.\n\n" +
+		"An exit code of 0 will be returned if no differences were found; 1 if some " +
+		"differences were found; or 2+ if an error occurred."
+
+	cmd := mybase.NewCommand("compare", summary, desc, CompareHandler)
+	cmd.AddOption(mybase.BoolOption("json", 0, false, "Output differences as JSON instead of DDL"))
+	cmd.AddArg("environment", "", true)
+	cmd.AddArg("to-environment", "", true)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// comparedObject describes one differing object in `skeema compare --json`
+// output.
+type comparedObject struct {
+	FromInstance string `json:"from_instance"`
+	FromSchema   string `json:"from_schema"`
+	ToInstance   string `json:"to_instance"`
+	ToSchema     string `json:"to_schema"`
+	ObjectType   string `json:"object_type"`
+	ObjectName   string `json:"object_name"`
+	DiffType     string `json:"diff_type"`
+	Statement    string `json:"statement,omitempty"`
+	Unsupported  bool   `json:"unsupported,omitempty"`
+}
+
+// CompareHandler is the handler method for `skeema compare`
+func CompareHandler(cfg *mybase.Config) error {
+	toEnvironment := cfg.Get("to-environment")
+	toCfg := cfg.Clone()
+	toCfg.SetRuntimeOverride("environment", toEnvironment)
+
+	fromDir, err := fs.ParseDir(".", cfg)
+	if err != nil {
+		return err
+	}
+	toDir, err := fs.ParseDir(".", toCfg)
+	if err != nil {
+		return err
+	}
+
+	var results []comparedObject
+	err = compareWalker(fromDir, toDir, &results, 5)
+	if err != nil && ExitCode(err) != CodePartialError {
+		return err
+	}
+	if cfg.GetBool("json") {
+		if results == nil {
+			results = []comparedObject{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(results); encErr != nil {
+			return encErr
+		}
+	}
+	if err != nil {
+		return err
+	} else if len(results) > 0 {
+		return NewExitValue(CodeDifferencesFound, "")
+	}
+	return nil
+}
+
+func compareWalker(fromDir, toDir *fs.Dir, results *[]comparedObject, maxDepth int) error {
+	for _, dir := range []*fs.Dir{fromDir, toDir} {
+		if dir.ParseError != nil {
+			log.Warnf("Skipping %s: %s", dir, dir.ParseError)
+			return NewExitValue(CodeBadConfig, "")
+		}
+	}
+
+	var err error
+	if fromDir.HasSchema() || toDir.HasSchema() {
+		err = compareDir(fromDir, toDir, results)
+	}
+
+	if maxDepth <= 0 {
+		return err
+	}
+	subdirs, subErr := fromDir.Subdirs()
+	if subErr != nil {
+		log.Errorf("Unable to list subdirs of %s: %s", fromDir, subErr)
+		return NewExitValue(CodeFatalError, "")
+	}
+	for _, fromSub := range subdirs {
+		toSub, subErr := toDir.Subdir(fromSub.BaseName())
+		if toSub == nil {
+			log.Errorf("Unable to parse %s for environment %q: %s", fromSub, toDir.Config.Get("environment"), subErr)
+			err = NewExitValue(CodePartialError, "")
+			continue
+		}
+		if walkErr := compareWalker(fromSub, toSub, results, maxDepth-1); ExitCode(walkErr) > ExitCode(err) {
+			err = walkErr
+		}
+	}
+	return err
+}
+
+// compareDir compares the schemas mapped to fromDir and toDir, appending any
+// differences to results. DDL is output to STDOUT unless --json was supplied.
+func compareDir(fromDir, toDir *fs.Dir, results *[]comparedObject) error {
+	fromInst, err := fromDir.FirstInstance()
+	if err != nil || fromInst == nil {
+		log.Errorf("Skipping %s: unable to connect to instance for environment %q: %v", fromDir, fromDir.Config.Get("environment"), err)
+		return NewExitValue(CodePartialError, "")
+	}
+	toInst, err := toDir.FirstInstance()
+	if err != nil || toInst == nil {
+		log.Errorf("Skipping %s: unable to connect to instance for environment %q: %v", toDir, toDir.Config.Get("environment"), err)
+		return NewExitValue(CodePartialError, "")
+	}
+	fromNames, err := fromDir.SchemaNames(fromInst)
+	if err != nil {
+		log.Errorf("Skipping %s: %s", fromDir, err)
+		return NewExitValue(CodePartialError, "")
+	}
+	toNames, err := toDir.SchemaNames(toInst)
+	if err != nil {
+		log.Errorf("Skipping %s: %s", toDir, err)
+		return NewExitValue(CodePartialError, "")
+	}
+	if len(fromNames) != len(toNames) {
+		log.Errorf("Skipping %s: environment %q maps to %d schemas, but environment %q maps to %d", fromDir, fromDir.Config.Get("environment"), len(fromNames), toDir.Config.Get("environment"), len(toNames))
+		return NewExitValue(CodePartialError, "")
+	}
+	sort.Strings(fromNames)
+	sort.Strings(toNames)
+
+	mods := tengo.StatementModifiers{
+		AllowUnsafe: true,
+		Flavor:      fromInst.Flavor(),
+	}
+	jsonOutput := fromDir.Config.GetBool("json")
+	for n := range fromNames {
+		fromSchema, err := compareLoadSchema(fromInst, fromNames[n], fromDir)
+		if err != nil {
+			log.Errorf("Skipping %s: %s", fromDir, err)
+			return NewExitValue(CodePartialError, "")
+		}
+		toSchema, err := compareLoadSchema(toInst, toNames[n], toDir)
+		if err != nil {
+			log.Errorf("Skipping %s: %s", toDir, err)
+			return NewExitValue(CodePartialError, "")
+		}
+		diff := tengo.NewSchemaDiff(fromSchema, toSchema)
+		objDiffs := diff.ObjectDiffs()
+		if len(objDiffs) == 0 {
+			log.Infof("%s %s vs %s %s: no differences", fromInst, fromNames[n], toInst, toNames[n])
+			continue
+		}
+		if !jsonOutput {
+			fmt.Printf("-- %s %s vs %s %s\n", fromInst, fromNames[n], toInst, toNames[n])
+			if fromSchema != nil {
+				fmt.Printf("USE %s;\n", tengo.EscapeIdentifier(fromNames[n]))
+			} else {
+				fmt.Printf("-- schema %s does not exist on %s\n", tengo.EscapeIdentifier(fromNames[n]), fromInst)
+			}
+		}
+		for _, od := range objDiffs {
+			key := od.ObjectKey()
+			result := comparedObject{
+				FromInstance: fromInst.String(),
+				FromSchema:   fromNames[n],
+				ToInstance:   toInst.String(),
+				ToSchema:     toNames[n],
+				ObjectType:   string(key.Type),
+				ObjectName:   key.Name,
+				DiffType:     od.DiffType().String(),
+			}
+			stmt, err := od.Statement(mods)
+			if tengo.IsUnsupportedDiff(err) {
+				result.Unsupported = true
+				log.Warnf("%s %s: unable to generate DDL for %s: %s", fromInst, fromNames[n], key, err)
+			} else if err != nil {
+				log.Errorf("%s %s: %s", fromInst, fromNames[n], err)
+				return NewExitValue(CodePartialError, "")
+			} else {
+				result.Statement = stmt
+			}
+			if !jsonOutput && stmt != "" {
+				fmt.Printf("%s;\n", stmt)
+			}
+			*results = append(*results, result)
+		}
+		if !jsonOutput {
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// compareLoadSchema introspects the named schema on inst, with dir's ignore
+// options applied. A nil schema and nil error are returned if the schema does
+// not exist.
+func compareLoadSchema(inst *tengo.Instance, name string, dir *fs.Dir) (*tengo.Schema, error) {
+	schema, err := inst.Schema(name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	schema.StripMatches(dir.IgnorePatterns)
+	return schema, nil
+}
@@ -18,7 +18,8 @@ func init() {
 		"\"production\".\n\n" +
 		"The `skeema diff` command is equivalent to running `skeema push` with its --dry-run option enabled.\n\n" +
 		"An exit code of 0 will be returned if no differences were found; 1 if some " +
-		"differences were found; or 2+ if an error occurred."
+		"differences were found; or 2+ if an error occurred, including use of unsupported " +
+		"features by any table when --strict-unsupported is enabled."
 
 	cmd := mybase.NewCommand("diff", summary, desc, DiffHandler)
 	cmd.AddArg("environment", "production", false)
@@ -44,15 +45,22 @@ func clonePushOptionsToDiff() {
 	}
 
 	descRewrites := map[string]string{
-		"allow-unsafe":    "Permit generating ALTER or DROP operations that are potentially destructive",
-		"alter-wrapper":   "Output ALTER TABLEs as shell commands rather than just raw DDL; see manual for template vars",
-		"brief":           "Don't output DDL to STDOUT; instead output list of instances with at least one difference",
-		"safe-below-size": "Always permit generating destructive operations for tables below this size in bytes",
+		"allow-unsafe":        "Permit generating ALTER or DROP operations that are potentially destructive",
+		"allow-unsafe-grants": "With --grants, permit generating REVOKE statements that remove account privileges",
+		"alter-wrapper":       "Output ALTER TABLEs as shell commands rather than just raw DDL; see manual for template vars",
+		"brief":               "Don't output DDL to STDOUT; instead output list of instances with at least one difference",
+		"safe-below-size":     "Always permit generating destructive operations for tables below this size in bytes",
 	}
 	hiddenRewrites := map[string]bool{
-		"brief":              false,
-		"dry-run":            true,
-		"foreign-key-checks": true,
+		"brief":               false,
+		"dry-run":             true,
+		"foreign-key-checks":  true,
+		"force-break-lock":    true,
+		"snapshot-dir":        true,
+		"version-table":       true,
+		"resume-file":         true,
+		"pre-statement-hook":  true,
+		"post-statement-hook": true,
 	}
 
 	diffOptions := diff.Options()
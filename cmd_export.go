@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/export"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/workspace"
+)
+
+func init() {
+	summary := "Convert the filesystem representation of schemas to an external format"
+	desc := "Converts the schemas defined by *.sql files in the current directory and " +
+		"its subdirectories to the format of an external design, documentation, or " +
+		"migration tool, selected via --format. Output is written to STDOUT unless " +
+		"--output is supplied. For formats which support it (such as dbt), an existing " +
+		"--output file is updated in-place, retaining content not derived from the schemas.\n\n" +
+		"This command relies on accessing database instances to test the SQL DDL in a " +
+		"temporary location. See the --workspace option for more information.\n\n" +
+		"You may optionally pass an environment name as a CLI arg. This will affect " +
+		"which section of .skeema config files is used for workspace selection. If no " +
+		"environment name is supplied, the default is \"production\"."
+
+	cmd := mybase.NewCommand("export", summary, desc, ExportHandler)
+	export.AddCommandOptions(cmd)
+	cmd.AddOptions("output",
+		mybase.StringOption("output", 0, "", "Write to this file instead of STDOUT"),
+	)
+	workspace.AddCommandOptions(cmd)
+	cmd.AddArg("environment", "production", false)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// ExportHandler is the handler method for `skeema export`
+func ExportHandler(cfg *mybase.Config) error {
+	dir, err := fs.ParseDir(".", cfg)
+	if err != nil {
+		return err
+	}
+	format := export.Lookup(cfg.Get("format"))
+	if format == nil || format.Export == nil {
+		return NewExitValue(CodeBadConfig, "Option --format must be one of: %s", strings.Join(export.Names(false), ", "))
+	}
+	opts, err := export.OptionsForDir(dir)
+	if err != nil {
+		return NewExitValue(CodeBadConfig, err.Error())
+	}
+
+	var schemas []*tengo.Schema
+	if err := exportWalker(dir, &schemas, 5); err != nil {
+		return err
+	}
+
+	// Formats supporting updates modify an existing output file in-place,
+	// retaining any content not derived from the schemas
+	if path := cfg.Get("output"); path != "" && format.Update != nil {
+		if existing, err := os.ReadFile(path); err == nil {
+			var buf bytes.Buffer
+			if err := format.Update(bytes.NewReader(existing), &buf, schemas, opts); err != nil {
+				return NewExitValue(CodeFatalError, "Unable to update %s: %s", path, err)
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0666); err != nil {
+				return NewExitValue(CodeCantCreate, "Unable to write %s: %s", path, err)
+			}
+			log.Infof("Updated %s with %s", path, countAndNoun(len(schemas), "schema", "schemas"))
+			return nil
+		} else if !os.IsNotExist(err) {
+			return NewExitValue(CodeNoInput, "Unable to read %s: %s", path, err)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if path := cfg.Get("output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return NewExitValue(CodeCantCreate, "Unable to create %s: %s", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := format.Export(w, schemas, opts); err != nil {
+		return NewExitValue(CodeFatalError, "Unable to export to %s: %s", format.Name, err)
+	}
+	log.Infof("Exported %s to %s format", countAndNoun(len(schemas), "schema", "schemas"), format.Name)
+	return nil
+}
+
+// exportWalker converts the logical schemas of dir and its subdirs into real
+// schemas using a workspace, appending them to schemas.
+func exportWalker(dir *fs.Dir, schemas *[]*tengo.Schema, maxDepth int) error {
+	dirSchemas, err := workspaceSchemas(dir)
+	if err != nil {
+		return err
+	}
+	*schemas = append(*schemas, dirSchemas...)
+
+	subdirs, err := dir.Subdirs()
+	if err != nil {
+		return NewExitValue(CodeFatalError, "Cannot list subdirs of %s: %s", dir, err)
+	} else if len(subdirs) > 0 && maxDepth <= 0 {
+		return NewExitValue(CodeFatalError, "Not walking subdirs of %s: max depth reached", dir)
+	}
+	for _, sub := range subdirs {
+		if err := exportWalker(sub, schemas, maxDepth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workspaceSchemas converts the logical schemas of dir (but not its subdirs)
+// into real schemas using a workspace. Objects matching the dir's ignore
+// patterns are stripped, and objects with SQL errors are skipped with a
+// warning.
+func workspaceSchemas(dir *fs.Dir) ([]*tengo.Schema, error) {
+	if dir.ParseError != nil {
+		return nil, NewExitValue(CodeBadConfig, "Unable to process %s: %s", dir.RelPath(), dir.ParseError)
+	}
+	if len(dir.LogicalSchemas) == 0 {
+		return nil, nil
+	}
+	inst, err := dir.FirstInstance()
+	if wsType, _ := dir.Config.GetEnum("workspace", "temp-schema", "docker"); wsType != "docker" || !dir.Config.Changed("flavor") {
+		if err != nil {
+			return nil, NewExitValue(CodeBadConfig, "Unable to process %s: %s", dir.RelPath(), err)
+		} else if inst == nil {
+			return nil, NewExitValue(CodeBadConfig, "This command needs either a host (with workspace=temp-schema) or flavor (with workspace=docker), but one is not configured for environment %q", dir.Config.Get("environment"))
+		}
+	}
+	wsOpts, err := workspace.OptionsForDir(dir, inst)
+	if err != nil {
+		return nil, NewExitValue(CodeBadConfig, "Unable to process %s: %s", dir.RelPath(), err)
+	}
+	schemas := make([]*tengo.Schema, 0, len(dir.LogicalSchemas))
+	for _, logicalSchema := range dir.LogicalSchemas {
+		wsSchema, err := workspace.ExecLogicalSchema(logicalSchema, wsOpts)
+		if err != nil {
+			return nil, NewExitValue(CodeFatalError, "Unable to process %s: %s", dir.RelPath(), err)
+		}
+		for _, failure := range wsSchema.Failures {
+			log.Warnf("Skipping %s: %s", failure.ObjectKey(), failure)
+		}
+		schema := wsSchema.Schema
+		schema.Name = exportSchemaName(dir, logicalSchema)
+		schema.StripMatches(dir.IgnorePatterns)
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// exportSchemaName returns the name to use for logicalSchema in exported
+// output. If the dir's schema option doesn't map to a single static name, the
+// dir's base name is used instead.
+func exportSchemaName(dir *fs.Dir, logicalSchema *fs.LogicalSchema) string {
+	if logicalSchema.Name != "" {
+		return logicalSchema.Name
+	}
+	if name := dir.Config.Get("schema"); name != "" && !strings.ContainsAny(name, "*,`") {
+		return name
+	}
+	return dir.BaseName()
+}
@@ -121,6 +121,7 @@ func formatDir(dir *fs.Dir) error {
 		dumpOpts := dumper.Options{
 			IncludeAutoInc: true,
 			CountOnly:      !dir.Config.GetBool("write"),
+			NameCaseMode:   wsOpts.NameCaseMode,
 		}
 		if dir.Config.GetBool("strip-partitioning") {
 			dumpOpts.Partitioning = tengo.PartitioningRemove
@@ -136,6 +137,9 @@ func formatDir(dir *fs.Dir) error {
 	for _, stmt := range dir.UnparsedStatements {
 		log.Debugf("%s: unable to parse statement", stmt.Location())
 	}
+	for _, stmt := range dir.MalformedStatements {
+		log.Warnf("Skipping %s: %s", stmt.ObjectKey(), stmt.Err)
+	}
 	if totalReformatCount > 0 {
 		return NewExitValue(CodeDifferencesFound, "")
 	}
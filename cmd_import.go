@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/export"
+	"github.com/skeema/skeema/internal/fs"
+)
+
+func init() {
+	summary := "Convert a document in an external format to *.sql files"
+	desc := "Converts a document from an external schema design tool, in the format " +
+		"selected via --format, into CREATE TABLE statements in *.sql files. Files are " +
+		"written to the directory specified by --dir, which defaults to the current " +
+		"directory. Existing files for the same table names are overwritten.\n\n" +
+		"Supported import formats: " + importFormatNames() + "."
+
+	cmd := mybase.NewCommand("import", summary, desc, ImportHandler)
+	export.AddCommandOptions(cmd)
+	cmd.AddOption(mybase.StringOption("dir", 'd', ".", "Directory to write *.sql files to"))
+	cmd.AddArg("file", "", true)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// importFormatNames is used in help text of the import command.
+func importFormatNames() string {
+	return strings.Join(export.Names(true), ", ")
+}
+
+// ImportHandler is the handler method for `skeema import`
+func ImportHandler(cfg *mybase.Config) error {
+	format := export.Lookup(cfg.Get("format"))
+	if format == nil || format.Import == nil {
+		return NewExitValue(CodeBadConfig, "Option --format must be one of: %s", importFormatNames())
+	}
+	dirPath := cfg.Get("dir")
+	if fi, err := os.Stat(dirPath); err != nil || !fi.IsDir() {
+		return NewExitValue(CodeBadConfig, "--dir=%s must refer to an existing directory", dirPath)
+	}
+	dir, err := fs.ParseDir(dirPath, cfg)
+	if err != nil {
+		return err
+	}
+	opts, err := export.OptionsForDir(dir)
+	if err != nil {
+		return NewExitValue(CodeBadConfig, err.Error())
+	}
+
+	inputPath := cfg.Get("file")
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return NewExitValue(CodeNoInput, "Unable to open %s: %s", inputPath, err)
+	}
+	defer f.Close()
+	tables, err := format.Import(f, opts)
+	if err != nil {
+		return NewExitValue(CodeBadInput, "Unable to import %s: %s", inputPath, err)
+	}
+	for _, table := range tables {
+		path := fs.PathForObject(dir.Path, table.Name)
+		contents := fmt.Sprintf("%s;\n", table.CreateStatement)
+		if err := os.WriteFile(path, []byte(contents), 0666); err != nil {
+			return NewExitValue(CodeCantCreate, "Unable to write %s: %s", path, err)
+		}
+		log.Infof("Wrote %s (%d bytes)", path, len(contents))
+	}
+	log.Infof("Imported %s from %s", countAndNoun(len(tables), "table", "tables"), inputPath)
+	return nil
+}
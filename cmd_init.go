@@ -9,7 +9,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
 	"github.com/skeema/skeema/internal/dumper"
+	"github.com/skeema/skeema/internal/dumpfile"
 	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/grants"
 	"github.com/skeema/skeema/internal/tengo"
 )
 
@@ -19,7 +21,13 @@ func init() {
 		"For each schema on the instance (or just the single schema specified by " +
 		"--schema), a subdir with a .skeema config file will be created. Each directory " +
 		"will be populated with .sql files containing CREATE statements for every " +
-		"table and routine in the schema.\n\n" +
+		"table, routine, view, and trigger in the schema. With --grants, the host dir " +
+		"is also populated with a grants.sql file containing the privileges of each " +
+		"account on the instance.\n\n" +
+		"With --from-dump, schemas are obtained from the output of mysqldump --no-data " +
+		"(a file) or mydumper (a directory) instead of from a DB instance. In this case " +
+		"no connection is made, and --host is optional if --dir is supplied. Views, " +
+		"triggers, and events in the dump are skipped.\n\n" +
 		"You may optionally pass an environment name as a CLI arg. This will affect " +
 		"which section of .skeema config files the host-related options are written to. " +
 		"For example, running `skeema init staging` will add config directives to the " +
@@ -35,6 +43,7 @@ func init() {
 	cmd.AddOption(mybase.StringOption("schema", 0, "", "Only import the one specified schema; skip creation of subdirs for each schema"))
 	cmd.AddOption(mybase.BoolOption("include-auto-inc", 0, false, "Include starting auto-inc values in table files"))
 	cmd.AddOption(mybase.BoolOption("strip-partitioning", 0, false, "Omit PARTITION BY clause when writing partitioned tables to filesystem"))
+	cmd.AddOption(mybase.StringOption("from-dump", 0, "", "Obtain schemas from this mysqldump output file or mydumper output dir, instead of connecting to a DB instance"))
 
 	// The temp-schema option is normally added via workspace.AddCommandOptions()
 	// only in subcommands that actually interact with workspaces. init doesn't use
@@ -42,6 +51,7 @@ func init() {
 	// of the temp-schema to the filesystem.
 	cmd.AddOption(mybase.StringOption("temp-schema", 't', "_skeema_tmp", "").Hidden())
 
+	grants.AddCommandOptions(cmd)
 	cmd.AddArg("environment", "production", false)
 	CommandSuite.AddSubCommand(cmd)
 }
@@ -62,6 +72,10 @@ func InitHandler(cfg *mybase.Config) error {
 		return NewExitValue(CodeBadConfig, "Environment name \"%s\" is invalid", environment)
 	}
 
+	if cfg.Get("from-dump") != "" {
+		return initFromDump(cfg, onlySchema)
+	}
+
 	hostDir, err := createHostDir(cfg)
 	if err != nil {
 		return err
@@ -91,7 +105,7 @@ func InitHandler(cfg *mybase.Config) error {
 	}
 
 	// Write host option file
-	err = createHostOptionFile(cfg, hostDir, inst, schemas)
+	err = createHostOptionFile(cfg, hostDir, inst, inst.Flavor(), schemas)
 	if err != nil {
 		return err
 	}
@@ -104,6 +118,63 @@ func InitHandler(cfg *mybase.Config) error {
 		}
 	}
 
+	// With --grants, also write account privileges to the host dir. This is only
+	// possible when the host dir does not also represent a single schema.
+	if cfg.GetBool("grants") {
+		if !separateSchemaSubdir {
+			log.Warn("Ignoring --grants: account privileges cannot be managed in a dir which also defines a schema")
+		} else if err := pullGrants(hostDir, inst); err != nil {
+			return NewExitValue(CodeFatalError, "Cannot examine grants on %s: %s", inst, err)
+		}
+	}
+
+	return nil
+}
+
+// initFromDump is called by InitHandler when the from-dump option is used.
+// Instead of connecting to a DB instance, schemas are obtained from the output
+// of a logical dump tool.
+func initFromDump(cfg *mybase.Config, onlySchema string) error {
+	dumpPath := cfg.Get("from-dump")
+	if _, err := os.Stat(dumpPath); err != nil {
+		return NewExitValue(CodeNoInput, "Unable to read --from-dump=%s: %s", dumpPath, err)
+	}
+	dump, err := dumpfile.Parse(dumpPath, onlySchema)
+	if err != nil {
+		return NewExitValue(CodeBadInput, "Unable to parse %s: %s", dumpPath, err)
+	}
+
+	// Only include non-system schemas, since a dump of all databases will contain
+	// these too
+	var schemas []*tengo.Schema
+	for _, s := range dump.Schemas {
+		if (onlySchema == "" && !isSystemSchema(s.Name)) || s.Name == onlySchema {
+			schemas = append(schemas, s)
+		}
+	}
+	if onlySchema != "" && len(schemas) == 0 {
+		return NewExitValue(CodeBadConfig, "Schema %s is not present in %s", onlySchema, dumpPath)
+	} else if len(schemas) == 0 {
+		return NewExitValue(CodeBadInput, "No schemas found in %s", dumpPath)
+	}
+
+	hostDir, err := createHostDir(cfg)
+	if err != nil {
+		return err
+	}
+	flavor := dump.Flavor
+	if cfg.OnCLI("flavor") || !flavor.Known() {
+		flavor = tengo.ParseFlavor(cfg.Get("flavor"))
+	}
+	if err := createHostOptionFile(cfg, hostDir, nil, flavor, schemas); err != nil {
+		return err
+	}
+	for _, s := range schemas {
+		s.StripMatches(hostDir.IgnorePatterns)
+		if err := PopulateSchemaDir(s, hostDir, onlySchema == ""); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -118,7 +189,10 @@ func isSystemSchema(name string) bool {
 }
 
 func createHostDir(cfg *mybase.Config) (*fs.Dir, error) {
-	if !cfg.OnCLI("host") {
+	if !cfg.OnCLI("host") && (cfg.Get("from-dump") == "" || !cfg.Changed("dir")) {
+		if cfg.Get("from-dump") != "" {
+			return nil, NewExitValue(CodeBadConfig, "Option --host or --dir must be supplied on the command-line")
+		}
 		return nil, NewExitValue(CodeBadConfig, "Option --host must be supplied on the command-line")
 	}
 	hostDirName := cfg.Get("dir")
@@ -144,10 +218,19 @@ func createHostDir(cfg *mybase.Config) (*fs.Dir, error) {
 	return hostDir, nil
 }
 
-func createHostOptionFile(cfg *mybase.Config, hostDir *fs.Dir, inst *tengo.Instance, schemas []*tengo.Schema) error {
+// createHostOptionFile writes the .skeema file for hostDir. If inst is nil,
+// as with --from-dump, the host-related options are taken from the command-line
+// instead, if present.
+func createHostOptionFile(cfg *mybase.Config, hostDir *fs.Dir, inst *tengo.Instance, flavor tengo.Flavor, schemas []*tengo.Schema) error {
 	environment := cfg.Get("environment")
 	hostOptionFile := mybase.NewFile(hostDir.Path, ".skeema")
-	if inst.SocketPath != "" {
+	if inst == nil {
+		for _, hostOpt := range []string{"host", "port", "socket"} {
+			if cfg.OnCLI(hostOpt) {
+				hostOptionFile.SetOptionValue(environment, hostOpt, cfg.Get(hostOpt))
+			}
+		}
+	} else if inst.SocketPath != "" {
 		hostOptionFile.SetOptionValue(environment, "host", "localhost")
 		hostOptionFile.SetOptionValue(environment, "socket", inst.SocketPath)
 	} else {
@@ -157,10 +240,10 @@ func createHostOptionFile(cfg *mybase.Config, hostDir *fs.Dir, inst *tengo.Insta
 	if !cfg.Changed("generator") {
 		hostOptionFile.SetOptionValue("", "generator", generatorString())
 	}
-	if flavor := inst.Flavor(); flavor.Known() {
+	if flavor.Known() {
 		hostOptionFile.SetOptionValue(environment, "flavor", flavor.Family().String())
 	}
-	for _, persistOpt := range []string{"user", "ignore-schema", "ignore-table", "ignore-proc", "ignore-func", "connect-options"} {
+	for _, persistOpt := range []string{"user", "ignore-schema", "ignore-table", "ignore-proc", "ignore-func", "ignore-view", "ignore-trigger", "grants", "ignore-grantee", "connect-options", "sql-mode", "session-init"} {
 		if cfg.OnCLI(persistOpt) {
 			hostOptionFile.SetOptionValue(environment, persistOpt, cfg.Get(persistOpt))
 		}
@@ -172,8 +255,7 @@ func createHostOptionFile(cfg *mybase.Config, hostDir *fs.Dir, inst *tengo.Insta
 	// between environments.
 	if cfg.Changed("schema") {
 		hostOptionFile.SetOptionValue("", "schema", cfg.Get("schema"))
-		hostOptionFile.SetOptionValue("", "default-character-set", schemas[0].CharSet)
-		hostOptionFile.SetOptionValue("", "default-collation", schemas[0].Collation)
+		setSchemaDefaults(hostOptionFile, schemas[0])
 	}
 
 	// Write the option file
@@ -185,10 +267,26 @@ func createHostOptionFile(cfg *mybase.Config, hostDir *fs.Dir, inst *tengo.Insta
 	if cfg.Changed("schema") {
 		suffix = "; skipping schema-level subdirs"
 	}
-	log.Infof("Using host dir %s for %s%s\n", hostDir.Path, inst, suffix)
+	if inst == nil {
+		log.Infof("Using host dir %s for %s%s\n", hostDir.Path, cfg.Get("from-dump"), suffix)
+	} else {
+		log.Infof("Using host dir %s for %s%s\n", hostDir.Path, inst, suffix)
+	}
 	return nil
 }
 
+// setSchemaDefaults sets the default-character-set and default-collation
+// options of optionFile to match s. Values which are not known, as is possible
+// with schemas from --from-dump, are omitted.
+func setSchemaDefaults(optionFile *mybase.File, s *tengo.Schema) {
+	if s.CharSet != "" {
+		optionFile.SetOptionValue("", "default-character-set", s.CharSet)
+	}
+	if s.Collation != "" {
+		optionFile.SetOptionValue("", "default-collation", s.Collation)
+	}
+}
+
 func generatorString() string {
 	return "skeema:" + versionString()
 }
@@ -226,8 +324,7 @@ func PopulateSchemaDir(s *tengo.Schema, parentDir *fs.Dir, makeSubdir bool) erro
 		}
 		optionFile := mybase.NewFile(dir.Path, ".skeema")
 		optionFile.SetOptionValue("", "schema", s.Name)
-		optionFile.SetOptionValue("", "default-character-set", s.CharSet)
-		optionFile.SetOptionValue("", "default-collation", s.Collation)
+		setSchemaDefaults(optionFile, s)
 		if err = dir.CreateOptionFile(optionFile); err != nil {
 			return NewExitValue(CodeCantCreate, "Cannot use dir %s for schema %s: %v", dir.Path, s.Name, err)
 		}
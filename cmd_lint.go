@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
@@ -40,6 +41,9 @@ func init() {
 		mybase.BoolOption("format", 0, true, "Reformat SQL statements to match canonical SHOW CREATE"),
 		mybase.BoolOption("strip-partitioning", 0, false, "Remove PARTITION BY clauses from *.sql files"),
 	)
+	cmd.AddOptions("output",
+		mybase.StringOption("template", 0, "", "Render annotations through the Go text/template in this file, after linting completes"),
+	)
 	workspace.AddCommandOptions(cmd)
 	cmd.AddArg("environment", "production", false)
 	CommandSuite.AddSubCommand(cmd)
@@ -53,6 +57,16 @@ func LintHandler(cfg *mybase.Config) error {
 	}
 
 	result := lintWalker(dir, 5)
+	if path := dir.Config.Get("template"); path != "" {
+		data := struct {
+			Command     string
+			Environment string
+			*linter.Result
+		}{"lint", dir.Config.Get("environment"), result}
+		if err := util.RenderTemplateFile(path, data, os.Stdout); err != nil {
+			return NewExitValue(CodeBadConfig, "Unable to render template %s: %s", path, err)
+		}
+	}
 	switch {
 	case len(result.Exceptions) > 0:
 		exitCode := ExitCode(HighestExitCode(result.Exceptions...))
@@ -163,6 +177,7 @@ func lintDir(dir *fs.Dir) *linter.Result {
 		if dir.Config.GetBool("format") && n == 0 {
 			dumpOpts := dumper.Options{
 				IncludeAutoInc: true,
+				NameCaseMode:   wsOpts.NameCaseMode,
 			}
 			if dir.Config.GetBool("strip-partitioning") {
 				dumpOpts.Partitioning = tengo.PartitioningRemove
@@ -196,6 +211,16 @@ func lintDir(dir *fs.Dir) *linter.Result {
 		}
 	}
 
+	// Add error annotations for malformed statements. Their objects are excluded
+	// from all other processing, so these are always reported.
+	for _, stmt := range dir.MalformedStatements {
+		note := linter.Note{
+			Summary: "Malformed statement",
+			Message: stmt.Err.Error(),
+		}
+		result.Annotate(stmt, linter.SeverityError, "", note)
+	}
+
 	// Make sure the problem messages have a deterministic order.
 	result.SortByFile()
 	return result
@@ -7,8 +7,11 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/codegen"
 	"github.com/skeema/skeema/internal/dumper"
+	"github.com/skeema/skeema/internal/fixture"
 	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/grants"
 	"github.com/skeema/skeema/internal/tengo"
 	"github.com/skeema/skeema/internal/workspace"
 )
@@ -33,7 +36,11 @@ func init() {
 	cmd.AddOption(mybase.BoolOption("new-schemas", 0, true, "Detect any new schemas and populate new dirs for them"))
 	cmd.AddOption(mybase.BoolOption("update-partitioning", 0, false, "Update PARTITION BY clauses in existing table files"))
 	cmd.AddOption(mybase.BoolOption("strip-partitioning", 0, false, "Omit PARTITION BY clause when writing partitioned tables to filesystem"))
+	cmd.AddOption(mybase.BoolOption("canonical-check-names", 0, false, "Rename server-generated CHECK constraints to <table>_chk_<N> format in table files"))
 	workspace.AddCommandOptions(cmd)
+	codegen.AddCommandOptions(cmd)
+	fixture.AddCommandOptions(cmd)
+	grants.AddCommandOptions(cmd)
 	cmd.AddArg("environment", "production", false)
 	CommandSuite.AddSubCommand(cmd)
 }
@@ -122,10 +129,30 @@ func pullWalker(dir *fs.Dir, maxDepth int) error {
 				return NewExitValue(CodePartialError, "")
 			}
 		}
+		if grantsErr := pullGrants(dir, instance); grantsErr != nil {
+			log.Warnf("Unable to update grants in %s: %s", dir, grantsErr)
+			return NewExitValue(CodePartialError, "")
+		}
 	}
 	return err
 }
 
+// pullGrants updates the GRANT statements in a host-level dir to reflect the
+// account privileges on instance, if the dir's configuration enables the
+// grants option.
+func pullGrants(dir *fs.Dir, instance *tengo.Instance) error {
+	opts, err := grants.OptionsForDir(dir)
+	if err != nil || !opts.Enabled {
+		return err
+	}
+	set, _, err := grants.Introspect(instance, opts)
+	if err != nil {
+		return err
+	}
+	_, err = grants.WriteDir(dir, set, opts)
+	return err
+}
+
 // pullSchemaDir updates all logical schemas in dir to reflect the actual
 // definitions found in instance. A slice of handled schema names is returned,
 // along with any error encountered.
@@ -184,7 +211,9 @@ func pullLogicalSchema(dir *fs.Dir, instance *tengo.Instance, logicalSchema *fs.
 	}
 
 	dumpOpts := dumper.Options{
-		IncludeAutoInc: dir.Config.GetBool("include-auto-inc"),
+		IncludeAutoInc:      dir.Config.GetBool("include-auto-inc"),
+		NameCaseMode:        instance.NameCaseMode(),
+		CanonicalCheckNames: dir.Config.GetBool("canonical-check-names"),
 	}
 	if !dir.Config.GetBool("update-partitioning") {
 		if dir.Config.GetBool("strip-partitioning") {
@@ -215,17 +244,62 @@ func pullLogicalSchema(dir *fs.Dir, instance *tengo.Instance, logicalSchema *fs.
 	}
 
 	_, err = dumper.DumpSchema(instSchema, dir, dumpOpts)
+	if err == nil {
+		// Keep any generated Go models in sync with the schema (if configured)
+		err = generateCode(dir, instSchema)
+	}
+	if err == nil {
+		// Keep any reference data CSV files in sync with the table contents
+		err = dumpFixtures(dir, instance, instSchema)
+	}
 	if err == nil {
 		os.Stderr.WriteString("\n")
 	}
 	return
 }
 
+// dumpFixtures writes the rows of any tables in schema configured as
+// reference data to CSV files, ordered by primary key.
+func dumpFixtures(dir *fs.Dir, instance *tengo.Instance, schema *tengo.Schema) error {
+	opts, err := fixture.OptionsForDir(dir)
+	if err != nil {
+		return NewExitValue(CodeBadConfig, err.Error())
+	}
+	tables := opts.Matches(schema)
+	if len(tables) == 0 {
+		return nil
+	}
+	db, err := instance.CachedConnectionPool(schema.Name, "")
+	if err != nil {
+		return NewExitValue(CodeFatalError, "Unable to connect to %s: %s", instance, err)
+	}
+	if err := os.MkdirAll(opts.Dir, 0777); err != nil {
+		return NewExitValue(CodeCantCreate, "Unable to create directory %s: %s", opts.Dir, err)
+	}
+	for _, table := range tables {
+		path := opts.Path(table.Name)
+		f, err := os.Create(path)
+		if err != nil {
+			return NewExitValue(CodeCantCreate, "Unable to create %s: %s", path, err)
+		}
+		count, err := fixture.Dump(db, table, f, opts.Masks)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return NewExitValue(CodeFatalError, "Unable to dump reference data for %s: %s", table.ObjectKey(), err)
+		}
+		log.Infof("Wrote %s -- %s of reference data", path, countAndNoun(count, "row", "rows"))
+	}
+	return nil
+}
+
 func statementModifiersForPull(config *mybase.Config, instance *tengo.Instance) tengo.StatementModifiers {
 	// We're permissive of unsafe operations here since we don't ever actually
 	// execute the generated statement! We just examine its type.
 	mods := tengo.StatementModifiers{
-		AllowUnsafe: true,
+		AllowUnsafe:    true,
+		LaxCheckNaming: config.GetBool("canonical-check-names"),
 	}
 	// pull command updates next auto-increment value for existing table always
 	// if requested, or only if previously present in file otherwise
@@ -2,13 +2,25 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
 	"github.com/skeema/skeema/internal/applier"
+	"github.com/skeema/skeema/internal/fixture"
 	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/grants"
 	"github.com/skeema/skeema/internal/linter"
+	"github.com/skeema/skeema/internal/metrics"
+	"github.com/skeema/skeema/internal/migration"
+	"github.com/skeema/skeema/internal/notify"
+	"github.com/skeema/skeema/internal/policy"
+	"github.com/skeema/skeema/internal/util"
 	"github.com/skeema/skeema/internal/workspace"
 	"golang.org/x/sync/errgroup"
 )
@@ -27,23 +39,32 @@ func init() {
 		"An exit code of 0 will be returned if the operation was fully successful; 1 if " +
 		"at least one table could not be updated due to use of unsupported features, or if " +
 		"the --dry-run option was used and differences were found; or 2+ if a fatal error " +
-		"occurred."
+		"occurred. With --strict-unsupported, any table using unsupported features is " +
+		"treated as a fatal error, even if it has no differences.\n\n" +
+		"While pushing to a schema, an advisory lock (via GET_LOCK) is held on its " +
+		"instance, so that concurrent pushes to the same schema cannot interleave " +
+		"their DDL. Use --force-break-lock to override a lock held by a stuck push."
 
 	cmd := mybase.NewCommand("push", summary, desc, PushHandler)
 
 	cmd.AddOptions("SQL generation",
 		mybase.BoolOption("exact-match", 0, false, "Follow *.sql table definitions exactly, even for differences with no functional impact"),
 		mybase.BoolOption("compare-metadata", 0, false, "For stored programs, detect changes to creation-time sql_mode or DB collation"),
+		mybase.BoolOption("canonical-check-names", 0, false, "Ignore differences in server-generated CHECK constraint names, comparing these checks by clause only"),
 		mybase.BoolOption("alter-validate-virtual", 0, false, "Apply a WITH VALIDATION clause to ALTER TABLEs affecting virtual columns"),
 		mybase.StringOption("alter-lock", 0, "", `Apply a LOCK clause to all ALTER TABLEs (valid values: "none", "shared", "exclusive")`),
 		mybase.StringOption("alter-algorithm", 0, "", `Apply an ALGORITHM clause to all ALTER TABLEs (valid values: "inplace", "copy", "instant", "nocopy")`),
 		mybase.StringOption("partitioning", 0, "keep", `Specify handling of partitioning status on the database side (valid values: "keep", "remove", "modify")`),
+		mybase.StringOption("ddl-strategy", 0, "", `For Vitess vtgate targets, submit DDL to Vitess OnlineDDL using this @@ddl_strategy (e.g. "vitess" or "vitess --postpone-completion"); reference data and version-table are not updated while migrations are pending`),
 	)
 
 	cmd.AddOptions("External tool",
 		mybase.StringOption("alter-wrapper", 'x', "", "External bin to shell out to for ALTER TABLE; see manual for template vars"),
 		mybase.StringOption("alter-wrapper-min-size", 0, "0", "Ignore --alter-wrapper for tables smaller than this size in bytes"),
 		mybase.StringOption("ddl-wrapper", 'X', "", "Like --alter-wrapper, but applies to all DDL types (CREATE, DROP, ALTER)"),
+		mybase.StringOption("ddl-transform", 0, "", "External bin to shell out to for rewriting each generated statement; non-zero exit vetoes it; see manual for template vars"),
+		mybase.StringOption("pre-statement-hook", 0, "", "External bin to shell out to before each statement; non-zero exit skips only that statement; see manual for template vars"),
+		mybase.StringOption("post-statement-hook", 0, "", "External bin to shell out to after each statement; see manual for template vars"),
 	)
 
 	cmd.AddOptions("linter rule",
@@ -54,21 +75,49 @@ func init() {
 	cmd.AddOptions("safety",
 		mybase.BoolOption("verify", 0, true, "Test all generated ALTER statements on temp schema to verify correctness"),
 		mybase.BoolOption("allow-unsafe", 0, false, "Permit running ALTER or DROP operations that are potentially destructive"),
+		mybase.BoolOption("allow-unsafe-grants", 0, false, "With --grants, permit running REVOKE statements that remove account privileges"),
 		mybase.BoolOption("dry-run", 0, false, "Output DDL but don't run it; equivalent to `skeema diff`"),
+		mybase.BoolOption("strict-unsupported", 0, false, "Treat any table using unsupported features as a fatal error, rather than skipping its changes"),
+		mybase.StringOption("snapshot-dir", 0, "", "Before each push, save prior definitions of modified objects in this dir (relative to each schema dir) for `skeema undo-last-push`"),
+		mybase.BoolOption("force-break-lock", 0, false, "If another push to the same schema holds the advisory lock, kill its lock connection and proceed"),
+		mybase.BoolOption("binlog", 0, true, "Replicate pushed DDL via the binary log; use --skip-binlog to only alter this instance, with sql_log_bin=0"),
 		mybase.BoolOption("foreign-key-checks", 0, false, "Force the server to check referential integrity of any new foreign key"),
 		mybase.StringOption("safe-below-size", 0, "0", "Always permit destructive operations for tables below this size in bytes"),
+		mybase.StringOption("avro-compat", 0, "ignore", `Check ALTERs for backward-incompatible changes to Debezium Avro schemas (valid values: "ignore", "warning", "error")`),
+	)
+
+	cmd.AddOptions("throttling",
+		mybase.StringOption("throttle-replicas", 0, "", "Comma-separated host:port list of replicas to monitor for lag between statements"),
+		mybase.StringOption("max-replica-lag", 0, "0", "Pause between statements while any throttle-replicas lag by more than this many seconds"),
+		mybase.StringOption("replica-lag-timeout", 0, "0", "Give up on remaining statements if replicas lag for longer than this many seconds; 0 waits indefinitely"),
 	)
 
 	cmd.AddOptions("sharding",
 		mybase.BoolOption("first-only", '1', false, "For dirs mapping to multiple instances or schemas, just run against the first per dir"),
 		mybase.BoolOption("brief", 'q', false, "<overridden by diff command>").Hidden(),
 		mybase.StringOption("concurrent-instances", 'c', "1", "Perform operations on this number of instances concurrently"),
+		mybase.StringOption("resume-file", 0, "", "Track fully-pushed instance/schema pairs in this file, and skip ones already listed there"),
+	)
+
+	cmd.AddOptions("output",
+		mybase.StringOption("version-table", 0, "", "After each successful push, record the git SHA, Skeema version, and diff hash in this schema-qualified table, which must not be in a schema managed by Skeema"),
+		mybase.StringOption("events-file", 0, "", `Write JSON-lines lifecycle events to this file, or to STDOUT if "-"`),
+		mybase.BoolOption("progress", 0, false, "Display a progress bar on STDERR, if STDERR is a terminal"),
+		mybase.StringOption("template", 0, "", "Render output through the Go text/template in this file, instead of displaying generated SQL"),
+		mybase.StringOption("plan-file", 0, "", `Write a JSON plan of changes to this file, or to STDOUT if "-", instead of displaying generated SQL`),
 	)
 
 	workspace.AddCommandOptions(cmd)
+	metrics.AddCommandOptions(cmd)
+	notify.AddCommandOptions(cmd)
+	policy.AddCommandOptions(cmd)
+	migration.AddCommandOptions(cmd)
+	fixture.AddCommandOptions(cmd)
+	grants.AddCommandOptions(cmd)
 	cmd.AddArg("environment", "production", false)
 	CommandSuite.AddSubCommand(cmd)
 	clonePushOptionsToDiff()
+	clonePushOptionsToUndo()
 }
 
 // PushHandler is the handler method for `skeema push`
@@ -83,6 +132,7 @@ func PushHandler(cfg *mybase.Config) error {
 		cfg.SetRuntimeOverride("verify", "0")
 		cfg.SetRuntimeOverride("lint", "0")
 		cfg.SetRuntimeOverride("allow-unsafe", "1")
+		cfg.SetRuntimeOverride("allow-unsafe-grants", "1")
 		if !cfg.GetBool("debug") {
 			log.SetLevel(log.WarnLevel)
 		}
@@ -99,13 +149,54 @@ func PushHandler(cfg *mybase.Config) error {
 	} else if concurrency < 1 {
 		return NewExitValue(CodeBadConfig, "concurrent-instances cannot be less than 1")
 	}
+	if err := validateMigrationOptions(dir.Config); err != nil {
+		return err
+	} else if dir.Config.Get("plan-file") != "" && !dir.Config.GetBool("dry-run") {
+		return NewExitValue(CodeBadConfig, "The plan-file option may only be used with `skeema diff`")
+	} else if dir.Config.Get("plan-file") != "" && dir.Config.Get("migration-format") != "" {
+		return NewExitValue(CodeBadConfig, "The plan-file option cannot be combined with migration-format")
+	}
 	printer := applier.NewPrinter(dir.Config)
+	applier.SetVersion(versionString())
+	start := time.Now()
+
+	// With --resume-file, skip targets already completed by a previous push
+	// attempt. This has no effect in dry-run mode.
+	var progress *applier.ProgressFile
+	if path := dir.Config.Get("resume-file"); path != "" && !dir.Config.GetBool("dry-run") {
+		if progress, err = applier.OpenProgressFile(path); err != nil {
+			return NewExitValue(CodeCantCreate, "Unable to read resume-file %s: %s", path, err)
+		} else if n := progress.CompletedCount(); n > 0 {
+			log.Infof("Resuming previous push: skipping %s already listed in %s", countAndNoun(n, "completed schema", "completed schemas"), path)
+		}
+	}
+	var failedTargets []string
+
+	if path := dir.Config.Get("events-file"); path != "" {
+		eventLog, err := applier.NewEventLog(path)
+		if err != nil {
+			return NewExitValue(CodeCantCreate, "Unable to create events-file %s: %s", path, err)
+		}
+		applier.SetEventLog(eventLog)
+		defer func() {
+			applier.SetEventLog(nil)
+			eventLog.Close()
+		}()
+	}
 
 	g, ctx := errgroup.WithContext(context.Background())
 	g.SetLimit(concurrency)
 	groups, skipCount := applier.TargetGroupsForDir(dir)
+	if err := applier.CheckVersionTables(groups); err != nil {
+		return err
+	}
 	sum := applier.Result{SkipCount: skipCount}
 	var sumLock sync.Mutex
+	collector := metrics.NewCollector("skeema")
+	if dir.Config.GetBool("progress") && util.StderrIsTerminal() {
+		stopProgress := startProgressBar(groups, progress, dir.Config.GetBool("dry-run"))
+		defer stopProgress()
+	}
 
 	for n := range groups {
 		tg := groups[n] // avoid loop iteration variable in closure below
@@ -116,22 +207,75 @@ func PushHandler(cfg *mybase.Config) error {
 				case <-ctx.Done():
 					return nil // Exit early if context cancelled
 				default:
+					if progress != nil && progress.Completed(t) {
+						continue
+					}
+					targetStart := time.Now()
 					result, err := applier.ApplyTarget(t, printer)
-					if err != nil {
+					collector.Timing(pushMetricsVerb(dir.Config)+".target_duration", time.Since(targetStart))
+					if err != nil && result.SkipCount == 0 {
 						return err
 					}
+					// Errors accompanied by a SkipCount are specific to this target (for
+					// example, the instance being unreachable) and have already been
+					// logged, so other targets should still proceed.
 					sumLock.Lock()
 					sum.Merge(result)
+					if result.Failed() {
+						failedTargets = append(failedTargets, fmt.Sprintf("%s %s", t.Instance, t.SchemaName))
+					}
 					sumLock.Unlock()
+					if progress != nil && !result.Failed() {
+						if err := progress.MarkCompleted(t); err != nil {
+							return NewExitValue(CodeCantCreate, "Unable to write resume-file %s: %s", dir.Config.Get("resume-file"), err)
+						}
+					}
 				}
 			}
 			return nil
 		})
 	}
 
-	if err := g.Wait(); err != nil {
+	err = g.Wait()
+
+	// Account privileges are reconciled after all schema changes, since grants
+	// may refer to newly-created objects
+	if err == nil {
+		sum.Merge(applier.ApplyGrantsForDir(dir, printer, 5))
+	}
+	if tp, ok := printer.(*applier.TemplatePrinter); ok && err == nil {
+		if tmplErr := renderPushTemplate(dir.Config, tp, sum); tmplErr != nil {
+			err = NewExitValue(CodeBadConfig, "Unable to render template %s: %s", dir.Config.Get("template"), tmplErr)
+		}
+	}
+	if mp, ok := printer.(*applier.MigrationPrinter); ok && err == nil {
+		if migErr := writeMigration(dir.Config, mp); migErr != nil {
+			err = NewExitValue(CodeCantCreate, "Unable to write %s migration: %s", dir.Config.Get("migration-format"), migErr)
+		}
+	}
+	if pp, ok := printer.(*applier.PlanPrinter); ok && err == nil {
+		if planErr := writePlan(dir.Config.Get("plan-file"), pp); planErr != nil {
+			err = NewExitValue(CodeCantCreate, "Unable to write plan-file %s: %s", dir.Config.Get("plan-file"), planErr)
+		}
+	}
+	emitPushMetrics(dir.Config, collector, sum, len(groups), time.Since(start), err)
+	sendPushNotifications(dir.Config, sum, failedTargets, err)
+	if err == nil && len(failedTargets) > 0 && len(groups) > 1 {
+		sort.Strings(failedTargets)
+		log.Warnf("Unable to fully process %s:\n  %s", countAndNoun(len(failedTargets), "schema", "schemas"), strings.Join(failedTargets, "\n  "))
+	}
+	if err == nil && progress != nil {
+		if len(failedTargets) == 0 {
+			if err := progress.Remove(); err != nil {
+				log.Warnf("Unable to remove resume-file %s: %s", dir.Config.Get("resume-file"), err)
+			}
+		} else {
+			log.Infof("To retry only the failed schemas, re-run with the same --resume-file=%s", dir.Config.Get("resume-file"))
+		}
+	}
+	if err != nil {
 		return err
-	} else if sum.SkipCount > 0 {
+	} else if sum.SkipCount+sum.ErrorCount > 0 {
 		return NewExitValue(CodeFatalError, sum.Summary())
 	} else if sum.UnsupportedCount > 0 {
 		return NewExitValue(CodePartialError, sum.Summary())
@@ -140,3 +284,173 @@ func PushHandler(cfg *mybase.Config) error {
 	}
 	return nil
 }
+
+// startProgressBar displays a progress bar on STDERR, updated as targets are
+// processed. The returned function stops updates and erases the bar.
+func startProgressBar(groups []applier.TargetGroup, progress *applier.ProgressFile, dryRun bool) (stop func()) {
+	var total int
+	for _, tg := range groups {
+		for _, t := range tg {
+			if progress == nil || !progress.Completed(t) {
+				total++
+			}
+		}
+	}
+	width, _ := util.TerminalWidth(int(os.Stderr.Fd()))
+	bar := util.NewProgressBar(os.Stderr, width)
+	applier.SetProgressFunc(func(p applier.Progress) {
+		label := fmt.Sprintf("%d/%d schemas, %s introspected", p.TargetsDone, p.TargetsTotal, countAndNoun(p.ObjectsIntrospected, "object", "objects"))
+		if dryRun {
+			label += fmt.Sprintf(", %s generated", countAndNoun(p.StatementsTotal, "statement", "statements"))
+		} else {
+			label += fmt.Sprintf(", %d/%d statements executed", p.StatementsExecuted, p.StatementsTotal)
+			if p.BytesTotal > 0 {
+				label += fmt.Sprintf(", ~%s/%s altered", util.FormatBytes(p.BytesDone), util.FormatBytes(p.BytesTotal))
+			}
+		}
+		bar.Update(p.TargetsDone, p.TargetsTotal, label)
+	}, total)
+	return func() {
+		applier.SetProgressFunc(nil, 0)
+		bar.Clear()
+	}
+}
+
+// renderPushTemplate displays the result of a push or diff using the template
+// file specified by the template option. Fields of applier.Result are available
+// to the template, along with Command, Environment, and Statements.
+func renderPushTemplate(cfg *mybase.Config, tp *applier.TemplatePrinter, sum applier.Result) error {
+	data := struct {
+		Command     string
+		Environment string
+		Statements  []applier.TemplateStatement
+		applier.Result
+	}{
+		Command:     "push",
+		Environment: cfg.Get("environment"),
+		Statements:  tp.Statements(),
+		Result:      sum,
+	}
+	if cfg.GetBool("dry-run") {
+		data.Command = "diff"
+	}
+	return util.RenderTemplateFile(cfg.Get("template"), data, os.Stdout)
+}
+
+// validateMigrationOptions confirms that the migration-format option, if set,
+// names a known format and is only used in dry-run mode without any wrapper
+// options, since migration files must consist of raw SQL.
+func validateMigrationOptions(cfg *mybase.Config) error {
+	format := cfg.Get("migration-format")
+	if format == "" {
+		return nil
+	} else if migration.Lookup(format) == nil {
+		return NewExitValue(CodeBadConfig, "Invalid value for migration-format: %q. Valid values are: %s", format, strings.Join(migration.Names(), ", "))
+	} else if !cfg.GetBool("dry-run") {
+		return NewExitValue(CodeBadConfig, "The migration-format option may only be used with `skeema diff`")
+	} else if cfg.Get("alter-wrapper") != "" || cfg.Get("ddl-wrapper") != "" {
+		return NewExitValue(CodeBadConfig, "The migration-format option cannot be combined with alter-wrapper or ddl-wrapper")
+	}
+	return nil
+}
+
+// writeMigration outputs the changes buffered by mp as migration files, in the
+// format specified by the migration-format option. Nothing is written if no
+// changes were generated.
+func writeMigration(cfg *mybase.Config, mp *applier.MigrationPrinter) error {
+	changes := mp.Changes()
+	if len(changes) == 0 {
+		return nil
+	}
+	format := migration.Lookup(cfg.Get("migration-format"))
+	if err := format.Write(changes, migration.OptionsForConfig(cfg)); err != nil {
+		return err
+	}
+	if dir := cfg.Get("migration-dir"); dir != "" {
+		log.Infof("Wrote %s migration with %s to %s", format.Name, countAndNoun(len(changes), "change", "changes"), dir)
+	}
+	return nil
+}
+
+// writePlan outputs the changes buffered by pp as JSON to path, or to STDOUT
+// if path is "-". Unlike migration files, a plan is always written, even if
+// no changes were generated.
+func writePlan(path string, pp *applier.PlanPrinter) error {
+	if path == "-" {
+		return pp.WriteJSON(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pp.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Infof("Wrote plan with %s to %s", countAndNoun(len(pp.Plan().ResourceChanges), "change", "changes"), path)
+	return nil
+}
+
+// pushMetricsVerb returns the first component of metric names emitted by
+// push or diff.
+func pushMetricsVerb(cfg *mybase.Config) string {
+	if cfg.GetBool("dry-run") {
+		return "diff"
+	}
+	return "push"
+}
+
+// emitPushMetrics sends counters and timings about a push or diff to any
+// monitoring systems configured via the metrics options. c should already
+// contain the per-target timings recorded during the operation.
+func emitPushMetrics(cfg *mybase.Config, c *metrics.Collector, sum applier.Result, instanceCount int, elapsed time.Duration, err error) {
+	if cfg.Get("statsd-address") == "" && cfg.Get("pushgateway-url") == "" {
+		return
+	}
+	verb := pushMetricsVerb(cfg)
+	c.Count(verb+".instances", instanceCount)
+	c.Count(verb+".objects_introspected", sum.ObjectCount)
+	c.Count(verb+".statements", sum.StatementCount)
+	c.Count(verb+".failures.skipped", sum.SkipCount)
+	c.Count(verb+".failures.unsupported", sum.UnsupportedCount)
+	c.Count(verb+".failures.reference_data", sum.ErrorCount)
+	if err != nil {
+		c.Count(verb+".failures.fatal", 1)
+	} else {
+		c.Count(verb+".failures.fatal", 0)
+	}
+	c.Timing(verb+".duration", elapsed)
+	c.Emit(cfg)
+}
+
+// sendPushNotifications posts a summary of a push or diff to any webhooks
+// configured via the notification options.
+func sendPushNotifications(cfg *mybase.Config, sum applier.Result, failedTargets []string, err error) {
+	summary := notify.Summary{
+		Command:       "push",
+		Environment:   cfg.Get("environment"),
+		Statements:    sum.StatementCount,
+		Destructive:   sum.DestructiveCount,
+		Failures:      sum.SkipCount + sum.UnsupportedCount + sum.ErrorCount,
+		FailedTargets: failedTargets,
+	}
+	if cfg.GetBool("dry-run") {
+		summary.Command = "diff"
+	}
+	if err != nil {
+		summary.Status = "failure"
+		summary.Error = err.Error()
+	} else if summary.Failures > 0 {
+		summary.Status = "partial failure"
+	} else if !sum.Differences {
+		summary.Status = "no differences"
+	} else if cfg.GetBool("dry-run") {
+		summary.Status = "differences found"
+	} else {
+		summary.Status = "success"
+	}
+	notify.Send(cfg, summary)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/export"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/workspace"
+)
+
+func init() {
+	summary := "Generate an HTML report documenting the filesystem representation of schemas"
+	desc := "Generates a single self-contained HTML file describing the schemas defined " +
+		"by *.sql files in the current directory and its subdirectories. The report " +
+		"includes sortable listings of tables, columns, indexes, and routines; foreign " +
+		"keys are cross-referenced in both directions; and a search box filters tables " +
+		"by table or column name. The file has no external dependencies, so it may be " +
+		"published as-is or attached to a CI build. This is equivalent to `skeema export " +
+		"--format=html`.\n\n" +
+		"This command relies on accessing database instances to test the SQL DDL in a " +
+		"temporary location. See the --workspace option for more information.\n\n" +
+		"You may optionally pass an environment name as a CLI arg. This will affect " +
+		"which section of .skeema config files is used for workspace selection. If no " +
+		"environment name is supplied, the default is \"production\"."
+
+	cmd := mybase.NewCommand("report", summary, desc, ReportHandler)
+	cmd.AddOptions("output",
+		mybase.StringOption("output", 0, "schema-report.html", `Write the report to this file, or to STDOUT if "-"`),
+	)
+	workspace.AddCommandOptions(cmd)
+	cmd.AddArg("environment", "production", false)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// ReportHandler is the handler method for `skeema report`
+func ReportHandler(cfg *mybase.Config) error {
+	dir, err := fs.ParseDir(".", cfg)
+	if err != nil {
+		return err
+	}
+	var schemas []*tengo.Schema
+	if err := exportWalker(dir, &schemas, 5); err != nil {
+		return err
+	}
+	if len(schemas) == 0 {
+		return NewExitValue(CodeBadInput, "No schemas found in %s or its subdirectories", dir)
+	}
+
+	path := cfg.Get("output")
+	if path == "-" {
+		return export.ExportHTML(os.Stdout, schemas, export.Options{})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return NewExitValue(CodeCantCreate, "Unable to create %s: %s", path, err)
+	}
+	defer f.Close()
+	if err := export.ExportHTML(f, schemas, export.Options{}); err != nil {
+		return NewExitValue(CodeFatalError, "Unable to generate report: %s", err)
+	}
+	log.Infof("Wrote %s -- report of %s", path, countAndNoun(len(schemas), "schema", "schemas"))
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/server"
+)
+
+func init() {
+	summary := "Run an HTTP API server for introspect, diff, lint, and push"
+	desc := "Runs a long-lived HTTP server, exposing a JSON API for performing schema " +
+		"operations against the schema repo in the current directory. This permits " +
+		"platform teams to centralize schema operations and database credentials on " +
+		"one host, instead of distributing credentials to each user of the CLI.\n\n" +
+		"All endpoints besides /healthz require an Authorization header containing " +
+		"the bearer token configured via --auth-token, which may refer to an " +
+		"environment variable such as $SKEEMA_AUTH_TOKEN. Endpoints accept a POST " +
+		"with a JSON body specifying the \"environment\" and the relative \"path\" of a " +
+		"subdirectory of the repo:\n\n" +
+		"  /v1/introspect  returns the CREATE statements of the live schemas\n" +
+		"  /v1/diff        runs `skeema diff` and returns its output and exit code\n" +
+		"  /v1/lint        runs `skeema lint` and returns its output and exit code\n" +
+		"  /v1/push        runs `skeema push`; only enabled with --allow-push\n\n" +
+		"The optional environment arg only affects which section of option files is " +
+		"used for the server's own configuration; each request supplies its own."
+
+	cmd := mybase.NewCommand("serve", summary, desc, ServeHandler)
+	server.AddCommandOptions(cmd)
+	cmd.AddArg("environment", "production", false)
+	CommandSuite.AddSubCommand(cmd)
+}
+
+// ServeHandler is the handler method for `skeema serve`
+func ServeHandler(cfg *mybase.Config) error {
+	token := cfg.GetAllowEnvVar("auth-token")
+	if token == "" {
+		return NewExitValue(CodeBadConfig, "`skeema serve` requires --auth-token")
+	}
+	timeout, err := time.ParseDuration(cfg.Get("request-timeout"))
+	if err != nil {
+		return NewExitValue(CodeBadConfig, "Invalid value for --request-timeout: %s", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return NewExitValue(CodeFatalError, "Unable to determine path of skeema binary: %s", err)
+	}
+	root, err := filepath.Abs(".")
+	if err != nil {
+		return err
+	}
+
+	srv := &server.Server{
+		Root:       root,
+		Config:     cfg,
+		Token:      token,
+		AllowPush:  cfg.GetBool("allow-push"),
+		Executable: exe,
+		Timeout:    timeout,
+	}
+	httpServer := &http.Server{
+		Addr:              cfg.Get("listen"),
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Infof("Listening on %s for requests against %s", httpServer.Addr, root)
+	if err := httpServer.ListenAndServe(); err != nil {
+		return NewExitValue(CodeFatalError, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/applier"
+	"github.com/skeema/skeema/internal/fs"
+)
+
+func init() {
+	summary := "Revert objects modified by the most recent push"
+	desc := "Reverts the changes made by the most recent `skeema push` to each schema, by " +
+		"restoring the definitions of modified objects that push saved immediately " +
+		"before executing any DDL. Objects created by that push are dropped. Snapshots " +
+		"are only saved if --snapshot-dir is configured, and this command " +
+		"must be run with the same configuration as the original push.\n\n" +
+		"Only objects modified by the most recent push are affected; any other changes " +
+		"made to the schema since then are left as-is. Data changes cannot be undone: " +
+		"reverting a push which dropped a column restores the column, but not its data, " +
+		"so --allow-unsafe is required as usual for any destructive reversions.\n\n" +
+		"Undoing is itself a push, which records its own snapshot. Running this command " +
+		"twice in a row therefore re-applies the original push.\n\n" +
+		"You may optionally pass an environment name as a CLI arg. This will affect " +
+		"which section of .skeema config files is used for processing. If no " +
+		"environment name is supplied, the default is \"production\".\n\n" +
+		"An exit code of 0 will be returned if the operation was fully successful, or " +
+		"2+ if any schema could not be reverted."
+
+	cmd := mybase.NewCommand("undo-last-push", summary, desc, UndoLastPushHandler)
+	cmd.AddArg("environment", "production", false)
+	CommandSuite.AddSubCommand(cmd)
+	clonePushOptionsToUndo()
+}
+
+// UndoLastPushHandler is the handler method for `skeema undo-last-push`
+func UndoLastPushHandler(cfg *mybase.Config) error {
+	// Never execute as a dry-run, and never reload reference data, since the
+	// goal is to revert the schema only. Output options which only apply to
+	// dry-runs are also disabled.
+	for name, value := range map[string]string{
+		"dry-run":          "0",
+		"brief":            "0",
+		"reference-tables": "",
+		"plan-file":        "",
+		"migration-format": "",
+	} {
+		cfg.SetRuntimeOverride(name, value)
+	}
+
+	dir, err := fs.ParseDir(".", cfg)
+	if err != nil {
+		return err
+	}
+	printer := applier.NewPrinter(dir.Config)
+	applier.SetVersion(versionString())
+
+	targets, skipCount := applier.TargetsForDir(dir, 5)
+	sum := applier.Result{SkipCount: skipCount}
+	var undoneCount int
+	for _, t := range targets {
+		result, err := applier.UndoTarget(t, printer)
+		if err == applier.ErrNoSnapshot {
+			log.Infof("%s %s: no snapshot of a previous push found, skipping", t.Instance, t.SchemaName)
+			continue
+		} else if err != nil && result.SkipCount == 0 {
+			return err
+		} else if err != nil {
+			log.Errorf("Skipping %s %s: %s", t.Instance, t.SchemaName, err)
+		}
+		sum.Merge(result)
+		undoneCount++
+	}
+	if sum.SkipCount > 0 {
+		return NewExitValue(CodeFatalError, sum.Summary())
+	} else if undoneCount == 0 {
+		log.Warn("No snapshots were found for any schemas, so nothing was reverted. Snapshots are only saved by push if the snapshot-dir option is configured.")
+	}
+	return nil
+}
+
+// clonePushOptionsToUndo copies options from `skeema push` into
+// `skeema undo-last-push`, since undo is performed using the same logic.
+func clonePushOptionsToUndo() {
+	// Logic relies on init() having been called in both cmd_push.go AND
+	// cmd_undo_last_push.go, so we call it from both places, but only one will
+	// succeed
+	undo, ok1 := CommandSuite.SubCommands["undo-last-push"]
+	push, ok2 := CommandSuite.SubCommands["push"]
+	if !ok1 || !ok2 {
+		return
+	}
+	hidden := map[string]bool{
+		"dry-run":          true,
+		"brief":            true,
+		"resume-file":      true,
+		"plan-file":        true,
+		"migration-format": true,
+		"reference-tables": true,
+	}
+	undoOptions := undo.Options()
+	for name, pushOpt := range push.Options() {
+		if _, already := undoOptions[name]; already {
+			continue
+		}
+		undoOpt := *pushOpt
+		if hidden[name] {
+			undoOpt.HiddenOnCLI = true
+		}
+		undo.AddOption(&undoOpt)
+	}
+}
@@ -3,11 +3,15 @@
 package applier
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/skeema/internal/fs"
 	"github.com/skeema/skeema/internal/linter"
+	"github.com/skeema/skeema/internal/policy"
 	"github.com/skeema/skeema/internal/tengo"
 	"github.com/skeema/skeema/internal/util"
 )
@@ -35,6 +39,10 @@ type Result struct {
 	Differences      bool
 	SkipCount        int
 	UnsupportedCount int
+	ErrorCount       int // number of reference data tables which could not be loaded after DDL succeeded
+	ObjectCount      int // number of objects introspected from the database
+	StatementCount   int // number of DDL statements generated
+	DestructiveCount int // number of generated statements which are potentially destructive
 }
 
 // Merge modifies the receiver to include the sub-totals from the supplied arg.
@@ -42,48 +50,113 @@ func (r *Result) Merge(other Result) {
 	r.Differences = r.Differences || other.Differences
 	r.SkipCount += other.SkipCount
 	r.UnsupportedCount += other.UnsupportedCount
+	r.ErrorCount += other.ErrorCount
+	r.ObjectCount += other.ObjectCount
+	r.StatementCount += other.StatementCount
+	r.DestructiveCount += other.DestructiveCount
 }
 
 // Summary returns a string reflecting the contents of the result.
 func (r Result) Summary() string {
-	if r.SkipCount+r.UnsupportedCount == 0 {
-		return ""
-	}
-	var plural, reason string
-	if r.SkipCount+r.UnsupportedCount > 1 {
-		plural = "s"
+	var summaries []string
+	if r.SkipCount+r.UnsupportedCount > 0 {
+		var plural, reason string
+		if r.SkipCount+r.UnsupportedCount > 1 {
+			plural = "s"
+		}
+		if r.SkipCount == 0 {
+			reason = "unsupported feature"
+		} else if r.UnsupportedCount == 0 {
+			reason = "problem"
+		} else {
+			reason = "problems or unsupported feature"
+		}
+		summaries = append(summaries, fmt.Sprintf("Skipped %d operation%s due to %s%s", r.SkipCount+r.UnsupportedCount, plural, reason, plural))
 	}
-	if r.SkipCount == 0 {
-		reason = "unsupported feature"
-	} else if r.UnsupportedCount == 0 {
-		reason = "problem"
-	} else {
-		reason = "problems or unsupported feature"
+	if r.ErrorCount > 0 {
+		summaries = append(summaries, fmt.Sprintf("Unable to load reference data for %s", countAndNoun(r.ErrorCount, "table")))
 	}
-	return fmt.Sprintf("Skipped %d operation%s due to %s%s", r.SkipCount+r.UnsupportedCount, plural, reason, plural)
+	return strings.Join(summaries, "; ")
+}
+
+// Failed returns true if any operation was skipped or could not be completed.
+func (r Result) Failed() bool {
+	return r.SkipCount+r.UnsupportedCount+r.ErrorCount > 0
 }
 
 // ApplyTarget generates the diff for the supplied target, prints the resulting
 // SQL, and executes the SQL if this isn't a dry-run.
 func ApplyTarget(t *Target, printer Printer) (Result, error) {
-	var result Result
+	defer reportProgress(func(p *Progress) { p.TargetsDone++ })
+
+	// The push lock must be held before introspection, so that the diff is never
+	// computed from a schema state that a concurrent push is still changing.
+	release, result, err := t.lockForPush()
+	if release == nil {
+		return result, err
+	}
+	defer release()
+	return t.apply(printer)
+}
+
+// lockForPush acquires the target's push lock, unless this is a dry-run. If
+// the returned release func is nil, the target must not be applied, and the
+// returned Result and error should be passed along to the caller. Otherwise,
+// the caller must call release once done.
+func (t *Target) lockForPush() (release func(), result Result, err error) {
+	if t.Dir.Config.GetBool("dry-run") {
+		return func() {}, result, nil
+	}
+	release, err = t.acquirePushLock()
+	if err != nil {
+		result.SkipCount++
+		log.Errorf("Skipping %s schema %s for %s: %s\n", t.Instance, t.SchemaName, t.Dir, err)
+		t.logEvent(Event{Type: EventError, Error: err.Error()})
+		return nil, result, nil
+	}
+	if _, _, err := versionTableName(t.Dir.Config.Get("version-table")); err != nil {
+		release()
+		return nil, result, err
+	}
+	return release, result, nil
+}
 
+// apply performs the work of ApplyTarget. The caller must already hold the
+// push lock, if this isn't a dry-run.
+func (t *Target) apply(printer Printer) (Result, error) {
+	var result Result
+	t.logEvent(Event{Type: EventIntrospectionStart})
 	schemaFromInstance, err := t.SchemaFromInstance()
 	if err != nil {
 		result.SkipCount++
 		log.Errorf("Skipping %s schema %s for %s: %s\n", t.Instance, t.SchemaName, t.Dir, err)
+		t.logEvent(Event{Type: EventError, Error: err.Error()})
 		return result, err
 	}
 
 	t.logApplyStart()
+	if err := t.checkBinlog(); err != nil {
+		result.SkipCount++
+		log.Errorf("Skipping %s schema %s for %s: %s\n", t.Instance, t.SchemaName, t.Dir, err)
+		t.logEvent(Event{Type: EventError, Error: err.Error()})
+		return result, nil
+	}
+	result.ObjectCount = len(schemaFromInstance.Objects())
+	reportProgress(func(p *Progress) { p.ObjectsIntrospected += result.ObjectCount })
 	schemaFromDir := t.SchemaFromDir()
 
+	alignNameCase(schemaFromInstance, schemaFromDir, t.Instance.NameCaseMode())
+
 	// Obtain StatementModifiers based on the dir's config
 	mods, err := StatementModifiersForDir(t.Dir)
 	if err != nil {
 		return result, ConfigError(err.Error())
 	}
 	mods.Flavor = t.Instance.Flavor()
+	if mods.AlgorithmClause == "instant" && mods.Flavor.HasVariant(tengo.VariantAurora) && !mods.Flavor.InstantDDL() {
+		log.Warnf("%s %s: alter-algorithm=instant is not supported by %s; omitting ALGORITHM clause so that Aurora's fast DDL can be used where possible", t.Instance, t.SchemaName, mods.Flavor)
+		mods.AlgorithmClause = ""
+	}
 	if mods.Partitioning == tengo.PartitioningRemove {
 		// With partitioning=remove, forcibly treat all filesystem definitions as if
 		// they didn't have a partitioning clause. This is designed to aid in the
@@ -93,29 +166,91 @@ func ApplyTarget(t *Target, printer Printer) (Result, error) {
 		stripPartitionClauses(schemaFromDir.Tables, mods.Flavor)
 	}
 
+	doneTiming := tengo.StartTiming("Diff computation for %s %s", t.Instance, t.SchemaName)
 	diff := tengo.NewSchemaDiff(schemaFromInstance, schemaFromDir)
+	doneTiming()
 	if err := VerifyDiff(diff, t); err != nil {
+		t.logEvent(Event{Type: EventError, Error: err.Error()})
 		return result, err
 	}
 
+	// With strict-unsupported, refuse to process the target at all if any table
+	// on either side uses unsupported features, even ones without differences
+	if t.Dir.Config.GetBool("strict-unsupported") {
+		if names := unsupportedTableNames(mods.Flavor, schemaFromInstance, schemaFromDir); len(names) > 0 {
+			result.SkipCount += len(names)
+			log.Errorf("Skipping %s %s: option strict-unsupported is enabled, but found %s using unsupported features: %s", t.Instance, t.SchemaName, countAndNoun(len(names), "table"), strings.Join(names, ", "))
+			t.logEvent(Event{Type: EventError, Error: "strict-unsupported: unsupported tables " + strings.Join(names, ", ")})
+			return result, nil
+		}
+	}
+
+	// Routines may legally reference each other cyclically, since references are
+	// resolved at call time, but the creation order cannot satisfy all of them
+	if err := diff.RoutineOrderErr; err != nil {
+		log.Warnf("%s %s: %s. These routines will be created in name order.", t.Instance, t.SchemaName, err)
+	}
+
 	// Build PlannedStatement for each ObjectDiff, handling pre-execution errors
 	// accordingly. Also track ObjectKeys for modified objects, for subsequent
 	// use in linting.
 	objDiffs := diff.ObjectDiffs()
+	var rollbacks map[tengo.ObjectKey]rollbackStatement
+	if _, ok := printer.(*MigrationPrinter); ok {
+		rollbacks = rollbackStatements(schemaFromInstance, schemaFromDir, mods)
+	}
+	var fromObjects, toObjects map[tengo.ObjectKey]tengo.DefKeyer
+	if _, ok := printer.(*PlanPrinter); ok {
+		fromObjects, toObjects = schemaFromInstance.Objects(), schemaFromDir.Objects()
+	}
 	stmts := make([]PlannedStatement, 0, len(objDiffs))
 	keys := make([]tengo.ObjectKey, 0, len(objDiffs))
+	changes := make([]policy.Change, 0, len(objDiffs))
 	for _, objDiff := range objDiffs {
 		ddl, err := NewDDLStatement(objDiff, mods, t)
 		if ddl == nil && err == nil {
 			continue // Skip entirely if mods made the statement a noop
 		}
 		result.Differences = true
+		t.logEvent(objectDiffEvent(objDiff))
+		var avroProblems []string
 		if err == nil {
+			avroProblems, err = t.checkAvroCompat(objDiff)
+		}
+		if err == nil {
+			ddl.rollback = rollbacks[objDiff.ObjectKey()]
+			if from := fromObjects[objDiff.ObjectKey()]; from != nil {
+				ddl.before = from.Def()
+			}
+			if to := toObjects[objDiff.ObjectKey()]; to != nil {
+				ddl.after = to.Def()
+			}
 			stmts = append(stmts, ddl)
 			keys = append(keys, objDiff.ObjectKey())
+			change := policy.Change{
+				ObjectType: string(objDiff.ObjectKey().Type),
+				ObjectName: objDiff.ObjectKey().Name,
+				DiffType:   objDiff.DiffType().String(),
+				Statement:  ddl.Statement(),
+
+				AvroIncompatible: avroProblems,
+			}
+			if _, err := objDiff.Statement(tengo.StatementModifiers{Flavor: mods.Flavor}); tengo.IsForbiddenDiff(err) {
+				result.DestructiveCount++
+				change.Unsafe = true
+				ddl.unsafe = true
+			}
+			changes = append(changes, change)
+		} else if vetoErr, ok := err.(*VetoError); ok {
+			result.SkipCount++
+			log.Warnf("Skipping %s: %s", vetoErr.ObjectKey, vetoErr.Err)
+			t.logEvent(Event{Type: EventError, Object: vetoErr.ObjectKey.String(), Error: vetoErr.Error()})
 		} else if unsupportedErr, ok := err.(*tengo.UnsupportedDiffError); ok {
 			result.UnsupportedCount++
 			log.Warnf("Skipping %s: Skeema does not support generating a diff of this table. Use --debug to see which properties of this table are not supported.", unsupportedErr.ObjectKey)
+			if unsupportedErr.Hint != "" {
+				log.Warnf("Likely cause: %s", unsupportedErr.Hint)
+			}
 			if td, ok := objDiff.(*tengo.TableDiff); ok && td.From != nil && td.From.Engine != "InnoDB" {
 				log.Warnf("This table's storage engine is %s. Skeema is primarily designed to operate on InnoDB tables. Diff support for other engines is less complete.", td.From.Engine)
 			}
@@ -123,6 +258,7 @@ func ApplyTarget(t *Target, printer Printer) (Result, error) {
 		} else {
 			result.SkipCount += len(objDiffs)
 			log.Errorf(err.Error())
+			t.logEvent(Event{Type: EventError, Object: objDiff.ObjectKey().String(), Error: err.Error()})
 			if len(objDiffs) > 1 {
 				log.Warnf("Skipping %d additional operations for %s %s due to previous error\n", len(objDiffs)-1, t.Instance, t.SchemaName)
 			}
@@ -151,9 +287,62 @@ func ApplyTarget(t *Target, printer Printer) (Result, error) {
 		}
 	}
 
+	// Evaluate the changes against any configured policy; skip target if denied
+	if err := t.checkPolicy(changes); err != nil {
+		result.SkipCount += len(stmts)
+		log.Warnf("Skipping %s %s: %s\n", t.Instance, t.SchemaName, err)
+		t.logEvent(Event{Type: EventError, Error: err.Error()})
+		return result, nil
+	}
+
+	// Before executing anything, record the current definitions of all objects
+	// about to be modified, for use by `skeema undo-last-push`. If snapshot-dir
+	// was supplied on the command-line, failure to save the snapshot causes the
+	// target to be skipped; otherwise it's just a warning, since the option file
+	// may be shared by environments where the dir isn't writable.
+	if !t.Dir.Config.GetBool("dry-run") && len(stmts) > 0 {
+		if err := t.saveSnapshot(schemaFromInstance, keys); err != nil && t.Dir.Config.OnCLI("snapshot-dir") {
+			result.SkipCount += len(stmts)
+			log.Errorf("Skipping %s %s: unable to save pre-push snapshot: %s", t.Instance, t.SchemaName, err)
+			t.logEvent(Event{Type: EventError, Error: err.Error()})
+			return result, nil
+		} else if err != nil {
+			log.Warnf("%s %s: unable to save pre-push snapshot, so `skeema undo-last-push` will not be available: %s", t.Instance, t.SchemaName, err)
+		}
+	}
+
 	// Print SQL; if not dry-run, execute it; final logging; return result
-	result.SkipCount += t.processSQL(stmts, printer)
+	result.StatementCount = len(stmts)
+	reportProgress(func(p *Progress) {
+		p.StatementsTotal += len(stmts)
+		for _, stmt := range stmts {
+			p.BytesTotal += estimatedBytes(stmt)
+		}
+	})
+	ctx, span := tengo.StartSpan(context.Background(), "skeema.ExecuteStatements", map[string]string{
+		"db.instance": t.Instance.String(),
+		"db.name":     t.SchemaName,
+	})
+	result.SkipCount += t.processSQL(ctx, stmts, printer)
+	span.End(nil)
+
+	// With ddl-strategy, Vitess only schedules the schema changes, so reference
+	// data and the version-table can't be updated until they actually complete
+	pending := pendingMigrations(stmts)
+	if len(pending) > 0 {
+		log.Warnf("%s %s: %s scheduled by Vitess OnlineDDL and may still be pending; reference data and version-table were not updated. Check status via SHOW VITESS_MIGRATIONS. UUIDs: %s",
+			t.Instance, t.SchemaName, countAndNoun(len(pending), "schema change was", "schema changes were"), strings.Join(pending, ", "))
+	}
+	if !t.Dir.Config.GetBool("dry-run") && result.SkipCount == 0 && len(pending) == 0 {
+		result.ErrorCount += t.loadFixtures()
+	}
+	if !t.Dir.Config.GetBool("dry-run") && result.SkipCount == 0 && len(pending) == 0 {
+		if err := t.recordVersion(stmts); err != nil {
+			log.Warnf("%s %s: Unable to record push in version-table: %s", t.Instance, t.SchemaName, err)
+		}
+	}
 	t.logApplyEnd(result)
+	t.logEvent(Event{Type: EventTargetComplete})
 	return result, nil
 }
 
@@ -188,11 +377,13 @@ func StatementModifiersForDir(dir *fs.Dir) (mods tengo.StatementModifiers, err e
 	mods.AllowUnsafe = dir.Config.GetBool("allow-unsafe")
 	mods.CompareMetadata = dir.Config.GetBool("compare-metadata")
 	mods.VirtualColValidation = dir.Config.GetBool("alter-validate-virtual")
+	mods.LaxCheckNaming = dir.Config.GetBool("canonical-check-names")
 	if dir.Config.GetBool("exact-match") {
 		mods.StrictIndexOrder = true
 		mods.StrictCheckOrder = true // only affects MariaDB
 		mods.StrictForeignKeyNaming = true
 		mods.StrictColumnDefinition = true // only affects MySQL 8
+		mods.LaxCheckNaming = false
 	}
 	if mods.AlgorithmClause, err = dir.Config.GetEnum("alter-algorithm", "inplace", "copy", "instant", "nocopy", "default"); err != nil {
 		return
@@ -226,3 +417,79 @@ func (ce ConfigError) Error() string {
 func (ce ConfigError) ExitCode() int {
 	return 78
 }
+
+// rollbackStatement is a statement which reverses a generated statement.
+type rollbackStatement struct {
+	stmt     string
+	compound bool
+}
+
+// rollbackStatements returns statements which would reverse the diff from
+// schema "from" to schema "to", keyed by the affected object. Destructive
+// statements are permitted, since reversing a CREATE requires a DROP. Objects
+// whose reverse diff is unsupported are omitted.
+func rollbackStatements(from, to *tengo.Schema, mods tengo.StatementModifiers) map[tengo.ObjectKey]rollbackStatement {
+	mods.AllowUnsafe = true
+	result := make(map[tengo.ObjectKey]rollbackStatement)
+	for _, objDiff := range tengo.NewSchemaDiff(to, from).ObjectDiffs() {
+		if stmt, err := objDiff.Statement(mods); err == nil && stmt != "" {
+			rs := rollbackStatement{stmt: stmt}
+			if compounder, ok := objDiff.(tengo.Compounder); ok {
+				rs.compound = compounder.IsCompoundStatement()
+			}
+			result[objDiff.ObjectKey()] = rs
+		}
+	}
+	return result
+}
+
+// unsupportedTableNames returns the sorted names of tables in any of the
+// supplied schemas which have UnsupportedDDL, logging the likely cause of each
+// at Warn level.
+func unsupportedTableNames(flavor tengo.Flavor, schemas ...*tengo.Schema) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, schema := range schemas {
+		if schema == nil {
+			continue
+		}
+		for _, table := range schema.Tables {
+			if details := table.UnsupportedDetails(flavor); details != nil && !seen[table.Name] {
+				seen[table.Name] = true
+				names = append(names, table.Name)
+				log.Warnf("Table %s.%s uses unsupported features. Likely cause: %s", tengo.EscapeIdentifier(schema.Name), tengo.EscapeIdentifier(table.Name), details.Reason)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// alignNameCase adjusts the names of tables in to, so that any which only
+// differ in letter case from a table in from will use the same name as in
+// from. This only has an effect with lower_case_table_names=2, where the
+// server preserves the letter case used at creation time but compares names
+// case-insensitively; otherwise, such tables would erroneously be treated as
+// a DROP of one table and CREATE of another. The to schema's Tables slice is
+// replaced rather than modified in-place, since it may be shared.
+func alignNameCase(from, to *tengo.Schema, mode tengo.NameCaseMode) {
+	if mode != tengo.NameCaseInsensitive || from == nil || to == nil {
+		return
+	}
+	fromTables := from.TablesByName()
+	tables := make([]*tengo.Table, len(to.Tables))
+	for n, table := range to.Tables {
+		tables[n] = table
+		if fromTables[table.Name] != nil {
+			continue
+		}
+		for _, fromTable := range from.Tables {
+			if mode.NamesEqual(fromTable.Name, table.Name) {
+				log.Debugf("Treating table %s as %s, since lower_case_table_names=2 makes table names case-insensitive", tengo.EscapeIdentifier(table.Name), tengo.EscapeIdentifier(fromTable.Name))
+				tables[n] = table.Renamed(fromTable.Name)
+				break
+			}
+		}
+	}
+	to.Tables = tables
+}
@@ -3,6 +3,7 @@ package applier
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/skeema/skeema/internal/tengo"
@@ -23,16 +24,26 @@ func TestResultMerge(t *testing.T) {
 		Differences:      false,
 		SkipCount:        1,
 		UnsupportedCount: 0,
+		ObjectCount:      10,
+		StatementCount:   0,
 	}
 	other := Result{
 		Differences:      true,
 		SkipCount:        3,
 		UnsupportedCount: 5,
+		ErrorCount:       1,
+		ObjectCount:      7,
+		StatementCount:   2,
+		DestructiveCount: 1,
 	}
 	expectSum := Result{
 		Differences:      true,
 		SkipCount:        4,
 		UnsupportedCount: 5,
+		ErrorCount:       1,
+		ObjectCount:      17,
+		StatementCount:   2,
+		DestructiveCount: 1,
 	}
 	r.Merge(other)
 	if r != expectSum {
@@ -40,6 +51,115 @@ func TestResultMerge(t *testing.T) {
 	}
 }
 
+func TestResultSummary(t *testing.T) {
+	cases := map[Result]string{
+		{}:                                   "",
+		{SkipCount: 1}:                       "Skipped 1 operation due to problem",
+		{SkipCount: 1, UnsupportedCount: 2}:  "Skipped 3 operations due to problems or unsupported features",
+		{ErrorCount: 2}:                      "Unable to load reference data for 2 tables",
+		{UnsupportedCount: 1, ErrorCount: 1}: "Skipped 1 operation due to unsupported feature; Unable to load reference data for 1 table",
+	}
+	for r, expected := range cases {
+		if actual := r.Summary(); actual != expected {
+			t.Errorf("Unexpected result from Summary() on %+v: expected %q, found %q", r, expected, actual)
+		}
+		if r.Failed() != (expected != "") {
+			t.Errorf("Unexpected result from Failed() on %+v", r)
+		}
+	}
+}
+
+func TestRollbackStatements(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	build := func(name string, cols ...string) *tengo.Table {
+		t.Helper()
+		builder := tengo.NewTableBuilder(name).Column("id", "int", tengo.NotNull())
+		for _, col := range cols {
+			builder = builder.Column(col, "int")
+		}
+		table, err := builder.PrimaryKey("id").Build(flavor)
+		if err != nil {
+			t.Fatalf("Unexpected error building table: %v", err)
+		}
+		return table
+	}
+	from := &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("a"), build("b")}}
+	to := &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("a", "x"), build("c")}}
+	rollbacks := rollbackStatements(from, to, tengo.StatementModifiers{Flavor: flavor})
+	expected := map[string]string{
+		"a": "ALTER TABLE `a` DROP COLUMN `x`",
+		"b": "CREATE TABLE `b`",
+		"c": "DROP TABLE `c`",
+	}
+	if len(rollbacks) != len(expected) {
+		t.Errorf("Expected %d rollback statements, instead found %d: %v", len(expected), len(rollbacks), rollbacks)
+	}
+	for name, prefix := range expected {
+		key := tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: name}
+		if rs := rollbacks[key]; !strings.HasPrefix(rs.stmt, prefix) || rs.compound {
+			t.Errorf("Unexpected rollback for %s: expected prefix %q, found %+v", key, prefix, rs)
+		}
+	}
+}
+
+func TestUnsupportedTableNames(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	build := func(name string, unsupported bool) *tengo.Table {
+		t.Helper()
+		table, err := tengo.NewTableBuilder(name).Column("id", "int", tengo.NotNull()).PrimaryKey("id").Build(flavor)
+		if err != nil {
+			t.Fatalf("Unexpected error building table: %v", err)
+		}
+		if unsupported {
+			table.CreateStatement += " SECONDARY_ENGINE=RAPID"
+			table.UnsupportedDDL = true
+		}
+		return table
+	}
+	from := &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("a", false), build("c", true), build("d", true)}}
+	to := &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("a", false), build("b", true), build("c", true)}}
+	if names := unsupportedTableNames(flavor, from, to, nil); fmt.Sprint(names) != "[b c d]" {
+		t.Errorf("Unexpected result from unsupportedTableNames: %v", names)
+	}
+	if names := unsupportedTableNames(flavor, &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("a", false)}}); len(names) != 0 {
+		t.Errorf("Expected no unsupported tables, instead found %v", names)
+	}
+}
+
+func TestAlignNameCase(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	build := func(name string) *tengo.Table {
+		t.Helper()
+		table, err := tengo.NewTableBuilder(name).Column("id", "int", tengo.NotNull()).PrimaryKey("id").Build(flavor)
+		if err != nil {
+			t.Fatalf("Unexpected error building table: %v", err)
+		}
+		return table
+	}
+	from := &tengo.Schema{Name: "s", Tables: []*tengo.Table{build("Foo"), build("bar")}}
+	origTo := []*tengo.Table{build("foo"), build("bar"), build("Baz")}
+	to := &tengo.Schema{Name: "s", Tables: origTo}
+
+	alignNameCase(from, to, tengo.NameCaseAsIs)
+	if to.Tables[0].Name != "foo" {
+		t.Errorf("Expected no renames with NameCaseAsIs, but table was renamed to %s", to.Tables[0].Name)
+	}
+
+	alignNameCase(from, to, tengo.NameCaseInsensitive)
+	if to.Tables[0].Name != "Foo" || !strings.HasPrefix(to.Tables[0].CreateStatement, "CREATE TABLE `Foo`") {
+		t.Errorf("Expected table to be renamed to Foo, instead found %s", to.Tables[0].CreateStatement)
+	}
+	if to.Tables[1] != origTo[1] || to.Tables[2] != origTo[2] {
+		t.Error("Expected tables without case differences to remain unchanged")
+	}
+	if origTo[0].Name != "foo" {
+		t.Error("Expected original Tables slice to remain unmodified")
+	}
+	if diff := tengo.NewSchemaDiff(from, to); len(diff.ObjectDiffs()) != 1 || diff.ObjectDiffs()[0].DiffType() != tengo.DiffTypeCreate {
+		t.Errorf("Unexpected diff after aligning name case: %+v", diff.ObjectDiffs())
+	}
+}
+
 func TestIntegration(t *testing.T) {
 	images := tengo.SplitEnv("SKEEMA_TEST_IMAGES")
 	if len(images) == 0 {
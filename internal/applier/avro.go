@@ -0,0 +1,38 @@
+package applier
+
+import (
+	"errors"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/export"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// checkAvroCompat examines an ALTER TABLE diff for changes which would break
+// backward compatibility of the table's Avro schema, as generated by a
+// Debezium connector for change data capture. Depending on the avro-compat
+// option, incompatibilities are ignored, logged as warnings, or returned as a
+// *VetoError to prevent the statement from being executed. The returned
+// strings describe any incompatibilities found.
+func (t *Target) checkAvroCompat(objDiff tengo.ObjectDiff) ([]string, error) {
+	severity, err := t.Dir.Config.GetEnum("avro-compat", "ignore", "warning", "error")
+	if err != nil {
+		return nil, ConfigError(err.Error())
+	}
+	td, ok := objDiff.(*tengo.TableDiff)
+	if severity == "ignore" || !ok || td.Type != tengo.DiffTypeAlter {
+		return nil, nil
+	}
+	problems := export.AvroIncompatibilities(td.From, td.To)
+	if len(problems) == 0 {
+		return nil, nil
+	} else if severity == "error" {
+		err := errors.New("backward-incompatible Avro schema change: " + strings.Join(problems, "; "))
+		return problems, &VetoError{Transformer: "avro-compat", ObjectKey: td.ObjectKey(), Err: err}
+	}
+	for _, problem := range problems {
+		log.Warnf("%s: backward-incompatible Avro schema change: %s", td.ObjectKey(), problem)
+	}
+	return problems, nil
+}
@@ -0,0 +1,51 @@
+package applier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestCheckAvroCompat(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	buildSchema := func(amountType string, extraCols ...string) *tengo.Schema {
+		t.Helper()
+		builder := tengo.NewTableBuilder("orders").
+			Column("id", "int", tengo.NotNull()).
+			Column("amount", amountType, tengo.NotNull())
+		for _, col := range extraCols {
+			builder = builder.Column(col, "varchar(20)", tengo.NotNull())
+		}
+		table, err := builder.PrimaryKey("id").Build(flavor)
+		if err != nil {
+			t.Fatalf("Unexpected error building table: %v", err)
+		}
+		return &tengo.Schema{Name: "product", Tables: []*tengo.Table{table}}
+	}
+	// Adding a NOT NULL column without a default is incompatible, as is changing
+	// decimal scale
+	incompatible := tengo.NewSchemaDiff(buildSchema("decimal(10,2)"), buildSchema("decimal(10,3)", "note")).ObjectDiffs()
+	if len(incompatible) != 1 {
+		t.Fatalf("Expected 1 object diff, instead found %d", len(incompatible))
+	}
+
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	if problems, err := target.checkAvroCompat(incompatible[0]); len(problems) != 0 || err != nil {
+		t.Errorf("Expected no problems with avro-compat=ignore, instead found %v, %v", problems, err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--avro-compat=warning")
+	if problems, err := target.checkAvroCompat(incompatible[0]); len(problems) != 2 || err != nil {
+		t.Errorf("Expected 2 problems and no error with avro-compat=warning, instead found %v, %v", problems, err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--avro-compat=error")
+	var vetoErr *VetoError
+	if problems, err := target.checkAvroCompat(incompatible[0]); len(problems) != 2 || !errors.As(err, &vetoErr) {
+		t.Errorf("Expected 2 problems and a VetoError with avro-compat=error, instead found %v, %v", problems, err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--avro-compat=bogus")
+	if _, err := target.checkAvroCompat(incompatible[0]); err == nil {
+		t.Error("Expected error from invalid avro-compat value, but err was nil")
+	}
+}
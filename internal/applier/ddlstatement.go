@@ -1,14 +1,18 @@
 package applier
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
 	"github.com/skeema/skeema/internal/tengo"
 	"github.com/skeema/skeema/internal/util"
 )
@@ -17,9 +21,19 @@ import (
 // It may represent an external command to shell out to, or a DDL statement to
 // run directly against a DB.
 type DDLStatement struct {
-	stmt     string
-	compound bool
-	shellOut *util.ShellOut
+	stmt      string
+	compound  bool
+	shellOut  *util.ShellOut
+	key       tengo.ObjectKey
+	diffType  tengo.DiffType
+	rollback  rollbackStatement // only populated when generating migration files
+	tableSize int64             // only populated if needed for options or progress reporting
+	unsafe    bool              // true if the statement is potentially destructive
+	before    string            // only populated when generating plan output
+	after     string            // only populated when generating plan output
+
+	onlineDDL     bool   // true if submitted to Vitess OnlineDDL via ddl-strategy
+	migrationUUID string // only populated after Execute schedules an OnlineDDL migration
 
 	instance      *tengo.Instance
 	schemaName    string
@@ -30,11 +44,14 @@ type DDLStatement struct {
 // being a no-op due to mods, both returned values will be nil. In the case of
 // an error constructing the statement (mods disallowing destructive DDL,
 // invalid variable interpolation in --alter-wrapper, etc), the DDLStatement
-// pointer will be nil, and a non-nil error will be returned.
+// pointer will be nil, and a non-nil error will be returned; this includes a
+// *VetoError if a StatementTransformer vetoed the statement.
 func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, target *Target) (ddl *DDLStatement, err error) {
 	ddl = &DDLStatement{
 		instance:   target.Instance,
 		schemaName: target.SchemaName,
+		key:        diff.ObjectKey(),
+		diffType:   diff.DiffType(),
 	}
 
 	// Don't run database-level DDL in a schema; not even possible for CREATE
@@ -50,6 +67,7 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 		if tableSize, err = getTableSize(target, diff.ObjectKey().Name); err != nil {
 			return nil, err
 		}
+		ddl.tableSize = tableSize
 
 		// If --safe-below-size option in use, enable additional statement modifier
 		// if the table's size is less than the supplied option value
@@ -61,6 +79,14 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 		}
 	}
 
+	// Table size of ALTERs is also used for estimating progress, if requested
+	if tableSize == 0 && progressEnabled() && diff.ObjectKey().Type == tengo.ObjectTypeTable && diff.DiffType() == tengo.DiffTypeAlter {
+		if ddl.tableSize, err = getTableSize(target, diff.ObjectKey().Name); err != nil {
+			log.Debugf("Unable to obtain size of %s for progress estimate: %s", diff.ObjectKey(), err)
+			ddl.tableSize = 0
+		}
+	}
+
 	// Options may indicate some/all DDL gets executed by shelling out to another program.
 	wrapper, err := getWrapper(target.Dir.Config, diff, tableSize, &mods)
 	if err != nil {
@@ -81,6 +107,18 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 		return nil, nil
 	}
 
+	// Apply any statement transformers, which may rewrite or veto the DDL
+	origStmt := ddl.stmt
+	info := StatementInfo{
+		InstanceName: ddl.instance.String(),
+		SchemaName:   ddl.schemaName,
+		ObjectKey:    ddl.key,
+		DiffType:     diff.DiffType(),
+	}
+	if ddl.stmt, err = target.transformStatement(info, ddl.stmt); err != nil {
+		return nil, err
+	}
+
 	// Determine if the statement is a compound statement, requiring special
 	// delimiter handling in output. Only stored program diffs (e.g. procs, funcs)
 	// implement this interface; others never generate compound statements.
@@ -88,8 +126,19 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 		ddl.compound = true
 	}
 
+	skipBinlog := !target.Dir.Config.GetBool("binlog")
 	if wrapper == "" {
 		ddl.connectParams = getConnectParams(diff, target.Dir.Config)
+		if skipBinlog {
+			ddl.connectParams = strings.TrimLeft(ddl.connectParams+"&sql_log_bin=0", "&")
+		}
+		if strategy := target.Dir.Config.Get("ddl-strategy"); strategy != "" {
+			if !ddl.instance.Flavor().HasVariant(tengo.VariantVitess) {
+				return nil, ConfigError(fmt.Sprintf("Option ddl-strategy requires a Vitess vtgate, but %s is %s", ddl.instance, ddl.instance.Flavor()))
+			}
+			ddl.connectParams = strings.TrimLeft(ddl.connectParams+"&ddl_strategy="+url.QueryEscape(ddlStrategyValue(strategy)), "&")
+			ddl.onlineDDL = true
+		}
 	} else {
 		var socket, port, connOpts string
 		if ddl.instance.SocketPath != "" {
@@ -100,6 +149,12 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 		if connOpts, err = util.RealConnectOptions(target.Dir.Config.Get("connect-options")); err != nil {
 			return nil, ConfigError(err.Error())
 		}
+		if connOpts, err = appendSessionVariables(connOpts, target.Dir); err != nil {
+			return nil, ConfigError(err.Error())
+		}
+		if skipBinlog {
+			connOpts = strings.TrimLeft(connOpts+",sql_log_bin=0", ",")
+		}
 		variables := map[string]string{
 			"HOST":        ddl.instance.Host,
 			"PORT":        port,
@@ -118,10 +173,17 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 			"CONNOPTS":    connOpts,
 			"DIRNAME":     target.Dir.BaseName(),
 			"DIRPATH":     target.Dir.Path,
+			"MAXLAG":      target.Dir.Config.Get("max-replica-lag"),
+			"REPLICAS":    target.Dir.Config.Get("throttle-replicas"),
 		}
 		if diff.ObjectKey().Type == tengo.ObjectTypeTable {
 			td := diff.(*tengo.TableDiff)
-			variables["CLAUSES"], _ = td.Clauses(mods)
+			origClauses, _ := td.Clauses(mods)
+			clauses, ok := transformedClauses(origStmt, origClauses, ddl.stmt)
+			if !ok && strings.Contains(strings.ToUpper(wrapper), "{CLAUSES}") {
+				return nil, ConfigError(fmt.Sprintf("Statement transformation for %s changed more than the table's clauses, so it cannot be used with an alter-wrapper or ddl-wrapper containing {CLAUSES}", diff.ObjectKey()))
+			}
+			variables["CLAUSES"] = clauses
 			variables["TABLE"] = variables["NAME"]
 		}
 
@@ -135,6 +197,25 @@ func NewDDLStatement(diff tengo.ObjectDiff, mods tengo.StatementModifiers, targe
 	return ddl, nil
 }
 
+// transformedClauses returns the clauses portion of newStmt, which is the
+// result of applying statement transformers to origStmt. The original clauses
+// are a suffix of origStmt; if newStmt retains the same prefix (e.g. "ALTER
+// TABLE [name] "), everything after it is returned. Otherwise the clauses
+// cannot be determined, and the original clauses are returned along with false.
+func transformedClauses(origStmt, origClauses, newStmt string) (string, bool) {
+	if newStmt == origStmt {
+		return origClauses, true
+	}
+	if !strings.HasSuffix(origStmt, origClauses) {
+		return origClauses, false
+	}
+	prefix := origStmt[:len(origStmt)-len(origClauses)]
+	if !strings.HasPrefix(newStmt, prefix) {
+		return origClauses, false
+	}
+	return newStmt[len(prefix):], true
+}
+
 // needTableSize returns true if diff represents an ALTER TABLE or DROP TABLE,
 // and at least one size-related option is in use, meaning that it will be
 // necessary to query for the table's size.
@@ -214,20 +295,69 @@ func getConnectParams(diff tengo.ObjectDiff, config *mybase.Config) string {
 	// operations can be slow on large tables.
 	// For ALTER TABLE, if requested, also use foreign_key_checks=1 if adding
 	// new foreign key constraints.
+	// MariaDB system-versioned tables can only be altered if history retention is
+	// explicitly permitted.
 	if td, ok := diff.(*tengo.TableDiff); ok && td.Type == tengo.DiffTypeAlter {
+		params := "readTimeout=0"
 		if config.GetBool("foreign-key-checks") {
 			_, addFKs := td.SplitAddForeignKeys()
 			if addFKs != nil {
-				return "readTimeout=0&foreign_key_checks=1"
+				params += "&foreign_key_checks=1"
 			}
 		}
-		return "readTimeout=0"
+		if td.From.SystemVersioned {
+			params += "&system_versioning_alter_history=KEEP"
+		}
+		return params
 	} else if ok && td.Type == tengo.DiffTypeDrop {
 		return "readTimeout=0"
 	}
 	return ""
 }
 
+// appendSessionVariables adds any session variables from the dir's sql-mode and
+// session-init options to connOpts, a comma-separated string in the same format
+// as the connect-options option, for use by external tools.
+func appendSessionVariables(connOpts string, dir *fs.Dir) (string, error) {
+	vars, err := dir.SessionVariables()
+	if err != nil || len(vars) == 0 {
+		return connOpts, err
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assignments := make([]string, 0, len(names)+1)
+	if connOpts != "" {
+		assignments = append(assignments, connOpts)
+	}
+	for _, name := range names {
+		assignments = append(assignments, name+"="+vars[name])
+	}
+	return strings.Join(assignments, ","), nil
+}
+
+// ddlStrategyValue returns the supplied ddl-strategy option value as a quoted
+// string literal, suitable for use as the value of Vitess's @@ddl_strategy
+// session variable. Vitess OnlineDDL then schedules each statement
+// asynchronously, rather than executing it directly and blocking.
+func ddlStrategyValue(strategy string) string {
+	return "'" + strings.ReplaceAll(strategy, "'", "''") + "'"
+}
+
+// pendingMigrations returns the UUIDs of any Vitess OnlineDDL migrations which
+// were scheduled by executing stmts. Vitess runs these asynchronously, so they
+// may still be queued or running.
+func pendingMigrations(stmts []PlannedStatement) (uuids []string) {
+	for _, stmt := range stmts {
+		if ddl, ok := stmt.(*DDLStatement); ok && ddl.migrationUUID != "" {
+			uuids = append(uuids, ddl.migrationUUID)
+		}
+	}
+	return uuids
+}
+
 // Execute runs the DDL statement, either by running a SQL query against a DB,
 // or shelling out to an external program, as appropriate.
 func (ddl *DDLStatement) Execute() error {
@@ -238,6 +368,16 @@ func (ddl *DDLStatement) Execute() error {
 	if err != nil {
 		return err
 	}
+	if ddl.onlineDDL {
+		// Vitess OnlineDDL returns the UUID of the scheduled migration, or no rows if
+		// the statement was executed directly (e.g. ddl-strategy=direct, or a DDL
+		// type which OnlineDDL does not support)
+		err = db.QueryRow(ddl.stmt).Scan(&ddl.migrationUUID)
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return err
+	}
 	_, err = db.Exec(ddl.stmt)
 	return err
 }
@@ -252,6 +392,36 @@ func (ddl *DDLStatement) Statement() string {
 	return ddl.stmt
 }
 
+// ObjectKey returns the key of the object affected by the statement.
+func (ddl *DDLStatement) ObjectKey() tengo.ObjectKey {
+	return ddl.key
+}
+
+// DiffType returns the type of diff which generated the statement.
+func (ddl *DDLStatement) DiffType() tengo.DiffType {
+	return ddl.diffType
+}
+
+// Rollback returns a statement which reverses ddl, if one was generated, and
+// whether that statement is a compound statement. This is only populated when
+// outputting migration files.
+func (ddl *DDLStatement) Rollback() (string, bool) {
+	return ddl.rollback.stmt, ddl.rollback.compound
+}
+
+// Unsafe returns true if the statement is potentially destructive, regardless
+// of whether the configuration permits running it.
+func (ddl *DDLStatement) Unsafe() bool {
+	return ddl.unsafe
+}
+
+// Definitions returns the CREATE statements of the affected object before and
+// after ddl is executed; either may be blank if the object does not exist at
+// that point. This is only populated when outputting a plan.
+func (ddl *DDLStatement) Definitions() (before, after string) {
+	return ddl.before, ddl.after
+}
+
 // ClientState returns a representation of the client state which would be
 // used in execution of the statement.
 func (ddl *DDLStatement) ClientState() ClientState {
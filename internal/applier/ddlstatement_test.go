@@ -63,6 +63,11 @@ func (s ApplierIntegrationSuite) TestNewDDLStatement(t *testing.T) {
 		"alter-lock":             "none",
 		"safe-below-size":        "0",
 		"connect-options":        "",
+		"session-init":           "",
+		"sql-mode":               "",
+		"binlog":                 "1",
+		"throttle-replicas":      "",
+		"max-replica-lag":        "0",
 		"environment":            "production",
 	}
 	if flavor.Matches(tengo.FlavorMySQL55) {
@@ -171,3 +176,30 @@ func objectDiffExpected(t *testing.T, diff tengo.ObjectDiff, ddl *DDLStatement,
 	}
 	return
 }
+
+func TestDDLStrategyValue(t *testing.T) {
+	cases := map[string]string{
+		"vitess":                         "'vitess'",
+		"vitess --postpone-completion":   "'vitess --postpone-completion'",
+		"online --singleton-context='x'": "'online --singleton-context=''x'''",
+	}
+	for input, expected := range cases {
+		if actual := ddlStrategyValue(input); actual != expected {
+			t.Errorf("Expected ddlStrategyValue(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	stmts := []PlannedStatement{
+		&DDLStatement{stmt: "CREATE TABLE foo (id int)", onlineDDL: true},
+		&DDLStatement{stmt: "ALTER TABLE bar ADD COLUMN x int", onlineDDL: true, migrationUUID: "a1b2c3d4_e5f6_11ee_8000_0a0b0c0d0e0f"},
+		&DDLStatement{stmt: "ALTER TABLE baz ADD COLUMN y int"},
+	}
+	if uuids := pendingMigrations(stmts[2:]); len(uuids) != 0 {
+		t.Errorf("Expected no pending migrations, instead found %v", uuids)
+	}
+	if uuids := pendingMigrations(stmts); len(uuids) != 1 || uuids[0] != "a1b2c3d4_e5f6_11ee_8000_0a0b0c0d0e0f" {
+		t.Errorf("Unexpected result from pendingMigrations: %v", uuids)
+	}
+}
@@ -0,0 +1,111 @@
+package applier
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// Constants enumerating event types written to an EventLog
+const (
+	EventIntrospectionStart = "introspection_start"
+	EventObjectDiff         = "object_diff"
+	EventStatementExecuted  = "statement_executed"
+	EventError              = "error"
+	EventTargetComplete     = "target_complete"
+)
+
+// Event represents a single lifecycle event of a diff or push.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Instance  string    `json:"instance,omitempty"`
+	Schema    string    `json:"schema,omitempty"`
+	Object    string    `json:"object,omitempty"`
+	DiffType  string    `json:"diff_type,omitempty"`
+	Statement string    `json:"statement,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// EventLog writes Events as JSON lines, one object per line. It is safe for
+// concurrent use.
+type EventLog struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewEventLog returns an EventLog which writes to the file at path, creating
+// or truncating it. If path is "-", events are written to STDOUT instead.
+func NewEventLog(path string) (*EventLog, error) {
+	if path == "-" {
+		return &EventLog{enc: json.NewEncoder(os.Stdout)}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &EventLog{enc: json.NewEncoder(f), closer: f}, nil
+}
+
+// Write appends an event to the log. If the event's Time is zero, it is set to
+// the current time. Write errors are ignored, since the event log is purely
+// informational.
+func (el *EventLog) Write(e Event) {
+	if el == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	el.mu.Lock()
+	el.enc.Encode(e)
+	el.mu.Unlock()
+}
+
+// Close closes the underlying file, if any.
+func (el *EventLog) Close() error {
+	if el == nil || el.closer == nil {
+		return nil
+	}
+	return el.closer.Close()
+}
+
+var eventLog struct {
+	sync.RWMutex
+	log *EventLog
+}
+
+// SetEventLog causes all subsequent lifecycle events to be written to el.
+// Supply nil to stop writing events.
+func SetEventLog(el *EventLog) {
+	eventLog.Lock()
+	eventLog.log = el
+	eventLog.Unlock()
+}
+
+// logEvent writes e to the current EventLog, if any, filling in the instance
+// and schema from t.
+func (t *Target) logEvent(e Event) {
+	eventLog.RLock()
+	el := eventLog.log
+	eventLog.RUnlock()
+	if el == nil {
+		return
+	}
+	e.Instance = t.Instance.String()
+	e.Schema = t.SchemaName
+	el.Write(e)
+}
+
+func objectDiffEvent(diff tengo.ObjectDiff) Event {
+	return Event{
+		Type:     EventObjectDiff,
+		Object:   diff.ObjectKey().String(),
+		DiffType: diff.DiffType().String(),
+	}
+}
@@ -0,0 +1,69 @@
+package applier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	el, err := NewEventLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error from NewEventLog: %v", err)
+	}
+	SetEventLog(el)
+	defer SetEventLog(nil)
+
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	target.logEvent(Event{Type: EventIntrospectionStart})
+	stmts := []PlannedStatement{
+		&fakeStatement{stmt: "DROP TABLE posts"},
+		&fakeStatement{stmt: "DROP TABLE posts", err: errors.New("boom")},
+	}
+	target.processSQL(context.Background(), stmts, &fakePrinter{})
+	if err := el.Close(); err != nil {
+		t.Fatalf("Unexpected error from Close: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Unable to open events file: %v", err)
+	}
+	defer f.Close()
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Unable to unmarshal event line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, instead found %d: %+v", len(events), events)
+	}
+	if events[0].Type != EventIntrospectionStart || events[0].Instance != "127.0.0.1:3306" || events[0].Schema != "product" || events[0].Time.IsZero() {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if events[1].Type != EventStatementExecuted || events[1].Error != "" || events[1].Object != "table `posts`" {
+		t.Errorf("Unexpected second event: %+v", events[1])
+	}
+	if events[2].Type != EventStatementExecuted || events[2].Error != "boom" {
+		t.Errorf("Unexpected third event: %+v", events[2])
+	}
+
+	// nil EventLog should be safe to use
+	var nilLog *EventLog
+	nilLog.Write(Event{Type: EventError})
+	if err := nilLog.Close(); err != nil {
+		t.Errorf("Unexpected error closing nil EventLog: %v", err)
+	}
+}
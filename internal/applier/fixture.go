@@ -0,0 +1,55 @@
+package applier
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/fixture"
+)
+
+// loadFixtures upserts the rows from the CSV files of any tables configured as
+// reference data. Tables without a CSV file are ignored. The number of tables
+// which could not be loaded is returned.
+func (t *Target) loadFixtures() (errorCount int) {
+	opts, err := fixture.OptionsForDir(t.Dir)
+	if err != nil {
+		log.Errorf("Unable to load reference data for %s %s: %s", t.Instance, t.SchemaName, err)
+		return 1
+	}
+	tables := opts.Matches(t.DesiredSchema.Schema)
+	if len(tables) == 0 {
+		return 0
+	}
+	// Rows may refer to other reference tables, which may not have been loaded
+	// yet, so disable foreign key checks. With --skip-binlog, the rows must not
+	// replicate either, consistent with the DDL.
+	params := "foreign_key_checks=0"
+	if !t.Dir.Config.GetBool("binlog") {
+		params += "&sql_log_bin=0"
+	}
+	db, err := t.Instance.CachedConnectionPool(t.SchemaName, params)
+	if err != nil {
+		log.Errorf("Unable to load reference data for %s %s: %s", t.Instance, t.SchemaName, err)
+		return len(tables)
+	}
+	for _, table := range tables {
+		path := opts.Path(table.Name)
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			log.Errorf("Unable to load reference data for %s: %s", table.ObjectKey(), err)
+			errorCount++
+			continue
+		}
+		count, err := fixture.Load(db, table, f, opts.Masks)
+		f.Close()
+		if err != nil {
+			log.Errorf("Unable to load reference data for %s: %s: %s", table.ObjectKey(), path, err)
+			errorCount++
+		} else {
+			log.Infof("Loaded %d rows from %s into %s %s.%s", count, path, t.Instance, t.SchemaName, table.Name)
+		}
+	}
+	return errorCount
+}
@@ -0,0 +1,151 @@
+package applier
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/grants"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/util"
+)
+
+// GrantStatement is a GRANT or REVOKE statement which reconciles the privileges
+// of an account on an instance with those configured in a host-level dir. It
+// satisfies the PlannedStatement interface.
+type GrantStatement struct {
+	stmt     string
+	instance *tengo.Instance
+	revoke   bool
+}
+
+// Execute runs the statement on the instance.
+func (gs *GrantStatement) Execute() error {
+	db, err := gs.instance.CachedConnectionPool("", "")
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(gs.stmt)
+	return err
+}
+
+// Statement returns the statement text, without any trailing delimiter.
+func (gs *GrantStatement) Statement() string {
+	return gs.stmt
+}
+
+// ClientState returns a representation of the client state which would be
+// used in execution of the statement. Grants are not specific to a schema.
+func (gs *GrantStatement) ClientState() ClientState {
+	return ClientState{
+		InstanceName: gs.instance.String(),
+		Delimiter:    ";",
+	}
+}
+
+// Unsafe returns true if the statement removes privileges.
+func (gs *GrantStatement) Unsafe() bool {
+	return gs.revoke
+}
+
+// ApplyGrantsForDir walks dir and its subdirs, reconciling the privileges of
+// accounts on each instance mapped by a host-level dir which enables the
+// grants option. Grants are applied after all schema changes, since they may
+// refer to newly-created objects.
+func ApplyGrantsForDir(dir *fs.Dir, printer Printer, maxDepth int) (result Result) {
+	// Problems parsing dirs are already reported by TargetsForDir
+	if dir.ParseError != nil {
+		return
+	}
+	if dir.Config.Changed("host") {
+		// Privileges are only managed at the host level; a dir which also defines
+		// a schema (flat layout) or is within a host dir is not eligible
+		opts, err := grants.OptionsForDir(dir)
+		if err != nil {
+			log.Errorf("Skipping grants for %s: %s", dir, err)
+			result.SkipCount++
+		} else if opts.Enabled {
+			instances, skipCount := instancesForDir(dir)
+			result.SkipCount += skipCount
+			for _, inst := range instances {
+				result.Merge(applyGrants(dir, inst, opts, printer))
+			}
+		}
+		return
+	}
+
+	subdirs, err := dir.Subdirs()
+	if err != nil || (len(subdirs) > 0 && maxDepth < 1) {
+		return // already reported by TargetsForDir
+	}
+	for _, subdir := range subdirs {
+		result.Merge(ApplyGrantsForDir(subdir, printer, maxDepth-1))
+	}
+	return
+}
+
+// applyGrants reconciles the privileges of accounts on inst with the GRANT
+// statements in dir.
+func applyGrants(dir *fs.Dir, inst *tengo.Instance, opts grants.Options, printer Printer) (result Result) {
+	desired, err := grants.FromDir(dir, opts)
+	if err != nil {
+		log.Errorf("Skipping grants for %s: %s", inst, err)
+		result.SkipCount++
+		return
+	}
+	actual, accounts, err := grants.Introspect(inst, opts)
+	if err != nil {
+		log.Errorf("Skipping grants for %s: %s", inst, err)
+		result.SkipCount++
+		return
+	}
+
+	var stmts []*GrantStatement
+	for _, change := range grants.Diff(actual, desired) {
+		result.Differences = true
+		if !accounts[change.Grantee] {
+			log.Errorf("Skipping grant for %s on %s: account does not exist. Skeema does not create or drop accounts.", change.Grantee, inst)
+			result.SkipCount++
+			continue
+		}
+		stmts = append(stmts, &GrantStatement{stmt: change.Statement, instance: inst, revoke: change.Revoke})
+		if change.Revoke {
+			result.DestructiveCount++
+		}
+	}
+
+	// REVOKEs are potentially destructive, so the entire set of changes is
+	// skipped unless explicitly permitted, just as with other unsafe changes
+	if result.DestructiveCount > 0 && !dir.Config.GetBool("allow-unsafe-grants") {
+		terminalWidth, _ := util.TerminalWidth(int(os.Stderr.Fd()))
+		for _, gs := range stmts {
+			if gs.revoke {
+				commentedOutStmt := "  # " + util.WrapStringWithPadding(gs.stmt, terminalWidth-29, "  # ")
+				log.Errorf("Preventing execution of unsafe or potentially destructive statement:\n%s\nUse --allow-unsafe-grants to permit this operation.", commentedOutStmt)
+			}
+		}
+		log.Warnf("Skipping %s for %s due to unsafe REVOKE statements\n", countAndNoun(len(stmts), "grant change"), inst)
+		result.SkipCount += len(stmts)
+		return
+	}
+
+	result.StatementCount = len(stmts)
+	for i, gs := range stmts {
+		printer.Print(gs)
+		if dir.Config.GetBool("dry-run") {
+			continue
+		}
+		if err := gs.Execute(); err != nil {
+			log.Errorf("Error running SQL statement on %s: %s\nFull SQL statement: %s;", inst, err, gs.stmt)
+			result.SkipCount += len(stmts) - i
+			if len(stmts)-i > 1 {
+				log.Warnf("Skipping %d remaining grant changes for %s due to previous error", len(stmts)-i-1, inst)
+			}
+			return
+		}
+	}
+	if len(stmts) > 0 && !dir.Config.GetBool("dry-run") {
+		log.Infof("%s: applied %s", inst, countAndNoun(len(stmts), "grant change"))
+	}
+	return
+}
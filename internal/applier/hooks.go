@@ -0,0 +1,117 @@
+package applier
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/util"
+)
+
+// HookPhase indicates whether a StatementEvent occurs before or after
+// execution of a statement.
+type HookPhase string
+
+// Constants enumerating hook phases
+const (
+	HookPhasePre  HookPhase = "pre"
+	HookPhasePost HookPhase = "post"
+)
+
+// StatementEvent provides context to a StatementHook.
+type StatementEvent struct {
+	Phase        HookPhase
+	InstanceName string
+	SchemaName   string
+	ObjectKey    tengo.ObjectKey // Zero value if statement does not affect a single object
+	Statement    string
+	Err          error // For HookPhasePost only: result of executing the statement
+}
+
+// StatementHook is a callback invoked before and after each statement is
+// executed by push. If a hook returns a non-nil error in HookPhasePre, the
+// statement is vetoed: it is skipped, but execution continues with the
+// target's remaining statements. Errors returned in HookPhasePost are logged
+// but otherwise ignored.
+type StatementHook func(event StatementEvent) error
+
+var statementHooks struct {
+	sync.RWMutex
+	hooks []StatementHook
+}
+
+// AddStatementHook registers a callback to be invoked around each executed
+// statement, in addition to any pre-statement-hook or post-statement-hook
+// shell commands configured via options. This permits custom gating or
+// notifications when using this package as a library.
+func AddStatementHook(hook StatementHook) {
+	statementHooks.Lock()
+	statementHooks.hooks = append(statementHooks.hooks, hook)
+	statementHooks.Unlock()
+}
+
+// runStatementHooks invokes all registered StatementHooks, followed by the
+// shell command configured for the event's phase, if any. Processing stops
+// upon the first error.
+func (t *Target) runStatementHooks(event StatementEvent) error {
+	statementHooks.RLock()
+	hooks := statementHooks.hooks
+	statementHooks.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(event); err != nil {
+			return err
+		}
+	}
+
+	optionName := string(event.Phase) + "-statement-hook"
+	command := t.Dir.Config.Get(optionName)
+	if command == "" {
+		return nil
+	}
+	var result, errText string
+	if event.Phase == HookPhasePost {
+		result = "success"
+		if event.Err != nil {
+			result, errText = "error", event.Err.Error()
+		}
+	}
+	variables := map[string]string{
+		"HOST":        t.Instance.Host,
+		"PORT":        strconv.Itoa(t.Instance.Port),
+		"SOCKET":      t.Instance.SocketPath,
+		"SCHEMA":      event.SchemaName,
+		"ENVIRONMENT": t.Dir.Config.Get("environment"),
+		"DDL":         event.Statement,
+		"NAME":        event.ObjectKey.Name,
+		"CLASS":       event.ObjectKey.Type.Caps(),
+		"DIRNAME":     t.Dir.BaseName(),
+		"DIRPATH":     t.Dir.Path,
+		"RESULT":      result,
+		"ERROR":       errText,
+	}
+	s, err := util.NewInterpolatedShellOut(command, variables)
+	if err != nil {
+		return ConfigError(fmt.Sprintf("Invalid %s: %s", optionName, err))
+	}
+	log.Debugf("Running %s: %s", optionName, s)
+	if err := s.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", optionName, err)
+	}
+	return nil
+}
+
+func newStatementEvent(phase HookPhase, stmt PlannedStatement) StatementEvent {
+	cs := stmt.ClientState()
+	event := StatementEvent{
+		Phase:        phase,
+		InstanceName: cs.InstanceName,
+		SchemaName:   cs.SchemaName,
+		Statement:    stmt.Statement(),
+	}
+	if keyer, ok := stmt.(tengo.ObjectKeyer); ok {
+		event.ObjectKey = keyer.ObjectKey()
+	}
+	return event
+}
@@ -0,0 +1,110 @@
+package applier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+type fakeStatement struct {
+	stmt     string
+	key      tengo.ObjectKey // defaults to table posts if zero value
+	executed bool
+	err      error
+}
+
+func (fs *fakeStatement) Execute() error {
+	fs.executed = true
+	return fs.err
+}
+
+func (fs *fakeStatement) Statement() string {
+	return fs.stmt
+}
+
+func (fs *fakeStatement) ClientState() ClientState {
+	return ClientState{InstanceName: "127.0.0.1:3306", SchemaName: "product", Delimiter: ";"}
+}
+
+func (fs *fakeStatement) ObjectKey() tengo.ObjectKey {
+	if fs.key == (tengo.ObjectKey{}) {
+		return tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "posts"}
+	}
+	return fs.key
+}
+
+func TestStatementHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Shell hooks not tested on Windows")
+	}
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	outFile := filepath.Join(t.TempDir(), "hook-output")
+	dir := getDir(t, "testdata/simple", "--pre-statement-hook='echo {PHASE}' --post-statement-hook='echo {RESULT} {CLASS} {NAME} >>"+outFile+"'")
+	target := &Target{Instance: inst, Dir: dir, SchemaName: "product"}
+
+	// Unknown variable in pre hook should prevent execution
+	stmt := &fakeStatement{stmt: "DROP TABLE posts"}
+	if err := target.runStatementHooks(newStatementEvent(HookPhasePre, stmt)); err == nil {
+		t.Error("Expected error from hook with unknown variable, but err was nil")
+	}
+
+	dir = getDir(t, "testdata/simple", "--pre-statement-hook='test {NAME} != posts' --post-statement-hook='echo {RESULT} {CLASS} {NAME} >>"+outFile+"'")
+	target.Dir = dir
+	var events []StatementEvent
+	AddStatementHook(func(event StatementEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	defer func() {
+		statementHooks.hooks = nil
+	}()
+	if skipCount := target.processSQL(context.Background(), []PlannedStatement{stmt}, &fakePrinter{}); skipCount != 1 || stmt.executed {
+		t.Errorf("Expected pre-statement-hook to prevent execution; instead found skipCount=%d executed=%t", skipCount, stmt.executed)
+	}
+
+	// A veto should only skip that statement, not subsequent ones
+	stmt.executed = false
+	other := &fakeStatement{stmt: "CREATE TABLE comments (id int)", key: tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "comments"}}
+	if skipCount := target.processSQL(context.Background(), []PlannedStatement{stmt, other}, &fakePrinter{}); skipCount != 1 || stmt.executed || !other.executed {
+		t.Errorf("Expected pre-statement-hook veto to only skip the vetoed statement; instead found skipCount=%d executed=%t,%t", skipCount, stmt.executed, other.executed)
+	}
+
+	// An invalid hook configuration should skip all remaining statements
+	target.Dir = getDir(t, "testdata/simple", "--pre-statement-hook='echo {PHASE}'")
+	other.executed = false
+	if skipCount := target.processSQL(context.Background(), []PlannedStatement{other, stmt}, &fakePrinter{}); skipCount != 2 || stmt.executed || other.executed {
+		t.Errorf("Expected invalid pre-statement-hook to skip all statements; instead found skipCount=%d executed=%t,%t", skipCount, other.executed, stmt.executed)
+	}
+	stmt.executed = false
+	if err := os.Remove(outFile); err != nil {
+		t.Fatalf("Unable to remove post-statement-hook output: %v", err)
+	}
+
+	dir = getDir(t, "testdata/simple", "--post-statement-hook='echo {RESULT} {CLASS} {NAME} >>"+outFile+"'")
+	target.Dir = dir
+	events = nil
+	stmt2 := &fakeStatement{stmt: "ALTER TABLE posts ADD COLUMN foo int", err: errors.New("boom")}
+	if skipCount := target.processSQL(context.Background(), []PlannedStatement{stmt, stmt2}, &fakePrinter{}); skipCount != 1 || !stmt.executed || !stmt2.executed {
+		t.Errorf("Unexpected result from processSQL: skipCount=%d executed=%t,%t", skipCount, stmt.executed, stmt2.executed)
+	}
+	if len(events) != 4 || events[0].Phase != HookPhasePre || events[3].Phase != HookPhasePost || events[3].Err == nil || events[1].ObjectKey.Name != "posts" {
+		t.Errorf("Unexpected events passed to StatementHook: %+v", events)
+	}
+	contents, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Unable to read post-statement-hook output: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(contents)), "\n"); len(lines) != 2 || lines[0] != "success TABLE posts" || lines[1] != "error TABLE posts" {
+		t.Errorf("Unexpected post-statement-hook output: %q", contents)
+	}
+}
+
+type fakePrinter struct{}
+
+func (fakePrinter) Print(PlannedStatement) {}
@@ -0,0 +1,98 @@
+package applier
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// pushLockName returns the name of the advisory lock used to prevent
+// concurrent pushes to the same schema. Lock names are limited to 64
+// characters in MySQL 5.7+, so long schema names are hashed.
+func pushLockName(schemaName string) string {
+	name := "skeema.push." + schemaName
+	if len(name) > 64 {
+		name = fmt.Sprintf("skeema.push.%x", sha1.Sum([]byte(schemaName)))
+	}
+	return name
+}
+
+// acquirePushLock obtains a GET_LOCK advisory lock on the target's instance
+// and schema, to prevent another concurrent push from interleaving conflicting
+// DDL. The lock is held by a dedicated connection until the returned release
+// function is called. If the lock is already held elsewhere, an error is
+// returned, unless the force-break-lock option is enabled, in which case the
+// connection holding the lock is killed.
+func (t *Target) acquirePushLock() (release func(), err error) {
+	db, err := t.Instance.CachedConnectionPool("", "")
+	if err != nil {
+		return nil, err
+	}
+	lockConn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	lockName := pushLockName(t.SchemaName)
+	defer func() {
+		if err != nil {
+			lockConn.Close()
+		}
+	}()
+
+	var result sql.NullInt64
+	if err := lockConn.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, 0)", lockName).Scan(&result); err != nil {
+		return nil, err
+	}
+	if result.Int64 != 1 {
+		var holder sql.NullInt64
+		if err := lockConn.QueryRowContext(context.Background(), "SELECT IS_USED_LOCK(?)", lockName).Scan(&holder); err != nil {
+			return nil, err
+		}
+		if !t.Dir.Config.GetBool("force-break-lock") {
+			return nil, fmt.Errorf("another push to this schema is already in progress, holding lock %q via connection ID %d. If this push is stuck, use --force-break-lock to override", lockName, holder.Int64)
+		}
+		if holder.Valid {
+			log.Warnf("Breaking lock %q on %s by killing connection ID %d, due to --force-break-lock", lockName, t.Instance, holder.Int64)
+			if _, err := lockConn.ExecContext(context.Background(), fmt.Sprintf("KILL %d", holder.Int64)); err != nil {
+				return nil, fmt.Errorf("unable to break lock %q by killing connection ID %d: %w", lockName, holder.Int64, err)
+			}
+		}
+		if err := lockConn.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, 5)", lockName).Scan(&result); err != nil {
+			return nil, err
+		} else if result.Int64 != 1 {
+			return nil, fmt.Errorf("unable to obtain lock %q even after breaking it", lockName)
+		}
+	}
+
+	// Keep the lock connection active in the background, since long-running DDL
+	// could otherwise cause it to exceed wait_timeout. The release function blocks
+	// until the lock has actually been released.
+	done, released := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(released)
+		defer lockConn.Close()
+		var result sql.NullInt64
+		for {
+			select {
+			case <-done:
+				if err := lockConn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName).Scan(&result); err != nil || result.Int64 != 1 {
+					log.Warnf("%s: Failed to release lock %q, or lock released early due to connection being dropped: %v", t.Instance, lockName, err)
+				}
+				return
+			case <-time.After(5 * time.Second):
+				if err := lockConn.QueryRowContext(context.Background(), "SELECT 1").Scan(&result); err != nil {
+					log.Warnf("%s: Lock %q released early due to connection being dropped: %s", t.Instance, lockName, err)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-released
+	}, nil
+}
@@ -0,0 +1,78 @@
+package applier
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPushLockName(t *testing.T) {
+	if name := pushLockName("product"); name != "skeema.push.product" {
+		t.Errorf("Unexpected lock name %q", name)
+	}
+	longName := strings.Repeat("x", 64)
+	name := pushLockName(longName)
+	if len(name) > 64 || !strings.HasPrefix(name, "skeema.push.") {
+		t.Errorf("Unexpected lock name %q for long schema name", name)
+	}
+	if name == pushLockName(longName+"y") {
+		t.Error("Expected distinct long schema names to have distinct lock names")
+	}
+}
+
+func (s ApplierIntegrationSuite) TestTargetAcquirePushLock(t *testing.T) {
+	inst := s.d[0].Instance
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	release, err := target.acquirePushLock()
+	if err != nil {
+		t.Fatalf("Unexpected error from acquirePushLock: %v", err)
+	}
+
+	// A second attempt should fail while the lock is held, but acquiring a lock
+	// for a different schema should work
+	if _, err := target.acquirePushLock(); err == nil || !strings.Contains(err.Error(), "force-break-lock") {
+		t.Errorf("Expected error mentioning force-break-lock, instead found %v", err)
+	}
+
+	// ApplyTarget should skip the target while the lock is held, before
+	// introspecting the schema
+	eventPath := filepath.Join(t.TempDir(), "events.jsonl")
+	el, err := NewEventLog(eventPath)
+	if err != nil {
+		t.Fatalf("Unexpected error from NewEventLog: %v", err)
+	}
+	SetEventLog(el)
+	result, err := ApplyTarget(target, nil)
+	SetEventLog(nil)
+	el.Close()
+	if err != nil || result.SkipCount != 1 {
+		t.Errorf("Expected ApplyTarget to skip target while lock held, instead found %+v, %v", result, err)
+	}
+	if b, err := os.ReadFile(eventPath); err != nil || strings.Contains(string(b), EventIntrospectionStart) {
+		t.Errorf("Expected no introspection while lock held, instead found events %q, err=%v", b, err)
+	}
+	otherTarget := &Target{Instance: inst, Dir: target.Dir, SchemaName: "analytics"}
+	if otherRelease, err := otherTarget.acquirePushLock(); err != nil {
+		t.Errorf("Unexpected error acquiring lock on different schema: %v", err)
+	} else {
+		otherRelease()
+	}
+
+	// With force-break-lock, the existing lock's connection is killed
+	target.Dir = getDir(t, "testdata/simple", "--force-break-lock")
+	forcedRelease, err := target.acquirePushLock()
+	if err != nil {
+		t.Fatalf("Unexpected error from acquirePushLock with --force-break-lock: %v", err)
+	}
+	forcedRelease()
+	release() // logs a warning since the lock was broken, but should not block or panic
+
+	// After release, lock may be obtained normally again
+	target.Dir = getDir(t, "testdata/simple", "")
+	if release, err = target.acquirePushLock(); err != nil {
+		t.Errorf("Unexpected error from acquirePushLock after release: %v", err)
+	} else {
+		release()
+	}
+}
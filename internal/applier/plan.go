@@ -0,0 +1,150 @@
+package applier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// PlanFormatVersion is the version of the JSON structure written by
+// PlanPrinter.WriteJSON. Its layout follows the resource_changes portion of
+// Terraform's machine-readable plan representation.
+const PlanFormatVersion = "1.0"
+
+// PlanPrinter buffers statements instead of displaying them, so that they may
+// be written as a JSON plan after all targets have been processed. This
+// permits schema changes to be rendered or approved by tooling designed for
+// infrastructure-as-code plans.
+type PlanPrinter struct {
+	changes []PlanResourceChange
+	m       sync.Mutex
+}
+
+// Plan is the top-level JSON structure written by PlanPrinter.
+type Plan struct {
+	FormatVersion   string               `json:"format_version"`
+	ResourceChanges []PlanResourceChange `json:"resource_changes"`
+}
+
+// PlanResourceChange describes a planned change to a single database object.
+// The Address uniquely identifies the object across all instances and schemas
+// in the plan, in the form type.schema["instance/name"].
+type PlanResourceChange struct {
+	Address     string     `json:"address"`
+	Mode        string     `json:"mode"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Index       string     `json:"index"`
+	Change      PlanChange `json:"change"`
+	Destructive bool       `json:"destructive"`
+	Statement   string     `json:"statement"`
+}
+
+// PlanChange describes the actions taken for a PlanResourceChange, along with
+// the object's state before and after. Before is nil for creations, and After
+// is nil for drops.
+type PlanChange struct {
+	Actions []string           `json:"actions"`
+	Before  *PlanResourceState `json:"before"`
+	After   *PlanResourceState `json:"after"`
+}
+
+// PlanResourceState represents a database object's attributes at one point in
+// a plan.
+type PlanResourceState struct {
+	Instance   string `json:"instance"`
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	Definition string `json:"definition,omitempty"`
+}
+
+var planInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Print buffers stmt for later retrieval via Plan. Statements which do not
+// modify a single database object are ignored.
+func (pp *PlanPrinter) Print(stmt PlannedStatement) {
+	ddl, ok := stmt.(*DDLStatement)
+	if !ok {
+		return
+	}
+	cs := ddl.ClientState()
+	key := ddl.ObjectKey()
+	schemaName := ddl.schemaName
+	if key.Type == tengo.ObjectTypeDatabase {
+		schemaName = key.Name
+	}
+	rc := PlanResourceChange{
+		Mode:        "managed",
+		Type:        "skeema_" + string(key.Type),
+		Name:        planResourceName(schemaName),
+		Index:       cs.InstanceName + "/" + key.Name,
+		Destructive: ddl.Unsafe(),
+		Statement:   ddl.Statement(),
+	}
+	rc.Address = fmt.Sprintf("%s.%s[%q]", rc.Type, rc.Name, rc.Index)
+
+	before, after := ddl.Definitions()
+	state := func(def string) *PlanResourceState {
+		return &PlanResourceState{Instance: cs.InstanceName, Schema: schemaName, Name: key.Name, Definition: def}
+	}
+	switch ddl.DiffType() {
+	case tengo.DiffTypeCreate:
+		rc.Change = PlanChange{Actions: []string{"create"}, After: state(after)}
+	case tengo.DiffTypeDrop:
+		rc.Change = PlanChange{Actions: []string{"delete"}, Before: state(before)}
+	default:
+		rc.Change = PlanChange{Actions: []string{"update"}, Before: state(before), After: state(after)}
+	}
+
+	pp.m.Lock()
+	defer pp.m.Unlock()
+	pp.changes = append(pp.changes, rc)
+}
+
+// Plan returns all buffered changes, grouped by instance and then schema.
+// Within each schema, changes retain their original order.
+func (pp *PlanPrinter) Plan() Plan {
+	pp.m.Lock()
+	defer pp.m.Unlock()
+	changes := make([]PlanResourceChange, len(pp.changes))
+	copy(changes, pp.changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		iState, jState := changes[i].state(), changes[j].state()
+		if iState.Instance != jState.Instance {
+			return iState.Instance < jState.Instance
+		}
+		return iState.Schema < jState.Schema
+	})
+	return Plan{FormatVersion: PlanFormatVersion, ResourceChanges: changes}
+}
+
+// WriteJSON writes the plan to w as indented JSON.
+func (pp *PlanPrinter) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(pp.Plan())
+}
+
+// planResourceName converts a schema name into a valid resource name, which
+// may only contain letters, digits, underscores, and dashes, and must not begin
+// with a digit or dash.
+func planResourceName(schemaName string) string {
+	name := planInvalidNameChars.ReplaceAllString(schemaName, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+	return name
+}
+
+// state returns whichever of the change's before or after states is present.
+func (rc PlanResourceChange) state() *PlanResourceState {
+	if rc.Change.After != nil {
+		return rc.Change.After
+	}
+	return rc.Change.Before
+}
@@ -0,0 +1,49 @@
+package applier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/policy"
+)
+
+// checkPolicy evaluates the supplied changes against the policy configured via
+// the policy-url option, if any. A non-nil error is returned if the policy
+// denies the changes or could not be evaluated, unless policy-override is
+// enabled, in which case a warning is logged instead.
+func (t *Target) checkPolicy(changes []policy.Change) error {
+	url := t.Dir.Config.Get("policy-url")
+	if url == "" || len(changes) == 0 {
+		return nil
+	}
+	command := "push"
+	if t.Dir.Config.GetBool("dry-run") {
+		command = "diff"
+	}
+	input := policy.Input{
+		Command:     command,
+		Environment: t.Dir.Config.Get("environment"),
+		Instance:    t.Instance.String(),
+		Schema:      t.SchemaName,
+		Changes:     changes,
+	}
+	decision, err := policy.Evaluate(url, input)
+	if err != nil {
+		err = fmt.Errorf("Unable to evaluate policy at %s: %w", url, err)
+	} else if !decision.Allow {
+		err = errors.New("Changes denied by policy")
+		if len(decision.Reasons) > 0 {
+			err = fmt.Errorf("Changes denied by policy: %s", strings.Join(decision.Reasons, "; "))
+		}
+	} else {
+		log.Debugf("Changes for %s %s allowed by policy", t.Instance, t.SchemaName)
+		return nil
+	}
+	if t.Dir.Config.GetBool("policy-override") {
+		log.Warnf("%s for %s %s; proceeding anyway due to policy-override", err, t.Instance, t.SchemaName)
+		return nil
+	}
+	return err
+}
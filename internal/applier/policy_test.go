@@ -0,0 +1,36 @@
+package applier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skeema/skeema/internal/policy"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result": {"deny": ["DROP TABLE not permitted"]}}`)
+	}))
+	defer server.Close()
+
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	changes := []policy.Change{{ObjectType: "table", ObjectName: "posts", DiffType: "DROP", Statement: "DROP TABLE `posts`"}}
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	if err := target.checkPolicy(changes); err != nil {
+		t.Errorf("Expected no error without policy-url, instead found %v", err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--policy-url="+server.URL)
+	if err := target.checkPolicy(changes); err == nil {
+		t.Error("Expected error from denied policy, but err was nil")
+	}
+	if err := target.checkPolicy(nil); err != nil {
+		t.Errorf("Expected no error with no changes, instead found %v", err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--policy-url="+server.URL+" --policy-override")
+	if err := target.checkPolicy(changes); err != nil {
+		t.Errorf("Expected policy-override to suppress error, instead found %v", err)
+	}
+}
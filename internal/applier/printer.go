@@ -2,9 +2,11 @@ package applier
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/migration"
 	"github.com/skeema/skeema/internal/tengo"
 )
 
@@ -31,11 +33,42 @@ type instanceDiffPrinter struct {
 	m            sync.Mutex
 }
 
+// TemplatePrinter buffers statements instead of displaying them, so that they
+// may be rendered all at once through a user-supplied template after all
+// targets have been processed.
+type TemplatePrinter struct {
+	statements []TemplateStatement
+	m          sync.Mutex
+}
+
+// MigrationPrinter buffers statements instead of displaying them, so that they
+// may be written as migration files after all targets have been processed.
+type MigrationPrinter struct {
+	changes []migration.Change
+	m       sync.Mutex
+}
+
+// TemplateStatement is the representation of a statement supplied to output
+// templates.
+type TemplateStatement struct {
+	Instance  string
+	Schema    string
+	Statement string
+	Delimiter string
+}
+
 // NewPrinter returns a standard printer (displaying all generated SQL), unless
 // the supplied configuration requests only outputting names of instances that
-// have differences.
+// have differences, rendering output through a template, writing migration
+// files, or writing a JSON plan.
 func NewPrinter(cfg *mybase.Config) Printer {
-	if cfg.GetBool("brief") {
+	if cfg.Get("plan-file") != "" {
+		return &PlanPrinter{}
+	} else if cfg.Get("migration-format") != "" {
+		return &MigrationPrinter{}
+	} else if cfg.Get("template") != "" {
+		return &TemplatePrinter{}
+	} else if cfg.GetBool("brief") {
 		return &instanceDiffPrinter{
 			seenInstance: make(map[string]bool),
 		}
@@ -84,3 +117,69 @@ func (idp *instanceDiffPrinter) Print(stmt PlannedStatement) {
 		idp.seenInstance[instString] = true
 	}
 }
+
+// Print buffers stmt for later retrieval via Statements.
+func (tp *TemplatePrinter) Print(stmt PlannedStatement) {
+	cs := stmt.ClientState()
+	tp.m.Lock()
+	defer tp.m.Unlock()
+	tp.statements = append(tp.statements, TemplateStatement{
+		Instance:  cs.InstanceName,
+		Schema:    cs.SchemaName,
+		Statement: stmt.Statement(),
+		Delimiter: cs.Delimiter,
+	})
+}
+
+// Statements returns all buffered statements, grouped by instance and then
+// schema. Within each schema, statements retain their original order.
+func (tp *TemplatePrinter) Statements() []TemplateStatement {
+	tp.m.Lock()
+	defer tp.m.Unlock()
+	result := make([]TemplateStatement, len(tp.statements))
+	copy(result, tp.statements)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Instance != result[j].Instance {
+			return result[i].Instance < result[j].Instance
+		}
+		return result[i].Schema < result[j].Schema
+	})
+	return result
+}
+
+// Print buffers stmt for later retrieval via Changes.
+func (mp *MigrationPrinter) Print(stmt PlannedStatement) {
+	cs := stmt.ClientState()
+	change := migration.Change{
+		Instance:  cs.InstanceName,
+		Schema:    cs.SchemaName,
+		Statement: stmt.Statement(),
+		Compound:  cs.Delimiter != ";" && cs.Delimiter != "",
+	}
+	if ddl, ok := stmt.(*DDLStatement); ok {
+		key := ddl.ObjectKey()
+		change.ObjectType = string(key.Type)
+		change.ObjectName = key.Name
+		change.DiffType = ddl.DiffType().String()
+		change.Rollback, change.RollbackCompound = ddl.Rollback()
+	}
+	mp.m.Lock()
+	defer mp.m.Unlock()
+	mp.changes = append(mp.changes, change)
+}
+
+// Changes returns all buffered statements, grouped by instance and then schema.
+// Within each schema, statements retain their original order.
+func (mp *MigrationPrinter) Changes() []migration.Change {
+	mp.m.Lock()
+	defer mp.m.Unlock()
+	result := make([]migration.Change, len(mp.changes))
+	copy(result, mp.changes)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Instance != result[j].Instance {
+			return result[i].Instance < result[j].Instance
+		}
+		return result[i].Schema < result[j].Schema
+	})
+	return result
+}
@@ -0,0 +1,157 @@
+package applier
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+type fakeClientStateStatement struct {
+	fakeStatement
+	cs ClientState
+}
+
+func (fcs *fakeClientStateStatement) ClientState() ClientState {
+	return fcs.cs
+}
+
+func TestTemplatePrinter(t *testing.T) {
+	if _, ok := NewPrinter(getBaseConfig(t, "")).(*standardPrinter); !ok {
+		t.Error("Expected NewPrinter to return a standardPrinter by default")
+	}
+	tp, ok := NewPrinter(getBaseConfig(t, "--template=output.tmpl")).(*TemplatePrinter)
+	if !ok {
+		t.Fatal("Expected NewPrinter to return a *TemplatePrinter with --template")
+	}
+	for _, input := range []struct{ inst, schema, stmt string }{
+		{"b:3306", "s1", "DROP TABLE `x`"},
+		{"a:3306", "s2", "DROP TABLE `y`"},
+		{"b:3306", "s1", "DROP TABLE `z`"},
+		{"a:3306", "s1", "CREATE TABLE `w` (id int)"},
+	} {
+		tp.Print(&fakeClientStateStatement{
+			fakeStatement: fakeStatement{stmt: input.stmt},
+			cs:            ClientState{InstanceName: input.inst, SchemaName: input.schema, Delimiter: ";"},
+		})
+	}
+	var actual []string
+	for _, ts := range tp.Statements() {
+		actual = append(actual, ts.Instance+" "+ts.Schema+" "+ts.Statement)
+	}
+	expected := []string{
+		"a:3306 s1 CREATE TABLE `w` (id int)",
+		"a:3306 s2 DROP TABLE `y`",
+		"b:3306 s1 DROP TABLE `x`",
+		"b:3306 s1 DROP TABLE `z`",
+	}
+	for n := range expected {
+		if n >= len(actual) || actual[n] != expected[n] {
+			t.Errorf("Unexpected Statements result: %v", actual)
+			break
+		}
+	}
+}
+
+func TestMigrationPrinter(t *testing.T) {
+	mp, ok := NewPrinter(getBaseConfig(t, "--migration-format=liquibase --template=output.tmpl")).(*MigrationPrinter)
+	if !ok {
+		t.Fatal("Expected NewPrinter to return a *MigrationPrinter with --migration-format")
+	}
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	mp.Print(&DDLStatement{
+		stmt:       "CREATE TABLE `w` (id int)",
+		key:        tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "w"},
+		diffType:   tengo.DiffTypeCreate,
+		rollback:   rollbackStatement{stmt: "DROP TABLE `w`"},
+		instance:   inst,
+		schemaName: "s1",
+	})
+	mp.Print(&DDLStatement{
+		stmt:       "DROP PROCEDURE `p`",
+		key:        tengo.ObjectKey{Type: tengo.ObjectTypeProc, Name: "p"},
+		diffType:   tengo.DiffTypeDrop,
+		compound:   true,
+		instance:   inst,
+		schemaName: "s1",
+	})
+	changes := mp.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, instead found %d", len(changes))
+	}
+	if c := changes[0]; c.ObjectType != "table" || c.ObjectName != "w" || c.DiffType != "CREATE" || c.Rollback != "DROP TABLE `w`" || c.Schema != "s1" || c.Compound {
+		t.Errorf("Unexpected first change: %+v", c)
+	}
+	if c := changes[1]; c.DiffType != "DROP" || c.Rollback != "" || !c.Compound {
+		t.Errorf("Unexpected second change: %+v", c)
+	}
+}
+
+func TestPlanPrinter(t *testing.T) {
+	pp, ok := NewPrinter(getBaseConfig(t, "--plan-file=plan.json --migration-format=liquibase")).(*PlanPrinter)
+	if !ok {
+		t.Fatal("Expected NewPrinter to return a *PlanPrinter with --plan-file")
+	}
+	inst1, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3307)/")
+	inst2, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	pp.Print(&DDLStatement{
+		stmt:       "DROP TABLE `w`",
+		key:        tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "w"},
+		diffType:   tengo.DiffTypeDrop,
+		unsafe:     true,
+		before:     "CREATE TABLE `w` (id int)",
+		instance:   inst1,
+		schemaName: "s1",
+	})
+	pp.Print(&DDLStatement{
+		stmt:       "ALTER TABLE `x` ADD COLUMN `b` int",
+		key:        tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "x"},
+		diffType:   tengo.DiffTypeAlter,
+		before:     "CREATE TABLE `x` (a int)",
+		after:      "CREATE TABLE `x` (a int, b int)",
+		instance:   inst2,
+		schemaName: "my.db",
+	})
+	pp.Print(&DDLStatement{
+		stmt:       "CREATE DATABASE `s3`",
+		key:        tengo.ObjectKey{Type: tengo.ObjectTypeDatabase, Name: "s3"},
+		diffType:   tengo.DiffTypeCreate,
+		instance:   inst2,
+		schemaName: "",
+	})
+	plan := pp.Plan()
+	if plan.FormatVersion != PlanFormatVersion || len(plan.ResourceChanges) != 3 {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+
+	// Changes should be sorted by instance and then schema
+	alter, create, drop := plan.ResourceChanges[0], plan.ResourceChanges[1], plan.ResourceChanges[2]
+	if alter.Address != `skeema_table.my_db["127.0.0.1:3306/x"]` || alter.Destructive || len(alter.Change.Actions) != 1 || alter.Change.Actions[0] != "update" {
+		t.Errorf("Unexpected ALTER resource change: %+v", alter)
+	} else if alter.Change.Before.Definition != "CREATE TABLE `x` (a int)" || alter.Change.After.Definition != "CREATE TABLE `x` (a int, b int)" || alter.Change.After.Schema != "my.db" {
+		t.Errorf("Unexpected ALTER before/after: %+v %+v", *alter.Change.Before, *alter.Change.After)
+	}
+	if create.Address != `skeema_database.s3["127.0.0.1:3306/s3"]` || create.Change.Actions[0] != "create" || create.Change.Before != nil || create.Change.After.Schema != "s3" {
+		t.Errorf("Unexpected CREATE DATABASE resource change: %+v", create)
+	}
+	if drop.Type != "skeema_table" || drop.Name != "s1" || drop.Index != "127.0.0.1:3307/w" || !drop.Destructive || drop.Change.Actions[0] != "delete" || drop.Change.After != nil {
+		t.Errorf("Unexpected DROP resource change: %+v", drop)
+	}
+
+	var buf bytes.Buffer
+	if err := pp.WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error from WriteJSON: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON output is not valid JSON: %v", err)
+	}
+	if rcs, ok := decoded["resource_changes"].([]interface{}); !ok || len(rcs) != 3 {
+		t.Errorf("Unexpected resource_changes in JSON output: %v", decoded["resource_changes"])
+	}
+	if !strings.Contains(buf.String(), `"before": null`) {
+		t.Errorf("Expected JSON output to include null before state for creation, but it did not:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,97 @@
+package applier
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ProgressFile tracks which targets have been pushed successfully, so that a
+// subsequent push after a partial failure can skip targets that were already
+// completed. Each completed target is recorded as one line of the file.
+type ProgressFile struct {
+	path      string
+	completed map[string]bool
+	mu        sync.Mutex
+}
+
+// OpenProgressFile reads the progress file at path, if it exists, and returns
+// a ProgressFile reflecting its contents. It is not an error for the file to
+// not exist yet; it will be created upon the first call to MarkCompleted.
+func OpenProgressFile(path string) (*ProgressFile, error) {
+	pf := &ProgressFile{
+		path:      path,
+		completed: make(map[string]bool),
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pf, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			pf.completed[line] = true
+		}
+	}
+	return pf, scanner.Err()
+}
+
+// Completed returns true if t was recorded as completed by a previous call to
+// MarkCompleted, either in this process or a previous one.
+func (pf *ProgressFile) Completed(t *Target) bool {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return pf.completed[progressKey(t)]
+}
+
+// CompletedCount returns the number of targets recorded as completed.
+func (pf *ProgressFile) CompletedCount() int {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return len(pf.completed)
+}
+
+// MarkCompleted records t as completed, appending it to the file immediately
+// so that progress is retained even if the process is killed.
+func (pf *ProgressFile) MarkCompleted(t *Target) error {
+	key := progressKey(t)
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	if pf.completed[key] {
+		return nil
+	}
+	f, err := os.OpenFile(pf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintln(f, key); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	pf.completed[key] = true
+	return nil
+}
+
+// Remove deletes the progress file. This should be called once all targets
+// have been pushed successfully, so that the next push starts from scratch.
+func (pf *ProgressFile) Remove() error {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	pf.completed = make(map[string]bool)
+	if err := os.Remove(pf.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func progressKey(t *Target) string {
+	return fmt.Sprintf("%s\t%s\t%s", t.Instance, t.SchemaName, t.Dir.Path)
+}
@@ -0,0 +1,56 @@
+package applier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestProgressFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress")
+	pf, err := OpenProgressFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening nonexistent progress file: %s", err)
+	}
+	inst, err := tengo.NewInstance("mysql", "root:password@tcp(127.0.0.1:3306)/")
+	if err != nil {
+		t.Fatalf("Unexpected error from NewInstance: %s", err)
+	}
+	dir := &fs.Dir{Path: "/tmp/schemas/product"}
+	target1 := &Target{Instance: inst, Dir: dir, SchemaName: "shard1"}
+	target2 := &Target{Instance: inst, Dir: dir, SchemaName: "shard2"}
+	if pf.Completed(target1) || pf.Completed(target2) {
+		t.Fatal("Expected new progress file to have no completed targets")
+	}
+	if err := pf.MarkCompleted(target1); err != nil {
+		t.Fatalf("Unexpected error from MarkCompleted: %s", err)
+	}
+	if err := pf.MarkCompleted(target1); err != nil {
+		t.Fatalf("Unexpected error from repeated MarkCompleted: %s", err)
+	}
+
+	// Re-opening the file should reflect the previously completed target
+	pf, err = OpenProgressFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error re-opening progress file: %s", err)
+	}
+	if !pf.Completed(target1) || pf.Completed(target2) || pf.CompletedCount() != 1 {
+		t.Errorf("Unexpected completion state after re-opening progress file")
+	}
+
+	if err := pf.Remove(); err != nil {
+		t.Fatalf("Unexpected error from Remove: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected progress file to be removed, but Stat returned %v", err)
+	}
+	if pf.Completed(target1) {
+		t.Error("Expected Remove to clear completion state")
+	}
+	if err := pf.Remove(); err != nil {
+		t.Errorf("Unexpected error from Remove on nonexistent file: %s", err)
+	}
+}
@@ -0,0 +1,67 @@
+package applier
+
+import (
+	"sync"
+)
+
+// Progress is a snapshot of cumulative progress across all targets of an
+// operation. Byte counts are estimates, based on the on-disk size of tables
+// being altered; they are only tracked while a ProgressFunc is set.
+type Progress struct {
+	TargetsTotal        int
+	TargetsDone         int
+	ObjectsIntrospected int
+	StatementsTotal     int // statements generated so far; grows as targets are processed
+	StatementsExecuted  int
+	BytesTotal          int64
+	BytesDone           int64
+}
+
+// ProgressFunc is a callback which receives a Progress snapshot each time
+// progress is made. It may be called concurrently from multiple goroutines,
+// but calls are serialized.
+type ProgressFunc func(Progress)
+
+var progressState struct {
+	sync.Mutex
+	fn      ProgressFunc
+	current Progress
+}
+
+// SetProgressFunc registers fn to receive progress updates, and resets all
+// counters. targetsTotal should be the total number of targets about to be
+// processed. Supply a nil fn to disable progress reporting.
+func SetProgressFunc(fn ProgressFunc, targetsTotal int) {
+	progressState.Lock()
+	defer progressState.Unlock()
+	progressState.fn = fn
+	progressState.current = Progress{TargetsTotal: targetsTotal}
+}
+
+// progressEnabled returns true if a ProgressFunc is currently registered.
+func progressEnabled() bool {
+	progressState.Lock()
+	defer progressState.Unlock()
+	return progressState.fn != nil
+}
+
+// reportProgress applies update to the current progress counters, and then
+// supplies the result to the registered ProgressFunc, if any.
+func reportProgress(update func(p *Progress)) {
+	progressState.Lock()
+	defer progressState.Unlock()
+	if progressState.fn == nil {
+		return
+	}
+	update(&progressState.current)
+	progressState.fn(progressState.current)
+}
+
+// estimatedBytes returns the estimated number of bytes that stmt will copy,
+// if known.
+func estimatedBytes(stmt PlannedStatement) int64 {
+	if ddl, ok := stmt.(*DDLStatement); ok {
+		return ddl.tableSize
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package applier
+
+import (
+	"testing"
+)
+
+func TestReportProgress(t *testing.T) {
+	// No-op without a registered func
+	reportProgress(func(p *Progress) { p.TargetsDone++ })
+	if progressEnabled() {
+		t.Fatal("Expected progress reporting to be disabled by default")
+	}
+
+	var updates []Progress
+	SetProgressFunc(func(p Progress) { updates = append(updates, p) }, 3)
+	defer SetProgressFunc(nil, 0)
+	if !progressEnabled() {
+		t.Error("Expected progress reporting to be enabled after SetProgressFunc")
+	}
+	stmts := []PlannedStatement{&DDLStatement{tableSize: 2048}, &fakeStatement{}}
+	reportProgress(func(p *Progress) {
+		p.StatementsTotal += len(stmts)
+		for _, stmt := range stmts {
+			p.BytesTotal += estimatedBytes(stmt)
+		}
+	})
+	reportProgress(func(p *Progress) {
+		p.StatementsExecuted++
+		p.BytesDone += estimatedBytes(stmts[0])
+	})
+	reportProgress(func(p *Progress) { p.TargetsDone++ })
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 progress updates, instead found %d", len(updates))
+	}
+	expected := Progress{TargetsTotal: 3, TargetsDone: 1, StatementsTotal: 2, StatementsExecuted: 1, BytesTotal: 2048, BytesDone: 2048}
+	if updates[2] != expected {
+		t.Errorf("Unexpected final progress: %+v", updates[2])
+	}
+}
@@ -0,0 +1,179 @@
+package applier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/snapshot"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/workspace"
+)
+
+// ErrNoSnapshot is returned by UndoTarget if no snapshot exists for the target.
+var ErrNoSnapshot = errors.New("no snapshot of a previous push exists")
+
+var reUnsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// snapshotPath returns the path of the target's snapshot file, or a blank
+// string if the snapshot-dir option has not been configured. Relative
+// snapshot-dir values are relative to the target's directory.
+func (t *Target) snapshotPath() string {
+	snapshotDir := t.Dir.Config.Get("snapshot-dir")
+	if snapshotDir == "" {
+		return ""
+	} else if !filepath.IsAbs(snapshotDir) {
+		snapshotDir = filepath.Join(t.Dir.Path, snapshotDir)
+	}
+	fileName := reUnsafeFileChars.ReplaceAllString(t.Instance.String()+"_"+t.SchemaName, "_") + ".json.gz"
+	return filepath.Join(snapshotDir, fileName)
+}
+
+// saveSnapshot records the current definitions of the objects corresponding
+// to keys, replacing any previous snapshot for the target. Database-level keys
+// are ignored, since undo only operates on objects within the schema.
+func (t *Target) saveSnapshot(from *tengo.Schema, keys []tengo.ObjectKey) error {
+	path := t.snapshotPath()
+	if path == "" {
+		return nil
+	}
+	if from == nil {
+		from = &tengo.Schema{Name: t.SchemaName}
+	}
+	snap := snapshot.New()
+	snap.AddObjects(from, keys)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	// Write to a temp file and then rename, so that a failure never leaves behind
+	// a partially-written snapshot
+	tmpPath := path + ".tmp"
+	if err := snap.WriteFile(tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	log.Debugf("Saved pre-push snapshot of %s in %s", countAndNoun(len(snap.Keys(from.Name)), "object"), path)
+	return nil
+}
+
+// loadSnapshot returns the target's most recent snapshot, or ErrNoSnapshot if
+// there is none.
+func (t *Target) loadSnapshot() (*snapshot.Snapshot, error) {
+	path := t.snapshotPath()
+	if path == "" {
+		return nil, ErrNoSnapshot
+	}
+	snap, err := snapshot.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoSnapshot
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read snapshot %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// UndoTarget reverts the objects modified by the most recent push to the
+// target, by restoring the definitions recorded in its snapshot. Objects which
+// did not exist prior to that push are dropped. The changes are generated and
+// executed the same way as ApplyTarget, so all normal safety checks and options apply; in
+// turn, this means a new snapshot is recorded, and a subsequent undo will
+// re-apply the original push. ErrNoSnapshot is returned if the target has no
+// snapshot.
+func UndoTarget(t *Target, printer Printer) (Result, error) {
+	snap, err := t.loadSnapshot()
+	if err != nil {
+		return Result{}, err
+	}
+	keys := snap.Keys(t.SchemaName)
+	log.Infof("%s %s: reverting %s modified by push at %s", t.Instance, t.SchemaName, countAndNoun(len(keys), "object"), snap.Time.Local().Format(time.RFC1123))
+
+	// Hold the push lock before introspecting, so that the current state can't
+	// be changed by a concurrent push before the undo is applied
+	defer reportProgress(func(p *Progress) { p.TargetsDone++ })
+	release, result, err := t.lockForPush()
+	if release == nil {
+		return result, err
+	}
+	defer release()
+	current, err := t.SchemaFromInstance()
+	if err != nil {
+		return Result{SkipCount: 1}, err
+	}
+	if current == nil {
+		current = &tengo.Schema{Name: t.SchemaName}
+	}
+
+	// Materialize the snapshot's previous definitions in a workspace
+	logicalSchema := fs.NewLogicalSchema()
+	logicalSchema.CharSet = current.CharSet
+	logicalSchema.Collation = current.Collation
+	restore := make(map[tengo.ObjectKey]bool, len(keys))
+	for _, key := range keys {
+		restore[key] = true
+		before := snap.Definition(t.SchemaName, key)
+		if before == "" {
+			continue
+		}
+		stmt := tengo.ParseStatementInString(before)
+		if stmt.Type != tengo.StatementTypeCreate || stmt.ObjectKey() != key {
+			return Result{SkipCount: 1}, fmt.Errorf("snapshot definition of %s is not a valid CREATE statement for that object", key)
+		}
+		if err := logicalSchema.AddStatement(stmt); err != nil {
+			return Result{SkipCount: 1}, err
+		}
+	}
+	opts, err := workspace.OptionsForDir(t.Dir, t.Instance)
+	if err != nil {
+		return Result{SkipCount: 1}, err
+	}
+	wsSchema, err := workspace.ExecLogicalSchema(logicalSchema, opts)
+	if err == nil && len(wsSchema.Failures) > 0 {
+		err = wsSchema.Failures[0]
+	}
+	if err != nil {
+		return Result{SkipCount: 1}, fmt.Errorf("unable to restore snapshot definitions: %w", err)
+	}
+
+	// The desired state is the current state, with snapshot objects replaced by
+	// their previous definitions, or removed if they didn't previously exist
+	desired := *current
+	desired.Tables, desired.Routines, desired.Views, desired.Triggers = nil, nil, nil, nil
+	for _, table := range current.Tables {
+		if !restore[table.ObjectKey()] {
+			desired.Tables = append(desired.Tables, table)
+		}
+	}
+	for _, routine := range current.Routines {
+		if !restore[routine.ObjectKey()] {
+			desired.Routines = append(desired.Routines, routine)
+		}
+	}
+	for _, view := range current.Views {
+		if !restore[view.ObjectKey()] {
+			desired.Views = append(desired.Views, view)
+		}
+	}
+	for _, trigger := range current.Triggers {
+		if !restore[trigger.ObjectKey()] {
+			desired.Triggers = append(desired.Triggers, trigger)
+		}
+	}
+	desired.Tables = append(desired.Tables, wsSchema.Tables...)
+	desired.Routines = append(desired.Routines, wsSchema.Routines...)
+	desired.Views = append(desired.Views, wsSchema.Views...)
+	desired.Triggers = append(desired.Triggers, wsSchema.Triggers...)
+
+	undoTarget := *t
+	undoTarget.DesiredSchema = &workspace.Schema{
+		Schema:        &desired,
+		LogicalSchema: logicalSchema,
+	}
+	return undoTarget.apply(printer)
+}
@@ -0,0 +1,63 @@
+package applier
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestTargetSnapshotRoundTrip(t *testing.T) {
+	inst, err := tengo.NewInstance("mysql", "root:fakepw@tcp(127.0.0.1:3306)/")
+	if err != nil {
+		t.Fatalf("Unexpected error from NewInstance: %v", err)
+	}
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	if path := target.snapshotPath(); path != "" {
+		t.Errorf("Expected blank snapshot path with snapshot-dir unset, instead found %q", path)
+	}
+	if _, err := target.loadSnapshot(); err != ErrNoSnapshot {
+		t.Errorf("Expected ErrNoSnapshot with snapshot-dir unset, instead found %v", err)
+	}
+
+	snapshotDir := t.TempDir()
+	target.Dir = getDir(t, "testdata/simple", "--snapshot-dir="+snapshotDir)
+	if path, expected := target.snapshotPath(), filepath.Join(snapshotDir, "127.0.0.1_3306_product.json.gz"); path != expected {
+		t.Errorf("Expected snapshot path %q, instead found %q", expected, path)
+	}
+	if _, err := target.loadSnapshot(); err != ErrNoSnapshot {
+		t.Errorf("Expected ErrNoSnapshot before any snapshot saved, instead found %v", err)
+	}
+
+	table := &tengo.Table{
+		Name:            "posts",
+		CreateStatement: "CREATE TABLE `posts` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+	}
+	from := &tengo.Schema{Name: "product", Tables: []*tengo.Table{table}}
+	keys := []tengo.ObjectKey{
+		{Type: tengo.ObjectTypeDatabase, Name: "product"},
+		table.ObjectKey(),
+		table.ObjectKey(), // duplicate, e.g. from a split ALTER
+		{Type: tengo.ObjectTypeProc, Name: "newproc"},
+	}
+	if err := target.saveSnapshot(from, keys); err != nil {
+		t.Fatalf("Unexpected error from saveSnapshot: %v", err)
+	}
+	snap, err := target.loadSnapshot()
+	if err != nil {
+		t.Fatalf("Unexpected error from loadSnapshot: %v", err)
+	}
+	if snap.Time.IsZero() {
+		t.Error("Expected snapshot time to be set")
+	}
+	procKey := tengo.ObjectKey{Type: tengo.ObjectTypeProc, Name: "newproc"}
+	if snapKeys := snap.Keys("product"); len(snapKeys) != 2 || snapKeys[0] != procKey || snapKeys[1] != table.ObjectKey() {
+		t.Fatalf("Unexpected keys in snapshot: %v", snapKeys)
+	}
+	if def := snap.Definition("product", table.ObjectKey()); def != table.CreateStatement {
+		t.Errorf("Unexpected snapshot definition of %s: %q", table.ObjectKey(), def)
+	}
+	if def := snap.Definition("product", procKey); def != "" {
+		t.Errorf("Expected blank snapshot definition for nonexistent %s, instead found %q", procKey, def)
+	}
+}
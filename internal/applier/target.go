@@ -1,7 +1,9 @@
 package applier
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"os"
 
 	log "github.com/sirupsen/logrus"
@@ -48,6 +50,26 @@ func (t *Target) logApplyStart() {
 	if len(t.Dir.UnparsedStatements) > 0 {
 		log.Warnf("Ignoring %d unsupported or unparseable statements found in this directory's *.sql files; run `skeema lint` for more info", len(t.Dir.UnparsedStatements))
 	}
+	for _, stmt := range t.Dir.MalformedStatements {
+		log.Warnf("Ignoring %s: %s", stmt.ObjectKey(), stmt.Err)
+	}
+}
+
+// checkBinlog confirms that the target's user has sufficient privileges to
+// disable binary logging, if the binlog option has been disabled. A prominent
+// warning is logged in this situation, since the DDL will not replicate.
+func (t *Target) checkBinlog() error {
+	if t.Dir.Config.GetBool("binlog") {
+		return nil
+	}
+	if !t.Instance.CanSkipBinlog() {
+		return fmt.Errorf("option skip-binlog requires the SUPER, SYSTEM_VARIABLES_ADMIN, SESSION_VARIABLES_ADMIN, or BINLOG ADMIN privilege on %s", t.Instance)
+	}
+	log.Warnf("*** Binary logging is DISABLED for DDL on %s %s: changes will NOT be replicated to any replicas of this server! ***", t.Instance, t.SchemaName)
+	if t.Dir.Config.Get("alter-wrapper") != "" || t.Dir.Config.Get("ddl-wrapper") != "" {
+		log.Warn("External commands from alter-wrapper or ddl-wrapper must apply the session variables in {CONNOPTS} in order to disable binary logging.")
+	}
+	return nil
 }
 
 func (t *Target) logApplyEnd(result Result) {
@@ -62,11 +84,68 @@ func (t *Target) logApplyEnd(result Result) {
 	}
 }
 
-func (t *Target) processSQL(stmts []PlannedStatement, printer Printer) (skipCount int) {
+func (t *Target) processSQL(ctx context.Context, stmts []PlannedStatement, printer Printer) (skipCount int) {
+	var throttler *replicaThrottler
+	if !t.Dir.Config.GetBool("dry-run") && len(stmts) > 0 {
+		var err error
+		if throttler, err = newReplicaThrottler(t.Dir); err != nil {
+			log.Errorf("Skipping %s %s: %s", t.Instance, t.SchemaName, err)
+			return len(stmts)
+		}
+	}
 	for i, stmt := range stmts {
 		printer.Print(stmt)
 		if !t.Dir.Config.GetBool("dry-run") {
-			if err := stmt.Execute(); err != nil {
+			if err := throttler.Wait(ctx); err != nil {
+				log.Errorf("Skipping %d remaining operations for %s %s: %s", len(stmts)-i, t.Instance, t.SchemaName, err)
+				t.logEvent(Event{Type: EventError, Error: err.Error()})
+				return skipCount + len(stmts) - i
+			}
+			doneTiming := tengo.StartTiming("Execute statement on %s %s: %s", t.Instance, t.SchemaName, stmt.Statement())
+			_, span := tengo.StartSpan(ctx, "skeema.ExecuteStatement", map[string]string{
+				"db.instance":  t.Instance.String(),
+				"db.name":      t.SchemaName,
+				"db.statement": stmt.Statement(),
+			})
+			event := Event{Type: EventStatementExecuted, Statement: stmt.Statement()}
+			if keyer, ok := stmt.(tengo.ObjectKeyer); ok {
+				event.Object = keyer.ObjectKey().String()
+			}
+			err := t.runStatementHooks(newStatementEvent(HookPhasePre, stmt))
+
+			// A pre-statement hook vetoing a statement only skips that statement. An
+			// invalid hook configuration is still treated as a statement failure,
+			// since it would otherwise veto every statement.
+			if _, isConfigErr := err.(ConfigError); err != nil && !isConfigErr {
+				log.Warnf("Skipping SQL statement on %s %s: vetoed by pre-statement hook: %s\nSkipped SQL statement: %s%s", t.Instance, t.SchemaName, err, stmt.Statement(), stmt.ClientState().Delimiter)
+				event.Type, event.Error = EventError, err.Error()
+				t.logEvent(event)
+				span.End(err)
+				doneTiming()
+				skipCount++
+				continue
+			}
+			if err == nil {
+				err = stmt.Execute()
+				postEvent := newStatementEvent(HookPhasePost, stmt)
+				postEvent.Err = err
+				if hookErr := t.runStatementHooks(postEvent); hookErr != nil {
+					log.Warnf("Error running post-statement hook on %s %s: %s", t.Instance, t.SchemaName, hookErr)
+				}
+			}
+			if err != nil {
+				event.Error = err.Error()
+			}
+			t.logEvent(event)
+			span.End(err)
+			doneTiming()
+			if err == nil {
+				reportProgress(func(p *Progress) {
+					p.StatementsExecuted++
+					p.BytesDone += estimatedBytes(stmt)
+				})
+			}
+			if err != nil {
 				log.Errorf("Error running SQL statement on %s %s: %s\nFull SQL statement: %s%s", t.Instance, t.SchemaName, err, stmt.Statement(), stmt.ClientState().Delimiter)
 				skipped := len(stmts) - i
 				skipCount += skipped
@@ -205,6 +284,15 @@ func targetsForLogicalSchema(logicalSchema *fs.LogicalSchema, dir *fs.Dir, insta
 		}
 	}
 
+	// With lower_case_table_names=0, table names which only differ in letter case
+	// work properly, but are non-portable to servers using other settings. (With
+	// other settings, this situation is an error, returned by ExecLogicalSchema.)
+	if instances[0].NameCaseMode() == tengo.NameCaseAsIs {
+		for _, collision := range logicalSchema.CaseCollisions() {
+			log.Warnf("%s: tables defined at %s line %d and %s line %d have names which only differ in letter case. This will cause problems on any database server using lower_case_table_names=1 or 2, including default installations on Windows and MacOS.", dir, collision.FirstFile, collision.FirstLine, collision.DupeFile, collision.DupeLine)
+		}
+	}
+
 	// Obtain a *tengo.Schema representation of the dir's *.sql files from a
 	// workspace
 	opts, err := workspace.OptionsForDir(dir, instances[0])
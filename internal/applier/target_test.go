@@ -6,7 +6,10 @@ import (
 	"testing"
 
 	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fixture"
 	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/migration"
+	"github.com/skeema/skeema/internal/policy"
 	"github.com/skeema/skeema/internal/tengo"
 	"github.com/skeema/skeema/internal/util"
 	"github.com/skeema/skeema/internal/workspace"
@@ -269,10 +272,15 @@ func getBaseConfig(t *testing.T, cliFlags string) *mybase.Config {
 	cmd := mybase.NewCommand("appliertest", "", "", nil)
 	cmd.AddOption(mybase.BoolOption("verify", 0, true, "Test all generated ALTER statements on temp schema to verify correctness"))
 	cmd.AddOption(mybase.BoolOption("allow-unsafe", 0, false, "Permit running ALTER or DROP operations that are potentially destructive"))
+	cmd.AddOption(mybase.BoolOption("strict-unsupported", 0, false, "Treat any table using unsupported features as a fatal error, rather than skipping its changes"))
 	cmd.AddOption(mybase.BoolOption("dry-run", 0, false, "Output DDL but don't run it; equivalent to `skeema diff`"))
 	cmd.AddOption(mybase.BoolOption("first-only", '1', false, "For dirs mapping to multiple instances or schemas, just run against the first per dir"))
 	cmd.AddOption(mybase.BoolOption("exact-match", 0, false, "Follow *.sql table definitions exactly, even for differences with no functional impact"))
 	cmd.AddOption(mybase.BoolOption("foreign-key-checks", 0, false, "Force the server to check referential integrity of any new foreign key"))
+	cmd.AddOption(mybase.StringOption("version-table", 0, "", "After each successful push, record the git SHA, Skeema version, and diff hash in this schema-qualified table on the instance"))
+	cmd.AddOption(mybase.StringOption("snapshot-dir", 0, "", "Before each push, save prior definitions of modified objects in this dir (relative to each schema dir) for `skeema undo-last-push`"))
+	cmd.AddOption(mybase.BoolOption("force-break-lock", 0, false, "If another push to the same schema holds the advisory lock, kill its lock connection and proceed"))
+	cmd.AddOption(mybase.BoolOption("binlog", 0, true, "Replicate pushed DDL via the binary log; use --skip-binlog to only alter this instance, with sql_log_bin=0"))
 	cmd.AddOption(mybase.BoolOption("brief", 'q', false, "<overridden by diff command>").Hidden())
 	cmd.AddOption(mybase.StringOption("alter-wrapper", 'x', "", "External bin to shell out to for ALTER TABLE; see manual for template vars"))
 	cmd.AddOption(mybase.StringOption("alter-wrapper-min-size", 0, "0", "Ignore --alter-wrapper for tables smaller than this size in bytes"))
@@ -280,10 +288,22 @@ func getBaseConfig(t *testing.T, cliFlags string) *mybase.Config {
 	cmd.AddOption(mybase.StringOption("alter-algorithm", 0, "", `Apply an ALGORITHM clause to all ALTER TABLEs (valid values: "inplace", "copy", "instant", "nocopy")`))
 	cmd.AddOption(mybase.StringOption("ddl-wrapper", 'X', "", "Like --alter-wrapper, but applies to all DDL types (CREATE, DROP, ALTER)"))
 	cmd.AddOption(mybase.StringOption("safe-below-size", 0, "0", "Always permit destructive operations for tables below this size in bytes"))
+	cmd.AddOption(mybase.StringOption("avro-compat", 0, "ignore", `Check ALTERs for backward-incompatible changes to Debezium Avro schemas (valid values: "ignore", "warning", "error")`))
+	cmd.AddOption(mybase.StringOption("ddl-transform", 0, "", "External bin to shell out to for rewriting each generated statement; non-zero exit vetoes it; see manual for template vars"))
+	cmd.AddOption(mybase.StringOption("pre-statement-hook", 0, "", "External bin to shell out to before each statement; non-zero exit skips only that statement; see manual for template vars"))
+	cmd.AddOption(mybase.StringOption("post-statement-hook", 0, "", "External bin to shell out to after each statement; see manual for template vars"))
+	cmd.AddOption(mybase.StringOption("plan-file", 0, "", "Write a JSON plan of changes to this file, or to STDOUT if \"-\", instead of displaying generated SQL"))
+	cmd.AddOption(mybase.StringOption("template", 0, "", "Render output through the Go text/template in this file, instead of displaying generated SQL"))
 	cmd.AddOption(mybase.StringOption("concurrent-instances", 'c', "1", "Perform operations on this number of instances concurrently"))
+	cmd.AddOption(mybase.StringOption("throttle-replicas", 0, "", "Comma-separated host:port list of replicas to monitor for lag between statements"))
+	cmd.AddOption(mybase.StringOption("max-replica-lag", 0, "0", "Pause between statements while any throttle-replicas lag by more than this many seconds"))
+	cmd.AddOption(mybase.StringOption("replica-lag-timeout", 0, "0", "Give up on remaining statements if replicas lag for longer than this many seconds; 0 waits indefinitely"))
 	cmd.AddArg("environment", "production", false)
 	util.AddGlobalOptions(cmd)
 	workspace.AddCommandOptions(cmd)
+	policy.AddCommandOptions(cmd)
+	migration.AddCommandOptions(cmd)
+	fixture.AddCommandOptions(cmd)
 	return mybase.ParseFakeCLI(t, cmd, fmt.Sprintf("appliertest %s", cliFlags))
 }
 
@@ -309,3 +329,18 @@ func setupHostList(t *testing.T, instances ...*tengo.Instance) {
 		fs.RemoveTestDirectory(t, "testdata/.scratch")
 	})
 }
+
+func (s ApplierIntegrationSuite) TestTargetCheckBinlog(t *testing.T) {
+	inst := s.d[0].Instance
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	if err := target.checkBinlog(); err != nil {
+		t.Errorf("Unexpected error from checkBinlog with binlog enabled: %v", err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--skip-binlog")
+	err := target.checkBinlog()
+	if inst.CanSkipBinlog() && err != nil {
+		t.Errorf("Unexpected error from checkBinlog with --skip-binlog: %v", err)
+	} else if !inst.CanSkipBinlog() && err == nil {
+		t.Error("Expected error from checkBinlog with --skip-binlog and insufficient privileges, but err was nil")
+	}
+}
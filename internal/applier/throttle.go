@@ -0,0 +1,104 @@
+package applier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// replicaThrottler pauses execution of statements while any of the configured
+// replicas are lagging behind their source by more than a threshold. This
+// prevents a series of DDL statements from causing replication incidents.
+type replicaThrottler struct {
+	replicas []*tengo.Instance
+	maxLag   time.Duration
+	timeout  time.Duration // 0 means wait indefinitely
+	interval time.Duration
+}
+
+// newReplicaThrottler returns a replicaThrottler based on the configuration
+// of dir, or nil if throttling is not enabled. Throttling requires both the
+// throttle-replicas and max-replica-lag options to be set.
+func newReplicaThrottler(dir *fs.Dir) (*replicaThrottler, error) {
+	hosts := dir.Config.GetSlice("throttle-replicas", ',', true)
+	maxLagSeconds, err := dir.Config.GetInt("max-replica-lag")
+	if err != nil {
+		return nil, ConfigError(err.Error())
+	} else if maxLagSeconds < 0 {
+		return nil, ConfigError("max-replica-lag cannot be negative")
+	} else if len(hosts) == 0 {
+		if maxLagSeconds > 0 {
+			log.Warnf("Option max-replica-lag has no effect in %s, since throttle-replicas is not set", dir)
+		}
+		return nil, nil
+	} else if maxLagSeconds == 0 {
+		return nil, ConfigError("Option throttle-replicas requires max-replica-lag to also be set")
+	}
+	timeoutSeconds, err := dir.Config.GetInt("replica-lag-timeout")
+	if err != nil {
+		return nil, ConfigError(err.Error())
+	}
+	replicas, err := dir.InstancesForHosts(hosts)
+	if err != nil {
+		return nil, err
+	}
+	return &replicaThrottler{
+		replicas: replicas,
+		maxLag:   time.Duration(maxLagSeconds) * time.Second,
+		timeout:  time.Duration(timeoutSeconds) * time.Second,
+		interval: time.Second,
+	}, nil
+}
+
+// Wait blocks until all replicas are lagging by no more than the threshold.
+// Replicas which cannot be queried, or which have replication stopped, are
+// treated as lagging. An error is returned if ctx is cancelled or the timeout
+// elapses.
+func (rt *replicaThrottler) Wait(ctx context.Context) error {
+	if rt == nil {
+		return nil
+	}
+	start := time.Now()
+	var paused bool
+	for {
+		problem := rt.check(ctx)
+		if problem == "" {
+			if paused {
+				log.Infof("Resuming after replicas caught up (paused for %s)", time.Since(start).Round(time.Second))
+			}
+			return nil
+		}
+		if !paused {
+			log.Warnf("Pausing until replicas catch up: %s", problem)
+			paused = true
+		} else {
+			log.Debugf("Still paused: %s", problem)
+		}
+		if rt.timeout > 0 && time.Since(start) >= rt.timeout {
+			return fmt.Errorf("replicas did not catch up within replica-lag-timeout of %s: %s", rt.timeout, problem)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rt.interval):
+		}
+	}
+}
+
+// check returns a description of the first problematic replica, or an empty
+// string if all replicas are within the lag threshold.
+func (rt *replicaThrottler) check(ctx context.Context) string {
+	for _, replica := range rt.replicas {
+		lag, err := replica.ReplicationLag(ctx)
+		if err != nil {
+			return fmt.Sprintf("unable to check replication lag of %s: %s", replica, err)
+		} else if lag > rt.maxLag {
+			return fmt.Sprintf("replica %s is %s behind, exceeding max-replica-lag of %s", replica, lag, rt.maxLag)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package applier
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestNewReplicaThrottler(t *testing.T) {
+	for _, flags := range []string{"", "--max-replica-lag=5"} {
+		if rt, err := newReplicaThrottler(getDir(t, "testdata/simple", flags)); rt != nil || err != nil {
+			t.Errorf("With flags %q, expected nil throttler and nil error, instead found %+v, %v", flags, rt, err)
+		}
+	}
+	for _, flags := range []string{"--throttle-replicas=replica1", "--throttle-replicas=replica1 --max-replica-lag=-1", "--throttle-replicas=replica1 --max-replica-lag=foo"} {
+		if _, err := newReplicaThrottler(getDir(t, "testdata/simple", flags)); err == nil {
+			t.Errorf("With flags %q, expected an error, but err was nil", flags)
+		}
+	}
+
+	rt, err := newReplicaThrottler(getDir(t, "testdata/simple", "--throttle-replicas=replica1,replica2:3307 --max-replica-lag=5 --replica-lag-timeout=60"))
+	if err != nil {
+		t.Fatalf("Unexpected error from newReplicaThrottler: %v", err)
+	}
+	if len(rt.replicas) != 2 || rt.replicas[1].Port != 3307 || rt.maxLag != 5*time.Second || rt.timeout != time.Minute {
+		t.Errorf("Unexpected throttler configuration: %+v", rt)
+	}
+
+	// A nil throttler never waits
+	var nilThrottler *replicaThrottler
+	if err := nilThrottler.Wait(context.Background()); err != nil {
+		t.Errorf("Unexpected error from nil throttler: %v", err)
+	}
+}
+
+func (s ApplierIntegrationSuite) TestReplicaThrottlerWait(t *testing.T) {
+	// The test instance is not a replica, so it should be treated as lagging
+	// until the timeout elapses
+	rt := &replicaThrottler{
+		replicas: []*tengo.Instance{s.d[0].Instance},
+		maxLag:   time.Second,
+		timeout:  50 * time.Millisecond,
+		interval: 10 * time.Millisecond,
+	}
+	if err := rt.Wait(context.Background()); err == nil || !strings.Contains(err.Error(), "not configured as a replica") {
+		t.Errorf("Expected Wait to return timeout error mentioning non-replica, instead found %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rt.timeout = 0
+	if err := rt.Wait(ctx); err != context.Canceled {
+		t.Errorf("Expected Wait to return context.Canceled, instead found %v", err)
+	}
+}
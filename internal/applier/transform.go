@@ -0,0 +1,124 @@
+package applier
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/util"
+)
+
+// StatementInfo provides context to a StatementTransformer.
+type StatementInfo struct {
+	InstanceName string
+	SchemaName   string
+	ObjectKey    tengo.ObjectKey
+	DiffType     tengo.DiffType
+}
+
+// StatementTransformer may rewrite a generated DDL statement, by returning
+// a different string than the one supplied. Returning a non-nil error vetoes
+// the statement, preventing it from being executed; the error is used as the
+// reason in log output.
+type StatementTransformer func(info StatementInfo, stmt string) (string, error)
+
+type namedTransformer struct {
+	name  string
+	order int
+	fn    StatementTransformer
+}
+
+var statementTransformers struct {
+	sync.RWMutex
+	list []namedTransformer
+}
+
+// AddStatementTransformer registers a StatementTransformer, which will be
+// applied to each generated statement before it is displayed or executed.
+// Transformers are applied in ascending order, with ties broken by name; each
+// receives the output of the previous one. Any ddl-transform shell command
+// configured via options is always applied last.
+func AddStatementTransformer(name string, order int, fn StatementTransformer) {
+	statementTransformers.Lock()
+	defer statementTransformers.Unlock()
+	statementTransformers.list = append(statementTransformers.list, namedTransformer{name: name, order: order, fn: fn})
+	sort.SliceStable(statementTransformers.list, func(i, j int) bool {
+		a, b := statementTransformers.list[i], statementTransformers.list[j]
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		return a.name < b.name
+	})
+}
+
+// VetoError indicates that a StatementTransformer prevented a statement from
+// being executed.
+type VetoError struct {
+	Transformer string
+	ObjectKey   tengo.ObjectKey
+	Err         error
+}
+
+// Error satisfies the builtin error interface.
+func (ve *VetoError) Error() string {
+	return fmt.Sprintf("Statement for %s vetoed by %s: %s", ve.ObjectKey, ve.Transformer, ve.Err)
+}
+
+// Unwrap returns the error returned by the transformer.
+func (ve *VetoError) Unwrap() error {
+	return ve.Err
+}
+
+// transformStatement applies all registered StatementTransformers, followed by
+// the ddl-transform shell command if configured, to stmt. A *VetoError is
+// returned if any transformer vetoes the statement.
+func (t *Target) transformStatement(info StatementInfo, stmt string) (string, error) {
+	statementTransformers.RLock()
+	list := statementTransformers.list
+	statementTransformers.RUnlock()
+	for _, tr := range list {
+		newStmt, err := tr.fn(info, stmt)
+		if err != nil {
+			return "", &VetoError{Transformer: tr.name, ObjectKey: info.ObjectKey, Err: err}
+		}
+		if newStmt != stmt {
+			log.Debugf("Statement for %s rewritten by %s", info.ObjectKey, tr.name)
+			stmt = newStmt
+		}
+	}
+
+	command := t.Dir.Config.Get("ddl-transform")
+	if command == "" {
+		return stmt, nil
+	}
+	variables := map[string]string{
+		"HOST":        t.Instance.Host,
+		"PORT":        strconv.Itoa(t.Instance.Port),
+		"SOCKET":      t.Instance.SocketPath,
+		"SCHEMA":      info.SchemaName,
+		"ENVIRONMENT": t.Dir.Config.Get("environment"),
+		"DDL":         stmt,
+		"NAME":        info.ObjectKey.Name,
+		"TYPE":        info.DiffType.String(),
+		"CLASS":       info.ObjectKey.Type.Caps(),
+		"DIRNAME":     t.Dir.BaseName(),
+		"DIRPATH":     t.Dir.Path,
+	}
+	s, err := util.NewInterpolatedShellOut(command, variables)
+	if err != nil {
+		return "", ConfigError(fmt.Sprintf("Invalid ddl-transform: %s", err))
+	}
+	output, err := s.RunCapture()
+	if err != nil {
+		return "", &VetoError{Transformer: "ddl-transform", ObjectKey: info.ObjectKey, Err: err}
+	}
+	if output = strings.TrimSpace(output); output != "" && output != stmt {
+		log.Debugf("Statement for %s rewritten by ddl-transform", info.ObjectKey)
+		stmt = output
+	}
+	return stmt, nil
+}
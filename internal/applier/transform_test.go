@@ -0,0 +1,88 @@
+package applier
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestTransformStatement(t *testing.T) {
+	inst, _ := tengo.NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/")
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", ""), SchemaName: "product"}
+	info := StatementInfo{
+		InstanceName: inst.String(),
+		SchemaName:   "product",
+		ObjectKey:    tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "posts"},
+		DiffType:     tengo.DiffTypeAlter,
+	}
+	defer func() {
+		statementTransformers.list = nil
+	}()
+
+	// Transformers should be applied in order, regardless of registration order
+	AddStatementTransformer("suffix", 20, func(info StatementInfo, stmt string) (string, error) {
+		return stmt + " /* suffix */", nil
+	})
+	AddStatementTransformer("ticket", 10, func(info StatementInfo, stmt string) (string, error) {
+		return stmt + " /* TICKET-123 */", nil
+	})
+	stmt, err := target.transformStatement(info, "ALTER TABLE `posts` ADD COLUMN `foo` int")
+	if expected := "ALTER TABLE `posts` ADD COLUMN `foo` int /* TICKET-123 */ /* suffix */"; stmt != expected || err != nil {
+		t.Errorf("Unexpected result from transformStatement: %q, %v", stmt, err)
+	}
+
+	// A transformer returning an error should veto the statement
+	AddStatementTransformer("blocker", 15, func(info StatementInfo, stmt string) (string, error) {
+		if info.DiffType == tengo.DiffTypeAlter {
+			return "", errors.New("ALTERs must go through the queue")
+		}
+		return stmt, nil
+	})
+	_, err = target.transformStatement(info, "ALTER TABLE `posts` ADD COLUMN `foo` int")
+	var vetoErr *VetoError
+	if !errors.As(err, &vetoErr) || vetoErr.Transformer != "blocker" || vetoErr.ObjectKey != info.ObjectKey {
+		t.Errorf("Expected VetoError from blocker, instead found %v", err)
+	}
+	statementTransformers.list = nil
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	target.Dir = getDir(t, "testdata/simple", "--ddl-transform='echo {DDL} -- {ENVIRONMENT}'")
+	stmt, err = target.transformStatement(info, "DROP TABLE `posts`")
+	if expected := "DROP TABLE `posts` -- production"; stmt != expected || err != nil {
+		t.Errorf("Unexpected result from ddl-transform: %q, %v", stmt, err)
+	}
+	target.Dir = getDir(t, "testdata/simple", "--ddl-transform='test {TYPE} != ALTER'")
+	if stmt, err = target.transformStatement(info, "ALTER TABLE `posts` ADD COLUMN `foo` int"); !errors.As(err, &vetoErr) || vetoErr.Transformer != "ddl-transform" {
+		t.Errorf("Expected VetoError from ddl-transform, instead found %q, %v", stmt, err)
+	}
+	info.DiffType = tengo.DiffTypeCreate
+	if stmt, err = target.transformStatement(info, "CREATE TABLE `posts` (id int)"); err != nil || !strings.HasPrefix(stmt, "CREATE TABLE") {
+		t.Errorf("Unexpected result from ddl-transform with no output: %q, %v", stmt, err)
+	}
+}
+
+func TestTransformedClauses(t *testing.T) {
+	origStmt := "ALTER TABLE `posts` ADD COLUMN `foo` int"
+	origClauses := "ADD COLUMN `foo` int"
+	cases := []struct {
+		newStmt  string
+		expected string
+		ok       bool
+	}{
+		{origStmt, origClauses, true},
+		{origStmt + ", ALGORITHM=INPLACE", "ADD COLUMN `foo` int, ALGORITHM=INPLACE", true},
+		{"ALTER TABLE `posts` ADD COLUMN `foo` bigint", "ADD COLUMN `foo` bigint", true},
+		{"/* TICKET-123 */ " + origStmt, origClauses, false},
+		{"ALTER TABLE `comments` ADD COLUMN `foo` int", origClauses, false},
+	}
+	for _, c := range cases {
+		if clauses, ok := transformedClauses(origStmt, origClauses, c.newStmt); clauses != c.expected || ok != c.ok {
+			t.Errorf("Unexpected result from transformedClauses for %q: returned %q, %t; expected %q, %t", c.newStmt, clauses, ok, c.expected, c.ok)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package applier
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+var skeemaVersion struct {
+	sync.RWMutex
+	value string
+}
+
+// SetVersion sets the Skeema version string recorded in version-table rows.
+func SetVersion(version string) {
+	skeemaVersion.Lock()
+	skeemaVersion.value = version
+	skeemaVersion.Unlock()
+}
+
+// versionTableName parses the value of the version-table option, which must be
+// a schema-qualified table name. It returns empty strings if the option is not
+// set.
+func versionTableName(value string) (schemaName, tableName string, err error) {
+	if value == "" {
+		return "", "", nil
+	}
+	schemaName, tableName, ok := strings.Cut(value, ".")
+	schemaName = strings.Trim(schemaName, "`")
+	tableName = strings.Trim(tableName, "`")
+	if !ok || schemaName == "" || tableName == "" || strings.Contains(tableName, ".") {
+		return "", "", ConfigError(fmt.Sprintf("Option version-table must be a schema-qualified table name, such as _skeema.versions; found %q", value))
+	}
+	return schemaName, tableName, nil
+}
+
+// CheckVersionTables returns a ConfigError if the version-table configured for
+// any target resides in a schema which is also a target on the same instance.
+// The version-table has no definition in the filesystem, so a subsequent push
+// with --allow-unsafe would otherwise drop it.
+func CheckVersionTables(groups []TargetGroup) error {
+	for _, tg := range groups {
+		targetSchemas := make(map[string]bool, len(tg))
+		for _, t := range tg {
+			targetSchemas[strings.ToLower(t.SchemaName)] = true
+		}
+		for _, t := range tg {
+			schemaName, tableName, err := versionTableName(t.Dir.Config.Get("version-table"))
+			if err != nil {
+				return err
+			} else if tableName != "" && targetSchemas[strings.ToLower(schemaName)] {
+				return ConfigError(fmt.Sprintf("Option version-table cannot refer to a table in schema %s on %s, since Skeema manages that schema and would treat the table as one to drop. Use a separate schema, such as _skeema.versions.", schemaName, t.Instance))
+			}
+		}
+	}
+	return nil
+}
+
+// diffHash returns a hex-encoded SHA-256 hash of the supplied statements, in
+// order. An empty set of statements still yields a hash, which is consistent
+// for all targets without differences.
+func diffHash(stmts []PlannedStatement) string {
+	h := sha256.New()
+	for _, stmt := range stmts {
+		h.Write([]byte(stmt.Statement()))
+		h.Write([]byte(";\n"))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// gitHeadSHA returns the commit SHA checked out in the git repo containing
+// dirPath, or an empty string if this cannot be determined.
+func gitHeadSHA(dirPath string) string {
+	out, err := exec.Command("git", "-C", dirPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// recordVersion writes a row to the version-table, if configured, describing
+// the push which was just completed on the target. The table and its schema are
+// created if they do not already exist. Each target's row is replaced
+// atomically in a single statement.
+func (t *Target) recordVersion(stmts []PlannedStatement) error {
+	schemaName, tableName, err := versionTableName(t.Dir.Config.Get("version-table"))
+	if err != nil || tableName == "" {
+		return err
+	}
+	// With --skip-binlog, the version row must not replicate either, since the
+	// DDL it describes did not
+	var params string
+	if !t.Dir.Config.GetBool("binlog") {
+		params = "sql_log_bin=0"
+	}
+	db, err := t.Instance.CachedConnectionPool("", params)
+	if err != nil {
+		return err
+	}
+	qualifiedName := tengo.EscapeIdentifier(schemaName) + "." + tengo.EscapeIdentifier(tableName)
+	if _, err := db.Exec("CREATE DATABASE IF NOT EXISTS " + tengo.EscapeIdentifier(schemaName)); err != nil {
+		return err
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + qualifiedName + ` (
+  schema_name varchar(64) NOT NULL,
+  git_sha varchar(64) DEFAULT NULL,
+  applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  skeema_version varchar(100) NOT NULL,
+  diff_hash char(64) NOT NULL,
+  statement_count int unsigned NOT NULL,
+  PRIMARY KEY (schema_name)
+) ENGINE=InnoDB`
+	if _, err := db.Exec(create); err != nil {
+		return err
+	}
+
+	var gitSHA interface{}
+	if sha := gitHeadSHA(t.Dir.Path); sha != "" {
+		gitSHA = sha
+	}
+	skeemaVersion.RLock()
+	version := skeemaVersion.value
+	skeemaVersion.RUnlock()
+	query := "REPLACE INTO " + qualifiedName + " (schema_name, git_sha, applied_at, skeema_version, diff_hash, statement_count) VALUES (?, ?, CURRENT_TIMESTAMP, ?, ?, ?)"
+	if _, err := db.Exec(query, t.SchemaName, gitSHA, version, diffHash(stmts), len(stmts)); err != nil {
+		return err
+	}
+	log.Debugf("Recorded push of %s %s in %s", t.Instance, t.SchemaName, qualifiedName)
+	return nil
+}
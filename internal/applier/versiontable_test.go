@@ -0,0 +1,111 @@
+package applier
+
+import (
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestVersionTableName(t *testing.T) {
+	cases := []struct {
+		value      string
+		schemaName string
+		tableName  string
+		expectErr  bool
+	}{
+		{"", "", "", false},
+		{"_skeema.versions", "_skeema", "versions", false},
+		{"`ops`.`schema_versions`", "ops", "schema_versions", false},
+		{"versions", "", "", true},
+		{".versions", "", "", true},
+		{"a.b.c", "", "", true},
+	}
+	for _, c := range cases {
+		schemaName, tableName, err := versionTableName(c.value)
+		if c.expectErr {
+			if _, ok := err.(ConfigError); !ok {
+				t.Errorf("Expected ConfigError for %q, instead found %v", c.value, err)
+			}
+		} else if err != nil || schemaName != c.schemaName || tableName != c.tableName {
+			t.Errorf("Unexpected result for %q: %q, %q, %v", c.value, schemaName, tableName, err)
+		}
+	}
+}
+
+func TestCheckVersionTables(t *testing.T) {
+	inst1, err := tengo.NewInstance("mysql", "root:fakepw@tcp(127.0.0.1:3306)/")
+	if err != nil {
+		t.Fatalf("Unexpected error from NewInstance: %v", err)
+	}
+	inst2, err := tengo.NewInstance("mysql", "root:fakepw@tcp(127.0.0.1:3307)/")
+	if err != nil {
+		t.Fatalf("Unexpected error from NewInstance: %v", err)
+	}
+	plainDir := getDir(t, "testdata/simple", "")
+	versionDir := getDir(t, "testdata/simple", "--version-table=analytics.versions")
+	groups := []TargetGroup{
+		{{Instance: inst1, Dir: versionDir, SchemaName: "product"}},
+		{{Instance: inst2, Dir: plainDir, SchemaName: "analytics"}},
+	}
+	if err := CheckVersionTables(groups); err != nil {
+		t.Errorf("Unexpected error from CheckVersionTables: %v", err)
+	}
+
+	// A version-table in a target schema on the same instance is rejected, even
+	// if the target using that schema doesn't configure version-table itself
+	groups[0] = append(groups[0], &Target{Instance: inst1, Dir: plainDir, SchemaName: "analytics"})
+	if _, ok := CheckVersionTables(groups).(ConfigError); !ok {
+		t.Error("Expected CheckVersionTables to return a ConfigError, but it did not")
+	}
+}
+
+func TestDiffHash(t *testing.T) {
+	stmts := []PlannedStatement{
+		&DDLStatement{stmt: "CREATE TABLE foo (id int)"},
+		&DDLStatement{stmt: "DROP TABLE bar"},
+	}
+	hash := diffHash(stmts)
+	if len(hash) != 64 {
+		t.Errorf("Expected 64-character hex hash, instead found %q", hash)
+	}
+	if diffHash(stmts[:1]) == hash || diffHash([]PlannedStatement{stmts[1], stmts[0]}) == hash {
+		t.Error("Expected hash to differ with different or reordered statements")
+	}
+	if diffHash(nil) != diffHash([]PlannedStatement{}) {
+		t.Error("Expected consistent hash for empty statement lists")
+	}
+}
+
+func (s ApplierIntegrationSuite) TestTargetRecordVersion(t *testing.T) {
+	inst := s.d[0].Instance
+	target := &Target{Instance: inst, Dir: getDir(t, "testdata/simple", "--version-table=_skeema_test.versions"), SchemaName: "product"}
+	SetVersion("1.2.3-test")
+	defer SetVersion("")
+	stmts := []PlannedStatement{&DDLStatement{stmt: "CREATE TABLE foo (id int)"}}
+	for n := 0; n < 2; n++ { // second pass confirms row is replaced
+		if err := target.recordVersion(stmts); err != nil {
+			t.Fatalf("Unexpected error from recordVersion: %v", err)
+		}
+	}
+	defer inst.DropSchema("_skeema_test", tengo.BulkDropOptions{})
+
+	db, err := inst.CachedConnectionPool("_skeema_test", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var rows []struct {
+		SchemaName     string `db:"schema_name"`
+		SkeemaVersion  string `db:"skeema_version"`
+		DiffHash       string `db:"diff_hash"`
+		StatementCount int    `db:"statement_count"`
+	}
+	if err := db.Select(&rows, "SELECT schema_name, skeema_version, diff_hash, statement_count FROM versions"); err != nil {
+		t.Fatalf("Unexpected error querying version-table: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("Expected 1 row in version-table, instead found %d", len(rows))
+	}
+	if row := rows[0]; row.SchemaName != "product" || row.SkeemaVersion != "1.2.3-test" || row.DiffHash != diffHash(stmts) || row.StatementCount != 1 {
+		t.Errorf("Unexpected row in version-table: %+v", row)
+	}
+}
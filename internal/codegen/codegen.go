@@ -0,0 +1,364 @@
+// Package codegen generates Go source code for model structs corresponding to
+// the tables of a schema. The generated structs include struct tags for common
+// database libraries, such as sqlx and GORM, and constants for enum values.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/export"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// NullableMode controls how nullable columns are represented in structs.
+type NullableMode int
+
+// Constants for NullableMode
+const (
+	NullableSQL     NullableMode = iota // use database/sql Null* types, e.g. sql.NullString
+	NullablePointer                     // use pointers, e.g. *string
+)
+
+// Options controls code generation.
+type Options struct {
+	Dir      string // output directory; empty if code generation is not configured
+	Package  string
+	Nullable NullableMode
+	Tags     []string // struct tag keys to emit: any of "db", "gorm", "json"
+}
+
+// AddCommandOptions adds code generation options to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("codegen",
+		mybase.StringOption("codegen-dir", 0, "", "Directory for generated Go model structs, relative to each schema dir"),
+		mybase.StringOption("codegen-package", 0, "", "Package name for generated Go code (default base name of codegen-dir)"),
+		mybase.StringOption("codegen-nullable", 0, "sql", `Representation of nullable columns in generated Go code (valid values: "sql", "pointer")`),
+		mybase.StringOption("codegen-tags", 0, "db", "Comma-separated list of struct tags for generated Go code (valid values: db, gorm, json)"),
+	)
+}
+
+// OptionsForDir returns Options based on the configuration of dir. If
+// codegen-dir is not configured, the returned Options will have an empty Dir
+// field.
+func OptionsForDir(dir *fs.Dir) (Options, error) {
+	var opts Options
+	if codegenDir := dir.Config.Get("codegen-dir"); codegenDir != "" {
+		opts.Dir = filepath.Join(dir.Path, codegenDir)
+	}
+	opts.Package = dir.Config.Get("codegen-package")
+	if opts.Package == "" && opts.Dir != "" {
+		opts.Package = packageName(filepath.Base(opts.Dir))
+	}
+	if nullable, err := dir.Config.GetEnum("codegen-nullable", "sql", "pointer"); err != nil {
+		return opts, err
+	} else if nullable == "pointer" {
+		opts.Nullable = NullablePointer
+	}
+	for _, tag := range dir.Config.GetSlice("codegen-tags", ',', true) {
+		tag = strings.ToLower(tag)
+		switch tag {
+		case "db", "gorm", "json":
+			opts.Tags = append(opts.Tags, tag)
+		default:
+			return opts, fmt.Errorf("Option codegen-tags has invalid value %q: valid values are db, gorm, json", tag)
+		}
+	}
+	return opts, nil
+}
+
+// FileName returns the name of the generated Go file for a schema.
+func FileName(schemaName string) string {
+	return strings.ToLower(invalidPackageChars.ReplaceAllString(schemaName, "_")) + ".go"
+}
+
+var invalidPackageChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// packageName converts name into a valid Go package name.
+func packageName(name string) string {
+	name = strings.ToLower(invalidPackageChars.ReplaceAllString(name, ""))
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "models" + name
+	}
+	return name
+}
+
+// Generate returns gofmt'ed Go source code defining a struct for each table in
+// schema, along with a string type and constants for each enum column.
+func Generate(schema *tengo.Schema, opts Options) ([]byte, error) {
+	tables := make([]*tengo.Table, len(schema.Tables))
+	copy(tables, schema.Tables)
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "models"
+	}
+	g := &generator{opts: opts, imports: make(map[string]bool)}
+	for _, table := range tables {
+		g.table(table)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by skeema codegen from schema %s. DO NOT EDIT.\n\n", schema.Name)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, strconv.Quote(imp))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&out, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	out.Write(g.body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+type generator struct {
+	opts    Options
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+func (g *generator) table(table *tengo.Table) {
+	structName := exportedName(table.Name)
+	if table.Comment != "" {
+		fmt.Fprintf(&g.body, "// %s represents a row of table %s: %s\n", structName, table.Name, singleLine(table.Comment))
+	} else {
+		fmt.Fprintf(&g.body, "// %s represents a row of table %s.\n", structName, table.Name)
+	}
+	fmt.Fprintf(&g.body, "type %s struct {\n", structName)
+	var enums bytes.Buffer
+	fieldNames := make(map[string]bool)
+	for _, col := range table.Columns {
+		fieldName := exportedName(col.Name)
+		for n := 2; fieldNames[fieldName]; n++ {
+			fieldName = exportedName(col.Name) + strconv.Itoa(n)
+		}
+		fieldNames[fieldName] = true
+		goType := g.goType(col)
+		if strings.HasPrefix(col.TypeInDB, "enum(") {
+			enumType := structName + fieldName
+			writeEnum(&enums, enumType, table.Name+"."+col.Name, col.TypeInDB)
+			if !col.Nullable || g.opts.Nullable == NullablePointer {
+				goType = strings.Replace(goType, "string", enumType, 1)
+			}
+		}
+		if col.Comment != "" {
+			fmt.Fprintf(&g.body, "// %s\n", singleLine(col.Comment))
+		}
+		fmt.Fprintf(&g.body, "%s %s %s\n", fieldName, goType, g.tags(table, col))
+	}
+	g.body.WriteString("}\n\n")
+	if g.hasTag("gorm") {
+		fmt.Fprintf(&g.body, "// TableName returns the name of the table, for use by GORM.\n")
+		fmt.Fprintf(&g.body, "func (%s) TableName() string {\nreturn %s\n}\n\n", structName, strconv.Quote(table.Name))
+	}
+	g.body.Write(enums.Bytes())
+}
+
+func (g *generator) hasTag(tag string) bool {
+	for _, t := range g.opts.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// tags returns the struct tags for a column's field.
+func (g *generator) tags(table *tengo.Table, col *tengo.Column) string {
+	var tags []string
+	for _, tag := range g.opts.Tags {
+		switch tag {
+		case "db", "json":
+			tags = append(tags, fmt.Sprintf("%s:%s", tag, strconv.Quote(col.Name)))
+		case "gorm":
+			tags = append(tags, fmt.Sprintf("gorm:%s", strconv.Quote(gormTag(table, col))))
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// gormTag returns the value of the gorm struct tag for a column, including
+// primary key, auto-increment, nullability, and index membership.
+func gormTag(table *tengo.Table, col *tengo.Column) string {
+	settings := []string{"column:" + col.Name}
+	if table.PrimaryKey != nil {
+		for _, part := range table.PrimaryKey.Parts {
+			if part.ColumnName == col.Name {
+				settings = append(settings, "primaryKey")
+			}
+		}
+	}
+	if col.AutoIncrement {
+		settings = append(settings, "autoIncrement")
+	}
+	if !col.Nullable {
+		settings = append(settings, "not null")
+	}
+	for _, idx := range table.SecondaryIndexes {
+		for n, part := range idx.Parts {
+			if part.ColumnName != col.Name {
+				continue
+			}
+			kind := "index"
+			if idx.Unique {
+				kind = "uniqueIndex"
+			}
+			setting := kind + ":" + idx.Name
+			if len(idx.Parts) > 1 {
+				setting += ",priority:" + strconv.Itoa(n+1)
+			}
+			settings = append(settings, setting)
+		}
+	}
+	return strings.Join(settings, ";")
+}
+
+// goType returns the Go type used for a column.
+func (g *generator) goType(col *tengo.Column) string {
+	colType := strings.ToLower(col.TypeInDB)
+	base := colType
+	if pos := strings.IndexAny(colType, "( "); pos > 0 {
+		base = colType[:pos]
+	}
+	unsigned := strings.Contains(colType, " unsigned")
+	var goType, nullType string
+	switch base {
+	case "tinyint":
+		if strings.HasPrefix(colType, "tinyint(1)") && !unsigned {
+			goType, nullType = "bool", "sql.NullBool"
+		} else if unsigned {
+			goType, nullType = "uint8", "sql.NullInt16"
+		} else {
+			goType, nullType = "int8", "sql.NullInt16"
+		}
+	case "smallint":
+		if unsigned {
+			goType, nullType = "uint16", "sql.NullInt32"
+		} else {
+			goType, nullType = "int16", "sql.NullInt16"
+		}
+	case "mediumint", "int", "integer":
+		if unsigned {
+			goType, nullType = "uint32", "sql.NullInt64"
+		} else {
+			goType, nullType = "int32", "sql.NullInt32"
+		}
+	case "bigint":
+		if unsigned {
+			goType, nullType = "uint64", "sql.NullInt64"
+		} else {
+			goType, nullType = "int64", "sql.NullInt64"
+		}
+	case "year":
+		goType, nullType = "int16", "sql.NullInt16"
+	case "float":
+		goType, nullType = "float32", "sql.NullFloat64"
+	case "double", "real":
+		goType, nullType = "float64", "sql.NullFloat64"
+	case "bit":
+		if strings.HasPrefix(colType, "bit(1)") {
+			goType, nullType = "bool", "sql.NullBool"
+		} else {
+			goType, nullType = "uint64", "sql.NullInt64"
+		}
+	case "date", "datetime", "timestamp":
+		g.imports["time"] = true
+		goType, nullType = "time.Time", "sql.NullTime"
+	case "json":
+		// json.RawMessage is already nilable, so no special handling for NULL
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		// []byte is already nilable, so no special handling for NULL
+		return "[]byte"
+	default: // char, varchar, text types, enum, set, decimal, time
+		goType, nullType = "string", "sql.NullString"
+	}
+	if !col.Nullable {
+		return goType
+	} else if g.opts.Nullable == NullablePointer {
+		return "*" + goType
+	}
+	g.imports["database/sql"] = true
+	return nullType
+}
+
+// writeEnum writes a string type for an enum column, along with a constant
+// for each of its values.
+func writeEnum(buf *bytes.Buffer, typeName, colName, colType string) {
+	fmt.Fprintf(buf, "// %s represents the values of enum column %s.\n", typeName, colName)
+	fmt.Fprintf(buf, "type %s string\n\n", typeName)
+	fmt.Fprintf(buf, "// Values of %s\n", typeName)
+	buf.WriteString("const (\n")
+	seen := make(map[string]bool)
+	for _, val := range export.EnumValues(colType) {
+		suffix := exportedName(val)
+		if val == "" {
+			suffix = "Empty"
+		}
+		constName := typeName + suffix
+		for n := 2; seen[constName]; n++ {
+			constName = typeName + suffix + strconv.Itoa(n)
+		}
+		seen[constName] = true
+		fmt.Fprintf(buf, "%s %s = %s\n", constName, typeName, strconv.Quote(val))
+	}
+	buf.WriteString(")\n\n")
+}
+
+// commonInitialisms are words which are fully capitalized in Go identifiers,
+// per the conventions of golint.
+var commonInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true,
+	"SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true,
+	"URL": true, "UTF8": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// exportedName converts a table, column, or enum value name into an exported
+// Go identifier, for example "user_id" becomes "UserID".
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			runes := []rune(word)
+			b.WriteRune(unicode.ToUpper(runes[0]))
+			b.WriteString(string(runes[1:]))
+		}
+	}
+	result := b.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// singleLine collapses a comment onto a single line, for use in Go comments.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,119 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestGenerate(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	users, err := tengo.NewTableBuilder("users").
+		Column("id", "bigint unsigned", tengo.AutoIncrement()).
+		Column("email", "varchar(100)", tengo.NotNull(), tengo.ColumnComment("login address")).
+		Column("status", "enum('active','banned','')", tengo.NotNull(), tengo.DefaultString("active")).
+		Column("nickname", "varchar(30)").
+		Column("is_admin", "tinyint(1)", tengo.NotNull()).
+		Column("created_at", "timestamp", tengo.NotNull(), tengo.DefaultExpr("CURRENT_TIMESTAMP")).
+		Column("deleted_at", "datetime").
+		Column("settings", "json").
+		PrimaryKey("id").
+		UniqueIndex("email", "email").
+		Index("status_created", "status", "created_at").
+		Comment("registered users").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	schema := &tengo.Schema{Name: "app", Tables: []*tengo.Table{users}}
+
+	src, err := Generate(schema, Options{Package: "models", Tags: []string{"db", "gorm"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from Generate: %v", err)
+	}
+	expected := `// Code generated by skeema codegen from schema app. DO NOT EDIT.
+
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Users represents a row of table users: registered users
+type Users struct {
+	ID uint64 ` + "`" + `db:"id" gorm:"column:id;primaryKey;autoIncrement;not null"` + "`" + `
+	// login address
+	Email     string          ` + "`" + `db:"email" gorm:"column:email;not null;uniqueIndex:email"` + "`" + `
+	Status    UsersStatus     ` + "`" + `db:"status" gorm:"column:status;not null;index:status_created,priority:1"` + "`" + `
+	Nickname  sql.NullString  ` + "`" + `db:"nickname" gorm:"column:nickname"` + "`" + `
+	IsAdmin   bool            ` + "`" + `db:"is_admin" gorm:"column:is_admin;not null"` + "`" + `
+	CreatedAt time.Time       ` + "`" + `db:"created_at" gorm:"column:created_at;not null;index:status_created,priority:2"` + "`" + `
+	DeletedAt sql.NullTime    ` + "`" + `db:"deleted_at" gorm:"column:deleted_at"` + "`" + `
+	Settings  json.RawMessage ` + "`" + `db:"settings" gorm:"column:settings"` + "`" + `
+}
+
+// TableName returns the name of the table, for use by GORM.
+func (Users) TableName() string {
+	return "users"
+}
+
+// UsersStatus represents the values of enum column users.status.
+type UsersStatus string
+
+// Values of UsersStatus
+const (
+	UsersStatusActive UsersStatus = "active"
+	UsersStatusBanned UsersStatus = "banned"
+	UsersStatusEmpty  UsersStatus = ""
+)
+`
+	if string(src) != expected {
+		t.Errorf("Unexpected generated code:\n%s\nExpected:\n%s", src, expected)
+	}
+
+	// Pointer mode, with only json tags
+	src, err = Generate(schema, Options{Nullable: NullablePointer, Tags: []string{"json"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from Generate: %v", err)
+	}
+	for _, line := range []string{"package models\n", "\tNickname  *string         `json:\"nickname\"`\n", "\tDeletedAt *time.Time      `json:\"deleted_at\"`\n"} {
+		if !strings.Contains(string(src), line) {
+			t.Errorf("Expected generated code to contain %q, but it did not:\n%s", line, src)
+		}
+	}
+	if strings.Contains(string(src), "database/sql") || strings.Contains(string(src), "TableName") {
+		t.Errorf("Unexpected content in generated code:\n%s", src)
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"user_id":      "UserID",
+		"users":        "Users",
+		"api_key_uuid": "APIKeyUUID",
+		"event-log":    "EventLog",
+		"2fa_secret":   "X2faSecret",
+		"":             "X",
+		"CamelCase":    "CamelCase",
+	}
+	for input, expected := range cases {
+		if actual := exportedName(input); actual != expected {
+			t.Errorf("Expected exportedName(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
+func TestPackageAndFileName(t *testing.T) {
+	if actual := packageName("my-models"); actual != "mymodels" {
+		t.Errorf("Unexpected package name %q", actual)
+	}
+	if actual := packageName("2024"); actual != "models2024" {
+		t.Errorf("Unexpected package name %q", actual)
+	}
+	if actual := FileName("Shard-01"); actual != "shard_01.go" {
+		t.Errorf("Unexpected file name %q", actual)
+	}
+}
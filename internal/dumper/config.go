@@ -6,11 +6,13 @@ import (
 
 // Options controls dumper behavior.
 type Options struct {
-	IncludeAutoInc bool                     // if false, strip AUTO_INCREMENT clauses from CREATE TABLE
-	Partitioning   tengo.PartitioningMode   // PartitioningKeep: retain previous FS partitioning clause; PartitioningRemove: strip partitioning clause
-	CountOnly      bool                     // if true, skip writing files, just report count of rewrites
-	skipKeys       map[tengo.ObjectKey]bool // skip objects with true values
-	onlyKeys       map[tengo.ObjectKey]bool // if map is non-nil, only format objects with true values
+	IncludeAutoInc      bool                     // if false, strip AUTO_INCREMENT clauses from CREATE TABLE
+	Partitioning        tengo.PartitioningMode   // PartitioningKeep: retain previous FS partitioning clause; PartitioningRemove: strip partitioning clause
+	CountOnly           bool                     // if true, skip writing files, just report count of rewrites
+	NameCaseMode        tengo.NameCaseMode       // server's lower_case_table_names; if non-zero, table names in files retain their original letter case
+	CanonicalCheckNames bool                     // if true, rename server-generated check constraints to a flavor-independent format
+	skipKeys            map[tengo.ObjectKey]bool // skip objects with true values
+	onlyKeys            map[tengo.ObjectKey]bool // if map is non-nil, only format objects with true values
 }
 
 // OnlyKeys specifies a list of tengo.ObjectKeys that the dump should
@@ -66,15 +66,33 @@ func updateCreateStatements(schema *tengo.Schema, dir *fs.Dir, opts Options) err
 	// TODO: handle dirs that contain multiple logical schemas by name
 	logicalSchema := dir.LogicalSchemas[0]
 
+	// Triggers are placed in the same file as their table, so handle them after
+	// all other objects, in the order that they fire
 	dbObjects := schema.Objects()
-	for key, object := range dbObjects {
+	keys := make([]tengo.ObjectKey, 0, len(dbObjects))
+	for key := range dbObjects {
+		if key.Type != tengo.ObjectTypeTrigger {
+			keys = append(keys, key)
+		}
+	}
+	for _, trigger := range schema.Triggers {
+		keys = append(keys, trigger.ObjectKey())
+	}
+
+	matchedCreates := make(map[tengo.ObjectKey]bool, len(dbObjects))
+	for _, key := range keys {
+		object := dbObjects[key]
 		if opts.shouldIgnore(object) {
 			continue
 		}
 		canonicalCreate := object.Def()
+		if table, ok := object.(*tengo.Table); ok && opts.CanonicalCheckNames {
+			canonicalCreate = table.CanonicalCheckNames()
+		}
 		var fsCreate string
-		stmt := logicalSchema.Creates[key]
+		stmt := opts.findCreate(logicalSchema, key)
 		if stmt != nil {
+			matchedCreates[stmt.ObjectKey()] = true
 			fsCreate, _ = stmt.SplitTextBody()
 		}
 
@@ -106,6 +124,14 @@ func updateCreateStatements(schema *tengo.Schema, dir *fs.Dir, opts Options) err
 			return errors.New("fatal parser exception")
 		}
 
+		// If table names are case-insensitive, retain the letter case used in the
+		// filesystem, rather than rewriting the file to use the server's casing
+		if stmt != nil && key.Type == tengo.ObjectTypeTable && opts.NameCaseMode > tengo.NameCaseAsIs {
+			if fsName := tengo.ParseStatementInString(fsCreate).ObjectName; fsName != key.Name && opts.NameCaseMode.NamesEqual(fsName, key.Name) {
+				canonicalCreate = tengo.ReplaceCreateTableName(canonicalCreate, fsName)
+			}
+		}
+
 		if stmt == nil {
 			// We didn't have a Statement from the fs, so append a new one, or just mark
 			// the file as dirty if doing CountOnly.
@@ -129,7 +155,7 @@ func updateCreateStatements(schema *tengo.Schema, dir *fs.Dir, opts Options) err
 
 	// Handle create statements that are in FS but do not exist in DB
 	for key, stmt := range logicalSchema.Creates {
-		if _, inDB := dbObjects[key]; !inDB && !opts.shouldIgnore(key) {
+		if _, inDB := dbObjects[key]; !inDB && !matchedCreates[key] && !opts.shouldIgnore(key) {
 			sqlFile := dir.FileFor(stmt)
 			if opts.CountOnly {
 				sqlFile.Dirty = true
@@ -141,3 +167,19 @@ func updateCreateStatements(schema *tengo.Schema, dir *fs.Dir, opts Options) err
 
 	return nil
 }
+
+// findCreate returns the statement in logicalSchema which creates the object
+// with the supplied key, or nil if there is no such statement. If opts
+// specifies a case-insensitive NameCaseMode, table names are compared
+// case-insensitively.
+func (opts *Options) findCreate(logicalSchema *fs.LogicalSchema, key tengo.ObjectKey) *tengo.Statement {
+	if stmt := logicalSchema.Creates[key]; stmt != nil || key.Type != tengo.ObjectTypeTable || opts.NameCaseMode <= tengo.NameCaseAsIs {
+		return stmt
+	}
+	for fsKey, stmt := range logicalSchema.Creates {
+		if fsKey.Type == key.Type && opts.NameCaseMode.NamesEqual(fsKey.Name, key.Name) {
+			return stmt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+// Package dumpfile obtains schema definitions from the output of logical dump
+// tools, such as mysqldump and mydumper. This permits creating a filesystem
+// representation of schemas in environments where Skeema cannot connect to the
+// database server directly.
+package dumpfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// Dump represents the schemas defined by the output of a logical dump tool.
+// Tables and routines only have their Name, Type (routines only), and
+// CreateStatement fields populated, which is sufficient for writing them to
+// the filesystem. Views, triggers, and events are not supported and are
+// skipped with a warning.
+type Dump struct {
+	Schemas []*tengo.Schema
+	Flavor  tengo.Flavor // flavor of the dumped server, if it could be identified
+}
+
+// Schema returns the schema with the supplied name, or nil if the dump does
+// not contain such a schema.
+func (d *Dump) Schema(name string) *tengo.Schema {
+	for _, s := range d.Schemas {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Parse reads the dump at path, which may be either a file of mysqldump output
+// or a directory of mydumper output. Objects which are not associated with any
+// schema name in the dump itself -- as in the output of mysqldump for a single
+// database without --databases -- are placed in defaultSchema; if this is
+// empty, the database name from the mysqldump header is used instead.
+func Parse(path, defaultSchema string) (*Dump, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return ParseMydumper(path)
+	}
+	r, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ParseMysqldump(r, path, defaultSchema)
+}
+
+var (
+	reServerVersion = regexp.MustCompile(`(?m)^-- Server version\s+(\S+)`)
+	reHeaderDB      = regexp.MustCompile(`(?m)^-- Host: .*\sDatabase: (\S+)`)
+)
+
+// ParseMysqldump reads the output of mysqldump from r. The dump should have
+// been generated with --no-data, although INSERT statements are skipped if
+// present. filePath is only used in error messages. See Parse for usage of
+// defaultSchema.
+func ParseMysqldump(r io.Reader, filePath, defaultSchema string) (*Dump, error) {
+	statements, err := tengo.ParseStatements(r, filePath)
+	if err != nil {
+		return nil, err
+	}
+	b := newBuilder()
+	for _, stmt := range statements {
+		if stmt.Type != tengo.StatementTypeNoop {
+			continue
+		}
+		if matches := reServerVersion.FindStringSubmatch(stmt.Text); matches != nil && !b.dump.Flavor.Known() {
+			b.dump.Flavor = tengo.IdentifyFlavor(matches[1], "")
+		}
+		if matches := reHeaderDB.FindStringSubmatch(stmt.Text); matches != nil && defaultSchema == "" {
+			defaultSchema = matches[1]
+		}
+	}
+	if err := b.addStatements(statements, defaultSchema); err != nil {
+		return nil, err
+	}
+	return b.finish(), nil
+}
+
+// ParseMydumper reads the schema files in a directory of mydumper output. Data
+// files and the metadata file are ignored. Files compressed with gzip are
+// supported.
+func ParseMydumper(dirPath string) (*Dump, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	b := newBuilder()
+	var postFiles []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gz")
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			if strings.Contains(name, "-schema") && !entry.IsDir() {
+				log.Warnf("Skipping %s: unsupported compression format", entry.Name())
+			}
+			continue
+		}
+		base := strings.TrimSuffix(name, ".sql")
+		var dbName string
+		switch {
+		case strings.HasSuffix(base, "-schema-create"):
+			dbName = strings.TrimSuffix(base, "-schema-create")
+		case strings.HasSuffix(base, "-schema-post"):
+			// Routines are processed after all schema-create files, so that they are
+			// associated with the correct schema name
+			postFiles = append(postFiles, entry.Name())
+			continue
+		case strings.Contains(base, "-schema"):
+			// Table, view, trigger, and sequence files are named db.object-schema*,
+			// where the object name may itself contain dots
+			dbName, _, _ = strings.Cut(base[:strings.LastIndex(base, "-schema")], ".")
+		default:
+			continue // data file
+		}
+		if err := b.addFile(filepath.Join(dirPath, entry.Name()), dbName); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range postFiles {
+		dbName := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), "-schema-post.sql")
+		if err := b.addFile(filepath.Join(dirPath, name), dbName); err != nil {
+			return nil, err
+		}
+	}
+	return b.finish(), nil
+}
+
+// openFile opens the file at path for reading, decompressing it if its name
+// ends in .gz.
+func openFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil || !strings.HasSuffix(path, ".gz") {
+		return f, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return gzipFile{gz, f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (gf gzipFile) Close() error {
+	gf.Reader.Close()
+	return gf.f.Close()
+}
+
+// builder accumulates schemas from a series of statements.
+type builder struct {
+	dump    *Dump
+	skipped map[string]map[string]bool // schema name -> "type name" of each skipped object
+}
+
+func newBuilder() *builder {
+	return &builder{
+		dump:    &Dump{},
+		skipped: make(map[string]map[string]bool),
+	}
+}
+
+// schema returns the schema with the supplied name, adding it to the dump if
+// not already present.
+func (b *builder) schema(name string) *tengo.Schema {
+	s := b.dump.Schema(name)
+	if s == nil {
+		s = &tengo.Schema{Name: name}
+		b.dump.Schemas = append(b.dump.Schemas, s)
+	}
+	return s
+}
+
+// addFile parses the file at path and adds its statements to the dump.
+func (b *builder) addFile(path, defaultSchema string) error {
+	r, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	statements, err := tengo.ParseStatements(r, path)
+	if err != nil {
+		return err
+	}
+	return b.addStatements(statements, defaultSchema)
+}
+
+var (
+	reVersionedComment  = regexp.MustCompile(`/\*!\d*\s?|\s?\*/`)
+	reVersionedContents = regexp.MustCompile(`(?s)/\*!\d*\s?(.*?)\s?\*/`)
+	reCreateDatabase    = regexp.MustCompile("(?is)^CREATE\\s+(?:DATABASE|SCHEMA)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?(`(?:[^`]|``)+`|\\w+)(.*)")
+	reCharSet           = regexp.MustCompile(`(?i)\bCHARACTER\s+SET\s*=?\s*(\w+)|\bCHARSET\s*=?\s*(\w+)`)
+	reCollate           = regexp.MustCompile(`(?i)\bCOLLATE\s*=?\s*(\w+)`)
+	reUnsupported       = regexp.MustCompile("(?is)^CREATE\\s+(?:.*?\\s)?(VIEW|TRIGGER|EVENT)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?((?:`(?:[^`]|``)+`|\\w+)(?:\\.(?:`(?:[^`]|``)+`|\\w+))?)")
+)
+
+// addStatements adds the CREATE statements in statements to the dump. Other
+// statements, such as SET, DROP, and INSERT, are ignored.
+func (b *builder) addStatements(statements []*tengo.Statement, defaultSchema string) error {
+	for _, stmt := range statements {
+		schemaName := stmt.Schema()
+		if schemaName == "" {
+			schemaName = defaultSchema
+		}
+		if stmt.Type == tengo.StatementTypeCreate && (stmt.ObjectType == tengo.ObjectTypeView || stmt.ObjectType == tengo.ObjectTypeTrigger) {
+			b.skip(schemaName, string(stmt.ObjectType), stmt.ObjectName, stmt)
+			continue
+		} else if stmt.Type == tengo.StatementTypeCreate {
+			if schemaName == "" {
+				return fmt.Errorf("%s: unable to determine schema name of %s; please specify a schema name", stmt.Location(), stmt.ObjectKey())
+			}
+			b.addObject(b.schema(schemaName), stmt)
+			continue
+		}
+
+		// mysqldump wraps some CREATE statements partially or entirely in
+		// version-gated comments, so these must be unwrapped before examining the
+		// statement. Statements consisting entirely of comments are parsed as noop.
+		var body string
+		if stmt.Type == tengo.StatementTypeUnknown {
+			body, _ = stmt.SplitTextBody()
+			body = reVersionedComment.ReplaceAllString(body, " ")
+		} else if stmt.Type == tengo.StatementTypeNoop {
+			for _, matches := range reVersionedContents.FindAllStringSubmatch(stmt.Text, -1) {
+				body += matches[1] + " "
+			}
+		}
+		body = strings.TrimSpace(body)
+		if matches := reCreateDatabase.FindStringSubmatch(body); matches != nil {
+			s := b.schema(unquoteIdentifier(matches[1]))
+			if cs := reCharSet.FindStringSubmatch(matches[2]); cs != nil {
+				s.CharSet = cs[1] + cs[2]
+			}
+			if coll := reCollate.FindStringSubmatch(matches[2]); coll != nil {
+				s.Collation = coll[1]
+			}
+		} else if matches := reUnsupported.FindStringSubmatch(body); matches != nil {
+			objType, name := strings.ToLower(matches[1]), matches[2]
+			if qualifier, objName, ok := splitQualifiedName(name); ok {
+				schemaName, name = qualifier, objName
+			} else {
+				name = unquoteIdentifier(name)
+			}
+			b.skip(schemaName, objType, name, stmt)
+		}
+	}
+	return nil
+}
+
+// skip records that the object of the supplied type and name was skipped, and
+// logs a warning the first time this occurs for the object.
+func (b *builder) skip(schemaName, objType, name string, stmt *tengo.Statement) {
+	if b.skipped[schemaName] == nil {
+		b.skipped[schemaName] = make(map[string]bool)
+	}
+	if key := objType + " " + name; !b.skipped[schemaName][key] {
+		b.skipped[schemaName][key] = true
+		log.Warnf("Skipping %s %s.%s at %s: this object type is not supported", objType, tengo.EscapeIdentifier(schemaName), tengo.EscapeIdentifier(name), stmt.Location())
+	}
+}
+
+// addObject adds the table or routine created by stmt to s. If s already has
+// an object with the same name and type, it is replaced.
+func (b *builder) addObject(s *tengo.Schema, stmt *tengo.Statement) {
+	create := stmt.Body()
+	if stmt.ObjectType == tengo.ObjectTypeTable {
+		for n, t := range s.Tables {
+			if t.Name == stmt.ObjectName {
+				s.Tables = append(s.Tables[:n], s.Tables[n+1:]...)
+				break
+			}
+		}
+		s.Tables = append(s.Tables, &tengo.Table{Name: stmt.ObjectName, CreateStatement: create})
+		return
+	}
+	for n, r := range s.Routines {
+		if r.ObjectKey() == stmt.ObjectKey() {
+			s.Routines = append(s.Routines[:n], s.Routines[n+1:]...)
+			break
+		}
+	}
+	s.Routines = append(s.Routines, &tengo.Routine{Name: stmt.ObjectName, Type: stmt.ObjectType, CreateStatement: create})
+}
+
+// finish returns the completed dump. Tables with the same name as a skipped
+// view are removed, since both mysqldump and mydumper create placeholder
+// tables for views, to permit other views to refer to them before they are
+// created.
+func (b *builder) finish() *Dump {
+	for _, s := range b.dump.Schemas {
+		tables := s.Tables[:0]
+		for _, t := range s.Tables {
+			if !b.skipped[s.Name]["view "+t.Name] {
+				tables = append(tables, t)
+			}
+		}
+		s.Tables = tables
+		sort.Slice(s.Tables, func(i, j int) bool { return s.Tables[i].Name < s.Tables[j].Name })
+		sort.Slice(s.Routines, func(i, j int) bool {
+			if s.Routines[i].Type != s.Routines[j].Type {
+				return s.Routines[i].Type > s.Routines[j].Type
+			}
+			return s.Routines[i].Name < s.Routines[j].Name
+		})
+	}
+	return b.dump
+}
+
+// splitQualifiedName splits a name of the form schema.object, where either
+// part may be backtick-quoted. ok is false if name is not qualified.
+func splitQualifiedName(name string) (qualifier, objName string, ok bool) {
+	var inQuote bool
+	for n, c := range name {
+		if c == '`' {
+			inQuote = !inQuote
+		} else if c == '.' && !inQuote {
+			return unquoteIdentifier(name[:n]), unquoteIdentifier(name[n+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// unquoteIdentifier removes backtick quoting from name, if present.
+func unquoteIdentifier(name string) string {
+	if len(name) < 2 || name[0] != '`' || name[len(name)-1] != '`' {
+		return name
+	}
+	return strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+}
@@ -0,0 +1,117 @@
+package dumpfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestParseMysqldump(t *testing.T) {
+	dump, err := Parse("testdata/mysqldump.sql", "")
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+	if expected := tengo.FlavorMySQL80.Dot(35); dump.Flavor != expected {
+		t.Errorf("Expected flavor %s, instead found %s", expected, dump.Flavor)
+	}
+	if len(dump.Schemas) != 2 {
+		t.Fatalf("Expected 2 schemas, instead found %d", len(dump.Schemas))
+	}
+
+	app := dump.Schema("app")
+	if app == nil || app.CharSet != "utf8mb4" || app.Collation != "utf8mb4_0900_ai_ci" {
+		t.Fatalf("Schema app missing or has unexpected defaults: %+v", app)
+	}
+	if len(app.Tables) != 1 || app.Tables[0].Name != "users" {
+		t.Fatalf("Unexpected tables in schema app: %+v", app.Tables)
+	}
+	if create := app.Tables[0].CreateStatement; !strings.HasPrefix(create, "CREATE TABLE `users` (") || !strings.HasSuffix(create, "COLLATE=utf8mb4_0900_ai_ci") {
+		t.Errorf("Unexpected CREATE TABLE: %s", create)
+	}
+	if len(app.Routines) != 2 {
+		t.Fatalf("Expected 2 routines in schema app, instead found %d", len(app.Routines))
+	}
+	proc, fn := app.Routines[0], app.Routines[1]
+	if proc.Type != tengo.ObjectTypeProc || proc.Name != "list_users" || !strings.HasPrefix(proc.CreateStatement, "CREATE DEFINER=`root`@`%` PROCEDURE `list_users`()") || !strings.HasSuffix(proc.CreateStatement, "END") {
+		t.Errorf("Unexpected procedure: %+v", proc)
+	}
+	if fn.Type != tengo.ObjectTypeFunc || fn.Name != "add_one" || !strings.HasSuffix(fn.CreateStatement, "RETURN x + 1") {
+		t.Errorf("Unexpected function: %+v", fn)
+	}
+
+	other := dump.Schema("other")
+	if other == nil || other.CharSet != "latin1" || other.Collation != "" || len(other.Tables) != 1 || len(other.Routines) != 0 {
+		t.Errorf("Schema other missing or has unexpected contents: %+v", other)
+	}
+
+	// Without USE commands or a database name in the header, a default schema
+	// name is required
+	input := "CREATE TABLE foo (id int);\n"
+	if _, err := ParseMysqldump(strings.NewReader(input), "", ""); err == nil {
+		t.Error("Expected error from ParseMysqldump without any schema name, but err was nil")
+	}
+	if dump, err := ParseMysqldump(strings.NewReader(input), "", "bar"); err != nil {
+		t.Errorf("Unexpected error from ParseMysqldump: %v", err)
+	} else if len(dump.Schemas) != 1 || dump.Schemas[0].Name != "bar" || dump.Flavor.Known() {
+		t.Errorf("Unexpected result from ParseMysqldump: %+v", dump)
+	}
+	input = "-- MySQL dump 10.19  Distrib 10.6.12-MariaDB, for debian-linux-gnu (x86_64)\n--\n-- Host: db1    Database: baz\n-- Server version\t10.6.12-MariaDB-log\n\n" + input
+	if dump, err := ParseMysqldump(strings.NewReader(input), "", ""); err != nil {
+		t.Errorf("Unexpected error from ParseMysqldump: %v", err)
+	} else if len(dump.Schemas) != 1 || dump.Schemas[0].Name != "baz" || dump.Flavor != tengo.FlavorMariaDB106.Dot(12) {
+		t.Errorf("Unexpected result from ParseMysqldump: %+v", dump)
+	}
+}
+
+func TestParseMydumper(t *testing.T) {
+	dump, err := Parse("testdata/mydumper", "")
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+	if dump.Flavor.Known() {
+		t.Errorf("Expected flavor to be unknown, instead found %s", dump.Flavor)
+	}
+	if len(dump.Schemas) != 1 {
+		t.Fatalf("Expected 1 schema, instead found %d", len(dump.Schemas))
+	}
+	app := dump.Schemas[0]
+	if app.Name != "app" || app.CharSet != "utf8mb4" || app.Collation != "utf8mb4_unicode_ci" {
+		t.Errorf("Unexpected schema name or defaults: %+v", app)
+	}
+	var tableNames []string
+	for _, table := range app.Tables {
+		tableNames = append(tableNames, table.Name)
+	}
+	if actual := strings.Join(tableNames, ","); actual != "posts,zipped" {
+		t.Errorf("Unexpected tables: %s", actual)
+	}
+	if len(app.Routines) != 1 || app.Routines[0].Name != "purge" || !strings.HasSuffix(app.Routines[0].CreateStatement, "END") {
+		t.Errorf("Unexpected routines: %+v", app.Routines)
+	}
+
+	if _, err := Parse("testdata/does-not-exist", ""); err == nil {
+		t.Error("Expected error from Parse on nonexistent path, but err was nil")
+	}
+}
+
+func TestSplitQualifiedName(t *testing.T) {
+	cases := []struct {
+		input     string
+		qualifier string
+		name      string
+		ok        bool
+	}{
+		{"foo", "", "", false},
+		{"`foo.bar`", "", "", false},
+		{"foo.bar", "foo", "bar", true},
+		{"`foo`.`b``ar`", "foo", "b`ar", true},
+		{"`f.oo`.bar", "f.oo", "bar", true},
+	}
+	for _, c := range cases {
+		qualifier, name, ok := splitQualifiedName(c.input)
+		if qualifier != c.qualifier || name != c.name || ok != c.ok {
+			t.Errorf("Unexpected result from splitQualifiedName(%q): %q, %q, %t", c.input, qualifier, name, ok)
+		}
+	}
+}
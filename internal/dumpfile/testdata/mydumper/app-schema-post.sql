@@ -0,0 +1,7 @@
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `purge`()
+BEGIN
+  DELETE FROM posts;
+  SELECT 1;
+END ;;
+DELIMITER ;
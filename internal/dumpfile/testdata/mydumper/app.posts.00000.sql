@@ -0,0 +1 @@
+INSERT INTO `posts` VALUES (1,NULL);
@@ -0,0 +1,4 @@
+/*!40101 SET NAMES binary*/;
+DROP TABLE IF EXISTS `recent_posts`;
+DROP VIEW IF EXISTS `recent_posts`;
+CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `recent_posts` AS select `posts`.`id` AS `id` from `posts`;
@@ -0,0 +1,4 @@
+/*!40101 SET NAMES binary*/;
+CREATE TABLE `recent_posts`(
+`id` int
+)ENGINE=MEMORY;
@@ -0,0 +1,102 @@
+-- MySQL dump 10.13  Distrib 8.0.35, for Linux (x86_64)
+--
+-- Host: 127.0.0.1    Database: 
+-- ------------------------------------------------------
+-- Server version	8.0.35
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
+/*!50503 SET NAMES utf8mb4 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+
+--
+-- Current Database: `app`
+--
+
+CREATE DATABASE /*!32312 IF NOT EXISTS*/ `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */ /*!80016 DEFAULT ENCRYPTION='N' */;
+
+USE `app`;
+
+--
+-- Table structure for table `users`
+--
+
+DROP TABLE IF EXISTS `users`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `users` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(30) NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Temporary view structure for view `active_users`
+--
+
+DROP TABLE IF EXISTS `active_users`;
+/*!50001 DROP VIEW IF EXISTS `active_users`*/;
+SET @saved_cs_client     = @@character_set_client;
+/*!50503 SET character_set_client = utf8mb4 */;
+/*!50001 CREATE VIEW `active_users` AS SELECT 
+ 1 AS `id`*/;
+SET character_set_client = @saved_cs_client;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES' */ ;
+DELIMITER ;;
+/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `users_bi` BEFORE INSERT ON `users` FOR EACH ROW SET NEW.name = TRIM(NEW.name) */;;
+DELIMITER ;
+
+--
+-- Dumping routines for database 'app'
+--
+/*!50003 DROP FUNCTION IF EXISTS `add_one` */;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY' */ ;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` FUNCTION `add_one`(x int) RETURNS int
+    DETERMINISTIC
+RETURN x + 1 ;;
+DELIMITER ;
+/*!50003 DROP PROCEDURE IF EXISTS `list_users` */;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `list_users`()
+BEGIN
+  SELECT * FROM users;
+  SELECT 1;
+END ;;
+DELIMITER ;
+
+--
+-- Current Database: `other`
+--
+
+CREATE DATABASE /*!32312 IF NOT EXISTS*/ `other` /*!40100 DEFAULT CHARACTER SET latin1 */;
+
+USE `other`;
+
+--
+-- Table structure for table `widgets`
+--
+
+DROP TABLE IF EXISTS `widgets`;
+CREATE TABLE `widgets` (
+  `id` int NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+--
+-- Final view structure for view `active_users`
+--
+
+USE `app`;
+/*!50001 DROP VIEW IF EXISTS `active_users`*/;
+/*!50001 CREATE ALGORITHM=UNDEFINED */
+/*!50013 DEFINER=`root`@`%` SQL SECURITY DEFINER */
+/*!50001 VIEW `active_users` AS select `users`.`id` AS `id` from `users` */;
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+
+-- Dump completed on 2023-11-01 12:00:00
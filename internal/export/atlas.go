@@ -0,0 +1,301 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to and from the HCL schema format used by
+// Atlas. See https://atlasgo.io/atlas-schema/hcl for the format's
+// documentation.
+
+func init() {
+	Register(&Format{
+		Name:        "atlas",
+		Description: "Atlas HCL schema",
+		Export:      ExportAtlas,
+		Import:      ImportAtlas,
+	})
+}
+
+// hclAttr is a single attribute of an HCL block, with its value already
+// formatted as an HCL expression.
+type hclAttr struct {
+	key string
+	val string
+}
+
+// writeHCLAttrs writes attrs at the supplied indentation, aligning their equals
+// signs in the same manner as `atlas schema fmt`.
+func writeHCLAttrs(w io.Writer, indent string, attrs []hclAttr) {
+	var width int
+	for _, attr := range attrs {
+		if len(attr.key) > width {
+			width = len(attr.key)
+		}
+	}
+	for _, attr := range attrs {
+		fmt.Fprintf(w, "%s%-*s = %s\n", indent, width, attr.key, attr.val)
+	}
+}
+
+// ExportAtlas writes schemas to w in Atlas HCL format.
+func ExportAtlas(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for _, schema := range schemas {
+		var attrs []hclAttr
+		if schema.CharSet != "" {
+			attrs = append(attrs, hclAttr{"charset", hclString(schema.CharSet)})
+		}
+		if schema.Collation != "" {
+			attrs = append(attrs, hclAttr{"collate", hclString(schema.Collation)})
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(bw, "schema %s {}\n\n", hclString(schema.Name))
+		} else {
+			fmt.Fprintf(bw, "schema %s {\n", hclString(schema.Name))
+			writeHCLAttrs(bw, "  ", attrs)
+			bw.WriteString("}\n\n")
+		}
+
+		for _, table := range sortedTables(schema) {
+			writeAtlasTable(bw, schema.Name, table)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeAtlasTable(w io.Writer, schemaName string, table *tengo.Table) {
+	fmt.Fprintf(w, "table %s {\n", hclString(table.Name))
+	attrs := []hclAttr{{"schema", hclRef("schema", schemaName)}}
+	if table.Comment != "" {
+		attrs = append(attrs, hclAttr{"comment", hclString(table.Comment)})
+	}
+	if table.CharSet != "" {
+		attrs = append(attrs, hclAttr{"charset", hclString(table.CharSet)})
+	}
+	if table.Collation != "" {
+		attrs = append(attrs, hclAttr{"collate", hclString(table.Collation)})
+	}
+	if table.Engine != "" {
+		attrs = append(attrs, hclAttr{"engine", table.Engine})
+	}
+	writeHCLAttrs(w, "  ", attrs)
+
+	for _, col := range table.Columns {
+		fmt.Fprintf(w, "  column %s {\n", hclString(col.Name))
+		colType, unsigned := atlasType(col.TypeInDB)
+		attrs := []hclAttr{
+			{"null", strconv.FormatBool(col.Nullable)},
+			{"type", colType},
+		}
+		if unsigned {
+			attrs = append(attrs, hclAttr{"unsigned", "true"})
+		}
+		if col.Default != "" && col.Default != "NULL" {
+			attrs = append(attrs, hclAttr{"default", atlasDefault(col.Default)})
+		}
+		if col.AutoIncrement {
+			attrs = append(attrs, hclAttr{"auto_increment", "true"})
+		}
+		if col.OnUpdate != "" {
+			attrs = append(attrs, hclAttr{"on_update", "sql(" + hclString(col.OnUpdate) + ")"})
+		}
+		if col.Comment != "" {
+			attrs = append(attrs, hclAttr{"comment", hclString(col.Comment)})
+		}
+		if col.Collation != "" && col.Collation != table.Collation {
+			attrs = append(attrs, hclAttr{"charset", hclString(col.CharSet)}, hclAttr{"collate", hclString(col.Collation)})
+		}
+		writeHCLAttrs(w, "    ", attrs)
+		if col.GenerationExpr != "" {
+			genType := "STORED"
+			if col.Virtual {
+				genType = "VIRTUAL"
+			}
+			io.WriteString(w, "    as {\n")
+			writeHCLAttrs(w, "      ", []hclAttr{{"expr", hclString(col.GenerationExpr)}, {"type", genType}})
+			io.WriteString(w, "    }\n")
+		}
+		io.WriteString(w, "  }\n")
+	}
+
+	if table.PrimaryKey != nil {
+		io.WriteString(w, "  primary_key {\n")
+		writeAtlasIndexParts(w, table.PrimaryKey, nil)
+		io.WriteString(w, "  }\n")
+	}
+	for _, idx := range table.SecondaryIndexes {
+		fmt.Fprintf(w, "  index %s {\n", hclString(idx.Name))
+		var attrs []hclAttr
+		if idx.Unique {
+			attrs = append(attrs, hclAttr{"unique", "true"})
+		}
+		if idx.Type != "" && idx.Type != "BTREE" {
+			attrs = append(attrs, hclAttr{"type", idx.Type})
+		}
+		if idx.Comment != "" {
+			attrs = append(attrs, hclAttr{"comment", hclString(idx.Comment)})
+		}
+		writeAtlasIndexParts(w, idx, attrs)
+		io.WriteString(w, "  }\n")
+	}
+	for _, fk := range table.ForeignKeys {
+		refTable := hclRef("table", fk.ReferencedTableName)
+		if fk.ReferencedSchemaName != "" {
+			refTable = hclRef("table", fk.ReferencedSchemaName, fk.ReferencedTableName)
+		}
+		cols := make([]string, len(fk.ColumnNames))
+		refCols := make([]string, len(fk.ReferencedColumnNames))
+		for n := range fk.ColumnNames {
+			cols[n] = hclRef("column", fk.ColumnNames[n])
+			refCols[n] = hclRef(refTable, "column", fk.ReferencedColumnNames[n])
+		}
+		fmt.Fprintf(w, "  foreign_key %s {\n", hclString(fk.Name))
+		writeHCLAttrs(w, "    ", []hclAttr{
+			{"columns", "[" + strings.Join(cols, ", ") + "]"},
+			{"ref_columns", "[" + strings.Join(refCols, ", ") + "]"},
+			{"on_update", strings.ReplaceAll(fk.UpdateRule, " ", "_")},
+			{"on_delete", strings.ReplaceAll(fk.DeleteRule, " ", "_")},
+		})
+		io.WriteString(w, "  }\n")
+	}
+	for _, cc := range table.Checks {
+		fmt.Fprintf(w, "  check %s {\n", hclString(cc.Name))
+		attrs := []hclAttr{{"expr", hclString(cc.Clause)}}
+		if !cc.Enforced {
+			attrs = append(attrs, hclAttr{"enforced", "false"})
+		}
+		writeHCLAttrs(w, "    ", attrs)
+		io.WriteString(w, "  }\n")
+	}
+	io.WriteString(w, "}\n\n")
+}
+
+// writeAtlasIndexParts writes the body of an index or primary_key block. Simple
+// column lists use a columns attribute; prefixes, expressions, and descending
+// parts require an on block per part.
+func writeAtlasIndexParts(w io.Writer, idx *tengo.Index, attrs []hclAttr) {
+	simple := true
+	cols := make([]string, len(idx.Parts))
+	for n, part := range idx.Parts {
+		if part.ColumnName == "" || part.PrefixLength > 0 || part.Descending {
+			simple = false
+		}
+		cols[n] = hclRef("column", part.ColumnName)
+	}
+	if simple {
+		attrs = append(attrs, hclAttr{"columns", "[" + strings.Join(cols, ", ") + "]"})
+	}
+	writeHCLAttrs(w, "    ", attrs)
+	if simple {
+		return
+	}
+	for n, part := range idx.Parts {
+		var partAttrs []hclAttr
+		if part.Expression != "" {
+			partAttrs = append(partAttrs, hclAttr{"expr", hclString(part.Expression)})
+		} else {
+			partAttrs = append(partAttrs, hclAttr{"column", cols[n]})
+		}
+		if part.PrefixLength > 0 {
+			partAttrs = append(partAttrs, hclAttr{"prefix", strconv.Itoa(int(part.PrefixLength))})
+		}
+		if part.Descending {
+			partAttrs = append(partAttrs, hclAttr{"desc", "true"})
+		}
+		io.WriteString(w, "    on {\n")
+		writeHCLAttrs(w, "      ", partAttrs)
+		io.WriteString(w, "    }\n")
+	}
+}
+
+var (
+	hclBareIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	atlasSimpleType   = regexp.MustCompile(`^([a-z]+)(\((.*)\))?$`)
+)
+
+// hclRef returns a reference traversal such as column.name. Names which are
+// not valid HCL identifiers use index syntax, for example column["my col"].
+func hclRef(root string, names ...string) string {
+	var b strings.Builder
+	b.WriteString(root)
+	for _, name := range names {
+		if hclBareIdentifier.MatchString(name) {
+			b.WriteString("." + name)
+		} else {
+			b.WriteString("[" + hclString(name) + "]")
+		}
+	}
+	return b.String()
+}
+
+// hclString returns s as a double-quoted HCL string literal. Template sequences
+// are escaped so that they are not interpolated.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for n := 0; n < len(s); n++ {
+		switch c := s[n]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteByte(c)
+			if n+1 < len(s) && s[n+1] == '{' {
+				b.WriteByte(c)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// atlasType converts a column type into an Atlas type expression, along with
+// whether the type is unsigned. Types which cannot be expressed natively are
+// wrapped in sql().
+func atlasType(colType string) (string, bool) {
+	unsigned := strings.HasSuffix(colType, " unsigned")
+	colType = strings.TrimSuffix(colType, " unsigned")
+	matches := atlasSimpleType.FindStringSubmatch(colType)
+	switch {
+	case matches == nil:
+		return "sql(" + hclString(colType) + ")", unsigned
+	case matches[1] == "enum" || matches[1] == "set":
+		values := EnumValues(colType)
+		quoted := make([]string, len(values))
+		for n, val := range values {
+			quoted[n] = hclString(val)
+		}
+		return matches[1] + "(" + strings.Join(quoted, ", ") + ")", unsigned
+	case matches[2] != "" && strings.ContainsAny(matches[3], "'\""):
+		return "sql(" + hclString(colType) + ")", unsigned
+	}
+	return colType, unsigned
+}
+
+// atlasDefault converts a column default, which is stored as a SQL expression,
+// into an Atlas default value.
+func atlasDefault(def string) string {
+	if len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		return hclString(strings.ReplaceAll(def[1:len(def)-1], "''", "'"))
+	}
+	if _, err := strconv.ParseFloat(def, 64); err == nil {
+		return def
+	}
+	return "sql(" + hclString(def) + ")"
+}
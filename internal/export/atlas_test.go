@@ -0,0 +1,190 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportAtlas(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("atlas").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expectLines := []string{
+		"schema \"app\" {}\n",
+		"table \"users\" {\n  schema  = schema.app\n  comment = \"registered users\"\n",
+		"  column \"id\" {\n    null           = false\n    type           = int\n    unsigned       = true\n    auto_increment = true\n  }\n",
+		"    type    = enum(\"active\", \"banned\")\n    default = \"active\"\n",
+		"    default = sql(\"CURRENT_TIMESTAMP\")\n",
+		"  primary_key {\n    columns = [column.user_id, column.seq]\n  }\n",
+		"  index \"email\" {\n    unique  = true\n    columns = [column.email]\n  }\n",
+		"    ref_columns = [table.users.column.id]\n    on_update   = NO_ACTION\n    on_delete   = CASCADE\n",
+	}
+	actual := buf.String()
+	for _, line := range expectLines {
+		if !strings.Contains(actual, line) {
+			t.Errorf("Expected Atlas output to contain %q, but it did not. Full output:\n%s", line, actual)
+		}
+	}
+
+	// Round-trip back to tables
+	tables, err := ImportAtlas(&buf, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error from import: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, instead found %d", len(tables))
+	}
+	for _, table := range tables {
+		orig := schema.Table(table.Name)
+		if orig == nil || orig.CreateStatement != table.CreateStatement {
+			t.Errorf("Round-trip mismatch for table %s:\n%s\nExpected:\n%s", table.Name, table.CreateStatement, orig.CreateStatement)
+		}
+	}
+}
+
+func TestImportAtlas(t *testing.T) {
+	input := `
+# Generated by hand
+schema "core" {
+  charset = "latin1"
+}
+
+table "authors" {
+  schema = schema.core
+  collate = "latin1_swedish_ci"
+  comment = "people"
+  column "id" {
+    type           = bigint
+    auto_increment = true
+  }
+  column "name" {
+    null = true
+    type = varchar(200)
+    default = null
+    charset = "utf8mb4"
+  }
+  column "active" {
+    type    = bool
+    default = true
+  }
+  column "bio" {
+    null = true
+    type = sql("mediumtext")
+    comment = <<-EOT
+      Free-form
+      biography
+    EOT
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "name_bio" {
+    on {
+      column = column.name
+      prefix = 10
+    }
+    on {
+      column = column["bio"]
+      prefix = 20
+    }
+  }
+  index "ft" {
+    type    = FULLTEXT
+    columns = [column.bio]
+    comment = "search"
+  }
+}
+
+table "books" {
+  schema = schema.core
+  collate = "latin1_swedish_ci"
+  column "id" {
+    type = int
+  }
+  column "author_id" {
+    type = bigint
+  }
+  column "sequel_of" {
+    null = true
+    type = int
+  }
+  column "price" {
+    type    = decimal(8, 2)
+    unsigned = true
+    default = 0.99
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "book_author" {
+    columns     = [column.author_id]
+    ref_columns = [table.authors.column.id]
+    on_delete   = SET_NULL
+  }
+  foreign_key "book_sequel" {
+    columns     = [column.sequel_of]
+    ref_columns = [column.id]
+  }
+  check "price_positive" {
+    expr = "(price > 0)"
+  }
+}
+
+view "ignored" {
+  schema = schema.core
+  as     = "SELECT 1"
+}
+`
+	tables, err := ImportAtlas(strings.NewReader(input), Options{Flavor: tengo.FlavorMySQL80.Dot(30)})
+	if err != nil {
+		t.Fatalf("Unexpected error from import: %v", err)
+	}
+	if len(tables) != 2 || tables[0].Name != "authors" || tables[1].Name != "books" {
+		t.Fatalf("Unexpected tables: %+v", tables)
+	}
+	expected := "CREATE TABLE `authors` (\n" +
+		"  `id` bigint NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(200) CHARACTER SET utf8mb4 DEFAULT NULL,\n" +
+		"  `active` tinyint(1) NOT NULL DEFAULT 1,\n" +
+		"  `bio` mediumtext COMMENT 'Free-form\\nbiography',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `name_bio` (`name`(10),`bio`(20)),\n" +
+		"  FULLTEXT KEY `ft` (`bio`) COMMENT 'search'\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1 COMMENT='people'"
+	if tables[0].CreateStatement != expected {
+		t.Errorf("Unexpected CREATE for authors:\n%s\nExpected:\n%s", tables[0].CreateStatement, expected)
+	}
+	expected = "CREATE TABLE `books` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `author_id` bigint NOT NULL,\n" +
+		"  `sequel_of` int DEFAULT NULL,\n" +
+		"  `price` decimal(8,2) unsigned NOT NULL DEFAULT 0.99,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  CONSTRAINT `book_author` FOREIGN KEY (`author_id`) REFERENCES `authors` (`id`) ON DELETE SET NULL,\n" +
+		"  CONSTRAINT `book_sequel` FOREIGN KEY (`sequel_of`) REFERENCES `books` (`id`),\n" +
+		"  CONSTRAINT `price_positive` CHECK ((price > 0))\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1"
+	if tables[1].CreateStatement != expected {
+		t.Errorf("Unexpected CREATE for books:\n%s\nExpected:\n%s", tables[1].CreateStatement, expected)
+	}
+
+	badInputs := []string{
+		`table "t" { column "c" { null = true } }`,
+		`table "t" { column "c" { type = int`,
+		`table "t" { column "c" { type = int } column "c2" { type = int } primary_key { columns = ["c"] } }`,
+		`table "t" { column "c" { type = varchar("${x}") } }`,
+		`table "t" { column "c" { type = int } } table "t" { column "c" { type = int } }`,
+		`table "t" { column "c" { type = int } foreign_key "fk" { columns = [column.c] } }`,
+	}
+	for _, input := range badInputs {
+		if _, err := ImportAtlas(strings.NewReader(input), Options{Flavor: tengo.FlavorMySQL80}); err == nil {
+			t.Errorf("Expected error from input %q, but err was nil", input)
+		}
+	}
+}
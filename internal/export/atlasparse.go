@@ -0,0 +1,624 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file contains a minimal HCL parser, supporting the subset of the
+// language used by Atlas schema files: blocks, attributes, string and numeric
+// literals, lists, function calls, and reference traversals. Template
+// interpolation and arithmetic are not supported.
+
+type hclTokenType int
+
+const (
+	hclTokenIdent hclTokenType = iota
+	hclTokenString
+	hclTokenNumber
+	hclTokenSymbol
+)
+
+type hclToken struct {
+	typ  hclTokenType
+	val  string
+	line int
+}
+
+func (tok hclToken) is(symbol string) bool {
+	return tok.typ == hclTokenSymbol && tok.val == symbol
+}
+
+// lexHCL splits src into tokens, discarding whitespace and comments.
+func lexHCL(src string) ([]hclToken, error) {
+	var tokens []hclToken
+	line := 1
+	for pos := 0; pos < len(src); {
+		c := src[pos]
+		start := pos
+		switch {
+		case c == '\n':
+			line++
+			pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			pos++
+		case c == '#' || strings.HasPrefix(src[pos:], "//"):
+			for pos < len(src) && src[pos] != '\n' {
+				pos++
+			}
+		case strings.HasPrefix(src[pos:], "/*"):
+			endPos := strings.Index(src[pos+2:], "*/")
+			if endPos < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[pos:pos+2+endPos], "\n")
+			pos += endPos + 4
+		case strings.HasPrefix(src[pos:], "<<"):
+			val, length, err := lexHCLHeredoc(src[pos:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			tokens = append(tokens, hclToken{typ: hclTokenString, val: val, line: line})
+			line += strings.Count(src[pos:pos+length], "\n")
+			pos += length
+		case c == '"':
+			var b strings.Builder
+			for pos++; pos < len(src) && src[pos] != '"'; pos++ {
+				switch {
+				case src[pos] == '\n':
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				case src[pos] == '\\' && pos+1 < len(src):
+					pos++
+					switch src[pos] {
+					case 'n':
+						b.WriteByte('\n')
+					case 'r':
+						b.WriteByte('\r')
+					case 't':
+						b.WriteByte('\t')
+					case 'u':
+						if pos+4 >= len(src) {
+							return nil, fmt.Errorf("line %d: invalid unicode escape", line)
+						}
+						r, err := strconv.ParseUint(src[pos+1:pos+5], 16, 32)
+						if err != nil {
+							return nil, fmt.Errorf("line %d: invalid unicode escape", line)
+						}
+						b.WriteRune(rune(r))
+						pos += 4
+					default:
+						b.WriteByte(src[pos])
+					}
+				case (src[pos] == '$' || src[pos] == '%') && strings.HasPrefix(src[pos+1:], string(src[pos])+"{"):
+					b.WriteByte(src[pos]) // escaped template sequence such as $${
+					pos++
+				case (src[pos] == '$' || src[pos] == '%') && pos+1 < len(src) && src[pos+1] == '{':
+					return nil, fmt.Errorf("line %d: template sequences are not supported", line)
+				default:
+					b.WriteByte(src[pos])
+				}
+			}
+			if pos >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			pos++
+			tokens = append(tokens, hclToken{typ: hclTokenString, val: b.String(), line: line})
+		case c == '_' || unicode.IsLetter(rune(c)):
+			for pos < len(src) && (src[pos] == '_' || src[pos] == '-' || unicode.IsLetter(rune(src[pos])) || unicode.IsDigit(rune(src[pos]))) {
+				pos++
+			}
+			tokens = append(tokens, hclToken{typ: hclTokenIdent, val: src[start:pos], line: line})
+		case unicode.IsDigit(rune(c)) || (c == '-' && pos+1 < len(src) && unicode.IsDigit(rune(src[pos+1]))):
+			pos++
+			for pos < len(src) && (unicode.IsDigit(rune(src[pos])) || src[pos] == '.' || src[pos] == 'e' || src[pos] == 'E') {
+				pos++
+			}
+			tokens = append(tokens, hclToken{typ: hclTokenNumber, val: src[start:pos], line: line})
+		case strings.ContainsRune("{}[](),.=", rune(c)):
+			pos++
+			tokens = append(tokens, hclToken{typ: hclTokenSymbol, val: string(c), line: line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+	return tokens, nil
+}
+
+// lexHCLHeredoc parses a heredoc string at the start of src, returning its
+// value and the length of src consumed. The indented <<- form has its common
+// leading whitespace removed.
+func lexHCLHeredoc(src string) (string, int, error) {
+	header, _, ok := strings.Cut(src, "\n")
+	if !ok {
+		return "", 0, fmt.Errorf("unterminated heredoc")
+	}
+	indented := strings.HasPrefix(header, "<<-")
+	marker := strings.TrimSpace(strings.TrimLeft(header, "<-"))
+	if marker == "" {
+		return "", 0, fmt.Errorf("heredoc missing delimiter")
+	}
+	pos := len(header) + 1
+	var lines []string
+	for pos < len(src) {
+		line, _, _ := strings.Cut(src[pos:], "\n")
+		pos += len(line) + 1
+		if strings.TrimSpace(line) == marker {
+			val := strings.Join(lines, "\n")
+			if indented {
+				val = dedent(val)
+			}
+			if pos > len(src) {
+				pos = len(src)
+			}
+			return val, pos, nil
+		}
+		lines = append(lines, line)
+	}
+	return "", 0, fmt.Errorf("unterminated heredoc %s", marker)
+}
+
+type hclValueKind int
+
+const (
+	hclValueString hclValueKind = iota
+	hclValueNumber
+	hclValueBool
+	hclValueNull
+	hclValueRef  // reference traversal, such as column.id
+	hclValueCall // function call, such as varchar(255)
+	hclValueList
+)
+
+// hclValue is a parsed HCL expression.
+type hclValue struct {
+	kind  hclValueKind
+	text  string     // literal value, function name, or root of a reference
+	parts []string   // for references, each traversal step including the root
+	items []hclValue // for lists, the elements; for calls, the arguments
+	line  int
+}
+
+// hclBlock is a parsed HCL block, or the top-level body of a document.
+type hclBlock struct {
+	typ    string
+	labels []string
+	attrs  map[string]hclValue
+	blocks []*hclBlock
+	line   int
+}
+
+type hclParser struct {
+	tokens []hclToken
+	pos    int
+}
+
+func (p *hclParser) peek() hclToken {
+	if p.pos >= len(p.tokens) {
+		return hclToken{typ: hclTokenSymbol, val: "EOF", line: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *hclParser) next() hclToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// parseHCL parses an HCL document into a block representing its top-level
+// body.
+func parseHCL(src string) (*hclBlock, error) {
+	tokens, err := lexHCL(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &hclParser{tokens: tokens}
+	root := &hclBlock{attrs: make(map[string]hclValue)}
+	if err := p.body(root, true); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// body parses attributes and nested blocks into block, through the closing
+// brace, or through the end of the document if topLevel is true.
+func (p *hclParser) body(block *hclBlock, topLevel bool) error {
+	for {
+		tok := p.next()
+		switch {
+		case tok.line < 0 && topLevel:
+			return nil
+		case tok.line < 0:
+			return fmt.Errorf("line %d: unterminated %s block", block.line, block.typ)
+		case tok.is("}") && !topLevel:
+			return nil
+		case tok.typ != hclTokenIdent:
+			return fmt.Errorf("line %d: unexpected %q", tok.line, tok.val)
+		case p.peek().is("="):
+			p.pos++
+			if _, already := block.attrs[tok.val]; already {
+				return fmt.Errorf("line %d: attribute %s defined more than once", tok.line, tok.val)
+			}
+			val, err := p.expr()
+			if err != nil {
+				return err
+			}
+			block.attrs[tok.val] = val
+		default:
+			child := &hclBlock{typ: tok.val, attrs: make(map[string]hclValue), line: tok.line}
+			for p.peek().typ == hclTokenString || p.peek().typ == hclTokenIdent {
+				child.labels = append(child.labels, p.next().val)
+			}
+			if next := p.next(); !next.is("{") {
+				return fmt.Errorf("line %d: expected \"{\", found %q", next.line, next.val)
+			}
+			if err := p.body(child, false); err != nil {
+				return err
+			}
+			block.blocks = append(block.blocks, child)
+		}
+	}
+}
+
+// expr parses a single expression.
+func (p *hclParser) expr() (hclValue, error) {
+	tok := p.next()
+	val := hclValue{text: tok.val, line: tok.line}
+	switch {
+	case tok.typ == hclTokenString:
+		val.kind = hclValueString
+	case tok.typ == hclTokenNumber:
+		val.kind = hclValueNumber
+	case tok.is("["), tok.typ == hclTokenIdent && p.peek().is("("):
+		closer := "]"
+		val.kind = hclValueList
+		if !tok.is("[") {
+			p.pos++
+			closer = ")"
+			val.kind = hclValueCall
+		}
+		for !p.peek().is(closer) {
+			item, err := p.expr()
+			if err != nil {
+				return val, err
+			}
+			val.items = append(val.items, item)
+			if p.peek().is(",") {
+				p.pos++
+			} else if !p.peek().is(closer) {
+				return val, fmt.Errorf("line %d: expected %q, found %q", p.peek().line, closer, p.peek().val)
+			}
+		}
+		p.pos++
+	case tok.typ == hclTokenIdent && (tok.val == "true" || tok.val == "false"):
+		val.kind = hclValueBool
+	case tok.typ == hclTokenIdent && tok.val == "null":
+		val.kind = hclValueNull
+	case tok.typ == hclTokenIdent:
+		val.kind = hclValueRef
+		val.parts = []string{tok.val}
+		for {
+			if p.peek().is(".") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].typ == hclTokenIdent {
+				val.parts = append(val.parts, p.tokens[p.pos+1].val)
+				p.pos += 2
+			} else if p.peek().is("[") && p.pos+2 < len(p.tokens) && p.tokens[p.pos+1].typ == hclTokenString && p.tokens[p.pos+2].is("]") {
+				val.parts = append(val.parts, p.tokens[p.pos+1].val)
+				p.pos += 3
+			} else {
+				break
+			}
+		}
+	default:
+		return val, fmt.Errorf("line %d: unexpected %q", tok.line, tok.val)
+	}
+	return val, nil
+}
+
+// str returns the value as a string if it is a literal or a single-part
+// reference, such as the bare identifier in engine = InnoDB.
+func (v hclValue) str() string {
+	if v.kind == hclValueRef {
+		return strings.Join(v.parts, ".")
+	}
+	return v.text
+}
+
+// ImportAtlas parses an Atlas HCL schema document, returning the tables it
+// defines. Schema blocks and any other block types besides tables are ignored.
+func ImportAtlas(r io.Reader, opts Options) ([]*tengo.Table, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseHCL(string(src))
+	if err != nil {
+		return nil, err
+	}
+	var tables []*tengo.Table
+	seen := make(map[string]bool)
+	for _, block := range root.blocks {
+		if block.typ != "table" {
+			continue
+		}
+		if len(block.labels) == 0 {
+			return nil, fmt.Errorf("line %d: table block has no name", block.line)
+		}
+		name := block.labels[len(block.labels)-1]
+		if seen[name] {
+			return nil, fmt.Errorf("line %d: table %s defined more than once", block.line, name)
+		}
+		seen[name] = true
+		table, err := atlasTable(name, block, opts.Flavor)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// atlasTable converts a table block into a table.
+func atlasTable(name string, block *hclBlock, flavor tengo.Flavor) (*tengo.Table, error) {
+	b := tengo.NewTableBuilder(name)
+	if val, ok := block.attrs["comment"]; ok {
+		b.Comment(val.str())
+	}
+	if val, ok := block.attrs["engine"]; ok {
+		b.Engine(val.str())
+	}
+	if charSet, collation := atlasCharSet(block); charSet != "" {
+		b.CharSet(charSet, collation)
+	}
+
+	// Index types and comments can't be expressed via TableBuilder, so they're
+	// applied after building
+	indexTypes := make(map[string]string)
+	indexComments := make(map[string]string)
+
+	for _, child := range block.blocks {
+		var childName string
+		if len(child.labels) > 0 {
+			childName = child.labels[0]
+		}
+		switch child.typ {
+		case "column":
+			if childName == "" {
+				return nil, fmt.Errorf("line %d: column block has no name", child.line)
+			}
+			colType, colOpts, err := atlasColumn(child)
+			if err != nil {
+				return nil, err
+			}
+			b.Column(childName, colType, colOpts...)
+		case "primary_key":
+			cols, err := atlasIndexColumns(child)
+			if err != nil {
+				return nil, err
+			}
+			b.PrimaryKey(cols...)
+		case "index":
+			if childName == "" {
+				return nil, fmt.Errorf("line %d: index block has no name", child.line)
+			}
+			cols, err := atlasIndexColumns(child)
+			if err != nil {
+				return nil, err
+			}
+			if val := child.attrs["unique"]; val.kind == hclValueBool && val.text == "true" {
+				b.UniqueIndex(childName, cols...)
+			} else {
+				b.Index(childName, cols...)
+			}
+			if val, ok := child.attrs["type"]; ok {
+				indexTypes[childName] = strings.ToUpper(val.str())
+			}
+			if val, ok := child.attrs["comment"]; ok {
+				indexComments[childName] = val.str()
+			}
+		case "foreign_key":
+			if err := atlasForeignKey(b, name, childName, child); err != nil {
+				return nil, err
+			}
+		case "check":
+			if val, ok := child.attrs["expr"]; ok {
+				b.Check(childName, val.str())
+			}
+		}
+	}
+
+	table, err := b.Build(flavor)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexTypes) > 0 || len(indexComments) > 0 {
+		for _, idx := range table.SecondaryIndexes {
+			if typ, ok := indexTypes[idx.Name]; ok {
+				idx.Type = typ
+			}
+			idx.Comment = indexComments[idx.Name]
+		}
+		table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	}
+	return table, nil
+}
+
+// atlasCharSet returns the character set and collation attributes of a table
+// or column block. If only a collation is specified, the character set is
+// derived from it.
+func atlasCharSet(block *hclBlock) (charSet, collation string) {
+	charSet = block.attrs["charset"].str()
+	collation = block.attrs["collate"].str()
+	if charSet == "" && collation != "" {
+		charSet, _, _ = strings.Cut(collation, "_")
+	}
+	return charSet, collation
+}
+
+// atlasColumn converts a column block into a column type and options.
+func atlasColumn(block *hclBlock) (string, []tengo.ColumnOption, error) {
+	typeVal, ok := block.attrs["type"]
+	if !ok {
+		return "", nil, fmt.Errorf("line %d: column %s has no type", block.line, block.labels[0])
+	}
+	colType, err := atlasColumnType(typeVal)
+	if err != nil {
+		return "", nil, err
+	}
+	if val := block.attrs["unsigned"]; val.kind == hclValueBool && val.text == "true" {
+		colType += " unsigned"
+	}
+	var opts []tengo.ColumnOption
+
+	// In Atlas, columns are NOT NULL unless specified otherwise
+	if val := block.attrs["null"]; val.kind != hclValueBool || val.text != "true" {
+		opts = append(opts, tengo.NotNull())
+	}
+	if val := block.attrs["auto_increment"]; val.kind == hclValueBool && val.text == "true" {
+		opts = append(opts, tengo.AutoIncrement())
+	}
+	if val, ok := block.attrs["default"]; ok {
+		switch {
+		case val.kind == hclValueString:
+			opts = append(opts, tengo.DefaultString(val.text))
+		case val.kind == hclValueNumber:
+			opts = append(opts, tengo.DefaultExpr(val.text))
+		case val.kind == hclValueBool && val.text == "true":
+			opts = append(opts, tengo.DefaultExpr("1"))
+		case val.kind == hclValueBool:
+			opts = append(opts, tengo.DefaultExpr("0"))
+		case val.kind == hclValueNull:
+			opts = append(opts, tengo.DefaultExpr("NULL"))
+		case val.kind == hclValueCall && val.text == "sql" && len(val.items) == 1:
+			opts = append(opts, tengo.DefaultExpr(val.items[0].text))
+		default:
+			return "", nil, fmt.Errorf("line %d: unsupported default value for column %s", val.line, block.labels[0])
+		}
+	}
+	if val, ok := block.attrs["on_update"]; ok {
+		if val.kind == hclValueCall && len(val.items) == 1 {
+			val = val.items[0]
+		}
+		opts = append(opts, tengo.OnUpdate(val.str()))
+	}
+	if val, ok := block.attrs["comment"]; ok {
+		opts = append(opts, tengo.ColumnComment(val.str()))
+	}
+	if charSet, collation := atlasCharSet(block); charSet != "" {
+		opts = append(opts, tengo.ColumnCharSet(charSet, collation))
+	}
+	if val, ok := block.attrs["as"]; ok {
+		opts = append(opts, tengo.Generated(val.str(), true))
+	}
+	for _, child := range block.blocks {
+		if child.typ == "as" {
+			virtual := !strings.EqualFold(child.attrs["type"].str(), "STORED")
+			opts = append(opts, tengo.Generated(child.attrs["expr"].str(), virtual))
+		}
+	}
+	return colType, opts, nil
+}
+
+// atlasColumnType converts an Atlas type expression into a column type.
+func atlasColumnType(val hclValue) (string, error) {
+	switch val.kind {
+	case hclValueString:
+		return val.text, nil
+	case hclValueRef:
+		switch name := val.str(); name {
+		case "bool", "boolean":
+			return "tinyint(1)", nil
+		default:
+			return name, nil
+		}
+	case hclValueCall:
+		if val.text == "sql" && len(val.items) == 1 {
+			return val.items[0].text, nil
+		}
+		args := make([]string, len(val.items))
+		for n, arg := range val.items {
+			switch arg.kind {
+			case hclValueString:
+				args[n] = "'" + strings.ReplaceAll(arg.text, "'", "''") + "'"
+			case hclValueNumber:
+				args[n] = arg.text
+			default:
+				return "", fmt.Errorf("line %d: unsupported argument to type %s", arg.line, val.text)
+			}
+		}
+		return val.text + "(" + strings.Join(args, ",") + ")", nil
+	}
+	return "", fmt.Errorf("line %d: unsupported column type expression", val.line)
+}
+
+// atlasIndexColumns returns the column names of an index or primary_key block,
+// from either its columns attribute or its on blocks. Prefix lengths are
+// returned using TableBuilder's parenthesized suffix syntax.
+func atlasIndexColumns(block *hclBlock) ([]string, error) {
+	var cols []string
+	if val, ok := block.attrs["columns"]; ok {
+		for _, item := range val.items {
+			if item.kind != hclValueRef {
+				return nil, fmt.Errorf("line %d: index columns must be column references", item.line)
+			}
+			cols = append(cols, item.parts[len(item.parts)-1])
+		}
+	}
+	for _, part := range block.blocks {
+		if part.typ != "on" {
+			continue
+		}
+		val, ok := part.attrs["column"]
+		if !ok || val.kind != hclValueRef {
+			return nil, fmt.Errorf("line %d: only column index parts are supported", part.line)
+		}
+		col := val.parts[len(val.parts)-1]
+		if prefix, ok := part.attrs["prefix"]; ok {
+			col += "(" + prefix.text + ")"
+		}
+		cols = append(cols, col)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("line %d: %s has no columns", block.line, block.typ)
+	}
+	return cols, nil
+}
+
+// atlasForeignKey adds a foreign key from a foreign_key block to b. Referenced
+// columns of the form column.x refer to the same table; table.t.column.x refers
+// to another table; and table.s.t.column.x refers to a table in another
+// schema.
+func atlasForeignKey(b *tengo.TableBuilder, tableName, fkName string, block *hclBlock) error {
+	if fkName == "" {
+		return fmt.Errorf("line %d: foreign_key block has no name", block.line)
+	}
+	var cols, refCols []string
+	for _, item := range block.attrs["columns"].items {
+		if item.kind != hclValueRef {
+			return fmt.Errorf("line %d: foreign key columns must be column references", item.line)
+		}
+		cols = append(cols, item.parts[len(item.parts)-1])
+	}
+	refTable := tableName
+	for _, item := range block.attrs["ref_columns"].items {
+		if item.kind != hclValueRef || len(item.parts) < 2 {
+			return fmt.Errorf("line %d: foreign key ref_columns must be column references", item.line)
+		}
+		parts := item.parts
+		refCols = append(refCols, parts[len(parts)-1])
+		if len(parts) >= 4 && parts[0] == "table" {
+			refTable = strings.Join(parts[1:len(parts)-2], ".")
+		}
+	}
+	if len(cols) == 0 || len(cols) != len(refCols) {
+		return fmt.Errorf("line %d: foreign key %s must have the same non-zero number of columns and ref_columns", block.line, fkName)
+	}
+	onUpdate := strings.ReplaceAll(block.attrs["on_update"].str(), "_", " ")
+	onDelete := strings.ReplaceAll(block.attrs["on_delete"].str(), "_", " ")
+	b.ForeignKey(fkName, cols, refTable, refCols, onDelete, onUpdate)
+	return nil
+}
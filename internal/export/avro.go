@@ -0,0 +1,272 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to Apache Avro schemas, matching the record
+// schemas which Debezium's MySQL connector registers for each table's change
+// events, using the connector's default type mapping modes. See
+// https://debezium.io/documentation/reference/stable/connectors/mysql.html#mysql-data-types
+// for the mapping.
+
+func init() {
+	Register(&Format{
+		Name:        "avro",
+		Description: "Avro schemas matching Debezium change events",
+		Export:      ExportAvro,
+	})
+}
+
+// avroType describes the Avro type which Debezium uses for a column.
+type avroType struct {
+	primitive   string     // Avro primitive type, or "record" for geometry types
+	connectName string     // Kafka Connect logical type name, if any
+	props       jsonObject // additional schema properties, after "type"
+	precision   int        // only used with decimal types
+	scale       int        // only used with decimal types
+}
+
+// avroGeometry is the record schema Debezium uses for all spatial types.
+var avroGeometry = jsonObject{
+	{"type", "record"},
+	{"name", "Geometry"},
+	{"namespace", "io.debezium.data.geometry"},
+	{"fields", []jsonObject{
+		{{"name", "wkb"}, {"type", "bytes"}},
+		{{"name", "srid"}, {"type", []string{"null", "int"}}, {"default", nil}},
+	}},
+	{"connect.name", "io.debezium.data.geometry.Geometry"},
+}
+
+// avroLogical returns an avroType for a Kafka Connect logical type.
+func avroLogical(primitive, connectName string, params ...string) avroType {
+	at := avroType{primitive: primitive, connectName: connectName}
+	at.props = jsonObject{{"connect.version", 1}}
+	if len(params) > 0 {
+		paramObj := jsonObject{}
+		for n := 0; n+1 < len(params); n += 2 {
+			paramObj.set(params[n], params[n+1])
+		}
+		at.props.set("connect.parameters", paramObj)
+	}
+	at.props.set("connect.name", connectName)
+	return at
+}
+
+// columnAvroType returns the Avro type Debezium uses for col, with the
+// connector's default decimal.handling.mode (precise), time.precision.mode
+// (adaptive_time_microseconds), and bigint.unsigned.handling.mode (long).
+func columnAvroType(col *tengo.Column) avroType {
+	ct := parseColumnType(col.TypeInDB)
+	switch ct.base {
+	case "bool", "boolean":
+		return avroType{primitive: "boolean"}
+	case "tinyint":
+		return avroType{primitive: "int", props: jsonObject{{"connect.type", "int16"}}}
+	case "smallint":
+		if ct.unsigned {
+			return avroType{primitive: "int"}
+		}
+		return avroType{primitive: "int", props: jsonObject{{"connect.type", "int16"}}}
+	case "mediumint":
+		return avroType{primitive: "int"}
+	case "int", "integer":
+		if ct.unsigned {
+			return avroType{primitive: "long"}
+		}
+		return avroType{primitive: "int"}
+	case "bigint":
+		return avroType{primitive: "long"}
+	case "float", "double", "real":
+		return avroType{primitive: "double"}
+	case "decimal", "numeric":
+		precision, scale := ct.arg(0, 10), ct.arg(1, 0)
+		at := avroLogical("bytes", "org.apache.kafka.connect.data.Decimal", "scale", strconv.Itoa(scale), "connect.decimal.precision", strconv.Itoa(precision))
+		at.props = append(jsonObject{{"scale", scale}, {"precision", precision}}, at.props...)
+		at.props.set("logicalType", "decimal")
+		at.precision, at.scale = precision, scale
+		return at
+	case "bit":
+		if bits := ct.arg(0, 1); bits > 1 {
+			return avroLogical("bytes", "io.debezium.data.Bits", "length", strconv.Itoa(bits))
+		}
+		return avroType{primitive: "boolean"}
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return avroType{primitive: "string"}
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return avroType{primitive: "bytes"}
+	case "enum":
+		return avroLogical("string", "io.debezium.data.Enum", "allowed", strings.Join(ct.args, ","))
+	case "set":
+		return avroLogical("string", "io.debezium.data.EnumSet", "allowed", strings.Join(ct.args, ","))
+	case "json":
+		return avroLogical("string", "io.debezium.data.Json")
+	case "date":
+		return avroLogical("int", "io.debezium.time.Date")
+	case "time":
+		return avroLogical("long", "io.debezium.time.MicroTime")
+	case "datetime":
+		if ct.arg(0, 0) > 3 {
+			return avroLogical("long", "io.debezium.time.MicroTimestamp")
+		}
+		return avroLogical("long", "io.debezium.time.Timestamp")
+	case "timestamp":
+		return avroLogical("string", "io.debezium.time.ZonedTimestamp")
+	case "year":
+		return avroLogical("int", "io.debezium.time.Year")
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return avroType{primitive: "record", connectName: "io.debezium.data.geometry.Geometry"}
+	}
+	return avroType{primitive: "string"}
+}
+
+// schema returns the JSON representation of at. Named types are only defined
+// on first use; defined tracks which names have been defined so far.
+func (at avroType) schema(defined map[string]bool) interface{} {
+	if at.primitive == "record" {
+		if defined[at.connectName] {
+			return at.connectName
+		}
+		defined[at.connectName] = true
+		return avroGeometry
+	}
+	if len(at.props) == 0 {
+		return at.primitive
+	}
+	return append(jsonObject{{"type", at.primitive}}, at.props...)
+}
+
+// avroDefault returns the Avro default value for a NOT NULL column, if
+// Debezium would include one. Only literal defaults of types without a logical
+// type are converted.
+func avroDefault(col *tengo.Column, at avroType) (interface{}, bool) {
+	if at.connectName != "" {
+		return nil, false
+	}
+	switch at.primitive {
+	case "int", "long":
+		return jsonSchemaDefault(col, "integer")
+	case "double":
+		return jsonSchemaDefault(col, "number")
+	case "string":
+		return jsonSchemaDefault(col, "string")
+	case "boolean":
+		return jsonSchemaDefault(col, "boolean")
+	}
+	return nil, false
+}
+
+var avroInvalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// avroName adjusts name to be a valid Avro name, in the same manner as
+// Debezium's field.name.adjustment.mode=avro.
+func avroName(name string) string {
+	name = avroInvalidNameChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// avroRecord returns the Avro record schema for the value of table's change
+// events. The namespace matches the Kafka topic name Debezium uses for the
+// table.
+func avroRecord(topicPrefix, schemaName string, table *tengo.Table, defined map[string]bool) jsonObject {
+	namespace := avroName(schemaName) + "." + avroName(table.Name)
+	if topicPrefix != "" {
+		namespace = avroName(topicPrefix) + "." + namespace
+	}
+	fields := make([]jsonObject, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		at := columnAvroType(col)
+		field := jsonObject{{"name", avroName(col.Name)}}
+		if col.Nullable {
+			field.set("type", []interface{}{"null", at.schema(defined)})
+			field.set("default", nil)
+		} else {
+			field.set("type", at.schema(defined))
+			if def, ok := avroDefault(col, at); ok {
+				field.set("default", def)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return jsonObject{
+		{"type", "record"},
+		{"name", "Value"},
+		{"namespace", namespace},
+		{"fields", fields},
+		{"connect.name", namespace + ".Value"},
+	}
+}
+
+// ExportAvro writes schemas to w as a JSON array containing the Avro record
+// schema of each table's change event values. If opts.AvroTopicPrefix is set,
+// it is used as the first component of each record's namespace, matching the
+// topic.prefix setting of the Debezium connector.
+func ExportAvro(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	defined := make(map[string]bool)
+	records := []jsonObject{}
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			records = append(records, avroRecord(opts.AvroTopicPrefix, schema.Name, table, defined))
+		}
+	}
+	return writeJSON(w, records)
+}
+
+// avroPromotions maps each Avro primitive type to the reader types it may be
+// promoted to, per the schema resolution rules of the Avro specification.
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// AvroIncompatibilities compares the Avro schemas of two versions of a table,
+// returning descriptions of any changes which are not backward-compatible:
+// that is, changes preventing consumers using the new schema from reading
+// events already written to the table's Kafka topic with the old schema. This
+// matches the default BACKWARD compatibility level of Confluent Schema
+// Registry. A nil slice is returned if the change is compatible.
+func AvroIncompatibilities(from, to *tengo.Table) (problems []string) {
+	oldCols := make(map[string]*tengo.Column, len(from.Columns))
+	for _, col := range from.Columns {
+		oldCols[avroName(col.Name)] = col
+	}
+	for _, col := range to.Columns {
+		newType := columnAvroType(col)
+		oldCol, existed := oldCols[avroName(col.Name)]
+		if !existed {
+			if _, hasDefault := avroDefault(col, newType); !col.Nullable && !hasDefault {
+				problems = append(problems, fmt.Sprintf("column %s is added without a default value", tengo.EscapeIdentifier(col.Name)))
+			}
+			continue
+		}
+		oldType := columnAvroType(oldCol)
+		if oldCol.Nullable && !col.Nullable {
+			problems = append(problems, fmt.Sprintf("column %s changes from nullable to NOT NULL", tengo.EscapeIdentifier(col.Name)))
+		}
+		if oldType.primitive != newType.primitive {
+			var promotable bool
+			for _, promoted := range avroPromotions[oldType.primitive] {
+				promotable = promotable || promoted == newType.primitive
+			}
+			if !promotable {
+				problems = append(problems, fmt.Sprintf("column %s changes Avro type from %s to %s", tengo.EscapeIdentifier(col.Name), oldType.primitive, newType.primitive))
+			}
+		} else if oldType.connectName == "org.apache.kafka.connect.data.Decimal" && newType.connectName == oldType.connectName && (oldType.precision != newType.precision || oldType.scale != newType.scale) {
+			problems = append(problems, fmt.Sprintf("column %s changes decimal precision or scale from (%d,%d) to (%d,%d)", tengo.EscapeIdentifier(col.Name), oldType.precision, oldType.scale, newType.precision, newType.scale))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportAvro(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30), AvroTopicPrefix: "dbserver1"}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("avro").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	var records []struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Fields    []struct {
+			Name    string          `json:"name"`
+			Type    json.RawMessage `json:"type"`
+			Default json.RawMessage `json:"default"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("Unable to decode output: %v\n%s", err, buf.String())
+	}
+	if len(records) != 2 || records[0].Namespace != "dbserver1.app.posts" || records[1].Namespace != "dbserver1.app.users" || records[1].Name != "Value" {
+		t.Fatalf("Unexpected records in output:\n%s", buf.String())
+	}
+	expected := map[string]string{
+		"user_id":    `"long"`,
+		"body":       `["null","string"]`,
+		"id":         `"long"`,
+		"email":      `"string"`,
+		"status":     `{"type":"string","connect.version":1,"connect.parameters":{"allowed":"active,banned"},"connect.name":"io.debezium.data.Enum"}`,
+		"created_at": `{"type":"string","connect.version":1,"connect.name":"io.debezium.time.ZonedTimestamp"}`,
+	}
+	for _, record := range records {
+		for _, field := range record.Fields {
+			var compact bytes.Buffer
+			json.Compact(&compact, field.Type)
+			if want, ok := expected[field.Name]; ok && compact.String() != want {
+				t.Errorf("Unexpected type for field %s: expected %s, found %s", field.Name, want, compact.String())
+			}
+		}
+	}
+	if body := records[0].Fields[2]; string(body.Default) != "null" {
+		t.Errorf("Expected nullable field to have null default, instead found %s", body.Default)
+	}
+}
+
+func TestColumnAvroType(t *testing.T) {
+	cases := map[string]string{
+		"tinyint(1)":                  `{"type":"int","connect.type":"int16"}`,
+		"smallint unsigned":           `"int"`,
+		"bigint unsigned":             `"long"`,
+		"float":                       `"double"`,
+		"decimal(8,3)":                `{"type":"bytes","scale":3,"precision":8,"connect.version":1,"connect.parameters":{"scale":"3","connect.decimal.precision":"8"},"connect.name":"org.apache.kafka.connect.data.Decimal","logicalType":"decimal"}`,
+		"bit(1)":                      `"boolean"`,
+		"bit(8)":                      `{"type":"bytes","connect.version":1,"connect.parameters":{"length":"8"},"connect.name":"io.debezium.data.Bits"}`,
+		"datetime(3)":                 `{"type":"long","connect.version":1,"connect.name":"io.debezium.time.Timestamp"}`,
+		"datetime(6)":                 `{"type":"long","connect.version":1,"connect.name":"io.debezium.time.MicroTimestamp"}`,
+		"date":                        `{"type":"int","connect.version":1,"connect.name":"io.debezium.time.Date"}`,
+		"varbinary(10)":               `"bytes"`,
+		"json":                        `{"type":"string","connect.version":1,"connect.name":"io.debezium.data.Json"}`,
+		"set('a','b')":                `{"type":"string","connect.version":1,"connect.parameters":{"allowed":"a,b"},"connect.name":"io.debezium.data.EnumSet"}`,
+		"point /*!80003 SRID 4326 */": `"io.debezium.data.geometry.Geometry"`,
+	}
+	defined := map[string]bool{"io.debezium.data.geometry.Geometry": true}
+	for colType, expected := range cases {
+		col := &tengo.Column{Name: "c", TypeInDB: colType}
+		actual, err := json.Marshal(columnAvroType(col).schema(defined))
+		if err != nil {
+			t.Errorf("Unexpected error marshaling %s: %v", colType, err)
+		} else if string(actual) != expected {
+			t.Errorf("Unexpected Avro type for %s: expected %s, found %s", colType, expected, actual)
+		}
+	}
+}
+
+func TestAvroIncompatibilities(t *testing.T) {
+	build := func(cols ...[2]string) *tengo.Table {
+		t.Helper()
+		builder := tengo.NewTableBuilder("t").Column("id", "int", tengo.NotNull())
+		for _, col := range cols {
+			if col[0] == "nullable" {
+				builder = builder.Column(col[1], "varchar(10)")
+			} else {
+				builder = builder.Column(col[0], col[1], tengo.NotNull())
+			}
+		}
+		table, err := builder.PrimaryKey("id").Build(tengo.FlavorMySQL80.Dot(30))
+		if err != nil {
+			t.Fatalf("Unexpected error building table: %v", err)
+		}
+		return table
+	}
+	cases := []struct {
+		from, to *tengo.Table
+		expected int
+	}{
+		{build(), build([2]string{"nullable", "note"}), 0},                 // add nullable column
+		{build([2]string{"a", "int"}), build(), 0},                         // drop column
+		{build([2]string{"a", "int"}), build([2]string{"a", "bigint"}), 0}, // int -> long promotion
+		{build(), build([2]string{"a", "int"}), 1},                         // add NOT NULL without default
+		{build([2]string{"a", "bigint"}), build([2]string{"a", "int"}), 1}, // long -> int
+		{build([2]string{"nullable", "a"}), build([2]string{"a", "varchar(10)"}), 1},
+		{build([2]string{"a", "decimal(5,2)"}), build([2]string{"a", "decimal(6,2)"}), 1},
+		{build([2]string{"a", "varchar(10)"}), build([2]string{"a", "varbinary(10)"}), 0},
+	}
+	for n, c := range cases {
+		if problems := AvroIncompatibilities(c.from, c.to); len(problems) != c.expected {
+			t.Errorf("Case %d: expected %d problems, instead found %v", n, c.expected, problems)
+		}
+	}
+}
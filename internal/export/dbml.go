@@ -0,0 +1,216 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to and from DBML, the Database Markup
+// Language used by dbdiagram.io and related tools. See https://dbml.dbdiagram.io
+// for the language specification.
+
+func init() {
+	Register(&Format{
+		Name:        "dbml",
+		Description: "Database Markup Language, as used by dbdiagram.io",
+		Export:      ExportDBML,
+		Import:      ImportDBML,
+	})
+}
+
+// ExportDBML writes schemas to w in DBML format. Table names are qualified
+// with their schema name if more than one schema is supplied. Enum column types
+// are emitted as DBML enums named after their table and column.
+func ExportDBML(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	bw := bufio.NewWriter(w)
+	qualify := len(schemas) > 1
+	for _, schema := range schemas {
+		tableName := func(name string) string {
+			if qualify {
+				return dbmlIdentifier(schema.Name) + "." + dbmlIdentifier(name)
+			}
+			return dbmlIdentifier(name)
+		}
+		tables := sortedTables(schema)
+		var refs []string
+		for _, table := range tables {
+			var enums []string
+			fmt.Fprintf(bw, "Table %s {\n", tableName(table.Name))
+			for _, col := range table.Columns {
+				colType := dbmlType(col.TypeInDB)
+				if strings.HasPrefix(col.TypeInDB, "enum(") {
+					enumName := tableName(table.Name + "_" + col.Name)
+					enum := fmt.Sprintf("Enum %s {\n", enumName)
+					for _, val := range EnumValues(col.TypeInDB) {
+						enum += fmt.Sprintf("  %s\n", dbmlString(val, '"'))
+					}
+					enums = append(enums, enum+"}\n")
+					colType = enumName
+				}
+				var settings []string
+				if table.PrimaryKey != nil && len(table.PrimaryKey.Parts) == 1 && strings.EqualFold(table.PrimaryKey.Parts[0].ColumnName, col.Name) {
+					settings = append(settings, "pk")
+				}
+				if col.AutoIncrement {
+					settings = append(settings, "increment")
+				}
+				if !col.Nullable {
+					settings = append(settings, "not null")
+				}
+				if col.Default != "" && col.Default != "NULL" {
+					settings = append(settings, "default: "+dbmlDefault(col.Default))
+				}
+				if col.Comment != "" {
+					settings = append(settings, "note: "+dbmlString(col.Comment, '\''))
+				}
+				fmt.Fprintf(bw, "  %s %s%s\n", dbmlIdentifier(col.Name), colType, dbmlSettings(settings))
+			}
+
+			var indexLines []string
+			if table.PrimaryKey != nil && len(table.PrimaryKey.Parts) > 1 {
+				indexLines = append(indexLines, dbmlIndexParts(table.PrimaryKey)+" [pk]")
+			}
+			for _, idx := range table.SecondaryIndexes {
+				settings := []string{"name: " + dbmlString(idx.Name, '\'')}
+				if idx.Unique {
+					settings = append(settings, "unique")
+				}
+				if idx.Type != "" && idx.Type != "BTREE" {
+					settings = append(settings, "type: "+strings.ToLower(idx.Type))
+				}
+				if idx.Comment != "" {
+					settings = append(settings, "note: "+dbmlString(idx.Comment, '\''))
+				}
+				indexLines = append(indexLines, dbmlIndexParts(idx)+dbmlSettings(settings))
+			}
+			if len(indexLines) > 0 {
+				bw.WriteString("\n  indexes {\n")
+				for _, line := range indexLines {
+					fmt.Fprintf(bw, "    %s\n", line)
+				}
+				bw.WriteString("  }\n")
+			}
+			if table.Comment != "" {
+				fmt.Fprintf(bw, "\n  Note: %s\n", dbmlString(table.Comment, '\''))
+			}
+			bw.WriteString("}\n\n")
+			for _, enum := range enums {
+				bw.WriteString(enum + "\n")
+			}
+
+			for _, fk := range table.ForeignKeys {
+				parent := tableName(fk.ReferencedTableName)
+				if fk.ReferencedSchemaName != "" {
+					parent = dbmlIdentifier(fk.ReferencedSchemaName) + "." + dbmlIdentifier(fk.ReferencedTableName)
+				}
+				var settings []string
+				if fk.DeleteRule != "" && fk.DeleteRule != "RESTRICT" && fk.DeleteRule != "NO ACTION" {
+					settings = append(settings, "delete: "+strings.ToLower(fk.DeleteRule))
+				}
+				if fk.UpdateRule != "" && fk.UpdateRule != "RESTRICT" && fk.UpdateRule != "NO ACTION" {
+					settings = append(settings, "update: "+strings.ToLower(fk.UpdateRule))
+				}
+				refs = append(refs, fmt.Sprintf("Ref %s: %s.%s > %s.%s%s\n",
+					dbmlIdentifier(fk.Name),
+					tableName(table.Name), dbmlColumnList(fk.ColumnNames),
+					parent, dbmlColumnList(fk.ReferencedColumnNames),
+					dbmlSettings(settings)))
+			}
+		}
+		for _, ref := range refs {
+			bw.WriteString(ref)
+		}
+		if len(refs) > 0 {
+			bw.WriteString("\n")
+		}
+	}
+	return bw.Flush()
+}
+
+var dbmlBareIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dbmlIdentifier returns name, double-quoted if it contains any characters
+// which are not permitted in a bare DBML identifier.
+func dbmlIdentifier(name string) string {
+	if dbmlBareIdentifier.MatchString(name) {
+		return name
+	}
+	return dbmlString(name, '"')
+}
+
+var dbmlBareType = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([0-9, ]+\))?$`)
+
+// dbmlType returns a column type, double-quoted unless it consists solely of a
+// type name and optional numeric arguments.
+func dbmlType(colType string) string {
+	if dbmlBareType.MatchString(colType) {
+		return colType
+	}
+	return dbmlString(colType, '"')
+}
+
+// dbmlString returns s as a DBML string literal using the supplied quote
+// character. Strings containing newlines use triple single-quotes.
+func dbmlString(s string, quote byte) string {
+	if strings.Contains(s, "\n") {
+		return "'''" + strings.ReplaceAll(s, "'''", `\'''`) + "'''"
+	}
+	q := string(quote)
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return q + strings.ReplaceAll(s, q, `\`+q) + q
+}
+
+// dbmlDefault converts a column default, which is stored as a SQL expression,
+// into a DBML default value.
+func dbmlDefault(def string) string {
+	if len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		return dbmlString(strings.ReplaceAll(def[1:len(def)-1], "''", "'"), '\'')
+	}
+	if _, err := strconv.ParseFloat(def, 64); err == nil {
+		return def
+	}
+	switch strings.ToLower(def) {
+	case "true", "false", "null":
+		return strings.ToLower(def)
+	}
+	return "`" + def + "`"
+}
+
+func dbmlSettings(settings []string) string {
+	if len(settings) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(settings, ", ") + "]"
+}
+
+func dbmlColumnList(colNames []string) string {
+	if len(colNames) == 1 {
+		return dbmlIdentifier(colNames[0])
+	}
+	quoted := make([]string, len(colNames))
+	for n, col := range colNames {
+		quoted[n] = dbmlIdentifier(col)
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+func dbmlIndexParts(idx *tengo.Index) string {
+	parts := make([]string, len(idx.Parts))
+	for n, part := range idx.Parts {
+		if part.Expression != "" {
+			parts[n] = "`" + part.Expression + "`"
+		} else {
+			parts[n] = dbmlIdentifier(part.ColumnName)
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// testSchema returns a schema with two related tables, for use in exporter
+// tests.
+func testSchema(t *testing.T, flavor tengo.Flavor) *tengo.Schema {
+	t.Helper()
+	users, err := tengo.NewTableBuilder("users").
+		Column("id", "int unsigned", tengo.AutoIncrement()).
+		Column("email", "varchar(100)", tengo.NotNull(), tengo.ColumnComment("login address")).
+		Column("status", "enum('active','banned')", tengo.NotNull(), tengo.DefaultString("active")).
+		Column("created_at", "timestamp", tengo.NotNull(), tengo.DefaultExpr("CURRENT_TIMESTAMP")).
+		PrimaryKey("id").
+		UniqueIndex("email", "email").
+		Comment("registered users").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	posts, err := tengo.NewTableBuilder("posts").
+		Column("user_id", "int unsigned", tengo.NotNull()).
+		Column("seq", "int unsigned", tengo.NotNull()).
+		Column("body", "text").
+		PrimaryKey("user_id", "seq").
+		ForeignKey("posts_user", []string{"user_id"}, "users", []string{"id"}, "cascade", "").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	return &tengo.Schema{Name: "app", Tables: []*tengo.Table{users, posts}}
+}
+
+func TestExportDBML(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("dbml").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `Table posts {
+  user_id "int unsigned" [not null]
+  seq "int unsigned" [not null]
+  body text
+
+  indexes {
+    (user_id, seq) [pk]
+  }
+}
+
+Table users {
+  id "int unsigned" [pk, increment, not null]
+  email varchar(100) [not null, note: 'login address']
+  status users_status [not null, default: 'active']
+  created_at timestamp [not null, default: ` + "`CURRENT_TIMESTAMP`" + `]
+
+  indexes {
+    email [name: 'email', unique]
+  }
+
+  Note: 'registered users'
+}
+
+Enum users_status {
+  "active"
+  "banned"
+}
+
+Ref posts_user: posts.user_id > users.id [delete: cascade]
+
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected DBML output:\n%s\nExpected:\n%s", actual, expected)
+	}
+
+	// Round-trip back to tables
+	tables, err := ImportDBML(&buf, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error from import: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, instead found %d", len(tables))
+	}
+	for _, table := range tables {
+		orig := schema.Table(table.Name)
+		if orig == nil || orig.CreateStatement != table.CreateStatement {
+			t.Errorf("Round-trip mismatch for table %s:\n%s\nExpected:\n%s", table.Name, table.CreateStatement, orig.CreateStatement)
+		}
+	}
+}
+
+func TestImportDBML(t *testing.T) {
+	input := `
+// A comment
+Project demo { database_type: 'MySQL' }
+
+Table core.authors as A [note: 'people'] {
+  id integer [primary key, increment]
+  name string [not null, note: '''
+    Multi-line
+    note''']
+  tier tiers [default: 'free']
+  score "decimal(5,2)" [default: -1.5]
+}
+
+Table books {
+  id int [pk]
+  author_id int [ref: > core.authors.id]
+  editor_id int
+  isbn varchar(13) [unique]
+  indexes {
+    (author_id, isbn) [name: 'author_isbn']
+  }
+}
+
+Enum tiers {
+  free
+  "paid" [note: 'paying customers']
+}
+
+Ref: authors.id < books.editor_id [update: set null, delete: set null]
+`
+	tables, err := ImportDBML(strings.NewReader(input), Options{Flavor: tengo.FlavorMySQL57})
+	if err != nil {
+		t.Fatalf("Unexpected error from import: %v", err)
+	}
+	if len(tables) != 2 || tables[0].Name != "authors" || tables[1].Name != "books" {
+		t.Fatalf("Unexpected tables: %+v", tables)
+	}
+	expected := "CREATE TABLE `authors` (\n" +
+		"  `id` int(11) NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(255) NOT NULL COMMENT 'Multi-line\\nnote',\n" +
+		"  `tier` enum('free','paid') DEFAULT 'free',\n" +
+		"  `score` decimal(5,2) DEFAULT -1.5,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='people'"
+	if tables[0].CreateStatement != expected {
+		t.Errorf("Unexpected CREATE TABLE:\n%s\nExpected:\n%s", tables[0].CreateStatement, expected)
+	}
+	books := tables[1]
+	if len(books.ForeignKeys) != 2 || len(books.SecondaryIndexes) != 2 {
+		t.Fatalf("Unexpected foreign keys or indexes: %s", books.CreateStatement)
+	}
+	for _, fk := range books.ForeignKeys {
+		if fk.ReferencedTableName != "authors" {
+			t.Errorf("Unexpected referenced table in %s", fk.Definition(tengo.FlavorMySQL57))
+		}
+		if fk.ColumnNames[0] == "editor_id" && (fk.DeleteRule != "SET NULL" || fk.UpdateRule != "SET NULL") {
+			t.Errorf("Unexpected rules in %s", fk.Definition(tengo.FlavorMySQL57))
+		}
+	}
+
+	badInputs := []string{
+		"Table t { id int [pk",
+		"Table t { id }",
+		"Table t { id int }\nRef: t.id <> u.id",
+		"Table t { id int }\nRef: x.id > t.id",
+		"Table t { id int }\nTable t { id int }",
+		"Table t { id int [note: 'unterminated] }",
+	}
+	for _, input := range badInputs {
+		if _, err := ImportDBML(strings.NewReader(input), Options{Flavor: tengo.FlavorMySQL80}); err == nil {
+			t.Errorf("Expected error importing %q, but err was nil", input)
+		}
+	}
+}
@@ -0,0 +1,696 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+type dbmlTokenType int
+
+const (
+	dbmlTokenIdent dbmlTokenType = iota
+	dbmlTokenString
+	dbmlTokenExpr   // backtick-wrapped expression
+	dbmlTokenNumber // numeric literal
+	dbmlTokenSymbol // single punctuation character, or a ref operator such as <>
+)
+
+type dbmlToken struct {
+	typ   dbmlTokenType
+	val   string
+	line  int
+	start int // byte offset in source
+	end   int
+}
+
+func (tok dbmlToken) is(symbol string) bool {
+	return tok.typ == dbmlTokenSymbol && tok.val == symbol
+}
+
+func (tok dbmlToken) isKeyword(keyword string) bool {
+	return tok.typ == dbmlTokenIdent && strings.EqualFold(tok.val, keyword)
+}
+
+// lexDBML splits src into tokens, discarding whitespace and comments.
+func lexDBML(src string) ([]dbmlToken, error) {
+	var tokens []dbmlToken
+	line := 1
+	for pos := 0; pos < len(src); {
+		c := src[pos]
+		start := pos
+		switch {
+		case c == '\n':
+			line++
+			pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			pos++
+		case strings.HasPrefix(src[pos:], "//"):
+			for pos < len(src) && src[pos] != '\n' {
+				pos++
+			}
+		case strings.HasPrefix(src[pos:], "/*"):
+			endPos := strings.Index(src[pos+2:], "*/")
+			if endPos < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[pos:pos+2+endPos], "\n")
+			pos += endPos + 4
+		case strings.HasPrefix(src[pos:], "'''"):
+			endPos := strings.Index(src[pos+3:], "'''")
+			for endPos > 0 && src[pos+3+endPos-1] == '\\' {
+				next := strings.Index(src[pos+3+endPos+1:], "'''")
+				if next < 0 {
+					endPos = -1
+					break
+				}
+				endPos += next + 1
+			}
+			if endPos < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			val := strings.ReplaceAll(src[pos+3:pos+3+endPos], `\'''`, "'''")
+			tokens = append(tokens, dbmlToken{typ: dbmlTokenString, val: dedent(val), line: line, start: start, end: pos + endPos + 6})
+			line += strings.Count(src[pos:pos+3+endPos], "\n")
+			pos += endPos + 6
+		case c == '\'' || c == '"' || c == '`':
+			var b strings.Builder
+			pos++
+			for pos < len(src) && src[pos] != c {
+				if src[pos] == '\\' && pos+1 < len(src) {
+					pos++
+				}
+				if src[pos] == '\n' {
+					line++
+				}
+				b.WriteByte(src[pos])
+				pos++
+			}
+			if pos >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			pos++
+			typ := dbmlTokenString
+			if c == '`' {
+				typ = dbmlTokenExpr
+			} else if c == '"' {
+				typ = dbmlTokenIdent // double-quoted strings are quoted identifiers
+			}
+			tokens = append(tokens, dbmlToken{typ: typ, val: b.String(), line: line, start: start, end: pos})
+		case c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			for pos < len(src) && (src[pos] == '_' || src[pos] >= 0x80 || unicode.IsLetter(rune(src[pos])) || unicode.IsDigit(rune(src[pos]))) {
+				pos++
+			}
+			tokens = append(tokens, dbmlToken{typ: dbmlTokenIdent, val: src[start:pos], line: line, start: start, end: pos})
+		case unicode.IsDigit(rune(c)) || (c == '-' && pos+1 < len(src) && unicode.IsDigit(rune(src[pos+1])) && (len(tokens) == 0 || !tokens[len(tokens)-1].is("."))):
+			pos++
+			for pos < len(src) && (unicode.IsDigit(rune(src[pos])) || src[pos] == '.') {
+				pos++
+			}
+			tokens = append(tokens, dbmlToken{typ: dbmlTokenNumber, val: src[start:pos], line: line, start: start, end: pos})
+		case strings.HasPrefix(src[pos:], "<>"):
+			pos += 2
+			tokens = append(tokens, dbmlToken{typ: dbmlTokenSymbol, val: "<>", line: line, start: start, end: pos})
+		case strings.ContainsRune("{}[]():,.<>-", rune(c)):
+			pos++
+			tokens = append(tokens, dbmlToken{typ: dbmlTokenSymbol, val: string(c), line: line, start: start, end: pos})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+	return tokens, nil
+}
+
+// dedent removes common leading indentation from a multi-line string, as
+// required for DBML triple-quoted strings.
+func dedent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for n, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[n] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dbmlParser converts DBML tokens into table builders.
+type dbmlParser struct {
+	src      string
+	tokens   []dbmlToken
+	pos      int
+	builders map[string]*tengo.TableBuilder
+	order    []string
+	columns  map[string][]dbmlColumn // added to builders once enum types are known
+	enums    map[string][]string
+	refs     []dbmlRef
+}
+
+type dbmlColumn struct {
+	name    string
+	colType string
+	opts    []tengo.ColumnOption
+}
+
+type dbmlRef struct {
+	name        string
+	childTable  string
+	childCols   []string
+	parentTable string
+	parentCols  []string
+	onDelete    string
+	onUpdate    string
+	line        int
+}
+
+func (p *dbmlParser) peek() dbmlToken {
+	if p.pos >= len(p.tokens) {
+		return dbmlToken{typ: dbmlTokenSymbol, val: "EOF", line: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *dbmlParser) next() dbmlToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *dbmlParser) expect(symbol string) error {
+	if tok := p.next(); !tok.is(symbol) {
+		return fmt.Errorf("line %d: expected %q, found %q", tok.line, symbol, tok.val)
+	}
+	return nil
+}
+
+// qualifiedName parses a name which may be prefixed by a schema name. The
+// schema name is discarded.
+func (p *dbmlParser) qualifiedName() (string, error) {
+	tok := p.next()
+	if tok.typ != dbmlTokenIdent && tok.typ != dbmlTokenString {
+		return "", fmt.Errorf("line %d: expected name, found %q", tok.line, tok.val)
+	}
+	name := tok.val
+	if p.peek().is(".") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].typ == dbmlTokenIdent {
+		p.pos++
+		name = p.next().val
+	}
+	return name, nil
+}
+
+// skipBlock skips tokens through the end of the next brace-delimited block.
+func (p *dbmlParser) skipBlock() error {
+	for !p.peek().is("{") {
+		if p.peek().val == "EOF" {
+			return nil
+		}
+		p.pos++
+	}
+	depth := 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.is("{"):
+			depth++
+		case tok.is("}"):
+			if depth--; depth == 0 {
+				return nil
+			}
+		case tok.val == "EOF" && tok.line < 0:
+			return fmt.Errorf("unterminated block")
+		}
+	}
+}
+
+// settings parses a bracketed settings list, returning each setting's
+// lowercased key and its value tokens.
+func (p *dbmlParser) settings() (keys []string, values [][]dbmlToken, err error) {
+	if err := p.expect("["); err != nil {
+		return nil, nil, err
+	}
+	for {
+		var item []dbmlToken
+		for !p.peek().is(",") && !p.peek().is("]") {
+			if p.peek().line < 0 {
+				return nil, nil, fmt.Errorf("unterminated settings list")
+			}
+			item = append(item, p.next())
+		}
+		if len(item) > 0 {
+			var key []string
+			n := 0
+			for ; n < len(item) && !item[n].is(":"); n++ {
+				key = append(key, strings.ToLower(item[n].val))
+			}
+			var val []dbmlToken
+			if n < len(item) {
+				val = item[n+1:]
+			}
+			keys = append(keys, strings.Join(key, " "))
+			values = append(values, val)
+		}
+		if p.next().is("]") {
+			return keys, values, nil
+		}
+	}
+}
+
+// ImportDBML parses a DBML document, returning the tables it defines. Refs
+// become foreign keys, enums become enum column types, and notes become
+// comments. Schema name qualifiers are ignored.
+func ImportDBML(r io.Reader, opts Options) ([]*tengo.Table, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := lexDBML(string(src))
+	if err != nil {
+		return nil, err
+	}
+	p := &dbmlParser{
+		src:      string(src),
+		tokens:   tokens,
+		builders: make(map[string]*tengo.TableBuilder),
+		columns:  make(map[string][]dbmlColumn),
+		enums:    make(map[string][]string),
+	}
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+		switch {
+		case tok.isKeyword("Table"):
+			err = p.table()
+		case tok.isKeyword("Ref"):
+			err = p.ref()
+		case tok.isKeyword("Enum"):
+			err = p.enum()
+		case tok.typ == dbmlTokenIdent: // Project, TableGroup, Note, etc
+			err = p.skipBlock()
+		default:
+			err = fmt.Errorf("line %d: unexpected %q", tok.line, tok.val)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.build(opts.Flavor)
+}
+
+func (p *dbmlParser) table() error {
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if p.peek().isKeyword("as") {
+		p.pos += 2 // alias is not needed
+	}
+	if _, already := p.builders[name]; already {
+		return fmt.Errorf("line %d: table %s defined more than once", p.peek().line, name)
+	}
+	b := tengo.NewTableBuilder(name)
+	p.builders[name] = b
+	p.order = append(p.order, name)
+	if p.peek().is("[") {
+		keys, values, err := p.settings()
+		if err != nil {
+			return err
+		}
+		for n, key := range keys {
+			if key == "note" && len(values[n]) > 0 {
+				b.Comment(values[n][0].val)
+			}
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().is("}") {
+		tok := p.peek()
+		if tok.line < 0 {
+			return fmt.Errorf("unterminated table %s", name)
+		}
+		switch {
+		case tok.isKeyword("indexes") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is("{"):
+			p.pos += 2
+			if err := p.indexes(name, b); err != nil {
+				return err
+			}
+		case tok.isKeyword("note") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is(":"):
+			p.pos += 2
+			b.Comment(p.next().val)
+		case tok.isKeyword("note") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is("{"):
+			p.pos += 2
+			b.Comment(p.next().val)
+			if err := p.expect("}"); err != nil {
+				return err
+			}
+		default:
+			if err := p.column(name, b); err != nil {
+				return err
+			}
+		}
+	}
+	p.pos++
+	return nil
+}
+
+func (p *dbmlParser) column(tableName string, b *tengo.TableBuilder) error {
+	nameTok := p.next()
+	if nameTok.typ != dbmlTokenIdent && nameTok.typ != dbmlTokenString {
+		return fmt.Errorf("line %d: expected column name, found %q", nameTok.line, nameTok.val)
+	}
+	// The type extends through the end of the line, or the settings list. Its
+	// raw text is used, so that types like decimal(10,2) are preserved.
+	first := p.peek()
+	if first.line != nameTok.line || first.is("[") || first.is("}") {
+		return fmt.Errorf("line %d: column %s has no type", nameTok.line, nameTok.val)
+	}
+	last := first
+	for tok := p.peek(); tok.line == nameTok.line && !tok.is("[") && !tok.is("}"); tok = p.peek() {
+		last = p.next()
+	}
+	colType := p.src[first.start:last.end]
+	if first == last && (first.typ == dbmlTokenIdent || first.typ == dbmlTokenString) {
+		colType = first.val
+	}
+	var opts []tengo.ColumnOption
+	if p.peek().is("[") {
+		keys, values, err := p.settings()
+		if err != nil {
+			return err
+		}
+		for n, key := range keys {
+			val := values[n]
+			switch key {
+			case "pk", "primary key":
+				b.PrimaryKey(nameTok.val)
+			case "increment":
+				opts = append(opts, tengo.AutoIncrement())
+			case "not null":
+				opts = append(opts, tengo.NotNull())
+			case "null":
+			case "unique":
+				b.UniqueIndex(nameTok.val, nameTok.val)
+			case "note":
+				if len(val) > 0 {
+					opts = append(opts, tengo.ColumnComment(val[0].val))
+				}
+			case "default":
+				if len(val) > 0 {
+					opts = append(opts, dbmlDefaultOption(val))
+				}
+			case "ref":
+				if len(val) < 2 {
+					return fmt.Errorf("line %d: invalid inline ref", nameTok.line)
+				}
+				sub := &dbmlParser{src: p.src, tokens: val[1:]}
+				parentTable, parentCols, err := sub.endpoint()
+				if err != nil {
+					return err
+				}
+				ref := dbmlRef{line: nameTok.line}
+				if err := ref.assign(val[0].val, dbmlEndpoint{tableName, []string{nameTok.val}}, dbmlEndpoint{parentTable, parentCols}); err != nil {
+					return err
+				}
+				p.refs = append(p.refs, ref)
+			}
+		}
+	}
+	p.columns[tableName] = append(p.columns[tableName], dbmlColumn{name: nameTok.val, colType: colType, opts: opts})
+	return nil
+}
+
+func dbmlDefaultOption(val []dbmlToken) tengo.ColumnOption {
+	tok := val[0]
+	switch {
+	case tok.typ == dbmlTokenString:
+		return tengo.DefaultString(tok.val)
+	case tok.typ == dbmlTokenExpr, tok.typ == dbmlTokenNumber:
+		return tengo.DefaultExpr(tok.val)
+	case tok.isKeyword("null"):
+		return tengo.DefaultExpr("NULL")
+	case tok.isKeyword("true"):
+		return tengo.DefaultExpr("1")
+	case tok.isKeyword("false"):
+		return tengo.DefaultExpr("0")
+	}
+	return tengo.DefaultExpr("'" + tok.val + "'")
+}
+
+func (p *dbmlParser) indexes(tableName string, b *tengo.TableBuilder) error {
+	for !p.peek().is("}") {
+		tok := p.peek()
+		if tok.line < 0 {
+			return fmt.Errorf("unterminated indexes block in table %s", tableName)
+		}
+		var cols []string
+		if tok.is("(") {
+			p.pos++
+			for !p.peek().is(")") {
+				part := p.next()
+				if part.typ == dbmlTokenExpr {
+					return fmt.Errorf("line %d: expression index parts are not supported", part.line)
+				} else if part.line < 0 {
+					return fmt.Errorf("unterminated index column list in table %s", tableName)
+				} else if !part.is(",") {
+					cols = append(cols, part.val)
+				}
+			}
+			p.pos++
+		} else if tok.typ == dbmlTokenExpr {
+			return fmt.Errorf("line %d: expression index parts are not supported", tok.line)
+		} else {
+			cols = append(cols, p.next().val)
+		}
+		var name string
+		var unique, pk bool
+		if p.peek().is("[") {
+			keys, values, err := p.settings()
+			if err != nil {
+				return err
+			}
+			for n, key := range keys {
+				switch key {
+				case "pk", "primary key":
+					pk = true
+				case "unique":
+					unique = true
+				case "name":
+					if len(values[n]) > 0 {
+						name = values[n][0].val
+					}
+				}
+			}
+		}
+		if name == "" {
+			name = cols[0]
+		}
+		if pk {
+			b.PrimaryKey(cols...)
+		} else if unique {
+			b.UniqueIndex(name, cols...)
+		} else {
+			b.Index(name, cols...)
+		}
+	}
+	p.pos++
+	return nil
+}
+
+type dbmlEndpoint struct {
+	table   string
+	columns []string
+}
+
+// endpoint parses table.column or table.(col1, col2), optionally prefixed
+// with a schema name.
+func (p *dbmlParser) endpoint() (string, []string, error) {
+	var parts []string
+	for {
+		tok := p.next()
+		if tok.is("(") {
+			var cols []string
+			for !p.peek().is(")") {
+				if col := p.next(); col.line < 0 {
+					return "", nil, fmt.Errorf("unterminated column list")
+				} else if !col.is(",") {
+					cols = append(cols, col.val)
+				}
+			}
+			p.pos++
+			if len(parts) == 0 {
+				return "", nil, fmt.Errorf("line %d: missing table name in ref", tok.line)
+			}
+			return parts[len(parts)-1], cols, nil
+		}
+		if tok.typ != dbmlTokenIdent && tok.typ != dbmlTokenString {
+			return "", nil, fmt.Errorf("line %d: expected name in ref, found %q", tok.line, tok.val)
+		}
+		parts = append(parts, tok.val)
+		if !p.peek().is(".") {
+			break
+		}
+		p.pos++
+	}
+	if len(parts) < 2 {
+		return "", nil, fmt.Errorf("ref endpoint %s must be in table.column format", strings.Join(parts, "."))
+	}
+	return parts[len(parts)-2], []string{parts[len(parts)-1]}, nil
+}
+
+// assign sets the child and parent sides of the ref based on the relationship
+// operator: for > and - the left side holds the foreign key, and for < the
+// right side does.
+func (ref *dbmlRef) assign(op string, left, right dbmlEndpoint) error {
+	switch op {
+	case ">", "-":
+	case "<":
+		left, right = right, left
+	default:
+		return fmt.Errorf("line %d: relationship %q cannot be represented as a foreign key", ref.line, op)
+	}
+	ref.childTable, ref.childCols = left.table, left.columns
+	ref.parentTable, ref.parentCols = right.table, right.columns
+	return nil
+}
+
+func (p *dbmlParser) ref() error {
+	var ref dbmlRef
+	ref.line = p.peek().line
+	if tok := p.peek(); tok.typ == dbmlTokenIdent && p.pos+1 < len(p.tokens) && (p.tokens[p.pos+1].is(":") || p.tokens[p.pos+1].is("{")) {
+		ref.name = tok.val
+		p.pos++
+	}
+	braced := p.peek().is("{")
+	p.pos++ // skip : or {
+	leftTable, leftCols, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	op := p.next()
+	rightTable, rightCols, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	if err := ref.assign(op.val, dbmlEndpoint{leftTable, leftCols}, dbmlEndpoint{rightTable, rightCols}); err != nil {
+		return err
+	}
+	if p.peek().is("[") {
+		keys, values, err := p.settings()
+		if err != nil {
+			return err
+		}
+		for n, key := range keys {
+			var words []string
+			for _, tok := range values[n] {
+				words = append(words, tok.val)
+			}
+			switch key {
+			case "delete":
+				ref.onDelete = strings.Join(words, " ")
+			case "update":
+				ref.onUpdate = strings.Join(words, " ")
+			}
+		}
+	}
+	if braced {
+		if err := p.expect("}"); err != nil {
+			return err
+		}
+	}
+	p.refs = append(p.refs, ref)
+	return nil
+}
+
+func (p *dbmlParser) enum() error {
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var values []string
+	for !p.peek().is("}") {
+		tok := p.next()
+		if tok.line < 0 {
+			return fmt.Errorf("unterminated enum %s", name)
+		}
+		values = append(values, tok.val)
+		if p.peek().is("[") {
+			if _, _, err := p.settings(); err != nil {
+				return err
+			}
+		}
+	}
+	p.pos++
+	p.enums[name] = values
+	return nil
+}
+
+// build resolves enum types and refs, and then builds each table.
+func (p *dbmlParser) build(flavor tengo.Flavor) ([]*tengo.Table, error) {
+	for _, ref := range p.refs {
+		b := p.builders[ref.childTable]
+		if b == nil {
+			return nil, fmt.Errorf("line %d: ref refers to undefined table %s", ref.line, ref.childTable)
+		}
+		name := ref.name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_fk", ref.childTable, strings.Join(ref.childCols, "_"))
+		}
+		b.ForeignKey(name, ref.childCols, ref.parentTable, ref.parentCols, ref.onDelete, ref.onUpdate)
+	}
+	tables := make([]*tengo.Table, 0, len(p.order))
+	for _, tableName := range p.order {
+		b := p.builders[tableName]
+		for _, col := range p.columns[tableName] {
+			if values, ok := p.enums[col.colType]; ok {
+				col.colType = enumColumnType(values)
+			} else if alias, ok := dbmlTypeAliases[strings.ToLower(col.colType)]; ok {
+				col.colType = alias
+			}
+			b.Column(col.name, col.colType, col.opts...)
+		}
+		table, err := b.Build(flavor)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// dbmlTypeAliases maps generic type names, commonly used in DBML documents
+// written for other databases, to MySQL column types.
+var dbmlTypeAliases = map[string]string{
+	"integer": "int",
+	"string":  "varchar(255)",
+	"varchar": "varchar(255)",
+	"bool":    "tinyint(1)",
+	"boolean": "tinyint(1)",
+	"uuid":    "char(36)",
+	"float8":  "double",
+	"int8":    "bigint",
+	"int4":    "int",
+	"int2":    "smallint",
+}
+
+// enumColumnType returns an enum column type with the supplied values.
+func enumColumnType(values []string) string {
+	quoted := make([]string, len(values))
+	for n, val := range values {
+		quoted[n] = "'" + strings.ReplaceAll(val, "'", "''") + "'"
+	}
+	return "enum(" + strings.Join(quoted, ",") + ")"
+}
@@ -0,0 +1,201 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements dbt source definitions, as used in a dbt project's
+// sources.yml. See https://docs.getdbt.com/reference/source-properties for the
+// format.
+
+func init() {
+	Register(&Format{
+		Name:        "dbt",
+		Description: "dbt sources.yml source definitions",
+		Export:      ExportDBT,
+		Update:      UpdateDBT,
+	})
+}
+
+// dbtTestsKeys lists the keys which dbt accepts for a column's data tests. The
+// first is used in newly-generated columns.
+var dbtTestsKeys = []string{"data_tests", "tests"}
+
+// ExportDBT writes schemas to w as a dbt sources.yml document, with one source
+// per schema. Descriptions are populated from table and column comments, and
+// not_null and unique tests are generated from NOT NULL columns and
+// single-column unique indexes.
+func ExportDBT(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	doc := &yamlNode{kind: yamlMap}
+	doc.set("version", yamlPlain("2"))
+	sources := &yamlNode{kind: yamlSeq}
+	for _, schema := range schemas {
+		sources.items = append(sources.items, dbtSource(schema, nil))
+	}
+	doc.set("sources", sources)
+	return writeYAML(w, doc)
+}
+
+// UpdateDBT reads an existing sources.yml document from r, and writes it to w
+// with its definitions of schemas brought up to date. Sources, tables, and
+// columns are added or removed to match the schemas, while metadata which is
+// not derived from the schemas -- such as descriptions of objects without a
+// comment, custom tests, freshness, and meta -- is retained. Sources which do
+// not correspond to any of the schemas are left unchanged. Comments in the
+// existing document are not retained.
+func UpdateDBT(r io.Reader, w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	doc, err := parseYAML(r)
+	if err != nil {
+		return err
+	} else if doc.kind != yamlMap {
+		return fmt.Errorf("expected a YAML mapping at top level of document")
+	}
+	if doc.get("version") == nil {
+		doc.set("version", yamlPlain("2"))
+	}
+	sources := doc.get("sources")
+	if sources == nil || sources.kind != yamlSeq {
+		sources = &yamlNode{kind: yamlSeq}
+		doc.set("sources", sources)
+	}
+	for _, schema := range schemas {
+		var found bool
+		for n, existing := range sources.items {
+			name := existing.get("schema").scalar()
+			if name == "" {
+				name = existing.get("name").scalar()
+			}
+			if name == schema.Name {
+				sources.items[n] = dbtSource(schema, existing)
+				found = true
+				break
+			}
+		}
+		if !found {
+			sources.items = append(sources.items, dbtSource(schema, nil))
+		}
+	}
+	return writeYAML(w, doc)
+}
+
+// dbtSource returns the source definition for schema. If existing is non-nil,
+// it is updated in-place and returned.
+func dbtSource(schema *tengo.Schema, existing *yamlNode) *yamlNode {
+	source := existing
+	if source == nil {
+		source = &yamlNode{kind: yamlMap}
+		source.set("name", yamlString(schema.Name))
+	}
+	oldTables := source.get("tables")
+	tables := &yamlNode{kind: yamlSeq}
+	for _, table := range sortedTables(schema) {
+		tables.items = append(tables.items, dbtTable(table, findByName(oldTables, table.Name)))
+	}
+	source.set("tables", tables)
+	return source
+}
+
+// dbtTable returns the table definition for table. If existing is non-nil, it
+// is updated in-place and returned.
+func dbtTable(table *tengo.Table, existing *yamlNode) *yamlNode {
+	node := existing
+	if node == nil {
+		node = &yamlNode{kind: yamlMap}
+		node.set("name", yamlString(table.Name))
+	}
+	setDescription(node, table.Comment)
+
+	// Determine which columns are unique on their own
+	unique := make(map[string]bool)
+	indexes := table.SecondaryIndexes
+	if table.PrimaryKey != nil {
+		indexes = append([]*tengo.Index{table.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		if (idx.PrimaryKey || idx.Unique) && len(idx.Parts) == 1 && idx.Parts[0].ColumnName != "" && idx.Parts[0].PrefixLength == 0 {
+			unique[idx.Parts[0].ColumnName] = true
+		}
+	}
+
+	oldColumns := node.get("columns")
+	columns := &yamlNode{kind: yamlSeq}
+	for _, col := range table.Columns {
+		colNode := findByName(oldColumns, col.Name)
+		if colNode == nil {
+			colNode = &yamlNode{kind: yamlMap}
+			colNode.set("name", yamlString(col.Name))
+		}
+		setDescription(colNode, col.Comment)
+		colNode.set("data_type", yamlString(col.TypeInDB))
+		setDBTTest(colNode, "not_null", !col.Nullable)
+		setDBTTest(colNode, "unique", unique[col.Name])
+		columns.items = append(columns.items, colNode)
+	}
+	node.set("columns", columns)
+	return node
+}
+
+// findByName returns the mapping in sequence seq whose name key is name, or
+// nil if there is no such mapping.
+func findByName(seq *yamlNode, name string) *yamlNode {
+	if seq == nil || seq.kind != yamlSeq {
+		return nil
+	}
+	for _, item := range seq.items {
+		if item.get("name").scalar() == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// setDescription sets the description of node to comment. If comment is empty,
+// any existing description is retained, since it may have been written in the
+// dbt project rather than derived from the schema.
+func setDescription(node *yamlNode, comment string) {
+	if comment != "" {
+		node.set("description", yamlString(comment))
+	}
+}
+
+// setDBTTest adds or removes a generic test without arguments, such as
+// not_null, from a column's data tests. An existing test of the same name
+// which has arguments or a custom configuration is left as-is.
+func setDBTTest(col *yamlNode, testName string, want bool) {
+	key := dbtTestsKeys[0]
+	for _, k := range dbtTestsKeys {
+		if col.get(k) != nil {
+			key = k
+			break
+		}
+	}
+	tests := col.get(key)
+	if tests == nil || tests.kind != yamlSeq {
+		tests = &yamlNode{kind: yamlSeq}
+	}
+	var have bool
+	items := tests.items[:0:0]
+	for _, item := range tests.items {
+		if item.kind == yamlScalar && item.value == testName {
+			if !want {
+				continue
+			}
+			have = true
+		} else if item.get(testName) != nil {
+			have = true
+		}
+		items = append(items, item)
+	}
+	if want && !have {
+		items = append(items, yamlString(testName))
+	}
+	tests.items = items
+	if len(items) == 0 {
+		col.remove(key)
+	} else {
+		col.set(key, tests)
+	}
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportDBT(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("dbt").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `version: 2
+sources:
+  - name: app
+    tables:
+      - name: posts
+        columns:
+          - name: user_id
+            data_type: int unsigned
+            data_tests:
+              - not_null
+          - name: seq
+            data_type: int unsigned
+            data_tests:
+              - not_null
+          - name: body
+            data_type: text
+      - name: users
+        description: registered users
+        columns:
+          - name: id
+            data_type: int unsigned
+            data_tests:
+              - not_null
+              - unique
+          - name: email
+            description: login address
+            data_type: varchar(100)
+            data_tests:
+              - not_null
+              - unique
+          - name: status
+            data_type: enum('active','banned')
+            data_tests:
+              - not_null
+          - name: created_at
+            data_type: timestamp
+            data_tests:
+              - not_null
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected dbt output. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestUpdateDBT(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	existing := `# managed partly by skeema
+version: 2
+
+sources:
+  - name: other
+    tables:
+      - name: untouched
+  - name: raw_app
+    schema: app
+    loader: fivetran
+    freshness:
+      warn_after: {count: 12, period: hour}
+    tables:
+      - name: dropped_table
+      - name: posts
+        description: >
+          Blog posts,
+          written by users.
+        columns:
+          - name: body
+            description: "Post body: markdown"
+            tests:
+              - not_null
+              - accepted_values:
+                  values: ['a', 'b']
+          - name: removed_col
+            tests: [not_null]
+        meta:
+          owner: "@analytics"
+`
+	var buf bytes.Buffer
+	if err := Lookup("dbt").Update(strings.NewReader(existing), &buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from update: %v", err)
+	}
+	expectedPrefix := `version: 2
+sources:
+  - name: other
+    tables:
+      - name: untouched
+  - name: raw_app
+    schema: app
+    loader: fivetran
+    freshness:
+      warn_after:
+        count: 12
+        period: hour
+    tables:
+      - name: posts
+        description: "Blog posts, written by users.\n"
+        columns:
+          - name: user_id
+            data_type: int unsigned
+            data_tests:
+              - not_null
+          - name: seq
+            data_type: int unsigned
+            data_tests:
+              - not_null
+          - name: body
+            description: "Post body: markdown"
+            tests:
+              - accepted_values:
+                  values:
+                    - a
+                    - b
+            data_type: text
+        meta:
+          owner: "@analytics"
+      - name: users
+        description: registered users
+`
+	if actual := buf.String(); !strings.HasPrefix(actual, expectedPrefix) {
+		t.Errorf("Unexpected dbt update output. Expected prefix:\n%s\nActual:\n%s", expectedPrefix, actual)
+	}
+	if _, err := parseYAML(strings.NewReader("a: 1\n  b: 2\n")); err == nil {
+		t.Error("Expected error parsing invalid YAML, but err was nil")
+	}
+	if err := Lookup("dbt").Update(strings.NewReader("- a\n- b\n"), &buf, nil, opts); err == nil {
+		t.Error("Expected error updating document which is not a mapping, but err was nil")
+	}
+}
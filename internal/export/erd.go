@@ -0,0 +1,318 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements entity relationship diagram exporters, for PlantUML and
+// Graphviz DOT. Both share the same options for which details to include, as
+// well as the same logic for inferring the cardinality of each foreign key.
+
+func init() {
+	Register(&Format{
+		Name:        "plantuml",
+		Description: "PlantUML entity relationship diagram",
+		Export:      ExportPlantUML,
+	})
+	Register(&Format{
+		Name:        "dot",
+		Description: "Graphviz DOT entity relationship diagram",
+		Export:      ExportDOT,
+	})
+}
+
+// relationship describes a foreign key along with its inferred cardinality.
+type relationship struct {
+	*tengo.ForeignKey
+	child          string // qualified name of table containing the foreign key
+	parent         string // qualified name of referenced table
+	childUnique    bool   // if true, each parent row has at most one child row
+	parentOptional bool   // if true, child rows may have no parent row
+}
+
+// relationships returns the foreign keys of table, with cardinality inferred
+// from the table's unique indexes and the nullability of the FK columns.
+func relationships(schemaName string, table *tengo.Table) []relationship {
+	rels := make([]relationship, 0, len(table.ForeignKeys))
+	cols := table.ColumnsByName()
+	for _, fk := range table.ForeignKeys {
+		rel := relationship{
+			ForeignKey: fk,
+			child:      schemaName + "." + table.Name,
+			parent:     schemaName + "." + fk.ReferencedTableName,
+		}
+		if fk.ReferencedSchemaName != "" {
+			rel.parent = fk.ReferencedSchemaName + "." + fk.ReferencedTableName
+		}
+		for _, colName := range fk.ColumnNames {
+			if col := cols[colName]; col == nil || col.Nullable {
+				rel.parentOptional = true
+			}
+		}
+		uniqueIndexes := table.SecondaryIndexes
+		if table.PrimaryKey != nil {
+			uniqueIndexes = append([]*tengo.Index{table.PrimaryKey}, uniqueIndexes...)
+		}
+		for _, idx := range uniqueIndexes {
+			if (idx.PrimaryKey || idx.Unique) && indexCoveredBy(idx, fk.ColumnNames) {
+				rel.childUnique = true
+				break
+			}
+		}
+		rels = append(rels, rel)
+	}
+	return rels
+}
+
+// indexCoveredBy returns true if every part of idx is a full, non-expression
+// column present in colNames. If a unique index is covered by a foreign key's
+// columns, then those columns are unique as well.
+func indexCoveredBy(idx *tengo.Index, colNames []string) bool {
+	for _, part := range idx.Parts {
+		if part.ColumnName == "" || part.PrefixLength > 0 {
+			return false
+		}
+		var found bool
+		for _, colName := range colNames {
+			if strings.EqualFold(colName, part.ColumnName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// erdIndexes returns a textual description of each index of table, for use in
+// diagrams that include indexes.
+func erdIndexes(table *tengo.Table) []string {
+	indexes := table.SecondaryIndexes
+	if table.PrimaryKey != nil {
+		indexes = append([]*tengo.Index{table.PrimaryKey}, indexes...)
+	}
+	descs := make([]string, len(indexes))
+	for n, idx := range indexes {
+		parts := make([]string, len(idx.Parts))
+		for m, part := range idx.Parts {
+			if part.Expression != "" {
+				parts[m] = "(" + part.Expression + ")"
+			} else if part.PrefixLength > 0 {
+				parts[m] = fmt.Sprintf("%s(%d)", part.ColumnName, part.PrefixLength)
+			} else {
+				parts[m] = part.ColumnName
+			}
+		}
+		if idx.PrimaryKey {
+			descs[n] = "PRIMARY KEY (" + strings.Join(parts, ", ") + ")"
+			continue
+		}
+		var kind string
+		if idx.Unique {
+			kind = "UNIQUE "
+		} else if idx.Type == "FULLTEXT" || idx.Type == "SPATIAL" {
+			kind = idx.Type + " "
+		}
+		descs[n] = fmt.Sprintf("%s%s (%s)", kind, idx.Name, strings.Join(parts, ", "))
+	}
+	return descs
+}
+
+// isPrimaryKeyColumn returns true if col is part of table's primary key.
+func isPrimaryKeyColumn(table *tengo.Table, col *tengo.Column) bool {
+	if table.PrimaryKey == nil {
+		return false
+	}
+	for _, part := range table.PrimaryKey.Parts {
+		if strings.EqualFold(part.ColumnName, col.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignKeyColumn returns true if col is part of any of table's foreign keys.
+func isForeignKeyColumn(table *tengo.Table, col *tengo.Column) bool {
+	for _, fk := range table.ForeignKeys {
+		for _, colName := range fk.ColumnNames {
+			if strings.EqualFold(colName, col.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var plantUMLAliasReplacer = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// plantUMLAlias converts a qualified table name into an identifier usable as a
+// PlantUML entity alias.
+func plantUMLAlias(qualifiedName string) string {
+	return plantUMLAliasReplacer.ReplaceAllString(strings.Replace(qualifiedName, ".", "__", 1), "_")
+}
+
+// ExportPlantUML writes schemas to w as a PlantUML entity relationship diagram,
+// using Information Engineering notation for relationships. Primary key columns
+// are listed first, and mandatory (NOT NULL) columns are marked with an
+// asterisk.
+func ExportPlantUML(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("@startuml\nhide circle\nskinparam linetype ortho\n\n")
+	var rels []relationship
+	for _, schema := range schemas {
+		indent := ""
+		if opts.ERDGroupSchemas {
+			fmt.Fprintf(bw, "package %q {\n", schema.Name)
+			indent = "  "
+		}
+		for _, table := range sortedTables(schema) {
+			qualifiedName := schema.Name + "." + table.Name
+			label := table.Name
+			if !opts.ERDGroupSchemas && len(schemas) > 1 {
+				label = qualifiedName
+			}
+			fmt.Fprintf(bw, "%sentity %q as %s {\n", indent, label, plantUMLAlias(qualifiedName))
+			var keyLines, otherLines []string
+			for _, col := range table.Columns {
+				line := col.Name
+				if !col.Nullable {
+					line = "* " + line
+				}
+				if opts.ERDColumnTypes {
+					line += " : " + col.TypeInDB
+				}
+				if isPrimaryKeyColumn(table, col) {
+					keyLines = append(keyLines, line+" <<PK>>")
+				} else if isForeignKeyColumn(table, col) {
+					otherLines = append(otherLines, line+" <<FK>>")
+				} else {
+					otherLines = append(otherLines, line)
+				}
+			}
+			for _, line := range keyLines {
+				fmt.Fprintf(bw, "%s  %s\n", indent, line)
+			}
+			fmt.Fprintf(bw, "%s  --\n", indent)
+			for _, line := range otherLines {
+				fmt.Fprintf(bw, "%s  %s\n", indent, line)
+			}
+			if opts.ERDIndexes && (table.PrimaryKey != nil || len(table.SecondaryIndexes) > 0) {
+				fmt.Fprintf(bw, "%s  ..\n", indent)
+				for _, desc := range erdIndexes(table) {
+					fmt.Fprintf(bw, "%s  %s\n", indent, desc)
+				}
+			}
+			fmt.Fprintf(bw, "%s}\n", indent)
+			rels = append(rels, relationships(schema.Name, table)...)
+		}
+		if opts.ERDGroupSchemas {
+			bw.WriteString("}\n")
+		}
+		bw.WriteString("\n")
+	}
+
+	for _, rel := range rels {
+		parentEnd, childEnd := "||", "o{"
+		if rel.parentOptional {
+			parentEnd = "|o"
+		}
+		if rel.childUnique {
+			childEnd = "o|"
+		}
+		fmt.Fprintf(bw, "%s %s--%s %s : %s\n", plantUMLAlias(rel.parent), parentEnd, childEnd, plantUMLAlias(rel.child), rel.Name)
+	}
+	if len(rels) > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("@enduml\n")
+	return bw.Flush()
+}
+
+// dotQuote returns s as a double-quoted DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// ExportDOT writes schemas to w as a Graphviz DOT entity relationship diagram.
+// Each table is rendered as an HTML-like table label, and relationships use
+// crow's foot arrowheads.
+func ExportDOT(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph schema {\n")
+	bw.WriteString("  graph [rankdir=LR];\n")
+	bw.WriteString("  node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	bw.WriteString("  edge [dir=both, fontname=\"Helvetica\", fontsize=10];\n")
+	var rels []relationship
+	for n, schema := range schemas {
+		indent := "  "
+		bw.WriteString("\n")
+		if opts.ERDGroupSchemas {
+			fmt.Fprintf(bw, "  subgraph %s {\n    label=%s;\n", dotQuote(fmt.Sprintf("cluster_%d", n)), dotQuote(schema.Name))
+			indent = "    "
+		}
+		for _, table := range sortedTables(schema) {
+			qualifiedName := schema.Name + "." + table.Name
+			label := table.Name
+			if !opts.ERDGroupSchemas && len(schemas) > 1 {
+				label = qualifiedName
+			}
+			fmt.Fprintf(bw, "%s%s [label=<\n", indent, dotQuote(qualifiedName))
+			fmt.Fprintf(bw, "%s  <table border=\"0\" cellborder=\"1\" cellspacing=\"0\">\n", indent)
+			fmt.Fprintf(bw, "%s  <tr><td bgcolor=\"lightgrey\"><b>%s</b></td></tr>\n", indent, html.EscapeString(label))
+			for _, col := range table.Columns {
+				text := html.EscapeString(col.Name)
+				if isPrimaryKeyColumn(table, col) {
+					text = "<u>" + text + "</u>"
+				}
+				if opts.ERDColumnTypes {
+					text += " <i>" + html.EscapeString(col.TypeInDB) + "</i>"
+				}
+				if !col.Nullable {
+					text += " *"
+				}
+				fmt.Fprintf(bw, "%s  <tr><td align=\"left\" port=\"%s\">%s</td></tr>\n", indent, html.EscapeString(col.Name), text)
+			}
+			if opts.ERDIndexes {
+				for _, desc := range erdIndexes(table) {
+					fmt.Fprintf(bw, "%s  <tr><td align=\"left\"><font point-size=\"10\">%s</font></td></tr>\n", indent, html.EscapeString(desc))
+				}
+			}
+			fmt.Fprintf(bw, "%s  </table>\n%s>];\n", indent, indent)
+			rels = append(rels, relationships(schema.Name, table)...)
+		}
+		if opts.ERDGroupSchemas {
+			bw.WriteString("  }\n")
+		}
+	}
+
+	if len(rels) > 0 {
+		bw.WriteString("\n")
+	}
+	for _, rel := range rels {
+		child, parent := dotQuote(rel.child), dotQuote(rel.parent)
+		if len(rel.ColumnNames) == 1 {
+			child += ":" + dotQuote(rel.ColumnNames[0])
+			parent += ":" + dotQuote(rel.ReferencedColumnNames[0])
+		}
+		childEnd, parentEnd := "crowodot", "teetee"
+		if rel.childUnique {
+			childEnd = "teeodot"
+		}
+		if rel.parentOptional {
+			parentEnd = "teeodot"
+		}
+		fmt.Fprintf(bw, "  %s -> %s [arrowtail=%s, arrowhead=%s, label=%s];\n", child, parent, childEnd, parentEnd, dotQuote(rel.Name))
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportPlantUML(t *testing.T) {
+	opts := Options{
+		Flavor:          tengo.FlavorMySQL80.Dot(30),
+		ERDColumnTypes:  true,
+		ERDIndexes:      true,
+		ERDGroupSchemas: true,
+	}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("plantuml").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `@startuml
+hide circle
+skinparam linetype ortho
+
+package "app" {
+  entity "posts" as app__posts {
+    * user_id : int unsigned <<PK>>
+    * seq : int unsigned <<PK>>
+    --
+    body : text
+    ..
+    PRIMARY KEY (user_id, seq)
+  }
+  entity "users" as app__users {
+    * id : int unsigned <<PK>>
+    --
+    * email : varchar(100)
+    * status : enum('active','banned')
+    * created_at : timestamp
+    ..
+    PRIMARY KEY (id)
+    UNIQUE email (email)
+  }
+}
+
+app__users ||--o{ app__posts : posts_user
+
+@enduml
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected PlantUML output:\n%s\nExpected:\n%s", actual, expected)
+	}
+
+	// Without types, indexes, or grouping
+	opts.ERDColumnTypes, opts.ERDIndexes, opts.ERDGroupSchemas = false, false, false
+	buf.Reset()
+	if err := ExportPlantUML(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	if actual := buf.String(); strings.Contains(actual, "package") || strings.Contains(actual, " : int") || strings.Contains(actual, "PRIMARY KEY") {
+		t.Errorf("Options not respected in PlantUML output:\n%s", actual)
+	} else if !strings.Contains(actual, "entity \"users\" as app__users {\n  * id <<PK>>\n") {
+		t.Errorf("Unexpected PlantUML output:\n%s", actual)
+	}
+}
+
+func TestRelationships(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	profiles, err := tengo.NewTableBuilder("profiles").
+		Column("id", "int", tengo.NotNull()).
+		Column("user_id", "int").
+		Column("owner_id", "int", tengo.NotNull()).
+		PrimaryKey("id").
+		UniqueIndex("user_id", "user_id").
+		ForeignKey("profile_user", []string{"user_id"}, "users", []string{"id"}, "", "").
+		ForeignKey("profile_owner", []string{"owner_id"}, "other.owners", []string{"id"}, "", "").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	rels := relationships("app", profiles)
+	if len(rels) != 2 {
+		t.Fatalf("Expected 2 relationships, instead found %d", len(rels))
+	}
+	for _, rel := range rels {
+		switch rel.Name {
+		case "profile_user":
+			if !rel.childUnique || !rel.parentOptional || rel.parent != "app.users" {
+				t.Errorf("Unexpected inference for %s: %+v", rel.Name, rel)
+			}
+		case "profile_owner":
+			if rel.childUnique || rel.parentOptional || rel.parent != "other.owners" {
+				t.Errorf("Unexpected inference for %s: %+v", rel.Name, rel)
+			}
+		}
+	}
+}
+
+func TestExportDOT(t *testing.T) {
+	opts := Options{
+		Flavor:          tengo.FlavorMySQL80.Dot(30),
+		ERDColumnTypes:  true,
+		ERDGroupSchemas: true,
+	}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("dot").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	actual := buf.String()
+	expectLines := []string{
+		"digraph schema {\n",
+		"  subgraph \"cluster_0\" {\n    label=\"app\";\n",
+		"    \"app.users\" [label=<\n",
+		"<tr><td align=\"left\" port=\"id\"><u>id</u> <i>int unsigned</i> *</td></tr>\n",
+		"<tr><td align=\"left\" port=\"status\">status <i>enum(&#39;active&#39;,&#39;banned&#39;)</i> *</td></tr>\n",
+		"  \"app.posts\":\"user_id\" -> \"app.users\":\"id\" [arrowtail=crowodot, arrowhead=teetee, label=\"posts_user\"];\n",
+	}
+	for _, line := range expectLines {
+		if !strings.Contains(actual, line) {
+			t.Errorf("Expected DOT output to contain %q, but it did not. Full output:\n%s", line, actual)
+		}
+	}
+	if strings.Contains(actual, "PRIMARY KEY") {
+		t.Errorf("Indexes unexpectedly included in DOT output:\n%s", actual)
+	}
+}
@@ -0,0 +1,210 @@
+// Package export converts schemas to and from the file formats of external
+// design, documentation, and migration tools. Each format registers itself
+// with an exporter and optionally an importer; the export and import commands
+// select a format by name.
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// ExportFunc writes schemas to w in a specific format.
+type ExportFunc func(w io.Writer, schemas []*tengo.Schema, opts Options) error
+
+// ImportFunc reads a document in a specific format from r, returning the
+// tables it defines. The tables should be obtained via tengo.TableBuilder,
+// so that their CreateStatement fields are populated for opts.Flavor.
+type ImportFunc func(r io.Reader, opts Options) ([]*tengo.Table, error)
+
+// UpdateFunc reads an existing document in a specific format from r, and
+// writes it to w with its definitions of schemas brought up to date, retaining
+// any content which is not derived from the schemas.
+type UpdateFunc func(r io.Reader, w io.Writer, schemas []*tengo.Schema, opts Options) error
+
+// Format describes a registered format.
+type Format struct {
+	Name        string
+	Description string
+	Export      ExportFunc // nil if export is not supported
+	Import      ImportFunc // nil if import is not supported
+	Update      UpdateFunc // nil if existing documents cannot be updated
+}
+
+var formats = map[string]*Format{}
+
+// Register adds a format to the registry. It panics if a format with the same
+// name has already been registered.
+func Register(format *Format) {
+	if _, already := formats[format.Name]; already {
+		panic(fmt.Errorf("export format %s registered more than once", format.Name))
+	}
+	formats[format.Name] = format
+}
+
+// Lookup returns the format with the supplied name, or nil if no such format
+// has been registered.
+func Lookup(name string) *Format {
+	return formats[strings.ToLower(name)]
+}
+
+// Names returns the sorted names of registered formats. If importable is true,
+// only formats which support import are included.
+func Names(importable bool) []string {
+	names := make([]string, 0, len(formats))
+	for name, format := range formats {
+		if !importable || format.Import != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options controls the behavior of exporters and importers.
+type Options struct {
+	Flavor tengo.Flavor // flavor used for rendering imported tables
+
+	// Options for entity relationship diagram formats
+	ERDColumnTypes  bool // include column types in each entity
+	ERDIndexes      bool // include a list of indexes in each entity
+	ERDGroupSchemas bool // group entities by schema
+
+	AvroTopicPrefix string // Debezium topic.prefix, used in Avro namespaces
+
+	OpenAPITables *regexp.Regexp // if non-nil, only matching tables are included in OpenAPI output
+}
+
+// AddCommandOptions adds options shared by the export and import commands to
+// the supplied mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("format",
+		mybase.StringOption("format", 'F', "", "Name of the external format: "+strings.Join(Names(false), ", ")),
+		mybase.BoolOption("erd-column-types", 0, true, "Include column types in diagram formats"),
+		mybase.BoolOption("erd-indexes", 0, false, "Include indexes in diagram formats"),
+		mybase.BoolOption("erd-group-schemas", 0, true, "Group tables by schema in diagram formats"),
+		mybase.StringOption("avro-topic-prefix", 0, "", "Debezium topic prefix to use in Avro record namespaces"),
+		mybase.StringOption("openapi-tables", 0, "", "Only include tables with names matching this regular expression in OpenAPI output"),
+	)
+}
+
+// OptionsForDir returns Options based on the configuration of dir.
+func OptionsForDir(dir *fs.Dir) (Options, error) {
+	opts := Options{
+		Flavor:          tengo.ParseFlavor(dir.Config.Get("flavor")),
+		ERDColumnTypes:  dir.Config.GetBool("erd-column-types"),
+		ERDIndexes:      dir.Config.GetBool("erd-indexes"),
+		ERDGroupSchemas: dir.Config.GetBool("erd-group-schemas"),
+		AvroTopicPrefix: dir.Config.Get("avro-topic-prefix"),
+	}
+	if !opts.Flavor.Known() {
+		opts.Flavor = tengo.FlavorMySQL80
+	}
+	var err error
+	if opts.OpenAPITables, err = dir.Config.GetRegexp("openapi-tables"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// sortedTables returns the tables of schema, sorted by name.
+func sortedTables(schema *tengo.Schema) []*tengo.Table {
+	tables := make([]*tengo.Table, len(schema.Tables))
+	copy(tables, schema.Tables)
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables
+}
+
+// EnumValues returns the values of an enum or set column type, unquoted.
+func EnumValues(colType string) []string {
+	open, close := strings.IndexByte(colType, '('), strings.LastIndexByte(colType, ')')
+	if open < 0 || close < open {
+		return nil
+	}
+	var values []string
+	var cur strings.Builder
+	var inQuote bool
+	inner := colType[open+1 : close]
+	for n := 0; n < len(inner); n++ {
+		c := inner[n]
+		switch {
+		case c == '\'' && inQuote && n+1 < len(inner) && inner[n+1] == '\'':
+			cur.WriteByte('\'')
+			n++
+		case c == '\'':
+			if inQuote {
+				values = append(values, cur.String())
+				cur.Reset()
+			}
+			inQuote = !inQuote
+		case c == '\\' && inQuote && n+1 < len(inner):
+			cur.WriteByte(inner[n+1])
+			n++
+		case inQuote:
+			cur.WriteByte(c)
+		}
+	}
+	return values
+}
+
+// columnType is a column type, decomposed into its base type name, its
+// parenthesized arguments, and its modifiers.
+type columnType struct {
+	base     string   // lowercase type name, for example "varchar"
+	args     []string // for example ["10", "2"] for decimal(10,2); enum and set values are unquoted
+	unsigned bool
+	zerofill bool
+}
+
+// parseColumnType decomposes a column type as shown by SHOW CREATE TABLE, for
+// example "int(10) unsigned" or "enum('a','b')".
+func parseColumnType(colType string) columnType {
+	var ct columnType
+	modifiers := colType
+	if open := strings.IndexByte(colType, '('); open > 0 {
+		close := strings.LastIndexByte(colType, ')')
+		ct.base = colType[:open]
+		if ct.base == "enum" || ct.base == "set" {
+			ct.args = EnumValues(colType)
+		} else if close > open {
+			for _, arg := range strings.Split(colType[open+1:close], ",") {
+				ct.args = append(ct.args, strings.TrimSpace(arg))
+			}
+		}
+		modifiers = colType[close+1:]
+	} else {
+		ct.base, _, _ = strings.Cut(colType, " ")
+		modifiers = strings.TrimPrefix(colType, ct.base)
+	}
+	ct.base = strings.ToLower(ct.base)
+	for _, word := range strings.Fields(modifiers) {
+		switch strings.ToLower(word) {
+		case "unsigned":
+			ct.unsigned = true
+		case "zerofill":
+			ct.zerofill = true
+		}
+	}
+	return ct
+}
+
+// arg returns the nth parenthesized argument as an integer, or def if the
+// argument is missing or non-numeric.
+func (ct columnType) arg(n, def int) int {
+	if n < len(ct.args) {
+		if val, err := strconv.Atoi(ct.args[n]); err == nil {
+			return val
+		}
+	}
+	return def
+}
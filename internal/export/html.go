@@ -0,0 +1,422 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements a self-contained HTML report describing schemas, with
+// sortable listings of tables and columns, cross-references between indexes,
+// foreign keys, and the tables they refer to, and a search box. The report has
+// no external dependencies: styles and scripts are inlined.
+
+func init() {
+	Register(&Format{
+		Name:        "html",
+		Description: "Self-contained HTML schema report",
+		Export:      ExportHTML,
+	})
+}
+
+type htmlReport struct {
+	Title        string
+	TableCount   int
+	ColumnCount  int
+	RoutineCount int
+	Schemas      []*htmlSchema
+	Tables       []*htmlTable // all tables of all schemas, for the summary listing
+}
+
+type htmlSchema struct {
+	Name     string
+	Tables   []*htmlTable
+	Routines []htmlRoutine
+}
+
+type htmlTable struct {
+	ID           string
+	Schema       string
+	Name         string
+	Engine       string
+	Comment      string
+	Columns      []htmlColumn
+	Indexes      []htmlIndex
+	ForeignKeys  []htmlForeignKey
+	ReferencedBy []htmlForeignKey
+	Create       string
+}
+
+type htmlColumn struct {
+	Position int
+	Name     string
+	Type     string
+	Nullable bool
+	Default  string
+	Extra    string
+	Keys     []string
+	Comment  string
+}
+
+type htmlIndex struct {
+	Name    string
+	Kind    string
+	Parts   string
+	Comment string
+}
+
+// htmlForeignKey describes a foreign key from the perspective of one side of
+// the relationship: for a table's own foreign keys, Table is the referenced
+// table; for ReferencedBy entries, Table is the table containing the key.
+type htmlForeignKey struct {
+	Name         string
+	Columns      string
+	Table        string
+	TableID      string // empty if the other table is not part of the report
+	OtherColumns string
+	Rules        string
+}
+
+type htmlRoutine struct {
+	Name    string
+	Type    string
+	Params  string
+	Returns string
+	Comment string
+}
+
+// ExportHTML writes schemas to w as a single self-contained HTML document.
+// Tables are listed by schema and then name, and each foreign key links to
+// the referenced table, if that table is also included in the report.
+func ExportHTML(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	report := &htmlReport{}
+	var names []string
+	tablesByName := make(map[string]*htmlTable)
+	for _, schema := range schemas {
+		names = append(names, schema.Name)
+		hs := &htmlSchema{Name: schema.Name}
+		for _, table := range sortedTables(schema) {
+			ht := htmlTableFor(schema.Name, table, opts.Flavor)
+			hs.Tables = append(hs.Tables, ht)
+			tablesByName[schema.Name+"."+table.Name] = ht
+			report.ColumnCount += len(ht.Columns)
+		}
+		routines := make([]*tengo.Routine, len(schema.Routines))
+		copy(routines, schema.Routines)
+		sort.Slice(routines, func(i, j int) bool {
+			return routines[i].Name < routines[j].Name
+		})
+		for _, r := range routines {
+			hs.Routines = append(hs.Routines, htmlRoutine{
+				Name:    r.Name,
+				Type:    string(r.Type),
+				Params:  r.ParamString,
+				Returns: r.ReturnDataType,
+				Comment: r.Comment,
+			})
+		}
+		report.TableCount += len(hs.Tables)
+		report.RoutineCount += len(hs.Routines)
+		report.Schemas = append(report.Schemas, hs)
+		report.Tables = append(report.Tables, hs.Tables...)
+	}
+	report.Title = "Schema report: " + strings.Join(names, ", ")
+
+	// Link each foreign key to its referenced table, and record the reverse
+	// reference on that table
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			child := tablesByName[schema.Name+"."+table.Name]
+			for n, rel := range relationships(schema.Name, table) {
+				parent := tablesByName[rel.parent]
+				if parent == nil {
+					continue
+				}
+				child.ForeignKeys[n].TableID = parent.ID
+				parent.ReferencedBy = append(parent.ReferencedBy, htmlForeignKey{
+					Name:         rel.Name,
+					Columns:      strings.Join(rel.ReferencedColumnNames, ", "),
+					Table:        rel.child,
+					TableID:      child.ID,
+					OtherColumns: strings.Join(rel.ColumnNames, ", "),
+					Rules:        child.ForeignKeys[n].Rules,
+				})
+			}
+		}
+	}
+	return htmlTemplate.Execute(w, report)
+}
+
+// htmlTableFor converts table into its report representation. Foreign key
+// links are populated separately, once all tables are known.
+func htmlTableFor(schemaName string, table *tengo.Table, flavor tengo.Flavor) *htmlTable {
+	ht := &htmlTable{
+		ID:      htmlID(schemaName, table.Name),
+		Schema:  schemaName,
+		Name:    table.Name,
+		Engine:  table.Engine,
+		Comment: table.Comment,
+		Create:  table.CreateStatement,
+	}
+	uniqueCols := make(map[string]bool)
+	for _, idx := range table.SecondaryIndexes {
+		if idx.Unique {
+			for _, part := range idx.Parts {
+				uniqueCols[part.ColumnName] = true
+			}
+		}
+	}
+	for n, col := range table.Columns {
+		hc := htmlColumn{
+			Position: n + 1,
+			Name:     col.Name,
+			Type:     col.TypeInDB,
+			Nullable: col.Nullable,
+			Default:  col.Default,
+			Comment:  col.Comment,
+		}
+		var extra []string
+		if col.AutoIncrement {
+			extra = append(extra, "auto_increment")
+		}
+		if col.OnUpdate != "" {
+			extra = append(extra, "on update "+col.OnUpdate)
+		}
+		if col.GenerationExpr != "" {
+			kind := "stored"
+			if col.Virtual {
+				kind = "virtual"
+			}
+			extra = append(extra, fmt.Sprintf("generated (%s) %s", col.GenerationExpr, kind))
+		}
+		if col.Invisible {
+			extra = append(extra, "invisible")
+		}
+		hc.Extra = strings.Join(extra, ", ")
+		if isPrimaryKeyColumn(table, col) {
+			hc.Keys = append(hc.Keys, "PK")
+		}
+		if uniqueCols[col.Name] {
+			hc.Keys = append(hc.Keys, "UK")
+		}
+		if isForeignKeyColumn(table, col) {
+			hc.Keys = append(hc.Keys, "FK")
+		}
+		ht.Columns = append(ht.Columns, hc)
+	}
+
+	indexes := table.SecondaryIndexes
+	if table.PrimaryKey != nil {
+		indexes = append([]*tengo.Index{table.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		hi := htmlIndex{Name: idx.Name, Kind: "INDEX", Comment: idx.Comment}
+		if idx.PrimaryKey {
+			hi.Kind = "PRIMARY KEY"
+		} else if idx.Unique {
+			hi.Kind = "UNIQUE"
+		} else if idx.Type != "" && idx.Type != "BTREE" {
+			hi.Kind = idx.Type
+		}
+		parts := make([]string, len(idx.Parts))
+		for n := range idx.Parts {
+			parts[n] = strings.ReplaceAll(idx.Parts[n].Definition(flavor), "`", "")
+		}
+		hi.Parts = strings.Join(parts, ", ")
+		ht.Indexes = append(ht.Indexes, hi)
+	}
+
+	for _, fk := range table.ForeignKeys {
+		referenced := fk.ReferencedTableName
+		if fk.ReferencedSchemaName != "" {
+			referenced = fk.ReferencedSchemaName + "." + referenced
+		} else {
+			referenced = schemaName + "." + referenced
+		}
+		var rules []string
+		if fk.UpdateRule != "" && fk.UpdateRule != "RESTRICT" && fk.UpdateRule != "NO ACTION" {
+			rules = append(rules, "ON UPDATE "+fk.UpdateRule)
+		}
+		if fk.DeleteRule != "" && fk.DeleteRule != "RESTRICT" && fk.DeleteRule != "NO ACTION" {
+			rules = append(rules, "ON DELETE "+fk.DeleteRule)
+		}
+		ht.ForeignKeys = append(ht.ForeignKeys, htmlForeignKey{
+			Name:         fk.Name,
+			Columns:      strings.Join(fk.ColumnNames, ", "),
+			Table:        referenced,
+			OtherColumns: strings.Join(fk.ReferencedColumnNames, ", "),
+			Rules:        strings.Join(rules, " "),
+		})
+	}
+	return ht
+}
+
+// htmlID returns an element ID for a table, which is unique for each distinct
+// combination of schema and table name. Characters other than ASCII letters,
+// digits, and dashes are hex-escaped with an underscore prefix.
+func htmlID(schemaName, tableName string) string {
+	var b strings.Builder
+	b.WriteString("table-")
+	for _, c := range []byte(schemaName + "." + tableName) {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' {
+			b.WriteByte(c)
+		} else {
+			b.WriteString("_" + strconv.FormatUint(uint64(c), 16))
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="Skeema">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 2em 2em; color: #222; }
+header { position: sticky; top: 0; background: #fff; padding: 1em 0; border-bottom: 1px solid #ccc; }
+h1 { margin: 0 0 0.5em; font-size: 1.5em; }
+h2 { margin-top: 2em; border-bottom: 2px solid #369; }
+h3 { margin-top: 1.5em; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.6em; text-align: left; vertical-align: top; }
+th { background: #eef2f7; cursor: pointer; user-select: none; }
+th.sorted-asc::after { content: " \25B2"; }
+th.sorted-desc::after { content: " \25BC"; }
+code, pre { font-family: Menlo, Consolas, monospace; font-size: 0.9em; }
+pre { background: #f6f8fa; padding: 0.75em; overflow-x: auto; }
+.key { display: inline-block; padding: 0 0.3em; margin-right: 0.2em; border-radius: 3px; font-size: 0.8em; background: #ddd; }
+.key-PK { background: #f5d76e; }
+.key-UK { background: #a9d8f5; }
+.key-FK { background: #c5e8b7; }
+.comment { color: #666; }
+.hidden { display: none; }
+#search { width: 24em; padding: 0.3em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search tables and columns" autocomplete="off">
+<span>Tables: {{.TableCount}}, columns: {{.ColumnCount}}, routines: {{.RoutineCount}}</span>
+</header>
+
+<h2>Tables</h2>
+<table class="sortable" id="summary">
+<thead><tr><th>Schema</th><th>Table</th><th>Engine</th><th>Columns</th><th>Indexes</th><th>Foreign keys</th><th>Referenced by</th><th>Comment</th></tr></thead>
+<tbody>
+{{- range .Tables}}
+<tr data-search="{{.Schema}}.{{.Name}}{{range .Columns}} {{.Name}}{{end}}"><td>{{.Schema}}</td><td><a href="#{{.ID}}">{{.Name}}</a></td><td>{{.Engine}}</td><td>{{len .Columns}}</td><td>{{len .Indexes}}</td><td>{{len .ForeignKeys}}</td><td>{{len .ReferencedBy}}</td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{range .Schemas}}
+<h2>Schema {{.Name}}</h2>
+{{- range .Tables}}
+<section class="table-section" id="{{.ID}}" data-search="{{.Schema}}.{{.Name}}{{range .Columns}} {{.Name}}{{end}}">
+<h3>{{.Schema}}.{{.Name}}</h3>
+{{- if .Comment}}
+<p class="comment">{{.Comment}}</p>
+{{- end}}
+<table class="sortable">
+<thead><tr><th>#</th><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Extra</th><th>Keys</th><th>Comment</th></tr></thead>
+<tbody>
+{{- range .Columns}}
+<tr><td>{{.Position}}</td><td>{{.Name}}</td><td><code>{{.Type}}</code></td><td>{{if .Nullable}}yes{{else}}no{{end}}</td><td><code>{{.Default}}</code></td><td>{{.Extra}}</td><td>{{range .Keys}}<span class="key key-{{.}}">{{.}}</span>{{end}}</td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .Indexes}}
+<h4>Indexes</h4>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Type</th><th>Columns</th><th>Comment</th></tr></thead>
+<tbody>
+{{- range .Indexes}}
+<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td><code>{{.Parts}}</code></td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .ForeignKeys}}
+<h4>Foreign keys</h4>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Columns</th><th>References</th><th>Referenced columns</th><th>Rules</th></tr></thead>
+<tbody>
+{{- range .ForeignKeys}}
+<tr><td>{{.Name}}</td><td><code>{{.Columns}}</code></td><td>{{if .TableID}}<a href="#{{.TableID}}">{{.Table}}</a>{{else}}{{.Table}}{{end}}</td><td><code>{{.OtherColumns}}</code></td><td>{{.Rules}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .ReferencedBy}}
+<h4>Referenced by</h4>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Columns</th><th>Table</th><th>Referencing columns</th><th>Rules</th></tr></thead>
+<tbody>
+{{- range .ReferencedBy}}
+<tr><td>{{.Name}}</td><td><code>{{.Columns}}</code></td><td><a href="#{{.TableID}}">{{.Table}}</a></td><td><code>{{.OtherColumns}}</code></td><td>{{.Rules}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Create}}
+<details><summary>CREATE TABLE</summary>
+<pre>{{.Create}}</pre>
+</details>
+{{- end}}
+</section>
+{{- end}}
+{{- if .Routines}}
+<h3>Routines</h3>
+<table class="sortable">
+<thead><tr><th>Name</th><th>Type</th><th>Parameters</th><th>Returns</th><th>Comment</th></tr></thead>
+<tbody>
+{{- range .Routines}}
+<tr data-search="{{.Name}}"><td>{{.Name}}</td><td>{{.Type}}</td><td><code>{{.Params}}</code></td><td><code>{{.Returns}}</code></td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{end}}
+<script>
+(function() {
+  function cellValue(row, n) {
+    var text = row.cells[n].textContent.trim();
+    var num = parseFloat(text);
+    return (text !== "" && !isNaN(num) && String(num) === text) ? num : text.toLowerCase();
+  }
+  document.querySelectorAll("table.sortable th").forEach(function(th) {
+    th.addEventListener("click", function() {
+      var table = th.closest("table"), tbody = table.tBodies[0], n = th.cellIndex;
+      var desc = th.classList.contains("sorted-asc");
+      table.querySelectorAll("th").forEach(function(other) { other.classList.remove("sorted-asc", "sorted-desc"); });
+      th.classList.add(desc ? "sorted-desc" : "sorted-asc");
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function(a, b) {
+        var x = cellValue(a, n), y = cellValue(b, n);
+        var cmp = (typeof x === typeof y) ? (x < y ? -1 : x > y ? 1 : 0) : (typeof x === "number" ? -1 : 1);
+        return desc ? -cmp : cmp;
+      });
+      rows.forEach(function(row) { tbody.appendChild(row); });
+    });
+  });
+  document.getElementById("search").addEventListener("input", function(e) {
+    var terms = e.target.value.toLowerCase().split(/\s+/).filter(Boolean);
+    document.querySelectorAll("[data-search]").forEach(function(el) {
+      var haystack = el.getAttribute("data-search").toLowerCase();
+      var match = terms.every(function(term) { return haystack.indexOf(term) >= 0; });
+      el.classList.toggle("hidden", !match);
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportHTML(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	schema.Routines = []*tengo.Routine{
+		{Name: "purge", Type: tengo.ObjectTypeProc, ParamString: "IN days int", Comment: "<cleanup>"},
+	}
+	var buf bytes.Buffer
+	if err := Lookup("html").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	actual := buf.String()
+	expectedSubstrings := []string{
+		"<title>Schema report: app</title>",
+		"Tables: 2, columns: 7, routines: 1",
+		// Summary listing, sorted by table name
+		`<td><a href="#table-app_2eposts">posts</a></td><td>InnoDB</td><td>3</td><td>1</td><td>1</td><td>0</td>`,
+		`<td><a href="#table-app_2eusers">users</a></td><td>InnoDB</td><td>4</td><td>2</td><td>0</td><td>1</td><td class="comment">registered users</td>`,
+		// Column details with key badges
+		`<tr><td>1</td><td>id</td><td><code>int unsigned</code></td><td>no</td><td><code></code></td><td>auto_increment</td><td><span class="key key-PK">PK</span></td>`,
+		`<td>email</td><td><code>varchar(100)</code></td><td>no</td><td><code></code></td><td></td><td><span class="key key-UK">UK</span></td><td class="comment">login address</td>`,
+		`<td>user_id</td><td><code>int unsigned</code></td><td>no</td><td><code></code></td><td></td><td><span class="key key-PK">PK</span><span class="key key-FK">FK</span></td>`,
+		// Indexes
+		`<tr><td>PRIMARY</td><td>PRIMARY KEY</td><td><code>user_id, seq</code></td>`,
+		`<tr><td>email</td><td>UNIQUE</td><td><code>email</code></td>`,
+		// Foreign key cross-references in both directions
+		`<tr><td>posts_user</td><td><code>user_id</code></td><td><a href="#table-app_2eusers">app.users</a></td><td><code>id</code></td><td>ON DELETE CASCADE</td></tr>`,
+		`<tr><td>posts_user</td><td><code>id</code></td><td><a href="#table-app_2eposts">app.posts</a></td><td><code>user_id</code></td><td>ON DELETE CASCADE</td></tr>`,
+		// Routines, with escaping
+		`<tr data-search="purge"><td>purge</td><td>procedure</td><td><code>IN days int</code></td><td><code></code></td><td class="comment">&lt;cleanup&gt;</td></tr>`,
+	}
+	for _, expected := range expectedSubstrings {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected HTML output to contain %s, but it did not", expected)
+		}
+	}
+	if strings.Index(actual, `id="table-app_2eposts"`) > strings.Index(actual, `id="table-app_2eusers"`) {
+		t.Error("Expected table sections to be sorted by name")
+	}
+}
+
+func TestHTMLID(t *testing.T) {
+	cases := map[[2]string]string{
+		{"app", "users"}:      "table-app_2eusers",
+		{"my-db", "a_b"}:      "table-my-db_2ea_5fb",
+		{"app", "weird name"}: "table-app_2eweird_20name",
+		{"a.b", "c"}:          "table-a_2eb_2ec",
+	}
+	for input, expected := range cases {
+		if actual := htmlID(input[0], input[1]); actual != expected {
+			t.Errorf("htmlID(%q, %q): expected %q, found %q", input[0], input[1], expected, actual)
+		}
+	}
+}
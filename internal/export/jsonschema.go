@@ -0,0 +1,286 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to JSON Schema (draft 2020-12), describing
+// the shape of each table's rows. See https://json-schema.org for the
+// specification.
+
+func init() {
+	Register(&Format{
+		Name:        "jsonschema",
+		Description: "JSON Schema document for each table",
+		Export:      ExportJSONSchema,
+	})
+}
+
+// jsonObject is a JSON object which preserves the order of its keys when
+// marshaled.
+type jsonObject []jsonField
+
+type jsonField struct {
+	key string
+	val interface{}
+}
+
+// set adds or replaces a key in the object.
+func (obj *jsonObject) set(key string, val interface{}) {
+	for n := range *obj {
+		if (*obj)[n].key == key {
+			(*obj)[n].val = val
+			return
+		}
+	}
+	*obj = append(*obj, jsonField{key, val})
+}
+
+// MarshalJSON satisfies encoding/json.Marshaler.
+func (obj jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, field := range obj {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(field.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeJSON writes v to w as indented JSON, followed by a newline.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ExportJSONSchema writes schemas to w as a single JSON Schema document. Each
+// table is described by a separate embedded schema resource in $defs, with its
+// own $id, so that it may be referenced or extracted individually. Table names
+// are qualified with their schema name if more than one schema is supplied.
+func ExportJSONSchema(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	defs := jsonObject{}
+	var titles []string
+	for _, schema := range schemas {
+		titles = append(titles, schema.Name)
+		for _, table := range sortedTables(schema) {
+			name := table.Name
+			if len(schemas) > 1 {
+				name = schema.Name + "." + table.Name
+			}
+			def := tableJSONSchema(table)
+			def = append(jsonObject{{"$id", name + ".schema.json"}}, def...)
+			defs.set(name, def)
+		}
+	}
+	doc := jsonObject{
+		{"$schema", "https://json-schema.org/draft/2020-12/schema"},
+		{"title", strings.Join(titles, ", ")},
+		{"$defs", defs},
+	}
+	return writeJSON(w, doc)
+}
+
+// tableJSONSchema returns a JSON Schema object describing a row of table.
+// NOT NULL columns are required; auto-increment and generated columns are
+// marked readOnly.
+func tableJSONSchema(table *tengo.Table) jsonObject {
+	obj := jsonObject{{"title", table.Name}}
+	if table.Comment != "" {
+		obj.set("description", table.Comment)
+	}
+	obj.set("type", "object")
+	props := jsonObject{}
+	required := []string{}
+	for _, col := range table.Columns {
+		props.set(col.Name, columnJSONSchema(col))
+		if !col.Nullable {
+			required = append(required, col.Name)
+		}
+	}
+	obj.set("properties", props)
+	obj.set("required", required)
+	obj.set("additionalProperties", false)
+	return obj
+}
+
+// intRanges maps integer column types to their signed minimum and maximum
+// values.
+var intRanges = map[string][2]int64{
+	"tinyint":   {math.MinInt8, math.MaxInt8},
+	"smallint":  {math.MinInt16, math.MaxInt16},
+	"mediumint": {-8388608, 8388607},
+	"int":       {math.MinInt32, math.MaxInt32},
+	"integer":   {math.MinInt32, math.MaxInt32},
+	"bigint":    {math.MinInt64, math.MaxInt64},
+}
+
+// textMaxLengths maps text column types to their maximum length in bytes.
+var textMaxLengths = map[string]uint64{
+	"tinytext":   255,
+	"text":       65535,
+	"mediumtext": 16777215,
+	"longtext":   4294967295,
+}
+
+// columnJSONSchema returns a JSON Schema object describing the values of col.
+func columnJSONSchema(col *tengo.Column) jsonObject {
+	ct := parseColumnType(col.TypeInDB)
+	obj := jsonObject{}
+	description := col.Comment
+	var typ string
+	switch ct.base {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		if ct.base == "tinyint" && ct.arg(0, 0) == 1 && !ct.unsigned {
+			typ = "boolean"
+			break
+		}
+		typ = "integer"
+		bounds := intRanges[ct.base]
+		if ct.unsigned {
+			obj.set("minimum", 0)
+			obj.set("maximum", uint64(bounds[1])*2+1)
+		} else {
+			obj.set("minimum", bounds[0])
+			obj.set("maximum", bounds[1])
+		}
+	case "year":
+		typ = "integer"
+		obj.set("minimum", 1901)
+		obj.set("maximum", 2155)
+	case "bit":
+		if bits := ct.arg(0, 1); bits == 1 {
+			typ = "boolean"
+		} else {
+			typ = "integer"
+			obj.set("minimum", 0)
+			obj.set("maximum", uint64(1)<<uint(bits)-1)
+		}
+	case "float", "double", "real":
+		typ = "number"
+		if ct.unsigned {
+			obj.set("minimum", 0)
+		}
+	case "decimal", "numeric":
+		// Represented as strings to avoid loss of precision
+		typ = "string"
+		precision, scale := ct.arg(0, 10), ct.arg(1, 0)
+		sign, intPart, fracPart := "-?", "0?", ""
+		if ct.unsigned {
+			sign = ""
+		}
+		if precision > scale {
+			intPart = fmt.Sprintf("[0-9]{1,%d}", precision-scale)
+		}
+		if scale > 0 {
+			fracPart = fmt.Sprintf(`(\.[0-9]{1,%d})?`, scale)
+		}
+		pattern := "^" + sign + intPart + fracPart + "$"
+		obj.set("pattern", pattern)
+	case "char", "varchar":
+		typ = "string"
+		obj.set("maxLength", ct.arg(0, 1))
+	case "tinytext", "text", "mediumtext", "longtext":
+		typ = "string"
+		obj.set("maxLength", textMaxLengths[ct.base])
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		typ = "string"
+		obj.set("contentEncoding", "base64")
+	case "enum":
+		typ = "string"
+		values := make([]interface{}, 0, len(ct.args)+1)
+		for _, val := range ct.args {
+			values = append(values, val)
+		}
+		if col.Nullable {
+			values = append(values, nil)
+		}
+		obj.set("enum", values)
+	case "set":
+		typ = "string"
+		if description != "" {
+			description += ". "
+		}
+		description += "Comma-separated subset of: " + strings.Join(ct.args, ", ")
+	case "date":
+		typ = "string"
+		obj.set("format", "date")
+	case "datetime", "timestamp":
+		typ = "string"
+		obj.set("format", "date-time")
+	case "time":
+		typ = "string"
+		obj.set("pattern", `^-?[0-9]{1,3}:[0-5][0-9]:[0-5][0-9](\.[0-9]{1,6})?$`)
+	case "json":
+		// Any JSON value is permitted, so no type keyword
+	default: // spatial types and anything unexpected
+		typ = "string"
+		obj.set("contentEncoding", "base64")
+	}
+
+	result := jsonObject{}
+	if typ != "" && col.Nullable {
+		result.set("type", []string{typ, "null"})
+	} else if typ != "" {
+		result.set("type", typ)
+	}
+	if description != "" {
+		result.set("description", description)
+	}
+	result = append(result, obj...)
+	if def, ok := jsonSchemaDefault(col, typ); ok {
+		result.set("default", def)
+	}
+	if col.AutoIncrement || col.GenerationExpr != "" {
+		result.set("readOnly", true)
+	}
+	return result
+}
+
+// jsonSchemaDefault converts a column's default value into a JSON value, if
+// the default is a literal that can be represented in the column's JSON type.
+func jsonSchemaDefault(col *tengo.Column, typ string) (interface{}, bool) {
+	def := col.Default
+	if def == "" || col.AutoIncrement {
+		return nil, false
+	} else if def == "NULL" {
+		return nil, true
+	}
+	if len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		def = strings.ReplaceAll(def[1:len(def)-1], "''", "'")
+	} else if _, err := strconv.ParseFloat(def, 64); err != nil {
+		return nil, false // expression default, such as CURRENT_TIMESTAMP
+	}
+	switch typ {
+	case "string":
+		return def, true
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(def, 64); err == nil {
+			return json.Number(def), true
+		}
+	case "boolean":
+		if def == "0" || def == "1" {
+			return def == "1", true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportJSONSchema(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("jsonschema").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "app",
+  "$defs": {
+    "posts": {
+      "$id": "posts.schema.json",
+      "title": "posts",
+      "type": "object",
+      "properties": {
+        "user_id": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 4294967295
+        },
+        "seq": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 4294967295
+        },
+        "body": {
+          "type": [
+            "string",
+            "null"
+          ],
+          "maxLength": 65535
+        }
+      },
+      "required": [
+        "user_id",
+        "seq"
+      ],
+      "additionalProperties": false
+    },
+    "users": {
+      "$id": "users.schema.json",
+      "title": "users",
+      "description": "registered users",
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer",
+          "minimum": 0,
+          "maximum": 4294967295,
+          "readOnly": true
+        },
+        "email": {
+          "type": "string",
+          "description": "login address",
+          "maxLength": 100
+        },
+        "status": {
+          "type": "string",
+          "enum": [
+            "active",
+            "banned"
+          ],
+          "default": "active"
+        },
+        "created_at": {
+          "type": "string",
+          "format": "date-time"
+        }
+      },
+      "required": [
+        "id",
+        "email",
+        "status",
+        "created_at"
+      ],
+      "additionalProperties": false
+    }
+  }
+}
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected JSON Schema output. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestColumnJSONSchema(t *testing.T) {
+	cases := []struct {
+		colType  string
+		nullable bool
+		def      string
+		expected string
+	}{
+		{"tinyint(1)", false, "1", `{"type":"boolean","default":true}`},
+		{"tinyint", true, "", `{"type":["integer","null"],"minimum":-128,"maximum":127}`},
+		{"bigint unsigned", false, "", `{"type":"integer","minimum":0,"maximum":18446744073709551615}`},
+		{"bit(4)", false, "", `{"type":"integer","minimum":0,"maximum":15}`},
+		{"decimal(5,2)", false, "'1.50'", `{"type":"string","pattern":"^-?[0-9]{1,3}(\\.[0-9]{1,2})?$","default":"1.50"}`},
+		{"decimal(3,3) unsigned", false, "", `{"type":"string","pattern":"^0?(\\.[0-9]{1,3})?$"}`},
+		{"double", false, "2.5", `{"type":"number","default":2.5}`},
+		{"enum('a','b')", true, "", `{"type":["string","null"],"enum":["a","b",null]}`},
+		{"set('x','y')", false, "", `{"type":"string","description":"Comma-separated subset of: x, y"}`},
+		{"date", false, "", `{"type":"string","format":"date"}`},
+		{"varbinary(16)", false, "", `{"type":"string","contentEncoding":"base64"}`},
+		{"json", true, "", `{}`},
+		{"datetime", false, "CURRENT_TIMESTAMP", `{"type":"string","format":"date-time"}`},
+	}
+	for _, c := range cases {
+		col := &tengo.Column{Name: "c", TypeInDB: c.colType, Nullable: c.nullable, Default: c.def}
+		actual, err := json.Marshal(columnJSONSchema(col))
+		if err != nil {
+			t.Errorf("Unexpected error marshaling schema for %s: %v", c.colType, err)
+		} else if string(actual) != c.expected {
+			t.Errorf("Unexpected schema for %s: expected %s, found %s", c.colType, c.expected, actual)
+		}
+	}
+	if !strings.Contains(Lookup("jsonschema").Description, "JSON Schema") {
+		t.Error("Unexpected description for jsonschema format")
+	}
+}
@@ -0,0 +1,169 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to OpenAPI 3.0 component schemas, for use
+// in scaffolding REST API definitions from the database model. See
+// https://spec.openapis.org/oas/v3.0.3#schema-object for the specification.
+
+func init() {
+	Register(&Format{
+		Name:        "openapi",
+		Description: "OpenAPI 3.0 component schemas in YAML",
+		Export:      ExportOpenAPI,
+	})
+}
+
+var openAPIInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// ExportOpenAPI writes schemas to w as an OpenAPI 3.0 document, with a
+// component schema for each table. If opts.OpenAPITables is non-nil, only
+// tables with matching names are included. Component names are qualified with
+// their schema name if more than one schema is supplied. The document has no
+// paths, since these cannot be derived from the schemas.
+func ExportOpenAPI(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	components := jsonObject{}
+	var titles []string
+	for _, schema := range schemas {
+		titles = append(titles, schema.Name)
+		for _, table := range sortedTables(schema) {
+			if opts.OpenAPITables != nil && !opts.OpenAPITables.MatchString(table.Name) {
+				continue
+			}
+			name := table.Name
+			if len(schemas) > 1 {
+				name = schema.Name + "." + table.Name
+			}
+			components.set(openAPIInvalidChars.ReplaceAllString(name, "_"), tableOpenAPISchema(table))
+		}
+	}
+	if len(components) == 0 {
+		return fmt.Errorf("no tables match openapi-tables=%s", opts.OpenAPITables)
+	}
+	doc := jsonObject{
+		{"openapi", "3.0.3"},
+		{"info", jsonObject{{"title", strings.Join(titles, ", ")}, {"version", "1.0.0"}}},
+		{"paths", jsonObject{}},
+		{"components", jsonObject{{"schemas", components}}},
+	}
+	return writeYAML(w, yamlValue(doc))
+}
+
+// tableOpenAPISchema returns an OpenAPI schema object describing a row of
+// table. NOT NULL columns are required; auto-increment and generated columns
+// are marked readOnly.
+func tableOpenAPISchema(table *tengo.Table) jsonObject {
+	obj := jsonObject{{"type", "object"}}
+	if table.Comment != "" {
+		obj.set("description", table.Comment)
+	}
+	props := jsonObject{}
+	var required []string
+	for _, col := range table.Columns {
+		props.set(col.Name, columnOpenAPISchema(col))
+		if !col.Nullable {
+			required = append(required, col.Name)
+		}
+	}
+	obj.set("properties", props)
+	if len(required) > 0 {
+		obj.set("required", required)
+	}
+	return obj
+}
+
+// columnOpenAPISchema returns an OpenAPI schema object describing the values
+// of col. This is derived from the column's JSON Schema, adjusted for the
+// differences in OpenAPI 3.0: nullability is expressed with a separate flag
+// rather than a type array, numeric and binary encodings are expressed with
+// format, and content keywords are not supported.
+func columnOpenAPISchema(col *tengo.Column) jsonObject {
+	ct := parseColumnType(col.TypeInDB)
+	result := jsonObject{}
+	var typ string
+	for _, field := range columnJSONSchema(col) {
+		switch field.key {
+		case "type":
+			if types, ok := field.val.([]string); ok {
+				typ = types[0]
+			} else {
+				typ = field.val.(string)
+			}
+			result.set("type", typ)
+			if typ == "integer" {
+				result.set("format", openAPIIntFormat(ct))
+			} else if typ == "number" && ct.base == "float" {
+				result.set("format", "float")
+			} else if typ == "number" {
+				result.set("format", "double")
+			}
+		case "contentEncoding":
+			result.set("format", "byte")
+		default:
+			result.set(field.key, field.val)
+		}
+	}
+	if col.Nullable {
+		// Applies to untyped JSON columns too
+		result.set("nullable", true)
+	}
+	return result
+}
+
+// openAPIIntFormat returns the OpenAPI format for an integer column type:
+// int32 if all values fit in a signed 32-bit integer, or int64 otherwise.
+func openAPIIntFormat(ct columnType) string {
+	switch ct.base {
+	case "bigint":
+		return "int64"
+	case "int", "integer":
+		if ct.unsigned {
+			return "int64"
+		}
+	case "bit":
+		if ct.arg(0, 1) > 31 {
+			return "int64"
+		}
+	}
+	return "int32"
+}
+
+// yamlValue converts a value built for JSON marshaling into a YAML node.
+func yamlValue(v interface{}) *yamlNode {
+	switch v := v.(type) {
+	case jsonObject:
+		node := &yamlNode{kind: yamlMap}
+		for _, field := range v {
+			node.set(field.key, yamlValue(field.val))
+		}
+		return node
+	case []string:
+		node := &yamlNode{kind: yamlSeq}
+		for _, item := range v {
+			node.items = append(node.items, yamlString(item))
+		}
+		return node
+	case []interface{}:
+		node := &yamlNode{kind: yamlSeq}
+		for _, item := range v {
+			node.items = append(node.items, yamlValue(item))
+		}
+		return node
+	case string:
+		return yamlString(v)
+	case json.Number:
+		return yamlPlain(v.String())
+	case nil:
+		return yamlPlain("null")
+	default: // bools and numbers
+		return yamlPlain(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportOpenAPI(t *testing.T) {
+	opts := Options{
+		Flavor:        tengo.FlavorMySQL80.Dot(30),
+		OpenAPITables: regexp.MustCompile("^users$"),
+	}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("openapi").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `openapi: 3.0.3
+info:
+  title: app
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    users:
+      type: object
+      description: registered users
+      properties:
+        id:
+          type: integer
+          format: int64
+          minimum: 0
+          maximum: 4294967295
+          readOnly: true
+        email:
+          type: string
+          description: login address
+          maxLength: 100
+        status:
+          type: string
+          enum:
+            - active
+            - banned
+          default: active
+        created_at:
+          type: string
+          format: date-time
+      required:
+        - id
+        - email
+        - status
+        - created_at
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected OpenAPI output. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	opts.OpenAPITables = regexp.MustCompile("^nope$")
+	if err := ExportOpenAPI(&buf, []*tengo.Schema{schema}, opts); err == nil {
+		t.Error("Expected error when no tables match, but err was nil")
+	}
+}
+
+func TestColumnOpenAPISchema(t *testing.T) {
+	cases := map[string]string{
+		"tinyint(1)":         `{"type":"boolean","nullable":true}`,
+		"smallint":           `{"type":"integer","format":"int32","minimum":-32768,"maximum":32767,"nullable":true}`,
+		"bigint":             `{"type":"integer","format":"int64","minimum":-9223372036854775808,"maximum":9223372036854775807,"nullable":true}`,
+		"float":              `{"type":"number","format":"float","nullable":true}`,
+		"double unsigned":    `{"type":"number","format":"double","minimum":0,"nullable":true}`,
+		"varbinary(16)":      `{"type":"string","format":"byte","nullable":true}`,
+		"json":               `{"nullable":true}`,
+		"date":               `{"type":"string","format":"date","nullable":true}`,
+		"enum('a','b')":      `{"type":"string","enum":["a","b",null],"nullable":true}`,
+		"bit(40)":            `{"type":"integer","format":"int64","minimum":0,"maximum":1099511627775,"nullable":true}`,
+		"mediumint unsigned": `{"type":"integer","format":"int32","minimum":0,"maximum":16777215,"nullable":true}`,
+	}
+	for colType, expected := range cases {
+		col := &tengo.Column{Name: "c", TypeInDB: colType, Nullable: true}
+		actual, err := columnOpenAPISchema(col).MarshalJSON()
+		if err != nil {
+			t.Errorf("Unexpected error marshaling %s: %v", colType, err)
+		} else if string(actual) != expected {
+			t.Errorf("Unexpected result for %s: expected %s, found %s", colType, expected, actual)
+		}
+	}
+}
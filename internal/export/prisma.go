@@ -0,0 +1,523 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements conversion to a Prisma schema datamodel, mirroring the
+// conventions of `prisma db pull` as closely as possible. See
+// https://www.prisma.io/docs/orm/prisma-schema for the format's documentation.
+
+func init() {
+	Register(&Format{
+		Name:        "prisma",
+		Description: "Prisma schema datamodel",
+		Export:      ExportPrisma,
+	})
+}
+
+// prismaModel tracks a table's Prisma model while rendering.
+type prismaModel struct {
+	schemaName string
+	table      *tengo.Table
+	name       string
+	fields     []prismaField
+	fieldNames map[string]bool
+	attrs      []string // block attributes, such as @@index
+	comments   []string // explanatory comments placed before block attributes
+}
+
+type prismaField struct {
+	name    string
+	typ     string
+	attrs   []string
+	comment string
+}
+
+// addField appends a field to the model, suffixing its name if necessary to
+// keep field names unique.
+func (m *prismaModel) addField(field prismaField) {
+	base := field.name
+	for n := 2; m.fieldNames[strings.ToLower(field.name)]; n++ {
+		field.name = fmt.Sprintf("%s_%d", base, n)
+	}
+	m.fieldNames[strings.ToLower(field.name)] = true
+	m.fields = append(m.fields, field)
+}
+
+// ExportPrisma writes schemas to w as a Prisma schema. Foreign keys become
+// relation fields on both sides, and enum columns become Prisma enums named
+// after their table and column. If multiple schemas are supplied, the
+// multiSchema preview feature is used, and model names are prefixed with their
+// schema name.
+func ExportPrisma(w io.Writer, schemas []*tengo.Schema, opts Options) error {
+	multiSchema := len(schemas) > 1
+	var models []*prismaModel
+	modelsByName := make(map[string]*prismaModel)
+	var enums bytes.Buffer
+	var fullText bool
+
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			m := &prismaModel{
+				schemaName: schema.Name,
+				table:      table,
+				name:       prismaIdentifier(table.Name),
+				fieldNames: make(map[string]bool),
+			}
+			if multiSchema {
+				m.name = prismaIdentifier(schema.Name + "_" + table.Name)
+			}
+			models = append(models, m)
+			modelsByName[schema.Name+"."+table.Name] = m
+
+			for _, col := range table.Columns {
+				field := prismaColumnField(table, col)
+				if strings.HasPrefix(field.typ, "enum(") {
+					enumName := prismaIdentifier(m.name + "_" + col.Name)
+					writePrismaEnum(&enums, enumName, parseColumnType(col.TypeInDB).args, schema.Name, multiSchema)
+					field.typ = strings.Replace(field.typ, "enum()", enumName, 1)
+					if def := col.Default; len(def) >= 2 && def[0] == '\'' {
+						field.attrs = append([]string{"@default(" + prismaEnumValue(strings.ReplaceAll(def[1:len(def)-1], "''", "'")) + ")"}, field.attrs...)
+					}
+				}
+				if isPrimaryKeyColumn(table, col) && len(table.PrimaryKey.Parts) == 1 && table.PrimaryKey.Parts[0].PrefixLength == 0 {
+					field.attrs = append([]string{"@id"}, field.attrs...)
+				}
+				if idx := prismaSingleUnique(table, col); idx != nil {
+					field.attrs = append(field.attrs, fmt.Sprintf("@unique(map: %s)", prismaString(idx.Name)))
+				}
+				if field.name != col.Name {
+					field.attrs = append(field.attrs, "@map("+prismaString(col.Name)+")")
+				}
+				if nativeType := prismaNativeType(col.TypeInDB); nativeType != "" {
+					field.attrs = append(field.attrs, nativeType)
+				}
+				m.addField(field)
+			}
+
+			if pk := table.PrimaryKey; pk != nil && (len(pk.Parts) > 1 || pk.Parts[0].PrefixLength > 0) {
+				m.attrs = append(m.attrs, "@@id("+prismaIndexFields(table, pk)+")")
+			}
+			for _, idx := range table.SecondaryIndexes {
+				if idx.Functional() {
+					m.comments = append(m.comments, fmt.Sprintf("// Index %s uses expressions, which Prisma does not support", prismaString(idx.Name)))
+					continue
+				}
+				var attr string
+				switch {
+				case idx.Type == "FULLTEXT":
+					attr = "@@fulltext"
+					fullText = true
+				case idx.Type == "SPATIAL":
+					m.comments = append(m.comments, fmt.Sprintf("// Spatial index %s is not supported by Prisma", prismaString(idx.Name)))
+					continue
+				case idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].PrefixLength == 0:
+					continue // already handled as a field attribute
+				case idx.Unique:
+					attr = "@@unique"
+				default:
+					attr = "@@index"
+				}
+				m.attrs = append(m.attrs, fmt.Sprintf("%s(%s, map: %s)", attr, prismaIndexFields(table, idx), prismaString(idx.Name)))
+			}
+			if m.name != table.Name {
+				m.attrs = append(m.attrs, "@@map("+prismaString(table.Name)+")")
+			}
+			if multiSchema {
+				m.attrs = append(m.attrs, "@@schema("+prismaString(schema.Name)+")")
+			}
+			if !prismaHasUniqueIdentifier(table) {
+				m.comments = append(m.comments, "/// The underlying table does not contain a valid unique identifier and can therefore currently not be handled by Prisma Client.")
+				m.attrs = append(m.attrs, "@@ignore")
+			}
+		}
+	}
+
+	// Relation fields are added once all models are known, since each foreign
+	// key adds a field to both the child and parent models
+	for _, child := range models {
+		rels := relationships(child.schemaName, child.table)
+		parentCount := make(map[string]int)
+		for _, rel := range rels {
+			parentCount[rel.parent]++
+		}
+		for _, rel := range rels {
+			parent := modelsByName[rel.parent]
+			if parent == nil {
+				child.comments = append(child.comments, fmt.Sprintf("// Foreign key %s references %s, which is not included in this export", prismaString(rel.Name), rel.parent))
+				continue
+			}
+			// Relation names are required whenever a pair of models has more than
+			// one relation, or a model relates to itself
+			var relationName string
+			childFieldName, parentFieldName := parent.name, child.name
+			if parentCount[rel.parent] > 1 || parent == child {
+				relationName = prismaString(rel.Name) + ", "
+				childFieldName = parent.name + "_" + prismaIdentifier(rel.Name)
+				parentFieldName = "other_" + child.name + "_" + prismaIdentifier(rel.Name)
+			}
+			fields := make([]string, len(rel.ColumnNames))
+			for n, colName := range rel.ColumnNames {
+				fields[n] = prismaIdentifier(colName)
+			}
+			references := make([]string, len(rel.ReferencedColumnNames))
+			for n, colName := range rel.ReferencedColumnNames {
+				references[n] = prismaIdentifier(colName)
+			}
+			childType := parent.name
+			if rel.parentOptional {
+				childType += "?"
+			}
+			child.addField(prismaField{
+				name: childFieldName,
+				typ:  childType,
+				attrs: []string{fmt.Sprintf("@relation(%sfields: [%s], references: [%s], onDelete: %s, onUpdate: %s, map: %s)",
+					relationName, strings.Join(fields, ", "), strings.Join(references, ", "),
+					prismaReferentialAction(rel.DeleteRule), prismaReferentialAction(rel.UpdateRule), prismaString(rel.Name))},
+			})
+			parentType := child.name + "[]"
+			if rel.childUnique {
+				parentType = child.name + "?"
+			}
+			var parentAttrs []string
+			if relationName != "" {
+				parentAttrs = []string{"@relation(" + strings.TrimSuffix(relationName, ", ") + ")"}
+			}
+			parent.addField(prismaField{name: parentFieldName, typ: parentType, attrs: parentAttrs})
+		}
+	}
+
+	var previewFeatures []string
+	if fullText {
+		previewFeatures = append(previewFeatures, `"fullTextIndex"`)
+	}
+	if multiSchema {
+		previewFeatures = append(previewFeatures, `"multiSchema"`)
+	}
+	generatorAttrs := []hclAttr{{"provider", `"prisma-client-js"`}}
+	if len(previewFeatures) > 0 {
+		generatorAttrs = append(generatorAttrs, hclAttr{"previewFeatures", "[" + strings.Join(previewFeatures, ", ") + "]"})
+	}
+	datasourceAttrs := []hclAttr{{"provider", `"mysql"`}, {"url", `env("DATABASE_URL")`}}
+	if multiSchema {
+		schemaNames := make([]string, len(schemas))
+		for n, schema := range schemas {
+			schemaNames[n] = prismaString(schema.Name)
+		}
+		datasourceAttrs = append(datasourceAttrs, hclAttr{"schemas", "[" + strings.Join(schemaNames, ", ") + "]"})
+	}
+	var buf bytes.Buffer
+	buf.WriteString("generator client {\n")
+	writeHCLAttrs(&buf, "  ", generatorAttrs)
+	buf.WriteString("}\n\ndatasource db {\n")
+	writeHCLAttrs(&buf, "  ", datasourceAttrs)
+	buf.WriteString("}\n")
+
+	for _, m := range models {
+		buf.WriteString("\n")
+		writePrismaComment(&buf, "", m.table.Comment)
+		fmt.Fprintf(&buf, "model %s {\n", m.name)
+		var nameWidth, typeWidth int
+		for _, field := range m.fields {
+			if len(field.name) > nameWidth {
+				nameWidth = len(field.name)
+			}
+			if len(field.typ) > typeWidth {
+				typeWidth = len(field.typ)
+			}
+		}
+		for _, field := range m.fields {
+			writePrismaComment(&buf, "  ", field.comment)
+			line := fmt.Sprintf("  %-*s %-*s %s", nameWidth, field.name, typeWidth, field.typ, strings.Join(field.attrs, " "))
+			buf.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+		if len(m.attrs) > 0 || len(m.comments) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, comment := range m.comments {
+			buf.WriteString("  " + comment + "\n")
+		}
+		for _, attr := range m.attrs {
+			buf.WriteString("  " + attr + "\n")
+		}
+		buf.WriteString("}\n")
+	}
+	buf.Write(enums.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writePrismaComment(buf *bytes.Buffer, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString(strings.TrimRight(indent+"/// "+line, " ") + "\n")
+	}
+}
+
+func writePrismaEnum(buf *bytes.Buffer, name string, values []string, schemaName string, multiSchema bool) {
+	fmt.Fprintf(buf, "\nenum %s {\n", name)
+	for _, val := range values {
+		if ident := prismaEnumValue(val); ident != val {
+			fmt.Fprintf(buf, "  %s @map(%s)\n", ident, prismaString(val))
+		} else {
+			fmt.Fprintf(buf, "  %s\n", ident)
+		}
+	}
+	if multiSchema {
+		buf.WriteString("\n  @@schema(" + prismaString(schemaName) + ")\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// prismaColumnField returns a field for col, with its scalar type and default
+// value. Enum columns are returned with a placeholder type of "enum()", which
+// the caller replaces with the name of a generated enum.
+func prismaColumnField(table *tengo.Table, col *tengo.Column) prismaField {
+	ct := parseColumnType(col.TypeInDB)
+	field := prismaField{
+		name:    prismaIdentifier(col.Name),
+		typ:     prismaScalarType(ct),
+		comment: col.Comment,
+	}
+	if col.AutoIncrement {
+		field.attrs = append(field.attrs, "@default(autoincrement())")
+	} else if def := prismaDefault(field.typ, col.Default); def != "" && ct.base != "enum" {
+		field.attrs = append(field.attrs, "@default("+def+")")
+	}
+	if ct.base == "enum" {
+		field.typ = "enum()"
+	} else if field.typ == "" {
+		field.typ = "Unsupported(" + prismaString(col.TypeInDB) + ")"
+	}
+	if col.Nullable {
+		field.typ += "?"
+	}
+	return field
+}
+
+// prismaScalarType returns the Prisma scalar type used for a column type, or
+// an empty string if Prisma does not support the type.
+func prismaScalarType(ct columnType) string {
+	switch ct.base {
+	case "tinyint":
+		if ct.arg(0, 0) == 1 && !ct.unsigned {
+			return "Boolean"
+		}
+		return "Int"
+	case "smallint", "mediumint", "int", "integer", "year":
+		return "Int"
+	case "bigint":
+		return "BigInt"
+	case "float", "double", "real":
+		return "Float"
+	case "decimal", "numeric":
+		return "Decimal"
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return "String"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "Bytes"
+	case "bit":
+		if ct.arg(0, 1) == 1 {
+			return "Boolean"
+		}
+		return "Bytes"
+	case "date", "datetime", "timestamp", "time":
+		return "DateTime"
+	case "json":
+		return "Json"
+	case "enum":
+		return "String"
+	}
+	return ""
+}
+
+// prismaNativeType returns the @db attribute for a column type, or an empty
+// string if the type is Prisma's default mapping for its scalar type.
+func prismaNativeType(colType string) string {
+	ct := parseColumnType(colType)
+	var native string
+	switch ct.base {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		name := map[string]string{"tinyint": "TinyInt", "smallint": "SmallInt", "mediumint": "MediumInt", "int": "Int", "integer": "Int", "bigint": "BigInt"}[ct.base]
+		if ct.unsigned {
+			native = "Unsigned" + name
+		} else if ct.base == "tinyint" && ct.arg(0, 0) != 1 {
+			native = name
+		} else if ct.base == "smallint" || ct.base == "mediumint" {
+			native = name
+		}
+	case "year":
+		native = "Year"
+	case "float":
+		native = "Float"
+	case "decimal", "numeric":
+		if p, s := ct.arg(0, 10), ct.arg(1, 0); p != 65 || s != 30 {
+			native = fmt.Sprintf("Decimal(%d, %d)", p, s)
+		}
+	case "char", "varchar", "binary", "varbinary":
+		name := map[string]string{"char": "Char", "varchar": "VarChar", "binary": "Binary", "varbinary": "VarBinary"}[ct.base]
+		if length := ct.arg(0, 1); ct.base != "varchar" || length != 191 {
+			native = fmt.Sprintf("%s(%d)", name, length)
+		}
+	case "tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob":
+		native = map[string]string{"tinytext": "TinyText", "text": "Text", "mediumtext": "MediumText", "longtext": "LongText", "tinyblob": "TinyBlob", "blob": "Blob", "mediumblob": "MediumBlob"}[ct.base]
+	case "bit":
+		native = fmt.Sprintf("Bit(%d)", ct.arg(0, 1))
+	case "date":
+		native = "Date"
+	case "datetime":
+		if fsp := ct.arg(0, 0); fsp != 3 {
+			native = fmt.Sprintf("DateTime(%d)", fsp)
+		}
+	case "timestamp":
+		native = fmt.Sprintf("Timestamp(%d)", ct.arg(0, 0))
+	case "time":
+		native = fmt.Sprintf("Time(%d)", ct.arg(0, 0))
+	}
+	if native == "" {
+		return ""
+	}
+	return "@db." + native
+}
+
+// prismaDefault converts a column default, which is stored as a SQL
+// expression, into the argument of a Prisma @default attribute. An empty
+// string is returned if the column has no default.
+func prismaDefault(scalarType, def string) string {
+	if def == "" || def == "NULL" {
+		return ""
+	}
+	if strings.HasPrefix(strings.ToUpper(def), "CURRENT_TIMESTAMP") || strings.EqualFold(def, "now()") {
+		return "now()"
+	}
+	literal, quoted := def, len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\''
+	if quoted {
+		literal = strings.ReplaceAll(def[1:len(def)-1], "''", "'")
+	}
+	switch scalarType {
+	case "String":
+		if quoted {
+			return prismaString(literal)
+		}
+	case "Int", "BigInt", "Float", "Decimal":
+		if _, err := strconv.ParseFloat(literal, 64); err == nil {
+			return literal
+		}
+	case "Boolean":
+		switch literal {
+		case "1", "b'1'":
+			return "true"
+		case "0", "b'0'":
+			return "false"
+		}
+	}
+	return "dbgenerated(" + prismaString(def) + ")"
+}
+
+// prismaReferentialAction converts a foreign key rule to a Prisma referential
+// action.
+func prismaReferentialAction(rule string) string {
+	switch rule {
+	case "CASCADE":
+		return "Cascade"
+	case "SET NULL":
+		return "SetNull"
+	case "SET DEFAULT":
+		return "SetDefault"
+	case "NO ACTION":
+		return "NoAction"
+	}
+	return "Restrict"
+}
+
+// prismaSingleUnique returns the unique secondary index consisting solely of
+// col, if any.
+func prismaSingleUnique(table *tengo.Table, col *tengo.Column) *tengo.Index {
+	for _, idx := range table.SecondaryIndexes {
+		if idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].PrefixLength == 0 && strings.EqualFold(idx.Parts[0].ColumnName, col.Name) && idx.Type != "FULLTEXT" {
+			return idx
+		}
+	}
+	return nil
+}
+
+// prismaHasUniqueIdentifier returns true if table has a primary key, or a
+// unique index consisting only of NOT NULL columns, which Prisma requires in
+// order to use the table's model.
+func prismaHasUniqueIdentifier(table *tengo.Table) bool {
+	if table.PrimaryKey != nil {
+		return true
+	}
+	cols := table.ColumnsByName()
+	for _, idx := range table.SecondaryIndexes {
+		if !idx.Unique || idx.Functional() {
+			continue
+		}
+		valid := true
+		for _, part := range idx.Parts {
+			if col := cols[part.ColumnName]; col == nil || col.Nullable {
+				valid = false
+			}
+		}
+		if valid {
+			return true
+		}
+	}
+	return false
+}
+
+// prismaIndexFields returns the bracketed field list of an index, for use in
+// @@id, @@unique, @@index, or @@fulltext.
+func prismaIndexFields(table *tengo.Table, idx *tengo.Index) string {
+	fields := make([]string, len(idx.Parts))
+	for n, part := range idx.Parts {
+		var args []string
+		if part.PrefixLength > 0 {
+			args = append(args, fmt.Sprintf("length: %d", part.PrefixLength))
+		}
+		if part.Descending {
+			args = append(args, "sort: Desc")
+		}
+		fields[n] = prismaIdentifier(part.ColumnName)
+		if len(args) > 0 {
+			fields[n] += "(" + strings.Join(args, ", ") + ")"
+		}
+	}
+	return "[" + strings.Join(fields, ", ") + "]"
+}
+
+var prismaInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// prismaIdentifier converts name into a valid Prisma model, field, or enum
+// value name, by replacing invalid characters with underscores and ensuring
+// it begins with a letter.
+func prismaIdentifier(name string) string {
+	ident := prismaInvalidChars.ReplaceAllString(name, "_")
+	if ident == "" || !(ident[0] >= 'A' && ident[0] <= 'Z' || ident[0] >= 'a' && ident[0] <= 'z') {
+		ident = "x" + ident
+	}
+	return ident
+}
+
+// prismaEnumValue converts an enum value into a valid Prisma enum value name.
+func prismaEnumValue(val string) string {
+	if val == "" {
+		return "EMPTY_ENUM_VALUE"
+	}
+	return prismaIdentifier(val)
+}
+
+// prismaString returns s as a double-quoted Prisma string literal.
+func prismaString(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestExportPrisma(t *testing.T) {
+	opts := Options{Flavor: tengo.FlavorMySQL80.Dot(30)}
+	schema := testSchema(t, opts.Flavor)
+	var buf bytes.Buffer
+	if err := Lookup("prisma").Export(&buf, []*tengo.Schema{schema}, opts); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	expected := `generator client {
+  provider = "prisma-client-js"
+}
+
+datasource db {
+  provider = "mysql"
+  url      = env("DATABASE_URL")
+}
+
+model posts {
+  user_id Int     @db.UnsignedInt
+  seq     Int     @db.UnsignedInt
+  body    String? @db.Text
+  users   users   @relation(fields: [user_id], references: [id], onDelete: Cascade, onUpdate: NoAction, map: "posts_user")
+
+  @@id([user_id, seq])
+}
+
+/// registered users
+model users {
+  id         Int          @id @default(autoincrement()) @db.UnsignedInt
+  /// login address
+  email      String       @unique(map: "email") @db.VarChar(100)
+  status     users_status @default(active)
+  created_at DateTime     @default(now()) @db.Timestamp(0)
+  posts      posts[]
+}
+
+enum users_status {
+  active
+  banned
+}
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected Prisma output:\n%s\nExpected:\n%s", actual, expected)
+	}
+}
+
+func TestExportPrismaEdgeCases(t *testing.T) {
+	flavor := tengo.FlavorMySQL80.Dot(30)
+	people, err := tengo.NewTableBuilder("people").
+		Column("id", "bigint", tengo.NotNull()).
+		Column("mother_id", "bigint").
+		Column("father_id", "bigint").
+		Column("active", "tinyint(1)", tengo.NotNull(), tengo.DefaultExpr("'1'")).
+		Column("bio", "text").
+		Column("location", "point").
+		PrimaryKey("id").
+		Index("bio_ft", "bio").
+		ForeignKey("mother", []string{"mother_id"}, "people", []string{"id"}, "set null", "").
+		ForeignKey("father", []string{"father_id"}, "people", []string{"id"}, "set null", "").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	people.SecondaryIndexes[0].Type = "FULLTEXT"
+	logTable, err := tengo.NewTableBuilder("event-log").
+		Column("person_id", "bigint").
+		Column("price", "decimal(10,2)", tengo.DefaultExpr("'0.00'")).
+		ForeignKey("log_person", []string{"person_id"}, "people", []string{"id"}, "", "").
+		Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	schema := &tengo.Schema{Name: "app", Tables: []*tengo.Table{people, logTable}}
+	var buf bytes.Buffer
+	if err := ExportPrisma(&buf, []*tengo.Schema{schema}, Options{Flavor: flavor}); err != nil {
+		t.Fatalf("Unexpected error from export: %v", err)
+	}
+	actual := buf.String()
+	expectLines := []string{
+		`  previewFeatures = ["fullTextIndex"]`,
+		"model event_log {\n",
+		"  person_id BigInt?\n",
+		"  price     Decimal? @default(0.00) @db.Decimal(10, 2)\n",
+		"  people    people?  @relation(fields: [person_id], references: [id], onDelete: NoAction, onUpdate: NoAction, map: \"log_person\")\n",
+		"  /// The underlying table does not contain a valid unique identifier and can therefore currently not be handled by Prisma Client.\n  @@map(\"event-log\")\n  @@ignore\n",
+		"  active              Boolean               @default(true)\n",
+		"  location            Unsupported(\"point\")?\n",
+		"  people_mother       people?               @relation(\"mother\", fields: [mother_id], references: [id], onDelete: SetNull, onUpdate: NoAction, map: \"mother\")\n",
+		"  other_people_mother people[]              @relation(\"mother\")\n",
+		"  event_log           event_log[]\n",
+		"  @@fulltext([bio], map: \"bio_ft\")\n",
+	}
+	for _, line := range expectLines {
+		if !strings.Contains(actual, line) {
+			t.Errorf("Expected Prisma output to contain %q, but it did not. Full output:\n%s", line, actual)
+		}
+	}
+}
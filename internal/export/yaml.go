@@ -0,0 +1,515 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// This file implements reading and writing of a subset of YAML: block-style
+// mappings and sequences, plain and quoted scalars, literal and folded block
+// scalars, and flow sequences and mappings of scalars. This is sufficient for
+// updating configuration files of external tools which are typically written
+// by hand in this style. Comments are not preserved.
+
+type yamlKind int
+
+const (
+	yamlScalar yamlKind = iota
+	yamlMap
+	yamlSeq
+)
+
+// yamlNode is a node of a YAML document. Mappings retain the order of their
+// keys.
+type yamlNode struct {
+	kind   yamlKind
+	value  string      // scalars only
+	str    bool        // scalars only: true if value must be a string, rather than a bool, number, or null
+	keys   []string    // mappings only
+	values []*yamlNode // mappings only, corresponding to keys
+	items  []*yamlNode // sequences only
+}
+
+// yamlString returns a scalar node for a string value.
+func yamlString(s string) *yamlNode {
+	return &yamlNode{kind: yamlScalar, value: s, str: true}
+}
+
+// yamlPlain returns a scalar node for a non-string value, such as a number.
+func yamlPlain(s string) *yamlNode {
+	return &yamlNode{kind: yamlScalar, value: s}
+}
+
+// get returns the value of key in a mapping node, or nil if the node is not a
+// mapping or lacks the key.
+func (n *yamlNode) get(key string) *yamlNode {
+	if n == nil || n.kind != yamlMap {
+		return nil
+	}
+	for i, k := range n.keys {
+		if k == key {
+			return n.values[i]
+		}
+	}
+	return nil
+}
+
+// set adds or replaces key in a mapping node.
+func (n *yamlNode) set(key string, val *yamlNode) {
+	for i, k := range n.keys {
+		if k == key {
+			n.values[i] = val
+			return
+		}
+	}
+	n.keys = append(n.keys, key)
+	n.values = append(n.values, val)
+}
+
+// remove deletes key from a mapping node, if present.
+func (n *yamlNode) remove(key string) {
+	for i, k := range n.keys {
+		if k == key {
+			n.keys = append(n.keys[:i], n.keys[i+1:]...)
+			n.values = append(n.values[:i], n.values[i+1:]...)
+			return
+		}
+	}
+}
+
+// scalar returns the value of a scalar node, or an empty string for any other
+// node.
+func (n *yamlNode) scalar() string {
+	if n == nil || n.kind != yamlScalar {
+		return ""
+	}
+	return n.value
+}
+
+type yamlLine struct {
+	num    int // 1-based line number in input
+	indent int
+	text   string // without indentation
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML parses a document from r. An empty document returns an empty
+// mapping.
+func parseYAML(r io.Reader) (*yamlNode, error) {
+	var p yamlParser
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for num := 1; scanner.Scan(); num++ {
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "---" || text == "..." {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{num: num, indent: len(raw) - len(text), text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return &yamlNode{kind: yamlMap}, nil
+	}
+	node, err := p.parseNode(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content")
+	}
+	return node, nil
+}
+
+func (p *yamlParser) errorf(format string, a ...interface{}) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("YAML line %d: %s", num, fmt.Sprintf(format, a...))
+}
+
+// skipBlank advances past blank lines and comment lines.
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && (p.lines[p.pos].text == "" || p.lines[p.pos].text[0] == '#') {
+		p.pos++
+	}
+}
+
+// isSeqItem returns true if text begins a block sequence item.
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits text of the form "key: value" into its key and value. ok is
+// false if text is not a mapping entry.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := quotedEnd(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		key, err := unquoteYAML(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(text[end+2:]), end+2 == len(text) || text[end+2] == ' '
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		} else if text[i] == ' ' && i+1 < len(text) && text[i+1] == '#' {
+			break
+		}
+	}
+	return "", "", false
+}
+
+// quotedEnd returns the index of the closing quote of the quoted string at the
+// start of text, or -1 if it is not terminated.
+func quotedEnd(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		if quote == '"' && text[i] == '\\' {
+			i++
+		} else if text[i] == quote {
+			if quote == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// parseNode parses the node beginning at the current line, which must have
+// the supplied indentation.
+func (p *yamlParser) parseNode(indent int) (*yamlNode, error) {
+	p.skipBlank()
+	line := p.lines[p.pos]
+	if isSeqItem(line.text) {
+		return p.parseSeq(indent)
+	} else if _, _, ok := splitKey(line.text); ok {
+		return p.parseMap(indent)
+	}
+	p.pos++
+	return parseYAMLScalar(line.text)
+}
+
+func (p *yamlParser) parseSeq(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlSeq}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent || !isSeqItem(line.text) {
+			break
+		} else if line.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		content := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		var item *yamlNode
+		var err error
+		if content == "" || content[0] == '#' {
+			p.pos++
+			if p.skipBlank(); p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				item, err = p.parseNode(p.lines[p.pos].indent)
+			} else {
+				item = yamlPlain("")
+			}
+		} else {
+			// Treat the item's content as if it began on its own line, indented to
+			// its position after the dash
+			p.lines[p.pos] = yamlLine{num: line.num, indent: line.indent + len(line.text) - len(content), text: content}
+			item, err = p.parseNode(p.lines[p.pos].indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseMap(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlMap}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		} else if line.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, p.errorf("expected mapping key")
+		}
+		p.pos++
+		var val *yamlNode
+		var err error
+		switch {
+		case rest == "" || rest[0] == '#':
+			p.skipBlank()
+			if p.pos < len(p.lines) && (p.lines[p.pos].indent > indent || (p.lines[p.pos].indent == indent && isSeqItem(p.lines[p.pos].text))) {
+				val, err = p.parseNode(p.lines[p.pos].indent)
+			} else {
+				val = yamlPlain("")
+			}
+		case rest[0] == '|' || rest[0] == '>':
+			val = p.parseBlockScalar(indent, rest)
+		default:
+			val, err = parseYAMLScalar(rest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.set(key, val)
+	}
+	return node, nil
+}
+
+// parseBlockScalar parses the lines of a literal (|) or folded (>) block
+// scalar, which must be indented more than parentIndent.
+func (p *yamlParser) parseBlockScalar(parentIndent int, header string) *yamlNode {
+	var lines []yamlLine
+	for p.pos < len(p.lines) && (p.lines[p.pos].text == "" || p.lines[p.pos].indent > parentIndent) {
+		lines = append(lines, p.lines[p.pos])
+		p.pos++
+	}
+	// Trailing blank lines belong to the scalar only for chomping purposes
+	for len(lines) > 0 && lines[len(lines)-1].text == "" {
+		lines = lines[:len(lines)-1]
+		p.pos--
+	}
+	contentIndent := -1
+	for _, line := range lines {
+		if line.text != "" && (contentIndent < 0 || line.indent < contentIndent) {
+			contentIndent = line.indent
+		}
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		text := ""
+		if line.text != "" {
+			text = strings.Repeat(" ", line.indent-contentIndent) + line.text
+		}
+		if i > 0 {
+			if header[0] == '>' && text != "" && lines[i-1].text != "" && line.indent == contentIndent {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(text)
+	}
+	value := b.String()
+	if !strings.Contains(header, "-") && value != "" {
+		value += "\n"
+	}
+	return yamlString(value)
+}
+
+// parseYAMLScalar parses a single-line value, which may be a quoted string, a
+// plain scalar, or a flow sequence or mapping of scalars.
+func parseYAMLScalar(text string) (*yamlNode, error) {
+	switch text[0] {
+	case '"', '\'':
+		end := quotedEnd(text)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated quoted string %s", text)
+		}
+		if rest := strings.TrimSpace(text[end+1:]); rest != "" && rest[0] != '#' {
+			return nil, fmt.Errorf("unexpected content after quoted string %s", text)
+		}
+		s, err := unquoteYAML(text[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		return yamlString(s), nil
+	case '[', '{':
+		closing := map[byte]byte{'[': ']', '{': '}'}[text[0]]
+		end := strings.LastIndexByte(text, closing)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated flow collection %s", text)
+		}
+		node := &yamlNode{kind: yamlSeq}
+		if text[0] == '{' {
+			node.kind = yamlMap
+		}
+		for _, item := range splitFlow(text[1:end]) {
+			if item == "" {
+				continue
+			}
+			if node.kind == yamlSeq {
+				itemNode, err := parseYAMLScalar(item)
+				if err != nil {
+					return nil, err
+				}
+				node.items = append(node.items, itemNode)
+				continue
+			}
+			key, rest, ok := splitKey(item)
+			if !ok {
+				return nil, fmt.Errorf("expected key: value in flow mapping %s", text)
+			}
+			val := yamlPlain("")
+			if rest != "" {
+				var err error
+				if val, err = parseYAMLScalar(rest); err != nil {
+					return nil, err
+				}
+			}
+			node.set(key, val)
+		}
+		return node, nil
+	}
+	if pos := strings.Index(text, " #"); pos >= 0 {
+		text = strings.TrimSpace(text[:pos])
+	}
+	return yamlPlain(text), nil
+}
+
+// splitFlow splits the contents of a flow collection on commas, ignoring
+// commas within quoted strings. Nested flow collections are not supported.
+func splitFlow(text string) (items []string) {
+	var start int
+	for i := 0; i < len(text); i++ {
+		if text[i] == '"' || text[i] == '\'' {
+			if end := quotedEnd(text[i:]); end > 0 {
+				i += end
+			}
+		} else if text[i] == ',' {
+			items = append(items, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(text[start:]))
+}
+
+// unquoteYAML decodes a single- or double-quoted YAML string.
+func unquoteYAML(s string) (string, error) {
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return strconv.Unquote(s)
+}
+
+// writeYAML writes node to w in block style, indented by two spaces per level.
+func writeYAML(w io.Writer, node *yamlNode) error {
+	bw := bufio.NewWriter(w)
+	writeYAMLNode(bw, node, 0)
+	return bw.Flush()
+}
+
+func writeYAMLNode(w *bufio.Writer, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch node.kind {
+	case yamlMap:
+		for i, key := range node.keys {
+			val := node.values[i]
+			w.WriteString(pad + yamlScalarText(key, true, indent) + ":")
+			writeYAMLValue(w, val, indent)
+		}
+	case yamlSeq:
+		for _, item := range node.items {
+			w.WriteString(pad + "-")
+			if item.kind == yamlMap && len(item.keys) > 0 {
+				// First key goes on the same line as the dash
+				w.WriteString(" ")
+				first := &yamlNode{kind: yamlMap, keys: item.keys[:1], values: item.values[:1]}
+				rest := &yamlNode{kind: yamlMap, keys: item.keys[1:], values: item.values[1:]}
+				var b strings.Builder
+				bw := bufio.NewWriter(&b)
+				writeYAMLNode(bw, first, indent+2)
+				bw.Flush()
+				w.WriteString(strings.TrimPrefix(b.String(), strings.Repeat(" ", indent+2)))
+				writeYAMLNode(w, rest, indent+2)
+			} else {
+				writeYAMLValue(w, item, indent)
+			}
+		}
+	default:
+		w.WriteString(pad + yamlScalarText(node.value, node.str, indent) + "\n")
+	}
+}
+
+// writeYAMLValue writes a mapping value or sequence item, following a key or
+// dash which has already been written at the supplied indentation.
+func writeYAMLValue(w *bufio.Writer, val *yamlNode, indent int) {
+	switch {
+	case val.kind == yamlMap && len(val.keys) == 0:
+		w.WriteString(" {}\n")
+	case val.kind == yamlSeq && len(val.items) == 0:
+		w.WriteString(" []\n")
+	case val.kind == yamlMap:
+		w.WriteString("\n")
+		writeYAMLNode(w, val, indent+2)
+	case val.kind == yamlSeq:
+		w.WriteString("\n")
+		writeYAMLNode(w, val, indent+2)
+	case val.value == "" && !val.str:
+		w.WriteString("\n")
+	default:
+		w.WriteString(" " + yamlScalarText(val.value, val.str, indent) + "\n")
+	}
+}
+
+// yamlScalarText returns s formatted as a YAML scalar. Strings which would be
+// misinterpreted as plain scalars are double-quoted, and multi-line strings use
+// a literal block scalar indented beyond the supplied level.
+func yamlScalarText(s string, str bool, indent int) string {
+	if !str {
+		return s
+	}
+	if strings.Contains(strings.TrimSuffix(s, "\n"), "\n") && !strings.ContainsAny(s, "\r\t") && !strings.HasPrefix(s, " ") {
+		header := "|-"
+		if strings.HasSuffix(s, "\n") {
+			header = "|"
+		}
+		pad := strings.Repeat(" ", indent+2)
+		lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = pad + line
+			}
+		}
+		return header + "\n" + strings.Join(lines, "\n")
+	}
+	if yamlNeedsQuotes(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// yamlNeedsQuotes returns true if s cannot be represented as a plain scalar
+// string.
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s, "\n\r\t\"") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	return false
+}
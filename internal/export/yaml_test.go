@@ -0,0 +1,79 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	input := `# leading comment
+name: 'it''s'
+quoted: "a \"b\""
+empty:
+plain: hello world # trailing comment
+keywords: [yes, "no", 'null', 'x, y']
+nested:
+  - first
+  -
+    a: 1
+    b: {c: d, "e": 'f'}
+  - - inner
+literal: |
+  line one
+    line two
+folded: >-
+  one
+  two
+`
+	doc, err := parseYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error from parseYAML: %v", err)
+	}
+	if val := doc.get("name").scalar(); val != "it's" {
+		t.Errorf("Unexpected value for name: %q", val)
+	}
+	if val := doc.get("folded").scalar(); val != "one two" {
+		t.Errorf("Unexpected value for folded: %q", val)
+	}
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, doc); err != nil {
+		t.Fatalf("Unexpected error from writeYAML: %v", err)
+	}
+	expected := `name: it's
+quoted: "a \"b\""
+empty:
+plain: hello world
+keywords:
+  - yes
+  - "no"
+  - "null"
+  - x, y
+nested:
+  - first
+  - a: 1
+    b:
+      c: d
+      e: f
+  -
+    - inner
+literal: |
+  line one
+    line two
+folded: one two
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected output from writeYAML. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	// Output must parse back to identical output
+	doc, err = parseYAML(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error re-parsing output: %v", err)
+	}
+	buf.Reset()
+	writeYAML(&buf, doc)
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Output changed after round trip:\n%s", actual)
+	}
+}
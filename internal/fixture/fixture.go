@@ -0,0 +1,320 @@
+// Package fixture dumps and loads the rows of reference data tables, such as
+// small lookup tables, as CSV files. This permits managing seed data alongside
+// the schema definition in version control: `skeema pull` writes each table's
+// current rows, ordered by primary key to keep diffs clean, and `skeema push`
+// upserts the rows into the table.
+package fixture
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// NullValue is the CSV representation of SQL NULL, matching the convention of
+// LOAD DATA and SELECT ... INTO OUTFILE.
+const NullValue = `\N`
+
+// batchSize is the maximum number of rows upserted by each INSERT statement.
+const batchSize = 100
+
+// Options controls which tables are treated as reference data, and where
+// their CSV files are located.
+type Options struct {
+	Dir    string         // directory containing CSV files
+	Tables *regexp.Regexp // nil if no tables are treated as reference data
+	Masks  []MaskRule     // masking to apply to column values when dumping
+}
+
+// AddCommandOptions adds fixture options to the supplied mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("fixture",
+		mybase.StringOption("reference-tables", 0, "", "Regular expression of reference data tables, whose rows are dumped to CSV by pull and upserted by push"),
+		mybase.StringOption("fixture-dir", 0, ".", "Directory for CSV files of reference-tables rows, relative to each schema dir"),
+		mybase.StringOption("fixture-mask", 0, "", "Comma-separated list of table.column=method rules for masking dumped values (methods: null, empty, hash, email)"),
+	)
+}
+
+// OptionsForDir returns Options based on the configuration of dir. If
+// reference-tables is not configured, the returned Options will have a nil
+// Tables field.
+func OptionsForDir(dir *fs.Dir) (opts Options, err error) {
+	opts.Dir = filepath.Join(dir.Path, dir.Config.Get("fixture-dir"))
+	if opts.Tables, err = dir.Config.GetRegexp("reference-tables"); err != nil {
+		return opts, err
+	}
+	opts.Masks, err = ParseMaskRules(dir.Config.GetSlice("fixture-mask", ',', true))
+	return opts, err
+}
+
+// Matches returns the tables of schema which are configured as reference data.
+func (opts Options) Matches(schema *tengo.Schema) (tables []*tengo.Table) {
+	if opts.Tables == nil {
+		return nil
+	}
+	for _, table := range schema.Tables {
+		if opts.Tables.MatchString(table.Name) {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// Path returns the path of the CSV file for the table with the supplied name.
+func (opts Options) Path(tableName string) string {
+	return filepath.Join(opts.Dir, tableName+".csv")
+}
+
+// Dump writes the rows of table to w in CSV format, with a header line of
+// column names. Rows are ordered by primary key, so that output is
+// deterministic. Generated columns are omitted. Values of binary columns are
+// hex-encoded, since they may not be valid UTF-8. Values of columns matching
+// any of masks are obscured, so that production-derived data may be safely
+// committed. The number of rows written is returned.
+func Dump(db *sqlx.DB, table *tengo.Table, w io.Writer, masks []MaskRule) (int, error) {
+	if table.PrimaryKey == nil {
+		return 0, fmt.Errorf("reference data table %s must have a primary key", tengo.EscapeIdentifier(table.Name))
+	}
+	maskers, err := maskFuncs(table, masks)
+	if err != nil {
+		return 0, err
+	}
+	cols := storedColumns(table)
+	colNames := make([]string, len(cols))
+	selectExprs := make([]string, len(cols))
+	for n, col := range cols {
+		colNames[n] = col.Name
+		selectExprs[n] = tengo.EscapeIdentifier(col.Name)
+	}
+	orderBy := make([]string, len(table.PrimaryKey.Parts))
+	for n, part := range table.PrimaryKey.Parts {
+		orderBy[n] = tengo.EscapeIdentifier(part.ColumnName)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", strings.Join(selectExprs, ", "), tengo.EscapeIdentifier(table.Name), strings.Join(orderBy, ", "))
+	rows, err := db.Query(query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	cw := csv.NewWriter(w)
+	cw.Write(colNames)
+	raw := make([]sql.RawBytes, len(cols))
+	dest := make([]interface{}, len(cols))
+	for n := range raw {
+		dest[n] = &raw[n]
+	}
+	record := make([]string, len(cols))
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		for n, col := range cols {
+			if mask := maskers[col.Name]; mask != nil && raw[n] != nil {
+				raw[n] = mask(raw[n])
+			}
+			record[n] = encodeValue(col, raw[n])
+		}
+		if err := cw.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	cw.Flush()
+	return count, cw.Error()
+}
+
+// Load reads CSV rows for table from r, in the format written by Dump, and
+// upserts them into the table in a single transaction. Existing rows with the
+// same primary key or unique key values are updated; rows which are not
+// present in the CSV are left as-is. Columns matching any of masks are not
+// loaded, since their CSV values were obscured by Dump and would otherwise
+// overwrite the real values. The number of rows read is returned.
+func Load(db *sqlx.DB, table *tengo.Table, r io.Reader, masks []MaskRule) (int, error) {
+	cols, rows, err := readRows(table, r)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	if cols, rows, err = omitMasked(table, cols, rows, masks); err != nil {
+		return 0, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var args []interface{}
+		for _, row := range rows[start:end] {
+			args = append(args, row...)
+		}
+		if _, err := tx.Exec(upsertStatement(table.Name, cols, end-start), args...); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	return len(rows), tx.Commit()
+}
+
+// storedColumns returns the columns of table which may be inserted into,
+// which excludes generated columns.
+func storedColumns(table *tengo.Table) []*tengo.Column {
+	cols := make([]*tengo.Column, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		if col.GenerationExpr == "" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// isBinary returns true if values of col are arbitrary bytes, rather than
+// text.
+func isBinary(col *tengo.Column) bool {
+	for _, prefix := range []string{"binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "bit"} {
+		if col.TypeInDB == prefix || strings.HasPrefix(col.TypeInDB, prefix+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeValue returns the CSV representation of a column value.
+func encodeValue(col *tengo.Column, raw sql.RawBytes) string {
+	if raw == nil {
+		return NullValue
+	} else if isBinary(col) {
+		return "0x" + hex.EncodeToString(raw)
+	}
+	return string(raw)
+}
+
+// decodeValue converts the CSV representation of a column value into a query
+// arg.
+func decodeValue(col *tengo.Column, s string) (interface{}, error) {
+	if s == NullValue {
+		return nil, nil
+	} else if !isBinary(col) {
+		return s, nil
+	}
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("value for binary column %s must be hex-encoded with 0x prefix", tengo.EscapeIdentifier(col.Name))
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex value for column %s: %w", tengo.EscapeIdentifier(col.Name), err)
+	}
+	return b, nil
+}
+
+// readRows parses CSV rows for table from r, returning the columns named by
+// the header line and the decoded values of each row.
+func readRows(table *tengo.Table, r io.Reader) (cols []*tengo.Column, rows [][]interface{}, err error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	colsByName := make(map[string]*tengo.Column, len(table.Columns))
+	for _, col := range storedColumns(table) {
+		colsByName[col.Name] = col
+	}
+	for _, name := range header {
+		col := colsByName[name]
+		if col == nil {
+			return nil, nil, fmt.Errorf("CSV header refers to column %s, which does not exist or is generated in table %s", tengo.EscapeIdentifier(name), tengo.EscapeIdentifier(table.Name))
+		}
+		cols = append(cols, col)
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		row := make([]interface{}, len(cols))
+		for n, col := range cols {
+			if row[n], err = decodeValue(col, record[n]); err != nil {
+				line, _ := cr.FieldPos(n)
+				return nil, nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(cols) == 0 && len(rows) > 0 {
+		return nil, nil, errors.New("CSV header does not name any columns")
+	}
+	return cols, rows, nil
+}
+
+// omitMasked removes the columns matching any of masks from cols, along with
+// the corresponding values of rows. An error is returned if a masked column is
+// part of the table's primary key, since rows could not be upserted correctly.
+func omitMasked(table *tengo.Table, cols []*tengo.Column, rows [][]interface{}, masks []MaskRule) ([]*tengo.Column, [][]interface{}, error) {
+	keep := make([]int, 0, len(cols))
+	for n, col := range cols {
+		if matchRule(table, col, masks) == nil {
+			keep = append(keep, n)
+			continue
+		}
+		if table.PrimaryKey == nil {
+			continue
+		}
+		for _, part := range table.PrimaryKey.Parts {
+			if part.ColumnName == col.Name {
+				return nil, nil, fmt.Errorf("cannot load reference data because primary key column %s is masked", tengo.EscapeIdentifier(col.Name))
+			}
+		}
+	}
+	if len(keep) == len(cols) {
+		return cols, rows, nil
+	}
+	keptCols := make([]*tengo.Column, len(keep))
+	for n, pos := range keep {
+		keptCols[n] = cols[pos]
+	}
+	for r, row := range rows {
+		keptRow := make([]interface{}, len(keep))
+		for n, pos := range keep {
+			keptRow[n] = row[pos]
+		}
+		rows[r] = keptRow
+	}
+	return keptCols, rows, nil
+}
+
+// upsertStatement returns an INSERT ... ON DUPLICATE KEY UPDATE statement for
+// rowCount rows of cols, using placeholders for the values.
+func upsertStatement(tableName string, cols []*tengo.Column, rowCount int) string {
+	colNames := make([]string, len(cols))
+	updates := make([]string, len(cols))
+	for n, col := range cols {
+		colNames[n] = tengo.EscapeIdentifier(col.Name)
+		updates[n] = fmt.Sprintf("%s = VALUES(%s)", colNames[n], colNames[n])
+	}
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")"
+	tuples := strings.TrimSuffix(strings.Repeat(tuple+", ", rowCount), ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+		tengo.EscapeIdentifier(tableName), strings.Join(colNames, ", "), tuples, strings.Join(updates, ", "))
+}
@@ -0,0 +1,156 @@
+package fixture
+
+import (
+	"bytes"
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func testTable(t *testing.T) *tengo.Table {
+	t.Helper()
+	table, err := tengo.NewTableBuilder("countries").
+		Column("code", "char(2)", tengo.NotNull()).
+		Column("name", "varchar(50)", tengo.NotNull()).
+		Column("flag", "varbinary(16)").
+		Column("name_upper", "varchar(50)", tengo.Generated("upper(`name`)", true)).
+		PrimaryKey("code").
+		Build(tengo.FlavorMySQL80.Dot(30))
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	return table
+}
+
+func TestOptionsMatches(t *testing.T) {
+	table := testTable(t)
+	other := &tengo.Table{Name: "users"}
+	schema := &tengo.Schema{Name: "app", Tables: []*tengo.Table{table, other}}
+
+	var opts Options
+	if tables := opts.Matches(schema); len(tables) != 0 {
+		t.Errorf("Expected no matches without reference-tables, instead found %d", len(tables))
+	}
+	opts = Options{Dir: "fixtures", Tables: regexp.MustCompile("^countr")}
+	if tables := opts.Matches(schema); len(tables) != 1 || tables[0] != table {
+		t.Errorf("Unexpected result from Matches: %+v", tables)
+	}
+	if path := opts.Path("countries"); path != "fixtures/countries.csv" {
+		t.Errorf("Unexpected result from Path: %q", path)
+	}
+}
+
+func TestEncodeDecodeValue(t *testing.T) {
+	table := testTable(t)
+	name, flag := table.Columns[1], table.Columns[2]
+	cases := []struct {
+		col     *tengo.Column
+		raw     sql.RawBytes
+		encoded string
+	}{
+		{name, sql.RawBytes("Côte d'Ivoire"), "Côte d'Ivoire"},
+		{name, nil, NullValue},
+		{name, sql.RawBytes(""), ""},
+		{flag, sql.RawBytes{0x00, 0xff, 'a'}, "0x00ff61"},
+		{flag, sql.RawBytes{}, "0x"},
+		{flag, nil, NullValue},
+	}
+	for _, c := range cases {
+		if actual := encodeValue(c.col, c.raw); actual != c.encoded {
+			t.Errorf("encodeValue(%s, %v): expected %q, found %q", c.col.Name, c.raw, c.encoded, actual)
+		}
+		decoded, err := decodeValue(c.col, c.encoded)
+		if err != nil {
+			t.Errorf("Unexpected error from decodeValue(%s, %q): %v", c.col.Name, c.encoded, err)
+		} else if c.raw == nil && decoded != nil {
+			t.Errorf("decodeValue(%s, %q): expected nil, found %v", c.col.Name, c.encoded, decoded)
+		} else if b, ok := decoded.([]byte); ok && !bytes.Equal(b, c.raw) {
+			t.Errorf("decodeValue(%s, %q): expected %v, found %v", c.col.Name, c.encoded, c.raw, b)
+		} else if s, ok := decoded.(string); ok && s != string(c.raw) {
+			t.Errorf("decodeValue(%s, %q): expected %q, found %q", c.col.Name, c.encoded, c.raw, s)
+		}
+	}
+	for _, bad := range []string{"00ff", "0xzz"} {
+		if _, err := decodeValue(flag, bad); err == nil {
+			t.Errorf("Expected error from decodeValue(%s, %q), but err was nil", flag.Name, bad)
+		}
+	}
+}
+
+func TestReadRows(t *testing.T) {
+	table := testTable(t)
+	input := "code,name,flag\nCI,Côte d'Ivoire,0x01\nFR,\"France, République\",\\N\n"
+	cols, rows, err := readRows(table, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error from readRows: %v", err)
+	}
+	if len(cols) != 3 || cols[2].Name != "flag" || len(rows) != 2 {
+		t.Fatalf("Unexpected result from readRows: cols=%+v rows=%+v", cols, rows)
+	}
+	if rows[1][1] != "France, République" || rows[1][2] != nil {
+		t.Errorf("Unexpected values in row: %+v", rows[1])
+	}
+
+	// Empty input is not an error
+	if cols, rows, err := readRows(table, strings.NewReader("")); err != nil || cols != nil || rows != nil {
+		t.Errorf("Unexpected result from readRows on empty input: %+v %+v %v", cols, rows, err)
+	}
+
+	badInputs := []string{
+		"code,nonexistent\nUS,foo\n",   // unknown column
+		"code,name_upper\nUS,FOO\n",    // generated column
+		"code,flag\nUS,01\n",           // binary value without hex prefix
+		"code,name\nUS,United States,", // wrong field count
+	}
+	for _, input := range badInputs {
+		if _, _, err := readRows(table, strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error from readRows on input %q, but err was nil", input)
+		}
+	}
+}
+
+func TestUpsertStatement(t *testing.T) {
+	table := testTable(t)
+	actual := upsertStatement(table.Name, table.Columns[0:2], 2)
+	expected := "INSERT INTO `countries` (`code`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `code` = VALUES(`code`), `name` = VALUES(`name`)"
+	if actual != expected {
+		t.Errorf("Unexpected result from upsertStatement:\n%s", actual)
+	}
+	if cols := storedColumns(table); len(cols) != 3 {
+		t.Errorf("Expected storedColumns to exclude generated column, instead found %d columns", len(cols))
+	}
+}
+
+func TestOmitMasked(t *testing.T) {
+	table := testTable(t)
+	input := "code,name,flag\nCI,Côte d'Ivoire,0x01\nFR,France,\\N\n"
+	cols, rows, err := readRows(table, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error from readRows: %v", err)
+	}
+
+	// Rules for other tables or columns don't affect anything
+	masks := []MaskRule{{Table: "users", Column: "*", Method: MaskHash}, {Table: "*", Column: "email", Method: MaskEmail}}
+	if keptCols, keptRows, err := omitMasked(table, cols, rows, masks); err != nil || len(keptCols) != 3 || len(keptRows[0]) != 3 {
+		t.Errorf("Unexpected result from omitMasked: cols=%+v rows=%+v err=%v", keptCols, keptRows, err)
+	}
+
+	// Masked columns are removed along with their values
+	masks = append(masks, MaskRule{Table: "countr*", Column: "name", Method: MaskHash})
+	keptCols, keptRows, err := omitMasked(table, cols, rows, masks)
+	if err != nil || len(keptCols) != 2 || keptCols[0].Name != "code" || keptCols[1].Name != "flag" {
+		t.Fatalf("Unexpected result from omitMasked: cols=%+v err=%v", keptCols, err)
+	}
+	if len(keptRows) != 2 || len(keptRows[0]) != 2 || keptRows[0][0] != "CI" || keptRows[1][1] != nil {
+		t.Errorf("Unexpected rows from omitMasked: %+v", keptRows)
+	}
+
+	// Masking a primary key column is an error
+	masks = []MaskRule{{Table: "*", Column: "code", Method: MaskHash}}
+	if _, _, err := omitMasked(table, cols, rows, masks); err == nil {
+		t.Error("Expected error from omitMasked with a masked primary key column, but err was nil")
+	}
+}
@@ -0,0 +1,143 @@
+package fixture
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// MaskMethod identifies how the values of a column are obscured when dumped.
+type MaskMethod string
+
+// Constants enumerating valid mask methods. All methods are deterministic, so
+// that dumping the same rows repeatedly yields the same output.
+const (
+	MaskNull  MaskMethod = "null"  // replace with NULL
+	MaskEmpty MaskMethod = "empty" // replace with an empty string
+	MaskHash  MaskMethod = "hash"  // replace with hex SHA-256 of the value, truncated to fit the column
+	MaskEmail MaskMethod = "email" // replace with a hash-based address at example.com
+)
+
+// MaskRule describes a masking method to apply to columns matching a pattern.
+type MaskRule struct {
+	Table  string // shell-style pattern for table names
+	Column string // shell-style pattern for column names
+	Method MaskMethod
+}
+
+// ParseMaskRules converts option values of the form "table.column=method"
+// into MaskRules. The table and column may use shell-style wildcards, and
+// the table may be omitted to match columns in any table.
+func ParseMaskRules(values []string) ([]MaskRule, error) {
+	rules := make([]MaskRule, 0, len(values))
+	for _, value := range values {
+		target, method, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("mask rule %q must be of the form table.column=method", value)
+		}
+		rule := MaskRule{Table: "*", Column: target, Method: MaskMethod(strings.ToLower(method))}
+		if table, column, ok := strings.Cut(target, "."); ok {
+			rule.Table, rule.Column = table, column
+		}
+		switch rule.Method {
+		case MaskNull, MaskEmpty, MaskHash, MaskEmail:
+		default:
+			return nil, fmt.Errorf("mask rule %q has invalid method: must be one of null, empty, hash, email", value)
+		}
+		for _, pattern := range []string{rule.Table, rule.Column} {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return nil, fmt.Errorf("mask rule %q has invalid pattern %q", value, pattern)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// maskFunc transforms a raw column value. It is not called for NULL values.
+type maskFunc func(raw sql.RawBytes) sql.RawBytes
+
+// maskFuncs returns a maskFunc for each column of table matched by rules,
+// keyed by column name. If multiple rules match a column, the first one
+// applies. An error is returned if a rule would replace values of a NOT NULL
+// column with NULL.
+func maskFuncs(table *tengo.Table, rules []MaskRule) (map[string]maskFunc, error) {
+	funcs := make(map[string]maskFunc)
+	for _, col := range table.Columns {
+		rule := matchRule(table, col, rules)
+		if rule == nil {
+			continue
+		} else if rule.Method == MaskNull && !col.Nullable {
+			return nil, fmt.Errorf("cannot mask NOT NULL column %s with null", tengo.EscapeIdentifier(col.Name))
+		}
+		funcs[col.Name] = newMaskFunc(col, rule.Method)
+	}
+	return funcs, nil
+}
+
+// matchRule returns the first of rules matching col of table, or nil if no
+// rule matches.
+func matchRule(table *tengo.Table, col *tengo.Column, rules []MaskRule) *MaskRule {
+	for n := range rules {
+		if tableMatch, _ := path.Match(rules[n].Table, table.Name); !tableMatch {
+			continue
+		} else if colMatch, _ := path.Match(rules[n].Column, col.Name); colMatch {
+			return &rules[n]
+		}
+	}
+	return nil
+}
+
+func newMaskFunc(col *tengo.Column, method MaskMethod) maskFunc {
+	maxLen := columnMaxLength(col)
+	truncate := func(b []byte) sql.RawBytes {
+		if maxLen > 0 && len(b) > maxLen {
+			b = b[:maxLen]
+		}
+		return b
+	}
+	switch method {
+	case MaskNull:
+		return func(sql.RawBytes) sql.RawBytes { return nil }
+	case MaskEmpty:
+		return func(sql.RawBytes) sql.RawBytes { return sql.RawBytes{} }
+	case MaskEmail:
+		return func(raw sql.RawBytes) sql.RawBytes {
+			// Shorten the local part rather than the domain, if needed to fit
+			const domain = "@example.com"
+			sum := sha256.Sum256(raw)
+			local := hex.EncodeToString(sum[:8])
+			if maxLen > len(domain) && len(local)+len(domain) > maxLen {
+				local = local[:maxLen-len(domain)]
+			}
+			return truncate([]byte(local + domain))
+		}
+	default: // MaskHash
+		return func(raw sql.RawBytes) sql.RawBytes {
+			sum := sha256.Sum256(raw)
+			if isBinary(col) {
+				return truncate(sum[:])
+			}
+			return truncate([]byte(hex.EncodeToString(sum[:])))
+		}
+	}
+}
+
+// columnMaxLength returns the maximum length of values of col for
+// fixed-length and variable-length string types, or 0 for other types.
+func columnMaxLength(col *tengo.Column) int {
+	for _, prefix := range []string{"char(", "varchar(", "binary(", "varbinary("} {
+		if strings.HasPrefix(col.TypeInDB, prefix) {
+			arg, _, _ := strings.Cut(strings.TrimPrefix(col.TypeInDB, prefix), ")")
+			n, _ := strconv.Atoi(arg)
+			return n
+		}
+	}
+	return 0
+}
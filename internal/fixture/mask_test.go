@@ -0,0 +1,84 @@
+package fixture
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func TestParseMaskRules(t *testing.T) {
+	rules, err := ParseMaskRules([]string{"users.email=email", "*_token=NULL", "user?.ssn=hash"})
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseMaskRules: %v", err)
+	}
+	expected := []MaskRule{
+		{Table: "users", Column: "email", Method: MaskEmail},
+		{Table: "*", Column: "*_token", Method: MaskNull},
+		{Table: "user?", Column: "ssn", Method: MaskHash},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, instead found %d", len(expected), len(rules))
+	}
+	for n := range rules {
+		if rules[n] != expected[n] {
+			t.Errorf("Rule %d: expected %+v, found %+v", n, expected[n], rules[n])
+		}
+	}
+
+	for _, bad := range []string{"users.email", "users.email=scramble", "users.[=null", ".email=hash"} {
+		if _, err := ParseMaskRules([]string{bad}); err == nil {
+			t.Errorf("Expected error from ParseMaskRules(%q), but err was nil", bad)
+		}
+	}
+}
+
+func TestMaskFuncs(t *testing.T) {
+	table, err := tengo.NewTableBuilder("users").
+		Column("id", "int unsigned", tengo.NotNull()).
+		Column("email", "varchar(30)", tengo.NotNull()).
+		Column("api_token", "varchar(40)").
+		Column("ssn_hash", "binary(8)", tengo.NotNull()).
+		Column("notes", "char(10)", tengo.NotNull()).
+		PrimaryKey("id").
+		Build(tengo.FlavorMySQL80.Dot(30))
+	if err != nil {
+		t.Fatalf("Unexpected error building table: %v", err)
+	}
+	rules, _ := ParseMaskRules([]string{"users.email=email", "*_token=null", "ssn*=hash", "notes=hash", "*=empty", "other.*=null"})
+	funcs, err := maskFuncs(table, rules)
+	if err != nil {
+		t.Fatalf("Unexpected error from maskFuncs: %v", err)
+	}
+	if len(funcs) != 5 {
+		t.Errorf("Expected 5 masked columns, instead found %d", len(funcs))
+	}
+
+	email := funcs["email"](sql.RawBytes("alice@company.com"))
+	if len(email) > 30 || !strings.HasSuffix(string(email), "@example.com") {
+		t.Errorf("Unexpected masked email: %q", email)
+	} else if again := funcs["email"](sql.RawBytes("alice@company.com")); string(again) != string(email) {
+		t.Errorf("Expected masking to be deterministic, but found %q vs %q", email, again)
+	} else if other := funcs["email"](sql.RawBytes("bob@company.com")); string(other) == string(email) {
+		t.Errorf("Expected different inputs to mask differently, but both yielded %q", email)
+	}
+	if token := funcs["api_token"](sql.RawBytes("secret")); token != nil {
+		t.Errorf("Expected null mask to return nil, instead found %q", token)
+	}
+	if ssn := funcs["ssn_hash"](sql.RawBytes("123-45-6789")); len(ssn) != 8 {
+		t.Errorf("Expected binary hash to be truncated to 8 bytes, instead found %d", len(ssn))
+	}
+	if notes := funcs["notes"](sql.RawBytes("hello")); len(notes) != 10 || strings.Trim(string(notes), "0123456789abcdef") != "" {
+		t.Errorf("Unexpected masked value for char(10) column: %q", notes)
+	}
+	if id := funcs["id"](sql.RawBytes("123")); id == nil || len(id) != 0 {
+		t.Errorf("Expected empty mask to return empty non-nil value, instead found %#v", id)
+	}
+
+	// Masking a NOT NULL column with null is an error
+	rules, _ = ParseMaskRules([]string{"email=null"})
+	if _, err := maskFuncs(table, rules); err == nil {
+		t.Error("Expected error from maskFuncs with null rule on NOT NULL column, but err was nil")
+	}
+}
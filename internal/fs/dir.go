@@ -26,6 +26,8 @@ type Dir struct {
 	SQLFiles              map[string]*SQLFile   // .sql files, keyed by normalized absolute file path
 	UnparsedStatements    []*tengo.Statement    // statements with unknown type / not supported by this package
 	NamedSchemaStatements []*tengo.Statement    // statements with explicit schema names: USE command or CREATEs with schema name qualifier
+	MalformedStatements   []*tengo.Statement    // statements with a non-nil Err; their objects are added to IgnorePatterns
+	GrantStatements       []*tengo.Statement    // GRANT statements, which are interpreted by the grants package
 	LogicalSchemas        []*LogicalSchema      // for now, always 0 or 1 elements; 2+ in same dir to be supported in future
 	IgnorePatterns        []tengo.ObjectPattern // regexes for matching objects that should be ignored
 	ParseError            error                 // any fatal error found parsing dir's config or contents
@@ -211,12 +213,15 @@ func (dir *Dir) Port() (int, bool) {
 // FileFor returns a SQLFile associated with the supplied keyer. If keyer is a
 // *tengo.Statement with non-empty File field, that path will be used as-is.
 // Otherwise, FileFor returns the default location for the supplied keyer based
-// on its type and name. In either case, if no known SQLFile exists at that
-// location yet, FileFor will instantiate a new SQLFile value for it.
+// on its type and name; triggers default to the same location as their table.
+// In either case, if no known SQLFile exists at that location yet, FileFor will
+// instantiate a new SQLFile value for it.
 func (dir *Dir) FileFor(keyer tengo.ObjectKeyer) *SQLFile {
 	var filePath string
 	if stmt, ok := keyer.(*tengo.Statement); ok && stmt.File != "" {
 		filePath = stmt.File
+	} else if trigger, ok := keyer.(*tengo.Trigger); ok {
+		filePath = PathForObject(dir.Path, NormalizeFileName(trigger.Table))
 	} else {
 		objName := keyer.ObjectKey().Name
 		filePath = PathForObject(dir.Path, NormalizeFileName(objName))
@@ -258,7 +263,16 @@ func (dir *Dir) Instances() ([]*tengo.Instance, error) {
 		// to do
 		return nil, nil
 	}
+	return dir.InstancesForHosts(hosts)
+}
 
+// InstancesForHosts returns a tengo.Instance pointer for each of the supplied
+// hostnames, using the directory's configuration for all other connection
+// information, such as user, password, port, and connect-options. Hostnames may
+// optionally include a port, in host:port format. This is useful for obtaining
+// instances for hosts other than those in the directory's host option, for
+// example replicas. As with Instances, connectivity is NOT checked.
+func (dir *Dir) InstancesForHosts(hosts []string) ([]*tengo.Instance, error) {
 	// Before looping over hostnames, do a single lookup of user, password,
 	// connect-options, port, socket.
 	user := dir.Config.GetAllowEnvVar("user")
@@ -557,6 +571,23 @@ func (dir *Dir) InstanceDefaultParams() (string, error) {
 		v.Set(name, value)
 	}
 
+	// Set values from sql-mode and session-init
+	sessionVars, err := dir.SessionVariables()
+	if err != nil {
+		return "", err
+	}
+	for name, value := range sessionVars {
+		if banned[name] {
+			return "", ConfigErrorf("session-init is not allowed to set %s", name)
+		}
+		for connOpt := range options {
+			if strings.EqualFold(connOpt, name) {
+				return "", ConfigErrorf("%s cannot be set in both connect-options and session-init or sql-mode", name)
+			}
+		}
+		v.Set(name, value)
+	}
+
 	// Set non-overridable options
 	v.Set("interpolateParams", "true")
 	v.Set("foreign_key_checks", "0")
@@ -564,6 +595,24 @@ func (dir *Dir) InstanceDefaultParams() (string, error) {
 	return v.Encode(), nil
 }
 
+// SessionVariables returns a map of session variable names to values, in SQL
+// literal form, based on the dir's sql-mode and session-init options. These are
+// applied to all database connections, including those used for executing DDL
+// in push and for loading workspaces.
+func (dir *Dir) SessionVariables() (map[string]string, error) {
+	vars, err := util.ParseSessionInit(dir.Config.Get("session-init"))
+	if err != nil {
+		return nil, ConfigError{err}
+	}
+	if sqlMode := dir.Config.Get("sql-mode"); sqlMode != "" {
+		if _, already := vars["sql_mode"]; already {
+			return nil, ConfigErrorf("sql_mode cannot be set in both sql-mode and session-init")
+		}
+		vars["sql_mode"] = "'" + strings.ReplaceAll(sqlMode, "'", "''") + "'"
+	}
+	return vars, nil
+}
+
 // Generator returns the version and edition of Skeema used to init or most
 // most recently pull this dir's contents. If this cannot be determined, all
 // results will be zero values.
@@ -690,14 +739,15 @@ func (dir *Dir) parseContents() {
 		sf := &SQLFile{
 			FilePath: filePath,
 		}
-		sf.Statements, dir.ParseError = tengo.ParseStatementsInFile(filePath)
-		if dir.ParseError != nil {
-			// Treat errors here as fatal. This includes: i/o error opening or reading
-			// the .sql file; file had unterminated quote or backtick or comment.
-			// These are all problematic, since if the caller otherwise just skipped the
-			// statements in the file, it could result in the caller emitting DROP
-			// statements incorrectly -- not good if the root cause is just an unclosed
-			// quote for example.
+		sf.Statements, err = tengo.ParseStatementsInFile(filePath)
+		if _, ok := err.(tengo.StatementErrors); !ok && err != nil {
+			// Treat other errors here as fatal. This includes: i/o error opening or
+			// reading the .sql file; file had unterminated quote or backtick or
+			// comment. These are all problematic, since if the caller otherwise just
+			// skipped the statements in the file, it could result in the caller
+			// emitting DROP statements incorrectly -- not good if the root cause is
+			// just an unclosed quote for example.
+			dir.ParseError = err
 			return
 		}
 		for _, stmt := range sf.Statements {
@@ -708,6 +758,27 @@ func (dir *Dir) parseContents() {
 				continue
 			}
 
+			// Individual malformed statements are not fatal, but their objects must be
+			// ignored entirely, for the same reason described above: otherwise the
+			// object would be considered missing from the filesystem.
+			if stmt.Err != nil {
+				dir.MalformedStatements = append(dir.MalformedStatements, stmt)
+				if stmt.ObjectName != "" {
+					dir.IgnorePatterns = append(dir.IgnorePatterns, tengo.ObjectPattern{
+						Type:    stmt.ObjectType,
+						Pattern: regexp.MustCompile("^" + regexp.QuoteMeta(stmt.ObjectName) + "$"),
+					})
+				}
+				continue
+			}
+
+			// GRANT statements are not schema objects, so they are tracked separately
+			// instead of being placed into a LogicalSchema
+			if stmt.Type == tengo.StatementTypeGrant {
+				dir.GrantStatements = append(dir.GrantStatements, stmt)
+				continue
+			}
+
 			if _, ok := logicalSchemasByName[stmt.Schema()]; !ok {
 				logicalSchemasByName[stmt.Schema()] = NewLogicalSchema()
 			}
@@ -312,10 +312,26 @@ func TestParseDirRedundantDelimiter(t *testing.T) {
 
 func TestParseDirCreateSelect(t *testing.T) {
 	// This dir contains a CREATE ... SELECT statement, which is explicitly not
-	// supported at this time.
-	_, err := ParseDir("testdata/createselect", getValidConfig(t))
-	if err == nil {
-		t.Fatal("In dir testdata/createselect, expected error from ParseDir(), but instead err is nil")
+	// supported at this time. This only affects the one statement, so it should
+	// not cause a fatal error, but the table it creates should be ignored.
+	dir, err := ParseDir("testdata/createselect", getValidConfig(t))
+	if err != nil {
+		t.Fatalf("In dir testdata/createselect, unexpected error from ParseDir(): %v", err)
+	}
+	if len(dir.MalformedStatements) != 1 {
+		t.Fatalf("In dir testdata/createselect, expected 1 MalformedStatements, instead found %d", len(dir.MalformedStatements))
+	}
+	stmt := dir.MalformedStatements[0]
+	if stmt.ObjectName != "two" || stmt.LineNo != 7 || stmt.Err == nil {
+		t.Errorf("Unexpected malformed statement: %+v", *stmt)
+	} else if msg := stmt.Err.Error(); !strings.Contains(msg, "tables.sql") || !strings.Contains(msg, "line 7") || !strings.Contains(msg, "CREATE TABLE `two` AS SELECT") {
+		t.Errorf("Expected error message to include file, line, and snippet, but it did not: %s", msg)
+	}
+	if len(dir.LogicalSchemas) != 1 || len(dir.LogicalSchemas[0].Creates) != 1 {
+		t.Errorf("In dir testdata/createselect, expected 1 logical schema with 1 CREATE, instead found %+v", dir.LogicalSchemas)
+	}
+	if !dir.ShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "two"}) || dir.ShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "one"}) {
+		t.Error("Expected table from malformed statement to be ignored, and only that table")
 	}
 }
 
@@ -403,9 +419,10 @@ func TestDirSubdirs(t *testing.T) {
 
 	dir = getDir(t, "testdata")
 	subs, err = dir.Subdirs()
-	// Expect at least 5 parse errors: 3 with unterminated quotes/comments, 1 bad
-	// symlink in cfgsymlinks2, 1 forbidden statement in createselect
-	if len(subs) < 19 || err != nil || countParseErrors(subs) < 5 {
+	// Expect at least 4 parse errors: 3 with unterminated quotes/comments, 1 bad
+	// symlink in cfgsymlinks2. (The forbidden statement in createselect is not a
+	// parse error, since it only affects that one statement.)
+	if len(subs) < 19 || err != nil || countParseErrors(subs) < 4 {
 		t.Errorf("Unexpected return from Subdirs(): %d subs, %d parse errors, err=%v", len(subs), countParseErrors(subs), err)
 	}
 }
@@ -556,7 +573,7 @@ func TestDirInstanceDefaultParams(t *testing.T) {
 	getFakeDir := func(connectOptions string) *Dir {
 		return &Dir{
 			Path:   "/tmp/dummydir",
-			Config: mybase.SimpleConfig(map[string]string{"connect-options": connectOptions, "ssl-mode": "preferred"}),
+			Config: mybase.SimpleConfig(map[string]string{"connect-options": connectOptions, "ssl-mode": "preferred", "session-init": "", "sql-mode": ""}),
 		}
 	}
 
@@ -608,7 +625,7 @@ func TestDirInstanceDefaultParams(t *testing.T) {
 	}
 	dir := getFakeDir("")
 	for sslMode, expected := range expectTLS {
-		dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "", "ssl-mode": sslMode})
+		dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "", "ssl-mode": sslMode, "session-init": "", "sql-mode": ""})
 		if parsed, err := url.ParseQuery(expected); err != nil {
 			t.Fatalf("Bad expected value %q: %s", expected, err)
 		} else {
@@ -621,14 +638,38 @@ func TestDirInstanceDefaultParams(t *testing.T) {
 			t.Errorf("Expected ssl-mode=%q to yield default params %q, instead found %q", sslMode, expected, actual)
 		}
 	}
-	dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "", "ssl-mode": "invalid-enum"})
+	dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "", "ssl-mode": "invalid-enum", "session-init": "", "sql-mode": ""})
 	if _, err := dir.InstanceDefaultParams(); err == nil {
 		t.Error("Expected an error from dir.InstanceDefaultParams() with invalid ssl-mode, but err was nil")
 	}
-	dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "tls=preferred", "ssl-mode": "required"})
+	dir.Config = mybase.SimpleConfig(map[string]string{"connect-options": "tls=preferred", "ssl-mode": "required", "session-init": "", "sql-mode": ""})
 	if _, err := dir.InstanceDefaultParams(); err == nil {
 		t.Error("Expected an error from dir.InstanceDefaultParams() with tls in connect-options while also setting ssl-mode, but err was nil")
 	}
+
+	// Test session-init and sql-mode
+	getSessionDir := func(connectOptions, sessionInit, sqlMode string) *Dir {
+		values := map[string]string{"connect-options": connectOptions, "ssl-mode": "preferred", "session-init": sessionInit, "sql-mode": sqlMode}
+		return &Dir{Path: "/tmp/dummydir", Config: mybase.SimpleConfig(values)}
+	}
+	dir = getSessionDir("foo=1", "SET innodb_strict_mode=0, SESSION lock_wait_timeout=30", "NO_ZERO_DATE,STRICT_ALL_TABLES")
+	expected, _ := url.ParseQuery(baseDefaults + "&foo=1&innodb_strict_mode=0&lock_wait_timeout=30&sql_mode=%27NO_ZERO_DATE,STRICT_ALL_TABLES%27")
+	if actual, err := dir.InstanceDefaultParams(); err != nil {
+		t.Errorf("Unexpected error from InstanceDefaultParams with session-init: %v", err)
+	} else if actual != expected.Encode() {
+		t.Errorf("Expected session-init to yield default params %q, instead found %q", expected.Encode(), actual)
+	}
+	for _, d := range []*Dir{
+		getSessionDir("sql_mode='ANSI'", "", "NO_ZERO_DATE"),
+		getSessionDir("", "SET sql_mode=''", "NO_ZERO_DATE"),
+		getSessionDir("Lock_Wait_Timeout=5", "SET lock_wait_timeout=30", ""),
+		getSessionDir("", "SET foreign_key_checks=1", ""),
+		getSessionDir("", "SET GLOBAL innodb_strict_mode=0", ""),
+	} {
+		if _, err := d.InstanceDefaultParams(); err == nil {
+			t.Errorf("Expected an error from InstanceDefaultParams with connect-options=%q session-init=%q sql-mode=%q, but err was nil", d.Config.Get("connect-options"), d.Config.Get("session-init"), d.Config.Get("sql-mode"))
+		}
+	}
 }
 
 func TestHostDefaultDirName(t *testing.T) {
@@ -835,3 +876,65 @@ func countParseErrors(subs []*Dir) (count int) {
 	}
 	return
 }
+
+func TestLogicalSchemaCaseCollisions(t *testing.T) {
+	logicalSchema := NewLogicalSchema()
+	stmts := []*tengo.Statement{
+		{File: "a.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeTable, ObjectName: "Foo"},
+		{File: "a.sql", LineNo: 5, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeTable, ObjectName: "bar"},
+		{File: "b.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeTable, ObjectName: "foo"},
+		{File: "c.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeProc, ObjectName: "BAR"},
+	}
+	for _, stmt := range stmts {
+		if err := logicalSchema.AddStatement(stmt); err != nil {
+			t.Fatalf("Unexpected error from AddStatement: %v", err)
+		}
+	}
+	collisions := logicalSchema.CaseCollisions()
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, instead found %d: %+v", len(collisions), collisions)
+	}
+	if c := collisions[0]; c.FirstFile != "a.sql" || c.FirstLine != 1 || c.DupeFile != "b.sql" || c.ObjectKey.Name != "foo" {
+		t.Errorf("Unexpected collision: %+v", c)
+	}
+	if err := logicalSchema.LowerCaseNames(tengo.NameCaseInsensitive); err == nil {
+		t.Error("Expected LowerCaseNames to return an error, but it did not")
+	}
+	delete(logicalSchema.Creates, tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "foo"})
+	if collisions := logicalSchema.CaseCollisions(); len(collisions) != 0 {
+		t.Errorf("Expected no collisions, instead found %+v", collisions)
+	}
+}
+
+func TestLogicalSchemaLowerCaseViewNames(t *testing.T) {
+	logicalSchema := NewLogicalSchema()
+	stmts := []*tengo.Statement{
+		{File: "a.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeTable, ObjectName: "Foo"},
+		{File: "b.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeView, ObjectName: "MyView"},
+	}
+	for _, stmt := range stmts {
+		if err := logicalSchema.AddStatement(stmt); err != nil {
+			t.Fatalf("Unexpected error from AddStatement: %v", err)
+		}
+	}
+
+	// With lower_case_table_names=2, only view names are forced to lowercase
+	if err := logicalSchema.LowerCaseNames(tengo.NameCaseInsensitive); err != nil {
+		t.Fatalf("Unexpected error from LowerCaseNames: %v", err)
+	}
+	if stmt := logicalSchema.Creates[tengo.ObjectKey{Type: tengo.ObjectTypeView, Name: "myview"}]; stmt == nil || stmt.ObjectName != "myview" {
+		t.Errorf("Expected view name to be lowercased, instead found %+v", logicalSchema.Creates)
+	}
+	if stmt := logicalSchema.Creates[tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "Foo"}]; stmt == nil {
+		t.Errorf("Expected table name to retain its case, instead found %+v", logicalSchema.Creates)
+	}
+
+	// With lower_case_table_names=1, a case-insensitive duplicate view is an error
+	dupe := &tengo.Statement{File: "c.sql", LineNo: 1, Type: tengo.StatementTypeCreate, ObjectType: tengo.ObjectTypeView, ObjectName: "MYVIEW"}
+	if err := logicalSchema.AddStatement(dupe); err != nil {
+		t.Fatalf("Unexpected error from AddStatement: %v", err)
+	}
+	if err := logicalSchema.LowerCaseNames(tengo.NameCaseLower); err == nil {
+		t.Error("Expected LowerCaseNames to return an error, but it did not")
+	}
+}
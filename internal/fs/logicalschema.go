@@ -2,6 +2,7 @@ package fs
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/skeema/skeema/internal/tengo"
@@ -65,53 +66,84 @@ func (logicalSchema *LogicalSchema) Empty() bool {
 func (logicalSchema *LogicalSchema) LowerCaseNames(mode tengo.NameCaseMode) error {
 	switch mode {
 	case tengo.NameCaseLower: // lower_case_table_names=1
-		// Schema names and table names are forced lowercase in this mode
+		// Schema names, table names, and view names are forced lowercase in this mode
 		logicalSchema.Name = strings.ToLower(logicalSchema.Name)
-		newCreates := make(map[tengo.ObjectKey]*tengo.Statement, len(logicalSchema.Creates))
-		for k, stmt := range logicalSchema.Creates {
-			if k.Type == tengo.ObjectTypeTable {
-				k.Name = strings.ToLower(k.Name)
-				stmt.ObjectName = strings.ToLower(stmt.ObjectName)
-				if origStmt, already := newCreates[k]; already {
-					return DuplicateDefinitionError{
-						ObjectKey: stmt.ObjectKey(),
-						FirstFile: origStmt.File,
-						FirstLine: origStmt.LineNo,
-						DupeFile:  stmt.File,
-						DupeLine:  stmt.LineNo,
-					}
-				}
-			}
-			newCreates[k] = stmt
-		}
-		logicalSchema.Creates = newCreates
+		return logicalSchema.lowerCaseCreates(tengo.ObjectTypeTable, tengo.ObjectTypeView)
 
 	case tengo.NameCaseInsensitive: // lower_case_table_names=2
-		// Only view names are forced to lowercase in this mode, but Community Edition
-		// codebase does not support views, so nothing to lowercase here.
-		// However, with this mode we still need to ensure there aren't any duplicate
-		// table names in CREATEs after accounting for case-insensitive table naming.
-		lowerTables := make(map[string]*tengo.Statement)
+		// Only view names are forced to lowercase in this mode. However, we still
+		// need to ensure there aren't any duplicate table names in CREATEs after
+		// accounting for case-insensitive table naming.
+		if collisions := logicalSchema.CaseCollisions(); len(collisions) > 0 {
+			return collisions[0]
+		}
+		return logicalSchema.lowerCaseCreates(tengo.ObjectTypeView)
+	}
+	return nil
+}
 
-		for k, stmt := range logicalSchema.Creates {
-			if k.Type == tengo.ObjectTypeTable {
-				lowerName := strings.ToLower(k.Name)
-				if origStmt, already := lowerTables[lowerName]; already {
-					return DuplicateDefinitionError{
-						ObjectKey: stmt.ObjectKey(),
-						FirstFile: origStmt.File,
-						FirstLine: origStmt.LineNo,
-						DupeFile:  stmt.File,
-						DupeLine:  stmt.LineNo,
-					}
+// lowerCaseCreates forces the names of CREATEs of the supplied object types to
+// lowercase, returning an error if this results in duplicate definitions.
+func (logicalSchema *LogicalSchema) lowerCaseCreates(types ...tengo.ObjectType) error {
+	newCreates := make(map[tengo.ObjectKey]*tengo.Statement, len(logicalSchema.Creates))
+	for k, stmt := range logicalSchema.Creates {
+		for _, typ := range types {
+			if k.Type != typ {
+				continue
+			}
+			k.Name = strings.ToLower(k.Name)
+			stmt.ObjectName = strings.ToLower(stmt.ObjectName)
+			if origStmt, already := newCreates[k]; already {
+				return DuplicateDefinitionError{
+					ObjectKey: stmt.ObjectKey(),
+					FirstFile: origStmt.File,
+					FirstLine: origStmt.LineNo,
+					DupeFile:  stmt.File,
+					DupeLine:  stmt.LineNo,
 				}
-				lowerTables[lowerName] = stmt
 			}
 		}
+		newCreates[k] = stmt
 	}
+	logicalSchema.Creates = newCreates
 	return nil
 }
 
+// CaseCollisions returns an error for each CREATE TABLE whose table name only
+// differs in letter case from an earlier CREATE TABLE in logicalSchema. Such
+// tables cannot coexist on a server using a non-zero lower_case_table_names.
+// Statements are examined in order of file name and line number.
+func (logicalSchema *LogicalSchema) CaseCollisions() (collisions []DuplicateDefinitionError) {
+	stmts := make([]*tengo.Statement, 0, len(logicalSchema.Creates))
+	for k, stmt := range logicalSchema.Creates {
+		if k.Type == tengo.ObjectTypeTable {
+			stmts = append(stmts, stmt)
+		}
+	}
+	sort.Slice(stmts, func(i, j int) bool {
+		if stmts[i].File != stmts[j].File {
+			return stmts[i].File < stmts[j].File
+		}
+		return stmts[i].LineNo < stmts[j].LineNo
+	})
+	lowerTables := make(map[string]*tengo.Statement, len(stmts))
+	for _, stmt := range stmts {
+		lowerName := strings.ToLower(stmt.ObjectName)
+		if origStmt, already := lowerTables[lowerName]; already {
+			collisions = append(collisions, DuplicateDefinitionError{
+				ObjectKey: stmt.ObjectKey(),
+				FirstFile: origStmt.File,
+				FirstLine: origStmt.LineNo,
+				DupeFile:  stmt.File,
+				DupeLine:  stmt.LineNo,
+			})
+		} else {
+			lowerTables[lowerName] = stmt
+		}
+	}
+	return collisions
+}
+
 // DuplicateDefinitionError is an error returned when Dir.parseContents()
 // encounters multiple CREATE statements for the same exact object.
 type DuplicateDefinitionError struct {
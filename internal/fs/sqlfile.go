@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/skeema/skeema/internal/tengo"
 )
@@ -186,12 +187,29 @@ func NormalizeFileName(name string) string {
 	return name
 }
 
+// maxFileNameBaseLen is the maximum length, in bytes, of the portion of a file
+// name returned by FileNameForObject preceding its ".sql" extension. Most
+// filesystems limit file names to 255 bytes, which an object name of 64
+// multi-byte characters could otherwise exceed.
+const maxFileNameBaseLen = 200
+
 // FileNameForObject returns a string containing the filename to use for the
 // SQLFile representing the supplied object name. Special characters in the
-// objectName will be removed; however, there is no risk of "conflicts" since
-// a single SQLFile can store definitions for multiple objects.
+// objectName will be removed, and excessively long names will be truncated;
+// however, there is no risk of "conflicts" since a single SQLFile can store
+// definitions for multiple objects.
 func FileNameForObject(objectName string) string {
 	objectName = strings.Map(removeSpecialChars, objectName)
+	if len(objectName) > maxFileNameBaseLen {
+		// Truncate on a rune boundary; objectName is valid UTF-8 at this point
+		// since strings.Map converts invalid bytes to utf8.RuneError, which
+		// removeSpecialChars then strips
+		end := maxFileNameBaseLen
+		for !utf8.RuneStart(objectName[end]) {
+			end--
+		}
+		objectName = objectName[:end]
+	}
 	if objectName == "" {
 		objectName = "symbols"
 	}
@@ -207,7 +225,7 @@ func PathForObject(dirPath, objectName string) string {
 }
 
 func removeSpecialChars(r rune) rune {
-	if unicode.IsSpace(r) {
+	if unicode.IsSpace(r) || !unicode.IsPrint(r) || r == utf8.RuneError {
 		return -1
 	}
 	banned := []rune{
@@ -2,9 +2,12 @@ package fs
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/skeema/skeema/internal/tengo"
 )
@@ -216,6 +219,10 @@ func TestPathForObject(t *testing.T) {
 		{"/var/schemas", "foo_bar", "/var/schemas/foo_bar.sql"},
 		{"/var/schemas", "foo-bar", "/var/schemas/foobar.sql"},
 		{"/var/schemas", "../../etc/passwd", "/var/schemas/etcpasswd.sql"},
+		{"/var/schemas", "emoji_🐬", "/var/schemas/emoji_🐬.sql"},
+		{"/var/schemas", "new\nline\x00\x1b", "/var/schemas/newline.sql"},
+		{"/var/schemas", "bad\xffutf8", "/var/schemas/badutf8.sql"},
+		{"/var/schemas", strings.Repeat("🐬", 64), "/var/schemas/" + strings.Repeat("🐬", 50) + ".sql"},
 	}
 	for _, c := range cases {
 		if runtime.GOOS == "windows" {
@@ -229,3 +236,29 @@ func TestPathForObject(t *testing.T) {
 		}
 	}
 }
+
+func FuzzFileNameForObject(f *testing.F) {
+	for _, seed := range []string{"", "foo", "foo`bar", "../../etc/passwd", "emoji 🐬", "new\nline", "nul\x00", "\xff\xfe", strings.Repeat("ü", 64)} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, objectName string) {
+		name := FileNameForObject(objectName)
+		if !strings.HasSuffix(name, ".sql") || len(name) == len(".sql") {
+			t.Fatalf("FileNameForObject(%q) returned unexpected name %q", objectName, name)
+		}
+		if len(name) > maxFileNameBaseLen+len(".sql") {
+			t.Errorf("FileNameForObject(%q) returned name %q exceeding length limit", objectName, name)
+		}
+		if !utf8.ValidString(name) {
+			t.Errorf("FileNameForObject(%q) returned invalid UTF-8 %q", objectName, name)
+		}
+		if strings.ContainsAny(name[:len(name)-len(".sql")], "./\\`\x00") || filepath.Base(name) != name {
+			t.Errorf("FileNameForObject(%q) returned unsafe name %q", objectName, name)
+		}
+		for _, r := range name {
+			if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+				t.Errorf("FileNameForObject(%q) returned name %q containing non-printable or space rune %q", objectName, name, r)
+			}
+		}
+	})
+}
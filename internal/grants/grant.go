@@ -0,0 +1,336 @@
+package grants
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// Grant represents the privileges held by a single grantee at a single
+// privilege level, as expressed by one GRANT statement. A grant of a role to an
+// account is represented with a blank Level, and the role's account name as the
+// privilege.
+type Grant struct {
+	Grantee         string   // account name, escaped like `user`@`host`, or just `role` for MariaDB roles
+	Level           string   // e.g. *.* or `db`.* or `db`.`tbl` or PROCEDURE `db`.`proc`; blank for roles
+	Privileges      []string // sorted; upper-case, aside from any column list or role name
+	WithGrantOption bool     // WITH GRANT OPTION, or WITH ADMIN OPTION for roles
+}
+
+// IsRole returns true if g represents a grant of a role, rather than a grant of
+// privileges.
+func (g *Grant) IsRole() bool {
+	return g.Level == ""
+}
+
+// String returns a canonical GRANT statement for g, without a delimiter.
+func (g *Grant) String() string {
+	if g.IsRole() {
+		stmt := fmt.Sprintf("GRANT %s TO %s", strings.Join(g.Privileges, ", "), g.Grantee)
+		if g.WithGrantOption {
+			stmt += " WITH ADMIN OPTION"
+		}
+		return stmt
+	}
+	privs := "USAGE"
+	if len(g.Privileges) > 0 {
+		privs = strings.Join(g.Privileges, ", ")
+	}
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s", privs, g.Level, g.Grantee)
+	if g.WithGrantOption {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt
+}
+
+// key returns a string which uniquely identifies the grantee and privilege
+// level of g.
+func (g *Grant) key() string {
+	if g.IsRole() {
+		return g.Grantee + " role " + strings.Join(g.Privileges, ",")
+	}
+	return g.Grantee + " on " + g.Level
+}
+
+// empty returns true if g does not actually grant anything, as is the case
+// with USAGE.
+func (g *Grant) empty() bool {
+	return len(g.Privileges) == 0 && !g.WithGrantOption
+}
+
+// Account returns the grantee's unescaped account name in user@host format,
+// or just the name for a MariaDB role lacking a host.
+func (g *Grant) Account() string {
+	toks := tokenize(g.Grantee)
+	user, host, _ := parseAccount(toks)
+	if host == nil {
+		return user
+	}
+	return user + "@" + *host
+}
+
+// ParseGrant parses a single GRANT statement, as returned by SHOW GRANTS or as
+// written in a privilege file. Clauses which do not relate to privileges, such
+// as IDENTIFIED BY or REQUIRE in older server versions, are discarded.
+func ParseGrant(stmt string) (*Grant, error) {
+	toks := tokenize(stmt)
+	if len(toks) == 0 || !toks[0].is("grant") {
+		return nil, fmt.Errorf("not a GRANT statement: %s", stmt)
+	}
+	toks = toks[1:]
+
+	// Split the remainder into privilege list, privilege level, and grantee
+	// portions. Role grants lack an ON clause.
+	onPos, toPos := -1, -1
+	var depth int
+	for n, t := range toks {
+		if t.val == "(" {
+			depth++
+		} else if t.val == ")" {
+			depth--
+		} else if depth == 0 && onPos < 0 && toPos < 0 && t.is("on") {
+			onPos = n
+		} else if depth == 0 && toPos < 0 && t.is("to") {
+			toPos = n
+		}
+	}
+	if toPos < 0 {
+		return nil, fmt.Errorf("GRANT statement lacks TO clause: %s", stmt)
+	}
+	g := &Grant{}
+	privToks := toks[:toPos]
+	if onPos >= 0 {
+		privToks = toks[:onPos]
+	}
+	granteeToks := toks[toPos+1:]
+
+	items := splitList(privToks)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("GRANT statement lacks privileges: %s", stmt)
+	}
+	for _, item := range items {
+		var priv string
+		var err error
+		if onPos < 0 {
+			priv, err = normalizeAccount(item)
+		} else {
+			priv, err = normalizePrivilege(item)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s in GRANT statement: %s", err, stmt)
+		} else if priv != "USAGE" {
+			g.Privileges = append(g.Privileges, priv)
+		}
+	}
+	if len(g.Privileges) == 0 && onPos < 0 {
+		return nil, fmt.Errorf("GRANT statement lacks roles: %s", stmt)
+	}
+	sort.Strings(g.Privileges)
+
+	if onPos >= 0 {
+		var err error
+		levelToks := toks[onPos+1 : toPos]
+		if len(g.Privileges) == 1 && g.Privileges[0] == "PROXY" {
+			g.Level, err = normalizeAccount(levelToks)
+		} else {
+			g.Level, err = normalizeLevel(levelToks)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s in GRANT statement: %s", err, stmt)
+		}
+	}
+
+	user, host, rest := parseAccount(granteeToks)
+	if user == "" && host == nil {
+		return nil, fmt.Errorf("GRANT statement lacks grantee: %s", stmt)
+	} else if len(rest) > 0 && rest[0].val == "," {
+		return nil, fmt.Errorf("GRANT statements with multiple grantees are not supported: %s", stmt)
+	}
+	g.Grantee = escapeAccount(user, host)
+	// The WITH clause may also contain resource limits in older server versions,
+	// in any order relative to GRANT OPTION
+	var inWith bool
+	for n, t := range rest {
+		if t.is("with") {
+			inWith = true
+		} else if inWith && n+1 < len(rest) && (t.is("grant") || t.is("admin")) && rest[n+1].is("option") {
+			g.WithGrantOption = true
+		}
+	}
+	return g, nil
+}
+
+// revokeStatement returns a REVOKE statement for the supplied privileges of g.
+// If privs is empty, the grant option is revoked instead.
+func (g *Grant) revokeStatement(privs []string) string {
+	if g.IsRole() {
+		return fmt.Sprintf("REVOKE %s FROM %s", strings.Join(privs, ", "), g.Grantee)
+	} else if len(privs) == 0 {
+		return fmt.Sprintf("REVOKE GRANT OPTION ON %s FROM %s", g.Level, g.Grantee)
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privs, ", "), g.Level, g.Grantee)
+}
+
+///// Tokenization /////////////////////////////////////////////////////////////
+
+type token struct {
+	val string
+	typ tengo.TokenType
+}
+
+// is returns true if t is a bare word equal to the supplied lower-case word,
+// compared case-insensitively.
+func (t token) is(word string) bool {
+	return t.typ == tengo.TokenWord && strings.ToLower(t.val) == word
+}
+
+// tokenize splits stmt into tokens, omitting whitespace and comments, and
+// stopping at the first delimiter.
+func tokenize(stmt string) (toks []token) {
+	lexer := tengo.NewLexer(strings.NewReader(stmt), ";", 4096)
+	for {
+		data, typ, err := lexer.Scan()
+		if err != nil || typ == tengo.TokenDelimiter {
+			return toks
+		} else if typ != tengo.TokenFiller {
+			toks = append(toks, token{val: string(data), typ: typ})
+		}
+	}
+}
+
+// splitList splits toks on commas which are not within parentheses.
+func splitList(toks []token) (items [][]token) {
+	var depth, start int
+	for n, t := range toks {
+		if t.val == "(" {
+			depth++
+		} else if t.val == ")" {
+			depth--
+		} else if t.val == "," && depth == 0 {
+			items = append(items, toks[start:n])
+			start = n + 1
+		}
+	}
+	if start < len(toks) {
+		items = append(items, toks[start:])
+	}
+	return items
+}
+
+// unquote returns the value of an identifier or string token, or a bare word
+// as-is.
+func unquote(t token) string {
+	switch t.typ {
+	case tengo.TokenIdent:
+		return strings.ReplaceAll(t.val[1:len(t.val)-1], "``", "`")
+	case tengo.TokenString:
+		quote := t.val[0:1]
+		return strings.ReplaceAll(t.val[1:len(t.val)-1], quote+quote, quote)
+	}
+	return t.val
+}
+
+var errMalformed = errors.New("malformed clause")
+
+// normalizePrivilege returns a canonical form of a single privilege, for
+// example "SELECT" or "SELECT (`a`, `b`)".
+func normalizePrivilege(toks []token) (string, error) {
+	var words []string
+	var cols []string
+	for n := 0; n < len(toks); n++ {
+		if toks[n].val == "(" {
+			for n++; n < len(toks) && toks[n].val != ")"; n++ {
+				if toks[n].val != "," {
+					cols = append(cols, tengo.EscapeIdentifier(unquote(toks[n])))
+				}
+			}
+			if n != len(toks)-1 || len(cols) == 0 {
+				return "", errMalformed
+			}
+		} else if toks[n].typ == tengo.TokenWord {
+			words = append(words, strings.ToUpper(toks[n].val))
+		} else {
+			return "", errMalformed
+		}
+	}
+	if len(words) == 0 {
+		return "", errMalformed
+	}
+	priv := strings.Join(words, " ")
+	if priv == "ALL" {
+		priv = "ALL PRIVILEGES"
+	}
+	if len(cols) > 0 {
+		priv += " (" + strings.Join(cols, ", ") + ")"
+	}
+	return priv, nil
+}
+
+// normalizeLevel returns a canonical form of a privilege level, such as *.* or
+// `db`.* or FUNCTION `db`.`func`. The optional TABLE object type is omitted.
+func normalizeLevel(toks []token) (string, error) {
+	var objType string
+	if len(toks) > 1 && (toks[0].is("table") || toks[0].is("function") || toks[0].is("procedure")) {
+		objType = strings.ToUpper(toks[0].val)
+		toks = toks[1:]
+	}
+	var parts []string
+	for n, t := range toks {
+		if n%2 == 1 {
+			if t.val != "." {
+				return "", errMalformed
+			}
+		} else if t.val == "*" {
+			parts = append(parts, "*")
+		} else if t.typ == tengo.TokenIdent || t.typ == tengo.TokenWord {
+			parts = append(parts, tengo.EscapeIdentifier(unquote(t)))
+		} else {
+			return "", errMalformed
+		}
+	}
+	if len(parts) == 0 || len(parts) > 2 || len(toks)%2 == 0 {
+		return "", errMalformed
+	}
+	level := strings.Join(parts, ".")
+	if objType == "FUNCTION" || objType == "PROCEDURE" {
+		level = objType + " " + level
+	}
+	return level, nil
+}
+
+// normalizeAccount returns a canonical escaped form of an account name.
+func normalizeAccount(toks []token) (string, error) {
+	user, host, rest := parseAccount(toks)
+	if len(rest) > 0 || (user == "" && host == nil) {
+		return "", errMalformed
+	}
+	return escapeAccount(user, host), nil
+}
+
+// parseAccount parses an account name from the beginning of toks, returning
+// its user and host (nil if no host was specified), along with any remaining
+// tokens.
+func parseAccount(toks []token) (user string, host *string, rest []token) {
+	if len(toks) == 0 || toks[0].typ == tengo.TokenSymbol {
+		return "", nil, toks
+	}
+	user = unquote(toks[0])
+	if len(toks) >= 3 && toks[1].val == "@" {
+		h := unquote(toks[2])
+		return user, &h, toks[3:]
+	}
+	return user, nil, toks[1:]
+}
+
+// escapeAccount returns an account name in the format used by SHOW GRANTS in
+// MySQL 8, with backticks around both the user and host. If host is nil, only
+// the user portion is returned; this is used for MariaDB roles.
+func escapeAccount(user string, host *string) string {
+	if host == nil {
+		return tengo.EscapeIdentifier(user)
+	}
+	return tengo.EscapeIdentifier(user) + "@" + tengo.EscapeIdentifier(*host)
+}
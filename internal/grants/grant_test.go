@@ -0,0 +1,81 @@
+package grants
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGrant(t *testing.T) {
+	cases := map[string]string{
+		"GRANT SELECT, INSERT ON `app`.* TO `reader`@`%`":                                       "GRANT INSERT, SELECT ON `app`.* TO `reader`@`%`",
+		"grant all on *.* to 'root'@'localhost' with grant option":                              "GRANT ALL PRIVILEGES ON *.* TO `root`@`localhost` WITH GRANT OPTION",
+		"GRANT USAGE ON *.* TO 'bob'@'%' IDENTIFIED BY PASSWORD '*ABCDEF'":                      "GRANT USAGE ON *.* TO `bob`@`%`",
+		"GRANT SELECT (b, `a`), UPDATE (a) ON app.users TO bob@localhost":                       "GRANT SELECT (`b`, `a`), UPDATE (`a`) ON `app`.`users` TO `bob`@`localhost`",
+		"GRANT EXECUTE ON PROCEDURE `app`.`myproc` TO `bob`@`%`":                                "GRANT EXECUTE ON PROCEDURE `app`.`myproc` TO `bob`@`%`",
+		"GRANT SELECT ON TABLE app.users TO bob@'%'":                                            "GRANT SELECT ON `app`.`users` TO `bob`@`%`",
+		"GRANT PROXY ON ''@'' TO 'root'@'localhost' WITH GRANT OPTION":                          "GRANT PROXY ON ``@`` TO `root`@`localhost` WITH GRANT OPTION",
+		"GRANT `r_read`@`%`,`r_write`@`%` TO `bob`@`%`":                                         "GRANT `r_read`@`%`, `r_write`@`%` TO `bob`@`%`",
+		"GRANT developer TO 'bob'@'%' WITH ADMIN OPTION":                                        "GRANT `developer` TO `bob`@`%` WITH ADMIN OPTION",
+		"GRANT BACKUP_ADMIN,SYSTEM_VARIABLES_ADMIN ON *.* TO `ops`@`10.0.0.%`":                  "GRANT BACKUP_ADMIN, SYSTEM_VARIABLES_ADMIN ON *.* TO `ops`@`10.0.0.%`",
+		"GRANT SELECT ON `we``ird`.* TO `bob`@`%`; -- trailing comment":                         "GRANT SELECT ON `we``ird`.* TO `bob`@`%`",
+		"GRANT CREATE TEMPORARY TABLES, LOCK TABLES ON `app`.* TO `bob`@`%` REQUIRE NONE":       "GRANT CREATE TEMPORARY TABLES, LOCK TABLES ON `app`.* TO `bob`@`%`",
+		"GRANT SELECT ON `app`.* TO `analytics`":                                                "GRANT SELECT ON `app`.* TO `analytics`",
+		"GRANT REPLICATION SLAVE ON *.* TO `repl`@`%` WITH MAX_USER_CONNECTIONS 5 GRANT OPTION": "GRANT REPLICATION SLAVE ON *.* TO `repl`@`%` WITH GRANT OPTION",
+	}
+	for input, expected := range cases {
+		g, err := ParseGrant(input)
+		if err != nil {
+			t.Errorf("Unexpected error from ParseGrant(%q): %v", input, err)
+		} else if actual := g.String(); actual != expected {
+			t.Errorf("Unexpected result from ParseGrant(%q).String():\nexpected: %s\nactual:   %s", input, expected, actual)
+		}
+	}
+
+	badInputs := []string{
+		"",
+		"REVOKE SELECT ON *.* FROM `bob`@`%`",
+		"GRANT SELECT ON *.*",
+		"GRANT ON *.* TO `bob`@`%`",
+		"GRANT SELECT ON a.b.c TO `bob`@`%`",
+		"GRANT SELECT ON *.* TO `bob`@`%`, `alice`@`%`",
+		"GRANT TO `bob`@`%`",
+	}
+	for _, input := range badInputs {
+		if g, err := ParseGrant(input); err == nil {
+			t.Errorf("Expected error from ParseGrant(%q), but instead it returned %+v", input, *g)
+		}
+	}
+}
+
+func TestGrantAccount(t *testing.T) {
+	cases := map[string]string{
+		"GRANT SELECT ON *.* TO `bob`@`%`":          "bob@%",
+		"GRANT SELECT ON *.* TO 'b@b'@'localhost'":  "b@b@localhost",
+		"GRANT SELECT ON *.* TO `analytics`":        "analytics",
+		"GRANT `r1`@`%` TO `bob`@`10.0.0.0/8`":      "bob@10.0.0.0/8",
+		"GRANT SELECT ON *.* TO `we``ird`@`%`":      "we`ird@%",
+		"GRANT SELECT ON *.* TO \"dq\"@\"example\"": "dq@example",
+	}
+	for input, expected := range cases {
+		g, err := ParseGrant(input)
+		if err != nil {
+			t.Errorf("Unexpected error from ParseGrant(%q): %v", input, err)
+		} else if actual := g.Account(); actual != expected {
+			t.Errorf("Expected Account() for %q to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
+func TestRevokeStatement(t *testing.T) {
+	g := &Grant{Grantee: "`bob`@`%`", Level: "`app`.*", Privileges: []string{"INSERT", "SELECT"}}
+	if actual, expected := g.revokeStatement([]string{"INSERT"}), "REVOKE INSERT ON `app`.* FROM `bob`@`%`"; actual != expected {
+		t.Errorf("Expected %q, instead found %q", expected, actual)
+	}
+	if actual := g.revokeStatement(nil); !strings.HasPrefix(actual, "REVOKE GRANT OPTION ON") {
+		t.Errorf("Unexpected result from revokeStatement(nil): %q", actual)
+	}
+	role := &Grant{Grantee: "`bob`@`%`", Privileges: []string{"`r1`@`%`"}}
+	if actual, expected := role.revokeStatement(role.Privileges), "REVOKE `r1`@`%` FROM `bob`@`%`"; actual != expected {
+		t.Errorf("Expected %q, instead found %q", expected, actual)
+	}
+}
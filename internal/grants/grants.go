@@ -0,0 +1,195 @@
+// Package grants manages account privileges as part of the filesystem
+// representation of a database instance. Privileges are expressed as GRANT
+// statements in the *.sql files of a host-level directory, which `skeema pull`
+// keeps in sync with the output of SHOW GRANTS, and which `skeema push`
+// reconciles with the instance using GRANT and REVOKE statements.
+//
+// Accounts themselves are never created or dropped by this package, since
+// doing so would require managing credentials. Grants to accounts which do not
+// exist on the instance are reported as errors instead.
+package grants
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// FileName is the name of the file, within a host-level directory, to which
+// grants are written by pull and init.
+const FileName = "grants.sql"
+
+// systemAccounts lists the users of reserved accounts created automatically
+// by the server. These are never introspected or modified.
+var systemAccounts = map[string]bool{
+	"mysql.sys":        true,
+	"mysql.session":    true,
+	"mysql.infoschema": true,
+	"mariadb.sys":      true,
+}
+
+// Options controls whether privileges are managed, and which grantees are
+// excluded from management.
+type Options struct {
+	Enabled       bool
+	IgnoreGrantee *regexp.Regexp // matched against user@host, or just the name of a MariaDB role
+}
+
+// AddCommandOptions adds grants-related options to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("grants",
+		mybase.BoolOption("grants", 0, false, "Manage account privileges via GRANT statements in each host-level dir's *.sql files"),
+		mybase.StringOption("ignore-grantee", 0, "", "Ignore privileges of accounts matching this regular expression of user@host"),
+	)
+}
+
+// OptionsForDir returns Options based on the configuration of dir. Privileges
+// are only managed in directories which configure a host but not a schema,
+// since such a directory represents an entire database instance.
+func OptionsForDir(dir *fs.Dir) (opts Options, err error) {
+	opts.Enabled = dir.Config.GetBool("grants") && dir.Config.Changed("host") && !dir.HasSchema()
+	opts.IgnoreGrantee, err = dir.Config.GetRegexp("ignore-grantee")
+	return opts, err
+}
+
+// ShouldIgnore returns true if the grantee of g matches the ignore-grantee
+// option.
+func (opts Options) ShouldIgnore(g *Grant) bool {
+	return opts.IgnoreGrantee != nil && opts.IgnoreGrantee.MatchString(g.Account())
+}
+
+// Introspect returns the grants of all accounts on inst, aside from reserved
+// system accounts and any accounts ignored by opts. A map of the escaped names
+// of all existing accounts is also returned, regardless of opts, for purposes
+// of determining whether grants to an account may be applied.
+func Introspect(inst *tengo.Instance, opts Options) (Set, map[string]bool, error) {
+	db, err := inst.CachedConnectionPool("", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	var rawAccounts []struct {
+		User string `db:"user"`
+		Host string `db:"host"`
+	}
+	query := "SELECT user AS user, host AS host FROM mysql.user ORDER BY user, host"
+	if err := db.Select(&rawAccounts, query); err != nil {
+		return nil, nil, fmt.Errorf("Unable to query accounts on %s: %s", inst, err)
+	}
+
+	set := make(Set)
+	accounts := make(map[string]bool, len(rawAccounts))
+	for _, acct := range rawAccounts {
+		if systemAccounts[acct.User] {
+			continue
+		}
+		// MariaDB roles have a blank host, and are referred to by name only
+		host := &acct.Host
+		if acct.Host == "" && inst.Flavor().IsMariaDB() {
+			host = nil
+		}
+		grantee := escapeAccount(acct.User, host)
+		accounts[grantee] = true
+		var lines []string
+		if err := db.Select(&lines, "SHOW GRANTS FOR "+grantee); err != nil {
+			return nil, nil, fmt.Errorf("Unable to query grants for %s on %s: %s", grantee, inst, err)
+		}
+		for _, line := range lines {
+			// Skip other statement types, such as SET DEFAULT ROLE in MariaDB, or
+			// REVOKE when MySQL's partial_revokes is enabled
+			if toks := tokenize(line); len(toks) == 0 || !toks[0].is("grant") {
+				continue
+			}
+			g, err := ParseGrant(line)
+			if err != nil {
+				return nil, nil, err
+			} else if !opts.ShouldIgnore(g) {
+				set.Add(g)
+			}
+		}
+	}
+	return set, accounts, nil
+}
+
+// FromDir returns the grants expressed by GRANT statements in dir's *.sql
+// files, aside from any ignored by opts.
+func FromDir(dir *fs.Dir, opts Options) (Set, error) {
+	set := make(Set)
+	for _, stmt := range dir.GrantStatements {
+		g, err := ParseGrant(stmt.Body())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", stmt.Location(), err)
+		} else if !opts.ShouldIgnore(g) {
+			set.Add(g)
+		}
+	}
+	return set, nil
+}
+
+// WriteDir rewrites the GRANT statements in dir's *.sql files to reflect the
+// supplied grants. All non-ignored GRANT statements are placed in FileName in
+// canonical form, and removed from any other files. If the dir already
+// contains exactly these statements, no files are modified. The number of
+// files written is returned.
+func WriteDir(dir *fs.Dir, set Set, opts Options) (int, error) {
+	path := filepath.Join(dir.Path, FileName)
+	var existing []*tengo.Statement
+	for _, stmt := range dir.GrantStatements {
+		if g, err := ParseGrant(stmt.Body()); err != nil || !opts.ShouldIgnore(g) {
+			existing = append(existing, stmt)
+		}
+	}
+	grants := set.Grants()
+	if alreadyCanonical(existing, grants, path) {
+		return 0, nil
+	}
+
+	for _, stmt := range existing {
+		dir.FileFor(stmt).RemoveStatement(stmt)
+	}
+	if dir.SQLFiles[path] == nil {
+		dir.SQLFiles[path] = &fs.SQLFile{
+			FilePath:   path,
+			Statements: []*tengo.Statement{},
+		}
+	}
+	for _, g := range grants {
+		stmt := tengo.ParseStatementInString(g.String() + ";\n")
+		stmt.File = path
+		dir.SQLFiles[path].AddStatement(stmt)
+	}
+
+	filesWritten := dir.DirtyFiles()
+	for n, file := range filesWritten {
+		exists, _ := file.Exists()
+		if bytesWritten, err := file.Write(); err != nil {
+			return n, err
+		} else if bytesWritten == 0 {
+			log.Infof("Deleted %s", file.FilePath)
+		} else if exists {
+			log.Infof("Wrote %s (%d bytes)", file.FilePath, bytesWritten)
+		} else {
+			log.Infof("Created %s (%d bytes)", file.FilePath, bytesWritten)
+		}
+	}
+	return len(filesWritten), nil
+}
+
+// alreadyCanonical returns true if stmts all reside in the file at path, and
+// consist of exactly the canonical form of grants, in the same order.
+func alreadyCanonical(stmts []*tengo.Statement, grants []*Grant, path string) bool {
+	if len(stmts) != len(grants) {
+		return false
+	}
+	for n, stmt := range stmts {
+		if stmt.File != path || stmt.Body() != grants[n].String() {
+			return false
+		}
+	}
+	return true
+}
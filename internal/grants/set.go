@@ -0,0 +1,145 @@
+package grants
+
+import (
+	"sort"
+)
+
+// Set is a collection of grants, merged such that there is at most one Grant
+// for each combination of grantee and privilege level. Role grants are split
+// such that there is one Grant per role, since the ADMIN OPTION is tracked
+// separately for each role.
+type Set map[string]*Grant
+
+// Add merges g into the set. Grants which do not actually grant anything, such
+// as GRANT USAGE, are ignored.
+func (s Set) Add(g *Grant) {
+	if g.empty() {
+		return
+	}
+	if g.IsRole() && len(g.Privileges) > 1 {
+		for _, role := range g.Privileges {
+			s.Add(&Grant{Grantee: g.Grantee, Privileges: []string{role}, WithGrantOption: g.WithGrantOption})
+		}
+		return
+	}
+	existing, ok := s[g.key()]
+	if !ok {
+		copied := *g
+		copied.Privileges = append([]string(nil), g.Privileges...)
+		s[g.key()] = &copied
+		return
+	}
+	existing.WithGrantOption = existing.WithGrantOption || g.WithGrantOption
+	if !existing.IsRole() {
+		existing.Privileges = union(existing.Privileges, g.Privileges)
+	}
+}
+
+// Grants returns the grants in the set, sorted by grantee and then by
+// privilege level, with role grants after privilege grants for each grantee.
+func (s Set) Grants() []*Grant {
+	result := make([]*Grant, 0, len(s))
+	for _, g := range s {
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Grantee != result[j].Grantee {
+			return result[i].Grantee < result[j].Grantee
+		} else if result[i].IsRole() != result[j].IsRole() {
+			return !result[i].IsRole()
+		}
+		return result[i].key() < result[j].key()
+	})
+	return result
+}
+
+// Change represents a single GRANT or REVOKE statement needed to reconcile two
+// Sets.
+type Change struct {
+	Grantee   string
+	Statement string
+	Revoke    bool // true if this change removes privileges, which is considered unsafe
+}
+
+// Diff returns the changes needed to transform from into to. For each grantee
+// and privilege level, any REVOKE precedes any GRANT, so that replacing ALL
+// PRIVILEGES with a narrower list of privileges behaves as expected.
+func Diff(from, to Set) (changes []Change) {
+	// Iterate over the union of keys in a deterministic order
+	all := make(Set, len(from)+len(to))
+	for key, g := range from {
+		all[key] = g
+	}
+	for key, g := range to {
+		all[key] = g
+	}
+	for _, g := range all.Grants() {
+		fromGrant, toGrant := from[g.key()], to[g.key()]
+		if fromGrant == nil {
+			fromGrant = &Grant{Grantee: g.Grantee, Level: g.Level}
+		}
+		if toGrant == nil {
+			toGrant = &Grant{Grantee: g.Grantee, Level: g.Level}
+		}
+
+		if g.IsRole() {
+			// There is no portable way to revoke only the ADMIN OPTION, so the role
+			// is revoked and re-granted in that situation
+			role := g.Privileges
+			if len(fromGrant.Privileges) > 0 && (len(toGrant.Privileges) == 0 || (fromGrant.WithGrantOption && !toGrant.WithGrantOption)) {
+				changes = append(changes, Change{Grantee: g.Grantee, Statement: fromGrant.revokeStatement(role), Revoke: true})
+				fromGrant = &Grant{Grantee: g.Grantee}
+			}
+			if len(toGrant.Privileges) > 0 && (len(fromGrant.Privileges) == 0 || toGrant.WithGrantOption != fromGrant.WithGrantOption) {
+				changes = append(changes, Change{Grantee: g.Grantee, Statement: toGrant.String()})
+			}
+			continue
+		}
+
+		if revoke := difference(fromGrant.Privileges, toGrant.Privileges); len(revoke) > 0 {
+			changes = append(changes, Change{Grantee: g.Grantee, Statement: fromGrant.revokeStatement(revoke), Revoke: true})
+		}
+		if fromGrant.WithGrantOption && !toGrant.WithGrantOption {
+			changes = append(changes, Change{Grantee: g.Grantee, Statement: fromGrant.revokeStatement(nil), Revoke: true})
+		}
+		add := &Grant{
+			Grantee:         g.Grantee,
+			Level:           g.Level,
+			Privileges:      difference(toGrant.Privileges, fromGrant.Privileges),
+			WithGrantOption: toGrant.WithGrantOption && !fromGrant.WithGrantOption,
+		}
+		if !add.empty() {
+			changes = append(changes, Change{Grantee: g.Grantee, Statement: add.String()})
+		}
+	}
+	return changes
+}
+
+// union returns the sorted union of two sorted string slices.
+func union(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var result []string
+	for _, s := range append(append([]string(nil), a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// difference returns the elements of a which are not in b, retaining the order
+// of a.
+func difference(a, b []string) (result []string) {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	for _, s := range a {
+		if !inB[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package grants
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/util"
+)
+
+func setFromStrings(t *testing.T, stmts ...string) Set {
+	t.Helper()
+	s := make(Set)
+	for _, stmt := range stmts {
+		g, err := ParseGrant(stmt)
+		if err != nil {
+			t.Fatalf("Unexpected error from ParseGrant: %v", err)
+		}
+		s.Add(g)
+	}
+	return s
+}
+
+func grantStrings(s Set) (result []string) {
+	for _, g := range s.Grants() {
+		result = append(result, g.String())
+	}
+	return result
+}
+
+func TestSetAdd(t *testing.T) {
+	s := setFromStrings(t,
+		"GRANT USAGE ON *.* TO `bob`@`%`",
+		"GRANT SELECT ON `app`.* TO `bob`@`%`",
+		"GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`",
+		"GRANT `r1`@`%`, `r2`@`%` TO `bob`@`%`",
+		"GRANT RELOAD ON *.* TO `alice`@`%`",
+		"GRANT USAGE ON *.* TO `alice`@`%` WITH GRANT OPTION",
+	)
+	expected := []string{
+		"GRANT RELOAD ON *.* TO `alice`@`%` WITH GRANT OPTION",
+		"GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`",
+		"GRANT `r1`@`%` TO `bob`@`%`",
+		"GRANT `r2`@`%` TO `bob`@`%`",
+	}
+	if actual := grantStrings(s); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected grants in set:\nexpected: %v\nactual:   %v", expected, actual)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := setFromStrings(t,
+		"GRANT ALL PRIVILEGES ON `app`.* TO `bob`@`%`",
+		"GRANT SELECT ON `old`.* TO `bob`@`%`",
+		"GRANT `r1`@`%` TO `bob`@`%` WITH ADMIN OPTION",
+		"GRANT RELOAD ON *.* TO `ops`@`%` WITH GRANT OPTION",
+		"GRANT SELECT ON `app`.* TO `same`@`%`",
+	)
+	to := setFromStrings(t,
+		"GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`",
+		"GRANT `r1`@`%` TO `bob`@`%`",
+		"GRANT `r2`@`%` TO `bob`@`%`",
+		"GRANT PROCESS, RELOAD ON *.* TO `ops`@`%`",
+		"GRANT SELECT ON `app`.* TO `same`@`%`",
+	)
+	expected := []Change{
+		{Grantee: "`bob`@`%`", Statement: "REVOKE ALL PRIVILEGES ON `app`.* FROM `bob`@`%`", Revoke: true},
+		{Grantee: "`bob`@`%`", Statement: "GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`"},
+		{Grantee: "`bob`@`%`", Statement: "REVOKE SELECT ON `old`.* FROM `bob`@`%`", Revoke: true},
+		{Grantee: "`bob`@`%`", Statement: "REVOKE `r1`@`%` FROM `bob`@`%`", Revoke: true},
+		{Grantee: "`bob`@`%`", Statement: "GRANT `r1`@`%` TO `bob`@`%`"},
+		{Grantee: "`bob`@`%`", Statement: "GRANT `r2`@`%` TO `bob`@`%`"},
+		{Grantee: "`ops`@`%`", Statement: "REVOKE GRANT OPTION ON *.* FROM `ops`@`%`", Revoke: true},
+		{Grantee: "`ops`@`%`", Statement: "GRANT PROCESS ON *.* TO `ops`@`%`"},
+	}
+	if actual := Diff(from, to); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected result from Diff:\nexpected: %+v\nactual:   %+v", expected, actual)
+	}
+	if changes := Diff(to, to); len(changes) != 0 {
+		t.Errorf("Expected no changes from diffing a set against itself, instead found %+v", changes)
+	}
+}
+
+func TestFromDirWriteDir(t *testing.T) {
+	dirPath := t.TempDir()
+	contents := map[string]string{
+		".skeema":   "host=127.0.0.1\ngrants\nignore-grantee=^monitor@\n",
+		"users.sql": "GRANT SELECT ON app.* TO bob@'%';\nGRANT PROCESS ON *.* TO 'monitor'@'localhost';\n",
+		"more.sql":  "grant insert on `app`.* to `bob`@`%`;\n",
+	}
+	for name, body := range contents {
+		if err := os.WriteFile(filepath.Join(dirPath, name), []byte(body), 0666); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+	cmd := mybase.NewCommand("grantstest", "", "", nil)
+	util.AddGlobalOptions(cmd)
+	AddCommandOptions(cmd)
+	cmd.AddArg("environment", "production", false)
+	cfg := mybase.ParseFakeCLI(t, cmd, "grantstest")
+	dir, err := fs.ParseDir(dirPath, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseDir: %v", err)
+	} else if len(dir.GrantStatements) != 3 || len(dir.LogicalSchemas) != 0 {
+		t.Fatalf("Expected dir to have 3 GrantStatements and no LogicalSchemas, instead found %d and %d", len(dir.GrantStatements), len(dir.LogicalSchemas))
+	}
+	opts, err := OptionsForDir(dir)
+	if err != nil || !opts.Enabled || opts.IgnoreGrantee == nil || opts.IgnoreGrantee.String() != "^monitor@" {
+		t.Fatalf("Unexpected result from OptionsForDir: %+v, %v", opts, err)
+	}
+	set, err := FromDir(dir, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error from FromDir: %v", err)
+	} else if actual := grantStrings(set); len(actual) != 1 || actual[0] != "GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`" {
+		t.Fatalf("Unexpected result from FromDir: %v", actual)
+	}
+
+	// Writing should consolidate the non-ignored grants into grants.sql, leaving
+	// the ignored grant in place
+	if n, err := WriteDir(dir, set, opts); err != nil || n != 3 {
+		t.Fatalf("Expected WriteDir to write 3 files without error, instead found %d, %v", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(dirPath, "more.sql")); !os.IsNotExist(err) {
+		t.Errorf("Expected more.sql to be deleted, but stat returned %v", err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dirPath, "users.sql")); err != nil || strings.Contains(string(b), "bob") || !strings.Contains(string(b), "monitor") {
+		t.Errorf("Unexpected contents of users.sql: %q, %v", b, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dirPath, FileName)); err != nil || string(b) != "GRANT INSERT, SELECT ON `app`.* TO `bob`@`%`;\n" {
+		t.Errorf("Unexpected contents of %s: %q, %v", FileName, b, err)
+	}
+
+	// Re-parsing and writing again should be a no-op
+	if dir, err = fs.ParseDir(dirPath, cfg); err != nil {
+		t.Fatalf("Unexpected error from ParseDir: %v", err)
+	}
+	if n, err := WriteDir(dir, set, opts); err != nil || n != 0 {
+		t.Errorf("Expected WriteDir to be a no-op, instead wrote %d files, err=%v", n, err)
+	}
+}
@@ -18,9 +18,9 @@ func init() {
 	RegisterRule(Rule{
 		CheckerFunc:     GenericChecker(definerChecker),
 		Name:            "definer",
-		Description:     "Only allow routine definers listed in --allow-definer",
+		Description:     "Only allow routine, view, and trigger definers listed in --allow-definer",
 		DefaultSeverity: SeverityError,
-		RelatedOption:   mybase.StringOption("allow-definer", 0, "%@%", "List of allowed routine definers for --lint-definer"),
+		RelatedOption:   mybase.StringOption("allow-definer", 0, "%@%", "List of allowed routine, view, and trigger definers for --lint-definer"),
 		ConfigFunc:      RuleConfigFunc(definerConfiger),
 	})
 }
@@ -47,9 +47,14 @@ func definerChecker(object tengo.DefKeyer, createStatement string, schema *tengo
 	}
 
 	var typ, name, definer string
-	if object, ok := object.(*tengo.Routine); ok {
+	switch object := object.(type) {
+	case *tengo.Routine:
 		typ, name, definer = strings.Title(string(object.Type)), object.Name, object.Definer
-	} else {
+	case *tengo.View:
+		typ, name, definer = "View", object.Name, object.Definer
+	case *tengo.Trigger:
+		typ, name, definer = "Trigger", object.Name, object.Definer
+	default:
 		return nil
 	}
 
@@ -18,7 +18,7 @@ func init() {
 
 var reHasFK = regexp.MustCompile(`(?i)foreign key`)
 
-func hasForeignKeysChecker(table *tengo.Table, createStatement string, _ *tengo.Schema, _ Options) *Note {
+func hasForeignKeysChecker(table *tengo.Table, createStatement string, _ *tengo.Schema, opts Options) *Note {
 	if len(table.ForeignKeys) == 0 {
 		return nil
 	}
@@ -30,6 +30,9 @@ func hasForeignKeysChecker(table *tengo.Table, createStatement string, _ *tengo.
 		"Table %s has %d foreign key%s. Foreign keys may harm write performance, and can be problematic for online schema change tools. They are also ineffective in sharded environments.",
 		table.Name, len(table.ForeignKeys), plural,
 	)
+	if !opts.Flavor.EnforcesForeignKeys() {
+		message += fmt.Sprintf(" Additionally, %s does not enforce foreign key constraints.", opts.Flavor.Family())
+	}
 	return &Note{
 		LineOffset: FindFirstLineOffset(reHasFK, createStatement),
 		Summary:    "Table has foreign keys",
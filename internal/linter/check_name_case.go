@@ -23,8 +23,7 @@ func nameCaseChecker(object tengo.DefKeyer, createStatement string, _ *tengo.Sch
 
 	// Only tables and views are affected by name-casing problems. (Also database
 	// names, but Skeema does not lint those currently...)
-	// Community edition does not handle views though, so only tables can match.
-	if typ != tengo.ObjectTypeTable {
+	if typ != tengo.ObjectTypeTable && typ != tengo.ObjectTypeView {
 		return nil
 	}
 
@@ -37,14 +36,15 @@ func nameCaseChecker(object tengo.DefKeyer, createStatement string, _ *tengo.Sch
 	} else {
 		// Non-canonicalized CREATE may include arbitrary whitespace, and may or may
 		// not use backticks. We just want to check the CREATE segment after "table"
-		// and before the first open-paren, unless we can't find them (e.g. CREATE
-		// TABLE ... LIKE), in which case we fall back to searching the full CREATE.
+		// or "view" and before the first open-paren, unless we can't find them (e.g.
+		// CREATE TABLE ... LIKE), in which case we fall back to searching the full
+		// CREATE.
 		var startPos, endPos int
 		if endPos = strings.Index(createStatement, "("); endPos < 0 {
 			endPos = len(createStatement)
 		}
-		if tableKeywordPos := strings.Index(strings.ToLower(createStatement[0:endPos]), "table"); tableKeywordPos >= 0 {
-			startPos = tableKeywordPos + 5 // len("table")
+		if typeKeywordPos := strings.Index(strings.ToLower(createStatement[0:endPos]), string(typ)); typeKeywordPos >= 0 {
+			startPos = typeKeywordPos + len(typ)
 		}
 		if strings.Contains(createStatement[startPos:endPos], name) {
 			return nil
@@ -0,0 +1,52 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func init() {
+	RegisterRule(Rule{
+		CheckerFunc:     TableBinaryChecker(unsupportedChecker),
+		Name:            "unsupported",
+		Description:     "Flag tables using features that Skeema cannot diff, along with the likely cause",
+		DefaultSeverity: SeverityWarning,
+	})
+}
+
+var reFullText = regexp.MustCompile(`(?i)fulltext`)
+
+func unsupportedChecker(table *tengo.Table, createStatement string, _ *tengo.Schema, opts Options) *Note {
+	details := table.UnsupportedDetails(opts.Flavor)
+	if details == nil {
+		return unsupportedFullTextChecker(table, createStatement, opts)
+	}
+	message := fmt.Sprintf(
+		"Table %s uses features or syntax which Skeema does not support, so Skeema cannot generate ALTERs for this table. Likely cause: %s.\nDifference between the CREATE TABLE that Skeema expected and the actual SHOW CREATE TABLE:\n%s",
+		table.Name, details.Reason, details.Diff,
+	)
+	return &Note{
+		Summary: "Table uses unsupported features",
+		Message: message,
+	}
+}
+
+// unsupportedFullTextChecker flags FULLTEXT indexes in flavors which accept
+// their definitions but ignore them, such as TiDB.
+func unsupportedFullTextChecker(table *tengo.Table, createStatement string, opts Options) *Note {
+	if opts.Flavor.FullTextIndexes() {
+		return nil
+	}
+	for _, idx := range table.SecondaryIndexes {
+		if idx.Type == "FULLTEXT" {
+			return &Note{
+				LineOffset: FindFirstLineOffset(reFullText, createStatement),
+				Summary:    "Table uses unsupported features",
+				Message:    fmt.Sprintf("Table %s has FULLTEXT index %s, but %s does not support FULLTEXT indexes. The server will ignore this index.", table.Name, idx.Name, opts.Flavor.Family()),
+			}
+		}
+	}
+	return nil
+}
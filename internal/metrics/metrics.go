@@ -0,0 +1,161 @@
+// Package metrics accumulates counters and timings describing Skeema's
+// operations, and emits them to external monitoring systems: a statsd daemon
+// or a Prometheus pushgateway.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+)
+
+// AddCommandOptions adds metrics-related option definitions to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("metrics",
+		mybase.StringOption("statsd-address", 0, "", "Emit metrics via UDP to statsd daemon at this host:port"),
+		mybase.StringOption("pushgateway-url", 0, "", "Emit metrics via HTTP to Prometheus pushgateway at this base URL"),
+	)
+}
+
+// Collector accumulates counters and timings in memory, for emission once an
+// operation completes. It is safe for concurrent use.
+type Collector struct {
+	prefix   string
+	mu       sync.Mutex
+	counters map[string]int64
+	timings  map[string][]time.Duration
+}
+
+// NewCollector returns a Collector which prepends prefix to all metric names.
+func NewCollector(prefix string) *Collector {
+	return &Collector{
+		prefix:   prefix,
+		counters: make(map[string]int64),
+		timings:  make(map[string][]time.Duration),
+	}
+}
+
+// Count adds delta to the named counter.
+func (c *Collector) Count(name string, delta int) {
+	c.mu.Lock()
+	c.counters[name] += int64(delta)
+	c.mu.Unlock()
+}
+
+// Timing records a duration for the named timer. If the same timer is recorded
+// multiple times, each duration is retained as a separate observation, so that
+// the monitoring system can compute its own aggregates.
+func (c *Collector) Timing(name string, elapsed time.Duration) {
+	c.mu.Lock()
+	c.timings[name] = append(c.timings[name], elapsed)
+	c.mu.Unlock()
+}
+
+// Emit sends all accumulated metrics to each destination configured in cfg.
+// Failures are logged as warnings rather than returned, since an unreachable
+// monitoring system should not cause the underlying operation to fail.
+func (c *Collector) Emit(cfg *mybase.Config) {
+	if addr := cfg.Get("statsd-address"); addr != "" {
+		if err := c.SendStatsd(addr); err != nil {
+			log.Warnf("Unable to send metrics to statsd at %s: %s", addr, err)
+		}
+	}
+	if baseURL := cfg.Get("pushgateway-url"); baseURL != "" {
+		if err := c.PushPrometheus(baseURL, c.prefix); err != nil {
+			log.Warnf("Unable to push metrics to Prometheus pushgateway at %s: %s", baseURL, err)
+		}
+	}
+}
+
+// SendStatsd sends all accumulated metrics via UDP to the statsd daemon at
+// addr, which should be in host:port format. Metric name components are
+// separated by periods, and each timing observation is sent as a separate
+// statsd timer.
+func (c *Collector) SendStatsd(addr string) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, line := range c.statsdLines() {
+		if _, err := conn.Write([]byte(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Collector) statsdLines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines := make([]string, 0, len(c.counters)+len(c.timings))
+	for name, value := range c.counters {
+		lines = append(lines, fmt.Sprintf("%s.%s:%d|c", c.prefix, name, value))
+	}
+	for name, observations := range c.timings {
+		for _, elapsed := range observations {
+			lines = append(lines, fmt.Sprintf("%s.%s:%d|ms", c.prefix, name, elapsed.Milliseconds()))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// PushPrometheus sends all accumulated metrics via HTTP to the Prometheus
+// pushgateway at baseURL, grouped under the supplied job name. Metric name
+// components are separated by underscores, and timings are expressed as
+// summaries in seconds.
+func (c *Collector) PushPrometheus(baseURL, job string) error {
+	pushURL := strings.TrimRight(baseURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	req, err := http.NewRequest(http.MethodPut, pushURL, bytes.NewBufferString(c.prometheusText()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP response status %s", resp.Status)
+	}
+	return nil
+}
+
+var rePrometheusInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func (c *Collector) prometheusText() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	promName := func(name, suffix string) string {
+		return rePrometheusInvalidChars.ReplaceAllString(c.prefix+"_"+name+suffix, "_")
+	}
+	entries := make([]string, 0, len(c.counters)+len(c.timings))
+	for name, value := range c.counters {
+		name = promName(name, "_total")
+		entries = append(entries, fmt.Sprintf("# TYPE %s counter\n%s %d\n", name, name, value))
+	}
+	for name, observations := range c.timings {
+		name = promName(name, "_seconds")
+		var total time.Duration
+		for _, elapsed := range observations {
+			total += elapsed
+		}
+		entries = append(entries, fmt.Sprintf("# TYPE %s summary\n%s_sum %g\n%s_count %d\n", name, name, total.Seconds(), name, len(observations)))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "")
+}
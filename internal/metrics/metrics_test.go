@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skeema/mybase"
+)
+
+func newTestCollector() *Collector {
+	c := NewCollector("skeema")
+	c.Count("push.targets", 2)
+	c.Count("push.targets", 1)
+	c.Count("push.failures.skipped", 0)
+	c.Timing("push.duration", 1500*time.Millisecond)
+	c.Timing("push.target_duration", 250*time.Millisecond)
+	c.Timing("push.target_duration", 1000*time.Millisecond)
+	return c
+}
+
+func TestCollectorStatsd(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on UDP: %s", err)
+	}
+	defer conn.Close()
+
+	c := newTestCollector()
+	if err := c.SendStatsd(conn.LocalAddr().String()); err != nil {
+		t.Fatalf("Unexpected error from SendStatsd: %s", err)
+	}
+	var received []string
+	buf := make([]byte, 512)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(received) < 5 {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error reading statsd packets: %s", err)
+		}
+		received = append(received, string(buf[:n]))
+	}
+	sort.Strings(received)
+	expected := []string{
+		"skeema.push.duration:1500|ms",
+		"skeema.push.failures.skipped:0|c",
+		"skeema.push.target_duration:1000|ms",
+		"skeema.push.target_duration:250|ms",
+		"skeema.push.targets:3|c",
+	}
+	if fmt.Sprint(received) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected statsd packets: %v", received)
+	}
+}
+
+func TestCollectorPushPrometheus(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+	}))
+	defer server.Close()
+
+	c := newTestCollector()
+	if err := c.PushPrometheus(server.URL+"/", "skeema"); err != nil {
+		t.Fatalf("Unexpected error from PushPrometheus: %s", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/skeema" {
+		t.Errorf("Unexpected request: %s %s", method, path)
+	}
+	for _, expectLine := range []string{
+		"# TYPE skeema_push_targets_total counter\nskeema_push_targets_total 3\n",
+		"skeema_push_failures_skipped_total 0\n",
+		"# TYPE skeema_push_duration_seconds summary\nskeema_push_duration_seconds_sum 1.5\nskeema_push_duration_seconds_count 1\n",
+		"# TYPE skeema_push_target_duration_seconds summary\nskeema_push_target_duration_seconds_sum 1.25\nskeema_push_target_duration_seconds_count 2\n",
+	} {
+		if !strings.Contains(body, expectLine) {
+			t.Errorf("Expected body to contain %q, but it did not. Body:\n%s", expectLine, body)
+		}
+	}
+
+	// Confirm non-2xx responses are errors
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	if err := c.PushPrometheus(server.URL, "skeema"); err == nil {
+		t.Error("Expected error from PushPrometheus with bad response status, but err was nil")
+	}
+}
+
+func TestCollectorEmitNoDestinations(t *testing.T) {
+	cmd := mybase.NewCommand("metricstest", "", "", nil)
+	AddCommandOptions(cmd)
+	cfg := mybase.ParseFakeCLI(t, cmd, "metricstest")
+	newTestCollector().Emit(cfg) // should be a no-op, without panicking
+}
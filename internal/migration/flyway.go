@@ -0,0 +1,177 @@
+package migration
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements Flyway versioned migrations. Each diff is written as a
+// pair of SQL files: a versioned migration containing the generated statements,
+// and an undo migration reversing them. See
+// https://documentation.red-gate.com/fd/migrations-184127470.html for the
+// naming conventions.
+
+func init() {
+	Register(&Format{
+		Name:        "flyway",
+		Description: "Flyway versioned and undo SQL migrations",
+		Write:       WriteFlyway,
+	})
+}
+
+// WriteFlyway writes changes as a Flyway versioned migration and corresponding
+// undo migration. Unless a version is configured, the version is one greater
+// than the highest existing versioned migration in opts.Dir, or 1 if there are
+// none. The Flyway checksum of each file is logged, for comparison against the
+// flyway_schema_history table.
+func WriteFlyway(changes []Change, opts Options) error {
+	version := opts.Version
+	if version == "" {
+		var err error
+		if version, err = nextFlywayVersion(opts); err != nil {
+			return err
+		}
+	}
+	suffix := version + opts.FlywaySeparator + slug(opts.Description, "_") + ".sql"
+	files := []struct {
+		name     string
+		rollback bool
+	}{
+		{opts.FlywayPrefix + suffix, false},
+		{opts.FlywayUndoPrefix + suffix, true},
+	}
+	for _, file := range files {
+		var buf bytes.Buffer
+		writeSQLScript(&buf, changes, file.rollback, true)
+		f, err := opts.create(file.name)
+		if err != nil {
+			return err
+		}
+		if opts.Dir == "" {
+			fmt.Fprintf(f, "-- %s\n", file.name)
+		}
+		_, err = f.Write(buf.Bytes())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Generated %s (Flyway checksum %d)", file.name, flywayChecksum(buf.Bytes()))
+	}
+	return nil
+}
+
+// nextFlywayVersion returns the version following the highest versioned
+// migration in opts.Dir. The last component of a multi-part version is
+// incremented, so V1_2 is followed by V1_3.
+func nextFlywayVersion(opts Options) (string, error) {
+	if opts.Dir == "" {
+		return "1", nil
+	}
+	entries, err := os.ReadDir(opts.Dir)
+	if os.IsNotExist(err) {
+		return "1", nil
+	} else if err != nil {
+		return "", err
+	}
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(opts.FlywayPrefix) + `([0-9]+(?:[._][0-9]+)*)` + regexp.QuoteMeta(opts.FlywaySeparator))
+	var highest []string
+	var highestRaw string
+	for _, entry := range entries {
+		matches := re.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		parts := strings.FieldsFunc(matches[1], func(r rune) bool { return r == '.' || r == '_' })
+		if highest == nil || compareVersions(parts, highest) > 0 {
+			highest, highestRaw = parts, matches[1]
+		}
+	}
+	if highest == nil {
+		return "1", nil
+	}
+	last := highest[len(highest)-1]
+	n, err := strconv.ParseUint(last, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("unable to increment version %s: %w", highestRaw, err)
+	}
+	return highestRaw[:len(highestRaw)-len(last)] + strconv.FormatUint(n+1, 10), nil
+}
+
+// compareVersions compares two versions, each split into numeric parts,
+// returning a negative value if a < b, 0 if equal, or positive if a > b.
+// Missing parts are treated as zero, matching Flyway's version ordering.
+func compareVersions(a, b []string) int {
+	for n := 0; n < len(a) || n < len(b); n++ {
+		var partA, partB uint64
+		if n < len(a) {
+			partA, _ = strconv.ParseUint(a[n], 10, 64)
+		}
+		if n < len(b) {
+			partB, _ = strconv.ParseUint(b[n], 10, 64)
+		}
+		if partA != partB {
+			if partA < partB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// flywayChecksum returns the checksum Flyway computes for a SQL migration: a
+// CRC32 over each line of the file, excluding line terminators and any leading
+// byte order mark, interpreted as a signed 32-bit integer.
+func flywayChecksum(contents []byte) int32 {
+	crc := crc32.NewIEEE()
+	contents = bytes.TrimPrefix(contents, []byte("\xEF\xBB\xBF"))
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for scanner.Scan() {
+		crc.Write(bytes.TrimSuffix(scanner.Bytes(), []byte("\r")))
+	}
+	return int32(crc.Sum32())
+}
+
+// writeSQLScript writes changes to w as a SQL script. If rollback is true, the
+// rollback statements are written in reverse order instead, with a comment for
+// any change which cannot be reversed. If changes affect multiple schemas, USE
+// statements switch the default database as needed. If useDelimiter is true,
+// compound statements are surrounded by DELIMITER commands, which are supported
+// by the mysql client and some migration tools' parsers.
+func writeSQLScript(w io.Writer, changes []Change, rollback, useDelimiter bool) {
+	useSchema := multipleSchemas(changes)
+	var lastSchema string
+	for n := range changes {
+		c, stmt, compound := changes[n], changes[n].Statement, changes[n].Compound
+		if rollback {
+			c = changes[len(changes)-1-n]
+			stmt, compound = c.Rollback, c.RollbackCompound
+		}
+		if stmt == "" {
+			fmt.Fprintf(w, "-- Unable to generate reverse of: %s %s %s\n", c.DiffType, c.ObjectType, tengo.EscapeIdentifier(c.ObjectName))
+			continue
+		}
+		if useSchema && c.Schema != lastSchema {
+			fmt.Fprintf(w, "USE %s;\n", tengo.EscapeIdentifier(c.Schema))
+			lastSchema = c.Schema
+		}
+		if compound && useDelimiter {
+			fmt.Fprintf(w, "DELIMITER //\n%s//\nDELIMITER ;\n", stmt)
+		} else {
+			fmt.Fprintf(w, "%s;\n", stmt)
+		}
+	}
+}
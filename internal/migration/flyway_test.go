@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func flywayTestOptions(dir string) Options {
+	return Options{
+		Dir:              dir,
+		Description:      "Add posts table",
+		FlywayPrefix:     "V",
+		FlywayUndoPrefix: "U",
+		FlywaySeparator:  "__",
+	}
+}
+
+func TestWriteFlyway(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"V1_9__a.sql", "V1_10__b.sql", "U1_10__b.sql", "R__views.sql", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0666); err != nil {
+			t.Fatalf("Unexpected error creating file: %v", err)
+		}
+	}
+	changes := testChanges()
+	changes = append(changes, Change{
+		Schema:     "app",
+		ObjectType: "procedure",
+		ObjectName: "p",
+		DiffType:   "CREATE",
+		Statement:  "CREATE PROCEDURE `p`() BEGIN SELECT 1; END",
+		Rollback:   "DROP PROCEDURE `p`",
+		Compound:   true,
+	})
+	if err := Lookup("flyway").Write(changes, flywayTestOptions(dir)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	forward, err := os.ReadFile(filepath.Join(dir, "V1_11__add_posts_table.sql"))
+	if err != nil {
+		t.Fatalf("Unable to read versioned migration: %v", err)
+	}
+	expected := "ALTER TABLE `users` ADD COLUMN `nick` varchar(20) DEFAULT NULL;\n" +
+		"CREATE TABLE `posts` (\n  `id` int NOT NULL,\n  `body` text COMMENT 'has ]]> in it',\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\n" +
+		"DROP TABLE `old`;\n" +
+		"DELIMITER //\nCREATE PROCEDURE `p`() BEGIN SELECT 1; END//\nDELIMITER ;\n"
+	if string(forward) != expected {
+		t.Errorf("Unexpected versioned migration contents. Expected:\n%s\nActual:\n%s", expected, forward)
+	}
+
+	undo, err := os.ReadFile(filepath.Join(dir, "U1_11__add_posts_table.sql"))
+	if err != nil {
+		t.Fatalf("Unable to read undo migration: %v", err)
+	}
+	expected = "DROP PROCEDURE `p`;\n" +
+		"-- Unable to generate reverse of: DROP table `old`\n" +
+		"DROP TABLE `posts`;\n" +
+		"ALTER TABLE `users` DROP COLUMN `nick`;\n"
+	if string(undo) != expected {
+		t.Errorf("Unexpected undo migration contents. Expected:\n%s\nActual:\n%s", expected, undo)
+	}
+}
+
+func TestNextFlywayVersion(t *testing.T) {
+	opts := flywayTestOptions(filepath.Join(t.TempDir(), "missing"))
+	if version, err := nextFlywayVersion(opts); version != "1" || err != nil {
+		t.Errorf("Expected version 1 for nonexistent dir, instead found %q, %v", version, err)
+	}
+	opts.Dir = t.TempDir()
+	opts.FlywayPrefix, opts.FlywaySeparator = "M", "-"
+	for _, name := range []string{"M2.1-x.sql", "M2-y.sql", "V9__z.sql"} {
+		os.WriteFile(filepath.Join(opts.Dir, name), nil, 0666)
+	}
+	if version, err := nextFlywayVersion(opts); version != "2.2" || err != nil {
+		t.Errorf("Expected version 2.2, instead found %q, %v", version, err)
+	}
+}
+
+func TestFlywayChecksum(t *testing.T) {
+	// Line endings and byte order marks do not affect the checksum
+	for _, contents := range []string{"SELECT 1;\nSELECT 2;\n", "SELECT 1;\r\nSELECT 2;\r\n", "\xEF\xBB\xBFSELECT 1;\nSELECT 2;"} {
+		if actual := flywayChecksum([]byte(contents)); actual != -1665099012 {
+			t.Errorf("Unexpected checksum for %q: %d", contents, actual)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// This file implements migration files for golang-migrate, which consist of a
+// pair of up and down SQL files per version. See
+// https://github.com/golang-migrate/migrate/blob/master/MIGRATIONS.md for the
+// naming conventions.
+
+func init() {
+	Register(&Format{
+		Name:        "golang-migrate",
+		Description: "golang-migrate up and down SQL migrations",
+		Write:       WriteGolangMigrate,
+	})
+}
+
+var golangMigrateFile = regexp.MustCompile(`^([0-9]+)_.*\.(?:up|down)\.[a-z]+$`)
+
+// WriteGolangMigrate writes changes as golang-migrate up and down files. The
+// mysql driver of golang-migrate executes each file with a single call, which
+// only permits multiple statements if the multiStatements DSN parameter is
+// enabled. So by default (opts.GolangMigrateSplit), a separate pair of files is
+// written for each statement. DELIMITER commands are never used, since the
+// driver sends files to the server as-is.
+//
+// Unless a version is configured, versions continue from the highest existing
+// version in opts.Dir, retaining any zero-padding; or otherwise begin with a
+// UTC timestamp, matching the default of `migrate create`.
+func WriteGolangMigrate(changes []Change, opts Options) error {
+	if multipleSchemas(changes) {
+		return errors.New("golang-migrate migrations can only affect a single schema, since golang-migrate tracks versions in the connection's default database; generate migrations for each schema separately")
+	}
+	version, width, err := firstGolangMigrateVersion(opts)
+	if err != nil {
+		return err
+	}
+	var groups [][]Change
+	var titles []string
+	if opts.GolangMigrateSplit {
+		for _, c := range changes {
+			groups = append(groups, []Change{c})
+			titles = append(titles, slug(c.DiffType+" "+c.ObjectType+" "+c.ObjectName, "_"))
+		}
+	} else {
+		groups = [][]Change{changes}
+		titles = []string{slug(opts.Description, "_")}
+	}
+
+	for n, group := range groups {
+		prefix := fmt.Sprintf("%0*d_%s", width, version+uint64(n), titles[n])
+		for _, direction := range []string{"up", "down"} {
+			rollback := direction == "down"
+			name := prefix + "." + direction + ".sql"
+			f, err := opts.create(name)
+			if err != nil {
+				return err
+			}
+			if opts.Dir == "" {
+				fmt.Fprintf(f, "-- %s\n", name)
+			}
+			writeSQLScript(f, group, rollback, false)
+			if err := f.Close(); err != nil {
+				return err
+			}
+			for _, c := range group {
+				if rollback && c.Rollback == "" {
+					log.Warnf("%s does not reverse %s %s %s", name, c.DiffType, c.ObjectType, c.ObjectName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// firstGolangMigrateVersion returns the version number to use for the first
+// generated migration, along with the minimum number of digits to format
+// versions with.
+func firstGolangMigrateVersion(opts Options) (version uint64, width int, err error) {
+	if opts.Version != "" {
+		version, err = strconv.ParseUint(opts.Version, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("golang-migrate versions must be unsigned integers: %w", err)
+		}
+		return version, len(opts.Version), nil
+	}
+	var highest uint64
+	var found bool
+	if opts.Dir != "" {
+		entries, err := os.ReadDir(opts.Dir)
+		if err != nil && !os.IsNotExist(err) {
+			return 0, 0, err
+		}
+		for _, entry := range entries {
+			matches := golangMigrateFile.FindStringSubmatch(entry.Name())
+			if matches == nil {
+				continue
+			}
+			n, err := strconv.ParseUint(matches[1], 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("unable to parse version of %s: %w", entry.Name(), err)
+			}
+			if !found || n >= highest {
+				highest, found, width = n, true, 0
+				if strings.HasPrefix(matches[1], "0") {
+					width = len(matches[1])
+				}
+			}
+		}
+	}
+	if found {
+		return highest + 1, width, nil
+	}
+	version, _ = strconv.ParseUint(opts.timestampVersion(), 10, 64)
+	return version, 0, nil
+}
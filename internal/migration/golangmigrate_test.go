@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteGolangMigrate(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000009_init.up.sql", "000009_init.down.sql", "000010_more.up.sql", "notes.txt"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0666)
+	}
+	opts := Options{Dir: dir, Description: "Skeema diff", GolangMigrateSplit: true}
+	if err := Lookup("golang-migrate").Write(testChanges(), opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"000011_alter_table_users.up.sql":    "ALTER TABLE `users` ADD COLUMN `nick` varchar(20) DEFAULT NULL;\n",
+		"000011_alter_table_users.down.sql":  "ALTER TABLE `users` DROP COLUMN `nick`;\n",
+		"000012_create_table_posts.up.sql":   "CREATE TABLE `posts` (\n  `id` int NOT NULL,\n  `body` text COMMENT 'has ]]> in it',\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\n",
+		"000012_create_table_posts.down.sql": "DROP TABLE `posts`;\n",
+		"000013_drop_table_old.up.sql":       "DROP TABLE `old`;\n",
+		"000013_drop_table_old.down.sql":     "-- Unable to generate reverse of: DROP table `old`\n",
+	}
+	for name, contents := range expected {
+		if actual, err := os.ReadFile(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Unable to read %s: %v", name, err)
+		} else if string(actual) != contents {
+			t.Errorf("Unexpected contents of %s. Expected:\n%s\nActual:\n%s", name, contents, actual)
+		}
+	}
+
+	// Without splitting, a single pair is written, with a timestamp version if
+	// the dir has no existing migrations
+	dir = t.TempDir()
+	opts = Options{Dir: dir, Description: "Skeema diff", Now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	changes := testChanges()[:2]
+	if err := Lookup("golang-migrate").Write(changes, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, " ") != "20240102030405_skeema_diff.down.sql 20240102030405_skeema_diff.up.sql" {
+		t.Errorf("Unexpected files written: %v", names)
+	}
+	if down, _ := os.ReadFile(filepath.Join(dir, names[0])); string(down) != "DROP TABLE `posts`;\nALTER TABLE `users` DROP COLUMN `nick`;\n" {
+		t.Errorf("Unexpected down migration contents:\n%s", down)
+	}
+
+	// Multiple schemas are not supported; nor are non-numeric versions
+	changes[1].Schema = "blog"
+	if err := Lookup("golang-migrate").Write(changes, opts); err == nil {
+		t.Error("Expected error writing changes for multiple schemas, but err was nil")
+	}
+	opts.Version = "v2"
+	if err := Lookup("golang-migrate").Write(testChanges(), opts); err == nil {
+		t.Error("Expected error from non-numeric version, but err was nil")
+	}
+}
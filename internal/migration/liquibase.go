@@ -0,0 +1,161 @@
+package migration
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// This file implements Liquibase changelogs, in both XML and YAML syntax. Each
+// generated statement becomes a separate changeSet containing a raw sql change,
+// with a rollback block from the reverse diff. Unless a version is configured,
+// changeSet IDs are prefixed with a UTC timestamp. See
+// https://docs.liquibase.com/concepts/changelogs/home.html for the format.
+
+func init() {
+	Register(&Format{
+		Name:        "liquibase",
+		Description: "Liquibase XML changelog",
+		Write:       WriteLiquibaseXML,
+	})
+	Register(&Format{
+		Name:        "liquibase-yaml",
+		Description: "Liquibase YAML changelog",
+		Write:       WriteLiquibaseYAML,
+	})
+}
+
+// liquibaseChangeSet is the content of a single changeSet. Each element of
+// sql and rollback is executed as a separate sql change.
+type liquibaseChangeSet struct {
+	id       string
+	comment  string
+	sql      []string
+	rollback []string
+}
+
+// liquibaseChangeSets converts changes into changeSets. If changes affect
+// multiple schemas, each changeSet begins by switching the default database.
+func liquibaseChangeSets(changes []Change, opts Options) []liquibaseChangeSet {
+	useSchema := multipleSchemas(changes)
+	result := make([]liquibaseChangeSet, 0, len(changes))
+	for n, c := range changes {
+		cs := liquibaseChangeSet{
+			id:      opts.timestampVersion() + "-" + strconv.Itoa(n+1),
+			comment: c.DiffType + " " + c.ObjectType + " " + tengo.EscapeIdentifier(c.ObjectName),
+		}
+		if useSchema {
+			use := "USE " + tengo.EscapeIdentifier(c.Schema)
+			cs.sql = append(cs.sql, use)
+			if c.Rollback != "" {
+				cs.rollback = append(cs.rollback, use)
+			}
+		}
+		cs.sql = append(cs.sql, c.Statement)
+		if c.Rollback != "" {
+			cs.rollback = append(cs.rollback, c.Rollback)
+		}
+		result = append(result, cs)
+	}
+	return result
+}
+
+// WriteLiquibaseXML writes changes as a Liquibase XML changelog. Changes
+// without a rollback statement omit the rollback element, so that Liquibase
+// refuses to roll them back rather than silently doing nothing.
+func WriteLiquibaseXML(changes []Change, opts Options) error {
+	f, err := opts.create(opts.timestampVersion() + "-" + slug(opts.Description, "-") + ".xml")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	w.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog
+    xmlns="http://www.liquibase.org/xml/ns/dbchangelog"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+    xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd">
+`)
+	for _, cs := range liquibaseChangeSets(changes, opts) {
+		fmt.Fprintf(w, "  <changeSet id=%s author=%s>\n", xmlAttr(cs.id), xmlAttr(opts.Author))
+		fmt.Fprintf(w, "    <comment>%s</comment>\n", xmlText(cs.comment))
+		for _, stmt := range cs.sql {
+			fmt.Fprintf(w, "    <sql splitStatements=\"false\" stripComments=\"false\">%s</sql>\n", xmlCDATA(stmt))
+		}
+		if len(cs.rollback) > 0 {
+			w.WriteString("    <rollback>\n")
+			for _, stmt := range cs.rollback {
+				fmt.Fprintf(w, "      <sql splitStatements=\"false\" stripComments=\"false\">%s</sql>\n", xmlCDATA(stmt))
+			}
+			w.WriteString("    </rollback>\n")
+		}
+		w.WriteString("  </changeSet>\n")
+	}
+	w.WriteString("</databaseChangeLog>\n")
+	return w.Flush()
+}
+
+// WriteLiquibaseYAML writes changes as a Liquibase YAML changelog.
+func WriteLiquibaseYAML(changes []Change, opts Options) error {
+	f, err := opts.create(opts.timestampVersion() + "-" + slug(opts.Description, "-") + ".yaml")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	w.WriteString("databaseChangeLog:\n")
+	for _, cs := range liquibaseChangeSets(changes, opts) {
+		w.WriteString("  - changeSet:\n")
+		fmt.Fprintf(w, "      id: %s\n", strconv.Quote(cs.id))
+		fmt.Fprintf(w, "      author: %s\n", strconv.Quote(opts.Author))
+		fmt.Fprintf(w, "      comment: %s\n", strconv.Quote(cs.comment))
+		w.WriteString("      changes:\n")
+		writeLiquibaseYAMLSQL(w, "        ", cs.sql)
+		if len(cs.rollback) > 0 {
+			w.WriteString("      rollback:\n")
+			writeLiquibaseYAMLSQL(w, "        ", cs.rollback)
+		}
+	}
+	return w.Flush()
+}
+
+// writeLiquibaseYAMLSQL writes a list of sql changes at the supplied
+// indentation, using literal block scalars for the statements.
+func writeLiquibaseYAMLSQL(w io.Writer, indent string, stmts []string) {
+	for _, stmt := range stmts {
+		fmt.Fprintf(w, "%s- sql:\n", indent)
+		fmt.Fprintf(w, "%s    splitStatements: false\n", indent)
+		fmt.Fprintf(w, "%s    stripComments: false\n", indent)
+		fmt.Fprintf(w, "%s    sql: |-\n", indent)
+		for _, line := range strings.Split(stmt, "\n") {
+			if line == "" {
+				io.WriteString(w, "\n")
+			} else {
+				fmt.Fprintf(w, "%s      %s\n", indent, line)
+			}
+		}
+	}
+}
+
+// xmlText escapes s for use as XML character data.
+func xmlText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xmlAttr returns s as a double-quoted XML attribute value.
+func xmlAttr(s string) string {
+	return `"` + xmlText(s) + `"`
+}
+
+// xmlCDATA returns s wrapped in a CDATA section, splitting the section as
+// needed if s contains the CDATA terminator.
+func xmlCDATA(s string) string {
+	return "<![CDATA[" + strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
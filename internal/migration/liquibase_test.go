@@ -0,0 +1,147 @@
+package migration
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testChanges() []Change {
+	return []Change{
+		{
+			Instance:   "localhost:3306",
+			Schema:     "app",
+			ObjectType: "table",
+			ObjectName: "users",
+			DiffType:   "ALTER",
+			Statement:  "ALTER TABLE `users` ADD COLUMN `nick` varchar(20) DEFAULT NULL",
+			Rollback:   "ALTER TABLE `users` DROP COLUMN `nick`",
+		},
+		{
+			Instance:   "localhost:3306",
+			Schema:     "app",
+			ObjectType: "table",
+			ObjectName: "posts",
+			DiffType:   "CREATE",
+			Statement:  "CREATE TABLE `posts` (\n  `id` int NOT NULL,\n  `body` text COMMENT 'has ]]> in it',\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+			Rollback:   "DROP TABLE `posts`",
+		},
+		{
+			Instance:   "localhost:3306",
+			Schema:     "app",
+			ObjectType: "table",
+			ObjectName: "old",
+			DiffType:   "DROP",
+			Statement:  "DROP TABLE `old`",
+		},
+	}
+}
+
+func TestWriteLiquibaseXML(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{Out: &buf, Version: "20240102030405", Description: "add posts", Author: "dba & co"}
+	if err := Lookup("liquibase").Write(testChanges(), opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog
+    xmlns="http://www.liquibase.org/xml/ns/dbchangelog"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+    xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd">
+  <changeSet id="20240102030405-1" author="dba &amp; co">
+    <comment>ALTER table ` + "`users`" + `</comment>
+    <sql splitStatements="false" stripComments="false"><![CDATA[ALTER TABLE ` + "`users` ADD COLUMN `nick`" + ` varchar(20) DEFAULT NULL]]></sql>
+    <rollback>
+      <sql splitStatements="false" stripComments="false"><![CDATA[ALTER TABLE ` + "`users` DROP COLUMN `nick`" + `]]></sql>
+    </rollback>
+  </changeSet>
+  <changeSet id="20240102030405-2" author="dba &amp; co">
+    <comment>CREATE table ` + "`posts`" + `</comment>
+    <sql splitStatements="false" stripComments="false"><![CDATA[CREATE TABLE ` + "`posts`" + ` (
+  ` + "`id`" + ` int NOT NULL,
+  ` + "`body`" + ` text COMMENT 'has ]]]]><![CDATA[> in it',
+  PRIMARY KEY (` + "`id`" + `)
+) ENGINE=InnoDB]]></sql>
+    <rollback>
+      <sql splitStatements="false" stripComments="false"><![CDATA[DROP TABLE ` + "`posts`" + `]]></sql>
+    </rollback>
+  </changeSet>
+  <changeSet id="20240102030405-3" author="dba &amp; co">
+    <comment>DROP table ` + "`old`" + `</comment>
+    <sql splitStatements="false" stripComments="false"><![CDATA[DROP TABLE ` + "`old`" + `]]></sql>
+  </changeSet>
+</databaseChangeLog>
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected output. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestWriteLiquibaseYAML(t *testing.T) {
+	changes := testChanges()[:2]
+	changes[1].Schema = "blog"
+	var buf bytes.Buffer
+	opts := Options{Out: &buf, Version: "7", Description: "x", Author: "skeema"}
+	if err := Lookup("liquibase-yaml").Write(changes, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "databaseChangeLog:\n" +
+		"  - changeSet:\n" +
+		"      id: \"7-1\"\n" +
+		"      author: \"skeema\"\n" +
+		"      comment: \"ALTER table `users`\"\n" +
+		"      changes:\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              USE `app`\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              ALTER TABLE `users` ADD COLUMN `nick` varchar(20) DEFAULT NULL\n" +
+		"      rollback:\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              USE `app`\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              ALTER TABLE `users` DROP COLUMN `nick`\n" +
+		"  - changeSet:\n" +
+		"      id: \"7-2\"\n" +
+		"      author: \"skeema\"\n" +
+		"      comment: \"CREATE table `posts`\"\n" +
+		"      changes:\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              USE `blog`\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              CREATE TABLE `posts` (\n" +
+		"                `id` int NOT NULL,\n" +
+		"                `body` text COMMENT 'has ]]> in it',\n" +
+		"                PRIMARY KEY (`id`)\n" +
+		"              ) ENGINE=InnoDB\n" +
+		"      rollback:\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              USE `blog`\n" +
+		"        - sql:\n" +
+		"            splitStatements: false\n" +
+		"            stripComments: false\n" +
+		"            sql: |-\n" +
+		"              DROP TABLE `posts`\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Unexpected output. Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,173 @@
+// Package migration converts the statements generated by `skeema diff` into
+// the migration file formats of external schema change tools. This permits
+// organizations standardized on another migration pipeline to review and apply
+// Skeema-computed changes through that pipeline. Each format registers itself
+// with a writer; the migration-format option selects a format by name.
+package migration
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skeema/mybase"
+)
+
+// Change describes a single generated statement, along with the statement
+// which would reverse it.
+type Change struct {
+	Instance   string
+	Schema     string
+	ObjectType string
+	ObjectName string
+	DiffType   string // "CREATE", "ALTER", or "DROP"
+	Statement  string
+	Rollback   string // empty if a reverse statement could not be generated
+	Compound   bool   // true if Statement is a compound statement, such as a stored program
+
+	RollbackCompound bool // true if Rollback is a compound statement
+}
+
+// WriteFunc writes changes in a specific format.
+type WriteFunc func(changes []Change, opts Options) error
+
+// Format describes a registered migration format.
+type Format struct {
+	Name        string
+	Description string
+	Write       WriteFunc
+}
+
+var formats = map[string]*Format{}
+
+// Register adds a format to the registry. It panics if a format with the same
+// name has already been registered.
+func Register(format *Format) {
+	if _, already := formats[format.Name]; already {
+		panic(fmt.Errorf("migration format %s registered more than once", format.Name))
+	}
+	formats[format.Name] = format
+}
+
+// Lookup returns the format with the supplied name, or nil if no such format
+// has been registered.
+func Lookup(name string) *Format {
+	return formats[strings.ToLower(name)]
+}
+
+// Names returns the sorted names of registered formats.
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options controls the behavior of migration writers.
+type Options struct {
+	Dir         string    // directory to write files to; if empty, output goes to Out
+	Out         io.Writer // destination for output if Dir is empty
+	Version     string    // version of the migration; if empty, formats choose a default
+	Description string    // short description of the migration
+	Author      string    // author of the migration, for formats which track this
+	Now         time.Time // time of generation, used for default versions
+
+	// Options for Flyway file naming
+	FlywayPrefix     string // prefix of versioned migrations
+	FlywayUndoPrefix string // prefix of undo migrations
+	FlywaySeparator  string // separator between version and description
+
+	GolangMigrateSplit bool // write a separate golang-migrate file pair per statement
+}
+
+// AddCommandOptions adds migration-related option definitions to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("migration",
+		mybase.StringOption("migration-format", 0, "", "Output generated changes as migration files in this format: "+strings.Join(Names(), ", ")),
+		mybase.StringOption("migration-dir", 0, "", "Write migration files to this directory, instead of STDOUT"),
+		mybase.StringOption("migration-version", 0, "", "Version of the generated migration (default depends on format)"),
+		mybase.StringOption("migration-description", 0, "skeema diff", "Description of the generated migration"),
+		mybase.StringOption("migration-author", 0, "skeema", "Author of the generated migration, for formats which track this"),
+		mybase.StringOption("flyway-prefix", 0, "V", "File name prefix of Flyway versioned migrations"),
+		mybase.StringOption("flyway-undo-prefix", 0, "U", "File name prefix of Flyway undo migrations"),
+		mybase.StringOption("flyway-separator", 0, "__", "Separator between version and description in Flyway file names"),
+		mybase.BoolOption("golang-migrate-split", 0, true, "Write a separate golang-migrate file pair for each statement, for use without multiStatements"),
+	)
+}
+
+// OptionsForConfig returns Options based on the supplied configuration.
+func OptionsForConfig(cfg *mybase.Config) Options {
+	opts := Options{
+		Dir:         cfg.Get("migration-dir"),
+		Out:         os.Stdout,
+		Version:     cfg.Get("migration-version"),
+		Description: cfg.Get("migration-description"),
+		Author:      cfg.Get("migration-author"),
+		Now:         time.Now().UTC(),
+
+		FlywayPrefix:     cfg.Get("flyway-prefix"),
+		FlywayUndoPrefix: cfg.Get("flyway-undo-prefix"),
+		FlywaySeparator:  cfg.Get("flyway-separator"),
+
+		GolangMigrateSplit: cfg.GetBool("golang-migrate-split"),
+	}
+	return opts
+}
+
+// timestampVersion returns opts.Version if set, or otherwise a version based
+// on opts.Now.
+func (opts Options) timestampVersion() string {
+	if opts.Version != "" {
+		return opts.Version
+	}
+	return opts.Now.Format("20060102150405")
+}
+
+// create returns a writer for a file with the supplied name in opts.Dir, or
+// opts.Out if no directory was configured. The caller must close the returned
+// writer.
+func (opts Options) create(name string) (io.WriteCloser, error) {
+	if opts.Dir == "" {
+		return nopCloser{opts.Out}, nil
+	}
+	if err := os.MkdirAll(opts.Dir, 0777); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(opts.Dir, name))
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// slug converts a description into a form suitable for use in file names,
+// with any runs of characters other than letters and digits replaced by sep.
+func slug(description, sep string) string {
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	if len(fields) == 0 {
+		return "migration"
+	}
+	return strings.Join(fields, sep)
+}
+
+// multipleSchemas returns true if changes affect more than one schema. In this
+// situation, writers must switch the default database between changes.
+func multipleSchemas(changes []Change) bool {
+	for _, c := range changes {
+		if c.Schema != changes[0].Schema {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	cases := map[string]string{
+		"skeema diff":        "skeema_diff",
+		"Add users.email!!":  "add_users_email",
+		"  ":                 "migration",
+		"v2 -- fix PK (big)": "v2_fix_pk_big",
+	}
+	for input, expected := range cases {
+		if actual := slug(input, "_"); actual != expected {
+			t.Errorf("slug(%q): expected %q, found %q", input, expected, actual)
+		}
+	}
+}
+
+func TestOptionsCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	opts := Options{Dir: dir}
+	f, err := opts.create("file.sql")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f.Write([]byte("SELECT 1;\n"))
+	f.Close()
+	if contents, err := os.ReadFile(filepath.Join(dir, "file.sql")); err != nil || string(contents) != "SELECT 1;\n" {
+		t.Errorf("Unexpected result reading created file: %q, %v", contents, err)
+	}
+	if len(Names()) == 0 || Lookup("LIQUIBASE") == nil || Lookup("bogus") != nil {
+		t.Error("Unexpected behavior from format registry")
+	}
+}
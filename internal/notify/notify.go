@@ -0,0 +1,106 @@
+// Package notify posts summaries of diff and push operations to webhook URLs,
+// including Slack incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+)
+
+// AddCommandOptions adds notification-related option definitions to the
+// supplied mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("notification",
+		mybase.StringOption("notify-webhook", 0, "", "Comma-separated URLs to POST a JSON summary of results to"),
+		mybase.StringOption("notify-slack", 0, "", "Comma-separated Slack incoming webhook URLs to post a summary of results to"),
+		mybase.StringOption("notify-template", 0, DefaultTemplate, "Go text/template for notification message text; see manual for fields"),
+	)
+}
+
+// DefaultTemplate is the default value of the notify-template option.
+const DefaultTemplate = "skeema {{.Command}} {{.Environment}}: {{.Status}}" +
+	"{{if .Statements}}, {{.Statements}} statement(s){{end}}" +
+	"{{if .Destructive}}, {{.Destructive}} destructive{{end}}" +
+	"{{if .Failures}}, {{.Failures}} skipped{{end}}" +
+	"{{range .FailedTargets}}\n  failed: {{.}}{{end}}" +
+	"{{if .Error}}\n  error: {{.Error}}{{end}}"
+
+// Summary describes the outcome of an operation. Its exported fields are
+// available to notification templates, and are included in the JSON body
+// posted to generic webhooks.
+type Summary struct {
+	Command       string   `json:"command"`
+	Environment   string   `json:"environment"`
+	Status        string   `json:"status"` // "no differences", "differences found", "success", "partial failure", "failure"
+	Statements    int      `json:"statements"`
+	Destructive   int      `json:"destructive"`
+	Failures      int      `json:"failures"`
+	FailedTargets []string `json:"failed_targets,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	Text          string   `json:"text"` // rendered from notify-template
+}
+
+// Send renders the summary's message text and posts it to each destination
+// configured in cfg. Failures are logged as warnings, since an unreachable
+// notification endpoint should not cause the underlying operation to fail.
+func Send(cfg *mybase.Config, summary Summary) {
+	webhooks := cfg.GetSlice("notify-webhook", ',', true)
+	slackHooks := cfg.GetSlice("notify-slack", ',', true)
+	if len(webhooks)+len(slackHooks) == 0 {
+		return
+	}
+	text, err := Render(cfg.Get("notify-template"), summary)
+	if err != nil {
+		log.Warnf("Unable to send notifications: invalid notify-template: %s", err)
+		return
+	}
+	summary.Text = text
+	for _, url := range webhooks {
+		if err := post(url, summary); err != nil {
+			log.Warnf("Unable to send notification to webhook %s: %s", url, err)
+		}
+	}
+	for _, url := range slackHooks {
+		if err := post(url, map[string]string{"text": text}); err != nil {
+			log.Warnf("Unable to send notification to Slack webhook %s: %s", url, err)
+		}
+	}
+}
+
+// Render executes the supplied text/template against summary.
+func Render(tmpl string, summary Summary) (string, error) {
+	t, err := template.New("notify").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, summary); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func post(url string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP response status %s", resp.Status)
+	}
+	return nil
+}
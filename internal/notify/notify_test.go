@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/skeema/mybase"
+)
+
+func TestRender(t *testing.T) {
+	summary := Summary{
+		Command:       "push",
+		Environment:   "production",
+		Status:        "partial failure",
+		Statements:    3,
+		Destructive:   1,
+		Failures:      2,
+		FailedTargets: []string{"db1:3306 foo"},
+	}
+	text, err := Render(DefaultTemplate, summary)
+	expected := "skeema push production: partial failure, 3 statement(s), 1 destructive, 2 skipped\n  failed: db1:3306 foo"
+	if err != nil || text != expected {
+		t.Errorf("Unexpected result from Render: %q, %v", text, err)
+	}
+	if _, err := Render("{{.DoesNotExist}}", summary); err == nil {
+		t.Error("Expected error rendering template with invalid field, but err was nil")
+	}
+}
+
+func TestSend(t *testing.T) {
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = string(b)
+	}))
+	defer server.Close()
+
+	cmd := mybase.NewCommand("notifytest", "", "", nil)
+	AddCommandOptions(cmd)
+	cfg := mybase.ParseFakeCLI(t, cmd, "notifytest --notify-webhook="+server.URL+"/hook --notify-slack="+server.URL+"/slack --notify-template='{{.Command}}: {{.Status}}'")
+	Send(cfg, Summary{Command: "diff", Status: "differences found", Statements: 2})
+
+	var slackBody map[string]string
+	if err := json.Unmarshal([]byte(bodies["/slack"]), &slackBody); err != nil || slackBody["text"] != "diff: differences found" {
+		t.Errorf("Unexpected Slack body: %q", bodies["/slack"])
+	}
+	var hookBody Summary
+	if err := json.Unmarshal([]byte(bodies["/hook"]), &hookBody); err != nil || hookBody.Statements != 2 || hookBody.Text != "diff: differences found" {
+		t.Errorf("Unexpected webhook body: %q", bodies["/hook"])
+	}
+	if strings.Contains(bodies["/hook"], "failed_targets") {
+		t.Errorf("Expected empty failed_targets to be omitted, but found %q", bodies["/hook"])
+	}
+}
@@ -0,0 +1,109 @@
+// Package policy evaluates generated schema changes against team-supplied
+// policies, using the REST API of an Open Policy Agent (OPA) server. This
+// permits centralized governance of which changes may be pushed, with the
+// policies themselves written in Rego and managed outside of Skeema.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/skeema/mybase"
+)
+
+// AddCommandOptions adds policy-related option definitions to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("policy",
+		mybase.StringOption("policy-url", 0, "", "OPA data API URL of a policy decision to evaluate generated changes against"),
+		mybase.BoolOption("policy-override", 0, false, "Proceed even if the policy denies the changes, logging a warning instead"),
+	)
+}
+
+// Input is the document supplied to the policy as its input. It describes the
+// changes for a single schema on a single database instance.
+type Input struct {
+	Command     string   `json:"command"` // "push" or "diff"
+	Environment string   `json:"environment"`
+	Instance    string   `json:"instance"`
+	Schema      string   `json:"schema"`
+	Changes     []Change `json:"changes"`
+}
+
+// Change describes a single generated statement.
+type Change struct {
+	ObjectType string `json:"object_type"`
+	ObjectName string `json:"object_name"`
+	DiffType   string `json:"diff_type"` // "CREATE", "ALTER", or "DROP"
+	Statement  string `json:"statement"`
+	Unsafe     bool   `json:"unsafe"`
+
+	// AvroIncompatible describes any backward-incompatible changes to the
+	// table's Avro schema; only populated if the avro-compat option is enabled.
+	AvroIncompatible []string `json:"avro_incompatible,omitempty"`
+}
+
+// Decision is the result of evaluating a policy.
+type Decision struct {
+	Allow   bool
+	Reasons []string
+}
+
+// ErrUndefined is returned by Evaluate if the policy did not produce a
+// decision for the input, typically indicating a misconfigured policy-url.
+var ErrUndefined = errors.New("policy decision is undefined")
+
+// Evaluate posts input to the OPA data API at url, and returns the decision.
+// The policy result may be either a boolean, or an object with an "allow"
+// boolean and an optional "deny" or "reasons" array of strings explaining the
+// decision. A non-empty "deny" array always results in the changes being
+// denied.
+func Evaluate(url string, input Input) (Decision, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return Decision{}, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Decision{}, fmt.Errorf("unexpected HTTP response status %s", resp.Status)
+	}
+	var respBody struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return Decision{}, fmt.Errorf("unable to decode response: %w", err)
+	}
+	if respBody.Result == nil {
+		return Decision{}, ErrUndefined
+	}
+
+	var allow bool
+	if err := json.Unmarshal(*respBody.Result, &allow); err == nil {
+		return Decision{Allow: allow}, nil
+	}
+	var result struct {
+		Allow   *bool    `json:"allow"`
+		Deny    []string `json:"deny"`
+		Reasons []string `json:"reasons"`
+	}
+	if err := json.Unmarshal(*respBody.Result, &result); err != nil {
+		return Decision{}, fmt.Errorf("unable to decode policy result: %w", err)
+	}
+	if result.Allow == nil && result.Deny == nil {
+		return Decision{}, ErrUndefined
+	}
+	decision := Decision{
+		Allow:   len(result.Deny) == 0 && (result.Allow == nil || *result.Allow),
+		Reasons: append(result.Deny, result.Reasons...),
+	}
+	return decision, nil
+}
@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	var received Input
+	var respond string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input Input `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = body.Input
+		fmt.Fprint(w, respond)
+	}))
+	defer server.Close()
+
+	input := Input{
+		Command:     "push",
+		Environment: "production",
+		Instance:    "127.0.0.1:3306",
+		Schema:      "product",
+		Changes: []Change{
+			{ObjectType: "table", ObjectName: "posts", DiffType: "DROP", Statement: "DROP TABLE `posts`", Unsafe: true},
+		},
+	}
+	cases := []struct {
+		response string
+		expected Decision
+	}{
+		{`{"result": true}`, Decision{Allow: true}},
+		{`{"result": false}`, Decision{Allow: false}},
+		{`{"result": {"allow": true}}`, Decision{Allow: true}},
+		{`{"result": {"allow": false, "reasons": ["no drops"]}}`, Decision{Allow: false, Reasons: []string{"no drops"}}},
+		{`{"result": {"deny": ["no drops in production"]}}`, Decision{Allow: false, Reasons: []string{"no drops in production"}}},
+		{`{"result": {"allow": true, "deny": ["conflicting"]}}`, Decision{Allow: false, Reasons: []string{"conflicting"}}},
+	}
+	for _, c := range cases {
+		respond = c.response
+		decision, err := Evaluate(server.URL, input)
+		if err != nil {
+			t.Errorf("Unexpected error from Evaluate with response %s: %v", c.response, err)
+		} else if decision.Allow != c.expected.Allow || fmt.Sprint(decision.Reasons) != fmt.Sprint(c.expected.Reasons) {
+			t.Errorf("Unexpected decision with response %s: %+v", c.response, decision)
+		}
+	}
+	if received.Schema != "product" || len(received.Changes) != 1 || !received.Changes[0].Unsafe {
+		t.Errorf("Input not received as expected: %+v", received)
+	}
+
+	for _, response := range []string{`{}`, `{"result": {}}`, `not json`, `{"result": "yes"}`} {
+		respond = response
+		if _, err := Evaluate(server.URL, input); err == nil {
+			t.Errorf("Expected error from Evaluate with response %s, but err was nil", response)
+		}
+	}
+}
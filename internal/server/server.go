@@ -0,0 +1,307 @@
+// Package server implements `skeema serve`, a long-running HTTP service which
+// exposes introspection, diff, lint, and optionally push operations over a
+// JSON REST API. This allows platform teams to centralize schema operations
+// and database credentials on one host, rather than distributing them to
+// every user of the CLI.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/skeema/mybase"
+	"github.com/skeema/skeema/internal/fs"
+)
+
+// AddCommandOptions adds server-related option definitions to the supplied
+// mybase.Command.
+func AddCommandOptions(cmd *mybase.Command) {
+	cmd.AddOptions("server",
+		mybase.StringOption("listen", 0, "127.0.0.1:8080", "Address and port to listen on"),
+		mybase.StringOption("auth-token", 0, "", "Bearer token required in the Authorization header of each request"),
+		mybase.BoolOption("allow-push", 0, false, "Permit the push endpoint, which modifies databases"),
+		mybase.StringOption("request-timeout", 0, "30m", "Maximum duration of each diff, lint, or push request"),
+	)
+}
+
+// Server is an http.Handler providing the API endpoints. Operations run
+// against the directory tree rooted at Root, using the option files found
+// there; callers select a subdirectory and environment in each request.
+type Server struct {
+	Root       string         // base directory of the schema repo
+	Config     *mybase.Config // config of the serve command, used for introspection
+	Token      string         // required bearer token
+	AllowPush  bool           // whether the push endpoint is enabled
+	Executable string         // path to the skeema binary, for diff, lint, and push
+	Timeout    time.Duration  // maximum duration of each subprocess
+
+	pushMutex sync.Mutex
+	muxOnce   sync.Once
+	mux       *http.ServeMux
+}
+
+// Request is the JSON body accepted by each endpoint.
+type Request struct {
+	Environment string `json:"environment"`
+	Path        string `json:"path"`         // relative to the server's root dir
+	AllowUnsafe bool   `json:"allow_unsafe"` // only used by diff and push
+
+	dirPath string // absolute path of Path with symlinks resolved, set by authenticated
+}
+
+// CommandResponse is the JSON body returned by the diff, lint, and push
+// endpoints.
+type CommandResponse struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+	Log      string `json:"log"`
+}
+
+// SchemaResponse is an element of the JSON array returned by the introspect
+// endpoint.
+type SchemaResponse struct {
+	Instance string           `json:"instance"`
+	Schema   string           `json:"schema"`
+	Objects  []ObjectResponse `json:"objects"`
+}
+
+// ObjectResponse describes a single object in a SchemaResponse.
+type ObjectResponse struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Create string `json:"create"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP satisfies http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.muxOnce.Do(func() {
+		s.mux = http.NewServeMux()
+		s.mux.HandleFunc("/healthz", s.handleHealth)
+		s.mux.HandleFunc("/v1/introspect", s.authenticated(s.handleIntrospect))
+		s.mux.HandleFunc("/v1/diff", s.authenticated(s.commandHandler("diff")))
+		s.mux.HandleFunc("/v1/lint", s.authenticated(s.commandHandler("lint")))
+		s.mux.HandleFunc("/v1/push", s.authenticated(s.commandHandler("push")))
+	})
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// authenticated wraps handler to require a valid bearer token and a POST
+// request with a well-formed JSON body.
+func (s *Server) authenticated(handler func(http.ResponseWriter, *http.Request, Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if s.Token == "" || token == authHeader || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, errorResponse{"missing or invalid bearer token"})
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"only POST is supported"})
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"invalid request body: " + err.Error()})
+			return
+		}
+		if req.Environment == "" {
+			req.Environment = "production"
+		}
+		if strings.ContainsAny(req.Environment, "[]\n\r") || strings.HasPrefix(req.Environment, "-") {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"invalid environment name"})
+			return
+		}
+		var err error
+		if req.dirPath, err = s.resolvePath(req.Path); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+		log.Infof("%s %s from %s: environment=%s path=%q", r.Method, r.URL.Path, r.RemoteAddr, req.Environment, req.Path)
+		handler(w, r, req)
+	}
+}
+
+// resolvePath returns the absolute path for a request's relative path, with
+// any symlinks resolved. Paths which would escape the server's root dir, either
+// lexically or via a symlink, are rejected.
+func (s *Server) resolvePath(relPath string) (string, error) {
+	if filepath.IsAbs(relPath) {
+		return "", errors.New("path must be relative")
+	}
+	cleaned := filepath.Clean(relPath)
+	if isOutsideDir(cleaned) {
+		return "", errors.New("path must not refer outside of the server's directory")
+	}
+	root, err := filepath.EvalSymlinks(s.Root)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve server's directory: %w", err)
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return "", fmt.Errorf("unable to resolve server's directory: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, cleaned))
+	if err != nil {
+		return "", errors.New("path does not exist or cannot be resolved")
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || isOutsideDir(rel) {
+		return "", errors.New("path must not refer outside of the server's directory")
+	}
+	return resolved, nil
+}
+
+// isOutsideDir returns true if the cleaned relative path refers to its base
+// dir's parent or anything beneath it.
+func isOutsideDir(cleaned string) bool {
+	return cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator))
+}
+
+// commandHandler returns a handler which runs the named skeema subcommand in
+// a subprocess, capturing its output. Subprocesses keep each request's
+// configuration and session state isolated from concurrent requests.
+func (s *Server) commandHandler(command string) func(http.ResponseWriter, *http.Request, Request) {
+	return func(w http.ResponseWriter, r *http.Request, req Request) {
+		if command == "push" {
+			if !s.AllowPush {
+				writeJSON(w, http.StatusForbidden, errorResponse{"push is not enabled on this server"})
+				return
+			}
+			// Only one push may run at a time
+			s.pushMutex.Lock()
+			defer s.pushMutex.Unlock()
+		}
+		args := []string{command, req.Environment}
+		if req.AllowUnsafe && command != "lint" {
+			args = append(args, "--allow-unsafe")
+		}
+		ctx := r.Context()
+		if s.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+		}
+		cmd := exec.CommandContext(ctx, s.Executable, args...)
+		cmd.Dir = req.dirPath
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		resp := CommandResponse{Command: command}
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			resp.ExitCode = exitErr.ExitCode()
+		} else if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{"unable to run command: " + err.Error()})
+			return
+		}
+		resp.Output, resp.Log = stdout.String(), stderr.String()
+		status := http.StatusOK
+		if resp.ExitCode > 1 {
+			status = http.StatusInternalServerError
+		}
+		writeJSON(w, status, resp)
+	}
+}
+
+// handleIntrospect returns the CREATE statements of all objects in the schemas
+// mapped to the requested dir and its subdirs.
+func (s *Server) handleIntrospect(w http.ResponseWriter, r *http.Request, req Request) {
+	cfg := s.Config.Clone()
+	cfg.SetRuntimeOverride("environment", req.Environment)
+	dir, err := fs.ParseDir(req.dirPath, cfg)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+	result := []SchemaResponse{}
+	if err := introspectWalker(dir, &result, 5); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func introspectWalker(dir *fs.Dir, result *[]SchemaResponse, maxDepth int) error {
+	if dir.ParseError != nil {
+		return dir.ParseError
+	}
+	if dir.HasSchema() {
+		inst, err := dir.FirstInstance()
+		if err != nil {
+			return err
+		} else if inst == nil {
+			return errors.New("no host defined for " + dir.RelPath())
+		}
+		names, err := dir.SchemaNames(inst)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			schema, err := inst.Schema(name)
+			if err == sql.ErrNoRows {
+				continue
+			} else if err != nil {
+				return err
+			}
+			schema.StripMatches(dir.IgnorePatterns)
+			sr := SchemaResponse{Instance: inst.String(), Schema: name}
+			for key, obj := range schema.Objects() {
+				sr.Objects = append(sr.Objects, ObjectResponse{
+					Type:   string(key.Type),
+					Name:   key.Name,
+					Create: obj.Def(),
+				})
+			}
+			sortObjects(sr.Objects)
+			*result = append(*result, sr)
+		}
+	}
+	if maxDepth <= 0 {
+		return nil
+	}
+	subdirs, err := dir.Subdirs()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subdirs {
+		if err := introspectWalker(sub, result, maxDepth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortObjects(objects []ObjectResponse) {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Type != objects[j].Type {
+			return objects[i].Type < objects[j].Type
+		}
+		return objects[i].Name < objects[j].Name
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
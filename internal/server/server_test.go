@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func doRequest(t *testing.T, s *Server, path, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	var result map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Unable to decode response body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, result
+}
+
+func TestServerAuth(t *testing.T) {
+	s := &Server{Root: t.TempDir(), Token: "sekrit", Executable: "echo"}
+	if code, _ := doRequest(t, s, "/v1/diff", "", "{}"); code != http.StatusUnauthorized {
+		t.Errorf("Expected missing token to return %d, instead found %d", http.StatusUnauthorized, code)
+	}
+	if code, _ := doRequest(t, s, "/v1/diff", "wrong", "{}"); code != http.StatusUnauthorized {
+		t.Errorf("Expected wrong token to return %d, instead found %d", http.StatusUnauthorized, code)
+	}
+	if code, _ := doRequest(t, s, "/v1/diff", "sekrit", "{bad json"); code != http.StatusBadRequest {
+		t.Errorf("Expected malformed body to return %d, instead found %d", http.StatusBadRequest, code)
+	}
+	for _, body := range []string{`{"path": "../elsewhere"}`, `{"path": "/etc"}`, `{"path": "nonexistent"}`, `{"environment": "--help"}`, `{"environment": "[prod]"}`} {
+		if code, _ := doRequest(t, s, "/v1/lint", "sekrit", body); code != http.StatusBadRequest {
+			t.Errorf("Expected body %s to return %d, instead found %d", body, http.StatusBadRequest, code)
+		}
+	}
+
+	// Token without the Bearer scheme is rejected
+	req := httptest.NewRequest(http.MethodPost, "/v1/lint", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "sekrit")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected token without Bearer prefix to return %d, instead found %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	// Health check does not require auth
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to return %d, instead found %d", http.StatusOK, rec.Code)
+	}
+
+	// Server without a configured token rejects everything
+	s = &Server{Root: t.TempDir(), Executable: "echo"}
+	if code, _ := doRequest(t, s, "/v1/diff", "", "{}"); code != http.StatusUnauthorized {
+		t.Errorf("Expected server without token to return %d, instead found %d", http.StatusUnauthorized, code)
+	}
+}
+
+func TestServerCommands(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo not available in PATH")
+	}
+	s := &Server{Root: t.TempDir(), Token: "sekrit", Executable: "echo"}
+	code, result := doRequest(t, s, "/v1/diff", "sekrit", `{"environment": "staging", "allow_unsafe": true}`)
+	if code != http.StatusOK || result["output"] != "diff staging --allow-unsafe\n" || result["exit_code"] != float64(0) {
+		t.Errorf("Unexpected result from diff: %d %v", code, result)
+	}
+	code, result = doRequest(t, s, "/v1/lint", "sekrit", `{"allow_unsafe": true}`)
+	if code != http.StatusOK || result["output"] != "lint production\n" {
+		t.Errorf("Unexpected result from lint: %d %v", code, result)
+	}
+	if code, _ = doRequest(t, s, "/v1/push", "sekrit", `{}`); code != http.StatusForbidden {
+		t.Errorf("Expected push to be forbidden by default, instead found %d", code)
+	}
+	s.AllowPush = true
+	code, result = doRequest(t, s, "/v1/push", "sekrit", `{}`)
+	if code != http.StatusOK || result["output"] != "push production\n" {
+		t.Errorf("Unexpected result from push: %d %v", code, result)
+	}
+
+	// Nonzero exit codes are passed through
+	if _, err := exec.LookPath("false"); err == nil {
+		s.Executable = "false"
+		code, result = doRequest(t, s, "/v1/diff", "sekrit", `{}`)
+		if code != http.StatusOK || result["exit_code"] != float64(1) {
+			t.Errorf("Unexpected result from diff with failing command: %d %v", code, result)
+		}
+	}
+}
+
+func TestServerResolvePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "product"), 0777); err != nil {
+		t.Fatalf("Unable to create subdir: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("Unable to create symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "product"), filepath.Join(root, "alias")); err != nil {
+		t.Skipf("Unable to create symlink: %v", err)
+	}
+	s := &Server{Root: root}
+	realRoot, _ := filepath.EvalSymlinks(root)
+	for _, relPath := range []string{"", "product", "alias", "product/../alias"} {
+		if _, err := s.resolvePath(relPath); err != nil {
+			t.Errorf("Unexpected error from resolvePath(%q): %v", relPath, err)
+		}
+	}
+	if resolved, _ := s.resolvePath("alias"); resolved != filepath.Join(realRoot, "product") {
+		t.Errorf("Expected symlink to be resolved, instead found %q", resolved)
+	}
+	for _, relPath := range []string{"escape", "escape/product", "nonexistent", "../" + filepath.Base(outside)} {
+		if _, err := s.resolvePath(relPath); err == nil {
+			t.Errorf("Expected resolvePath(%q) to return an error, but it did not", relPath)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package snapshot stores point-in-time schema state in a compact on-disk
+// format. Object definitions are content-addressed by hash, so identical
+// definitions (for example across many sharded schemas) are only stored once,
+// and two snapshots can be compared just by examining their hashes.
+package snapshot
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// formatVersion is incremented upon any backwards-incompatible change to the
+// serialized representation.
+const formatVersion = 1
+
+// Snapshot represents the state of one or more schemas.
+type Snapshot struct {
+	Version int                `json:"version"`
+	Time    time.Time          `json:"time"`
+	Schemas map[string]*Schema `json:"schemas"`
+	Blobs   map[string]string  `json:"blobs"` // hash -> object definition
+}
+
+// Schema represents the state of a single schema within a Snapshot.
+type Schema struct {
+	CharSet   string            `json:"charset"`
+	Collation string            `json:"collation"`
+	Objects   map[string]string `json:"objects"`          // ObjectKey.String() -> hash
+	Absent    []string          `json:"absent,omitempty"` // ObjectKey.String() of objects recorded as nonexistent; see AddObjects
+	keys      map[string]tengo.ObjectKey
+}
+
+// New returns an empty Snapshot, taken at the current time.
+func New() *Snapshot {
+	return &Snapshot{
+		Version: formatVersion,
+		Time:    time.Now().UTC(),
+		Schemas: make(map[string]*Schema),
+		Blobs:   make(map[string]string),
+	}
+}
+
+// Hash returns the content address of the supplied object definition.
+func Hash(def string) string {
+	sum := sha256.Sum256([]byte(def))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddSchema adds s to the snapshot, replacing any existing schema of the same
+// name.
+func (snap *Snapshot) AddSchema(s *tengo.Schema) {
+	objects := s.Objects()
+	ss := &Schema{
+		CharSet:   s.CharSet,
+		Collation: s.Collation,
+		Objects:   make(map[string]string, len(objects)),
+		keys:      make(map[string]tengo.ObjectKey, len(objects)),
+	}
+	for key, obj := range objects {
+		def := obj.Def()
+		hash := Hash(def)
+		snap.Blobs[hash] = def
+		ss.Objects[key.String()] = hash
+		ss.keys[key.String()] = key
+	}
+	snap.Schemas[s.Name] = ss
+}
+
+// AddObjects adds the state of only the objects in s with the supplied keys,
+// replacing any existing schema of the same name. Keys of objects which do not
+// exist in s are recorded as absent, distinguishing them from objects which
+// were not included at all. Database-level keys are ignored.
+func (snap *Snapshot) AddObjects(s *tengo.Schema, keys []tengo.ObjectKey) {
+	objects := s.Objects()
+	ss := &Schema{
+		CharSet:   s.CharSet,
+		Collation: s.Collation,
+		Objects:   make(map[string]string, len(keys)),
+		keys:      make(map[string]tengo.ObjectKey, len(keys)),
+	}
+	for _, key := range keys {
+		keyStr := key.String()
+		if _, already := ss.keys[keyStr]; already || key.Type == tengo.ObjectTypeDatabase {
+			continue
+		}
+		ss.keys[keyStr] = key
+		if obj := objects[key]; obj != nil {
+			def := obj.Def()
+			hash := Hash(def)
+			snap.Blobs[hash] = def
+			ss.Objects[keyStr] = hash
+		} else {
+			ss.Absent = append(ss.Absent, keyStr)
+		}
+	}
+	snap.Schemas[s.Name] = ss
+}
+
+// Keys returns the keys of all objects recorded in the named schema, including
+// ones recorded as absent, sorted by type and name.
+func (snap *Snapshot) Keys(schemaName string) []tengo.ObjectKey {
+	ss := snap.Schemas[schemaName]
+	if ss == nil {
+		return nil
+	}
+	keys := make([]tengo.ObjectKey, 0, len(ss.keys))
+	for _, key := range ss.keys {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// Definition returns the CREATE statement for the object with the supplied key
+// in the named schema, or a blank string if no such object is in the snapshot.
+func (snap *Snapshot) Definition(schemaName string, key tengo.ObjectKey) string {
+	if ss := snap.Schemas[schemaName]; ss != nil {
+		if hash, ok := ss.Objects[key.String()]; ok {
+			return snap.Blobs[hash]
+		}
+	}
+	return ""
+}
+
+// Changes describes the differences in a single schema between two snapshots.
+type Changes struct {
+	Added    []tengo.ObjectKey
+	Removed  []tengo.ObjectKey
+	Modified []tengo.ObjectKey
+}
+
+// Empty returns true if no changes are present.
+func (c Changes) Empty() bool {
+	return len(c.Added)+len(c.Removed)+len(c.Modified) == 0
+}
+
+// Compare returns the changes needed to get from the named schema in snap to
+// the same-named schema in other. Only object hashes are compared, so this is
+// cheap even for very large schemas. Each list of keys is sorted by type and
+// then name.
+func (snap *Snapshot) Compare(other *Snapshot, schemaName string) (c Changes) {
+	from, to := snap.Schemas[schemaName], other.Schemas[schemaName]
+	if from == nil {
+		from = &Schema{}
+	}
+	if to == nil {
+		to = &Schema{}
+	}
+	for keyStr, hash := range to.Objects {
+		if fromHash, ok := from.Objects[keyStr]; !ok {
+			c.Added = append(c.Added, to.keys[keyStr])
+		} else if fromHash != hash {
+			c.Modified = append(c.Modified, to.keys[keyStr])
+		}
+	}
+	for keyStr := range from.Objects {
+		if _, ok := to.Objects[keyStr]; !ok {
+			c.Removed = append(c.Removed, from.keys[keyStr])
+		}
+	}
+	for _, keys := range [][]tengo.ObjectKey{c.Added, c.Removed, c.Modified} {
+		sortKeys(keys)
+	}
+	return c
+}
+
+func sortKeys(keys []tengo.ObjectKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+		return keys[i].Name < keys[j].Name
+	})
+}
+
+// Write serializes the snapshot to w as gzip-compressed JSON.
+func (snap *Snapshot) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(snap); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteFile serializes the snapshot to a new file at path, replacing any
+// existing file there.
+func (snap *Snapshot) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := snap.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read deserializes a snapshot previously written by Snapshot.Write.
+func Read(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	snap := New()
+	if err := json.NewDecoder(gz).Decode(snap); err != nil {
+		return nil, err
+	}
+	if snap.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d", snap.Version)
+	}
+	for _, ss := range snap.Schemas {
+		ss.keys = make(map[string]tengo.ObjectKey, len(ss.Objects))
+		for keyStr, hash := range ss.Objects {
+			key, err := parseObjectKey(keyStr)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := snap.Blobs[hash]; !ok {
+				return nil, fmt.Errorf("snapshot is missing definition of %s", keyStr)
+			}
+			ss.keys[keyStr] = key
+		}
+		for _, keyStr := range ss.Absent {
+			key, err := parseObjectKey(keyStr)
+			if err != nil {
+				return nil, err
+			}
+			ss.keys[keyStr] = key
+		}
+	}
+	return snap, nil
+}
+
+// ReadFile deserializes the snapshot file at path.
+func ReadFile(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// parseObjectKey is the inverse of tengo.ObjectKey.String.
+func parseObjectKey(keyStr string) (key tengo.ObjectKey, err error) {
+	for _, ot := range []tengo.ObjectType{tengo.ObjectTypeTable, tengo.ObjectTypeProc, tengo.ObjectTypeFunc, tengo.ObjectTypeView, tengo.ObjectTypeTrigger} {
+		name := strings.TrimPrefix(keyStr, string(ot)+" ")
+		if name != keyStr && len(name) > 2 && name[0] == '`' && name[len(name)-1] == '`' {
+			key.Type = ot
+			key.Name = strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+			return key, nil
+		}
+	}
+	return key, fmt.Errorf("unable to parse object key %q in snapshot", keyStr)
+}
@@ -0,0 +1,122 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+func testSchema(name string, tableDefs ...string) *tengo.Schema {
+	s := &tengo.Schema{
+		Name:      name,
+		CharSet:   "utf8mb4",
+		Collation: "utf8mb4_general_ci",
+	}
+	for n, def := range tableDefs {
+		s.Tables = append(s.Tables, &tengo.Table{
+			Name:            fmt.Sprintf("t`%d", n),
+			CreateStatement: def,
+		})
+	}
+	return s
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	snap := New()
+	snap.AddSchema(testSchema("shard1", "CREATE TABLE a", "CREATE TABLE b"))
+	snap.AddSchema(testSchema("shard2", "CREATE TABLE a", "CREATE TABLE b"))
+	if len(snap.Blobs) != 2 {
+		t.Errorf("Expected identical definitions to be stored once, but found %d blobs", len(snap.Blobs))
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.gz")
+	if err := snap.WriteFile(path); err != nil {
+		t.Fatalf("Unexpected error from WriteFile: %s", err)
+	}
+	snap2, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error from ReadFile: %s", err)
+	}
+	key := tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "t`1"}
+	if def := snap2.Definition("shard2", key); def != "CREATE TABLE b" {
+		t.Errorf("Unexpected definition for %s after round-trip: %q", key, def)
+	}
+	if changes := snap.Compare(snap2, "shard1"); !changes.Empty() {
+		t.Errorf("Expected no changes after round-trip, instead found %+v", changes)
+	}
+	if snap2.Schemas["shard1"].Collation != "utf8mb4_general_ci" {
+		t.Errorf("Schema collation not retained after round-trip")
+	}
+
+	if _, err := Read(bytes.NewBufferString("not gzip")); err == nil {
+		t.Error("Expected error reading invalid snapshot, but err was nil")
+	}
+}
+
+func TestSnapshotCompare(t *testing.T) {
+	before, after := New(), New()
+	before.AddSchema(testSchema("foo", "CREATE TABLE a", "CREATE TABLE b", "CREATE TABLE c"))
+	after.AddSchema(testSchema("foo", "CREATE TABLE a", "CREATE TABLE b2"))
+	changes := before.Compare(after, "foo")
+	if len(changes.Added) != 0 || len(changes.Removed) != 1 || len(changes.Modified) != 1 {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+	if changes.Removed[0].Name != "t`2" || changes.Modified[0].Name != "t`1" {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+
+	// Schema missing from one side: everything is added
+	changes = New().Compare(after, "foo")
+	if len(changes.Added) != 2 || changes.Added[0].Name != "t`0" || changes.Added[1].Name != "t`1" {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+}
+
+func TestSnapshotAddObjects(t *testing.T) {
+	s := testSchema("foo", "CREATE TABLE a", "CREATE TABLE b")
+	keys := []tengo.ObjectKey{
+		{Type: tengo.ObjectTypeDatabase, Name: "foo"},
+		s.Tables[1].ObjectKey(),
+		s.Tables[1].ObjectKey(), // duplicate, e.g. from a split ALTER
+		{Type: tengo.ObjectTypeProc, Name: "newproc"},
+	}
+	snap := New()
+	snap.AddObjects(s, keys)
+
+	var buf bytes.Buffer
+	if err := snap.Write(&buf); err != nil {
+		t.Fatalf("Unexpected error from Write: %s", err)
+	}
+	snap2, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error from Read: %s", err)
+	}
+	if !snap2.Time.Equal(snap.Time) || snap2.Time.IsZero() {
+		t.Errorf("Snapshot time not retained after round-trip: %s vs %s", snap2.Time, snap.Time)
+	}
+	actual := snap2.Keys("foo")
+	expected := []tengo.ObjectKey{keys[3], s.Tables[1].ObjectKey()} // sorted by type, then name
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected keys %v, instead found %v", expected, actual)
+	}
+	for n := range expected {
+		if actual[n] != expected[n] {
+			t.Errorf("Expected keys %v, instead found %v", expected, actual)
+		}
+	}
+	if def := snap2.Definition("foo", keys[1]); def != "CREATE TABLE b" {
+		t.Errorf("Unexpected definition for %s: %q", keys[1], def)
+	}
+	if def := snap2.Definition("foo", keys[3]); def != "" {
+		t.Errorf("Expected absent object to have no definition, instead found %q", def)
+	}
+	if def := snap2.Definition("foo", s.Tables[0].ObjectKey()); def != "" {
+		t.Errorf("Expected excluded object to have no definition, instead found %q", def)
+	}
+	if keys := snap2.Keys("bar"); keys != nil {
+		t.Errorf("Expected nil keys for nonexistent schema, instead found %v", keys)
+	}
+}
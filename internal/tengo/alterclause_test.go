@@ -55,6 +55,8 @@ func TestModifyColumnUnsafe(t *testing.T) {
 		{"inet4", "char(10)"},
 		{"char(31)", "uuid"},
 		{"uuid", "binary(15)"},
+		{"vector(3)", "vector(4)"},
+		{"vector(4)", "varbinary(16)"},
 	}
 	for _, types := range expectUnsafe {
 		assertUnsafe(types[0], types[1], true)
@@ -198,6 +200,70 @@ func (s TengoIntegrationSuite) TestAlterPageCompression(t *testing.T) {
 	}
 }
 
+// TestAlterCheckConstraintsAutoNames confirms that checks differing only in
+// server-generated names are flagged as such, and suppressed when using
+// StatementModifiers.LaxCheckNaming.
+func TestAlterCheckConstraintsAutoNames(t *testing.T) {
+	flavor := FlavorMySQL80.Dot(23)
+	from, to := aTableForFlavor(flavor, 1), aTableForFlavor(flavor, 1)
+	from.Checks = []*Check{
+		{Name: "CONSTRAINT_1", Clause: "alive != 0", Enforced: true},
+		{Name: "CONSTRAINT_2", Clause: "ssn <> '000000000'", Enforced: true},
+		{Name: "named", Clause: "ssn <> '111111111'", Enforced: true},
+	}
+	from.CreateStatement = from.GeneratedCreateStatement(flavor)
+	to.Checks = []*Check{
+		{Name: to.Name + "_chk_1", Clause: "alive != 0", Enforced: true},
+		{Name: to.Name + "_chk_2", Clause: "ssn <> '999999999'", Enforced: true},
+		{Name: "named", Clause: "ssn <> '111111111'", Enforced: true},
+	}
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+
+	td := NewAlterTable(&from, &to)
+	var renameOnly int
+	for _, clause := range td.alterClauses {
+		if dcc, ok := clause.(DropCheck); ok && dcc.renameOnly {
+			renameOnly++
+			if dcc.Check != from.Checks[0] {
+				t.Errorf("Unexpected check flagged as rename-only: %+v", dcc.Check)
+			}
+		} else if acc, ok := clause.(AddCheck); ok && acc.renameOnly {
+			renameOnly++
+			if acc.Check != to.Checks[0] {
+				t.Errorf("Unexpected check flagged as rename-only: %+v", acc.Check)
+			}
+		}
+	}
+	if renameOnly != 2 {
+		t.Errorf("Expected 2 rename-only clauses, instead found %d", renameOnly)
+	}
+
+	strict, _ := td.Statement(StatementModifiers{Flavor: flavor})
+	lax, _ := td.Statement(StatementModifiers{Flavor: flavor, LaxCheckNaming: true})
+	if !strings.Contains(strict, "CONSTRAINT_1") || strings.Contains(lax, "CONSTRAINT_1") || strings.Contains(lax, "_chk_1") {
+		t.Errorf("Unexpected handling of rename-only clauses.\nStrict: %s\nLax: %s", strict, lax)
+	}
+	if !strings.Contains(lax, "CONSTRAINT_2") || !strings.Contains(lax, "_chk_2") {
+		t.Errorf("Expected check with changed clause to be modified even with LaxCheckNaming, instead found %s", lax)
+	}
+
+	// With only rename differences, the lax statement should be blank
+	to.Checks[1].Clause = from.Checks[1].Clause
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	td = NewAlterTable(&from, &to)
+	if lax, err := td.Statement(StatementModifiers{Flavor: flavor, LaxCheckNaming: true}); lax != "" || err != nil {
+		t.Errorf("Expected blank statement with LaxCheckNaming, instead found %q, %v", lax, err)
+	}
+
+	// Explicit names are never treated as auto-generated
+	to.Checks[0].Name = "explicit"
+	to.CreateStatement = to.GeneratedCreateStatement(flavor)
+	td = NewAlterTable(&from, &to)
+	if lax, _ := td.Statement(StatementModifiers{Flavor: flavor, LaxCheckNaming: true}); !strings.Contains(lax, "CONSTRAINT_1") {
+		t.Errorf("Expected explicitly-named check to be renamed even with LaxCheckNaming, instead found %q", lax)
+	}
+}
+
 // TestAlterCheckConstraints provides unit test coverage relating to diffs of
 // check constraints.
 func TestAlterCheckConstraints(t *testing.T) {
@@ -0,0 +1,620 @@
+package tengo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// This file builds a shallow syntax tree for CREATE TABLE, CREATE PROCEDURE,
+// and CREATE FUNCTION statements, on top of the tokens from Lexer. As with the
+// rest of this package's parsing, the goal is not full SQL grammar coverage:
+// each node identifies a definition's structure and position, while
+// exposing the raw text of sub-clauses (column attributes, table options,
+// routine bodies, etc) rather than parsing them further.
+
+// Pos represents a position within a statement's source file. Line and Col
+// are 1-based, with Col measured in runes. Offset is the 0-based byte offset
+// from the start of the statement's Text.
+type Pos struct {
+	Line   int
+	Col    int
+	Offset int
+}
+
+// String returns the position in "line:col" format.
+func (pos Pos) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Col)
+}
+
+// Node is implemented by all syntax tree node types.
+type Node interface {
+	Position() Pos
+}
+
+// CreateTableNode represents a parsed CREATE TABLE statement.
+type CreateTableNode struct {
+	Pos          Pos
+	Schema       string // schema name qualifier, if any
+	Name         string
+	Columns      []*ColumnDefNode
+	Indexes      []*IndexDefNode // includes the primary key, if any
+	ForeignKeys  []*ForeignKeyDefNode
+	Checks       []*CheckDefNode
+	Options      string // raw text of table options following the definitions list
+	Partitioning string // raw text of the PARTITION BY clause, if any
+}
+
+// ColumnDefNode represents a column definition in a CREATE TABLE.
+type ColumnDefNode struct {
+	Pos        Pos
+	Name       string
+	Type       string // data type, including any length/values and signedness
+	Attributes string // raw text following the data type, e.g. NOT NULL DEFAULT ...
+	Text       string // raw text of the entire definition
+}
+
+// IndexDefNode represents an index definition in a CREATE TABLE. Kind is one
+// of "PRIMARY", "UNIQUE", "FULLTEXT", "SPATIAL", or "INDEX".
+type IndexDefNode struct {
+	Pos   Pos
+	Name  string
+	Kind  string
+	Parts []string // raw text of each key part, with backticks removed from plain column names
+	Text  string
+}
+
+// ForeignKeyDefNode represents a foreign key definition in a CREATE TABLE.
+type ForeignKeyDefNode struct {
+	Pos               Pos
+	Name              string
+	Columns           []string
+	ReferencedSchema  string
+	ReferencedTable   string
+	ReferencedColumns []string
+	Text              string
+}
+
+// CheckDefNode represents a check constraint definition in a CREATE TABLE.
+type CheckDefNode struct {
+	Pos    Pos
+	Name   string
+	Clause string // expression inside the parentheses
+	Text   string
+}
+
+// CreateRoutineNode represents a parsed CREATE PROCEDURE or CREATE FUNCTION.
+type CreateRoutineNode struct {
+	Pos             Pos
+	Type            ObjectType
+	Definer         string // raw text of the DEFINER value, if any
+	Schema          string
+	Name            string
+	Params          []*ParamNode
+	Returns         string // data type returned, only for functions
+	Characteristics string // raw text of characteristics, e.g. DETERMINISTIC
+	Body            string
+	BodyPos         Pos
+}
+
+// ParamNode represents a parameter of a stored procedure or function. Mode is
+// only populated for procedures, and is one of "IN", "OUT", or "INOUT".
+type ParamNode struct {
+	Pos  Pos
+	Mode string
+	Name string
+	Type string
+}
+
+// Position satisfies the Node interface.
+func (n *CreateTableNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *ColumnDefNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *IndexDefNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *ForeignKeyDefNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *CheckDefNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *CreateRoutineNode) Position() Pos { return n.Pos }
+
+// Position satisfies the Node interface.
+func (n *ParamNode) Position() Pos { return n.Pos }
+
+// ErrNoAST is returned by Statement.AST for statements which are not a
+// supported type of CREATE.
+var ErrNoAST = errors.New("syntax tree only available for CREATE TABLE, CREATE PROCEDURE, and CREATE FUNCTION statements")
+
+// AST returns a syntax tree for the statement, which will be either a
+// *CreateTableNode or a *CreateRoutineNode. An error is returned if the
+// statement is not a supported type of CREATE, or its definition could not be
+// parsed.
+func (stmt *Statement) AST() (Node, error) {
+	if stmt == nil || stmt.Type != StatementTypeCreate {
+		return nil, ErrNoAST
+	}
+	body, _ := stmt.SplitTextBody()
+	ap, err := newASTParser(stmt, body)
+	if err != nil {
+		return nil, err
+	}
+	switch stmt.ObjectType {
+	case ObjectTypeTable:
+		return ap.parseCreateTable()
+	case ObjectTypeProc, ObjectTypeFunc:
+		return ap.parseCreateRoutine()
+	}
+	return nil, ErrNoAST
+}
+
+type astParser struct {
+	stmt   *Statement
+	text   string
+	tokens []Token // excludes filler
+	pos    int     // index of next unconsumed token
+}
+
+func newASTParser(stmt *Statement, body string) (*astParser, error) {
+	ap := &astParser{stmt: stmt, text: body}
+	delim := stmt.Delimiter
+	if delim == "" || delim == "\000" {
+		delim = ";"
+	}
+	lex := NewLexer(strings.NewReader(body), delim, 8192)
+	var offset int
+	for {
+		data, typ, err := lex.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if typ != TokenFiller {
+			ap.tokens = append(ap.tokens, Token{val: string(data), typ: typ, offset: uint32(offset)})
+		}
+		offset += len(data)
+	}
+	return ap, nil
+}
+
+// position converts a byte offset in the statement text to a Pos.
+func (ap *astParser) position(offset int) Pos {
+	pos := Pos{Line: ap.stmt.LineNo, Col: ap.stmt.CharNo, Offset: offset}
+	if pos.Line == 0 {
+		pos.Line, pos.Col = 1, 1
+	}
+	s := ap.text[:offset]
+	if lastNewline := strings.LastIndexByte(s, '\n'); lastNewline >= 0 {
+		pos.Line += strings.Count(s, "\n")
+		pos.Col = 1
+		s = s[lastNewline+1:]
+	}
+	pos.Col += utf8.RuneCountInString(s)
+	return pos
+}
+
+func (ap *astParser) done() bool {
+	return ap.pos >= len(ap.tokens)
+}
+
+func (ap *astParser) peek() Token {
+	if ap.done() {
+		return Token{}
+	}
+	return ap.tokens[ap.pos]
+}
+
+func (ap *astParser) next() Token {
+	t := ap.peek()
+	if !ap.done() {
+		ap.pos++
+	}
+	return t
+}
+
+// peekWord returns the lowercased value of the next token if it is a bare
+// word, or a blank string otherwise.
+func (ap *astParser) peekWord() string {
+	if t := ap.peek(); t.typ == TokenWord {
+		return strings.ToLower(t.val)
+	}
+	return ""
+}
+
+// acceptWords consumes the supplied sequence of bare words, if present.
+func (ap *astParser) acceptWords(words ...string) bool {
+	if ap.pos+len(words) > len(ap.tokens) {
+		return false
+	}
+	for n, w := range words {
+		if t := ap.tokens[ap.pos+n]; t.typ != TokenWord || !strings.EqualFold(t.val, w) {
+			return false
+		}
+	}
+	ap.pos += len(words)
+	return true
+}
+
+func (ap *astParser) acceptSymbol(sym string) bool {
+	if t := ap.peek(); t.typ == TokenSymbol && t.val == sym {
+		ap.pos++
+		return true
+	}
+	return false
+}
+
+func (ap *astParser) errorf(format string, a ...interface{}) error {
+	var pos Pos
+	if ap.done() {
+		pos = ap.position(len(ap.text))
+	} else {
+		pos = ap.position(int(ap.peek().offset))
+	}
+	return fmt.Errorf("%s at line %d, column %d", fmt.Sprintf(format, a...), pos.Line, pos.Col)
+}
+
+// textBetween returns the raw text from the start of token index from, through
+// the end of token index to-1.
+func (ap *astParser) textBetween(from, to int) string {
+	if from >= to {
+		return ""
+	}
+	start := int(ap.tokens[from].offset)
+	end := int(ap.tokens[to-1].offset) + len(ap.tokens[to-1].val)
+	return ap.text[start:end]
+}
+
+// parseName consumes an optionally schema-qualified object name.
+func (ap *astParser) parseName() (schema, name string, err error) {
+	var ok bool
+	if name, ok = getNameFromToken(ap.peek()); !ok {
+		return "", "", ap.errorf("Expected object name")
+	}
+	ap.pos++
+	if ap.acceptSymbol(".") {
+		schema = name
+		if name, ok = getNameFromToken(ap.peek()); !ok {
+			return "", "", ap.errorf("Expected object name after schema qualifier")
+		}
+		ap.pos++
+	}
+	return schema, name, nil
+}
+
+// skipParenGroup consumes tokens through the parenthesis matching an opening
+// parenthesis at the current position, returning the index of the closing
+// parenthesis token.
+func (ap *astParser) skipParenGroup() (closeIndex int, err error) {
+	if !ap.acceptSymbol("(") {
+		return 0, ap.errorf("Expected (")
+	}
+	depth := 1
+	for !ap.done() {
+		t := ap.next()
+		if t.typ == TokenSymbol && t.val == "(" {
+			depth++
+		} else if t.typ == TokenSymbol && t.val == ")" {
+			if depth--; depth == 0 {
+				return ap.pos - 1, nil
+			}
+		}
+	}
+	return 0, ap.errorf("Unterminated parenthesis")
+}
+
+// splitList returns [start, end) token index ranges for each top-level
+// comma-separated item between token indexes from and to.
+func (ap *astParser) splitList(from, to int) (items [][2]int) {
+	var depth int
+	start := from
+	for n := from; n < to; n++ {
+		t := ap.tokens[n]
+		if t.typ != TokenSymbol {
+			continue
+		} else if t.val == "(" {
+			depth++
+		} else if t.val == ")" {
+			depth--
+		} else if t.val == "," && depth == 0 {
+			items = append(items, [2]int{start, n})
+			start = n + 1
+		}
+	}
+	if start < to {
+		items = append(items, [2]int{start, to})
+	}
+	return items
+}
+
+// parseParenList consumes a parenthesized list, returning the raw text of each
+// item. Items consisting of a single identifier have their backticks removed.
+func (ap *astParser) parseParenList() ([]string, error) {
+	openIndex := ap.pos
+	closeIndex, err := ap.skipParenGroup()
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, item := range ap.splitList(openIndex+1, closeIndex) {
+		if item[1]-item[0] == 1 {
+			if name, ok := getNameFromToken(ap.tokens[item[0]]); ok {
+				result = append(result, name)
+				continue
+			}
+		}
+		result = append(result, ap.textBetween(item[0], item[1]))
+	}
+	return result, nil
+}
+
+// parseDataType consumes a data type, including any parenthesized length or
+// value list, and any trailing modifiers which are part of the type.
+func (ap *astParser) parseDataType() (string, error) {
+	start := ap.pos
+	if ap.peek().typ != TokenWord {
+		return "", ap.errorf("Expected data type")
+	}
+	ap.pos++
+	if w := ap.peekWord(); w == "varying" || w == "precision" { // CHARACTER VARYING, DOUBLE PRECISION
+		ap.pos++
+	}
+	if t := ap.peek(); t.typ == TokenSymbol && t.val == "(" {
+		if _, err := ap.skipParenGroup(); err != nil {
+			return "", err
+		}
+	}
+	for {
+		switch ap.peekWord() {
+		case "unsigned", "signed", "zerofill", "binary":
+			ap.pos++
+			continue
+		case "charset", "collate":
+			ap.pos += 2
+			continue
+		case "character":
+			if ap.acceptWords("character", "set") {
+				ap.pos++
+				continue
+			}
+		}
+		break
+	}
+	if ap.pos > len(ap.tokens) {
+		return "", ap.errorf("Unexpected end of data type")
+	}
+	return ap.textBetween(start, ap.pos), nil
+}
+
+func (ap *astParser) parseCreateTable() (*CreateTableNode, error) {
+	node := &CreateTableNode{Pos: ap.position(0)}
+	if !ap.acceptWords("create") {
+		return nil, ap.errorf("Expected CREATE")
+	}
+	ap.acceptWords("temporary")
+	if !ap.acceptWords("table") {
+		return nil, ap.errorf("Expected TABLE")
+	}
+	ap.acceptWords("if", "not", "exists")
+	var err error
+	if node.Schema, node.Name, err = ap.parseName(); err != nil {
+		return nil, err
+	}
+
+	openIndex := ap.pos
+	closeIndex, err := ap.skipParenGroup()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range ap.splitList(openIndex+1, closeIndex) {
+		if err := ap.parseTableElement(node, item[0], item[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Remaining tokens are table options, optionally followed by partitioning
+	optionsStart := ap.pos
+	for !ap.done() && ap.peekWord() != "partition" {
+		ap.pos++
+	}
+	node.Options = ap.textBetween(optionsStart, ap.pos)
+	if !ap.done() {
+		node.Partitioning = ap.textBetween(ap.pos, len(ap.tokens))
+	}
+	return node, nil
+}
+
+// parseTableElement parses a single column, index, or constraint definition
+// spanning token indexes [from, to).
+func (ap *astParser) parseTableElement(node *CreateTableNode, from, to int) error {
+	if from >= to {
+		return ap.errorf("Empty definition")
+	}
+	// Use a sub-parser limited to this element's tokens
+	sub := &astParser{stmt: ap.stmt, text: ap.text, tokens: ap.tokens[:to], pos: from}
+	pos := ap.position(int(ap.tokens[from].offset))
+	text := ap.textBetween(from, to)
+
+	var constraintName string
+	if sub.acceptWords("constraint") {
+		if w := sub.peekWord(); w != "primary" && w != "unique" && w != "foreign" && w != "check" {
+			constraintName, _ = getNameFromToken(sub.next())
+		}
+	}
+
+	switch sub.peekWord() {
+	case "primary", "unique", "fulltext", "spatial", "key", "index":
+		idx := &IndexDefNode{Pos: pos, Text: text, Kind: "INDEX"}
+		if sub.acceptWords("primary", "key") {
+			idx.Kind, idx.Name = "PRIMARY", "PRIMARY"
+		} else {
+			if w := sub.peekWord(); w != "key" && w != "index" {
+				idx.Kind = strings.ToUpper(w)
+				sub.pos++
+			}
+			if w := sub.peekWord(); w == "key" || w == "index" {
+				sub.pos++
+			}
+			idx.Name = constraintName
+			if t := sub.peek(); !(t.typ == TokenSymbol && t.val == "(") {
+				idx.Name, _ = getNameFromToken(sub.next())
+			}
+		}
+		var err error
+		if idx.Parts, err = sub.parseParenList(); err != nil {
+			return err
+		}
+		node.Indexes = append(node.Indexes, idx)
+	case "foreign":
+		fk := &ForeignKeyDefNode{Pos: pos, Text: text, Name: constraintName}
+		sub.acceptWords("foreign", "key")
+		if t := sub.peek(); !(t.typ == TokenSymbol && t.val == "(") && fk.Name == "" {
+			fk.Name, _ = getNameFromToken(sub.next())
+		}
+		var err error
+		if fk.Columns, err = sub.parseParenList(); err != nil {
+			return err
+		}
+		if !sub.acceptWords("references") {
+			return sub.errorf("Expected REFERENCES")
+		}
+		if fk.ReferencedSchema, fk.ReferencedTable, err = sub.parseName(); err != nil {
+			return err
+		}
+		if fk.ReferencedColumns, err = sub.parseParenList(); err != nil {
+			return err
+		}
+		node.ForeignKeys = append(node.ForeignKeys, fk)
+	case "check":
+		cc := &CheckDefNode{Pos: pos, Text: text, Name: constraintName}
+		sub.pos++
+		openIndex := sub.pos
+		closeIndex, err := sub.skipParenGroup()
+		if err != nil {
+			return err
+		}
+		cc.Clause = ap.textBetween(openIndex+1, closeIndex)
+		node.Checks = append(node.Checks, cc)
+	default:
+		if constraintName != "" {
+			return sub.errorf("Unexpected constraint type")
+		}
+		col := &ColumnDefNode{Pos: pos, Text: text}
+		var ok bool
+		if col.Name, ok = getNameFromToken(sub.next()); !ok {
+			return sub.errorf("Expected column name")
+		}
+		var err error
+		if col.Type, err = sub.parseDataType(); err != nil {
+			return err
+		}
+		col.Attributes = ap.textBetween(sub.pos, to)
+		node.Columns = append(node.Columns, col)
+	}
+	return nil
+}
+
+// routineCharacteristics maps the first word of each stored routine
+// characteristic to the number of additional tokens it consumes.
+var routineCharacteristics = map[string]int{
+	"comment":       1,
+	"language":      1,
+	"not":           1,
+	"deterministic": 0,
+	"contains":      1,
+	"no":            1,
+	"reads":         2,
+	"modifies":      2,
+	"sql":           2,
+}
+
+func (ap *astParser) parseCreateRoutine() (*CreateRoutineNode, error) {
+	node := &CreateRoutineNode{Pos: ap.position(0)}
+	if !ap.acceptWords("create") {
+		return nil, ap.errorf("Expected CREATE")
+	}
+	if ap.acceptWords("definer") {
+		if !ap.acceptSymbol("=") {
+			return nil, ap.errorf("Expected =")
+		}
+		start := ap.pos
+		for !ap.done() {
+			if w := ap.peekWord(); w == "procedure" || w == "function" {
+				break
+			}
+			ap.pos++
+		}
+		node.Definer = ap.textBetween(start, ap.pos)
+	}
+	switch ap.peekWord() {
+	case "procedure":
+		node.Type = ObjectTypeProc
+	case "function":
+		node.Type = ObjectTypeFunc
+	default:
+		return nil, ap.errorf("Expected PROCEDURE or FUNCTION")
+	}
+	ap.pos++
+	ap.acceptWords("if", "not", "exists")
+	var err error
+	if node.Schema, node.Name, err = ap.parseName(); err != nil {
+		return nil, err
+	}
+
+	openIndex := ap.pos
+	closeIndex, err := ap.skipParenGroup()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range ap.splitList(openIndex+1, closeIndex) {
+		sub := &astParser{stmt: ap.stmt, text: ap.text, tokens: ap.tokens[:item[1]], pos: item[0]}
+		param := &ParamNode{Pos: ap.position(int(ap.tokens[item[0]].offset))}
+		if node.Type == ObjectTypeProc {
+			param.Mode = "IN"
+			if w := sub.peekWord(); w == "in" || w == "out" || w == "inout" {
+				param.Mode = strings.ToUpper(w)
+				sub.pos++
+			}
+		}
+		var ok bool
+		if param.Name, ok = getNameFromToken(sub.next()); !ok {
+			return nil, sub.errorf("Expected parameter name")
+		}
+		param.Type = ap.textBetween(sub.pos, item[1])
+		node.Params = append(node.Params, param)
+	}
+
+	if node.Type == ObjectTypeFunc {
+		if !ap.acceptWords("returns") {
+			return nil, ap.errorf("Expected RETURNS")
+		}
+		if node.Returns, err = ap.parseDataType(); err != nil {
+			return nil, err
+		}
+	}
+
+	charStart := ap.pos
+	for !ap.done() {
+		w := ap.peekWord()
+		extra, ok := routineCharacteristics[w]
+		if !ok || (w == "sql" && !(ap.pos+1 < len(ap.tokens) && strings.EqualFold(ap.tokens[ap.pos+1].val, "security"))) {
+			break
+		}
+		ap.pos += 1 + extra
+	}
+	if ap.pos >= len(ap.tokens) {
+		return nil, ap.errorf("Expected routine body")
+	}
+	node.Characteristics = ap.textBetween(charStart, ap.pos)
+	bodyOffset := int(ap.tokens[ap.pos].offset)
+	node.Body = ap.text[bodyOffset:]
+	node.BodyPos = ap.position(bodyOffset)
+	return node, nil
+}
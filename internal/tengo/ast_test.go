@@ -0,0 +1,127 @@
+package tengo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStatementASTCreateTable(t *testing.T) {
+	input := "USE foo;\n" + `CREATE TABLE IF NOT EXISTS bar.` + "`my tbl`" + ` (
+  id int(10) unsigned NOT NULL AUTO_INCREMENT,
+  name varchar(40) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT 'a,b',
+  parent_id int unsigned,
+  PRIMARY KEY (id),
+  UNIQUE KEY ` + "`name`" + ` (name(10), parent_id),
+  KEY idx_expr ((lower(name))),
+  CONSTRAINT fk_parent FOREIGN KEY (parent_id) REFERENCES other.parents (id) ON DELETE CASCADE,
+  CONSTRAINT chk_id CHECK (id > 0 AND (id < 1000))
+) ENGINE=InnoDB DEFAULT CHARSET=latin1
+PARTITION BY HASH (id) PARTITIONS 4;
+`
+	stmts, err := ParseStatementsInString(input)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseStatementsInString: %v", err)
+	}
+	if _, err := stmts[0].AST(); err != ErrNoAST {
+		t.Errorf("Expected USE command to return ErrNoAST, instead found %v", err)
+	}
+	node, err := stmts[1].AST()
+	if err != nil {
+		t.Fatalf("Unexpected error from AST: %v", err)
+	}
+	ct, ok := node.(*CreateTableNode)
+	if !ok {
+		t.Fatalf("Expected *CreateTableNode, instead found %T", node)
+	}
+	if ct.Schema != "bar" || ct.Name != "my tbl" || ct.Pos.String() != "2:1" {
+		t.Errorf("Unexpected table name or position: %s %s %s", ct.Schema, ct.Name, ct.Pos)
+	}
+	if len(ct.Columns) != 3 {
+		t.Fatalf("Expected 3 columns, instead found %d", len(ct.Columns))
+	}
+	expectCols := []ColumnDefNode{
+		{Name: "id", Type: "int(10) unsigned", Attributes: "NOT NULL AUTO_INCREMENT"},
+		{Name: "name", Type: "varchar(40) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin", Attributes: "DEFAULT 'a,b'"},
+		{Name: "parent_id", Type: "int unsigned"},
+	}
+	for n, expect := range expectCols {
+		col := ct.Columns[n]
+		if col.Name != expect.Name || col.Type != expect.Type || col.Attributes != expect.Attributes {
+			t.Errorf("Unexpected column[%d]: %+v", n, *col)
+		}
+	}
+	if pos := ct.Columns[1].Pos; pos.Line != 4 || pos.Col != 3 || input[9+pos.Offset:9+pos.Offset+4] != "name" {
+		t.Errorf("Unexpected position for column[1]: %+v", pos)
+	}
+	if len(ct.Indexes) != 3 {
+		t.Fatalf("Expected 3 indexes, instead found %d", len(ct.Indexes))
+	}
+	if idx := ct.Indexes[0]; idx.Kind != "PRIMARY" || idx.Name != "PRIMARY" || fmt.Sprint(idx.Parts) != "[id]" {
+		t.Errorf("Unexpected primary key: %+v", *idx)
+	}
+	if idx := ct.Indexes[1]; idx.Kind != "UNIQUE" || idx.Name != "name" || fmt.Sprint(idx.Parts) != "[name(10) parent_id]" {
+		t.Errorf("Unexpected unique index: %+v", *idx)
+	}
+	if idx := ct.Indexes[2]; idx.Kind != "INDEX" || idx.Name != "idx_expr" || fmt.Sprint(idx.Parts) != "[(lower(name))]" {
+		t.Errorf("Unexpected functional index: %+v", *idx)
+	}
+	if len(ct.ForeignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key, instead found %d", len(ct.ForeignKeys))
+	}
+	if fk := ct.ForeignKeys[0]; fk.Name != "fk_parent" || fmt.Sprint(fk.Columns) != "[parent_id]" || fk.ReferencedSchema != "other" || fk.ReferencedTable != "parents" || fmt.Sprint(fk.ReferencedColumns) != "[id]" {
+		t.Errorf("Unexpected foreign key: %+v", *fk)
+	}
+	if len(ct.Checks) != 1 || ct.Checks[0].Name != "chk_id" || ct.Checks[0].Clause != "id > 0 AND (id < 1000)" {
+		t.Errorf("Unexpected checks: %+v", ct.Checks)
+	}
+	if ct.Options != "ENGINE=InnoDB DEFAULT CHARSET=latin1" {
+		t.Errorf("Unexpected table options: %q", ct.Options)
+	}
+	if ct.Partitioning != "PARTITION BY HASH (id) PARTITIONS 4" {
+		t.Errorf("Unexpected partitioning: %q", ct.Partitioning)
+	}
+
+	// Confirm errors are returned for unparseable definitions
+	stmt := ParseStatementInString("CREATE TABLE foo (id int, CONSTRAINT c1 FOREIGN KEY (id) parents (id))")
+	if _, err := stmt.AST(); err == nil {
+		t.Error("Expected error from malformed foreign key, but err was nil")
+	}
+}
+
+func TestStatementASTCreateRoutine(t *testing.T) {
+	input := "CREATE DEFINER=`root`@`%` PROCEDURE `proc1`(IN a int, OUT b varchar(20), c decimal(10,2))\n" +
+		"    SQL SECURITY INVOKER\n    COMMENT 'hello'\nBEGIN\n  SELECT a INTO b;\nEND"
+	node, err := ParseStatementInString(input).AST()
+	if err != nil {
+		t.Fatalf("Unexpected error from AST: %v", err)
+	}
+	cr, ok := node.(*CreateRoutineNode)
+	if !ok {
+		t.Fatalf("Expected *CreateRoutineNode, instead found %T", node)
+	}
+	if cr.Type != ObjectTypeProc || cr.Name != "proc1" || cr.Definer != "`root`@`%`" {
+		t.Errorf("Unexpected routine header: %+v", *cr)
+	}
+	var params []string
+	for _, param := range cr.Params {
+		params = append(params, param.Mode+" "+param.Name+" "+param.Type)
+	}
+	if fmt.Sprint(params) != "[IN a int OUT b varchar(20) IN c decimal(10,2)]" {
+		t.Errorf("Unexpected params: %v", params)
+	}
+	if cr.Characteristics != "SQL SECURITY INVOKER\n    COMMENT 'hello'" {
+		t.Errorf("Unexpected characteristics: %q", cr.Characteristics)
+	}
+	if cr.Body != "BEGIN\n  SELECT a INTO b;\nEND" || cr.BodyPos.String() != "4:1" {
+		t.Errorf("Unexpected body or body position: %q at %s", cr.Body, cr.BodyPos)
+	}
+
+	input = "CREATE FUNCTION f1(x int) RETURNS varchar(20) CHARSET utf8mb4 DETERMINISTIC RETURN CONCAT('x', x)"
+	if node, err = ParseStatementInString(input).AST(); err != nil {
+		t.Fatalf("Unexpected error from AST: %v", err)
+	}
+	cr = node.(*CreateRoutineNode)
+	if cr.Type != ObjectTypeFunc || len(cr.Params) != 1 || cr.Params[0].Mode != "" || cr.Returns != "varchar(20) CHARSET utf8mb4" || cr.Characteristics != "DETERMINISTIC" || cr.Body != "RETURN CONCAT('x', x)" {
+		t.Errorf("Unexpected function node: %+v", *cr)
+	}
+}
@@ -0,0 +1,544 @@
+package tengo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TableBuilder constructs a Table programmatically, rather than via
+// introspection or parsing. Its methods may be chained; any problems are
+// reported by Build, which validates the table and renders its CREATE TABLE
+// statement for a specific flavor.
+type TableBuilder struct {
+	table *Table
+	errs  []string
+}
+
+// ColumnOption adjusts a column being added by TableBuilder.Column.
+type ColumnOption func(*Column)
+
+// NewTableBuilder returns a TableBuilder for a table with the supplied name.
+// The table defaults to the InnoDB storage engine and the utf8mb4 character
+// set.
+func NewTableBuilder(name string) *TableBuilder {
+	return &TableBuilder{
+		table: &Table{
+			Name:    name,
+			Engine:  "InnoDB",
+			CharSet: "utf8mb4",
+		},
+	}
+}
+
+func (b *TableBuilder) addError(format string, a ...interface{}) {
+	b.errs = append(b.errs, fmt.Sprintf(format, a...))
+}
+
+// Engine sets the table's storage engine.
+func (b *TableBuilder) Engine(engine string) *TableBuilder {
+	b.table.Engine = engine
+	return b
+}
+
+// CharSet sets the table's default character set and collation. If collation
+// is empty, the character set's default collation for the flavor supplied to
+// Build is used.
+func (b *TableBuilder) CharSet(charSet, collation string) *TableBuilder {
+	b.table.CharSet = strings.ToLower(charSet)
+	b.table.Collation = strings.ToLower(collation)
+	return b
+}
+
+// Comment sets the table's comment.
+func (b *TableBuilder) Comment(comment string) *TableBuilder {
+	b.table.Comment = comment
+	return b
+}
+
+// Column adds a column with the supplied name and type, for example
+// "varchar(30)" or "bigint unsigned". Columns are nullable unless the NotNull
+// option is supplied.
+func (b *TableBuilder) Column(name, colType string, opts ...ColumnOption) *TableBuilder {
+	col := &Column{
+		Name:     name,
+		TypeInDB: strings.ToLower(strings.TrimSpace(colType)),
+		Nullable: true,
+	}
+	for _, opt := range opts {
+		opt(col)
+	}
+	b.table.Columns = append(b.table.Columns, col)
+	return b
+}
+
+// NotNull marks a column as NOT NULL.
+func NotNull() ColumnOption {
+	return func(c *Column) {
+		c.Nullable = false
+	}
+}
+
+// AutoIncrement marks a column as AUTO_INCREMENT. This also implies NotNull.
+func AutoIncrement() ColumnOption {
+	return func(c *Column) {
+		c.AutoIncrement = true
+		c.Nullable = false
+	}
+}
+
+// DefaultExpr sets a column's default to the supplied expression, which is
+// used verbatim; for example "0", "NULL", or "CURRENT_TIMESTAMP".
+func DefaultExpr(expr string) ColumnOption {
+	return func(c *Column) {
+		c.Default = expr
+	}
+}
+
+// DefaultString sets a column's default to the supplied string literal, which
+// will be quoted and escaped as needed.
+func DefaultString(value string) ColumnOption {
+	return func(c *Column) {
+		c.Default = "'" + EscapeValueForCreateTable(value) + "'"
+	}
+}
+
+// OnUpdate sets a column's ON UPDATE expression, typically
+// "CURRENT_TIMESTAMP".
+func OnUpdate(expr string) ColumnOption {
+	return func(c *Column) {
+		c.OnUpdate = expr
+	}
+}
+
+// Generated makes a column a generated column based on the supplied
+// expression, either VIRTUAL or STORED.
+func Generated(expr string, virtual bool) ColumnOption {
+	return func(c *Column) {
+		c.GenerationExpr = expr
+		c.Virtual = virtual
+	}
+}
+
+// ColumnCharSet overrides the table's default character set and collation
+// for a textual column. If collation is empty, the character set's default
+// collation is used.
+func ColumnCharSet(charSet, collation string) ColumnOption {
+	return func(c *Column) {
+		c.CharSet = strings.ToLower(charSet)
+		c.Collation = strings.ToLower(collation)
+	}
+}
+
+// ColumnComment sets a column's comment.
+func ColumnComment(comment string) ColumnOption {
+	return func(c *Column) {
+		c.Comment = comment
+	}
+}
+
+// Invisible marks a column as invisible, which requires MySQL 8.0.23+ or
+// MariaDB 10.3+.
+func Invisible() ColumnOption {
+	return func(c *Column) {
+		c.Invisible = true
+	}
+}
+
+// PrimaryKey sets the table's primary key to the supplied columns.
+func (b *TableBuilder) PrimaryKey(colNames ...string) *TableBuilder {
+	if b.table.PrimaryKey != nil {
+		b.addError("primary key defined more than once")
+	}
+	b.table.PrimaryKey = &Index{
+		Name:       "PRIMARY",
+		Parts:      builderIndexParts(colNames),
+		PrimaryKey: true,
+		Unique:     true,
+		Type:       "BTREE",
+	}
+	return b
+}
+
+// Index adds a secondary index with the supplied name on the supplied columns.
+// A column name may be suffixed with a parenthesized prefix length, for
+// example "name(10)".
+func (b *TableBuilder) Index(name string, colNames ...string) *TableBuilder {
+	b.table.SecondaryIndexes = append(b.table.SecondaryIndexes, &Index{
+		Name:  name,
+		Parts: builderIndexParts(colNames),
+		Type:  "BTREE",
+	})
+	return b
+}
+
+// UniqueIndex adds a unique secondary index with the supplied name on the
+// supplied columns.
+func (b *TableBuilder) UniqueIndex(name string, colNames ...string) *TableBuilder {
+	b.Index(name, colNames...)
+	b.table.SecondaryIndexes[len(b.table.SecondaryIndexes)-1].Unique = true
+	return b
+}
+
+// ForeignKey adds a foreign key constraint. referencedTable may be prefixed
+// with a schema name and a dot, if the parent table is in another schema.
+// Empty onDelete or onUpdate rules are treated as omitted, which the server
+// reports as NO ACTION in MySQL 8 and as RESTRICT in other flavors.
+func (b *TableBuilder) ForeignKey(name string, colNames []string, referencedTable string, referencedColNames []string, onDelete, onUpdate string) *TableBuilder {
+	fk := &ForeignKey{
+		Name:                  name,
+		ColumnNames:           colNames,
+		ReferencedTableName:   referencedTable,
+		ReferencedColumnNames: referencedColNames,
+		DeleteRule:            builderFKRule(onDelete),
+		UpdateRule:            builderFKRule(onUpdate),
+	}
+	if schemaName, tableName, ok := strings.Cut(referencedTable, "."); ok {
+		fk.ReferencedSchemaName, fk.ReferencedTableName = schemaName, tableName
+	}
+	b.table.ForeignKeys = append(b.table.ForeignKeys, fk)
+	return b
+}
+
+// Check adds a check constraint with the supplied name and expression.
+func (b *TableBuilder) Check(name, clause string) *TableBuilder {
+	b.table.Checks = append(b.table.Checks, &Check{
+		Name:     name,
+		Clause:   clause,
+		Enforced: true,
+	})
+	return b
+}
+
+func builderIndexParts(colNames []string) []IndexPart {
+	parts := make([]IndexPart, len(colNames))
+	for n, colName := range colNames {
+		if open := strings.IndexByte(colName, '('); open > 0 && strings.HasSuffix(colName, ")") {
+			var prefix uint16
+			if _, err := fmt.Sscanf(colName[open+1:len(colName)-1], "%d", &prefix); err == nil {
+				parts[n] = IndexPart{ColumnName: colName[:open], PrefixLength: prefix}
+				continue
+			}
+		}
+		parts[n] = IndexPart{ColumnName: colName}
+	}
+	return parts
+}
+
+func builderFKRule(rule string) string {
+	return strings.ToUpper(rule)
+}
+
+// Build validates the table and returns it, with its CreateStatement set to
+// DDL appropriate for flavor. The returned Table is a new value on each call,
+// so a TableBuilder may be used to build tables for multiple flavors.
+func (b *TableBuilder) Build(flavor Flavor) (*Table, error) {
+	t := b.table.copyForBuild()
+	errs := append([]string{}, b.errs...)
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, a...))
+	}
+
+	if t.Name == "" {
+		fail("table name is empty")
+	}
+	if t.Collation == "" {
+		t.Collation = defaultCollationForCharSet(t.CharSet, flavor)
+		if t.Collation == "" {
+			fail("default collation of character set %s is not known; supply a collation explicitly", t.CharSet)
+		}
+	}
+	t.CollationIsDefault = (t.Collation == defaultCollationForCharSet(t.CharSet, flavor))
+	if len(t.Columns) == 0 {
+		fail("table has no columns")
+	}
+
+	cols := make(map[string]*Column, len(t.Columns))
+	var autoIncCol *Column
+	for _, col := range t.Columns {
+		lowerName := strings.ToLower(col.Name)
+		if col.Name == "" {
+			fail("column name is empty")
+		} else if cols[lowerName] != nil {
+			fail("column %s defined more than once", EscapeIdentifier(col.Name))
+		}
+		cols[lowerName] = col
+		if col.TypeInDB == "" {
+			fail("column %s has no type", EscapeIdentifier(col.Name))
+		}
+		if flavor.OmitIntDisplayWidth() {
+			col.TypeInDB, _ = StripDisplayWidth(col.TypeInDB)
+		} else {
+			col.TypeInDB = addDisplayWidth(col.TypeInDB)
+		}
+		if col.AutoIncrement {
+			if autoIncCol != nil {
+				fail("only one AUTO_INCREMENT column is permitted, but found %s and %s", EscapeIdentifier(autoIncCol.Name), EscapeIdentifier(col.Name))
+			}
+			autoIncCol = col
+		}
+		if col.Invisible && !flavor.Min(FlavorMySQL80.Dot(23)) && !flavor.Min(FlavorMariaDB103) {
+			fail("column %s is invisible, which is not supported by %s", EscapeIdentifier(col.Name), flavor.Family())
+		}
+		if isTextualType(col.TypeInDB) {
+			if col.CharSet == "" {
+				col.CharSet, col.Collation = t.CharSet, t.Collation
+			} else if col.Collation == "" {
+				col.Collation = defaultCollationForCharSet(col.CharSet, flavor)
+				if col.Collation == "" {
+					fail("default collation of character set %s is not known; supply a collation explicitly for column %s", col.CharSet, EscapeIdentifier(col.Name))
+				}
+			}
+			col.CollationIsDefault = (col.Collation == defaultCollationForCharSet(col.CharSet, flavor))
+		} else if col.CharSet != "" {
+			fail("column %s has a character set, but type %s is not textual", EscapeIdentifier(col.Name), col.TypeInDB)
+		}
+	}
+
+	indexes := t.SecondaryIndexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*Index{t.PrimaryKey}, indexes...)
+	}
+	indexNames := make(map[string]bool, len(indexes))
+	autoIncIndexed := (autoIncCol == nil)
+	for _, idx := range indexes {
+		lowerName := strings.ToLower(idx.Name)
+		if idx.Name == "" {
+			fail("index name is empty")
+		} else if indexNames[lowerName] {
+			fail("index %s defined more than once", EscapeIdentifier(idx.Name))
+		}
+		indexNames[lowerName] = true
+		if len(idx.Parts) == 0 {
+			fail("index %s has no columns", EscapeIdentifier(idx.Name))
+		}
+		for n, part := range idx.Parts {
+			col := cols[strings.ToLower(part.ColumnName)]
+			if col == nil {
+				fail("index %s references nonexistent column %s", EscapeIdentifier(idx.Name), EscapeIdentifier(part.ColumnName))
+				continue
+			}
+			if idx.PrimaryKey && col.Nullable {
+				col.Nullable = false // primary key columns are implicitly NOT NULL
+			}
+			if n == 0 && col == autoIncCol {
+				autoIncIndexed = true
+			}
+		}
+	}
+	if !autoIncIndexed {
+		fail("AUTO_INCREMENT column %s must be the first column of an index", EscapeIdentifier(autoIncCol.Name))
+	}
+
+	// Mirror SHOW CREATE TABLE's display of an implicit DEFAULT NULL
+	for _, col := range t.Columns {
+		if col.Default == "" && col.Nullable && !col.AutoIncrement && col.GenerationExpr == "" {
+			if flavor.Min(FlavorMariaDB102) || !(strings.HasSuffix(col.TypeInDB, "blob") || strings.HasSuffix(col.TypeInDB, "text")) {
+				col.Default = "NULL"
+			}
+		}
+	}
+
+	implicitRule := "RESTRICT"
+	if flavor.Min(FlavorMySQL80) {
+		implicitRule = "NO ACTION"
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == "" {
+			fail("foreign key name is empty")
+		}
+		if fk.DeleteRule == "" {
+			fk.DeleteRule = implicitRule
+		}
+		if fk.UpdateRule == "" {
+			fk.UpdateRule = implicitRule
+		}
+		if len(fk.ColumnNames) == 0 || len(fk.ColumnNames) != len(fk.ReferencedColumnNames) {
+			fail("foreign key %s must have the same nonzero number of columns on each side", EscapeIdentifier(fk.Name))
+		}
+		for _, colName := range fk.ColumnNames {
+			if cols[strings.ToLower(colName)] == nil {
+				fail("foreign key %s references nonexistent column %s", EscapeIdentifier(fk.Name), EscapeIdentifier(colName))
+			}
+		}
+		if fk.ReferencedTableName == "" {
+			fail("foreign key %s has no referenced table", EscapeIdentifier(fk.Name))
+		}
+		for _, rule := range []string{fk.DeleteRule, fk.UpdateRule} {
+			switch rule {
+			case "RESTRICT", "CASCADE", "SET NULL", "NO ACTION", "SET DEFAULT":
+			default:
+				fail("foreign key %s has invalid referential action %s", EscapeIdentifier(fk.Name), rule)
+			}
+		}
+	}
+	if len(t.ForeignKeys) > 0 && flavor.SortedForeignKeys() {
+		sort.Slice(t.ForeignKeys, func(i, j int) bool {
+			return t.ForeignKeys[i].Name < t.ForeignKeys[j].Name
+		})
+	}
+
+	if len(t.Checks) > 0 && !flavor.HasCheckConstraints() {
+		fail("check constraints are not supported by %s", flavor.Family())
+	}
+	for _, cc := range t.Checks {
+		if cc.Name == "" {
+			fail("check constraint name is empty")
+		} else if cc.Clause == "" {
+			fail("check constraint %s has an empty clause", EscapeIdentifier(cc.Name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid table %s: %s", EscapeIdentifier(t.Name), strings.Join(errs, "; "))
+	}
+	t.CreateStatement = t.GeneratedCreateStatement(flavor)
+	return t, nil
+}
+
+// copyForBuild returns a copy of the table with its columns, indexes, foreign
+// keys, and checks also copied, so that Build may adjust them freely.
+func (t *Table) copyForBuild() *Table {
+	result := *t
+	result.Columns = make([]*Column, len(t.Columns))
+	for n, col := range t.Columns {
+		colCopy := *col
+		result.Columns[n] = &colCopy
+	}
+	if t.PrimaryKey != nil {
+		pkCopy := *t.PrimaryKey
+		result.PrimaryKey = &pkCopy
+	}
+	result.SecondaryIndexes = make([]*Index, len(t.SecondaryIndexes))
+	for n, idx := range t.SecondaryIndexes {
+		idxCopy := *idx
+		result.SecondaryIndexes[n] = &idxCopy
+	}
+	result.ForeignKeys = make([]*ForeignKey, len(t.ForeignKeys))
+	for n, fk := range t.ForeignKeys {
+		fkCopy := *fk
+		result.ForeignKeys[n] = &fkCopy
+	}
+	result.Checks = make([]*Check, len(t.Checks))
+	for n, cc := range t.Checks {
+		ccCopy := *cc
+		result.Checks[n] = &ccCopy
+	}
+	return &result
+}
+
+// defaultDisplayWidths maps int family types to their default display widths
+// when signed and unsigned, respectively.
+var defaultDisplayWidths = map[string][2]int{
+	"tinyint":   {4, 3},
+	"smallint":  {6, 5},
+	"mediumint": {9, 8},
+	"int":       {11, 10},
+	"bigint":    {20, 20},
+}
+
+// addDisplayWidth adds the default display width to an int family column type
+// which lacks one, as shown by SHOW CREATE TABLE in flavors prior to MySQL
+// 8.0.19. Other column types are returned unchanged.
+func addDisplayWidth(colType string) string {
+	base, modifiers, _ := strings.Cut(colType, " ")
+	widths, ok := defaultDisplayWidths[base]
+	if !ok {
+		if base == "year" {
+			return "year(4)"
+		}
+		return colType
+	}
+	width := widths[0]
+	if strings.Contains(modifiers, "unsigned") {
+		width = widths[1]
+	}
+	if modifiers != "" {
+		modifiers = " " + modifiers
+	}
+	return fmt.Sprintf("%s(%d)%s", base, width, modifiers)
+}
+
+// isTextualType returns true if colType is a type which has a character set.
+func isTextualType(colType string) bool {
+	base := colType
+	if pos := strings.IndexAny(base, "( "); pos > -1 {
+		base = base[:pos]
+	}
+	switch base {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
+// defaultCollations maps character sets to their default collations, for
+// character sets whose default does not vary by flavor.
+var defaultCollations = map[string]string{
+	"armscii8": "armscii8_general_ci",
+	"ascii":    "ascii_general_ci",
+	"big5":     "big5_chinese_ci",
+	"binary":   "binary",
+	"cp1250":   "cp1250_general_ci",
+	"cp1251":   "cp1251_general_ci",
+	"cp1256":   "cp1256_general_ci",
+	"cp1257":   "cp1257_general_ci",
+	"cp850":    "cp850_general_ci",
+	"cp852":    "cp852_general_ci",
+	"cp866":    "cp866_general_ci",
+	"cp932":    "cp932_japanese_ci",
+	"dec8":     "dec8_swedish_ci",
+	"eucjpms":  "eucjpms_japanese_ci",
+	"euckr":    "euckr_korean_ci",
+	"gb2312":   "gb2312_chinese_ci",
+	"gbk":      "gbk_chinese_ci",
+	"geostd8":  "geostd8_general_ci",
+	"greek":    "greek_general_ci",
+	"hebrew":   "hebrew_general_ci",
+	"hp8":      "hp8_english_ci",
+	"keybcs2":  "keybcs2_general_ci",
+	"koi8r":    "koi8r_general_ci",
+	"koi8u":    "koi8u_general_ci",
+	"latin1":   "latin1_swedish_ci",
+	"latin2":   "latin2_general_ci",
+	"latin5":   "latin5_turkish_ci",
+	"latin7":   "latin7_general_ci",
+	"macce":    "macce_general_ci",
+	"macroman": "macroman_general_ci",
+	"sjis":     "sjis_japanese_ci",
+	"swe7":     "swe7_swedish_ci",
+	"tis620":   "tis620_thai_ci",
+	"ucs2":     "ucs2_general_ci",
+	"ujis":     "ujis_japanese_ci",
+	"utf16":    "utf16_general_ci",
+	"utf16le":  "utf16le_general_ci",
+	"utf32":    "utf32_general_ci",
+}
+
+// defaultCollationForCharSet returns the default collation of the supplied
+// character set in flavor, or an empty string if not known.
+func defaultCollationForCharSet(charSet string, flavor Flavor) string {
+	switch charSet {
+	case "utf8mb4":
+		if flavor.Min(FlavorMySQL80) {
+			return "utf8mb4_0900_ai_ci"
+		}
+		return "utf8mb4_general_ci"
+	case "utf8", "utf8mb3":
+		if flavor.Min(FlavorMySQL80.Dot(30)) || flavor.Min(FlavorMariaDB106) {
+			return "utf8mb3_general_ci"
+		}
+		return "utf8_general_ci"
+	}
+	return defaultCollations[charSet]
+}
+
+// AlterStatement returns an ALTER TABLE statement which transforms from into
+// to, using the supplied modifiers. An empty string is returned if the tables
+// are identical. Both tables must have the same name.
+func AlterStatement(from, to *Table, mods StatementModifiers) (string, error) {
+	if from.Name != to.Name {
+		return "", fmt.Errorf("Cannot generate ALTER TABLE between differently-named tables %s and %s", EscapeIdentifier(from.Name), EscapeIdentifier(to.Name))
+	}
+	return NewAlterTable(from, to).Statement(mods)
+}
@@ -0,0 +1,116 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableBuilder(t *testing.T) {
+	b := NewTableBuilder("widgets").
+		Column("id", "int(10) unsigned", AutoIncrement()).
+		Column("name", "varchar(45)", NotNull(), DefaultString("it's")).
+		Column("code", "char(4)", ColumnCharSet("latin1", "")).
+		Column("owner_id", "int(10) unsigned").
+		Column("updated_at", "timestamp", NotNull(), DefaultExpr("CURRENT_TIMESTAMP"), OnUpdate("CURRENT_TIMESTAMP")).
+		PrimaryKey("id").
+		UniqueIndex("name", "name(10)").
+		Index("owner", "owner_id").
+		ForeignKey("owner_fk", []string{"owner_id"}, "owners", []string{"id"}, "cascade", "").
+		Comment("things")
+
+	table, err := b.Build(FlavorMySQL57)
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	expected := "CREATE TABLE `widgets` (\n" +
+		"  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(45) NOT NULL DEFAULT 'it''s',\n" +
+		"  `code` char(4) CHARACTER SET latin1 DEFAULT NULL,\n" +
+		"  `owner_id` int(10) unsigned DEFAULT NULL,\n" +
+		"  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `name` (`name`(10)),\n" +
+		"  KEY `owner` (`owner_id`),\n" +
+		"  CONSTRAINT `owner_fk` FOREIGN KEY (`owner_id`) REFERENCES `owners` (`id`) ON DELETE CASCADE\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='things'"
+	if table.CreateStatement != expected {
+		t.Errorf("Unexpected CREATE TABLE:\n%s\nexpected:\n%s", table.CreateStatement, expected)
+	}
+
+	// Same builder, different flavor: display widths stripped, collation shown
+	table, err = b.Build(FlavorMySQL80.Dot(30))
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	if !strings.Contains(table.CreateStatement, "`id` int unsigned NOT NULL") || !strings.HasSuffix(table.CreateStatement, "COLLATE=utf8mb4_0900_ai_ci COMMENT='things'") {
+		t.Errorf("Unexpected CREATE TABLE for MySQL 8:\n%s", table.CreateStatement)
+	}
+	if table.Columns[1].Collation != "utf8mb4_0900_ai_ci" || !table.Columns[1].CollationIsDefault {
+		t.Errorf("Unexpected collation for textual column: %+v", *table.Columns[1])
+	}
+	if table.Columns[0].CharSet != "" {
+		t.Errorf("Expected non-textual column to have no character set, instead found %q", table.Columns[0].CharSet)
+	}
+}
+
+func TestTableBuilderAlter(t *testing.T) {
+	from, err := NewTableBuilder("t").Column("id", "int", NotNull()).PrimaryKey("id").Build(FlavorMySQL80)
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	to, err := NewTableBuilder("t").Column("id", "int", NotNull()).Column("name", "varchar(20)").PrimaryKey("id").Index("name", "name").Build(FlavorMySQL80)
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	mods := StatementModifiers{Flavor: FlavorMySQL80}
+	stmt, err := AlterStatement(from, to, mods)
+	expected := "ALTER TABLE `t` ADD COLUMN `name` varchar(20) DEFAULT NULL, ADD KEY `name` (`name`)"
+	if err != nil || stmt != expected {
+		t.Errorf("Unexpected result from AlterStatement: %q, %v", stmt, err)
+	}
+	if stmt, err := AlterStatement(from, from, mods); stmt != "" || err != nil {
+		t.Errorf("Expected identical tables to return empty statement, instead found %q, %v", stmt, err)
+	}
+	other := *to
+	other.Name = "t2"
+	if _, err := AlterStatement(from, &other, mods); err == nil {
+		t.Error("Expected error from AlterStatement with differently-named tables, but err was nil")
+	}
+}
+
+func TestTableBuilderValidation(t *testing.T) {
+	cases := map[string]*TableBuilder{
+		"no columns":         NewTableBuilder("t"),
+		"duplicate column":   NewTableBuilder("t").Column("a", "int").Column("A", "int"),
+		"missing type":       NewTableBuilder("t").Column("a", ""),
+		"unknown index col":  NewTableBuilder("t").Column("a", "int").Index("idx", "b"),
+		"duplicate index":    NewTableBuilder("t").Column("a", "int").Index("idx", "a").UniqueIndex("idx", "a"),
+		"unindexed autoinc":  NewTableBuilder("t").Column("a", "int", AutoIncrement()),
+		"two autoincs":       NewTableBuilder("t").Column("a", "int", AutoIncrement()).Column("b", "int", AutoIncrement()).Index("a", "a").Index("b", "b"),
+		"fk col count":       NewTableBuilder("t").Column("a", "int").ForeignKey("fk", []string{"a"}, "p", []string{"x", "y"}, "", ""),
+		"fk bad rule":        NewTableBuilder("t").Column("a", "int").ForeignKey("fk", []string{"a"}, "p", []string{"x"}, "explode", ""),
+		"unknown charset":    NewTableBuilder("t").CharSet("klingon", "").Column("a", "int"),
+		"charset on int":     NewTableBuilder("t").Column("a", "int", ColumnCharSet("latin1", "")),
+		"check unsupported":  NewTableBuilder("t").Column("a", "int").Check("positive", "a > 0"),
+		"invisible in 5.7":   NewTableBuilder("t").Column("a", "int", Invisible()),
+		"duplicate pk":       NewTableBuilder("t").Column("a", "int").PrimaryKey("a").PrimaryKey("a"),
+		"empty check clause": NewTableBuilder("t").Column("a", "int").Check("c", ""),
+	}
+	for name, b := range cases {
+		if _, err := b.Build(FlavorMySQL57); err == nil {
+			t.Errorf("Case %q: expected error from Build, but err was nil", name)
+		}
+	}
+
+	// Primary key columns become NOT NULL; check constraints work in 8.0.16+
+	table, err := NewTableBuilder("t").Column("a", "int").PrimaryKey("a").Check("positive", "(`a` > 0)").Build(FlavorMySQL80.Dot(16))
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	if table.Columns[0].Nullable {
+		t.Error("Expected primary key column to be NOT NULL")
+	}
+	if len(table.Checks) != 1 || !strings.Contains(table.CreateStatement, "CONSTRAINT `positive` CHECK") {
+		t.Errorf("Unexpected CREATE TABLE:\n%s", table.CreateStatement)
+	}
+}
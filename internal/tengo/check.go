@@ -2,6 +2,8 @@ package tengo
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 )
 
 // Check represents a single check constraint in a table.
@@ -20,3 +22,80 @@ func (cc *Check) Definition(flavor Flavor) string {
 	}
 	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)%s", EscapeIdentifier(cc.Name), cc.Clause, notEnforced)
 }
+
+var reMariaDBCheckName = regexp.MustCompile(`^CONSTRAINT_[0-9]+$`)
+
+// checkNameAutoGenerated returns true if checkName matches the format used by
+// the database server when a check constraint is created without an explicit
+// name. MySQL names these <table>_chk_<N>, while MariaDB uses CONSTRAINT_<N>.
+func checkNameAutoGenerated(tableName, checkName string) bool {
+	if reMariaDBCheckName.MatchString(checkName) {
+		return true
+	}
+	prefix := tableName + "_chk_"
+	if !strings.HasPrefix(checkName, prefix) || len(checkName) == len(prefix) {
+		return false
+	}
+	for _, r := range checkName[len(prefix):] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalCheckNames returns the table's CREATE statement, modified so that
+// any check constraints with MariaDB-style server-generated names instead use
+// MySQL's naming format of <table>_chk_<N>, using the lowest unused values of
+// N. This permits the same definition to be used across flavors without
+// unnecessary differences in check names. The table itself is not modified.
+func (t *Table) CanonicalCheckNames() string {
+	create := t.CreateStatement
+	used := make(map[string]bool, len(t.Checks))
+	for _, cc := range t.Checks {
+		used[strings.ToLower(cc.Name)] = true
+	}
+	var n int
+	for _, cc := range t.Checks {
+		if !reMariaDBCheckName.MatchString(cc.Name) {
+			continue
+		}
+		var canonical string
+		for canonical == "" || used[strings.ToLower(canonical)] {
+			n++
+			canonical = fmt.Sprintf("%s_chk_%d", t.Name, n)
+		}
+		used[strings.ToLower(canonical)] = true
+		before := "CONSTRAINT " + EscapeIdentifier(cc.Name) + " CHECK "
+		after := "CONSTRAINT " + EscapeIdentifier(canonical) + " CHECK "
+		create = strings.Replace(create, before, after, 1)
+	}
+	return create
+}
+
+// checkRenamePairs returns a mapping between checks in from and checks in to
+// which differ only in their server-generated names. Each key is a Check from
+// the from side, with the corresponding value being a Check from the to side.
+// Checks present by name on both sides are never paired.
+func checkRenamePairs(from, to *Table) map[*Check]*Check {
+	fromChecks := from.checksByName()
+	toChecks := to.checksByName()
+	pairs := make(map[*Check]*Check)
+	paired := make(map[*Check]bool)
+	for _, fromCheck := range from.Checks {
+		if _, stillExists := toChecks[fromCheck.Name]; stillExists || !checkNameAutoGenerated(from.Name, fromCheck.Name) {
+			continue
+		}
+		for _, toCheck := range to.Checks {
+			if _, existedBefore := fromChecks[toCheck.Name]; existedBefore || paired[toCheck] || !checkNameAutoGenerated(to.Name, toCheck.Name) {
+				continue
+			}
+			if fromCheck.Clause == toCheck.Clause && fromCheck.Enforced == toCheck.Enforced {
+				pairs[fromCheck] = toCheck
+				paired[toCheck] = true
+				break
+			}
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,62 @@
+package tengo
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var introspectionRetries int32
+
+// SetIntrospectionRetries configures detection of schema changes which occur
+// concurrently with introspection. Since introspection consists of many
+// separate information_schema queries and SHOW CREATE calls, often run in
+// parallel, a schema being modified during introspection can yield internally
+// inconsistent objects. Transactions do not help, since information_schema
+// does not support consistent snapshots. Instead, if n is greater than 0, a
+// checksum of each schema's metadata is computed before and after
+// introspection; if these differ, introspection of that schema is retried up
+// to n times before returning an error. The default of 0 disables this check.
+func SetIntrospectionRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&introspectionRetries, int32(n))
+}
+
+func getIntrospectionRetries() int {
+	return int(atomic.LoadInt32(&introspectionRetries))
+}
+
+// schemaChecksum returns a string summarizing the metadata of the objects in
+// the named schema. Any DDL affecting the schema's tables, columns, indexes,
+// foreign keys, routines, or triggers will change the result. The value is only
+// meaningful for comparing against other values from the same instance.
+func schemaChecksum(ctx context.Context, db *sqlx.DB, schema string) (checksum string, err error) {
+	defer StartTiming("Checksum schema %s", schema)()
+	query := `
+		SELECT CONCAT_WS('/',
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          table_name, table_type, engine, table_collation, create_options, table_comment, create_time))), 0))
+			 FROM   information_schema.tables WHERE table_schema = ?),
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          table_name, column_name, ordinal_position, column_type, is_nullable, column_default,
+			          extra, collation_name, column_comment))), 0))
+			 FROM   information_schema.columns WHERE table_schema = ?),
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          table_name, index_name, seq_in_index, column_name, non_unique, sub_part, index_type))), 0))
+			 FROM   information_schema.statistics WHERE table_schema = ?),
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          table_name, constraint_name, referenced_table_name, update_rule, delete_rule))), 0))
+			 FROM   information_schema.referential_constraints WHERE constraint_schema = ?),
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          routine_name, routine_type, created, last_altered))), 0))
+			 FROM   information_schema.routines WHERE routine_schema = ?),
+			(SELECT CONCAT(COUNT(*), ':', COALESCE(SUM(CRC32(CONCAT_WS(',',
+			          trigger_name, event_object_table, action_order, created))), 0))
+			 FROM   information_schema.triggers WHERE trigger_schema = ?)
+		)`
+	err = db.GetContext(ctx, &checksum, query, schema, schema, schema, schema, schema, schema)
+	return checksum, err
+}
@@ -7,23 +7,29 @@ import (
 
 // Column represents a single column of a table.
 type Column struct {
-	Name               string `json:"name"`
-	TypeInDB           string `json:"type"`
-	Nullable           bool   `json:"nullable,omitempty"`
-	AutoIncrement      bool   `json:"autoIncrement,omitempty"`
-	Default            string `json:"default,omitempty"` // Stored as an expression, i.e. quote-wrapped if string
-	OnUpdate           string `json:"onUpdate,omitempty"`
-	GenerationExpr     string `json:"generationExpression,omitempty"` // Only populated if generated column
-	Virtual            bool   `json:"virtual,omitempty"`
-	CharSet            string `json:"charSet,omitempty"`            // Only populated if textual type
-	Collation          string `json:"collation,omitempty"`          // Only populated if textual type
-	CollationIsDefault bool   `json:"collationIsDefault,omitempty"` // Only populated if textual type; indicates default for CharSet
-	ForceShowCharSet   bool   `json:"forceShowCharSet,omitempty"`   // Always include CharSet in SHOW CREATE; only true in MySQL 8 edge cases
-	ForceShowCollation bool   `json:"forceShowCollation,omitempty"` // Always include Collation in SHOW CREATE; only true in MySQL 8 edge cases
-	Compression        string `json:"compression,omitempty"`        // Only non-empty if using column compression in Percona Server or MariaDB
-	Comment            string `json:"comment,omitempty"`
-	Invisible          bool   `json:"invisible,omitempty"` // True if an invisible column (MariaDB 10.3+, MySQL 8.0.23+)
-	CheckClause        string `json:"check,omitempty"`     // Only non-empty for MariaDB inline check constraint clause
+	Name                     string `json:"name"`
+	TypeInDB                 string `json:"type"`
+	Nullable                 bool   `json:"nullable,omitempty"`
+	AutoIncrement            bool   `json:"autoIncrement,omitempty"`
+	Default                  string `json:"default,omitempty"` // Stored as an expression, i.e. quote-wrapped if string
+	OnUpdate                 string `json:"onUpdate,omitempty"`
+	GenerationExpr           string `json:"generationExpression,omitempty"` // Only populated if generated column
+	Virtual                  bool   `json:"virtual,omitempty"`
+	CharSet                  string `json:"charSet,omitempty"`            // Only populated if textual type
+	Collation                string `json:"collation,omitempty"`          // Only populated if textual type
+	CollationIsDefault       bool   `json:"collationIsDefault,omitempty"` // Only populated if textual type; indicates default for CharSet
+	ForceShowCharSet         bool   `json:"forceShowCharSet,omitempty"`   // Always include CharSet in SHOW CREATE; only true in MySQL 8 edge cases
+	ForceShowCollation       bool   `json:"forceShowCollation,omitempty"` // Always include Collation in SHOW CREATE; only true in MySQL 8 edge cases
+	Compression              string `json:"compression,omitempty"`        // Only non-empty if using column compression in Percona Server or MariaDB
+	Comment                  string `json:"comment,omitempty"`
+	Invisible                bool   `json:"invisible,omitempty"`                // True if an invisible column (MariaDB 10.3+, MySQL 8.0.23+)
+	CheckClause              string `json:"check,omitempty"`                    // Only non-empty for MariaDB inline check constraint clause
+	AutoRandom               string `json:"autoRandom,omitempty"`               // Only non-empty for TiDB AUTO_RANDOM columns, e.g. "AUTO_RANDOM(5)"
+	VersioningRow            string `json:"versioningRow,omitempty"`            // "START" or "END" for explicit row period columns of MariaDB system-versioned tables
+	WithoutVersioning        bool   `json:"withoutVersioning,omitempty"`        // True if excluded from MariaDB system versioning
+	NotSecondary             bool   `json:"notSecondary,omitempty"`             // True if excluded from MySQL secondary engine (NOT SECONDARY)
+	SecondaryEngineAttribute string `json:"secondaryEngineAttribute,omitempty"` // MySQL 8.0.21+ only; any necessary escaping is already present
+	SRID                     string `json:"srid,omitempty"`                     // Only non-empty for MySQL 8 spatial columns with an explicit SRID
 }
 
 // Definition returns this column's definition clause, for use as part of a DDL
@@ -31,7 +37,7 @@ type Column struct {
 // SET clause to be omitted if the table and column have the same *collation*
 // (mirroring the specific display logic used by SHOW CREATE TABLE)
 func (c *Column) Definition(flavor Flavor, table *Table) string {
-	var compression, charSet, collation, generated, nullability, visibility, autoIncrement, defaultValue, onUpdate, colFormat, comment, check string
+	var compression, charSet, collation, generated, nullability, srid, visibility, autoIncrement, autoRandom, defaultValue, versioning, onUpdate, notSecondary, colFormat, comment, secondaryAttr, check string
 	if c.Compression != "" && flavor.IsMariaDB() {
 		// MariaDB puts compression modifiers in a different place than Percona Server
 		compression = fmt.Sprintf(" /*!100301 %s*/", c.Compression)
@@ -66,12 +72,19 @@ func (c *Column) Definition(flavor Flavor, table *Table) string {
 		}
 		generated = fmt.Sprintf(" GENERATED ALWAYS AS (%s) %s", c.GenerationExpr, genKind)
 	}
-	if !c.Nullable {
+	if c.VersioningRow != "" {
+		// MariaDB row period columns are implicitly NOT NULL, and SHOW CREATE TABLE
+		// omits nullability for them
+		generated = " GENERATED ALWAYS AS ROW " + c.VersioningRow
+	} else if !c.Nullable {
 		nullability = " NOT NULL"
 	} else if strings.HasPrefix(c.TypeInDB, "timestamp") {
 		// Oddly the timestamp type always displays nullability
 		nullability = " NULL"
 	}
+	if c.SRID != "" {
+		srid = " /*!80003 SRID " + c.SRID + " */"
+	}
 	if c.Invisible {
 		if flavor.IsMariaDB() {
 			visibility = " INVISIBLE"
@@ -82,28 +95,40 @@ func (c *Column) Definition(flavor Flavor, table *Table) string {
 	if c.AutoIncrement {
 		autoIncrement = " AUTO_INCREMENT"
 	}
+	if c.AutoRandom != "" {
+		autoRandom = fmt.Sprintf(" /*T![auto_rand] %s */", c.AutoRandom)
+	}
 	if c.Default != "" {
 		defaultValue = fmt.Sprintf(" DEFAULT %s", c.Default)
 	}
+	if c.WithoutVersioning {
+		versioning = " WITHOUT SYSTEM VERSIONING"
+	}
 	if c.OnUpdate != "" {
 		onUpdate = fmt.Sprintf(" ON UPDATE %s", c.OnUpdate)
 	}
+	if c.NotSecondary {
+		notSecondary = " NOT SECONDARY"
+	}
 	if c.Compression != "" && flavor.HasVariant(VariantPercona) {
 		colFormat = fmt.Sprintf(" /*!50633 COLUMN_FORMAT %s */", c.Compression)
 	}
 	if c.Comment != "" {
 		comment = fmt.Sprintf(" COMMENT '%s'", EscapeValueForCreateTable(c.Comment))
 	}
+	if c.SecondaryEngineAttribute != "" {
+		secondaryAttr = fmt.Sprintf(" /*!80021 SECONDARY_ENGINE_ATTRIBUTE='%s' */", c.SecondaryEngineAttribute)
+	}
 	if c.CheckClause != "" {
 		check = fmt.Sprintf(" CHECK (%s)", c.CheckClause)
 	}
 	clauses := []string{
-		EscapeIdentifier(c.Name), " ", c.TypeInDB, compression, charSet, collation, generated, nullability,
+		EscapeIdentifier(c.Name), " ", c.TypeInDB, compression, charSet, collation, generated, nullability, srid,
 	}
 	if flavor.IsMariaDB() {
-		clauses = append(clauses, visibility, autoIncrement, defaultValue, onUpdate, colFormat, comment, check)
+		clauses = append(clauses, visibility, autoIncrement, defaultValue, versioning, onUpdate, colFormat, comment, check)
 	} else {
-		clauses = append(clauses, autoIncrement, defaultValue, onUpdate, visibility, colFormat, comment)
+		clauses = append(clauses, autoIncrement, autoRandom, defaultValue, onUpdate, visibility, notSecondary, colFormat, comment, secondaryAttr)
 	}
 	return strings.Join(clauses, "")
 }
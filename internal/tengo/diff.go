@@ -3,8 +3,6 @@ package tengo
 import (
 	"fmt"
 	"strings"
-
-	"github.com/pmezard/go-difflib/difflib"
 )
 
 // DiffType enumerates possible ways that two objects differ
@@ -79,7 +77,8 @@ type StatementModifiers struct {
 	StrictCheckOrder       bool             // If true, maintain check constraint order even though it never has a functional difference (only affects MariaDB)
 	StrictForeignKeyNaming bool             // If true, maintain foreign key definition even if differences are cosmetic (name change, RESTRICT vs NO ACTION, etc)
 	StrictColumnDefinition bool             // If true, maintain column properties that are purely cosmetic (only affects MySQL 8)
-	CompareMetadata        bool             // If true, compare creation-time sql_mode and db collation for funcs, procs (and eventually events, triggers)
+	LaxCheckNaming         bool             // If true, ignore differences in server-generated check constraint names, comparing these checks by clause only
+	CompareMetadata        bool             // If true, compare creation-time sql_mode and db collation for funcs, procs, triggers, and creation-time charset/collation for views, triggers (and eventually events)
 	VirtualColValidation   bool             // If true, add WITH VALIDATION clause for ALTER TABLE affecting virtual columns
 	SkipPreDropAlters      bool             // If true, skip ALTERs that were only generated to make DROP TABLE faster
 	Flavor                 Flavor           // Adjust generated DDL to match vendor/version. Zero value is FlavorUnknown which makes no adjustments.
@@ -94,6 +93,14 @@ type SchemaDiff struct {
 	ToSchema     *Schema
 	TableDiffs   []*TableDiff   // a set of statements that, if run, would turn tables in FromSchema into ToSchema
 	RoutineDiffs []*RoutineDiff // " but for funcs and procs
+	ViewDiffs    []*ViewDiff    // " but for views
+	TriggerDiffs []*TriggerDiff // " but for triggers
+
+	// RoutineOrderErr is non-nil if new routines reference each other in a
+	// cycle, in which case RoutineDiffs cannot satisfy every reference. This is
+	// not fatal, since routine references are resolved at call time. See
+	// OrderRoutineDiffs.
+	RoutineOrderErr error
 }
 
 // NewSchemaDiff computes the set of differences between two database schemas.
@@ -108,7 +115,9 @@ func NewSchemaDiff(from, to *Schema) *SchemaDiff {
 	}
 
 	result.TableDiffs = compareTables(from, to)
-	result.RoutineDiffs = compareRoutines(from, to)
+	result.RoutineDiffs, result.RoutineOrderErr = compareRoutines(from, to)
+	result.ViewDiffs = compareViews(from, to)
+	result.TriggerDiffs = compareTriggers(from, to)
 	return result
 }
 
@@ -148,7 +157,7 @@ func compareTables(from, to *Schema) []*TableDiff {
 	return tableDiffs
 }
 
-func compareRoutines(from, to *Schema) (routineDiffs []*RoutineDiff) {
+func compareRoutines(from, to *Schema) (routineDiffs []*RoutineDiff, err error) {
 	compare := func(fromByName map[string]*Routine, toByName map[string]*Routine) {
 		for name, fromRoutine := range fromByName {
 			toRoutine, stillExists := toByName[name]
@@ -179,7 +188,79 @@ func compareRoutines(from, to *Schema) (routineDiffs []*RoutineDiff) {
 	}
 	compare(from.ProceduresByName(), to.ProceduresByName())
 	compare(from.FunctionsByName(), to.FunctionsByName())
-	return
+
+	// Order CREATEs so that routines referenced by other new routines come first.
+	// A cycle still yields a usable ordering, but the error is returned so that
+	// callers can report it.
+	return OrderRoutineDiffs(routineDiffs)
+}
+
+func compareViews(from, to *Schema) (viewDiffs []*ViewDiff) {
+	fromByName := from.ViewsByName()
+	toByName := to.ViewsByName()
+	for name, fromView := range fromByName {
+		toView, stillExists := toByName[name]
+		if !stillExists {
+			viewDiffs = append(viewDiffs, &ViewDiff{From: fromView})
+		} else if !fromView.Equals(toView) {
+			// As with routines, flag changes which only affect creation-time metadata
+			// (character_set_client, collation_connection), since these require
+			// StatementModifiers to execute
+			metadataOnly := fromView.CreateStatement == toView.CreateStatement
+			viewDiffs = append(viewDiffs, &ViewDiff{From: fromView, To: toView, ForMetadata: metadataOnly})
+		}
+	}
+	for name, toView := range toByName {
+		if _, alreadyExists := fromByName[name]; !alreadyExists {
+			viewDiffs = append(viewDiffs, &ViewDiff{To: toView})
+		}
+	}
+	return orderViewDiffs(viewDiffs)
+}
+
+func compareTriggers(from, to *Schema) (triggerDiffs []*TriggerDiff) {
+	fromByName := from.TriggersByName()
+	toByName := to.TriggersByName()
+	creating := make(map[string]*TriggerDiff)
+	for name, fromTrigger := range fromByName {
+		toTrigger, stillExists := toByName[name]
+		if !stillExists {
+			triggerDiffs = append(triggerDiffs, &TriggerDiff{From: fromTrigger})
+		} else if !fromTrigger.Equals(toTrigger) {
+			// As with routines, flag changes which only affect creation-time metadata,
+			// since these require StatementModifiers to execute
+			metadataOnly := fromTrigger.CreateStatement == toTrigger.CreateStatement
+			create := &TriggerDiff{To: toTrigger, ForReplace: true, ForMetadata: metadataOnly}
+			triggerDiffs = append(triggerDiffs,
+				&TriggerDiff{From: fromTrigger, ForReplace: true, ForMetadata: metadataOnly},
+				create,
+			)
+			creating[name] = create
+		}
+	}
+	for name, toTrigger := range toByName {
+		if _, alreadyExists := fromByName[name]; !alreadyExists {
+			create := &TriggerDiff{To: toTrigger}
+			triggerDiffs = append(triggerDiffs, create)
+			creating[name] = create
+		}
+	}
+
+	// A trigger created without a FOLLOWS clause is placed after all other
+	// triggers for the same table, timing, and event. If it is supposed to be
+	// first, but the trigger after it already exists and isn't being re-created,
+	// it must explicitly precede that trigger instead.
+	for _, create := range creating {
+		if create.To.Follows != "" {
+			continue
+		}
+		for _, other := range to.Triggers {
+			if other.Follows == create.To.Name && other.sameGroup(create.To) && creating[other.Name] == nil {
+				create.Precedes = other.Name
+			}
+		}
+	}
+	return orderTriggerDiffs(triggerDiffs)
 }
 
 // DatabaseDiff returns an object representing database-level DDL (CREATE
@@ -203,13 +284,41 @@ func (sd *SchemaDiff) ObjectDiffs() []ObjectDiff {
 	if dd != nil {
 		result = append(result, dd)
 	}
+	// Views are dropped before any table DDL, since a view and table cannot share
+	// a name. All other view DDL comes last, since views may refer to tables and
+	// functions.
+	var viewDrops, otherViewDiffs []ObjectDiff
+	for _, vd := range sd.ViewDiffs {
+		if vd.DiffType() == DiffTypeDrop {
+			viewDrops = append(viewDrops, vd)
+		} else {
+			otherViewDiffs = append(otherViewDiffs, vd)
+		}
+	}
+	result = append(result, viewDrops...)
+
+	// Triggers being removed entirely are dropped before any table DDL, since
+	// dropping a table implicitly drops its triggers. Trigger creations, and
+	// drops which are part of a replacement, come after table DDL, since the
+	// trigger's table may be new.
+	var triggerDrops, otherTriggerDiffs []ObjectDiff
+	for _, td := range sd.TriggerDiffs {
+		if td.DiffType() == DiffTypeDrop && !td.ForReplace {
+			triggerDrops = append(triggerDrops, td)
+		} else {
+			otherTriggerDiffs = append(otherTriggerDiffs, td)
+		}
+	}
+	result = append(result, triggerDrops...)
+
 	for _, td := range sd.TableDiffs {
 		result = append(result, td)
 	}
 	for _, rd := range sd.RoutineDiffs {
 		result = append(result, rd)
 	}
-	return result
+	result = append(result, otherTriggerDiffs...)
+	return append(result, otherViewDiffs...)
 }
 
 // String returns the set of differences between two schemas as a single string.
@@ -456,13 +565,13 @@ func (td *TableDiff) SplitAddForeignKeys() (*TableDiff, *TableDiff) {
 
 // SplitConflicts looks through a TableDiff's alterClauses and pulls out any
 // clauses that need to be placed into a separate TableDiff in order to yield
-// legal or error-free DDL. Currently this only handles attempts to add multiple
-// FULLTEXT indexes in a single ALTER, but may handle additional cases in the
-// future.
+// legal or error-free DDL. Currently this handles attempts to add multiple
+// FULLTEXT indexes in a single ALTER, as well as changes to TiDB's TiFlash
+// replica count, but may handle additional cases in the future.
 // This method returns a slice of TableDiffs. The first element will be
-// equivalent to the receiver (td) with any conflicting clauses removed;
-// subsequent slice elements, if any, will be separate TableDiffs each
-// consisting of individual conflicting clauses.
+// equivalent to the receiver (td) with any conflicting clauses removed, unless
+// no clauses remain; subsequent slice elements, if any, will be separate
+// TableDiffs each consisting of individual conflicting clauses.
 // This method does not interact with AddForeignKey clauses; see dedicated
 // method SplitAddForeignKeys for that logic.
 func (td *TableDiff) SplitConflicts() (result []*TableDiff) {
@@ -482,17 +591,22 @@ func (td *TableDiff) SplitConflicts() (result []*TableDiff) {
 				continue
 			}
 			seenAddFulltext = true
+		} else if _, ok := clause.(ChangeTiFlashReplica); ok {
+			separateClauses = append(separateClauses, clause)
+			continue
 		}
 		keepClauses = append(keepClauses, clause)
 	}
 
-	result = append(result, &TableDiff{
-		Type:         DiffTypeAlter,
-		From:         td.From,
-		To:           td.To,
-		alterClauses: keepClauses,
-		supported:    true,
-	})
+	if len(keepClauses) > 0 {
+		result = append(result, &TableDiff{
+			Type:         DiffTypeAlter,
+			From:         td.From,
+			To:           td.To,
+			alterClauses: keepClauses,
+			supported:    true,
+		})
+	}
 	for n := range separateClauses {
 		result = append(result, &TableDiff{
 			Type:         DiffTypeAlter,
@@ -568,22 +682,26 @@ func (td *TableDiff) Clauses(mods StatementModifiers) (string, error) {
 func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 	if !td.supported {
 		if td.To.UnsupportedDDL {
+			expected := td.To.GeneratedCreateStatement(mods.Flavor)
 			return "", &UnsupportedDiffError{
 				ObjectKey:      td.ObjectKey(),
 				Reason:         "The desired state (\"to\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE.",
-				ExpectedCreate: td.To.GeneratedCreateStatement(mods.Flavor),
+				ExpectedCreate: expected,
 				ExpectedDesc:   "desired state expected CREATE",
 				ActualCreate:   td.To.CreateStatement,
 				ActualDesc:     "desired state actual SHOW CREATE",
+				Hint:           guessUnsupportedReason(expected, td.To.CreateStatement),
 			}
 		} else if td.From.UnsupportedDDL {
+			expected := td.From.GeneratedCreateStatement(mods.Flavor)
 			return "", &UnsupportedDiffError{
 				ObjectKey:      td.ObjectKey(),
 				Reason:         "The original state (\"from\" side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE.",
-				ExpectedCreate: td.From.GeneratedCreateStatement(mods.Flavor),
+				ExpectedCreate: expected,
 				ExpectedDesc:   "original state expected CREATE",
 				ActualCreate:   td.From.CreateStatement,
 				ActualDesc:     "original state actual SHOW CREATE",
+				Hint:           guessUnsupportedReason(expected, td.From.CreateStatement),
 			}
 		} else {
 			return "", &UnsupportedDiffError{
@@ -603,6 +721,11 @@ func (td *TableDiff) alterStatement(mods StatementModifiers) (string, error) {
 		mods.StrictIndexOrder = true
 	}
 
+	// SingleStore does not support ALGORITHM or LOCK clauses in ALTER TABLE
+	if mods.Flavor.IsSingleStore() {
+		mods.LockClause, mods.AlgorithmClause = "", ""
+	}
+
 	clauseStrings := make([]string, 0, len(td.alterClauses))
 	var partitionClauseString string
 	var err error
@@ -763,6 +886,176 @@ func (rd *RoutineDiff) IsCompoundStatement() bool {
 	return rd.To != nil && ParseStatementInString(rd.To.CreateStatement).Compound
 }
 
+///// ViewDiff /////////////////////////////////////////////////////////////////
+
+// ViewDiff represents a difference between two views.
+type ViewDiff struct {
+	From        *View
+	To          *View
+	ForMetadata bool // if true, view is being replaced only to update creation-time metadata
+}
+
+// ObjectKey returns a value representing the type and name of the view being
+// diff'ed. The name will be the From side view, unless this is a Create, in
+// which case the To side view name is used.
+func (vd *ViewDiff) ObjectKey() ObjectKey {
+	if vd != nil && vd.From != nil {
+		return vd.From.ObjectKey()
+	} else if vd != nil && vd.To != nil {
+		return vd.To.ObjectKey()
+	}
+	return ObjectKey{}
+}
+
+// DiffType returns the type of diff operation.
+func (vd *ViewDiff) DiffType() DiffType {
+	if vd == nil || (vd.To == nil && vd.From == nil) {
+		return DiffTypeNone
+	} else if vd.To == nil {
+		return DiffTypeDrop
+	} else if vd.From == nil {
+		return DiffTypeCreate
+	}
+	return DiffTypeAlter
+}
+
+// Statement returns the full DDL statement corresponding to the ViewDiff. A
+// blank string may be returned if the mods indicate the statement should be
+// skipped. If the mods indicate the statement should be disallowed, it will
+// still be returned as-is, but the error will be non-nil. Be sure not to
+// ignore the error value of this method.
+func (vd *ViewDiff) Statement(mods StatementModifiers) (string, error) {
+	if vd == nil {
+		return "", nil
+	}
+	switch vd.DiffType() {
+	case DiffTypeCreate:
+		return vd.To.CreateStatement, nil
+	case DiffTypeAlter:
+		// Replacing a view only to change its creation-time metadata is opt-in, for
+		// the same reasons as with routines
+		if vd.ForMetadata && !mods.CompareMetadata {
+			return "", nil
+		}
+		var comment string
+		if vd.ForMetadata {
+			comment = fmt.Sprintf("# Replacing %s to update metadata\n", vd.ObjectKey())
+		}
+		// CREATE OR REPLACE is used instead of ALTER VIEW, since the two are
+		// equivalent, but the former also permits reusing the canonical CREATE
+		return comment + strings.Replace(vd.To.CreateStatement, "CREATE ", "CREATE OR REPLACE ", 1), nil
+	case DiffTypeDrop:
+		stmt := vd.From.DropStatement()
+		var err error
+		if !mods.AllowUnsafe {
+			err = &ForbiddenDiffError{
+				Reason:    "DROP VIEW not permitted",
+				Statement: stmt,
+			}
+		}
+		return stmt, err
+	default: // DiffTypeRename not supported yet
+		return "", fmt.Errorf("Unsupported diff type %d", vd.DiffType())
+	}
+}
+
+///// TriggerDiff //////////////////////////////////////////////////////////////
+
+// TriggerDiff represents a difference between two triggers. Changes to an
+// existing trigger are represented by a pair of TriggerDiffs: a DROP and a
+// CREATE, both with ForReplace set.
+type TriggerDiff struct {
+	From        *Trigger
+	To          *Trigger
+	ForReplace  bool   // if true, trigger is being dropped/re-created to replace
+	ForMetadata bool   // if true, trigger is being replaced only to update creation-time metadata
+	Precedes    string // if non-blank, name of an existing trigger that a created trigger must precede
+}
+
+// ObjectKey returns a value representing the type and name of the trigger
+// being diff'ed. The name will be the From side trigger, unless this is a
+// Create, in which case the To side trigger name is used.
+func (td *TriggerDiff) ObjectKey() ObjectKey {
+	if td != nil && td.From != nil {
+		return td.From.ObjectKey()
+	} else if td != nil && td.To != nil {
+		return td.To.ObjectKey()
+	}
+	return ObjectKey{}
+}
+
+// DiffType returns the type of diff operation.
+func (td *TriggerDiff) DiffType() DiffType {
+	if td == nil || (td.To == nil && td.From == nil) {
+		return DiffTypeNone
+	} else if td.To == nil {
+		return DiffTypeDrop
+	} else if td.From == nil {
+		return DiffTypeCreate
+	}
+	return DiffTypeAlter
+}
+
+// Statement returns the full DDL statement corresponding to the TriggerDiff. A
+// blank string may be returned if the mods indicate the statement should be
+// skipped. If the mods indicate the statement should be disallowed, it will
+// still be returned as-is, but the error will be non-nil. Be sure not to
+// ignore the error value of this method.
+func (td *TriggerDiff) Statement(mods StatementModifiers) (string, error) {
+	if td == nil {
+		return "", nil
+	}
+
+	// Replacing a trigger only to change its creation-time metadata is opt-in,
+	// for the same reasons as with routines
+	if td.ForMetadata && !mods.CompareMetadata {
+		return "", nil
+	}
+
+	var comment string
+	mariaReplace := td.ForReplace && mods.Flavor.IsMariaDB()
+	switch td.DiffType() {
+	case DiffTypeCreate:
+		if mariaReplace && td.ForMetadata {
+			comment = fmt.Sprintf("# Replacing %s to update metadata\n", td.ObjectKey())
+		}
+		stmt := td.To.CreateStatement
+		if td.Precedes != "" {
+			stmt = strings.Replace(stmt, " FOR EACH ROW ", " FOR EACH ROW PRECEDES "+EscapeIdentifier(td.Precedes)+" ", 1)
+		}
+		if mariaReplace {
+			stmt = strings.Replace(stmt, "CREATE ", "CREATE OR REPLACE ", 1)
+		}
+		return comment + stmt, nil
+	case DiffTypeDrop:
+		// MariaDB can use CREATE OR REPLACE, so omit any replacement-motivated
+		// DROP statements; this also avoids a period where the trigger is missing
+		if mariaReplace {
+			return "", nil
+		}
+		if td.ForMetadata {
+			comment = fmt.Sprintf("# Dropping and re-creating %s to update metadata\n", td.ObjectKey())
+		}
+		stmt := comment + td.From.DropStatement()
+		var err error
+		if !mods.AllowUnsafe {
+			err = &ForbiddenDiffError{
+				Reason:    "DROP TRIGGER not permitted",
+				Statement: stmt,
+			}
+		}
+		return stmt, err
+	default: // DiffTypeAlter and DiffTypeRename not supported
+		return "", fmt.Errorf("Unsupported diff type %d", td.DiffType())
+	}
+}
+
+// IsCompoundStatement returns true if the diff is a compound CREATE statement,
+// requiring special delimiter handling.
+func (td *TriggerDiff) IsCompoundStatement() bool {
+	return td.To != nil && ParseStatementInString(td.To.CreateStatement).Compound
+}
+
 ///// Errors ///////////////////////////////////////////////////////////////////
 
 // ForbiddenDiffError can be returned by ObjectDiff.Statement when the supplied
@@ -794,6 +1087,7 @@ type UnsupportedDiffError struct {
 	ExpectedDesc   string
 	ActualCreate   string
 	ActualDesc     string
+	Hint           string // Best-guess cause of the mismatch, if known
 }
 
 // Error satisfies the builtin error interface.
@@ -804,16 +1098,9 @@ func (e *UnsupportedDiffError) Error() string {
 // ExtendedError returns a string with more information about why the diff is
 // not supported.
 func (e *UnsupportedDiffError) ExtendedError() string {
-	diff := difflib.UnifiedDiff{
-		A:        difflib.SplitLines(e.ExpectedCreate),
-		B:        difflib.SplitLines(e.ActualCreate),
-		FromFile: e.ExpectedDesc,
-		ToFile:   e.ActualDesc,
-		Context:  0,
-	}
-	diffText, err := difflib.GetUnifiedDiffString(diff)
-	if err != nil {
-		return err.Error()
+	diffText := unsupportedDiff(e.ExpectedCreate, e.ActualCreate, e.ExpectedDesc, e.ActualDesc)
+	if e.Hint != "" {
+		diffText = "Likely cause: " + e.Hint + "\n" + diffText
 	}
 	if e.Reason != "" {
 		diffText = e.Reason + "\n" + diffText
@@ -627,6 +627,192 @@ func TestSchemaDiffRoutines(t *testing.T) {
 	}
 }
 
+func TestSchemaDiffViews(t *testing.T) {
+	s1t1 := anotherTable()
+	s2t1 := anotherTable()
+	s1 := aSchema("s1", &s1t1)
+	s2 := aSchema("s2", &s2t1)
+	s2v1 := aView("v1", "select `actor`.`actor_id` AS `actor_id` from `actor`")
+	s2.Views = []*View{&s2v1}
+
+	// Test create
+	sd := NewSchemaDiff(&s1, &s2)
+	if len(sd.ViewDiffs) != 1 {
+		t.Fatalf("Incorrect number of view diffs: expected 1, found %d", len(sd.ViewDiffs))
+	}
+	vd := sd.ViewDiffs[0]
+	if vd.DiffType() != DiffTypeCreate || vd.To != &s2v1 || vd.ObjectKey() != s2v1.ObjectKey() {
+		t.Fatalf("Unexpected diff returned: %+v", vd)
+	}
+	if stmt, err := vd.Statement(StatementModifiers{}); err != nil || stmt != s2v1.CreateStatement {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+
+	// Test drop, which should be ordered prior to any table DDL
+	s1.Tables = nil
+	sd = NewSchemaDiff(&s2, &s1)
+	objDiffs := sd.ObjectDiffs()
+	if len(objDiffs) != 2 || objDiffs[0].ObjectKey() != s2v1.ObjectKey() || objDiffs[0].DiffType() != DiffTypeDrop {
+		t.Fatalf("Unexpected result from ObjectDiffs: %+v", objDiffs)
+	}
+	if stmt, err := objDiffs[0].Statement(StatementModifiers{}); stmt != "DROP VIEW `v1`" || !IsForbiddenDiff(err) {
+		t.Errorf("Modifier AllowUnsafe=false not working; expected forbidden diff error for %s, instead err=%v", stmt, err)
+	}
+	if stmt, err := objDiffs[0].Statement(StatementModifiers{AllowUnsafe: true}); stmt == "" || err != nil {
+		t.Errorf("Modifier AllowUnsafe=true not working; error (%v) returned for %s", err, stmt)
+	}
+
+	// Test create, which should be ordered after all table DDL
+	sd = NewSchemaDiff(&s1, &s2)
+	objDiffs = sd.ObjectDiffs()
+	if len(objDiffs) != 2 || objDiffs[1].ObjectKey() != s2v1.ObjectKey() || objDiffs[1].DiffType() != DiffTypeCreate {
+		t.Fatalf("Unexpected result from ObjectDiffs: %+v", objDiffs)
+	}
+
+	// Test alter, which uses CREATE OR REPLACE
+	s1 = aSchema("s1", &s1t1)
+	s1v1 := aView("v1", "select `actor`.`first_name` AS `first_name` from `actor`")
+	s1.Views = []*View{&s1v1}
+	sd = NewSchemaDiff(&s1, &s2)
+	if len(sd.ViewDiffs) != 1 || sd.ViewDiffs[0].DiffType() != DiffTypeAlter {
+		t.Fatalf("Unexpected view diffs: %+v", sd.ViewDiffs)
+	}
+	expected := strings.Replace(s2v1.CreateStatement, "CREATE ", "CREATE OR REPLACE ", 1)
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{}); stmt != expected || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+
+	// Test creation-time metadata change, which requires CompareMetadata
+	s1v1 = aView("v1", s2v1.Body)
+	s1v1.CharSetClient = "latin1"
+	s1v1.CollationConnection = "latin1_swedish_ci"
+	sd = NewSchemaDiff(&s1, &s2)
+	if len(sd.ViewDiffs) != 1 || !sd.ViewDiffs[0].ForMetadata {
+		t.Fatalf("Unexpected view diffs: %+v", sd.ViewDiffs)
+	}
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{}); stmt != "" || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+	if stmt, err := sd.ViewDiffs[0].Statement(StatementModifiers{CompareMetadata: true}); !strings.HasPrefix(stmt, "# ") || !strings.Contains(stmt, "CREATE OR REPLACE") || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+
+	// No diff when identical
+	s1v1 = aView("v1", s2v1.Body)
+	if sd = NewSchemaDiff(&s1, &s2); len(sd.ViewDiffs) != 0 {
+		t.Errorf("Expected no view diffs, instead found %+v", sd.ViewDiffs)
+	}
+}
+
+func TestSchemaDiffTriggers(t *testing.T) {
+	s1t1 := anotherTable()
+	s2t1 := anotherTable()
+	s1 := aSchema("s1", &s1t1)
+	s2 := aSchema("s2", &s2t1)
+	s2tr1 := aTrigger("tr1", "actor", "", "SET NEW.last_update = NOW()")
+	s2tr2 := aTrigger("tr2", "actor", "tr1", "SET NEW.first_name = UPPER(NEW.first_name)")
+	s2.Triggers = []*Trigger{&s2tr1, &s2tr2}
+
+	// Test create, which should be ordered after all table DDL, and in order of
+	// FOLLOWS dependencies
+	s1.Tables = nil
+	sd := NewSchemaDiff(&s1, &s2)
+	objDiffs := sd.ObjectDiffs()
+	if len(objDiffs) != 3 || objDiffs[1].ObjectKey() != s2tr1.ObjectKey() || objDiffs[2].ObjectKey() != s2tr2.ObjectKey() {
+		t.Fatalf("Unexpected result from ObjectDiffs: %+v", objDiffs)
+	}
+	for _, od := range objDiffs[1:] {
+		if stmt, err := od.Statement(StatementModifiers{}); err != nil || stmt != od.(*TriggerDiff).To.CreateStatement {
+			t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+		}
+	}
+
+	// Test drop, which should be ordered prior to any table DDL
+	sd = NewSchemaDiff(&s2, &s1)
+	objDiffs = sd.ObjectDiffs()
+	if len(objDiffs) != 3 || objDiffs[0].DiffType() != DiffTypeDrop || objDiffs[0].ObjectKey() != s2tr1.ObjectKey() || objDiffs[2].ObjectKey() != s2t1.ObjectKey() {
+		t.Fatalf("Unexpected result from ObjectDiffs: %+v", objDiffs)
+	}
+	if stmt, err := objDiffs[0].Statement(StatementModifiers{}); stmt != "DROP TRIGGER `tr1`" || !IsForbiddenDiff(err) {
+		t.Errorf("Modifier AllowUnsafe=false not working; expected forbidden diff error for %s, instead err=%v", stmt, err)
+	}
+	if stmt, err := objDiffs[0].Statement(StatementModifiers{AllowUnsafe: true}); stmt == "" || err != nil {
+		t.Errorf("Modifier AllowUnsafe=true not working; error (%v) returned for %s", err, stmt)
+	}
+
+	// Test replacing the first trigger, while the one following it is unchanged:
+	// the re-created trigger must explicitly precede the existing one
+	s1 = aSchema("s1", &s1t1)
+	s1tr1 := aTrigger("tr1", "actor", "", "SET NEW.last_update = '2000-01-01'")
+	s1tr2 := s2tr2
+	s1.Triggers = []*Trigger{&s1tr1, &s1tr2}
+	sd = NewSchemaDiff(&s1, &s2)
+	if len(sd.TriggerDiffs) != 2 || sd.TriggerDiffs[1].DiffType() != DiffTypeCreate || sd.TriggerDiffs[1].Precedes != "tr2" {
+		t.Fatalf("Unexpected trigger diffs: %+v", sd.TriggerDiffs)
+	}
+	expected := "CREATE DEFINER=`root`@`%` TRIGGER `tr1` BEFORE INSERT ON `actor` FOR EACH ROW PRECEDES `tr2` SET NEW.last_update = NOW()"
+	if stmt, err := sd.TriggerDiffs[1].Statement(StatementModifiers{}); stmt != expected || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+
+	// Test swapping the order of two triggers: both are re-created, so no
+	// PRECEDES clause is needed, and the new first trigger is created first
+	s1tr1 = aTrigger("tr1", "actor", "tr2", s2tr1.Body)
+	s1tr2 = aTrigger("tr2", "actor", "", s2tr2.Body)
+	s1.Triggers = []*Trigger{&s1tr2, &s1tr1}
+	sd = NewSchemaDiff(&s1, &s2)
+	expectedOrder := []string{"DROP tr1", "CREATE tr1", "DROP tr2", "CREATE tr2"}
+	if len(sd.TriggerDiffs) != len(expectedOrder) {
+		t.Fatalf("Unexpected trigger diffs: %+v", sd.TriggerDiffs)
+	}
+	for n, td := range sd.TriggerDiffs {
+		if actual := td.DiffType().String() + " " + td.ObjectKey().Name; actual != expectedOrder[n] || td.Precedes != "" {
+			t.Errorf("Expected trigger diff[%d] to be %s, instead found %s (precedes=%q)", n, expectedOrder[n], actual, td.Precedes)
+		}
+	}
+
+	// Test replacement, which uses CREATE OR REPLACE in MariaDB
+	s1tr1 = aTrigger("tr1", "actor", "", "SET NEW.last_update = '2000-01-01'")
+	s2tr1 = aTrigger("tr1", "actor", "", "SET NEW.last_update = NOW()")
+	s1.Triggers = []*Trigger{&s1tr1}
+	s2.Triggers = []*Trigger{&s2tr1}
+	sd = NewSchemaDiff(&s1, &s2)
+	if len(sd.TriggerDiffs) != 2 || sd.TriggerDiffs[0].DiffType() != DiffTypeDrop || sd.TriggerDiffs[1].DiffType() != DiffTypeCreate {
+		t.Fatalf("Unexpected trigger diffs: %+v", sd.TriggerDiffs)
+	}
+	mods := StatementModifiers{AllowUnsafe: true, Flavor: ParseFlavor("mariadb:10.11")}
+	if stmt, err := sd.TriggerDiffs[0].Statement(mods); stmt != "" || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+	expected = strings.Replace(s2tr1.CreateStatement, "CREATE ", "CREATE OR REPLACE ", 1)
+	if stmt, err := sd.TriggerDiffs[1].Statement(mods); stmt != expected || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+	mods.Flavor = ParseFlavor("mysql:8.0")
+	if stmt, err := sd.TriggerDiffs[0].Statement(mods); stmt != "DROP TRIGGER `tr1`" || err != nil {
+		t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+	}
+
+	// Test creation-time metadata change, which requires CompareMetadata
+	s1tr1 = aTrigger("tr1", "actor", "", s2tr1.Body)
+	s1tr1.SQLMode = ""
+	sd = NewSchemaDiff(&s1, &s2)
+	if len(sd.TriggerDiffs) != 2 || !sd.TriggerDiffs[0].ForMetadata || !sd.TriggerDiffs[1].ForMetadata {
+		t.Fatalf("Unexpected trigger diffs: %+v", sd.TriggerDiffs)
+	}
+	for _, td := range sd.TriggerDiffs {
+		if stmt, err := td.Statement(StatementModifiers{}); stmt != "" || err != nil {
+			t.Errorf("Unexpected return value from Statement(): %s / %v", stmt, err)
+		}
+	}
+
+	// No diff when identical
+	s1tr1 = aTrigger("tr1", "actor", "", s2tr1.Body)
+	if sd = NewSchemaDiff(&s1, &s2); len(sd.TriggerDiffs) != 0 {
+		t.Errorf("Expected no trigger diffs, instead found %+v", sd.TriggerDiffs)
+	}
+}
+
 func TestSchemaDiffFilteredTableDiffs(t *testing.T) {
 	s1t1 := anotherTable()
 	s1t2 := aTable(1)
@@ -702,6 +888,7 @@ func TestTableDiffUnsupportedAlter(t *testing.T) {
 		// unsupported diff triggered the issue.
 		extended := err.(*UnsupportedDiffError).ExtendedError()
 		expected := fmt.Sprintf(`The %s (%q side of diff) contains unexpected or unsupported clauses in SHOW CREATE TABLE.
+Likely cause: unsupported partitioning clause
 --- %s expected CREATE
 +++ %s actual SHOW CREATE
 @@ -8,0 +9,2 @@
@@ -17,6 +17,8 @@ const (
 	VendorUnknown Vendor = iota
 	VendorMySQL
 	VendorMariaDB
+	VendorTiDB
+	VendorSingleStore
 )
 
 func (v Vendor) String() string {
@@ -25,6 +27,10 @@ func (v Vendor) String() string {
 		return "mysql"
 	case VendorMariaDB:
 		return "mariadb"
+	case VendorTiDB:
+		return "tidb"
+	case VendorSingleStore:
+		return "singlestore"
 	default:
 		return "unknown"
 	}
@@ -114,6 +120,7 @@ type Variant uint32
 const (
 	VariantPercona Variant = 1 << iota
 	VariantAurora
+	VariantVitess
 )
 
 // Variant zero value constants can either express no variant or unknown variants.
@@ -131,6 +138,9 @@ func (variant Variant) String() string {
 	if variant&VariantAurora != 0 {
 		ss = append(ss, "aurora")
 	}
+	if variant&VariantVitess != 0 {
+		ss = append(ss, "vitess")
+	}
 	return strings.Join(ss, "-")
 }
 
@@ -176,25 +186,35 @@ var FlavorUnknown = Flavor{}
 // comparison with these, although they're useful as args to Flavor.Matches()
 // and Flavor.Min().
 var (
-	FlavorMySQL55     = Flavor{Vendor: VendorMySQL, Version: Version{5, 5, 0}}
-	FlavorMySQL56     = Flavor{Vendor: VendorMySQL, Version: Version{5, 6, 0}}
-	FlavorMySQL57     = Flavor{Vendor: VendorMySQL, Version: Version{5, 7, 0}}
-	FlavorMySQL80     = Flavor{Vendor: VendorMySQL, Version: Version{8, 0, 0}}
-	FlavorPercona55   = Flavor{Vendor: VendorMySQL, Version: Version{5, 5, 0}, Variants: VariantPercona}
-	FlavorPercona56   = Flavor{Vendor: VendorMySQL, Version: Version{5, 6, 0}, Variants: VariantPercona}
-	FlavorPercona57   = Flavor{Vendor: VendorMySQL, Version: Version{5, 7, 0}, Variants: VariantPercona}
-	FlavorPercona80   = Flavor{Vendor: VendorMySQL, Version: Version{8, 0, 0}, Variants: VariantPercona}
-	FlavorMariaDB101  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 1, 0}}
-	FlavorMariaDB102  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 2, 0}}
-	FlavorMariaDB103  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 3, 0}}
-	FlavorMariaDB104  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 4, 0}}
-	FlavorMariaDB105  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 5, 0}}
-	FlavorMariaDB106  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 6, 0}}
-	FlavorMariaDB107  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 7, 0}}
-	FlavorMariaDB108  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 8, 0}}
-	FlavorMariaDB109  = Flavor{Vendor: VendorMariaDB, Version: Version{10, 9, 0}}
-	FlavorMariaDB1010 = Flavor{Vendor: VendorMariaDB, Version: Version{10, 10, 0}}
-	FlavorMariaDB1011 = Flavor{Vendor: VendorMariaDB, Version: Version{10, 11, 0}}
+	FlavorMySQL55       = Flavor{Vendor: VendorMySQL, Version: Version{5, 5, 0}}
+	FlavorMySQL56       = Flavor{Vendor: VendorMySQL, Version: Version{5, 6, 0}}
+	FlavorMySQL57       = Flavor{Vendor: VendorMySQL, Version: Version{5, 7, 0}}
+	FlavorMySQL80       = Flavor{Vendor: VendorMySQL, Version: Version{8, 0, 0}}
+	FlavorPercona55     = Flavor{Vendor: VendorMySQL, Version: Version{5, 5, 0}, Variants: VariantPercona}
+	FlavorPercona56     = Flavor{Vendor: VendorMySQL, Version: Version{5, 6, 0}, Variants: VariantPercona}
+	FlavorPercona57     = Flavor{Vendor: VendorMySQL, Version: Version{5, 7, 0}, Variants: VariantPercona}
+	FlavorPercona80     = Flavor{Vendor: VendorMySQL, Version: Version{8, 0, 0}, Variants: VariantPercona}
+	FlavorMariaDB101    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 1, 0}}
+	FlavorMariaDB102    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 2, 0}}
+	FlavorMariaDB103    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 3, 0}}
+	FlavorMariaDB104    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 4, 0}}
+	FlavorMariaDB105    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 5, 0}}
+	FlavorMariaDB106    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 6, 0}}
+	FlavorMariaDB107    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 7, 0}}
+	FlavorMariaDB108    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 8, 0}}
+	FlavorMariaDB109    = Flavor{Vendor: VendorMariaDB, Version: Version{10, 9, 0}}
+	FlavorMariaDB1010   = Flavor{Vendor: VendorMariaDB, Version: Version{10, 10, 0}}
+	FlavorMariaDB1011   = Flavor{Vendor: VendorMariaDB, Version: Version{10, 11, 0}}
+	FlavorMariaDB110    = Flavor{Vendor: VendorMariaDB, Version: Version{11, 0, 0}}
+	FlavorMariaDB111    = Flavor{Vendor: VendorMariaDB, Version: Version{11, 1, 0}}
+	FlavorMariaDB112    = Flavor{Vendor: VendorMariaDB, Version: Version{11, 2, 0}}
+	FlavorMariaDB114    = Flavor{Vendor: VendorMariaDB, Version: Version{11, 4, 0}}
+	FlavorMariaDB118    = Flavor{Vendor: VendorMariaDB, Version: Version{11, 8, 0}}
+	FlavorTiDB65        = Flavor{Vendor: VendorTiDB, Version: Version{6, 5, 0}}
+	FlavorTiDB70        = Flavor{Vendor: VendorTiDB, Version: Version{7, 0, 0}}
+	FlavorTiDB71        = Flavor{Vendor: VendorTiDB, Version: Version{7, 1, 0}}
+	FlavorTiDB75        = Flavor{Vendor: VendorTiDB, Version: Version{7, 5, 0}}
+	FlavorSingleStore80 = Flavor{Vendor: VendorSingleStore, Version: Version{8, 0, 0}}
 )
 
 // ParseFlavor returns a Flavor value based on the supplied string in format
@@ -218,11 +238,30 @@ func ParseFlavor(s string) Flavor {
 // IdentifyFlavor returns a Flavor value based on inputs obtained from server
 // vars @@global.version and @@global.version_comment. It accounts for how some
 // distributions and/or cloud platforms manipulate those values.
+// TiDB reports a MySQL-compatible version number followed by its own version,
+// for example "8.0.11-TiDB-v7.5.0"; in this case the TiDB version is used. If
+// TiDB is only identifiable from the version comment, its version is unknown.
+// Vitess's vtgate reports the MySQL version it emulates, with a "-Vitess"
+// suffix.
+// Aurora only identifies itself in the version string in some releases, for
+// example "8.0.mysql_aurora.3.04.0", in which case the MySQL patch version is
+// unknown. Otherwise, Aurora must be detected separately using
+// @@aurora_version.
+// SingleStore (formerly MemSQL) also reports the MySQL version it emulates,
+// and is only identifiable from the version comment. Its actual version must
+// be obtained separately from @@memsql_version, so the returned version is
+// zero in this case.
 func IdentifyFlavor(versionString, versionComment string) (flavor Flavor) {
 	flavor.Version, _ = ParseVersion(versionString)
 	versionString = strings.ToLower(versionString)
 	versionComment = strings.ToLower(versionComment)
-	if strings.Contains(versionComment, "percona") || strings.Contains(versionString, "percona") {
+	if _, tidbVersion, ok := strings.Cut(versionString, "-tidb-"); ok || strings.Contains(versionComment, "tidb") {
+		flavor.Vendor = VendorTiDB
+		flavor.Version, _ = ParseVersion(tidbVersion)
+	} else if strings.Contains(versionComment, "singlestore") || strings.Contains(versionComment, "memsql") {
+		flavor.Vendor = VendorSingleStore
+		flavor.Version = Version{}
+	} else if strings.Contains(versionComment, "percona") || strings.Contains(versionString, "percona") {
 		flavor.Vendor = VendorMySQL
 		flavor.Variants = VariantPercona
 	} else {
@@ -232,14 +271,20 @@ func IdentifyFlavor(versionString, versionComment string) (flavor Flavor) {
 				break
 			}
 		}
+		if strings.Contains(versionString, "vitess") {
+			flavor.Variants |= VariantVitess
+		}
+		if strings.Contains(versionString, "mysql_aurora") {
+			flavor.Variants |= VariantAurora
+		}
 	}
 
 	// If the vendor is still unknown after the above checks, it may be because
 	// various distribution methods adjust one or both of those strings. Fall
 	// back to sane defaults for known major versions.
-	// This logic will need to change whenever MySQL 9+ or MariaDB 11+ exists.
+	// This logic will need to change whenever MySQL 9+ or MariaDB 12+ exists.
 	if flavor.Vendor == VendorUnknown {
-		if flavor.Version[0] == 10 {
+		if flavor.Version[0] == 10 || flavor.Version[0] == 11 {
 			flavor.Vendor = VendorMariaDB
 		} else if flavor.Version[0] == 5 || flavor.Version[0] == 8 {
 			flavor.Vendor = VendorMySQL
@@ -351,13 +396,27 @@ func (fl Flavor) IsMariaDB() bool {
 	return fl.Vendor == VendorMariaDB
 }
 
+// IsTiDB returns true if the receiver's Vendor is VendorTiDB.
+func (fl Flavor) IsTiDB() bool {
+	return fl.Vendor == VendorTiDB
+}
+
+// IsSingleStore returns true if the receiver's Vendor is VendorSingleStore.
+func (fl Flavor) IsSingleStore() bool {
+	return fl.Vendor == VendorSingleStore
+}
+
 // Supported returns true if package tengo officially supports this flavor.
 func (fl Flavor) Supported() bool {
 	switch fl.Vendor {
 	case VendorMySQL:
 		return fl.Version.AtLeast(Version{5, 5}) && fl.Version.Below(Version{8, 1}) // MySQL 5.5.0-8.0.x is supported
 	case VendorMariaDB:
-		return fl.Version.AtLeast(Version{10, 1}) && fl.Version.Below(Version{11, 0}) // MariaDB 10.1-10.11 is supported
+		return fl.Version.AtLeast(Version{10, 1}) && fl.Version.Below(Version{12, 0}) // MariaDB 10.1-11.x is supported
+	case VendorTiDB:
+		return fl.Version.AtLeast(Version{6, 5}) && fl.Version.Below(Version{8, 0}) // TiDB 6.5-7.x is supported
+	case VendorSingleStore:
+		return fl.Version.AtLeast(Version{8, 0}) && fl.Version.Below(Version{9, 0}) // SingleStore 8.x is supported
 	default:
 		return false
 	}
@@ -380,14 +439,43 @@ func (fl Flavor) Known() bool {
 // using MySQL's native syntax. (Although MariaDB 10.1 has support for generated
 // columns, its syntax is borrowed from other DBMS, so false is returned.)
 func (fl Flavor) GeneratedColumns() bool {
-	return fl.Min(FlavorMySQL57) || fl.Min(FlavorMariaDB102)
+	return fl.Min(FlavorMySQL57) || fl.Min(FlavorMariaDB102) || fl.IsTiDB()
+}
+
+// EnforcesForeignKeys returns true if the flavor enforces foreign key
+// constraints. TiDB prior to 7.0 accepts and displays foreign key definitions,
+// but does not enforce them. SingleStore never enforces them.
+func (fl Flavor) EnforcesForeignKeys() bool {
+	return (!fl.IsTiDB() || fl.Min(FlavorTiDB70)) && !fl.IsSingleStore()
+}
+
+// InstantDDL returns true if the flavor supports ALGORITHM=INSTANT in ALTER
+// TABLE. This includes Aurora 3, which is based on MySQL 8.0.23+, but not
+// Aurora 2: its "fast DDL" is based on MySQL 5.7 and cannot be requested using
+// an ALGORITHM clause. If the flavor is not known, true is returned, so that
+// the server can make the determination.
+func (fl Flavor) InstantDDL() bool {
+	if fl.IsMySQL() && fl.Version.Major() > 0 {
+		return fl.Min(FlavorMySQL80.Dot(12))
+	} else if fl.IsMariaDB() && fl.Version.Major() > 0 {
+		return fl.Min(FlavorMariaDB103)
+	}
+	return true
+}
+
+// FullTextIndexes returns true if the flavor supports FULLTEXT indexes. TiDB
+// accepts FULLTEXT index definitions for compatibility purposes, but ignores
+// them.
+func (fl Flavor) FullTextIndexes() bool {
+	return !fl.IsTiDB()
 }
 
 // SortedForeignKeys returns true if the flavor sorts foreign keys
 // lexicographically in SHOW CREATE TABLE.
 func (fl Flavor) SortedForeignKeys() bool {
-	// MySQL sorts lexicographically in 5.6 through 8.0.18; MariaDB always does
-	return !fl.Matches(FlavorMySQL55) && !fl.Min(FlavorMySQL80.Dot(19))
+	// MySQL sorts lexicographically in 5.6 through 8.0.18; MariaDB always does;
+	// TiDB uses creation order
+	return !fl.Matches(FlavorMySQL55) && !fl.Min(FlavorMySQL80.Dot(19)) && !fl.IsTiDB()
 }
 
 // OmitIntDisplayWidth returns true if the flavor omits inclusion of display
@@ -6,10 +6,12 @@ import (
 
 func TestParseVendor(t *testing.T) {
 	cases := map[string]Vendor{
-		"mysql":    VendorMySQL,
-		"mariadb":  VendorMariaDB,
-		"postgres": VendorUnknown,
-		"":         VendorUnknown,
+		"mysql":       VendorMySQL,
+		"mariadb":     VendorMariaDB,
+		"tidb":        VendorTiDB,
+		"singlestore": VendorSingleStore,
+		"postgres":    VendorUnknown,
+		"":            VendorUnknown,
 	}
 	for input, expected := range cases {
 		if actual := ParseVendor(input); actual != expected {
@@ -78,6 +80,7 @@ func TestVariantString(t *testing.T) {
 		{VariantAurora, "aurora"},
 		{VariantPercona | VariantAurora, "percona-aurora"},
 		{VariantPercona | VariantUnknown, "percona"},
+		{VariantVitess, "vitess"},
 	}
 	for _, tc := range cases {
 		if actual := tc.input.String(); actual != tc.expected {
@@ -95,6 +98,7 @@ func TestParseVariant(t *testing.T) {
 		"percona-aurora":  VariantPercona | VariantAurora, // doesn't actually exist, just testing multi-variant logic
 		"aurora-percona":  VariantPercona | VariantAurora, // ditto, confirming ordering not important to parsing
 		"aurora-tidb":     VariantAurora,
+		"vitess":          VariantVitess,
 		"percona-percona": VariantPercona,
 	}
 	for input, expected := range cases {
@@ -133,6 +137,9 @@ func TestParseFlavor(t *testing.T) {
 		"supersecretdb:9.9": {VendorUnknown, Version{9, 9}, VariantNone},
 		"":                  FlavorUnknown,
 		"aurora:8.0":        {VendorMySQL, Version{8, 0}, VariantAurora},
+		"tidb:7.5":          FlavorTiDB75,
+		"vitess:8.0":        {VendorMySQL, Version{8, 0}, VariantVitess},
+		"singlestore:8.0":   FlavorSingleStore80,
 	}
 	for input, expected := range cases {
 		if actual := ParseFlavor(input); actual != expected {
@@ -158,10 +165,20 @@ func TestIdentifyFlavor(t *testing.T) {
 		{"10.3.8-MariaDB-log", "Source distribution", FlavorMariaDB103.Dot(8)},
 		{"10.3.16-MariaDB", "Homebrew", FlavorMariaDB103.Dot(16)},
 		{"10.3.8-0ubuntu0.18.04.1", "(Ubuntu)", FlavorMariaDB103.Dot(8)}, // due to major version 10 --> MariaDB
-		{"5.7.26", "Homebrew", FlavorMySQL57.Dot(26)},                    // due to major version 5 --> MySQL
-		{"8.0.13", "Homebrew", FlavorMySQL80.Dot(13)},                    // due to major version 8 --> MySQL
+		{"11.4.2-MariaDB-ubu2404", "mariadb.org binary distribution", FlavorMariaDB114.Dot(2)},
+		{"11.4.2-0ubuntu1", "(Ubuntu)", FlavorMariaDB114.Dot(2)}, // due to major version 11 --> MariaDB
+		{"5.7.26", "Homebrew", FlavorMySQL57.Dot(26)},            // due to major version 5 --> MySQL
+		{"8.0.13", "Homebrew", FlavorMySQL80.Dot(13)},            // due to major version 8 --> MySQL
 		{"webscalesql", "webscalesql", FlavorUnknown},
 		{"6.0.3", "Source distribution", Flavor{VendorUnknown, Version{6, 0, 3}, VariantNone}},
+		{"5.7.25-TiDB-v7.1.2", "", FlavorTiDB71.Dot(2)},
+		{"8.0.11-TiDB-v7.5.0", "", FlavorTiDB75},
+		{"8.0.11-TiDB-v6.5.3-serverless", "", FlavorTiDB65.Dot(3)},
+		{"8.0.11", "TiDB Server (Apache License 2.0) Community Edition, MySQL 8.0 compatible", Flavor{VendorTiDB, Version{}, VariantNone}},
+		{"8.0.mysql_aurora.3.04.0", "Source distribution", Flavor{VendorMySQL, Version{8, 0, 0}, VariantAurora}},
+		{"5.7.32", "SingleStoreDB source distribution (compatible; MySQL Enterprise & MySQL Commercial)", Flavor{VendorSingleStore, Version{}, VariantNone}},
+		{"5.5.58", "MemSQL source distribution (compatible; MySQL Enterprise & MySQL Commercial)", Flavor{VendorSingleStore, Version{}, VariantNone}},
+		{"8.0.30-Vitess", "Version: 17.0.0", Flavor{VendorMySQL, Version{8, 0, 30}, VariantVitess}},
 	}
 	for _, tc := range cases {
 		fl := IdentifyFlavor(tc.versionString, tc.versionComment)
@@ -271,19 +288,25 @@ func TestFlavorMin(t *testing.T) {
 
 func TestFlavorSupported(t *testing.T) {
 	cases := map[Flavor]bool{
-		FlavorMySQL55:            true,
-		FlavorMySQL80:            true,
-		FlavorMySQL80.Dot(123):   true,
-		FlavorPercona56:          true,
-		FlavorMariaDB101:         true,
-		FlavorMariaDB104.Dot(22): true,
-		FlavorMariaDB107:         true,
-		FlavorUnknown:            false,
-		{VendorUnknown, Version{5, 5, 20}, VariantNone}:  false,
-		{VendorMySQL, Version{8, 2, 12}, VariantNone}:    false,
-		{VendorMySQL, Version{10, 6}, VariantNone}:       false,
-		{VendorMariaDB, Version{11, 0, 12}, VariantNone}: false,
-		{VendorMySQL, Version{}, VariantNone}:            false,
+		FlavorMySQL55:              true,
+		FlavorMySQL80:              true,
+		FlavorMySQL80.Dot(123):     true,
+		FlavorPercona56:            true,
+		FlavorMariaDB101:           true,
+		FlavorMariaDB104.Dot(22):   true,
+		FlavorMariaDB107:           true,
+		FlavorTiDB65:               true,
+		FlavorTiDB75.Dot(1):        true,
+		FlavorSingleStore80.Dot(5): true,
+		{VendorSingleStore, Version{7, 8, 1}, VariantNone}: false,
+		FlavorUnknown: false,
+		{VendorUnknown, Version{5, 5, 20}, VariantNone}: false,
+		{VendorMySQL, Version{8, 2, 12}, VariantNone}:   false,
+		{VendorTiDB, Version{6, 1, 7}, VariantNone}:     false,
+		{VendorMySQL, Version{10, 6}, VariantNone}:      false,
+		{VendorMariaDB, Version{12, 0, 1}, VariantNone}: false,
+		FlavorMariaDB114.Dot(5):                         true,
+		{VendorMySQL, Version{}, VariantNone}:           false,
 	}
 	for flavor, expected := range cases {
 		if flavor.Supported() != expected {
@@ -322,6 +345,55 @@ func TestFlavorIs(t *testing.T) {
 	if FlavorUnknown.IsMariaDB() || FlavorMySQL80.IsMariaDB() || FlavorPercona57.IsMariaDB() || !FlavorMariaDB101.IsMariaDB() {
 		t.Error("Incorrect behavior for IsMariaDB")
 	}
+	if FlavorUnknown.IsTiDB() || FlavorMySQL80.IsTiDB() || !FlavorTiDB70.IsTiDB() {
+		t.Error("Incorrect behavior for IsTiDB")
+	}
+	if FlavorUnknown.IsSingleStore() || FlavorMySQL57.IsSingleStore() || !FlavorSingleStore80.IsSingleStore() {
+		t.Error("Incorrect behavior for IsSingleStore")
+	}
+}
+
+func TestFlavorFullTextIndexes(t *testing.T) {
+	if !FlavorMySQL57.FullTextIndexes() || !FlavorMariaDB103.FullTextIndexes() || FlavorTiDB75.FullTextIndexes() {
+		t.Error("Incorrect behavior for FullTextIndexes")
+	}
+}
+
+func TestFlavorInstantDDL(t *testing.T) {
+	cases := map[Flavor]bool{
+		FlavorMySQL57:                           false,
+		FlavorMySQL80.Dot(11):                   false,
+		FlavorMySQL80.Dot(12):                   true,
+		ParseFlavor("aurora:5.7.12"):            false,
+		ParseFlavor("aurora:8.0.32"):            true,
+		FlavorMariaDB102:                        false,
+		FlavorMariaDB103:                        true,
+		FlavorTiDB75:                            true,
+		FlavorUnknown:                           true,
+		{VendorMySQL, Version{}, VariantAurora}: true,
+	}
+	for flavor, expected := range cases {
+		if actual := flavor.InstantDDL(); actual != expected {
+			t.Errorf("Expected %s InstantDDL() to return %t, instead found %t", flavor, expected, actual)
+		}
+	}
+}
+
+func TestFlavorEnforcesForeignKeys(t *testing.T) {
+	cases := map[Flavor]bool{
+		FlavorMySQL55:       true,
+		FlavorMariaDB1011:   true,
+		FlavorUnknown:       true,
+		FlavorTiDB65.Dot(3): false,
+		FlavorTiDB70:        true,
+		FlavorTiDB75:        true,
+		FlavorSingleStore80: false,
+	}
+	for flavor, expected := range cases {
+		if actual := flavor.EnforcesForeignKeys(); actual != expected {
+			t.Errorf("Expected %s EnforcesForeignKeys() to return %t, instead found %t", flavor, expected, actual)
+		}
+	}
 }
 
 func TestFlavorGeneratedColumns(t *testing.T) {
@@ -338,6 +410,7 @@ func TestFlavorGeneratedColumns(t *testing.T) {
 		{FlavorMariaDB102, true},
 		{FlavorPercona56, false},
 		{FlavorPercona57, true},
+		{FlavorTiDB65, true},
 		{FlavorUnknown, false},
 	}
 	for _, tc := range cases {
@@ -364,6 +437,7 @@ func TestFlavorSortedForeignKeys(t *testing.T) {
 		{FlavorMariaDB101, true},
 		{FlavorMariaDB102, true},
 		{FlavorMariaDB103, true},
+		{FlavorTiDB75, false},
 		{Flavor{VendorUnknown, Version{5, 6, 0}, VariantNone}, true},
 	}
 	for _, tc := range cases {
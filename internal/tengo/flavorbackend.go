@@ -0,0 +1,116 @@
+package tengo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FlavorBackend covers the vendor-specific portions of table introspection and
+// DDL rendering. It only handles the differences between vendors which speak
+// the MySQL protocol and expose a MySQL-like information_schema; database
+// systems outside of that family are not supported. Vendors without a
+// registered FlavorBackend use BaseFlavorBackend, which implements the MySQL
+// and MariaDB behavior.
+type FlavorBackend interface {
+	// QueryTables returns the base tables in the schema, along with the names of
+	// any tables that are partitioned. Columns, indexes, and other table
+	// components are queried separately.
+	QueryTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, []string, error)
+
+	// QueryColumns returns the columns of the tables in the schema, keyed by
+	// table name.
+	QueryColumns(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string][]*Column, error)
+
+	// QueryIndexes returns the primary key and secondary indexes of the tables
+	// in the schema, keyed by table name.
+	QueryIndexes(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string]*Index, map[string][]*Index, error)
+
+	// ShowCreate returns the CREATE statement for the supplied table, as
+	// canonically formatted by the server.
+	ShowCreate(ctx context.Context, db *sqlx.DB, table string) (string, error)
+
+	// QuoteIdentifier returns the supplied name quoted as an identifier.
+	QuoteIdentifier(name string) string
+
+	// RenderDDL returns a CREATE statement for the table generated from its
+	// fields. For tables which are fully supported, this matches the output of
+	// ShowCreate exactly.
+	RenderDDL(t *Table, flavor Flavor) string
+}
+
+// TableFixer may optionally be implemented by a FlavorBackend which must
+// adjust each introspected table after its components have been assembled, for
+// example to parse attributes which are only exposed in SHOW CREATE TABLE.
+// FixTable is called prior to comparing RenderDDL's output with ShowCreate's.
+type TableFixer interface {
+	FixTable(t *Table, flavor Flavor)
+}
+
+var flavorBackendRegistry = struct {
+	sync.RWMutex
+	byVendor map[Vendor]FlavorBackend
+}{
+	byVendor: make(map[Vendor]FlavorBackend),
+}
+
+// RegisterFlavorBackend sets the FlavorBackend used for all flavors of the
+// supplied vendor. An error is returned if a FlavorBackend has already been
+// registered for the vendor.
+func RegisterFlavorBackend(vendor Vendor, fb FlavorBackend) error {
+	flavorBackendRegistry.Lock()
+	defer flavorBackendRegistry.Unlock()
+	if _, already := flavorBackendRegistry.byVendor[vendor]; already {
+		return fmt.Errorf("FlavorBackend for vendor %s is already registered", vendor)
+	}
+	flavorBackendRegistry.byVendor[vendor] = fb
+	return nil
+}
+
+// GetFlavorBackend returns the FlavorBackend registered for the flavor's
+// vendor, or BaseFlavorBackend if there is none.
+func GetFlavorBackend(flavor Flavor) FlavorBackend {
+	flavorBackendRegistry.RLock()
+	defer flavorBackendRegistry.RUnlock()
+	if fb, ok := flavorBackendRegistry.byVendor[flavor.Vendor]; ok {
+		return fb
+	}
+	return BaseFlavorBackend{}
+}
+
+// BaseFlavorBackend is the FlavorBackend for MySQL and MariaDB. Other vendors'
+// FlavorBackends may embed it, in order to only override the methods which
+// differ.
+type BaseFlavorBackend struct{}
+
+// QueryTables satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) QueryTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, []string, error) {
+	return queryTablesInSchema(ctx, db, schema, flavor)
+}
+
+// QueryColumns satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) QueryColumns(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string][]*Column, error) {
+	return queryColumnsInSchema(ctx, db, schema, flavor)
+}
+
+// QueryIndexes satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) QueryIndexes(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string]*Index, map[string][]*Index, error) {
+	return queryIndexesInSchema(ctx, db, schema, flavor)
+}
+
+// ShowCreate satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) ShowCreate(ctx context.Context, db *sqlx.DB, table string) (string, error) {
+	return showCreateTable(ctx, db, table)
+}
+
+// QuoteIdentifier satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) QuoteIdentifier(name string) string {
+	return EscapeIdentifier(name)
+}
+
+// RenderDDL satisfies the FlavorBackend interface.
+func (BaseFlavorBackend) RenderDDL(t *Table, flavor Flavor) string {
+	return t.baseCreateStatement(flavor)
+}
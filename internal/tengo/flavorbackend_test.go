@@ -0,0 +1,42 @@
+package tengo
+
+import (
+	"testing"
+)
+
+func TestFlavorBackendRegistry(t *testing.T) {
+	if _, ok := GetFlavorBackend(FlavorMySQL80).(BaseFlavorBackend); !ok {
+		t.Error("Expected MySQL to use BaseFlavorBackend")
+	}
+	if _, ok := GetFlavorBackend(FlavorUnknown).(BaseFlavorBackend); !ok {
+		t.Error("Expected unknown flavor to use BaseFlavorBackend")
+	}
+	if _, ok := GetFlavorBackend(FlavorTiDB75).(tidbFlavorBackend); !ok {
+		t.Error("Expected TiDB to use tidbFlavorBackend")
+	}
+	if _, ok := GetFlavorBackend(FlavorSingleStore80).(TableFixer); !ok {
+		t.Error("Expected SingleStore's FlavorBackend to implement TableFixer")
+	}
+	if err := RegisterFlavorBackend(VendorTiDB, BaseFlavorBackend{}); err == nil {
+		t.Error("Expected duplicate registration to return an error, but it did not")
+	}
+	if err := RegisterFlavorBackend(VendorMariaDB, BaseFlavorBackend{}); err != nil {
+		t.Fatalf("Unexpected error from RegisterFlavorBackend: %v", err)
+	}
+	flavorBackendRegistry.Lock()
+	delete(flavorBackendRegistry.byVendor, VendorMariaDB)
+	flavorBackendRegistry.Unlock()
+}
+
+func TestFlavorBackendRenderDDL(t *testing.T) {
+	table := aTable(1)
+	if actual := GetFlavorBackend(FlavorMySQL57).RenderDDL(&table, FlavorMySQL57); actual != table.CreateStatement {
+		t.Errorf("RenderDDL does not match expected CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+	if actual := GetFlavorBackend(FlavorSingleStore80).RenderDDL(&table, FlavorSingleStore80); actual == table.CreateStatement {
+		t.Error("Expected SingleStore RenderDDL to differ from MySQL format, but it did not")
+	}
+	if actual := (BaseFlavorBackend{}).QuoteIdentifier("a`b"); actual != "`a``b`" {
+		t.Errorf("Unexpected result from QuoteIdentifier: %s", actual)
+	}
+}
@@ -17,6 +17,8 @@ type Index struct {
 	Comment        string      `json:"comment,omitempty"`
 	Type           string      `json:"type"`
 	FullTextParser string      `json:"parser,omitempty"`
+	Clustering     string      `json:"clustering,omitempty"`    // TiDB primary keys only: "CLUSTERED" or "NONCLUSTERED"
+	VectorOptions  string      `json:"vectorOptions,omitempty"` // MariaDB VECTOR indexes only, e.g. "`M`=8 `DISTANCE`=cosine"
 }
 
 // IndexPart represents an individual indexed column or expression. Each index
@@ -35,7 +37,7 @@ func (idx *Index) Definition(flavor Flavor) string {
 	for n := range idx.Parts {
 		parts[n] = idx.Parts[n].Definition(flavor)
 	}
-	var typeAndName, comment, invis, parser string
+	var typeAndName, comment, invis, vectorOptions, clustering, parser string
 	if idx.PrimaryKey {
 		if !idx.Unique {
 			panic(errors.New("Index is primary key, but isn't marked as unique"))
@@ -58,12 +60,18 @@ func (idx *Index) Definition(flavor Flavor) string {
 			invis = " /*!80000 INVISIBLE */"
 		}
 	}
+	if idx.Type == "VECTOR" && idx.VectorOptions != "" {
+		vectorOptions = " " + idx.VectorOptions
+	}
+	if idx.Clustering != "" {
+		clustering = fmt.Sprintf(" /*T![clustered_index] %s */", idx.Clustering)
+	}
 	if idx.Type == "FULLTEXT" && idx.FullTextParser != "" {
 		// Note the trailing space here is intentional -- it's always present in SHOW
 		// CREATE TABLE for this particular clause
 		parser = fmt.Sprintf(" /*!50100 WITH PARSER `%s` */ ", idx.FullTextParser)
 	}
-	return fmt.Sprintf("%s (%s)%s%s%s", typeAndName, strings.Join(parts, ","), comment, invis, parser)
+	return fmt.Sprintf("%s (%s)%s%s%s%s%s", typeAndName, strings.Join(parts, ","), comment, invis, vectorOptions, clustering, parser)
 }
 
 // Equals returns true if two indexes are completely identical, false otherwise.
@@ -103,7 +111,7 @@ func (idx *Index) Equivalent(other *Index) bool {
 	if idx == nil || other == nil {
 		return idx == other // only equivalent if BOTH are nil
 	}
-	if idx.PrimaryKey != other.PrimaryKey || idx.Unique != other.Unique || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser {
+	if idx.PrimaryKey != other.PrimaryKey || idx.Unique != other.Unique || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser || idx.VectorOptions != other.VectorOptions || idx.Clustering != other.Clustering {
 		return false
 	}
 	return idx.sameParts(other)
@@ -121,7 +129,7 @@ func (idx *Index) RedundantTo(other *Index) bool {
 	if idx == nil || other == nil {
 		return false
 	}
-	if idx.PrimaryKey || (idx.Unique && !other.Unique) || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser {
+	if idx.PrimaryKey || (idx.Unique && !other.Unique) || idx.Type != other.Type || idx.FullTextParser != other.FullTextParser || idx.VectorOptions != other.VectorOptions {
 		return false
 	}
 	if !idx.Invisible && other.Invisible {
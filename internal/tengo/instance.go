@@ -147,7 +147,9 @@ func (instance *Instance) Connect(defaultSchema string, params string) (*sqlx.DB
 
 func (instance *Instance) rawConnectionPool(defaultSchema, fullParams string, alreadyLocked bool) (*sqlx.DB, error) {
 	fullDSN := fmt.Sprintf("%s%s?%s", instance.BaseDSN, defaultSchema, fullParams)
+	doneTiming := StartTiming("Connect to %s", instance)
 	db, err := sqlx.Connect(instance.Driver, fullDSN)
+	doneTiming()
 	if err != nil {
 		return nil, err
 	}
@@ -254,6 +256,17 @@ const (
 	NameCaseInsensitive NameCaseMode = 2
 )
 
+// NamesEqual returns true if the supplied table or schema names refer to the
+// same object under this lower_case_table_names mode. With NameCaseLower or
+// NameCaseInsensitive, names are compared case-insensitively; otherwise an
+// exact comparison is used.
+func (mode NameCaseMode) NamesEqual(a, b string) bool {
+	if mode == NameCaseLower || mode == NameCaseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
 // NameCaseMode returns a value reflecting this instance's lower_case_table_names,
 // normally a value between 0 and 2 if successfully queryable.
 func (instance *Instance) NameCaseMode() NameCaseMode {
@@ -310,6 +323,28 @@ func (instance *Instance) hydrateVars(db *sqlx.DB, lock bool) {
 	}
 	instance.valid = true
 	instance.flavor = IdentifyFlavor(result.Version, result.VersionComment)
+	if instance.flavor.IsSingleStore() {
+		var memsqlVersion string
+		if err = db.Get(&memsqlVersion, "SELECT @@global.memsql_version"); err == nil {
+			instance.flavor.Version, _ = ParseVersion(memsqlVersion)
+		}
+	} else if instance.flavor.IsMySQL() && (instance.flavor.HasVariant(VariantAurora) || strings.HasSuffix(strings.ToLower(instance.Host), ".rds.amazonaws.com")) {
+		// Aurora doesn't always identify itself in @@version, and when it does, the
+		// MySQL patch version is replaced by Aurora's own version. To avoid an extra
+		// query on every other server, this is only checked if @@version or the
+		// hostname suggests Aurora; non-Aurora RDS servers will simply return an
+		// error here, which is ignored.
+		var aurora struct {
+			AuroraVersion string
+			InnoDBVersion string
+		}
+		if err = db.Get(&aurora, "SELECT @@global.aurora_version AS auroraversion, @@global.innodb_version AS innodbversion"); err == nil {
+			instance.flavor.Variants |= VariantAurora
+			if version, parseErr := ParseVersion(aurora.InnoDBVersion); parseErr == nil {
+				instance.flavor.Version = version
+			}
+		}
+	}
 	instance.sqlMode = strings.Split(result.SQLMode, ",")
 	instance.waitTimeout = result.WaitTimeout
 	instance.lockWaitTimeout = result.LockWaitTimeout
@@ -382,6 +417,20 @@ func (instance *Instance) SchemaNames() ([]string, error) {
 // more schema names as args to filter the result to just those schemas.
 // Note that the ordering of the resulting slice is not guaranteed.
 func (instance *Instance) Schemas(onlyNames ...string) ([]*Schema, error) {
+	return instance.schemas(false, onlyNames)
+}
+
+// SchemaSummaries behaves like Schemas, but only introspects an inventory of
+// objects, which is much faster for schemas with many tables. Each returned
+// Table only has its name, engine, charset, collation, create options, and
+// comment populated; each returned Routine only has its name, type, definer,
+// and comment populated. In particular, CreateStatement is blank, so these
+// objects are not suitable for use in diff operations.
+func (instance *Instance) SchemaSummaries(onlyNames ...string) ([]*Schema, error) {
+	return instance.schemas(true, onlyNames)
+}
+
+func (instance *Instance) schemas(summaryOnly bool, onlyNames []string) ([]*Schema, error) {
 	db, err := instance.CachedConnectionPool("", "")
 	if err != nil {
 		return nil, err
@@ -449,16 +498,60 @@ func (instance *Instance) Schemas(onlyNames ...string) ([]*Schema, error) {
 			// concurrent introspection queries reuse conns more effectively.
 			schemaDB.SetMaxIdleConns(20)
 		}
-		g, ctx := errgroup.WithContext(context.Background())
-		g.Go(func() (err error) {
-			schemas[n].Tables, err = querySchemaTables(ctx, schemaDB, rawSchema.Name, flavor)
-			return err
-		})
-		g.Go(func() (err error) {
-			schemas[n].Routines, err = querySchemaRoutines(ctx, schemaDB, rawSchema.Name, flavor)
-			return err
+		spanCtx, span := StartSpan(context.Background(), "tengo.IntrospectSchema", map[string]string{
+			"db.instance": instance.String(),
+			"db.name":     rawSchema.Name,
 		})
-		err = g.Wait()
+
+		// If configured via SetIntrospectionRetries, compare checksums before and
+		// after introspection, and retry if the schema was modified in between
+		retries := getIntrospectionRetries()
+		for attempt := 0; ; attempt++ {
+			var before, after string
+			if retries > 0 {
+				if before, err = schemaChecksum(spanCtx, schemaDB, rawSchema.Name); err != nil {
+					break
+				}
+			}
+			g, ctx := errgroup.WithContext(spanCtx)
+			if summaryOnly {
+				g.Go(func() (err error) {
+					schemas[n].Tables, _, err = GetFlavorBackend(flavor).QueryTables(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+				g.Go(func() (err error) {
+					schemas[n].Routines, err = queryRoutineSummariesInSchema(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+			} else {
+				g.Go(func() (err error) {
+					schemas[n].Tables, err = querySchemaTables(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+				g.Go(func() (err error) {
+					schemas[n].Routines, err = querySchemaRoutines(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+				g.Go(func() (err error) {
+					schemas[n].Views, err = queryViewsInSchema(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+				g.Go(func() (err error) {
+					schemas[n].Triggers, err = queryTriggersInSchema(ctx, schemaDB, rawSchema.Name, flavor)
+					return err
+				})
+			}
+			if err = g.Wait(); err != nil || retries == 0 {
+				break
+			}
+			if after, err = schemaChecksum(spanCtx, schemaDB, rawSchema.Name); err != nil || after == before {
+				break
+			} else if attempt >= retries {
+				err = fmt.Errorf("schema %s was modified during introspection on %d consecutive attempts", EscapeIdentifier(rawSchema.Name), attempt+1)
+				break
+			}
+		}
+		span.End(err)
 		schemaDB.Close()
 		if err != nil {
 			return nil, err
@@ -525,7 +618,7 @@ func (instance *Instance) ShowCreateTable(schema, table string) (string, error)
 	if err != nil {
 		return "", err
 	}
-	return showCreateTable(context.Background(), db, table)
+	return GetFlavorBackend(instance.Flavor()).ShowCreate(context.Background(), db, table)
 }
 
 // introspectionParams returns a params string which ensures safe session
@@ -736,10 +829,15 @@ func (instance *Instance) DropSchema(schema string, opts BulkDropOptions) error
 // default collation of opts.DefaultCharSet. (Supplying an empty string for both
 // is also allowed, but is a no-op.)
 func (instance *Instance) AlterSchema(schema string, opts SchemaCreationOptions) error {
-	s, err := instance.Schema(schema)
+	// Only the schema's current charset and collation are needed here, so a
+	// summary is sufficient and avoids the SHOW CREATE fan-out
+	schemas, err := instance.SchemaSummaries(schema)
 	if err != nil {
 		return err
+	} else if len(schemas) == 0 {
+		return sql.ErrNoRows
 	}
+	s := schemas[0]
 	statement := s.AlterStatement(opts.DefaultCharSet, opts.DefaultCollation)
 	if statement == "" {
 		return nil
@@ -900,6 +998,47 @@ func (instance *Instance) DropRoutinesInSchema(schema string, opts BulkDropOptio
 	return g.Wait()
 }
 
+// DropViewsInSchema drops all views in a schema.
+func (instance *Instance) DropViewsInSchema(schema string, opts BulkDropOptions) error {
+	db, err := instance.CachedConnectionPool(schema, opts.params())
+	if err != nil {
+		return err
+	}
+
+	// Obtain names directly; faster than going through instance.Schema(schema)
+	// since we don't need other introspection
+	var names []string
+	if opts.Schema != nil {
+		for _, view := range opts.Schema.Views {
+			names = append(names, view.Name)
+		}
+	} else {
+		query := `
+			SELECT table_name AS table_name
+			FROM   information_schema.views
+			WHERE  table_schema = ?`
+		if err := db.Select(&names, query, schema); err != nil {
+			return err
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Views may refer to each other, but dropping a view never fails due to
+	// another view depending on it, so there's no need for any ordering here
+	g := new(errgroup.Group)
+	g.SetLimit(opts.Concurrency())
+	for _, name := range names {
+		name := name
+		g.Go(func() error {
+			_, err := db.Exec("DROP VIEW " + EscapeIdentifier(name))
+			return err
+		})
+	}
+	return g.Wait()
+}
+
 // tablesToPartitions returns a map whose keys are all tables in the schema
 // (whether partitioned or not), and values are either nil (if unpartitioned or
 // partitioned in a way that doesn't support DROP PARTITION) or a slice of
@@ -928,6 +1067,7 @@ func tablesToPartitions(db *sqlx.DB, schema string, flavor Flavor) (map[string][
 		FROM     information_schema.partitions p
 		WHERE    p.table_schema = ?
 		ORDER BY p.table_name, p.partition_ordinal_position`
+	query = stripQueryHints(query, flavor)
 	if err := db.Select(&rawNames, query, schema); err != nil {
 		return nil, err
 	}
@@ -1,12 +1,14 @@
 package tengo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -497,6 +499,40 @@ func (s TengoIntegrationSuite) TestInstanceSchemas(t *testing.T) {
 	}
 }
 
+func (s TengoIntegrationSuite) TestInstanceSchemaSummaries(t *testing.T) {
+	s.SourceTestSQL(t, "integration-ext.sql")
+
+	schemas, err := s.d.SchemasByName()
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemasByName: %v", err)
+	}
+	summaries, err := s.d.SchemaSummaries()
+	if err != nil {
+		t.Fatalf("Unexpected error from SchemaSummaries: %v", err)
+	} else if len(summaries) != len(schemas) {
+		t.Fatalf("Expected SchemaSummaries to return %d schemas, instead found %d", len(schemas), len(summaries))
+	}
+	for _, summary := range summaries {
+		schema := schemas[summary.Name]
+		if schema == nil {
+			t.Errorf("SchemaSummaries returned unexpected schema %s", summary.Name)
+			continue
+		}
+		if len(summary.Tables) != len(schema.Tables) || len(summary.Routines) != len(schema.Routines) {
+			t.Errorf("Object counts in schema %s do not match: summary has %d tables and %d routines; full introspection has %d tables and %d routines", summary.Name, len(summary.Tables), len(summary.Routines), len(schema.Tables), len(schema.Routines))
+		}
+		for _, table := range summary.Tables {
+			if fullTable := schema.Table(table.Name); fullTable == nil {
+				t.Errorf("Table %s.%s found in summary but not in full introspection", summary.Name, table.Name)
+			} else if table.Engine != fullTable.Engine || table.Collation != fullTable.Collation || table.Comment != fullTable.Comment {
+				t.Errorf("Table %s.%s fields do not match between summary and full introspection", summary.Name, table.Name)
+			} else if table.CreateStatement != "" || len(table.Columns) > 0 {
+				t.Errorf("Table %s.%s in summary unexpectedly has CreateStatement or Columns populated", summary.Name, table.Name)
+			}
+		}
+	}
+}
+
 func (s TengoIntegrationSuite) TestInstanceShowCreateTable(t *testing.T) {
 	t1create, err1 := s.d.ShowCreateTable("testing", "actor")
 	t2create, err2 := s.d.ShowCreateTable("testing", "actor_in_film")
@@ -859,3 +895,88 @@ func (s TengoIntegrationSuite) TestInstanceAlterSchema(t *testing.T) {
 	assertNoError("testcharcoll", "latin1", "", "latin1", "latin1_swedish_ci")
 	assertNoError("testing", "utf8mb4", "utf8mb4_general_ci", "utf8mb4", "utf8mb4_general_ci")
 }
+
+func (s TengoIntegrationSuite) TestInstanceIntrospectionRetries(t *testing.T) {
+	db, err := s.d.CachedConnectionPool("testing", "")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	before, err := schemaChecksum(context.Background(), db, "testing")
+	if err != nil {
+		t.Fatalf("Unexpected error from schemaChecksum: %v", err)
+	}
+	if again, err := schemaChecksum(context.Background(), db, "testing"); err != nil || again != before {
+		t.Errorf("Expected schemaChecksum to be stable; instead found %q vs %q, err=%v", before, again, err)
+	}
+	if _, err := db.Exec("ALTER TABLE actor ADD COLUMN checksum_test int"); err != nil {
+		t.Fatalf("Unexpected error altering table: %v", err)
+	}
+	if after, err := schemaChecksum(context.Background(), db, "testing"); err != nil || after == before {
+		t.Errorf("Expected schemaChecksum to change after ALTER; instead found %q vs %q, err=%v", before, after, err)
+	}
+
+	// With no concurrent DDL, introspection should succeed on the first attempt
+	SetIntrospectionRetries(2)
+	defer SetIntrospectionRetries(0)
+	if table := s.GetTable(t, "testing", "actor"); table.Columns[len(table.Columns)-1].Name != "checksum_test" {
+		t.Errorf("Unexpected last column of table: %s", table.Columns[len(table.Columns)-1].Name)
+	}
+}
+
+func TestNameCaseModeNamesEqual(t *testing.T) {
+	cases := []struct {
+		mode     NameCaseMode
+		a, b     string
+		expected bool
+	}{
+		{NameCaseAsIs, "foo", "foo", true},
+		{NameCaseAsIs, "foo", "Foo", false},
+		{NameCaseLower, "foo", "Foo", true},
+		{NameCaseInsensitive, "FOO", "Foo", true},
+		{NameCaseInsensitive, "foo", "foo2", false},
+		{NameCaseUnknown, "foo", "Foo", false},
+	}
+	for _, c := range cases {
+		if actual := c.mode.NamesEqual(c.a, c.b); actual != c.expected {
+			t.Errorf("Expected NameCaseMode(%d).NamesEqual(%q, %q) to return %t, instead found %t", c.mode, c.a, c.b, c.expected, actual)
+		}
+	}
+}
+
+func TestSetIntrospectionRetries(t *testing.T) {
+	defer SetIntrospectionRetries(0)
+	SetIntrospectionRetries(3)
+	if actual := getIntrospectionRetries(); actual != 3 {
+		t.Errorf("Expected 3, instead found %d", actual)
+	}
+	SetIntrospectionRetries(-5)
+	if actual := getIntrospectionRetries(); actual != 0 {
+		t.Errorf("Expected negative value to be treated as 0, instead found %d", actual)
+	}
+}
+
+func TestLagFromReplicaStatus(t *testing.T) {
+	cases := []struct {
+		row      map[string]interface{}
+		expected time.Duration
+		wantErr  bool
+	}{
+		{map[string]interface{}{"Seconds_Behind_Source": []byte("12")}, 12 * time.Second, false},
+		{map[string]interface{}{"Seconds_Behind_Master": int64(3)}, 3 * time.Second, false},
+		{map[string]interface{}{"Seconds_Behind_Source": nil}, 0, true},
+		{map[string]interface{}{"Seconds_Behind_Source": []byte("x")}, 0, true},
+		{map[string]interface{}{"Slave_IO_Running": []byte("Yes")}, 0, true},
+	}
+	for n, c := range cases {
+		lag, err := lagFromReplicaStatus(c.row)
+		if lag != c.expected || (err != nil) != c.wantErr {
+			t.Errorf("Case %d: expected lag %s and error=%t, instead found %s, %v", n, c.expected, c.wantErr, lag, err)
+		}
+	}
+}
+
+func (s TengoIntegrationSuite) TestInstanceReplicationLag(t *testing.T) {
+	if _, err := s.d.ReplicationLag(context.Background()); err != ErrNotReplica {
+		t.Errorf("Expected ErrNotReplica from non-replica instance, instead found %v", err)
+	}
+}
@@ -22,34 +22,45 @@ import (
 var reExtraOnUpdate = regexp.MustCompile(`(?i)\bon update (current_timestamp(?:\(\d*\))?)`)
 
 func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, error) {
-	tables, havePartitions, err := queryTablesInSchema(ctx, db, schema, flavor)
+	fb := GetFlavorBackend(flavor)
+	tables, partitionedTableNames, err := fb.QueryTables(ctx, db, schema, flavor)
 	if err != nil {
 		return nil, err
 	}
 
 	g, subCtx := errgroup.WithContext(ctx)
 
-	for n := range tables {
-		t := tables[n] // avoid issues with goroutines and loop iterator values
-		g.Go(func() (err error) {
-			t.CreateStatement, err = showCreateTable(subCtx, db, t.Name)
-			if err != nil {
-				err = fmt.Errorf("Error executing SHOW CREATE TABLE for %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(t.Name), err)
-			}
-			return err
-		})
-	}
+	g.Go(func() error {
+		defer StartTiming("SHOW CREATE TABLE for %d tables in schema %s", len(tables), schema)()
+		showGroup, showCtx := errgroup.WithContext(subCtx)
+		for n := range tables {
+			t := tables[n] // avoid issues with goroutines and loop iterator values
+			showGroup.Go(func() (err error) {
+				spanCtx, span := StartSpan(showCtx, "tengo.ShowCreateTable", map[string]string{
+					"db.name":  schema,
+					"db.table": t.Name,
+				})
+				t.CreateStatement, err = fb.ShowCreate(spanCtx, db, t.Name)
+				span.End(err)
+				if err != nil {
+					err = fmt.Errorf("Error executing SHOW CREATE TABLE for %s.%s: %s", fb.QuoteIdentifier(schema), fb.QuoteIdentifier(t.Name), err)
+				}
+				return err
+			})
+		}
+		return showGroup.Wait()
+	})
 
 	var columnsByTableName map[string][]*Column
 	g.Go(func() (err error) {
-		columnsByTableName, err = queryColumnsInSchema(subCtx, db, schema, flavor)
+		columnsByTableName, err = fb.QueryColumns(subCtx, db, schema, flavor)
 		return err
 	})
 
 	var primaryKeyByTableName map[string]*Index
 	var secondaryIndexesByTableName map[string][]*Index
 	g.Go(func() (err error) {
-		primaryKeyByTableName, secondaryIndexesByTableName, err = queryIndexesInSchema(subCtx, db, schema, flavor)
+		primaryKeyByTableName, secondaryIndexesByTableName, err = fb.QueryIndexes(subCtx, db, schema, flavor)
 		return err
 	})
 
@@ -68,9 +79,9 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 	}
 
 	var partitioningByTableName map[string]*TablePartitioning
-	if havePartitions {
+	if len(partitionedTableNames) > 0 {
 		g.Go(func() (err error) {
-			partitioningByTableName, err = queryPartitionsInSchema(subCtx, db, schema, flavor)
+			partitioningByTableName, err = queryPartitionsInSchema(subCtx, db, schema, flavor, partitionedTableNames)
 			return err
 		})
 	}
@@ -82,6 +93,7 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 
 	// Assemble all the data, fix edge cases, and determine if SHOW CREATE TABLE
 	// matches expectation
+	defer StartTiming("Table introspection fixups in schema %s", schema)()
 	for _, t := range tables {
 		t.Columns = columnsByTableName[t.Name]
 		t.PrimaryKey = primaryKeyByTableName[t.Name]
@@ -100,6 +112,12 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		// Obtain TABLESPACE clause from SHOW CREATE TABLE, if present
 		t.Tablespace = ParseCreateTablespace(t.CreateStatement)
 
+		// MySQL secondary engine attributes are also easiest to obtain from SHOW
+		// CREATE TABLE
+		if flavor.Min(FlavorMySQL80) && strings.Contains(t.CreateStatement, "SECONDARY") {
+			fixSecondaryEngine(t)
+		}
+
 		// Obtain next AUTO_INCREMENT value from SHOW CREATE TABLE, which avoids
 		// potential problems with information_schema discrepancies
 		_, t.NextAutoIncrement = ParseCreateAutoInc(t.CreateStatement)
@@ -113,12 +131,16 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		// Index order is unpredictable with new MySQL 8 data dictionary, so reorder
 		// indexes based on parsing SHOW CREATE TABLE if needed
 		if flavor.Min(FlavorMySQL80) && len(t.SecondaryIndexes) > 1 {
-			fixIndexOrder(t)
+			if err := fixIndexOrder(t); err != nil {
+				t.fixupErr = err
+			}
 		}
 		// Foreign keys order is unpredictable in MySQL before 5.6, so reorder
 		// foreign keys based on parsing SHOW CREATE TABLE if needed
 		if !flavor.SortedForeignKeys() && len(t.ForeignKeys) > 1 {
-			fixForeignKeyOrder(t)
+			if err := fixForeignKeyOrder(t); err != nil {
+				t.fixupErr = err
+			}
 		}
 		// Create options order is unpredictable with the new MySQL 8 data dictionary
 		// Also need to fix some charset/collation edge cases in SHOW CREATE TABLE
@@ -145,12 +167,26 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		if strings.Contains(t.CreateStatement, "WITH PARSER") {
 			fixFulltextIndexParsers(t, flavor)
 		}
+		// Similarly, MariaDB VECTOR indexes may have options which aren't exposed
+		// in I_S
+		if strings.Contains(t.CreateStatement, "VECTOR KEY") {
+			fixVectorIndexOptions(t, flavor)
+		}
+		// MariaDB system versioning attributes of columns are not fully exposed in
+		// I_S
+		if flavor.Min(FlavorMariaDB103) && strings.Contains(t.CreateStatement, "SYSTEM VERSIONING") {
+			fixSystemVersioningColumns(t)
+		}
 		// Fix problems with I_S data for default expressions as well as functional
 		// indexes in MySQL 8
 		if flavor.Min(FlavorMySQL80) {
 			fixDefaultExpression(t, flavor)
 			fixIndexExpression(t, flavor)
 		}
+		// Apply any vendor-specific fixups
+		if fixer, ok := fb.(TableFixer); ok {
+			fixer.FixTable(t, flavor)
+		}
 		// Fix shortcoming in I_S data for check constraints
 		if len(t.Checks) > 0 {
 			fixChecks(t, flavor)
@@ -159,14 +195,18 @@ func querySchemaTables(ctx context.Context, db *sqlx.DB, schema string, flavor F
 		// Compare what we expect the create DDL to be, to determine if we support
 		// diffing for the table. (No need to remove next AUTO_INCREMENT from this
 		// comparison since the value was parsed from t.CreateStatement earlier.)
-		if t.CreateStatement != t.GeneratedCreateStatement(flavor) {
+		if t.fixupErr != nil || t.CreateStatement != t.GeneratedCreateStatement(flavor) {
 			t.UnsupportedDDL = true
 		}
 	}
 	return tables, nil
 }
 
-func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, bool, error) {
+// queryTablesInSchema returns the base tables in the schema, along with the
+// names of any tables that are partitioned. MariaDB system-versioned tables are
+// included as well, as these have a distinct table_type.
+func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, []string, error) {
+	defer StartTiming("Query of information_schema.tables for schema %s", schema)()
 	var rawTables []struct {
 		Name               string         `db:"table_name"`
 		Type               string         `db:"table_type"`
@@ -186,15 +226,17 @@ func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 		FROM   information_schema.tables t
 		JOIN   information_schema.collations c ON t.table_collation = c.collation_name
 		WHERE  t.table_schema = ?
-		AND    t.table_type = 'BASE TABLE'`
+		AND    t.table_type IN ('BASE TABLE', 'SYSTEM VERSIONED')`
+	query = fixCollationsJoin(query, flavor)
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawTables, query, schema); err != nil {
-		return nil, false, fmt.Errorf("Error querying information_schema.tables for schema %s: %s", schema, err)
+		return nil, nil, fmt.Errorf("Error querying information_schema.tables for schema %s: %s", schema, err)
 	}
 	if len(rawTables) == 0 {
-		return []*Table{}, false, nil
+		return []*Table{}, nil, nil
 	}
 	tables := make([]*Table, len(rawTables))
-	var havePartitions bool
+	var partitionedTableNames []string
 	for n, rawTable := range rawTables {
 		// Note that we no longer set Table.NextAutoIncrement here. information_schema
 		// potentially has bad data, e.g. a table without an auto-inc col can still
@@ -207,18 +249,20 @@ func queryTablesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 			Collation:          rawTable.TableCollation.String,
 			CollationIsDefault: rawTable.CollationIsDefault != "",
 			Comment:            rawTable.Comment,
+			SystemVersioned:    rawTable.Type == "SYSTEM VERSIONED",
 		}
 		if rawTable.CreateOptions.Valid && rawTable.CreateOptions.String != "" {
 			if strings.Contains(strings.ToUpper(rawTable.CreateOptions.String), "PARTITIONED") {
-				havePartitions = true
+				partitionedTableNames = append(partitionedTableNames, rawTable.Name)
 			}
 			tables[n].CreateOptions = reformatCreateOptions(rawTable.CreateOptions.String)
 		}
 	}
-	return tables, havePartitions, nil
+	return tables, partitionedTableNames, nil
 }
 
 func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string][]*Column, error) {
+	defer StartTiming("Query of information_schema.columns for schema %s", schema)()
 	stripDisplayWidth := flavor.OmitIntDisplayWidth()
 	var rawColumns []struct {
 		Name               string         `db:"column_name"`
@@ -232,6 +276,7 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 		CharSet            sql.NullString `db:"character_set_name"`
 		Collation          sql.NullString `db:"collation_name"`
 		CollationIsDefault sql.NullString `db:"is_default"`
+		SRID               sql.NullString `db:"srs_id"`
 	}
 	query := `
 		SELECT    SQL_BUFFER_RESULT
@@ -241,24 +286,42 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 		          %s AS generation_expression,
 		          c.column_comment AS column_comment,
 		          c.character_set_name AS character_set_name,
-		          c.collation_name AS collation_name, co.is_default AS is_default
+		          c.collation_name AS collation_name, co.is_default AS is_default,
+		          %s AS srs_id
 		FROM      information_schema.columns c
 		LEFT JOIN information_schema.collations co ON co.collation_name = c.collation_name
 		WHERE     c.table_schema = ?
 		ORDER BY  c.table_name, c.ordinal_position`
-	genExpr := "NULL"
+	genExpr, srsID := "NULL", "NULL"
 	if flavor.GeneratedColumns() {
 		genExpr = "c.generation_expression"
 	}
-	query = fmt.Sprintf(query, genExpr)
+	if flavor.Min(FlavorMySQL80) {
+		srsID = "c.srs_id" // MySQL 8 supports explicit SRID attributes on spatial columns
+	}
+	query = fmt.Sprintf(query, genExpr, srsID)
+	query = fixCollationsJoin(query, flavor)
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawColumns, query, schema); err != nil {
 		return nil, fmt.Errorf("Error querying information_schema.columns for schema %s: %s", schema, err)
 	}
-	columnsByTableName := make(map[string][]*Column)
+	// Large schemas may have tens of thousands of columns, so we pre-size each
+	// table's slice and intern frequently-repeated strings such as types and
+	// collations. Columns are allocated individually, so that retaining one
+	// *Column does not keep every other column in the schema in memory.
+	colCountByTableName := make(map[string]int)
+	for _, rawColumn := range rawColumns {
+		colCountByTableName[rawColumn.TableName]++
+	}
+	columnsByTableName := make(map[string][]*Column, len(colCountByTableName))
+	for tableName, count := range colCountByTableName {
+		columnsByTableName[tableName] = make([]*Column, 0, count)
+	}
+	strs := make(stringInterner)
 	for _, rawColumn := range rawColumns {
 		col := &Column{
 			Name:          rawColumn.Name,
-			TypeInDB:      rawColumn.Type,
+			TypeInDB:      strs.intern(rawColumn.Type),
 			Nullable:      strings.ToUpper(rawColumn.IsNullable) == "YES",
 			AutoIncrement: strings.Contains(rawColumn.Extra, "auto_increment"),
 			Comment:       rawColumn.Comment,
@@ -279,6 +342,12 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 		if rawColumn.GenerationExpr.Valid {
 			col.GenerationExpr = rawColumn.GenerationExpr.String
 			col.Virtual = strings.Contains(rawColumn.Extra, "VIRTUAL GENERATED")
+			// MariaDB reports the row period columns of system-versioned tables as
+			// generated, but these don't have an actual generation expression
+			if col.GenerationExpr == "ROW START" || col.GenerationExpr == "ROW END" {
+				col.VersioningRow = strings.TrimPrefix(col.GenerationExpr, "ROW ")
+				col.GenerationExpr = ""
+			}
 		}
 		if !rawColumn.Default.Valid {
 			allowNullDefault := col.Nullable && !col.AutoIncrement && col.GenerationExpr == ""
@@ -297,7 +366,7 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 				col.Default = "NULL"
 			}
 		} else if flavor.Min(FlavorMariaDB102) {
-			if !col.AutoIncrement && col.GenerationExpr == "" {
+			if !col.AutoIncrement && col.GenerationExpr == "" && col.VersioningRow == "" {
 				// MariaDB 10.2+ exposes defaults as expressions / quote-wrapped strings
 				col.Default = rawColumn.Default.String
 			}
@@ -323,19 +392,40 @@ func queryColumnsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 			}
 		}
 		if rawColumn.Collation.Valid { // only text-based column types have a notion of charset and collation
-			col.CharSet = rawColumn.CharSet.String
-			col.Collation = rawColumn.Collation.String
+			col.CharSet = strs.intern(rawColumn.CharSet.String)
+			col.Collation = strs.intern(rawColumn.Collation.String)
 			col.CollationIsDefault = (rawColumn.CollationIsDefault.String != "")
 		}
-		if columnsByTableName[rawColumn.TableName] == nil {
-			columnsByTableName[rawColumn.TableName] = make([]*Column, 0)
+		if rawColumn.SRID.Valid {
+			col.SRID = rawColumn.SRID.String
 		}
+		col.Default = strs.intern(col.Default)
 		columnsByTableName[rawColumn.TableName] = append(columnsByTableName[rawColumn.TableName], col)
 	}
 	return columnsByTableName, nil
 }
 
+// fixCollationsJoin adjusts a query's join against information_schema.collations
+// for MariaDB 10.10+. In these versions, the Unicode 14 collations (such as
+// utf8mb4_uca1400_ai_ci, the default in MariaDB 11.5+) are listed in that
+// table without a character set prefix, e.g. uca1400_ai_ci. Their full names
+// are only available from collation_character_set_applicability, which also
+// has an is_default column in these versions.
+func fixCollationsJoin(query string, flavor Flavor) string {
+	if !flavor.Min(FlavorMariaDB1010) {
+		return query
+	}
+	query = strings.Replace(query,
+		"information_schema.collations c ON t.table_collation = c.collation_name",
+		"information_schema.collation_character_set_applicability c ON t.table_collation = c.full_collation_name", 1)
+	query = strings.Replace(query,
+		"information_schema.collations co ON co.collation_name = c.collation_name",
+		"information_schema.collation_character_set_applicability co ON co.full_collation_name = c.collation_name", 1)
+	return query
+}
+
 func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string]*Index, map[string][]*Index, error) {
+	defer StartTiming("Query of information_schema.statistics for schema %s", schema)()
 	var rawIndexes []struct {
 		Name       string         `db:"index_name"`
 		TableName  string         `db:"table_name"`
@@ -370,6 +460,7 @@ func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 		visSelect = "IF(ignored = 'YES', 'NO', 'YES')"
 	}
 	query = fmt.Sprintf(query, exprSelect, visSelect)
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawIndexes, query, schema); err != nil {
 		return nil, nil, fmt.Errorf("Error querying information_schema.statistics for schema %s: %s", schema, err)
 	}
@@ -382,7 +473,11 @@ func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 	// stitch together the col info. We cannot use an ORDER BY on this query, since
 	// only the unsorted result matches the same order of secondary indexes as the
 	// CREATE TABLE statement.
-	indexesByTableAndName := make(map[string]*Index)
+	type tableAndIndexName struct {
+		table, index string
+	}
+	indexesByTableAndName := make(map[tableAndIndexName]*Index)
+	strs := make(stringInterner)
 	for _, rawIndex := range rawIndexes {
 		if rawIndex.SeqInIndex > 1 {
 			continue
@@ -391,7 +486,7 @@ func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 			Name:      rawIndex.Name,
 			Unique:    rawIndex.NonUnique == 0,
 			Comment:   rawIndex.Comment.String,
-			Type:      rawIndex.Type,
+			Type:      strs.intern(rawIndex.Type),
 			Invisible: (rawIndex.Visible == "NO"),
 		}
 		if strings.ToUpper(index.Name) == "PRIMARY" {
@@ -403,29 +498,33 @@ func queryIndexesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavo
 			}
 			secondaryIndexesByTableName[rawIndex.TableName] = append(secondaryIndexesByTableName[rawIndex.TableName], index)
 		}
-		fullNameStr := fmt.Sprintf("%s.%s.%s", schema, rawIndex.TableName, rawIndex.Name)
-		indexesByTableAndName[fullNameStr] = index
+		indexesByTableAndName[tableAndIndexName{rawIndex.TableName, rawIndex.Name}] = index
 	}
 	for _, rawIndex := range rawIndexes {
-		fullIndexNameStr := fmt.Sprintf("%s.%s.%s", schema, rawIndex.TableName, rawIndex.Name)
-		index, ok := indexesByTableAndName[fullIndexNameStr]
+		index, ok := indexesByTableAndName[tableAndIndexName{rawIndex.TableName, rawIndex.Name}]
 		if !ok {
-			panic(fmt.Errorf("Cannot find index %s", fullIndexNameStr))
+			return nil, nil, fmt.Errorf("Cannot find index %s.%s.%s", schema, rawIndex.TableName, rawIndex.Name)
 		}
 		for len(index.Parts) < int(rawIndex.SeqInIndex) {
 			index.Parts = append(index.Parts, IndexPart{})
 		}
-		index.Parts[rawIndex.SeqInIndex-1] = IndexPart{
-			ColumnName:   rawIndex.ColumnName.String,
-			Expression:   rawIndex.Expression.String,
-			PrefixLength: uint16(rawIndex.SubPart.Int64),
-			Descending:   (rawIndex.Collation.String == "D"),
+		part := IndexPart{
+			ColumnName: strs.intern(rawIndex.ColumnName.String),
+			Expression: rawIndex.Expression.String,
+			Descending: (rawIndex.Collation.String == "D"),
+		}
+		// Expression parts can never have a prefix length, but MySQL I_S may report
+		// a sub_part for multi-valued index parts, e.g. CAST(... AS CHAR(N) ARRAY)
+		if part.Expression == "" {
+			part.PrefixLength = uint16(rawIndex.SubPart.Int64)
 		}
+		index.Parts[rawIndex.SeqInIndex-1] = part
 	}
 	return primaryKeyByTableName, secondaryIndexesByTableName, nil
 }
 
 func queryForeignKeysInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string][]*ForeignKey, error) {
+	defer StartTiming("Query of information_schema.referential_constraints for schema %s", schema)()
 	var rawForeignKeys []struct {
 		Name                 string `db:"constraint_name"`
 		TableName            string `db:"table_name"`
@@ -450,6 +549,7 @@ func queryForeignKeysInSchema(ctx context.Context, db *sqlx.DB, schema string, f
 		                                 kcu.referenced_column_name IS NOT NULL
 		WHERE    rc.constraint_schema = ?
 		ORDER BY BINARY rc.constraint_name, kcu.ordinal_position`
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawForeignKeys, query, schema, schema); err != nil {
 		return nil, fmt.Errorf("Error querying foreign key constraints for schema %s: %s", schema, err)
 	}
@@ -477,6 +577,7 @@ func queryForeignKeysInSchema(ctx context.Context, db *sqlx.DB, schema string, f
 }
 
 func queryChecksInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string][]*Check, error) {
+	defer StartTiming("Query of information_schema.check_constraints for schema %s", schema)()
 	checksByTableName := make(map[string][]*Check)
 	var rawChecks []struct {
 		Name      string `db:"constraint_name"`
@@ -513,6 +614,7 @@ func queryChecksInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 			WHERE    table_schema = ? AND constraint_type = 'CHECK'
 			ORDER BY table_name, constraint_name`
 	}
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawChecks, query, schema); err != nil {
 		return nil, fmt.Errorf("Error querying check constraints for schema %s: %s", schema, err)
 	}
@@ -527,7 +629,29 @@ func queryChecksInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor
 	return checksByTableName, nil
 }
 
-func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) (map[string]*TablePartitioning, error) {
+// partitionQueryBatchSize is the maximum number of partitioned tables whose
+// partitions are fetched in a single query of information_schema.partitions.
+// Schemas with thousands of partitioned tables, each with hundreds of
+// partitions, can otherwise yield a result set of millions of rows in one
+// query, which is slow to materialize and may exceed server-side temp table
+// limits.
+var partitionQueryBatchSize = 100
+
+func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor, tableNames []string) (map[string]*TablePartitioning, error) {
+	defer StartTiming("Query of information_schema.partitions for schema %s", schema)()
+	partitioningByTableName := make(map[string]*TablePartitioning, len(tableNames))
+	for _, batch := range splitBatches(tableNames, partitionQueryBatchSize) {
+		if err := queryPartitionsForTables(ctx, db, schema, flavor, batch, partitioningByTableName); err != nil {
+			return nil, err
+		}
+	}
+	return partitioningByTableName, nil
+}
+
+// queryPartitionsForTables queries information_schema.partitions for the
+// supplied subset of tables in schema, adding the results to
+// partitioningByTableName.
+func queryPartitionsForTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor, tableNames []string, partitioningByTableName map[string]*TablePartitioning) error {
 	var rawPartitioning []struct {
 		TableName     string         `db:"table_name"`
 		PartitionName string         `db:"partition_name"`
@@ -551,14 +675,18 @@ func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, fl
 		         p.partition_comment AS partition_comment
 		FROM     information_schema.partitions p
 		WHERE    p.table_schema = ?
+		AND      p.table_name IN (?)
 		AND      p.partition_name IS NOT NULL
 		ORDER BY p.table_name, p.partition_ordinal_position,
 		         p.subpartition_ordinal_position`
-	if err := db.SelectContext(ctx, &rawPartitioning, query, schema); err != nil {
-		return nil, fmt.Errorf("Error querying information_schema.partitions for schema %s: %s", schema, err)
+	query, args, err := sqlx.In(stripQueryHints(query, flavor), schema, tableNames)
+	if err == nil {
+		err = db.SelectContext(ctx, &rawPartitioning, query, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("Error querying information_schema.partitions for schema %s: %s", schema, err)
 	}
 
-	partitioningByTableName := make(map[string]*TablePartitioning)
 	for _, rawPart := range rawPartitioning {
 		p, ok := partitioningByTableName[rawPart.TableName]
 		if !ok {
@@ -578,47 +706,76 @@ func queryPartitionsInSchema(ctx context.Context, db *sqlx.DB, schema string, fl
 			Comment: rawPart.Comment,
 		})
 	}
-	return partitioningByTableName, nil
+	return nil
+}
+
+// FixupError indicates that a table's SHOW CREATE TABLE could not be parsed to
+// correct a shortcoming in its information_schema representation. This
+// typically occurs with unusual identifiers, for example ones containing
+// newlines. Rather than causing introspection to fail, the affected table is
+// marked with UnsupportedDDL.
+type FixupError struct {
+	Table  string
+	Fixup  string
+	Reason string
+}
+
+// Error satisfies the builtin error interface.
+func (fe *FixupError) Error() string {
+	return fmt.Sprintf("unable to parse SHOW CREATE TABLE of %s for %s: %s", EscapeIdentifier(fe.Table), fe.Fixup, fe.Reason)
 }
 
 var reIndexLine = regexp.MustCompile("^\\s+(?:UNIQUE |FULLTEXT |SPATIAL )?KEY `((?:[^`]|``)+)` (?:USING \\w+ )?\\([`(]")
 
 // MySQL 8.0 uses a different index order in SHOW CREATE TABLE than in
 // information_schema. This function fixes the struct to match SHOW CREATE
-// TABLE's ordering.
-func fixIndexOrder(t *Table) {
+// TABLE's ordering. If the ordering cannot be determined, a *FixupError is
+// returned and the struct is left unmodified.
+func fixIndexOrder(t *Table) error {
 	byName := t.SecondaryIndexesByName()
-	t.SecondaryIndexes = make([]*Index, len(byName))
-	var cur int
+	ordered := make([]*Index, 0, len(t.SecondaryIndexes))
 	for _, line := range strings.Split(t.CreateStatement, "\n") {
 		matches := reIndexLine.FindStringSubmatch(line)
 		if matches == nil {
 			continue
 		}
-		t.SecondaryIndexes[cur] = byName[matches[1]]
-		cur++
+		idx := byName[unescapeIdentifier(matches[1])]
+		if idx == nil || len(ordered) == len(t.SecondaryIndexes) {
+			return &FixupError{Table: t.Name, Fixup: "index order", Reason: fmt.Sprintf("unexpected index %s", EscapeIdentifier(unescapeIdentifier(matches[1])))}
+		}
+		ordered = append(ordered, idx)
 	}
-	if cur != len(t.SecondaryIndexes) {
-		panic(fmt.Errorf("Failed to parse indexes of %s for reordering: only matched %d of %d secondary indexes", t.Name, cur, len(t.SecondaryIndexes)))
+	if len(ordered) != len(t.SecondaryIndexes) {
+		return &FixupError{Table: t.Name, Fixup: "index order", Reason: fmt.Sprintf("only matched %d of %d secondary indexes", len(ordered), len(t.SecondaryIndexes))}
 	}
+	t.SecondaryIndexes = ordered
+	return nil
 }
 
 var reForeignKeyLine = regexp.MustCompile("^\\s+CONSTRAINT `((?:[^`]|``)+)` FOREIGN KEY")
 
 // MySQL 5.5 doesn't alphabetize foreign keys; this function fixes the struct
-// to match SHOW CREATE TABLE's order
-func fixForeignKeyOrder(t *Table) {
+// to match SHOW CREATE TABLE's order. If the ordering cannot be determined, a
+// *FixupError is returned and the struct is left unmodified.
+func fixForeignKeyOrder(t *Table) error {
 	byName := t.foreignKeysByName()
-	t.ForeignKeys = make([]*ForeignKey, len(byName))
-	var cur int
+	ordered := make([]*ForeignKey, 0, len(t.ForeignKeys))
 	for _, line := range strings.Split(t.CreateStatement, "\n") {
 		matches := reForeignKeyLine.FindStringSubmatch(line)
 		if matches == nil {
 			continue
 		}
-		t.ForeignKeys[cur] = byName[matches[1]]
-		cur++
+		fk := byName[unescapeIdentifier(matches[1])]
+		if fk == nil || len(ordered) == len(t.ForeignKeys) {
+			return &FixupError{Table: t.Name, Fixup: "foreign key order", Reason: fmt.Sprintf("unexpected foreign key %s", EscapeIdentifier(unescapeIdentifier(matches[1])))}
+		}
+		ordered = append(ordered, fk)
+	}
+	if len(ordered) != len(t.ForeignKeys) {
+		return &FixupError{Table: t.Name, Fixup: "foreign key order", Reason: fmt.Sprintf("only matched %d of %d foreign keys", len(ordered), len(t.ForeignKeys))}
 	}
+	t.ForeignKeys = ordered
+	return nil
 }
 
 // MySQL 8.0 uses a different order for table options in SHOW CREATE TABLE
@@ -671,12 +828,21 @@ func fixCreateOptionsOrder(t *Table, flavor Flavor) {
 //     charset, while tables created in 8.0 will generally include it whenever a
 //     CHARACTER SET is shown in a column definition
 func fixShowCharSets(t *Table) {
-	lines := strings.Split(t.CreateStatement, "\n")
-	for n, col := range t.Columns {
+	for _, col := range t.Columns {
 		if col.CharSet == "" || col.Collation == "" {
 			continue // non-character-based column type, nothing to do
 		}
-		line := lines[n+1] // columns start on second line of CREATE TABLE
+		// Locate the column's line by its name, rather than by position, since
+		// quoted column names may themselves contain newlines
+		prefix := "\n  " + EscapeIdentifier(col.Name) + " "
+		pos := strings.Index(t.CreateStatement, prefix)
+		if pos == -1 {
+			continue
+		}
+		line := t.CreateStatement[pos+len(prefix):]
+		if end := strings.IndexByte(line, '\n'); end > -1 {
+			line = line[:end]
+		}
 		if col.Collation == t.Collation && strings.Contains(line, "CHARACTER SET "+col.CharSet) {
 			col.ForceShowCharSet = true
 		}
@@ -716,6 +882,8 @@ func fixGenerationExpr(t *Table, flavor Flavor) {
 	}
 }
 
+var reSystemTimePartitioning = regexp.MustCompile(`\n PARTITION BY SYSTEM_TIME ([^\n]+)`)
+
 // fixPartitioningEdgeCases handles situations that are reflected in SHOW CREATE
 // TABLE, but missing (or difficult to obtain) in information_schema.
 func fixPartitioningEdgeCases(t *Table, flavor Flavor) {
@@ -743,6 +911,26 @@ func fixPartitioningEdgeCases(t *Table, flavor Flavor) {
 		}
 	}
 
+	// MariaDB SYSTEM_TIME partitioning may have an INTERVAL or LIMIT clause,
+	// which isn't exposed in information_schema. Its partitions are all HISTORY
+	// partitions except for the last one, which is always the CURRENT partition.
+	if t.Partitioning.Method == "SYSTEM_TIME" {
+		t.Partitioning.Expression = ""
+		if matches := reSystemTimePartitioning.FindStringSubmatch(t.CreateStatement); matches != nil {
+			t.Partitioning.Expression = matches[1]
+		}
+		if strings.Contains(t.CreateStatement, fmt.Sprintf("\nPARTITIONS %d", len(t.Partitioning.Partitions))) {
+			t.Partitioning.ForcePartitionList = PartitionListCount
+		}
+		for n, p := range t.Partitioning.Partitions {
+			if n == len(t.Partitioning.Partitions)-1 {
+				p.Values = "CURRENT"
+			} else {
+				p.Values = "HISTORY"
+			}
+		}
+	}
+
 	// Process DATA DIRECTORY clauses, which are easier to parse from SHOW CREATE
 	// TABLE instead of information_schema.innodb_sys_tablespaces.
 	if (t.Partitioning.ForcePartitionList == PartitionListDefault || t.Partitioning.ForcePartitionList == PartitionListExplicit) &&
@@ -773,7 +961,9 @@ func fixPerconaColCompression(t *Table) {
 		if matches == nil {
 			continue
 		}
-		colsByName[matches[1]].Compression = matches[2]
+		if col := colsByName[unescapeIdentifier(matches[1])]; col != nil {
+			col.Compression = matches[2]
+		}
 	}
 }
 
@@ -796,6 +986,74 @@ func fixFulltextIndexParsers(t *Table, flavor Flavor) {
 	}
 }
 
+// fixVectorIndexOptions parses the table's CREATE string in order to populate
+// Index.VectorOptions for any MariaDB VECTOR indexes with non-default options,
+// such as M or DISTANCE.
+func fixVectorIndexOptions(t *Table, flavor Flavor) {
+	for _, idx := range t.SecondaryIndexes {
+		if idx.Type == "VECTOR" {
+			idx.VectorOptions = ""
+			template := regexp.QuoteMeta(idx.Definition(flavor))
+			template += "((?: `\\w+`=\\w+)+),?\n"
+			re := regexp.MustCompile(template)
+			if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
+				idx.VectorOptions = matches[1][1:]
+			}
+		}
+	}
+}
+
+var reColumnLine = regexp.MustCompile("^\\s+`((?:[^`]|``)+)` ")
+
+// fixSystemVersioningColumns parses the table's CREATE string in order to
+// populate Column.VersioningRow and Column.WithoutVersioning for MariaDB
+// system-versioned tables. Only the row period columns are identifiable in
+// information_schema, and even then only as a special generation expression.
+func fixSystemVersioningColumns(t *Table) {
+	colsByName := t.ColumnsByName()
+	for _, line := range strings.Split(t.CreateStatement, "\n") {
+		matches := reColumnLine.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		col := colsByName[unescapeIdentifier(matches[1])]
+		if col == nil {
+			continue
+		}
+		if strings.Contains(line, " GENERATED ALWAYS AS ROW START") {
+			col.VersioningRow, col.GenerationExpr = "START", ""
+		} else if strings.Contains(line, " GENERATED ALWAYS AS ROW END") {
+			col.VersioningRow, col.GenerationExpr = "END", ""
+		}
+		col.WithoutVersioning = strings.Contains(line, " WITHOUT SYSTEM VERSIONING")
+	}
+}
+
+var reSecondaryEngineAttribute = regexp.MustCompile(` /\*!80021 SECONDARY_ENGINE_ATTRIBUTE='((?:''|\\.|[^'\\])*)' \*/`)
+
+// fixSecondaryEngine parses the table's CREATE string in order to populate
+// Table.SecondaryEngine, as well as Column.NotSecondary and
+// Column.SecondaryEngineAttribute, for MySQL tables using a secondary engine
+// such as HeatWave.
+func fixSecondaryEngine(t *Table) {
+	t.SecondaryEngine = ParseCreateSecondaryEngine(t.CreateStatement)
+	colsByName := t.ColumnsByName()
+	for _, line := range strings.Split(t.CreateStatement, "\n") {
+		matches := reColumnLine.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		col := colsByName[unescapeIdentifier(matches[1])]
+		if col == nil {
+			continue
+		}
+		col.NotSecondary = strings.Contains(line, " NOT SECONDARY")
+		if attrMatches := reSecondaryEngineAttribute.FindStringSubmatch(line); attrMatches != nil {
+			col.SecondaryEngineAttribute = attrMatches[1]
+		}
+	}
+}
+
 // fixDefaultExpression parses the table's CREATE string in order to correct
 // problems in Column.Default for columns using a default expression in MySQL 8:
 //   - In MySQL 8.0.13-8.0.22, blob/text cols may have default expressions but
@@ -822,7 +1080,11 @@ func fixDefaultExpression(t *Table, flavor Flavor) {
 		}
 		if colDefinition := col.Definition(flavor, t); !strings.Contains(t.CreateStatement, colDefinition) {
 			defaultClause := " DEFAULT " + col.Default
-			after := colDefinition[strings.Index(colDefinition, defaultClause)+len(defaultClause):]
+			pos := strings.Index(colDefinition, defaultClause)
+			if pos == -1 {
+				continue
+			}
+			after := colDefinition[pos+len(defaultClause):]
 			reTemplate := `(?m)^\s*` + regexp.QuoteMeta(EscapeIdentifier(col.Name)) + matcher + regexp.QuoteMeta(after)
 			re := regexp.MustCompile(reTemplate)
 			if matches := re.FindStringSubmatch(t.CreateStatement); matches != nil {
@@ -911,6 +1173,7 @@ func fixChecks(t *Table, flavor Flavor) {
 }
 
 func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Routine, error) {
+	defer StartTiming("Query of information_schema.routines for schema %s", schema)()
 	// Obtain the routines in the schema
 	// We completely exclude routines that the user can call, but not examine --
 	// e.g. user has EXECUTE priv but missing other vital privs. In this case
@@ -938,6 +1201,7 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 		       r.definer AS definer, r.database_collation AS database_collation
 		FROM   information_schema.routines r
 		WHERE  r.routine_schema = ? AND routine_definition IS NOT NULL`
+	query = stripQueryHints(query, flavor)
 	if err := db.SelectContext(ctx, &rawRoutines, query, schema); err != nil {
 		return nil, fmt.Errorf("Error querying information_schema.routines for schema %s: %s", schema, err)
 	}
@@ -1026,6 +1290,190 @@ func querySchemaRoutines(ctx context.Context, db *sqlx.DB, schema string, flavor
 	return routines, err
 }
 
+// queryRoutineSummariesInSchema returns the routines in the schema, without
+// running SHOW CREATE or querying mysql.proc. Only the Name, Type, Definer,
+// and Comment fields are populated.
+func queryRoutineSummariesInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Routine, error) {
+	var rawRoutines []struct {
+		Name    string `db:"routine_name"`
+		Type    string `db:"routine_type"`
+		Definer string `db:"definer"`
+		Comment string `db:"routine_comment"`
+	}
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       r.routine_name AS routine_name, UPPER(r.routine_type) AS routine_type,
+		       r.definer AS definer, r.routine_comment AS routine_comment
+		FROM   information_schema.routines r
+		WHERE  r.routine_schema = ? AND routine_definition IS NOT NULL`
+	query = stripQueryHints(query, flavor)
+	if err := db.SelectContext(ctx, &rawRoutines, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.routines for schema %s: %s", schema, err)
+	}
+	routines := make([]*Routine, len(rawRoutines))
+	for n, rawRoutine := range rawRoutines {
+		routines[n] = &Routine{
+			Name:    rawRoutine.Name,
+			Type:    ObjectType(strings.ToLower(rawRoutine.Type)),
+			Definer: rawRoutine.Definer,
+			Comment: rawRoutine.Comment,
+		}
+	}
+	return routines, nil
+}
+
+func queryViewsInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*View, error) {
+	defer StartTiming("Query of information_schema.views for schema %s", schema)()
+	var rawViews []struct {
+		Name                string `db:"table_name"`
+		CheckOption         string `db:"check_option"`
+		Definer             string `db:"definer"`
+		SecurityType        string `db:"security_type"`
+		CharSetClient       string `db:"character_set_client"`
+		CollationConnection string `db:"collation_connection"`
+	}
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       v.table_name AS table_name, UPPER(v.check_option) AS check_option,
+		       v.definer AS definer, UPPER(v.security_type) AS security_type,
+		       v.character_set_client AS character_set_client,
+		       v.collation_connection AS collation_connection
+		FROM   information_schema.views v
+		WHERE  v.table_schema = ?`
+	query = stripQueryHints(query, flavor)
+	if err := db.SelectContext(ctx, &rawViews, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.views for schema %s: %s", schema, err)
+	}
+	views := make([]*View, len(rawViews))
+	for n, rawView := range rawViews {
+		views[n] = &View{
+			Name:                rawView.Name,
+			Definer:             rawView.Definer,
+			SecurityType:        rawView.SecurityType,
+			CharSetClient:       rawView.CharSetClient,
+			CollationConnection: rawView.CollationConnection,
+		}
+		if rawView.CheckOption != "NONE" {
+			views[n].CheckOption = rawView.CheckOption
+		}
+	}
+
+	// information_schema.views.view_definition qualifies all table names with
+	// their schema name, and MySQL does not expose the algorithm there at all, so
+	// run a SHOW CREATE per view, using multiple goroutines for performance
+	// reasons.
+	g, subCtx := errgroup.WithContext(ctx)
+	for n := range views {
+		v := views[n] // avoid issues with goroutines and loop iterator values
+		g.Go(func() (err error) {
+			v.CreateStatement, err = showCreateView(subCtx, db, v.Name)
+			if err == nil {
+				v.CreateStatement = strings.Replace(v.CreateStatement, "\r\n", "\n", -1)
+				err = v.parseCreateStatement(schema)
+			} else {
+				err = fmt.Errorf("Error executing SHOW CREATE VIEW for %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(v.Name), err)
+			}
+			return err
+		})
+	}
+	return views, g.Wait()
+}
+
+func showCreateView(ctx context.Context, db *sqlx.DB, view string) (create string, err error) {
+	var createRows []struct {
+		CreateStatement sql.NullString `db:"Create View"`
+	}
+	err = db.SelectContext(ctx, &createRows, "SHOW CREATE VIEW "+EscapeIdentifier(view))
+	if (err == nil && len(createRows) != 1) || IsDatabaseError(err, mysqlerr.ER_NO_SUCH_TABLE) {
+		err = sql.ErrNoRows
+	} else if err == nil {
+		create = createRows[0].CreateStatement.String
+	}
+	return
+}
+
+func queryTriggersInSchema(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Trigger, error) {
+	defer StartTiming("Query of information_schema.triggers for schema %s", schema)()
+	var rawTriggers []struct {
+		Name                string `db:"trigger_name"`
+		Table               string `db:"event_object_table"`
+		Timing              string `db:"action_timing"`
+		Event               string `db:"event_manipulation"`
+		Definer             string `db:"definer"`
+		SQLMode             string `db:"sql_mode"`
+		CharSetClient       string `db:"character_set_client"`
+		CollationConnection string `db:"collation_connection"`
+		DatabaseCollation   string `db:"database_collation"`
+	}
+	query := `
+		SELECT SQL_BUFFER_RESULT
+		       t.trigger_name AS trigger_name, t.event_object_table AS event_object_table,
+		       UPPER(t.action_timing) AS action_timing,
+		       UPPER(t.event_manipulation) AS event_manipulation,
+		       t.definer AS definer, t.sql_mode AS sql_mode,
+		       t.character_set_client AS character_set_client,
+		       t.collation_connection AS collation_connection,
+		       t.database_collation AS database_collation
+		FROM   information_schema.triggers t
+		WHERE  t.trigger_schema = ?
+		ORDER BY t.event_object_table, t.action_timing, t.event_manipulation, t.action_order`
+	query = stripQueryHints(query, flavor)
+	if err := db.SelectContext(ctx, &rawTriggers, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.triggers for schema %s: %s", schema, err)
+	}
+	triggers := make([]*Trigger, len(rawTriggers))
+	for n, rawTrigger := range rawTriggers {
+		triggers[n] = &Trigger{
+			Name:                rawTrigger.Name,
+			Table:               rawTrigger.Table,
+			Timing:              rawTrigger.Timing,
+			Event:               rawTrigger.Event,
+			Definer:             rawTrigger.Definer,
+			SQLMode:             rawTrigger.SQLMode,
+			CharSetClient:       rawTrigger.CharSetClient,
+			CollationConnection: rawTrigger.CollationConnection,
+			DatabaseCollation:   rawTrigger.DatabaseCollation,
+		}
+		// Rows are ordered by action_order within each table, timing, and event, so
+		// each trigger follows the previous row if it is in the same group
+		if n > 0 && triggers[n].sameGroup(triggers[n-1]) {
+			triggers[n].Follows = triggers[n-1].Name
+		}
+	}
+
+	// information_schema.triggers.action_statement has the same escaping problems
+	// as information_schema.routines, so obtain the body from SHOW CREATE TRIGGER
+	// instead, using multiple goroutines for performance reasons.
+	g, subCtx := errgroup.WithContext(ctx)
+	for n := range triggers {
+		trig := triggers[n] // avoid issues with goroutines and loop iterator values
+		g.Go(func() error {
+			showCreate, err := showCreateTrigger(subCtx, db, trig.Name)
+			if err == nil {
+				showCreate = strings.Replace(showCreate, "\r\n", "\n", -1)
+				err = trig.parseCreateStatement(flavor, schema, showCreate)
+			} else {
+				err = fmt.Errorf("Error executing SHOW CREATE TRIGGER for %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(trig.Name), err)
+			}
+			return err
+		})
+	}
+	return triggers, g.Wait()
+}
+
+func showCreateTrigger(ctx context.Context, db *sqlx.DB, trigger string) (create string, err error) {
+	var createRows []struct {
+		CreateStatement sql.NullString `db:"SQL Original Statement"`
+	}
+	err = db.SelectContext(ctx, &createRows, "SHOW CREATE TRIGGER "+EscapeIdentifier(trigger))
+	if (err == nil && len(createRows) != 1) || IsDatabaseError(err, mysqlerr.ER_TRG_DOES_NOT_EXIST) {
+		err = sql.ErrNoRows
+	} else if err == nil {
+		create = createRows[0].CreateStatement.String
+	}
+	return
+}
+
 func showCreateRoutine(ctx context.Context, db *sqlx.DB, routine string, ot ObjectType) (create string, err error) {
 	query := fmt.Sprintf("SHOW CREATE %s %s", ot.Caps(), EscapeIdentifier(routine))
 	if ot == ObjectTypeProc {
@@ -76,15 +76,17 @@ func (s TengoIntegrationSuite) TestInstanceSchemaIntrospection(t *testing.T) {
 	} else {
 		// Test index order correction, even if no test image is using new data dict
 		aTableFromDB.SecondaryIndexes[0], aTableFromDB.SecondaryIndexes[1], aTableFromDB.SecondaryIndexes[2] = aTableFromDB.SecondaryIndexes[2], aTableFromDB.SecondaryIndexes[0], aTableFromDB.SecondaryIndexes[1]
-		fixIndexOrder(aTableFromDB)
-		if aTableFromDB.GeneratedCreateStatement(flavor) != aTableFromDB.CreateStatement {
+		if err := fixIndexOrder(aTableFromDB); err != nil {
+			t.Errorf("Unexpected error from fixIndexOrder: %v", err)
+		} else if aTableFromDB.GeneratedCreateStatement(flavor) != aTableFromDB.CreateStatement {
 			t.Error("fixIndexOrder did not behave as expected")
 		}
 
 		// Test foreign key order correction, even if no test image lacks sorted FKs
 		aTableFromDB.ForeignKeys[0], aTableFromDB.ForeignKeys[1], aTableFromDB.ForeignKeys[2] = aTableFromDB.ForeignKeys[2], aTableFromDB.ForeignKeys[0], aTableFromDB.ForeignKeys[1]
-		fixForeignKeyOrder(aTableFromDB)
-		if aTableFromDB.GeneratedCreateStatement(flavor) != aTableFromDB.CreateStatement {
+		if err := fixForeignKeyOrder(aTableFromDB); err != nil {
+			t.Errorf("Unexpected error from fixForeignKeyOrder: %v", err)
+		} else if aTableFromDB.GeneratedCreateStatement(flavor) != aTableFromDB.CreateStatement {
 			t.Error("fixForeignKeyOrder did not behave as expected")
 		}
 
@@ -387,6 +389,77 @@ func TestFixFulltextIndexParsers(t *testing.T) {
 	}
 }
 
+// TestFixIndexExpressionMultiValued confirms that MySQL 8 multi-valued index
+// expressions are corrected from SHOW CREATE TABLE when I_S mangles them.
+func TestFixIndexExpressionMultiValued(t *testing.T) {
+	flavor := FlavorMySQL80.Dot(17)
+	table := anotherTableForFlavor(flavor)
+	table.Columns = append(table.Columns, &Column{Name: "custinfo", TypeInDB: "json", Nullable: true, Default: "NULL"})
+	zips := &Index{
+		Name: "zips",
+		Type: "BTREE",
+		Parts: []IndexPart{
+			{Expression: "cast(json_extract(`custinfo`,_utf8mb4'$.zip') as unsigned array)"},
+			{ColumnName: "film_name"},
+		},
+	}
+	tags := &Index{
+		Name:  "tags",
+		Type:  "BTREE",
+		Parts: []IndexPart{{Expression: "cast(json_extract(`custinfo`,_utf8mb4'$.tags') as char(20) array)"}},
+	}
+	table.SecondaryIndexes = append(table.SecondaryIndexes, zips, tags)
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	for _, expected := range []string{
+		"  KEY `zips` ((cast(json_extract(`custinfo`,_utf8mb4'$.zip') as unsigned array)),`film_name`),\n",
+		"  KEY `tags` ((cast(json_extract(`custinfo`,_utf8mb4'$.tags') as char(20) array)))\n",
+	} {
+		if !strings.Contains(table.CreateStatement, expected) {
+			t.Errorf("Generated CREATE unexpectedly does not contain %q:\n%s", expected, table.CreateStatement)
+		}
+	}
+
+	// Simulate I_S mangling the escaping of single quotes in the expressions
+	zips.Parts[0].Expression = "cast(json_extract(`custinfo`,_utf8mb4\\'$.zip\\') as unsigned array)"
+	tags.Parts[0].Expression = "cast(json_extract(`custinfo`,_utf8mb4\\'$.tags\\') as char(20) array)"
+	fixIndexExpression(&table, flavor)
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+}
+
+func TestFixVectorIndexOptions(t *testing.T) {
+	flavor := FlavorMariaDB114
+	table := anotherTableForFlavor(flavor)
+	table.Columns = append(table.Columns, &Column{Name: "embedding", TypeInDB: "vector(3)"})
+	idx := &Index{
+		Name:          "vidx",
+		Parts:         []IndexPart{{ColumnName: "embedding"}},
+		Type:          "VECTOR",
+		VectorOptions: "`M`=8 `DISTANCE`=cosine",
+	}
+	if expected, actual := "VECTOR KEY `vidx` (`embedding`) `M`=8 `DISTANCE`=cosine", idx.Definition(flavor); actual != expected {
+		t.Errorf("Index.Definition() expected %q, instead found %q", expected, actual)
+	}
+	table.SecondaryIndexes = append(table.SecondaryIndexes, idx)
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	idx.VectorOptions = ""
+	fixVectorIndexOptions(&table, flavor)
+	if idx.VectorOptions != "`M`=8 `DISTANCE`=cosine" {
+		t.Errorf("fixVectorIndexOptions unexpectedly set options to %q", idx.VectorOptions)
+	}
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+
+	// Confirm no options = no change from fix
+	table.CreateStatement = strings.Replace(table.CreateStatement, " `M`=8 `DISTANCE`=cosine", "", 1)
+	fixVectorIndexOptions(&table, flavor)
+	if idx.VectorOptions != "" {
+		t.Errorf("fixVectorIndexOptions unexpectedly set options to %q", idx.VectorOptions)
+	}
+}
+
 // TestFixBlobDefaultExpression confirms CREATE TABLE parsing works for blob/
 // text default expressions in versions which omit them from information_schema.
 func TestFixBlobDefaultExpression(t *testing.T) {
@@ -480,3 +553,200 @@ func TestFixShowCharSets(t *testing.T) {
 		t.Errorf("Mismatch between generated CREATE statement and SHOW.\nGenerated:\n%s\n\nSHOW:\n%s\n", gen, table.CreateStatement)
 	}
 }
+
+// TestFixupsSpecialIdentifiers confirms that the SHOW CREATE TABLE fixups
+// properly handle identifiers containing backticks, newlines, and multi-byte
+// characters, returning a *FixupError rather than panicking when the ordering
+// cannot be determined.
+func TestFixupsSpecialIdentifiers(t *testing.T) {
+	stmt := strings.ReplaceAll(`CREATE TABLE ~odd names~ (
+  ~id~ int NOT NULL,
+  ~🐬~ varchar(20) CHARACTER SET latin1 DEFAULT NULL,
+  ~new
+line~ varchar(20) CHARACTER SET latin1 COLLATE latin1_bin DEFAULT NULL,
+  ~ü~ varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,
+  PRIMARY KEY (~id~),
+  KEY ~back~~tick~ (~🐬~),
+  KEY ~idx
+🐬~ (~new
+line~),
+  KEY ~plain~ (~id~,~🐬~)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci`, "~", "`")
+	table := &Table{
+		Name:               "odd names",
+		Engine:             "InnoDB",
+		CharSet:            "utf8mb4",
+		Collation:          "utf8mb4_0900_ai_ci",
+		CollationIsDefault: true,
+		Columns: []*Column{
+			{Name: "id", TypeInDB: "int", Default: ""},
+			{Name: "🐬", TypeInDB: "varchar(20)", Nullable: true, Default: "NULL", CharSet: "latin1", Collation: "latin1_swedish_ci", CollationIsDefault: true},
+			{Name: "new\nline", TypeInDB: "varchar(20)", Nullable: true, Default: "NULL", CharSet: "latin1", Collation: "latin1_bin"},
+			{Name: "ü", TypeInDB: "varchar(20)", Nullable: true, Default: "NULL", CharSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci", CollationIsDefault: true},
+		},
+		PrimaryKey: &Index{Name: "PRIMARY", PrimaryKey: true, Unique: true, Type: "BTREE", Parts: []IndexPart{{ColumnName: "id"}}},
+		SecondaryIndexes: []*Index{
+			{Name: "plain", Type: "BTREE", Parts: []IndexPart{{ColumnName: "id"}, {ColumnName: "🐬"}}},
+			{Name: "idx\n🐬", Type: "BTREE", Parts: []IndexPart{{ColumnName: "new\nline"}}},
+			{Name: "back`tick", Type: "BTREE", Parts: []IndexPart{{ColumnName: "🐬"}}},
+		},
+		CreateStatement: stmt,
+	}
+	flavor := FlavorMySQL80.Dot(30)
+
+	// The index name containing a newline cannot be matched line-by-line, so
+	// fixIndexOrder must return an error without modifying the table
+	err := fixIndexOrder(table)
+	if fe, ok := err.(*FixupError); !ok || fe.Table != table.Name {
+		t.Errorf("Expected fixIndexOrder to return a *FixupError, instead found %v", err)
+	} else if table.SecondaryIndexes[0].Name != "plain" || table.SecondaryIndexes[2].Name != "back`tick" {
+		t.Error("Expected fixIndexOrder to leave table unmodified upon error")
+	}
+
+	// Without the problematic index, reordering should succeed, including for
+	// the index name containing a backtick
+	table.SecondaryIndexes = []*Index{table.SecondaryIndexes[0], table.SecondaryIndexes[2]}
+	table.CreateStatement = strings.Replace(stmt, "  KEY `idx\n🐬` (`new\nline`),\n", "", 1)
+	if err := fixIndexOrder(table); err != nil {
+		t.Errorf("Unexpected error from fixIndexOrder: %v", err)
+	} else if table.SecondaryIndexes[0].Name != "back`tick" || table.SecondaryIndexes[1].Name != "plain" {
+		t.Errorf("fixIndexOrder did not behave as expected: found %s, %s", table.SecondaryIndexes[0].Name, table.SecondaryIndexes[1].Name)
+	}
+
+	// fixShowCharSets must locate column lines by name, since the previous
+	// column's name contains a newline
+	fixShowCharSets(table)
+	if col := table.Columns[3]; !col.ForceShowCharSet || !col.ForceShowCollation {
+		t.Errorf("Unexpected result from fixShowCharSets for column %q: %+v", col.Name, col)
+	}
+	if gen := table.GeneratedCreateStatement(flavor); gen != table.CreateStatement {
+		t.Errorf("Mismatch between generated CREATE statement and SHOW.\nGenerated:\n%s\n\nSHOW:\n%s\n", gen, table.CreateStatement)
+	}
+
+	// fixPerconaColCompression must unescape captured names, and ignore lines
+	// which don't correspond to a known column
+	table.Columns[1].Name = "back`tick"
+	table.CreateStatement = "CREATE TABLE `t` (\n" +
+		"  `back``tick` text /*!50633 COLUMN_FORMAT COMPRESSED */,\n" +
+		"  `unknown` text /*!50633 COLUMN_FORMAT COMPRESSED */\n" +
+		") ENGINE=InnoDB"
+	fixPerconaColCompression(table)
+	if table.Columns[1].Compression != "COMPRESSED" {
+		t.Errorf("Expected fixPerconaColCompression to handle escaped backtick, instead found compression %q", table.Columns[1].Compression)
+	}
+
+	// A table with a fixup error must be reported as unsupported, with the
+	// error as its reason
+	table.fixupErr = &FixupError{Table: table.Name, Fixup: "index order", Reason: "testing"}
+	table.UnsupportedDDL = true
+	if details := table.UnsupportedDetails(flavor); details == nil || details.Reason != table.fixupErr.Error() {
+		t.Errorf("Unexpected UnsupportedDetails: %+v", details)
+	}
+}
+
+func TestFixForeignKeyOrderSpecialIdentifiers(t *testing.T) {
+	table := &Table{
+		Name: "fks",
+		ForeignKeys: []*ForeignKey{
+			{Name: "fk`b"},
+			{Name: "fk_ünï"},
+		},
+		CreateStatement: "CREATE TABLE `fks` (\n" +
+			"  CONSTRAINT `fk_ünï` FOREIGN KEY (`a`) REFERENCES `x` (`a`),\n" +
+			"  CONSTRAINT `fk``b` FOREIGN KEY (`b`) REFERENCES `x` (`b`)\n" +
+			") ENGINE=InnoDB",
+	}
+	if err := fixForeignKeyOrder(table); err != nil {
+		t.Fatalf("Unexpected error from fixForeignKeyOrder: %v", err)
+	}
+	if table.ForeignKeys[0].Name != "fk_ünï" || table.ForeignKeys[1].Name != "fk`b" {
+		t.Errorf("fixForeignKeyOrder did not behave as expected: found %s, %s", table.ForeignKeys[0].Name, table.ForeignKeys[1].Name)
+	}
+
+	// Unknown or extra foreign key names result in an error
+	table.CreateStatement = strings.Replace(table.CreateStatement, "fk``b", "fk_other", 1)
+	if err := fixForeignKeyOrder(table); err == nil {
+		t.Error("Expected fixForeignKeyOrder to return an error for unknown foreign key, but it did not")
+	}
+	if table.ForeignKeys[0].Name != "fk_ünï" || table.ForeignKeys[1].Name != "fk`b" {
+		t.Error("Expected fixForeignKeyOrder to leave table unmodified upon error")
+	}
+}
+
+func TestFixCollationsJoin(t *testing.T) {
+	query := `
+		SELECT t.table_name AS table_name
+		FROM   information_schema.tables t
+		JOIN   information_schema.collations c ON t.table_collation = c.collation_name`
+	for _, flavor := range []Flavor{FlavorMySQL80, FlavorMariaDB109, FlavorTiDB75} {
+		if actual := fixCollationsJoin(query, flavor); actual != query {
+			t.Errorf("Expected query to be unchanged for %s, instead found %q", flavor, actual)
+		}
+	}
+	for _, flavor := range []Flavor{FlavorMariaDB1011, FlavorMariaDB114.Dot(3)} {
+		if actual := fixCollationsJoin(query, flavor); !strings.Contains(actual, "collation_character_set_applicability c ON t.table_collation = c.full_collation_name") {
+			t.Errorf("Expected query to join on full_collation_name for %s, instead found %q", flavor, actual)
+		}
+	}
+}
+
+func TestFixSystemVersioningColumns(t *testing.T) {
+	flavor := FlavorMariaDB106
+	table := anotherTableForFlavor(flavor)
+	table.SystemVersioned = true
+	table.Columns = append(table.Columns,
+		&Column{Name: "notes", TypeInDB: "text", Nullable: true, Default: "NULL", CharSet: "latin1", Collation: "latin1_swedish_ci", CollationIsDefault: true, WithoutVersioning: true},
+		&Column{Name: "row_start", TypeInDB: "timestamp(6)", VersioningRow: "START", Invisible: true},
+		&Column{Name: "row_end", TypeInDB: "timestamp(6)", VersioningRow: "END", Invisible: true},
+	)
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	for _, expected := range []string{
+		"  `notes` text DEFAULT NULL WITHOUT SYSTEM VERSIONING,\n",
+		"  `row_start` timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE,\n",
+		"  `row_end` timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE,\n",
+		"  PERIOD FOR SYSTEM_TIME (`row_start`, `row_end`)\n",
+		" WITH SYSTEM VERSIONING",
+	} {
+		if !strings.Contains(table.CreateStatement, expected) {
+			t.Errorf("Generated CREATE unexpectedly does not contain %q:\n%s", expected, table.CreateStatement)
+		}
+	}
+
+	// Simulate the information_schema representation, and confirm the fixup
+	// restores the original values
+	table.Columns[2].WithoutVersioning = false
+	table.Columns[3].VersioningRow, table.Columns[3].GenerationExpr = "", "ROW START"
+	table.Columns[4].VersioningRow, table.Columns[4].GenerationExpr = "", "ROW END"
+	fixSystemVersioningColumns(&table)
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+}
+
+func TestFixSecondaryEngine(t *testing.T) {
+	flavor := FlavorMySQL80
+	table := anotherTableForFlavor(flavor)
+	table.SecondaryEngine = "RAPID"
+	table.Columns = append(table.Columns,
+		&Column{Name: "notes", TypeInDB: "blob", Nullable: true, Default: "NULL", NotSecondary: true},
+		&Column{Name: "score", TypeInDB: "int", Nullable: true, Default: "NULL", Comment: "hi", SecondaryEngineAttribute: `{"encoding": "VARLEN"}`},
+	)
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	for _, expected := range []string{
+		"  `notes` blob DEFAULT NULL NOT SECONDARY,\n",
+		"  `score` int DEFAULT NULL COMMENT 'hi' /*!80021 SECONDARY_ENGINE_ATTRIBUTE='{\"encoding\": \"VARLEN\"}' */,\n",
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1 SECONDARY_ENGINE=RAPID",
+	} {
+		if !strings.Contains(table.CreateStatement, expected) {
+			t.Errorf("Generated CREATE unexpectedly does not contain %q:\n%s", expected, table.CreateStatement)
+		}
+	}
+
+	table.SecondaryEngine = ""
+	table.Columns[2].NotSecondary = false
+	table.Columns[3].SecondaryEngineAttribute = ""
+	fixSecondaryEngine(&table)
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+}
@@ -11,13 +11,17 @@ import (
 	"unicode/utf8"
 )
 
-// MalformedSQLError represents a fatal problem parsing or lexing SQL: the input
-// contains an unterminated quote or unterminated multi-line comment.
+// MalformedSQLError represents a problem parsing or lexing SQL. This is fatal
+// if the input contains an unterminated quote or unterminated multi-line
+// comment, since the remainder of the input cannot be tokenized. Other problems
+// only affect a single statement, in which case the error is stored in the
+// Statement's Err field, and ParseStatements returns a StatementErrors.
 type MalformedSQLError struct {
 	str        string
 	filePath   string
 	lineNumber int
 	colNumber  int
+	snippet    string // beginning of the problematic text, for context in error messages
 }
 
 // Error satisfies the builtin error interface.
@@ -37,9 +41,34 @@ func (mse *MalformedSQLError) Error() string {
 			parts = append(parts, fmt.Sprintf(", column %d", mse.colNumber))
 		}
 	}
+	if mse.snippet != "" {
+		parts = append(parts, fmt.Sprintf(", near %q", mse.snippet))
+	}
 	return strings.Join(parts, "")
 }
 
+// Position returns the file path, line number, and column number where the
+// problem was found. The file path may be blank if the input did not come from
+// a file, and the line and column numbers may be 0 if unknown.
+func (mse *MalformedSQLError) Position() (filePath string, lineNumber, colNumber int) {
+	return mse.filePath, mse.lineNumber, mse.colNumber
+}
+
+// errorSnippet returns the first line of text, truncated if it is lengthy, for
+// use in error messages.
+func errorSnippet(text string) string {
+	const maxRunes = 40
+	text = strings.TrimLeft(text, " \t\r\n")
+	if pos := strings.IndexAny(text, "\r\n"); pos > -1 {
+		text = text[:pos]
+	}
+	if utf8.RuneCountInString(text) > maxRunes {
+		runes := []rune(text)
+		text = string(runes[:maxRunes]) + "..."
+	}
+	return text
+}
+
 // TokenType represents the category of a lexical token.
 type TokenType uint32
 
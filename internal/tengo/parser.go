@@ -14,6 +14,8 @@ import (
 // explicit non-goal. Ability to handle invalid SQL is actually a goal. The
 // purpose of this parser is just to identify statement types, object types,
 // object names, schema name qualifiers, DEFINER clauses, and delimiters.
+// A shallow syntax tree of CREATE statements may be obtained separately via
+// Statement.AST; see ast.go.
 
 // Token represents a lexical token in a .sql file.
 type Token struct {
@@ -31,14 +33,27 @@ type Token struct {
 // comments and/or whitespace, since any comments and/or whitespace between SQL
 // statements gets split into separate Statement values. Other "statements" are
 // actually client commands (USE, DELIMITER).
+//
+// If any individual statements are malformed in a way which does not prevent
+// parsing of subsequent statements, parsing continues through the rest of the
+// input, and a StatementErrors is returned alongside the full result. Other
+// errors, such as an unterminated quote or comment, halt parsing; in this
+// case the result only includes statements up to that point.
 func ParseStatements(r io.Reader, filePath string) (result []*Statement, err error) {
 	p := newParser(r, filePath, ";")
+	var stmtErrs StatementErrors
 	for {
 		stmt, err := p.nextStatement()
 		if stmt != nil {
 			result = append(result, stmt)
+			if stmt.Err != nil {
+				stmtErrs = append(stmtErrs, stmt.Err)
+			}
 		}
 		if err == io.EOF {
+			if len(stmtErrs) > 0 {
+				return result, stmtErrs
+			}
 			return result, nil
 		} else if err != nil {
 			return result, err
@@ -46,6 +61,20 @@ func ParseStatements(r io.Reader, filePath string) (result []*Statement, err err
 	}
 }
 
+// StatementErrors is returned by ParseStatements if one or more statements
+// were malformed, but parsing was able to continue past them. Each malformed
+// Statement also has its Err field set.
+type StatementErrors []error
+
+// Error satisfies the builtin error interface.
+func (se StatementErrors) Error() string {
+	msgs := make([]string, len(se))
+	for n, err := range se {
+		msgs[n] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
 // ParseStatementsInFile opens the file at filePath and then calls
 // ParseStatements with it as the reader.
 func ParseStatementsInFile(filePath string) (result []*Statement, err error) {
@@ -117,12 +146,17 @@ func init() {
 		"create":    processCreateStatement,
 		"use":       processUseCommand,
 		"delimiter": processDelimiterCommand,
+		"grant":     processGrantStatement,
 	}
 	createProcessors = map[string]statementProcessor{
 		"table":     processCreateTable,
 		"function":  processCreateRoutine,
 		"procedure": processCreateRoutine,
 		"definer":   processCreateWithDefiner,
+		"algorithm": processCreateViewAttribute,
+		"sql":       processCreateViewAttribute,
+		"view":      processCreateView,
+		"trigger":   processCreateTrigger,
 	}
 }
 
@@ -153,7 +187,12 @@ func (p *parser) positionAfterBuffer() (lineNumber, colNumber int) {
 
 func (p *parser) nextStatement() (stmt *Statement, err error) {
 	if p.stmt != nil {
-		panic(fmt.Errorf("parser.nextStatement: at %s:%d:%d, previous statement not closed properly", p.filePath, p.lineNumber, p.colNumber))
+		return nil, &MalformedSQLError{
+			str:        "Internal parser error: previous statement not closed properly",
+			filePath:   p.filePath,
+			lineNumber: p.lineNumber,
+			colNumber:  p.colNumber,
+		}
 	}
 	p.stmt = &Statement{
 		File:            p.filePath,
@@ -221,6 +260,7 @@ func (p *parser) nextToken() (Token, error) {
 		if mse, ok := p.lexer.err.(*MalformedSQLError); ok {
 			mse.filePath = p.filePath
 			mse.lineNumber, mse.colNumber = p.positionAfterBuffer()
+			mse.snippet = errorSnippet(string(val))
 		}
 	}
 
@@ -514,6 +554,13 @@ func processDelimiterCommand(p *parser, _ []Token) (stmt *Statement, err error)
 	return p.finishStatement(), err
 }
 
+// processGrantStatement flags the statement as a GRANT, without attempting to
+// parse it further. Privilege files are interpreted by the grants package.
+func processGrantStatement(p *parser, tokens []Token) (stmt *Statement, err error) {
+	p.stmt.Type = StatementTypeGrant
+	return processUntilDelimiter(p, tokens)
+}
+
 func processCreateStatement(p *parser, tokens []Token) (stmt *Statement, err error) {
 	var processor statementProcessor
 	tokens = p.nextTokens(tokens, 20)
@@ -539,13 +586,16 @@ func processCreateTable(p *parser, tokens []Token) (*Statement, error) {
 		p.stmt.ObjectType = ObjectTypeTable
 	}
 
+	// CREATE TABLE...SELECT is not supported, but this only affects the current
+	// statement, so parsing can continue with subsequent statements
 	matched, tokens := p.skipUntilSequence(tokens, "select")
 	if matched != nil {
-		p.err = &MalformedSQLError{
+		p.stmt.Err = &MalformedSQLError{
 			str:        "Statements of the form CREATE TABLE...SELECT are not supported",
 			filePath:   p.filePath,
-			lineNumber: p.lineNumber,
-			colNumber:  p.colNumber,
+			lineNumber: p.stmt.LineNo,
+			colNumber:  p.stmt.CharNo,
+			snippet:    errorSnippet(p.b.String()),
 		}
 	}
 
@@ -596,6 +646,13 @@ func processCreateWithDefiner(p *parser, tokens []Token) (*Statement, error) {
 
 	// Now delegate to the appropriate processor for the type of create statement
 	// indicated by the next token
+	return processCreateRemainder(p, tokens)
+}
+
+// processCreateRemainder delegates to the CREATE processor indicated by the
+// first token, for use after a processor has consumed an optional clause which
+// may precede the object type.
+func processCreateRemainder(p *parser, tokens []Token) (*Statement, error) {
 	var processor statementProcessor
 	if len(tokens) > 0 && tokens[0].typ == TokenWord {
 		processor = createProcessors[strings.ToLower(tokens[0].val)]
@@ -606,6 +663,46 @@ func processCreateWithDefiner(p *parser, tokens []Token) (*Statement, error) {
 	return processor(p, tokens)
 }
 
+// processCreateViewAttribute consumes an ALGORITHM or SQL SECURITY clause, which
+// may precede the VIEW keyword, and then delegates to the next processor. The
+// DEFINER clause, which may also occur here, is handled by
+// processCreateWithDefiner.
+func processCreateViewAttribute(p *parser, tokens []Token) (*Statement, error) {
+	matched, tokens := p.matchNextSequence(tokens,
+		"algorithm = undefined", "algorithm = merge", "algorithm = temptable",
+		"sql security definer", "sql security invoker")
+	if matched == nil {
+		return processUntilDelimiter(p, tokens) // cannot parse, unexpected tokens
+	}
+	return processCreateRemainder(p, tokens)
+}
+
+func processCreateView(p *parser, tokens []Token) (*Statement, error) {
+	// Attempt to parse object name, skipping past the VIEW token; only set
+	// statement and object types if successful
+	tokens = p.parseObjectNameClause(tokens[1:])
+	if p.stmt.ObjectName != "" {
+		p.stmt.Type = StatementTypeCreate
+		p.stmt.ObjectType = ObjectTypeView
+	}
+	return processUntilDelimiter(p, tokens)
+}
+
+func processCreateTrigger(p *parser, tokens []Token) (*Statement, error) {
+	// Ignore the optional IF NOT EXISTS clause, after skipping past the TRIGGER
+	// token
+	_, tokens = p.matchNextSequence(tokens[1:], "if not exists")
+
+	// Attempt to parse object name; only set statement and object types if
+	// successful
+	tokens = p.parseObjectNameClause(tokens)
+	if p.stmt.ObjectName != "" {
+		p.stmt.Type = StatementTypeCreate
+		p.stmt.ObjectType = ObjectTypeTrigger
+	}
+	return processStoredProgram(p, tokens)
+}
+
 // processStoredProgram parses the definition of a stored program (proc/func/
 // trigger/event) after the initial part of the CREATE statement. This may
 // include args (proc/func), return value (func), and body of the statement,
@@ -189,15 +189,27 @@ func TestParseStatementsInString(t *testing.T) {
 	if stmts, err := ParseStatementsInString(""); err != nil || len(stmts) != 0 {
 		t.Errorf("Unexpected return from ParseStatementsInString: %+v, %v", stmts, err)
 	}
+	if stmts, err := ParseStatementsInString("GRANT SELECT ON `app`.* TO `reader`@`%`;\ngrant usage on *.* to bob;\n"); err != nil || len(stmts) != 2 || stmts[0].Type != StatementTypeGrant || stmts[1].Type != StatementTypeGrant {
+		t.Errorf("Unexpected return from ParseStatementsInString: %+v, %v", stmts, err)
+	} else if stmts[0].Body() != "GRANT SELECT ON `app`.* TO `reader`@`%`" || stmts[0].ObjectKey() != (ObjectKey{}) {
+		t.Errorf("Unexpected Body or ObjectKey for GRANT statement: %+v", *stmts[0])
+	}
 }
 
 func TestParseStatementInString(t *testing.T) {
 	cases := map[string]ObjectKey{
 		"":      {},
 		"x y z": {},
-		"/* hello */\nCREATE TABLE foo (id int);\n":                {},
-		"CREATE TABLE foo (id int);\n":                             {Type: ObjectTypeTable, Name: "foo"},
-		"CREATE TABLE foo (id int);\nCREATE TABLE bar (id int);\n": {Type: ObjectTypeTable, Name: "foo"},
+		"/* hello */\nCREATE TABLE foo (id int);\n":                                                                                          {},
+		"CREATE TABLE foo (id int);\n":                                                                                                       {Type: ObjectTypeTable, Name: "foo"},
+		"CREATE TABLE foo (id int);\nCREATE TABLE bar (id int);\n":                                                                           {Type: ObjectTypeTable, Name: "foo"},
+		"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY DEFINER VIEW `v1` AS select 1 AS `1`":                            {Type: ObjectTypeView, Name: "v1"},
+		"create view v2 as select * from foo":                                                                                                {Type: ObjectTypeView, Name: "v2"},
+		"CREATE SQL SECURITY INVOKER VIEW analytics.v3 AS SELECT 1;\n":                                                                       {Type: ObjectTypeView, Name: "v3"},
+		"CREATE algorithm = merge DEFINER=CURRENT_USER VIEW v4 AS SELECT 1":                                                                  {Type: ObjectTypeView, Name: "v4"},
+		"CREATE ALGORITHM=BOGUS VIEW v5 AS SELECT 1":                                                                                         {},
+		"CREATE DEFINER=`root`@`%` TRIGGER `tr1` BEFORE INSERT ON `actor` FOR EACH ROW SET NEW.last_update = NOW()":                          {Type: ObjectTypeTrigger, Name: "tr1"},
+		"create trigger if not exists analytics.tr2 after delete on foo for each row follows tr1 begin delete from bar where id=old.id; end": {Type: ObjectTypeTrigger, Name: "tr2"},
 	}
 	for input, expected := range cases {
 		if actual := ParseStatementInString(input).ObjectKey(); actual != expected {
@@ -271,3 +283,108 @@ func BenchmarkParseStatementsInFile(b *testing.B) {
 		}
 	}
 }
+
+func TestParseStatementsErrorRecovery(t *testing.T) {
+	input := "CREATE TABLE one (id int);\nCREATE TABLE two AS\n  SELECT * FROM one;\nCREATE TABLE three (id int);\n"
+	statements, err := ParseStatements(strings.NewReader(input), "recover.sql")
+	stmtErrs, ok := err.(StatementErrors)
+	if !ok || len(stmtErrs) != 1 {
+		t.Fatalf("Expected StatementErrors with 1 error, instead found %T %v", err, err)
+	}
+	var creates []string
+	for _, stmt := range statements {
+		if stmt.Type == StatementTypeCreate {
+			creates = append(creates, stmt.ObjectName)
+		}
+	}
+	if strings.Join(creates, ",") != "one,two,three" {
+		t.Errorf("Expected parsing to continue past malformed statement, instead found creates %v", creates)
+	}
+	var malformed []*Statement
+	for _, stmt := range statements {
+		if stmt.Err != nil {
+			malformed = append(malformed, stmt)
+		}
+	}
+	if len(malformed) != 1 || malformed[0].ObjectName != "two" || malformed[0].Err != stmtErrs[0] {
+		t.Fatalf("Expected exactly one malformed statement for table two, instead found %+v", malformed)
+	}
+	mse, ok := malformed[0].Err.(*MalformedSQLError)
+	if !ok {
+		t.Fatalf("Expected *MalformedSQLError, instead found %T", malformed[0].Err)
+	}
+	if file, line, col := mse.Position(); file != "recover.sql" || line != 2 || col != 1 {
+		t.Errorf("Unexpected error position %s:%d:%d", file, line, col)
+	}
+	if msg := mse.Error(); !strings.Contains(msg, `near "CREATE TABLE two AS"`) {
+		t.Errorf("Expected error message to include snippet of first line of statement, instead found %q", msg)
+	}
+
+	// Fatal errors halt parsing, but still include a snippet
+	_, err = ParseStatementsInString("CREATE TABLE one (id int);\nCREATE TABLE `two (id int);\nCREATE TABLE three (id int);\n")
+	if mse, ok := err.(*MalformedSQLError); !ok {
+		t.Errorf("Expected *MalformedSQLError, instead found %T %v", err, err)
+	} else if _, line, col := mse.Position(); line != 2 || col != 14 || mse.snippet != "`two (id int);" {
+		t.Errorf("Unexpected position or snippet in error: %v", err)
+	}
+}
+
+func TestErrorSnippet(t *testing.T) {
+	cases := map[string]string{
+		"":                             "",
+		"  \n\tCREATE TABLE foo\n(id)": "CREATE TABLE foo",
+		"abc\r\ndef":                   "abc",
+		strings.Repeat("🐬", 50):        strings.Repeat("🐬", 40) + "...",
+	}
+	for input, expected := range cases {
+		if actual := errorSnippet(input); actual != expected {
+			t.Errorf("Expected errorSnippet(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
+func FuzzParseStatements(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"CREATE TABLE foo (id int);\n",
+		"USE `db`\nCREATE TABLE `a``b` (id int);",
+		"DELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; END//\nDELIMITER ;\n",
+		"CREATE DEFINER=`root`@`%` FUNCTION f() RETURNS int RETURN 1;",
+		"CREATE TABLE t AS SELECT 1; CREATE TABLE u (id int);",
+		"CREATE TABLE `unterminated (id int);",
+		"/* unterminated comment",
+		"\uFEFF-- comment\nCREATE TABLE x (y int)",
+		"CREATE OR REPLACE TABLE t (id int) ENGINE=Aria PAGE_CHECKSUM=1;",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		statements, err := ParseStatementsInString(input)
+		_, recoverable := err.(StatementErrors)
+		if err != nil && !recoverable {
+			if _, ok := err.(*MalformedSQLError); !ok {
+				t.Fatalf("Unexpected error type %T: %v", err, err)
+			}
+			return
+		}
+		var b strings.Builder
+		var errCount int
+		prevLine := 1
+		for _, stmt := range statements {
+			b.WriteString(stmt.Text)
+			if stmt.LineNo < prevLine {
+				t.Errorf("Statement line numbers not monotonic: %d after %d", stmt.LineNo, prevLine)
+			}
+			prevLine = stmt.LineNo
+			if stmt.Err != nil {
+				errCount++
+			}
+		}
+		if b.String() != input {
+			t.Errorf("Concatenated statement text does not match input %q", input)
+		}
+		if recoverable && errCount != len(err.(StatementErrors)) {
+			t.Errorf("Expected %d statements with errors, instead found %d", len(err.(StatementErrors)), errCount)
+		}
+	})
+}
@@ -22,9 +22,9 @@ const (
 // populated, the rest of this package does not fully support subpartitioning
 // yet.
 type TablePartitioning struct {
-	Method             string            `json:"method"`              // one of "RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", "LINEAR HASH", "KEY", or "LINEAR KEY"
-	SubMethod          string            `json:"subMethod,omitempty"` // one of "" (no sub-partitioning), "HASH", "LINEAR HASH", "KEY", or "LINEAR KEY"; not fully supported yet
-	Expression         string            `json:"expression"`
+	Method             string            `json:"method"`                  // one of "RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", "LINEAR HASH", "KEY", "LINEAR KEY", or "SYSTEM_TIME" (MariaDB only)
+	SubMethod          string            `json:"subMethod,omitempty"`     // one of "" (no sub-partitioning), "HASH", "LINEAR HASH", "KEY", or "LINEAR KEY"; not fully supported yet
+	Expression         string            `json:"expression"`              // for SYSTEM_TIME, full text of optional INTERVAL or LIMIT clause
 	SubExpression      string            `json:"subExpression,omitempty"` // empty string if no sub-partitioning; not fully supported yet
 	Partitions         []*Partition      `json:"partitions"`
 	ForcePartitionList PartitionListMode `json:"forcePartitionList,omitempty"`
@@ -74,6 +74,12 @@ func (tp *TablePartitioning) Definition(flavor Flavor) string {
 // partitionBy returns the partitioning method and expression, formatted to
 // match SHOW CREATE TABLE's extremely arbitrary, completely inconsistent way.
 func (tp *TablePartitioning) partitionBy(flavor Flavor) string {
+	// MariaDB's SYSTEM_TIME method has no parenthesized expression, just an
+	// optional INTERVAL or LIMIT clause
+	if tp.Method == "SYSTEM_TIME" {
+		return strings.TrimSpace("SYSTEM_TIME " + tp.Expression)
+	}
+
 	method, expr := fmt.Sprintf("%s ", tp.Method), tp.Expression
 
 	if tp.Method == "RANGE COLUMNS" {
@@ -121,7 +127,7 @@ func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlte
 	}
 
 	// Modifications to partition list: ignored for RANGE, RANGE COLUMNS, LIST,
-	// LIST COLUMNS via generation of a no-op placeholder clause. This is done
+	// LIST COLUMNS, and SYSTEM_TIME via generation of a no-op placeholder clause. This is done
 	// to side-step the safety mechanism at the end of Table.Diff() which treats 0
 	// clauses as indicative of an unsupported diff.
 	// For other partitioning methods, changing the partition list is currently
@@ -138,7 +144,7 @@ func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlte
 			}
 		}
 	}
-	if foundPartitionsDiff && (strings.HasPrefix(tp.Method, "RANGE") || strings.HasPrefix(tp.Method, "LIST")) {
+	if foundPartitionsDiff && (strings.HasPrefix(tp.Method, "RANGE") || strings.HasPrefix(tp.Method, "LIST") || tp.Method == "SYSTEM_TIME") {
 		return []TableAlterClause{ModifyPartitions{}}, true
 	}
 	return nil, !foundPartitionsDiff
@@ -148,7 +154,7 @@ func (tp *TablePartitioning) Diff(other *TablePartitioning) (clauses []TableAlte
 type Partition struct {
 	Name    string `json:"name"`
 	SubName string `json:"subName,omitempty"` // empty string if no sub-partitioning; not fully supported yet
-	Values  string `json:"values,omitempty"`  // only populated for RANGE or LIST
+	Values  string `json:"values,omitempty"`  // only populated for RANGE or LIST, or "HISTORY" or "CURRENT" for SYSTEM_TIME
 	Comment string `json:"comment,omitempty"`
 	Engine  string `json:"engine"`
 	DataDir string `json:"dataDir,omitempty"`
@@ -173,6 +179,8 @@ func (p *Partition) Definition(flavor Flavor, method string) string {
 		values = fmt.Sprintf("VALUES LESS THAN (%s) ", p.Values)
 	} else if strings.Contains(method, "LIST") {
 		values = fmt.Sprintf("VALUES IN (%s) ", p.Values)
+	} else if method == "SYSTEM_TIME" && p.Values != "" {
+		values = p.Values + " "
 	}
 
 	var dataDir string
@@ -489,3 +489,49 @@ func unpartitionedTable(flavor Flavor) Table {
 	}
 	return t
 }
+
+func TestSystemTimePartitioning(t *testing.T) {
+	flavor := FlavorMariaDB106
+	table := anotherTableForFlavor(flavor)
+	table.SystemVersioned = true
+	table.Partitioning = &TablePartitioning{
+		Method:     "SYSTEM_TIME",
+		Expression: "INTERVAL 1 WEEK",
+		Partitions: []*Partition{
+			{Name: "p0", Values: "HISTORY", Engine: "InnoDB"},
+			{Name: "p1", Values: "HISTORY", Engine: "InnoDB"},
+			{Name: "pn", Values: "CURRENT", Engine: "InnoDB"},
+		},
+	}
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	expected := " WITH SYSTEM VERSIONING\n PARTITION BY SYSTEM_TIME INTERVAL 1 WEEK\n(PARTITION `p0` HISTORY ENGINE = InnoDB,\n PARTITION `p1` HISTORY ENGINE = InnoDB,\n PARTITION `pn` CURRENT ENGINE = InnoDB)"
+	if !strings.HasSuffix(table.CreateStatement, expected) {
+		t.Fatalf("Generated CREATE does not end with expected partitioning clause:\n%s", table.CreateStatement)
+	}
+
+	// Simulate the information_schema representation, and confirm the fixup
+	// restores the original values
+	table.Partitioning.Expression = ""
+	for _, p := range table.Partitioning.Partitions {
+		p.Values = ""
+	}
+	fixPartitioningEdgeCases(&table, flavor)
+	if actual := table.GeneratedCreateStatement(flavor); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+
+	// Adding a partition should be ignored, just like RANGE or LIST partitioning
+	other := anotherTableForFlavor(flavor)
+	other.SystemVersioned = true
+	other.Partitioning = &TablePartitioning{
+		Method:     "SYSTEM_TIME",
+		Expression: "INTERVAL 1 WEEK",
+		Partitions: append([]*Partition{{Name: "p2", Values: "HISTORY", Engine: "InnoDB"}}, table.Partitioning.Partitions...),
+	}
+	other.CreateStatement = other.GeneratedCreateStatement(flavor)
+	if clauses, supported := table.Diff(&other); !supported || len(clauses) != 1 {
+		t.Errorf("Unexpected result from Diff: %+v, supported=%t", clauses, supported)
+	} else if _, ok := clauses[0].(ModifyPartitions); !ok {
+		t.Errorf("Expected clause of type ModifyPartitions, instead found %T", clauses[0])
+	}
+}
@@ -0,0 +1,88 @@
+package tengo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotReplica is returned by Instance.ReplicationLag if the instance is not
+// configured as a replica.
+var ErrNotReplica = errors.New("instance is not configured as a replica")
+
+// ReplicationLag returns the number of seconds that this instance's
+// replication is behind its source, as reported by Seconds_Behind_Source (or
+// Seconds_Behind_Master in older versions). If the instance replicates from
+// multiple sources, the largest lag is returned. ErrNotReplica is returned if
+// the instance has no replication configured, and an error is also returned if
+// replication is not currently running on any channel.
+func (instance *Instance) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	db, err := instance.CachedConnectionPool("", "")
+	if err != nil {
+		return 0, err
+	}
+	query := "SHOW SLAVE STATUS"
+	if instance.Flavor().Min(FlavorMySQL80.Dot(22)) || instance.Flavor().Min(FlavorMariaDB105) {
+		query = "SHOW REPLICA STATUS"
+	}
+	rows, err := db.QueryxContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var maxLag time.Duration
+	var channels int
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			return 0, err
+		}
+		channels++
+		lag, err := lagFromReplicaStatus(row)
+		if err != nil {
+			return 0, err
+		}
+		if lag > maxLag {
+			maxLag = lag
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	} else if channels == 0 {
+		return 0, ErrNotReplica
+	}
+	return maxLag, nil
+}
+
+// lagFromReplicaStatus extracts the replication lag from a single row of
+// SHOW REPLICA STATUS or SHOW SLAVE STATUS output.
+func lagFromReplicaStatus(row map[string]interface{}) (time.Duration, error) {
+	for _, col := range []string{"Seconds_Behind_Source", "Seconds_Behind_Master"} {
+		value, ok := row[col]
+		if !ok {
+			continue
+		}
+		var str string
+		switch v := value.(type) {
+		case nil:
+			return 0, errors.New("replication is not running")
+		case []byte:
+			str = string(v)
+		case sql.RawBytes:
+			str = string(v)
+		default:
+			str = fmt.Sprint(v)
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse %s value %q: %w", col, str, err)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, errors.New("replica status does not include Seconds_Behind_Source")
+}
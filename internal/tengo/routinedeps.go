@@ -0,0 +1,234 @@
+package tengo
+
+import (
+	"sort"
+	"strings"
+)
+
+// RoutineCycleError is returned by OrderRoutineDiffs when the routines being
+// created reference each other in a cycle, preventing a strict dependency
+// ordering.
+type RoutineCycleError struct {
+	Cycle []ObjectKey // first and last element are the same object
+}
+
+// Error satisfies the builtin error interface.
+func (rce *RoutineCycleError) Error() string {
+	parts := make([]string, len(rce.Cycle))
+	for n, key := range rce.Cycle {
+		parts[n] = key.String()
+	}
+	return "routines reference each other in a cycle: " + strings.Join(parts, " -> ")
+}
+
+// OrderRoutineDiffs returns a copy of diffs reordered such that any routine
+// being created comes after the creation of other routines that its body
+// references. DROPs of routines being removed entirely come first; the DROP
+// portion of a replacement remains immediately before its corresponding
+// CREATE. Ordering is otherwise deterministic, by object type and name.
+//
+// If the routines being created reference each other cyclically, the members
+// of the cycle are ordered by type and name, and a *RoutineCycleError is
+// returned alongside the otherwise-usable ordering. (MySQL and MariaDB resolve
+// routine references at call time rather than creation time, so callers may
+// choose to treat this as a warning.)
+func OrderRoutineDiffs(diffs []*RoutineDiff) ([]*RoutineDiff, error) {
+	result := make([]*RoutineDiff, 0, len(diffs))
+	creates := make(map[ObjectKey]*RoutineDiff)
+	replaceDrops := make(map[ObjectKey]*RoutineDiff)
+	var drops []*RoutineDiff
+	for _, rd := range diffs {
+		if rd.To != nil {
+			creates[rd.ObjectKey()] = rd
+		} else if rd.ForReplace {
+			replaceDrops[rd.ObjectKey()] = rd
+		} else {
+			drops = append(drops, rd)
+		}
+	}
+	sort.SliceStable(drops, func(i, j int) bool {
+		return objectKeyLess(drops[i].ObjectKey(), drops[j].ObjectKey())
+	})
+	result = append(result, drops...)
+
+	// Build the dependency graph among routines being created
+	routines := make(map[ObjectKey]*Routine, len(creates))
+	for key, rd := range creates {
+		routines[key] = rd.To
+	}
+	deps := make(map[ObjectKey][]ObjectKey, len(creates))
+	for key, r := range routines {
+		deps[key] = routineReferences(r, routines)
+	}
+
+	ordered, cycleErr := topoSortObjectKeys(deps)
+	for _, key := range ordered {
+		if drop := replaceDrops[key]; drop != nil {
+			result = append(result, drop)
+			delete(replaceDrops, key)
+		}
+		result = append(result, creates[key])
+	}
+
+	// Any replacement DROP lacking a corresponding CREATE shouldn't be possible,
+	// but retain it rather than silently discarding it
+	for _, rd := range diffs {
+		if replaceDrops[rd.ObjectKey()] == rd {
+			result = append(result, rd)
+		}
+	}
+	if cycleErr != nil {
+		return result, cycleErr
+	}
+	return result, nil
+}
+
+// routineReferences returns the keys of routines in candidates which are
+// referenced by r's body: procedures via CALL, and functions via invocation
+// syntax. Routine names are case-insensitive. Self-references (recursion) are
+// excluded. Results are sorted by type and name.
+func routineReferences(r *Routine, candidates map[ObjectKey]*Routine) []ObjectKey {
+	byLowerName := make(map[ObjectKey]ObjectKey, len(candidates))
+	for key := range candidates {
+		byLowerName[ObjectKey{Type: key.Type, Name: strings.ToLower(key.Name)}] = key
+	}
+	tokens := routineBodyTokens(r.Body)
+	seen := make(map[ObjectKey]bool)
+	var result []ObjectKey
+	for n, tok := range tokens {
+		if tok.typ != TokenWord && tok.typ != TokenIdent {
+			continue
+		}
+		// For schema-qualified names, only the final part is considered, since the
+		// routine's own schema name is not known here
+		if n+1 < len(tokens) && tokens[n+1].val == "." {
+			continue
+		}
+		var typ ObjectType
+		if n > 0 && strings.EqualFold(tokens[n-1].val, "CALL") && tokens[n-1].typ == TokenWord {
+			typ = ObjectTypeProc
+		} else if n > 2 && tokens[n-1].val == "." && strings.EqualFold(tokens[n-3].val, "CALL") {
+			typ = ObjectTypeProc
+		} else if n+1 < len(tokens) && tokens[n+1].val == "(" {
+			typ = ObjectTypeFunc
+		} else {
+			continue
+		}
+		name := tok.val
+		if tok.typ == TokenIdent {
+			name = stripBackticks(name)
+		}
+		lookup := ObjectKey{Type: typ, Name: strings.ToLower(name)}
+		key, ok := byLowerName[lookup]
+		if !ok || key == r.ObjectKey() || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return objectKeyLess(result[i], result[j])
+	})
+	return result
+}
+
+// routineBodyTokens lexes a routine body, returning all tokens other than
+// filler. String literals and comments are therefore never mistaken for
+// routine references. Lexer errors simply end the token stream early.
+func routineBodyTokens(body string) (tokens []Token) {
+	lex := NewLexer(strings.NewReader(body), ";", 8192)
+	for {
+		data, typ, err := lex.Scan()
+		if err != nil { // includes io.EOF
+			return tokens
+		}
+		if typ != TokenFiller {
+			tokens = append(tokens, Token{val: string(data), typ: typ})
+		}
+	}
+}
+
+// topoSortObjectKeys returns the keys of deps ordered such that each key comes
+// after all of the keys it depends on. Among keys whose dependencies are all
+// satisfied, ordering is by type and name. If a cycle exists, the keys which
+// could not be ordered are appended by type and name, and a *RoutineCycleError
+// describing one of the cycles is returned.
+func topoSortObjectKeys(deps map[ObjectKey][]ObjectKey) ([]ObjectKey, error) {
+	remaining := make(map[ObjectKey]int, len(deps))          // count of unsatisfied deps
+	dependents := make(map[ObjectKey][]ObjectKey, len(deps)) // reverse edges
+	for key, keyDeps := range deps {
+		var count int
+		for _, dep := range keyDeps {
+			if _, ok := deps[dep]; ok {
+				count++
+				dependents[dep] = append(dependents[dep], key)
+			}
+		}
+		remaining[key] = count
+	}
+	var ready, result []ObjectKey
+	for key, count := range remaining {
+		if count == 0 {
+			ready = append(ready, key)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			return objectKeyLess(ready[i], ready[j])
+		})
+		key := ready[0]
+		ready = ready[1:]
+		result = append(result, key)
+		delete(remaining, key)
+		for _, dependent := range dependents[key] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(remaining) == 0 {
+		return result, nil
+	}
+	leftover := make([]ObjectKey, 0, len(remaining))
+	for key := range remaining {
+		leftover = append(leftover, key)
+	}
+	sort.Slice(leftover, func(i, j int) bool {
+		return objectKeyLess(leftover[i], leftover[j])
+	})
+	result = append(result, leftover...)
+	return result, &RoutineCycleError{Cycle: findCycle(leftover[0], deps, remaining)}
+}
+
+// findCycle walks dependencies from start, restricted to keys in remaining,
+// until a key repeats. Every key in remaining has at least one unsatisfied
+// dependency which is also in remaining, so this always terminates in a cycle.
+func findCycle(start ObjectKey, deps map[ObjectKey][]ObjectKey, remaining map[ObjectKey]int) []ObjectKey {
+	path := []ObjectKey{start}
+	visited := map[ObjectKey]int{start: 0}
+	cur := start
+	for {
+		var next ObjectKey
+		for _, dep := range deps[cur] {
+			if _, ok := remaining[dep]; ok {
+				next = dep
+				break
+			}
+		}
+		if pos, ok := visited[next]; ok {
+			return append(path[pos:], next)
+		}
+		visited[next] = len(path)
+		path = append(path, next)
+		cur = next
+	}
+}
+
+// objectKeyLess orders ObjectKeys by type and then name.
+func objectKeyLess(a, b ObjectKey) bool {
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Name < b.Name
+}
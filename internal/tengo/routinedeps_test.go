@@ -0,0 +1,122 @@
+package tengo
+
+import (
+	"testing"
+)
+
+func TestRoutineReferences(t *testing.T) {
+	candidates := map[ObjectKey]*Routine{
+		{Type: ObjectTypeProc, Name: "setup"}:   {Name: "setup", Type: ObjectTypeProc},
+		{Type: ObjectTypeProc, Name: "Cleanup"}: {Name: "Cleanup", Type: ObjectTypeProc},
+		{Type: ObjectTypeFunc, Name: "calc"}:    {Name: "calc", Type: ObjectTypeFunc},
+		{Type: ObjectTypeFunc, Name: "unused"}:  {Name: "unused", Type: ObjectTypeFunc},
+	}
+	r := &Routine{
+		Name: "caller",
+		Type: ObjectTypeProc,
+		Body: "BEGIN\n" +
+			"  CALL setup();\n" +
+			"  CALL `mydb`.`cleanup`;\n" +
+			"  SELECT calc (1), 'unused(2)' /* setup() */;\n" +
+			"  -- CALL unused\n" +
+			"  SELECT setup FROM t;\n" +
+			"END",
+	}
+	refs := routineReferences(r, candidates)
+	expected := []ObjectKey{
+		{Type: ObjectTypeFunc, Name: "calc"},
+		{Type: ObjectTypeProc, Name: "Cleanup"},
+		{Type: ObjectTypeProc, Name: "setup"},
+	}
+	if len(refs) != len(expected) {
+		t.Fatalf("Expected references %v, instead found %v", expected, refs)
+	}
+	for n := range refs {
+		if refs[n] != expected[n] {
+			t.Errorf("Expected references %v, instead found %v", expected, refs)
+			break
+		}
+	}
+
+	// Recursive self-references are ignored
+	candidates[r.ObjectKey()] = r
+	r.Body = "BEGIN CALL caller(); END"
+	if refs := routineReferences(r, candidates); len(refs) != 0 {
+		t.Errorf("Expected self-reference to be ignored, instead found %v", refs)
+	}
+}
+
+func TestOrderRoutineDiffs(t *testing.T) {
+	newProc := func(name, body string) *Routine {
+		return &Routine{Name: name, Type: ObjectTypeProc, Body: body}
+	}
+	newFunc := func(name, body string) *Routine {
+		return &Routine{Name: name, Type: ObjectTypeFunc, Body: body}
+	}
+	keysOf := func(diffs []*RoutineDiff) (result []string) {
+		for _, rd := range diffs {
+			result = append(result, rd.DiffType().String()+" "+rd.ObjectKey().String())
+		}
+		return result
+	}
+
+	a := newProc("a", "BEGIN CALL b(); SELECT f1(); END")
+	b := newProc("b", "BEGIN SELECT f2(); END")
+	f1 := newFunc("f1", "RETURN f2() + 1")
+	f2 := newFunc("f2", "RETURN 1")
+	old := newProc("old", "BEGIN END")
+	oldB := newProc("b", "BEGIN SELECT 1; END")
+	diffs := []*RoutineDiff{
+		{To: a},
+		{From: oldB, ForReplace: true},
+		{To: b, ForReplace: true},
+		{To: f1},
+		{From: old},
+		{To: f2},
+	}
+	ordered, err := OrderRoutineDiffs(diffs)
+	if err != nil {
+		t.Fatalf("Unexpected error from OrderRoutineDiffs: %v", err)
+	}
+	expected := []string{
+		"DROP procedure `old`",
+		"CREATE function `f2`",
+		"CREATE function `f1`",
+		"DROP procedure `b`",
+		"CREATE procedure `b`",
+		"CREATE procedure `a`",
+	}
+	actual := keysOf(ordered)
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected ordering %v, instead found %v", expected, actual)
+	}
+	for n := range actual {
+		if actual[n] != expected[n] {
+			t.Fatalf("Expected ordering %v, instead found %v", expected, actual)
+		}
+	}
+
+	// Introduce a cycle: f2 now calls f1, which calls f2
+	f2.Body = "RETURN f1() - 1"
+	ordered, err = OrderRoutineDiffs(diffs)
+	if cycleErr, ok := err.(*RoutineCycleError); !ok {
+		t.Fatalf("Expected *RoutineCycleError, instead found %v", err)
+	} else if len(cycleErr.Cycle) != 3 || cycleErr.Cycle[0] != cycleErr.Cycle[2] {
+		t.Errorf("Unexpected cycle: %v", cycleErr.Cycle)
+	} else if errText := cycleErr.Error(); errText != "routines reference each other in a cycle: function `f1` -> function `f2` -> function `f1`" {
+		t.Errorf("Unexpected error text: %s", errText)
+	}
+	if len(ordered) != len(diffs) {
+		t.Errorf("Expected ordering to still include all %d diffs despite cycle, instead found %v", len(diffs), keysOf(ordered))
+	}
+
+	// NewSchemaDiff should surface the cycle as well
+	sd := NewSchemaDiff(&Schema{Name: "s"}, &Schema{Name: "s", Routines: []*Routine{f1, f2}})
+	if _, ok := sd.RoutineOrderErr.(*RoutineCycleError); !ok {
+		t.Errorf("Expected SchemaDiff.RoutineOrderErr to be a *RoutineCycleError, instead found %v", sd.RoutineOrderErr)
+	}
+	f2.Body = "RETURN 1"
+	if sd := NewSchemaDiff(&Schema{Name: "s"}, &Schema{Name: "s", Routines: []*Routine{f1, f2}}); sd.RoutineOrderErr != nil {
+		t.Errorf("Expected SchemaDiff.RoutineOrderErr to be nil without a cycle, instead found %v", sd.RoutineOrderErr)
+	}
+}
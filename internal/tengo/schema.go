@@ -12,6 +12,8 @@ type Schema struct {
 	Collation string     `json:"defaultCollation"`
 	Tables    []*Table   `json:"tables,omitempty"`
 	Routines  []*Routine `json:"routines,omitempty"`
+	Views     []*View    `json:"views,omitempty"`
+	Triggers  []*Trigger `json:"triggers,omitempty"`
 }
 
 // ObjectKey returns a value useful for uniquely refering to a Schema, for
@@ -90,19 +92,51 @@ func (s *Schema) routinesByNameAndType(ot ObjectType) map[string]*Routine {
 	return result
 }
 
+// ViewsByName returns a mapping of view names to View struct pointers, for all
+// views in the schema.
+func (s *Schema) ViewsByName() map[string]*View {
+	if s == nil {
+		return map[string]*View{}
+	}
+	result := make(map[string]*View, len(s.Views))
+	for _, v := range s.Views {
+		result[v.Name] = v
+	}
+	return result
+}
+
+// TriggersByName returns a mapping of trigger names to Trigger struct pointers,
+// for all triggers in the schema.
+func (s *Schema) TriggersByName() map[string]*Trigger {
+	if s == nil {
+		return map[string]*Trigger{}
+	}
+	result := make(map[string]*Trigger, len(s.Triggers))
+	for _, trig := range s.Triggers {
+		result[trig.Name] = trig
+	}
+	return result
+}
+
 // Objects returns DefKeyers for all objects in the schema, excluding the schema
 // itself. The result is a map, keyed by ObjectKey (type+name).
 func (s *Schema) Objects() map[ObjectKey]DefKeyer {
 	if s == nil {
 		return nil
 	}
-	dict := make(map[ObjectKey]DefKeyer, len(s.Tables)+len(s.Routines))
+	dict := make(map[ObjectKey]DefKeyer, len(s.Tables)+len(s.Routines)+len(s.Views)+len(s.Triggers))
 	for _, table := range s.Tables {
 		dict[table.ObjectKey()] = table
 	}
 	for _, routine := range s.Routines {
 		dict[routine.ObjectKey()] = routine
 	}
+	for _, view := range s.Views {
+		dict[view.ObjectKey()] = view
+	}
+	for _, trig := range s.Triggers {
+		dict[trig.ObjectKey()] = trig
+	}
 	return dict
 }
 
@@ -119,6 +153,10 @@ func (s *Schema) StripMatches(removePatterns []ObjectPattern) {
 			s.Tables = stripMatchingObjects(s.Tables, pattern)
 		case ObjectTypeProc, ObjectTypeFunc:
 			s.Routines = stripMatchingObjects(s.Routines, pattern)
+		case ObjectTypeView:
+			s.Views = stripMatchingObjects(s.Views, pattern)
+		case ObjectTypeTrigger:
+			s.Triggers = stripMatchingObjects(s.Triggers, pattern)
 		}
 	}
 }
@@ -0,0 +1,110 @@
+package tengo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterFlavorBackend(VendorSingleStore, singleStoreFlavorBackend{})
+}
+
+// singleStoreFlavorBackend is the FlavorBackend for SingleStore, whose SHOW
+// CREATE TABLE format differs substantially from MySQL's.
+type singleStoreFlavorBackend struct {
+	BaseFlavorBackend
+}
+
+// FixTable satisfies the TableFixer interface.
+func (singleStoreFlavorBackend) FixTable(t *Table, _ Flavor) {
+	fixSingleStoreTableOptions(t)
+}
+
+// RenderDDL satisfies the FlavorBackend interface.
+func (singleStoreFlavorBackend) RenderDDL(t *Table, flavor Flavor) string {
+	return t.singleStoreCreateStatement(flavor)
+}
+
+var (
+	reSingleStoreKey       = regexp.MustCompile("(?m)^  (SHARD|SORT) KEY (`(?:[^`]|``)+`) \\((.*)\\),?$")
+	reSingleStoreKeyPart   = regexp.MustCompile("`((?:[^`]|``)+)`( DESC)?")
+	reSingleStoreAutoInc   = regexp.MustCompile(`^ AUTO_INCREMENT=\d+`)
+	reSingleStoreTableName = regexp.MustCompile("^CREATE (?:ROWSTORE )?TABLE `(?:[^`]|``)+` \\(\n")
+)
+
+// fixSingleStoreTableOptions parses the table's CREATE string in order to
+// populate SingleStore-specific attributes which aren't available in
+// information_schema: whether the table is a rowstore or columnstore, its shard
+// key and sort key, and its table options. Shard keys and sort keys are removed
+// from t.SecondaryIndexes if information_schema reported them there.
+func fixSingleStoreTableOptions(t *Table) {
+	if !reSingleStoreTableName.MatchString(t.CreateStatement) {
+		return
+	}
+	t.Rowstore = strings.HasPrefix(t.CreateStatement, "CREATE ROWSTORE ")
+	t.ShardKey, t.SortKey = nil, nil
+	for _, matches := range reSingleStoreKey.FindAllStringSubmatch(t.CreateStatement, -1) {
+		idx := &Index{
+			Name:  unescapeIdentifier(matches[2][1 : len(matches[2])-1]),
+			Type:  matches[1],
+			Parts: []IndexPart{},
+		}
+		for _, partMatches := range reSingleStoreKeyPart.FindAllStringSubmatch(matches[3], -1) {
+			idx.Parts = append(idx.Parts, IndexPart{
+				ColumnName: unescapeIdentifier(partMatches[1]),
+				Descending: partMatches[2] != "",
+			})
+		}
+		if idx.Type == "SHARD" {
+			t.ShardKey = idx
+		} else {
+			t.SortKey = idx
+		}
+	}
+	keep := make([]*Index, 0, len(t.SecondaryIndexes))
+	for _, idx := range t.SecondaryIndexes {
+		if (t.ShardKey == nil || idx.Name != t.ShardKey.Name) && (t.SortKey == nil || idx.Name != t.SortKey.Name) {
+			keep = append(keep, idx)
+		}
+	}
+	t.SecondaryIndexes = keep
+
+	// Table options are everything on the final line, aside from the next
+	// auto-increment value and comment, which are tracked in other fields
+	optionsLine := t.CreateStatement[strings.LastIndex(t.CreateStatement, "\n)")+2:]
+	optionsLine = reSingleStoreAutoInc.ReplaceAllString(optionsLine, "")
+	if t.Comment != "" {
+		optionsLine = strings.TrimSuffix(optionsLine, fmt.Sprintf(" COMMENT='%s'", EscapeValueForCreateTable(t.Comment)))
+	}
+	t.CreateOptions = strings.TrimPrefix(optionsLine, " ")
+}
+
+// singleStoreCreateStatement returns a CREATE TABLE statement in the format
+// used by SingleStore's SHOW CREATE TABLE, which omits the storage engine,
+// default character set, and tablespace. Instead, the table's storage type
+// precedes the TABLE keyword, and SingleStore's own table options are stored
+// as-is in t.CreateOptions.
+func (t *Table) singleStoreCreateStatement(flavor Flavor) string {
+	var storageType, autoIncClause, createOptions, comment string
+	if t.Rowstore {
+		storageType = "ROWSTORE "
+	}
+	if t.NextAutoIncrement > 1 {
+		autoIncClause = fmt.Sprintf(" AUTO_INCREMENT=%d", t.NextAutoIncrement)
+	}
+	if t.CreateOptions != "" {
+		createOptions = fmt.Sprintf(" %s", t.CreateOptions)
+	}
+	if t.Comment != "" {
+		comment = fmt.Sprintf(" COMMENT='%s'", EscapeValueForCreateTable(t.Comment))
+	}
+	return fmt.Sprintf("CREATE %sTABLE %s (\n  %s\n)%s%s%s",
+		storageType,
+		EscapeIdentifier(t.Name),
+		strings.Join(t.definitions(flavor), ",\n  "),
+		autoIncClause,
+		createOptions,
+		comment,
+	)
+}
@@ -17,6 +17,7 @@ const (
 	StatementTypeCommand               // currently just USE or DELIMITER
 	StatementTypeCreate
 	StatementTypeAlter // not actually ever parsed yet
+	StatementTypeGrant // GRANT statements, used in privilege files
 	// Other types will be added once they are supported by the package
 )
 
@@ -35,6 +36,7 @@ type Statement struct {
 	ObjectQualifier string
 	Delimiter       string // delimiter in use at the time of statement; not necessarily present in Text though
 	Compound        bool   // if true, this is a compound statement (stored program with a BEGIN block, requiring alternative delimiter)
+	Err             error  // non-nil if the statement is malformed, but in a way which did not prevent parsing subsequent statements
 	nameClause      string // raw version, potentially with schema name qualifier and/or surrounding backticks
 }
 
@@ -22,10 +22,21 @@ type Table struct {
 	Checks             []*Check           `json:"checks,omitempty"`
 	Comment            string             `json:"comment,omitempty"`
 	Tablespace         string             `json:"tablespace,omitempty"`
+	SystemVersioned    bool               `json:"systemVersioned,omitempty"` // MariaDB only
+	SecondaryEngine    string             `json:"secondaryEngine,omitempty"` // MySQL 8 only, e.g. "RAPID" for HeatWave
+	ShardRowIDBits     uint64             `json:"shardRowIdBits,omitempty"`  // TiDB only
+	PreSplitRegions    uint64             `json:"preSplitRegions,omitempty"` // TiDB only; only has an effect at creation time
+	PlacementPolicy    string             `json:"placementPolicy,omitempty"` // TiDB only
+	TTLOptions         []string           `json:"ttlOptions,omitempty"`      // TiDB only, e.g. "TTL_ENABLE='ON'"
+	TiFlashReplicas    uint64             `json:"tiflashReplicas,omitempty"` // TiDB only; not part of CreateStatement
+	Rowstore           bool               `json:"rowstore,omitempty"`        // SingleStore only; false means columnstore
+	ShardKey           *Index             `json:"shardKey,omitempty"`        // SingleStore only
+	SortKey            *Index             `json:"sortKey,omitempty"`         // SingleStore columnstore tables only
 	NextAutoIncrement  uint64             `json:"nextAutoIncrement,omitempty"`
 	Partitioning       *TablePartitioning `json:"partitioning,omitempty"`       // nil if table isn't partitioned
 	UnsupportedDDL     bool               `json:"unsupportedForDiff,omitempty"` // If true, tengo cannot diff this table or auto-generate its CREATE TABLE
 	CreateStatement    string             `json:"showCreateTable"`              // complete SHOW CREATE TABLE obtained from an instance
+	fixupErr           error              // non-nil if introspection could not correct some aspect of the table
 }
 
 // ObjectKey returns a value useful for uniquely refering to a Table within a
@@ -60,7 +71,14 @@ func (t *Table) DropStatement() string {
 // the output of MySQL's SHOW CREATE TABLE statement. But if t.UnsupportedDDL
 // is true, this means the table uses MySQL features that Tengo does not yet
 // support, and so the output of this method will differ from MySQL.
+// Rendering is handled by the flavor's FlavorBackend.
 func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
+	return GetFlavorBackend(flavor).RenderDDL(t, flavor)
+}
+
+// definitions returns the column, index, and constraint definitions which
+// make up the body of the table's CREATE statement, in order.
+func (t *Table) definitions(flavor Flavor) []string {
 	defs := make([]string, len(t.Columns), len(t.Columns)+len(t.SecondaryIndexes)+len(t.ForeignKeys)+len(t.Checks)+1)
 	for n, c := range t.Columns {
 		defs[n] = c.Definition(flavor, t)
@@ -71,12 +89,26 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 	for _, idx := range t.SecondaryIndexes {
 		defs = append(defs, idx.Definition(flavor))
 	}
+	for _, idx := range []*Index{t.ShardKey, t.SortKey} {
+		if idx != nil {
+			defs = append(defs, idx.Definition(flavor))
+		}
+	}
+	if period := t.versioningPeriod(); period != nil {
+		defs = append(defs, fmt.Sprintf("PERIOD FOR SYSTEM_TIME (%s, %s)", EscapeIdentifier(period[0]), EscapeIdentifier(period[1])))
+	}
 	for _, fk := range t.ForeignKeys {
 		defs = append(defs, fk.Definition(flavor))
 	}
 	for _, cc := range t.Checks {
 		defs = append(defs, cc.Definition(flavor))
 	}
+	return defs
+}
+
+// baseCreateStatement returns a CREATE TABLE statement in the format used by
+// SHOW CREATE TABLE in MySQL, MariaDB, and TiDB.
+func (t *Table) baseCreateStatement(flavor Flavor) string {
 	var tablespaceClause string
 	if t.Tablespace != "" {
 		tablespaceClause = fmt.Sprintf(" /*!50100 TABLESPACE %s */", EscapeIdentifier(t.Tablespace))
@@ -92,9 +124,16 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 		charSet = "utf8mb3"
 	}
 	var collate string
-	if !t.CollationIsDefault || (t.CharSet == "utf8mb4" && flavor.Min(FlavorMySQL80)) || flavor.AlwaysShowCollate() {
+	if !t.CollationIsDefault || (t.CharSet == "utf8mb4" && flavor.Min(FlavorMySQL80)) || flavor.AlwaysShowCollate() || flavor.IsTiDB() {
 		collate = fmt.Sprintf(" COLLATE=%s", t.Collation)
 	}
+	if t.ShardRowIDBits > 0 {
+		collate += fmt.Sprintf(" /*T! SHARD_ROW_ID_BITS=%d", t.ShardRowIDBits)
+		if t.PreSplitRegions > 0 {
+			collate += fmt.Sprintf(" PRE_SPLIT_REGIONS=%d", t.PreSplitRegions)
+		}
+		collate += " */"
+	}
 	var createOptions string
 	if t.CreateOptions != "" {
 		createOptions = fmt.Sprintf(" %s", t.CreateOptions)
@@ -103,9 +142,23 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 	if t.Comment != "" {
 		comment = fmt.Sprintf(" COMMENT='%s'", EscapeValueForCreateTable(t.Comment))
 	}
-	result := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s ENGINE=%s%s DEFAULT CHARSET=%s%s%s%s%s",
+	if t.SecondaryEngine != "" {
+		comment += " SECONDARY_ENGINE=" + t.SecondaryEngine
+	}
+	var tidbOptions string
+	if t.PlacementPolicy != "" {
+		tidbOptions = fmt.Sprintf(" /*T![placement] PLACEMENT POLICY=%s */", EscapeIdentifier(t.PlacementPolicy))
+	}
+	for _, opt := range t.TTLOptions {
+		tidbOptions += fmt.Sprintf(" /*T![ttl] %s */", opt)
+	}
+	var versioning string
+	if t.SystemVersioned {
+		versioning = " WITH SYSTEM VERSIONING"
+	}
+	result := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s ENGINE=%s%s DEFAULT CHARSET=%s%s%s%s%s%s%s",
 		EscapeIdentifier(t.Name),
-		strings.Join(defs, ",\n  "),
+		strings.Join(t.definitions(flavor), ",\n  "),
 		tablespaceClause,
 		t.Engine,
 		autoIncClause,
@@ -113,6 +166,8 @@ func (t *Table) GeneratedCreateStatement(flavor Flavor) string {
 		collate,
 		createOptions,
 		comment,
+		tidbOptions,
+		versioning,
 		t.Partitioning.Definition(flavor),
 	)
 	return result
@@ -132,6 +187,30 @@ func (t *Table) UnpartitionedCreateStatement(flavor Flavor) string {
 	return base
 }
 
+// Renamed returns a shallow copy of the table with its name changed to name,
+// including in its CREATE statement. This is intended for reconciling
+// differences in letter case, which are not meaningful on servers using a
+// non-zero lower_case_table_names.
+func (t *Table) Renamed(name string) *Table {
+	renamed := *t
+	renamed.Name = name
+	renamed.CreateStatement = ReplaceCreateTableName(t.CreateStatement, name)
+	return &renamed
+}
+
+// ReplaceCreateTableName returns create, a canonical CREATE TABLE statement as
+// returned by SHOW CREATE TABLE, with its table name replaced by name. If
+// create does not begin with a backtick-quoted table name, it is returned
+// unchanged.
+func ReplaceCreateTableName(create, name string) string {
+	const prefix = "CREATE TABLE `"
+	if !strings.HasPrefix(create, prefix) {
+		return create
+	}
+	oldName := leadingIdentifier(create[len(prefix)-1:])
+	return "CREATE TABLE " + EscapeIdentifier(name) + create[len(prefix)-1+len(oldName):]
+}
+
 // ColumnsByName returns a mapping of column names to Column value pointers,
 // for all columns in the table.
 func (t *Table) ColumnsByName() map[string]*Column {
@@ -183,6 +262,46 @@ func (t *Table) HasAutoIncrement() bool {
 	return false
 }
 
+// splitEncryption returns the value of the table's ENCRYPTION option, if any,
+// along with its remaining create options.
+func (t *Table) splitEncryption() (encryption, otherOptions string) {
+	if !strings.Contains(t.CreateOptions, "ENCRYPTION=") {
+		return "", t.CreateOptions
+	}
+	opts := strings.Split(t.CreateOptions, " ")
+	others := make([]string, 0, len(opts))
+	for _, kv := range opts {
+		if strings.HasPrefix(kv, "ENCRYPTION=") {
+			encryption = strings.TrimPrefix(kv, "ENCRYPTION=")
+		} else {
+			others = append(others, kv)
+		}
+	}
+	return encryption, strings.Join(others, " ")
+}
+
+// versioningPeriod returns the names of the explicitly-defined row start and
+// row end columns of a MariaDB system-versioned table, in that order. If the
+// table isn't system-versioned, or its period columns are implicit (hidden),
+// nil is returned instead.
+func (t *Table) versioningPeriod() []string {
+	if !t.SystemVersioned {
+		return nil
+	}
+	var start, end string
+	for _, col := range t.Columns {
+		if col.VersioningRow == "START" {
+			start = col.Name
+		} else if col.VersioningRow == "END" {
+			end = col.Name
+		}
+	}
+	if start == "" || end == "" {
+		return nil
+	}
+	return []string{start, end}
+}
+
 // ClusteredIndexKey returns which index is used for an InnoDB table's clustered
 // index. This will be the primary key if one exists; otherwise, it will be the
 // first unique key made of only non-nullable, non-expression columns. If there
@@ -242,15 +361,22 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 
 	// If both tables have same output for SHOW CREATE TABLE, we know they're the same.
 	// We do this check prior to the UnsupportedDDL check so that we only emit the
-	// warning if the tables actually changed.
+	// warning if the tables actually changed. TiFlash replica counts aren't part
+	// of SHOW CREATE TABLE, so they must be compared separately.
 	if from.CreateStatement != "" && from.CreateStatement == to.CreateStatement {
-		return []TableAlterClause{}, true
+		return from.diffTiFlashReplicas(to), true
 	}
 
 	if from.UnsupportedDDL || to.UnsupportedDDL {
 		return nil, false
 	}
 
+	// SingleStore cannot change a table's storage type, shard key, or sort key
+	// without recreating the table.
+	if from.Rowstore != to.Rowstore || !from.ShardKey.Equals(to.ShardKey) || !from.SortKey.Equals(to.SortKey) {
+		return nil, false
+	}
+
 	clauses = make([]TableAlterClause, 0)
 
 	// Check for default charset or collation changes first, prior to looking at
@@ -272,7 +398,11 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 	clauses = append(clauses, cc.columnModifications()...)
 	clauses = append(clauses, cc.columnAdds()...)
 
-	// Compare PK
+	// Compare PK. TiDB cannot change whether a primary key is clustered without
+	// recreating the table.
+	if from.PrimaryKey != nil && to.PrimaryKey != nil && from.PrimaryKey.Clustering != to.PrimaryKey.Clustering {
+		return nil, false
+	}
 	if !from.PrimaryKey.Equals(to.PrimaryKey) {
 		if from.PrimaryKey == nil {
 			clauses = append(clauses, AddIndex{Index: to.PrimaryKey})
@@ -372,20 +502,28 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 	// Compare check constraints. Although the order of check constraints has no
 	// functional impact, ordering changes must nonetheless must be detected, as
 	// MariaDB lists checks in creation order for I_S and SHOW CREATE.
+	// Checks which differ only in server-generated names are flagged, so that
+	// StatementModifiers.LaxCheckNaming can suppress them.
 	fromChecks := from.checksByName()
 	toChecks := to.checksByName()
+	renamePairs := checkRenamePairs(from, to)
+	renamedTo := make(map[*Check]bool, len(renamePairs))
+	for _, toCheck := range renamePairs {
+		renamedTo[toCheck] = true
+	}
 	var fromCheckStillExist []*Check // ordered list of checks from "from" that still exist in "to"
 	for _, fromCheck := range from.Checks {
 		if _, stillExists := toChecks[fromCheck.Name]; stillExists {
 			fromCheckStillExist = append(fromCheckStillExist, fromCheck)
 		} else {
-			clauses = append(clauses, DropCheck{Check: fromCheck})
+			_, renameOnly := renamePairs[fromCheck]
+			clauses = append(clauses, DropCheck{Check: fromCheck, renameOnly: renameOnly})
 		}
 	}
 	var reorderChecks bool
 	for n, toCheck := range to.Checks {
 		if fromCheck, existedBefore := fromChecks[toCheck.Name]; !existedBefore {
-			clauses = append(clauses, AddCheck{Check: toCheck})
+			clauses = append(clauses, AddCheck{Check: toCheck, renameOnly: renamedTo[toCheck]})
 			reorderChecks = true
 		} else if fromCheck.Clause != toCheck.Clause {
 			clauses = append(clauses, DropCheck{Check: fromCheck}, AddCheck{Check: toCheck})
@@ -420,14 +558,20 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 		clauses = append(clauses, cai)
 	}
 
-	// Compare create options
-	if from.CreateOptions != to.CreateOptions {
+	// Compare create options. Encryption is compared separately, since changing
+	// it requires rebuilding the table.
+	fromEncryption, fromCreateOptions := from.splitEncryption()
+	toEncryption, toCreateOptions := to.splitEncryption()
+	if fromCreateOptions != toCreateOptions {
 		cco := ChangeCreateOptions{
-			OldCreateOptions: from.CreateOptions,
-			NewCreateOptions: to.CreateOptions,
+			OldCreateOptions: fromCreateOptions,
+			NewCreateOptions: toCreateOptions,
 		}
 		clauses = append(clauses, cco)
 	}
+	if fromEncryption != toEncryption {
+		clauses = append(clauses, ChangeEncryption{NewEncryption: toEncryption})
+	}
 
 	// Compare comment
 	if from.Comment != to.Comment {
@@ -439,6 +583,39 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 		clauses = append(clauses, ChangeTablespace{NewTablespace: to.Tablespace})
 	}
 
+	// Compare secondary engine
+	if from.SecondaryEngine != to.SecondaryEngine {
+		clauses = append(clauses, ChangeSecondaryEngine{NewSecondaryEngine: to.SecondaryEngine})
+	}
+
+	// Compare system versioning. Changing which columns make up the period of an
+	// already-versioned table is not supported.
+	if from.SystemVersioned != to.SystemVersioned {
+		csv := ChangeSystemVersioning{NewSystemVersioned: to.SystemVersioned}
+		if to.SystemVersioned {
+			csv.Period = to.versioningPeriod()
+		}
+		clauses = append(clauses, csv)
+	} else if from.SystemVersioned && strings.Join(from.versioningPeriod(), ",") != strings.Join(to.versioningPeriod(), ",") {
+		return nil, false
+	}
+
+	// Compare TiDB row ID sharding, placement policy, TTL options, and TiFlash
+	// replicas
+	if from.ShardRowIDBits != to.ShardRowIDBits || from.PreSplitRegions != to.PreSplitRegions {
+		clauses = append(clauses, ChangeShardRowIDBits{
+			NewShardRowIDBits: to.ShardRowIDBits,
+			OldShardRowIDBits: from.ShardRowIDBits,
+		})
+	}
+	if from.PlacementPolicy != to.PlacementPolicy {
+		clauses = append(clauses, ChangePlacementPolicy{NewPlacementPolicy: to.PlacementPolicy})
+	}
+	if strings.Join(from.TTLOptions, " ") != strings.Join(to.TTLOptions, " ") {
+		clauses = append(clauses, ChangeTTL{NewTTLOptions: to.TTLOptions})
+	}
+	clauses = append(clauses, from.diffTiFlashReplicas(to)...)
+
 	// Compare partitioning. This must be performed last due to a MySQL requirement
 	// of PARTITION BY / REMOVE PARTITIONING occurring last in a multi-clause ALTER
 	// TABLE.
@@ -462,6 +639,20 @@ func (t *Table) Diff(to *Table) (clauses []TableAlterClause, supported bool) {
 	return clauses, true
 }
 
+// diffTiFlashReplicas returns a ChangeTiFlashReplica clause if to has a
+// different nonzero TiFlash replica count than the receiver. Replicas are never
+// removed automatically, since the count cannot be expressed in a CREATE TABLE
+// and is typically managed separately from the rest of the schema.
+func (t *Table) diffTiFlashReplicas(to *Table) []TableAlterClause {
+	if to.TiFlashReplicas == 0 || to.TiFlashReplicas == t.TiFlashReplicas {
+		return []TableAlterClause{}
+	}
+	return []TableAlterClause{ChangeTiFlashReplica{
+		NewReplicaCount: to.TiFlashReplicas,
+		OldReplicaCount: t.TiFlashReplicas,
+	}}
+}
+
 func (t *Table) compareColumnExistence(other *Table) columnsComparison {
 	self := t // keeping name as t in method definition to satisfy linter
 	cc := columnsComparison{
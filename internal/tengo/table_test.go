@@ -25,6 +25,31 @@ func TestTableGeneratedCreateStatement(t *testing.T) {
 	}
 }
 
+func TestTableRenamed(t *testing.T) {
+	table := aTable(1)
+	renamed := table.Renamed("Actor")
+	if renamed.Name != "Actor" || table.Name != "actor" {
+		t.Errorf("Unexpected names after rename: original %q, renamed %q", table.Name, renamed.Name)
+	}
+	if renamed.CreateStatement != renamed.GeneratedCreateStatement(FlavorUnknown) {
+		t.Errorf("Renamed CREATE does not match generated CREATE\nExpected:\n%s\nFound:\n%s", renamed.GeneratedCreateStatement(FlavorUnknown), renamed.CreateStatement)
+	}
+	if !strings.HasPrefix(renamed.CreateStatement, "CREATE TABLE `Actor` (") {
+		t.Errorf("Unexpected renamed CREATE: %s", renamed.CreateStatement)
+	}
+
+	cases := map[string]string{
+		"CREATE TABLE `a``b` (\n  `id` int\n)": "CREATE TABLE `New` (\n  `id` int\n)",
+		"CREATE TABLE `a` (`b` int)":           "CREATE TABLE `New` (`b` int)",
+		"create table a (b int)":               "create table a (b int)",
+	}
+	for input, expected := range cases {
+		if actual := ReplaceCreateTableName(input, "New"); actual != expected {
+			t.Errorf("Expected ReplaceCreateTableName(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
 func TestTableClusteredIndexKey(t *testing.T) {
 	table := aTable(1)
 	if table.ClusteredIndexKey() == nil || table.ClusteredIndexKey() != table.PrimaryKey {
@@ -1230,6 +1255,384 @@ func TestTableAlterTablespace(t *testing.T) {
 	assertChangeTablespace(explicitFPT, explicitSys, true, "TABLESPACE `innodb_system`")
 }
 
+func TestTableAlterSystemVersioning(t *testing.T) {
+	flavor := FlavorMariaDB106
+	getTable := func(versioned, explicitPeriod bool) *Table {
+		t := anotherTableForFlavor(flavor)
+		t.SystemVersioned = versioned
+		if explicitPeriod {
+			t.Columns = append(t.Columns,
+				&Column{Name: "rs", TypeInDB: "timestamp(6)", VersioningRow: "START"},
+				&Column{Name: "re", TypeInDB: "timestamp(6)", VersioningRow: "END"},
+			)
+		}
+		t.CreateStatement = t.GeneratedCreateStatement(flavor)
+		return &t
+	}
+	assertChangeVersioning := func(a, b *Table, expectClause string, expectUnsafe bool) {
+		t.Helper()
+		tableAlters, supported := a.Diff(b)
+		if !supported || len(tableAlters) == 0 {
+			t.Fatalf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+		}
+		ta, ok := tableAlters[len(tableAlters)-1].(ChangeSystemVersioning)
+		if !ok {
+			t.Fatalf("Incorrect type of alter returned: expected %T, found %T", ta, tableAlters[len(tableAlters)-1])
+		}
+		if actual := ta.Clause(StatementModifiers{Flavor: flavor}); actual != expectClause {
+			t.Errorf("Incorrect ALTER TABLE clause returned: expected %q, found %q", expectClause, actual)
+		}
+		if ta.Unsafe() != expectUnsafe {
+			t.Errorf("Expected Unsafe() to return %t, instead found %t", expectUnsafe, ta.Unsafe())
+		}
+	}
+
+	plain := getTable(false, false)
+	implicit := getTable(true, false)
+	explicit := getTable(true, true)
+	assertChangeVersioning(plain, implicit, "ADD SYSTEM VERSIONING", false)
+	assertChangeVersioning(implicit, plain, "DROP SYSTEM VERSIONING", true)
+	assertChangeVersioning(plain, explicit, "ADD PERIOD FOR SYSTEM_TIME (`rs`, `re`), ADD SYSTEM VERSIONING", false)
+	if td := NewAlterTable(plain, explicit); td == nil {
+		t.Error("Expected non-nil TableDiff")
+	} else if stmt, err := td.Statement(StatementModifiers{Flavor: flavor}); err != nil || !strings.Contains(stmt, "ADD COLUMN `re` timestamp(6) GENERATED ALWAYS AS ROW END, ADD PERIOD") {
+		t.Errorf("Unexpected result from Statement(): %q, %v", stmt, err)
+	}
+
+	// Changing the period columns of an already-versioned table is unsupported
+	if _, supported := implicit.Diff(explicit); supported {
+		t.Error("Expected diff between implicit and explicit period columns to be unsupported")
+	}
+}
+
+func TestTableAlterSecondaryEngine(t *testing.T) {
+	flavor := FlavorMySQL80
+	getTable := func(secondaryEngine string) *Table {
+		t := anotherTableForFlavor(flavor)
+		t.SecondaryEngine = secondaryEngine
+		t.CreateStatement = t.GeneratedCreateStatement(flavor)
+		return &t
+	}
+	none, rapid := getTable(""), getTable("RAPID")
+	cases := []struct {
+		from, to *Table
+		expected string
+	}{
+		{none, rapid, "SECONDARY_ENGINE=RAPID"},
+		{rapid, none, "SECONDARY_ENGINE=NULL"},
+	}
+	for _, c := range cases {
+		tableAlters, supported := c.from.Diff(c.to)
+		if len(tableAlters) != 1 || !supported {
+			t.Errorf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+		} else if actual := tableAlters[0].Clause(StatementModifiers{Flavor: flavor}); actual != c.expected {
+			t.Errorf("Incorrect ALTER TABLE clause returned: expected %q, found %q", c.expected, actual)
+		}
+	}
+
+	// Column-level changes are handled by MODIFY COLUMN
+	notSecondary := getTable("RAPID")
+	notSecondary.Columns[1].NotSecondary = true
+	notSecondary.CreateStatement = notSecondary.GeneratedCreateStatement(flavor)
+	tableAlters, supported := rapid.Diff(notSecondary)
+	if len(tableAlters) != 1 || !supported {
+		t.Errorf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+	} else if actual := tableAlters[0].Clause(StatementModifiers{Flavor: flavor}); actual != "MODIFY COLUMN `film_name` varchar(60) NOT NULL NOT SECONDARY" {
+		t.Errorf("Incorrect ALTER TABLE clause returned: %q", actual)
+	}
+}
+
+func TestTableAlterSRID(t *testing.T) {
+	flavor := FlavorMySQL80
+	getTable := func(srid string) *Table {
+		t := anotherTableForFlavor(flavor)
+		t.Columns = append(t.Columns, &Column{Name: "location", TypeInDB: "point", SRID: srid})
+		t.CreateStatement = t.GeneratedCreateStatement(flavor)
+		return &t
+	}
+	none, wgs84, cartesian := getTable(""), getTable("4326"), getTable("0")
+	if !strings.Contains(wgs84.CreateStatement, "`location` point NOT NULL /*!80003 SRID 4326 */,\n") {
+		t.Errorf("Generated CREATE does not contain expected SRID attribute:\n%s", wgs84.CreateStatement)
+	}
+	cases := []struct {
+		from, to *Table
+		expected string
+	}{
+		{none, wgs84, "MODIFY COLUMN `location` point NOT NULL /*!80003 SRID 4326 */"},
+		{wgs84, cartesian, "MODIFY COLUMN `location` point NOT NULL /*!80003 SRID 0 */"},
+		{cartesian, none, "MODIFY COLUMN `location` point NOT NULL"},
+	}
+	for _, c := range cases {
+		tableAlters, supported := c.from.Diff(c.to)
+		if len(tableAlters) != 1 || !supported {
+			t.Errorf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+		} else if actual := tableAlters[0].Clause(StatementModifiers{Flavor: flavor}); actual != c.expected {
+			t.Errorf("Incorrect ALTER TABLE clause returned: expected %q, found %q", c.expected, actual)
+		}
+	}
+}
+
+func TestTableAlterEncryption(t *testing.T) {
+	flavor := FlavorMySQL80
+	getTable := func(createOptions string) *Table {
+		t := anotherTableForFlavor(flavor)
+		t.CreateOptions = createOptions
+		t.CreateStatement = t.GeneratedCreateStatement(flavor)
+		return &t
+	}
+	plain := getTable("")
+	encrypted := getTable("ENCRYPTION='Y'")
+	compressed := getTable("ROW_FORMAT=COMPRESSED")
+	both := getTable("ROW_FORMAT=COMPRESSED ENCRYPTION='Y'")
+	unencrypted := getTable("ENCRYPTION='N'")
+	cases := []struct {
+		from, to *Table
+		expected []string
+	}{
+		{plain, encrypted, []string{"ENCRYPTION='Y'"}},
+		{encrypted, plain, []string{"ENCRYPTION='N'"}},
+		{encrypted, unencrypted, []string{"ENCRYPTION='N'"}},
+		{compressed, both, []string{"ENCRYPTION='Y'"}},
+		{both, compressed, []string{"ENCRYPTION='N'"}},
+		{encrypted, both, []string{"ROW_FORMAT=COMPRESSED"}},
+		{plain, both, []string{"ROW_FORMAT=COMPRESSED", "ENCRYPTION='Y'"}},
+	}
+	for _, c := range cases {
+		tableAlters, supported := c.from.Diff(c.to)
+		if len(tableAlters) != len(c.expected) || !supported {
+			t.Errorf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+			continue
+		}
+		for n, ta := range tableAlters {
+			if actual := ta.Clause(StatementModifiers{Flavor: flavor}); actual != c.expected[n] {
+				t.Errorf("Incorrect ALTER TABLE clause returned: expected %q, found %q", c.expected[n], actual)
+			}
+		}
+	}
+
+	// Encryption changes require a table rebuild, so they should be forbidden
+	// unless unsafe changes are permitted
+	td := NewAlterTable(plain, encrypted)
+	if _, err := td.Statement(StatementModifiers{Flavor: flavor}); !IsForbiddenDiff(err) {
+		t.Errorf("Expected encryption change to be forbidden without AllowUnsafe, instead err=%v", err)
+	}
+	if stmt, err := td.Statement(StatementModifiers{Flavor: flavor, AllowUnsafe: true}); err != nil || stmt != "ALTER TABLE `"+plain.Name+"` ENCRYPTION='Y'" {
+		t.Errorf("Unexpected result from Statement: %q, %v", stmt, err)
+	}
+}
+
+func TestTableTiDBOptions(t *testing.T) {
+	getTable := func(placementPolicy string, ttlOptions ...string) *Table {
+		t := aTableForFlavor(FlavorTiDB75, 0)
+		pk := *t.PrimaryKey
+		pk.Clustering = "CLUSTERED"
+		t.PrimaryKey = &pk
+		t.PlacementPolicy = placementPolicy
+		t.TTLOptions = ttlOptions
+		t.CreateStatement = t.GeneratedCreateStatement(FlavorTiDB75)
+		return &t
+	}
+	ttl := []string{"TTL=`last_update` + INTERVAL 3 MONTH", "TTL_ENABLE='ON'", "TTL_JOB_INTERVAL='1h'"}
+	plain := getTable("")
+	fancy := getTable("p`1", ttl...)
+	for _, expect := range []string{
+		" /*T![clustered_index] CLUSTERED */,\n",
+		" /*T![placement] PLACEMENT POLICY=`p``1` */ /*T![ttl] TTL=`last_update` + INTERVAL 3 MONTH */ /*T![ttl] TTL_ENABLE='ON' */ /*T![ttl] TTL_JOB_INTERVAL='1h' */",
+		" DEFAULT CHARSET=utf8 COLLATE=utf8_general_ci /*T![placement]",
+	} {
+		if !strings.Contains(fancy.CreateStatement, expect) {
+			t.Errorf("Expected CREATE TABLE to contain %q, but it did not: %s", expect, fancy.CreateStatement)
+		}
+	}
+
+	// Confirm introspection fixup obtains the same values from the CREATE
+	introspected := *fancy
+	pk := *introspected.PrimaryKey
+	pk.Clustering = ""
+	introspected.PrimaryKey = &pk
+	introspected.PlacementPolicy, introspected.TTLOptions = "", nil
+	fixTiDBTableOptions(&introspected)
+	if introspected.GeneratedCreateStatement(FlavorTiDB75) != fancy.CreateStatement {
+		t.Errorf("Introspection fixup did not restore TiDB options; found %q, %v, %q", introspected.PlacementPolicy, introspected.TTLOptions, introspected.PrimaryKey.Clustering)
+	}
+
+	assertClauses := func(from, to *Table, expected ...string) {
+		t.Helper()
+		tableAlters, supported := from.Diff(to)
+		if !supported || len(tableAlters) != len(expected) {
+			t.Errorf("Incorrect result from Table.Diff(): %d alter clauses, supported=%t", len(tableAlters), supported)
+			return
+		}
+		for n := range tableAlters {
+			if actual := tableAlters[n].Clause(StatementModifiers{}); actual != expected[n] {
+				t.Errorf("Incorrect ALTER TABLE clause returned: expected %q, found %q", expected[n], actual)
+			}
+		}
+	}
+	assertClauses(plain, fancy, "PLACEMENT POLICY=`p``1`", strings.Join(ttl, " "))
+	assertClauses(fancy, plain, "PLACEMENT POLICY=DEFAULT", "REMOVE TTL")
+	assertClauses(fancy, getTable("p`1", ttl[0], "TTL_ENABLE='OFF'", ttl[2]), "TTL=`last_update` + INTERVAL 3 MONTH TTL_ENABLE='OFF' TTL_JOB_INTERVAL='1h'")
+
+	// TiFlash replica count changes are only generated for nonzero counts, and
+	// must be run separately from other clauses
+	replicated := *plain
+	replicated.TiFlashReplicas = 2
+	assertClauses(plain, &replicated, "SET TIFLASH REPLICA 2")
+	assertClauses(&replicated, plain)
+	replicatedFancy := *fancy
+	replicatedFancy.TiFlashReplicas = 1
+	assertClauses(&replicated, &replicatedFancy, "PLACEMENT POLICY=`p``1`", strings.Join(ttl, " "), "SET TIFLASH REPLICA 1")
+	if alters := NewAlterTable(&replicated, &replicatedFancy).SplitConflicts(); len(alters) != 2 || len(alters[0].alterClauses) != 2 || len(alters[1].alterClauses) != 1 {
+		t.Errorf("Unexpected result from SplitConflicts: %+v", alters)
+	}
+	if alters := NewAlterTable(plain, &replicated).SplitConflicts(); len(alters) != 1 || len(alters[0].alterClauses) != 1 {
+		t.Errorf("Unexpected result from SplitConflicts: %+v", alters)
+	}
+
+	// Changing primary key clustering is not supported
+	nonclustered := *plain
+	pk = *nonclustered.PrimaryKey
+	pk.Clustering = "NONCLUSTERED"
+	nonclustered.PrimaryKey = &pk
+	nonclustered.CreateStatement = nonclustered.GeneratedCreateStatement(FlavorTiDB75)
+	if _, supported := plain.Diff(&nonclustered); supported {
+		t.Error("Expected change in primary key clustering to be unsupported, but it was not")
+	}
+}
+
+func TestTableTiDBQuirks(t *testing.T) {
+	// Simulate introspection of a TiDB table: columns and indexes come from I_S,
+	// and everything else is parsed from SHOW CREATE TABLE
+	createStmt := "CREATE TABLE `events` (\n" +
+		"  `id` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(5) */,\n" +
+		"  `payload` varchar(100) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T! SHARD_ROW_ID_BITS=4 PRE_SPLIT_REGIONS=2 */ /*T![auto_rand_base] AUTO_RANDOM_BASE=30001 */ COMMENT='hi'"
+	table := &Table{
+		Name:               "events",
+		Engine:             "InnoDB",
+		CharSet:            "utf8mb4",
+		Collation:          "utf8mb4_bin",
+		CollationIsDefault: true,
+		Comment:            "hi",
+		Columns: []*Column{
+			{Name: "id", TypeInDB: "bigint(20)"},
+			{Name: "payload", TypeInDB: "varchar(100)", Nullable: true, Default: "NULL", CharSet: "utf8mb4", Collation: "utf8mb4_bin", CollationIsDefault: true},
+		},
+		PrimaryKey: &Index{
+			Name:       "PRIMARY",
+			Parts:      []IndexPart{{ColumnName: "id"}},
+			PrimaryKey: true,
+			Unique:     true,
+			Type:       "BTREE",
+		},
+		CreateStatement: createStmt,
+	}
+	fixTiDBTableOptions(table)
+	if table.Columns[0].AutoRandom != "AUTO_RANDOM(5)" || table.Columns[1].AutoRandom != "" {
+		t.Errorf("Unexpected AutoRandom values: %q, %q", table.Columns[0].AutoRandom, table.Columns[1].AutoRandom)
+	}
+	if table.ShardRowIDBits != 4 || table.PreSplitRegions != 2 {
+		t.Errorf("Unexpected ShardRowIDBits=%d PreSplitRegions=%d", table.ShardRowIDBits, table.PreSplitRegions)
+	}
+	if strings.Contains(table.CreateStatement, "AUTO_RANDOM_BASE") {
+		t.Errorf("Expected AUTO_RANDOM_BASE to be stripped, but it was not: %s", table.CreateStatement)
+	}
+	if actual := table.GeneratedCreateStatement(FlavorTiDB75); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+
+	// Changes to SHARD_ROW_ID_BITS can be altered, but PRE_SPLIT_REGIONS cannot
+	other := *table
+	other.ShardRowIDBits, other.PreSplitRegions = 6, 0
+	other.CreateStatement = other.GeneratedCreateStatement(FlavorTiDB75)
+	if clauses, supported := table.Diff(&other); !supported || len(clauses) != 1 || clauses[0].Clause(StatementModifiers{}) != "SHARD_ROW_ID_BITS=6" {
+		t.Errorf("Unexpected result from Diff: %+v, supported=%t", clauses, supported)
+	}
+	other.ShardRowIDBits = table.ShardRowIDBits
+	other.CreateStatement = other.GeneratedCreateStatement(FlavorTiDB75)
+	if clauses, supported := table.Diff(&other); !supported || len(clauses) != 1 || clauses[0].Clause(StatementModifiers{}) != "" {
+		t.Errorf("Unexpected result from Diff: %+v, supported=%t", clauses, supported)
+	}
+}
+
+func TestTableSingleStoreOptions(t *testing.T) {
+	// Simulate introspection of a SingleStore table: columns and indexes come
+	// from I_S, and everything else is parsed from SHOW CREATE TABLE
+	createStmt := "CREATE TABLE `metrics` (\n" +
+		"  `id` bigint(20) NOT NULL,\n" +
+		"  `ts` bigint(20) NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  SHARD KEY `__SHARDKEY` (`id`),\n" +
+		"  SORT KEY `ts_sort` (`ts` DESC)\n" +
+		") AUTO_INCREMENT=42 AUTOSTATS_CARDINALITY_MODE=INCREMENTAL AUTOSTATS_HISTOGRAM_MODE=CREATE SQL_MODE='STRICT_ALL_TABLES' COMMENT='hi'"
+	table := &Table{
+		Name:              "metrics",
+		Comment:           "hi",
+		NextAutoIncrement: 42,
+		Columns: []*Column{
+			{Name: "id", TypeInDB: "bigint(20)"},
+			{Name: "ts", TypeInDB: "bigint(20)"},
+		},
+		PrimaryKey: &Index{
+			Name:       "PRIMARY",
+			Parts:      []IndexPart{{ColumnName: "id"}},
+			PrimaryKey: true,
+			Unique:     true,
+			Type:       "BTREE",
+		},
+		SecondaryIndexes: []*Index{
+			{Name: "__SHARDKEY", Parts: []IndexPart{{ColumnName: "id"}}, Type: "BTREE"},
+		},
+		CreateStatement: createStmt,
+	}
+	fixSingleStoreTableOptions(table)
+	if table.Rowstore || table.ShardKey == nil || table.SortKey == nil || len(table.SecondaryIndexes) != 0 {
+		t.Fatalf("Unexpected result from fixSingleStoreTableOptions: %+v", table)
+	}
+	if !table.SortKey.Parts[0].Descending || table.SortKey.Name != "ts_sort" {
+		t.Errorf("Unexpected sort key: %+v", *table.SortKey)
+	}
+	if expected := "AUTOSTATS_CARDINALITY_MODE=INCREMENTAL AUTOSTATS_HISTOGRAM_MODE=CREATE SQL_MODE='STRICT_ALL_TABLES'"; table.CreateOptions != expected {
+		t.Errorf("Expected CreateOptions %q, instead found %q", expected, table.CreateOptions)
+	}
+	if actual := table.GeneratedCreateStatement(FlavorSingleStore80); actual != table.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", table.CreateStatement, actual)
+	}
+
+	// Rowstore tables have no sort key
+	rowstore := *table
+	rowstore.CreateStatement = strings.Replace(createStmt, "CREATE TABLE", "CREATE ROWSTORE TABLE", 1)
+	rowstore.CreateStatement = strings.Replace(rowstore.CreateStatement, "`id`),\n  SORT KEY `ts_sort` (`ts` DESC)", "`id`)", 1)
+	fixSingleStoreTableOptions(&rowstore)
+	if !rowstore.Rowstore || rowstore.ShardKey == nil || rowstore.SortKey != nil {
+		t.Errorf("Unexpected result from fixSingleStoreTableOptions: %+v", rowstore)
+	}
+	if actual := rowstore.GeneratedCreateStatement(FlavorSingleStore80); actual != rowstore.CreateStatement {
+		t.Errorf("Generated CREATE does not match SHOW CREATE.\nExpected:\n%s\nActual:\n%s", rowstore.CreateStatement, actual)
+	}
+
+	// Changing the storage type, shard key, or sort key is not supported, but
+	// other changes may be diffed normally, without ALGORITHM or LOCK clauses
+	if _, supported := table.Diff(&rowstore); supported {
+		t.Error("Expected change in storage type to be unsupported, but it was not")
+	}
+	other := *table
+	other.SortKey = &Index{Name: "ts_sort", Type: "SORT", Parts: []IndexPart{{ColumnName: "ts"}}}
+	other.CreateStatement = other.GeneratedCreateStatement(FlavorSingleStore80)
+	if _, supported := table.Diff(&other); supported {
+		t.Error("Expected change in sort key to be unsupported, but it was not")
+	}
+	other.SortKey = table.SortKey
+	other.Comment = "hello"
+	other.CreateStatement = other.GeneratedCreateStatement(FlavorSingleStore80)
+	td := NewAlterTable(table, &other)
+	mods := StatementModifiers{Flavor: FlavorSingleStore80, AlgorithmClause: "inplace", LockClause: "none"}
+	if stmt, err := td.Statement(mods); err != nil || stmt != "ALTER TABLE `metrics` COMMENT 'hello'" {
+		t.Errorf("Unexpected result from Statement: %q, %v", stmt, err)
+	}
+}
+
 func TestTableAlterUnsupportedTable(t *testing.T) {
 	from, to := unsupportedTable(), unsupportedTable()
 	newCol := &Column{
@@ -1276,3 +1679,36 @@ func BenchmarkColumnModifications(b *testing.B) {
 		cc.columnModifications()
 	}
 }
+
+func TestTableCanonicalCheckNames(t *testing.T) {
+	flavor := FlavorMariaDB105
+	table := aTableForFlavor(flavor, 1)
+	table.Checks = []*Check{
+		{Name: "CONSTRAINT_1", Clause: "alive != 0", Enforced: true},
+		{Name: "named", Clause: "ssn <> '111111111'", Enforced: true},
+		{Name: table.Name + "_chk_1", Clause: "ssn <> '222222222'", Enforced: true},
+		{Name: "CONSTRAINT_2", Clause: "ssn <> '333333333'", Enforced: true},
+	}
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	create := table.CanonicalCheckNames()
+	for _, expected := range []string{
+		"CONSTRAINT `" + table.Name + "_chk_2` CHECK (alive != 0)",
+		"CONSTRAINT `named` CHECK",
+		"CONSTRAINT `" + table.Name + "_chk_1` CHECK (ssn <> '222222222')",
+		"CONSTRAINT `" + table.Name + "_chk_3` CHECK (ssn <> '333333333')",
+	} {
+		if !strings.Contains(create, expected) {
+			t.Errorf("Expected canonical CREATE to contain %q, but it did not:\n%s", expected, create)
+		}
+	}
+	if strings.Contains(create, "CONSTRAINT_") || table.Checks[0].Name != "CONSTRAINT_1" {
+		t.Errorf("Unexpected result from CanonicalCheckNames:\n%s", create)
+	}
+
+	// Already-canonical names should be left as-is
+	table.Checks = table.Checks[1:3]
+	table.CreateStatement = table.GeneratedCreateStatement(flavor)
+	if create := table.CanonicalCheckNames(); create != table.CreateStatement {
+		t.Errorf("Expected CREATE to be unchanged, instead found:\n%s", create)
+	}
+}
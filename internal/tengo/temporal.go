@@ -0,0 +1,82 @@
+package tengo
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// TemporalSettings describes server settings which affect how temporal column
+// defaults are interpreted and displayed. Literal defaults of TIMESTAMP columns
+// are stored in UTC but displayed in the session time zone, and the
+// explicit_defaults_for_timestamp setting controls whether TIMESTAMP columns
+// implicitly receive NOT NULL and CURRENT_TIMESTAMP clauses. Two servers with
+// differing settings will display the same table differently, so workspaces
+// on a separate server should mirror these settings.
+type TemporalSettings struct {
+	TimeZone                     string // session time_zone if it is a named zone, e.g. "America/New_York"; empty if SYSTEM or an offset
+	TimeZoneOffset               string // current UTC offset of the session time_zone, e.g. "+00:00" or "-05:00"
+	ExplicitDefaultsForTimestamp bool
+}
+
+// TemporalSettings queries the instance's session time zone and its current
+// offset, as well as its explicit_defaults_for_timestamp setting, as seen by
+// connections using the instance's default connection parameters. Since
+// explicit_defaults_for_timestamp does not exist in MySQL 5.5 or MariaDB prior
+// to 10.1.8, it is treated as disabled if it cannot be queried, matching the
+// legacy behavior.
+func (instance *Instance) TemporalSettings() (TemporalSettings, error) {
+	var settings TemporalSettings
+	db, err := instance.CachedConnectionPool("", "")
+	if err != nil {
+		return settings, err
+	}
+	var timeZone, offset string
+	if err := db.QueryRow("SELECT @@session.time_zone, TIME_FORMAT(TIMEDIFF(NOW(), UTC_TIMESTAMP()), '%H:%i')").Scan(&timeZone, &offset); err != nil {
+		return settings, err
+	}
+	if timeZone != "SYSTEM" && !strings.HasPrefix(timeZone, "+") && !strings.HasPrefix(timeZone, "-") {
+		settings.TimeZone = timeZone
+	}
+	settings.TimeZoneOffset = normalizeTimeZoneOffset(offset)
+	var explicitDefaults int
+	if err := db.QueryRow("SELECT @@session.explicit_defaults_for_timestamp").Scan(&explicitDefaults); err == nil {
+		settings.ExplicitDefaultsForTimestamp = (explicitDefaults == 1)
+	}
+	return settings, nil
+}
+
+// HasTimeZone returns true if the instance recognizes the supplied time zone,
+// which may be either a UTC offset or a named zone. Named zones are only
+// recognized if the server's time zone tables have been loaded.
+func (instance *Instance) HasTimeZone(timeZone string) (bool, error) {
+	db, err := instance.CachedConnectionPool("", "")
+	if err != nil {
+		return false, err
+	}
+	var converted sql.NullString
+	if err := db.QueryRow("SELECT CONVERT_TZ('2000-01-01 00:00:00', '+00:00', ?)", timeZone).Scan(&converted); err != nil {
+		return false, err
+	}
+	return converted.Valid, nil
+}
+
+// normalizeTimeZoneOffset converts a time difference such as "05:00", "-04:00",
+// or "05:30:00" into the "+HH:MM" format accepted by the time_zone variable.
+func normalizeTimeZoneOffset(offset string) string {
+	offset = strings.TrimSpace(offset)
+	sign := "+"
+	if strings.HasPrefix(offset, "-") || strings.HasPrefix(offset, "+") {
+		sign, offset = offset[0:1], offset[1:]
+	}
+	parts := strings.SplitN(offset, ":", 3)
+	if len(parts) < 2 {
+		parts = append(parts, "00")
+	}
+	if len(parts[0]) < 2 {
+		parts[0] = "0" + parts[0]
+	}
+	if sign == "-" && parts[0] == "00" && parts[1] == "00" {
+		sign = "+"
+	}
+	return sign + parts[0] + ":" + parts[1]
+}
@@ -0,0 +1,61 @@
+package tengo
+
+import (
+	"testing"
+)
+
+func TestNormalizeTimeZoneOffset(t *testing.T) {
+	cases := map[string]string{
+		"00:00":     "+00:00",
+		"-00:00":    "+00:00",
+		"05:00":     "+05:00",
+		"-04:00":    "-04:00",
+		"05:30:00":  "+05:30",
+		"-9:00":     "-09:00",
+		" 13:45 ":   "+13:45",
+		"+02:00":    "+02:00",
+		"-00:30:00": "-00:30",
+	}
+	for input, expected := range cases {
+		if actual := normalizeTimeZoneOffset(input); actual != expected {
+			t.Errorf("Expected normalizeTimeZoneOffset(%q) to return %q, instead found %q", input, expected, actual)
+		}
+	}
+}
+
+func (s TengoIntegrationSuite) TestInstanceTemporalSettings(t *testing.T) {
+	settings, err := s.d.TemporalSettings()
+	if err != nil {
+		t.Fatalf("Unexpected error from TemporalSettings: %v", err)
+	}
+	if settings.TimeZoneOffset != "+00:00" {
+		t.Errorf("Expected Dockerized instance to have UTC time zone, instead found offset %q", settings.TimeZoneOffset)
+	}
+	if settings.TimeZone != "" {
+		t.Errorf("Expected Dockerized instance to use SYSTEM time zone, instead found %q", settings.TimeZone)
+	}
+
+	// Confirm time_zone in connection params is reflected in the offset
+	inst, err := NewInstance("mysql", s.d.BaseDSN+"?time_zone=%27-05%3A00%27")
+	if err != nil {
+		t.Fatalf("Unexpected error from NewInstance: %v", err)
+	}
+	if settings, err = inst.TemporalSettings(); err != nil {
+		t.Fatalf("Unexpected error from TemporalSettings: %v", err)
+	} else if settings.TimeZoneOffset != "-05:00" || settings.TimeZone != "" {
+		t.Errorf("Expected offset -05:00 with no named time zone, instead found %q, %q", settings.TimeZoneOffset, settings.TimeZone)
+	}
+
+	// Offsets are always recognized, but bogus named zones never are
+	if ok, err := s.d.HasTimeZone("+01:00"); !ok || err != nil {
+		t.Errorf("Expected HasTimeZone to recognize an offset, instead found %t, %v", ok, err)
+	}
+	if ok, err := s.d.HasTimeZone("Not/A_Zone"); ok || err != nil {
+		t.Errorf("Expected HasTimeZone to not recognize a bogus zone, instead found %t, %v", ok, err)
+	}
+
+	// explicit_defaults_for_timestamp is enabled by default in MySQL 8
+	if s.d.Flavor().Min(FlavorMySQL80) && !settings.ExplicitDefaultsForTimestamp {
+		t.Error("Expected explicit_defaults_for_timestamp to be enabled in MySQL 8")
+	}
+}
@@ -21,6 +21,8 @@ const (
 	ObjectTypeTable    ObjectType = "table"
 	ObjectTypeProc     ObjectType = "procedure"
 	ObjectTypeFunc     ObjectType = "function"
+	ObjectTypeView     ObjectType = "view"
+	ObjectTypeTrigger  ObjectType = "trigger"
 )
 
 // Caps returns the object type as an uppercase string.
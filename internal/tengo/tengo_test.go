@@ -175,7 +175,10 @@ func flavorTestFiles(flavor Flavor) []string {
 	}
 
 	if flavor.Min(FlavorMySQL80) {
-		result = append(result, "index-mysql8.sql") // functional indexes, descending indexes, invisible indexes
+		result = append(result, "index-mysql8.sql", "srid.sql") // functional indexes, descending indexes, invisible indexes; spatial SRID attributes
+		if flavor.Min(FlavorMySQL80.Dot(17)) {
+			result = append(result, "index-multivalued.sql")
+		}
 	} else if flavor.Min(FlavorMariaDB106) {
 		result = append(result, "index-maria106.sql") // ignored indexes
 	}
@@ -680,3 +683,35 @@ func aFunc(dbCollation, sqlMode string) Routine {
 	r.CreateStatement = r.Definition(FlavorUnknown)
 	return r
 }
+
+func aView(name, body string) View {
+	v := View{
+		Name:                name,
+		Algorithm:           "UNDEFINED",
+		Definer:             "root@%",
+		SecurityType:        "DEFINER",
+		Body:                body,
+		CharSetClient:       "utf8mb4",
+		CollationConnection: "utf8mb4_general_ci",
+	}
+	v.CreateStatement = v.Definition(FlavorUnknown)
+	return v
+}
+
+func aTrigger(name, table, follows, body string) Trigger {
+	trig := Trigger{
+		Name:                name,
+		Table:               table,
+		Timing:              "BEFORE",
+		Event:               "INSERT",
+		Follows:             follows,
+		Definer:             "root@%",
+		Body:                body,
+		SQLMode:             "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION",
+		CharSetClient:       "utf8mb4",
+		CollationConnection: "utf8mb4_general_ci",
+		DatabaseCollation:   "latin1_swedish_ci",
+	}
+	trig.CreateStatement = trig.Definition(FlavorUnknown)
+	return trig
+}
@@ -0,0 +1,14 @@
+# Multi-valued indexes on JSON arrays, supported in MySQL 8.0.17+
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE customers (
+	id bigint NOT NULL AUTO_INCREMENT,
+	modified datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	custinfo json,
+	PRIMARY KEY (id),
+	INDEX zips ((CAST(custinfo->'$.zipcode' AS UNSIGNED ARRAY))),
+	INDEX tags_modified ((CAST(custinfo->'$.tags' AS CHAR(20) ARRAY)), modified)
+);
@@ -0,0 +1,15 @@
+# Spatial columns with explicit SRID attributes, supported in MySQL 8+
+
+SET foreign_key_checks=0;
+
+use testing
+
+CREATE TABLE places (
+	id int unsigned NOT NULL AUTO_INCREMENT,
+	name varchar(80) NOT NULL,
+	location point NOT NULL SRID 4326,
+	area polygon SRID 0 DEFAULT NULL,
+	route linestring,
+	PRIMARY KEY (id),
+	SPATIAL KEY location (location)
+);
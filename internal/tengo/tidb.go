@@ -0,0 +1,100 @@
+package tengo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func init() {
+	RegisterFlavorBackend(VendorTiDB, tidbFlavorBackend{})
+}
+
+// tidbFlavorBackend is the FlavorBackend for TiDB. Aside from TiFlash replica
+// counts, TiDB-specific table and column attributes are only exposed in SHOW
+// CREATE TABLE, so most of its logic consists of fixups.
+type tidbFlavorBackend struct {
+	BaseFlavorBackend
+}
+
+// QueryTables returns the base tables in the schema, including their TiFlash
+// replica counts.
+func (fb tidbFlavorBackend) QueryTables(ctx context.Context, db *sqlx.DB, schema string, flavor Flavor) ([]*Table, []string, error) {
+	tables, partitionedTableNames, err := fb.BaseFlavorBackend.QueryTables(ctx, db, schema, flavor)
+	if err != nil || len(tables) == 0 {
+		return tables, partitionedTableNames, err
+	}
+	replicasByTableName, err := queryTiFlashReplicasInSchema(ctx, db, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range tables {
+		t.TiFlashReplicas = replicasByTableName[t.Name]
+	}
+	return tables, partitionedTableNames, nil
+}
+
+// FixTable satisfies the TableFixer interface.
+func (tidbFlavorBackend) FixTable(t *Table, _ Flavor) {
+	fixTiDBTableOptions(t)
+}
+
+// queryTiFlashReplicasInSchema returns the number of TiFlash replicas for each
+// table in the schema that has at least one. This is only available in TiDB.
+func queryTiFlashReplicasInSchema(ctx context.Context, db *sqlx.DB, schema string) (map[string]uint64, error) {
+	defer StartTiming("Query of information_schema.tiflash_replica for schema %s", schema)()
+	var rawReplicas []struct {
+		TableName    string `db:"table_name"`
+		ReplicaCount uint64 `db:"replica_count"`
+	}
+	query := `
+		SELECT table_name AS table_name, replica_count AS replica_count
+		FROM   information_schema.tiflash_replica
+		WHERE  table_schema = ?`
+	if err := db.SelectContext(ctx, &rawReplicas, query, schema); err != nil {
+		return nil, fmt.Errorf("Error querying information_schema.tiflash_replica for schema %s: %s", schema, err)
+	}
+	replicasByTableName := make(map[string]uint64, len(rawReplicas))
+	for _, raw := range rawReplicas {
+		replicasByTableName[raw.TableName] = raw.ReplicaCount
+	}
+	return replicasByTableName, nil
+}
+
+var (
+	reTiDBPrimaryKeyClustering = regexp.MustCompile(`(?m)^  PRIMARY KEY \(.*/\*T!\[clustered_index\] (CLUSTERED|NONCLUSTERED) \*/,?$`)
+	reTiDBShardRowIDBits       = regexp.MustCompile(`\n\) .* /\*T! SHARD_ROW_ID_BITS=(\d+)(?: PRE_SPLIT_REGIONS=(\d+))? \*/`)
+	reTiDBAutoRandomBase       = regexp.MustCompile(` /\*T!\[auto_rand_base\] AUTO_RANDOM_BASE=\d+ \*/`)
+)
+
+// fixTiDBTableOptions parses the table's CREATE string in order to populate
+// TiDB-specific attributes which aren't available in information_schema: the
+// primary key's clustering, AUTO_RANDOM columns, and the table's row ID
+// sharding, placement policy, and TTL options. The next AUTO_RANDOM value is
+// removed from the CREATE string entirely, since it's a counter much like the
+// next AUTO_INCREMENT value, but isn't parsed in a way that could be restored.
+func fixTiDBTableOptions(t *Table) {
+	t.CreateStatement = reTiDBAutoRandomBase.ReplaceAllString(t.CreateStatement, "")
+	if t.PrimaryKey != nil {
+		if matches := reTiDBPrimaryKeyClustering.FindStringSubmatch(t.CreateStatement); matches != nil {
+			t.PrimaryKey.Clustering = matches[1]
+		}
+	}
+	if strings.Contains(t.CreateStatement, "/*T![auto_rand] ") {
+		for _, col := range t.Columns {
+			template := `(?m)^  ` + regexp.QuoteMeta(EscapeIdentifier(col.Name)) + ` .* /\*T!\[auto_rand\] (AUTO_RANDOM\([0-9, ]+\)) \*/`
+			if matches := regexp.MustCompile(template).FindStringSubmatch(t.CreateStatement); matches != nil {
+				col.AutoRandom = matches[1]
+			}
+		}
+	}
+	if matches := reTiDBShardRowIDBits.FindStringSubmatch(t.CreateStatement); matches != nil {
+		t.ShardRowIDBits, _ = strconv.ParseUint(matches[1], 10, 64)
+		t.PreSplitRegions, _ = strconv.ParseUint(matches[2], 10, 64)
+	}
+	t.PlacementPolicy, t.TTLOptions = ParseCreateTiDBOptions(t.CreateStatement)
+}
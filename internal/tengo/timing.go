@@ -0,0 +1,43 @@
+package tengo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TimingFunc is a callback which receives the elapsed wall-clock time of a
+// named phase of execution, such as a connection attempt or an individual
+// information_schema query.
+type TimingFunc func(phase string, elapsed time.Duration)
+
+var (
+	timingFunc     TimingFunc
+	timingFuncLock sync.RWMutex
+)
+
+// SetTimingFunc installs a callback which will be invoked upon completion of
+// each timed phase of execution. Supply nil to disable timing. Timing is
+// disabled by default.
+func SetTimingFunc(f TimingFunc) {
+	timingFuncLock.Lock()
+	timingFunc = f
+	timingFuncLock.Unlock()
+}
+
+// StartTiming begins timing a phase of execution, described by a format
+// string and args. It returns a function which should be called upon
+// completion of the phase. If no TimingFunc has been set via SetTimingFunc,
+// StartTiming is a no-op, and the phase description is never formatted.
+func StartTiming(format string, a ...interface{}) (done func()) {
+	timingFuncLock.RLock()
+	f := timingFunc
+	timingFuncLock.RUnlock()
+	if f == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		f(fmt.Sprintf(format, a...), time.Since(start))
+	}
+}
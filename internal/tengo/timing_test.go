@@ -0,0 +1,28 @@
+package tengo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartTiming(t *testing.T) {
+	// With no TimingFunc, the returned func should be a safe no-op
+	StartTiming("phase %d", 1)()
+
+	var phases []string
+	SetTimingFunc(func(phase string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("Unexpected negative elapsed time %s for phase %s", elapsed, phase)
+		}
+		phases = append(phases, phase)
+	})
+	defer SetTimingFunc(nil)
+	done := StartTiming("phase %d of %s", 2, "test")
+	if len(phases) != 0 {
+		t.Fatalf("TimingFunc called before phase completed: %v", phases)
+	}
+	done()
+	if len(phases) != 1 || phases[0] != "phase 2 of test" {
+		t.Errorf("Unexpected phases recorded: %v", phases)
+	}
+}
@@ -0,0 +1,52 @@
+package tengo
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracer is an interface for distributed tracing systems, such as an
+// OpenTelemetry SDK, to observe introspection and DDL execution. This package
+// does not depend on any particular tracing library; callers wishing to export
+// spans should supply an adapter via SetTracer.
+type Tracer interface {
+	// StartSpan begins a new span with the supplied name and attributes. The
+	// returned context should carry the new span, so that any spans started
+	// from it become children.
+	StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span represents a single traced operation.
+type Span interface {
+	// End completes the span. err is the operation's error, or nil on success.
+	End(err error)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(error) {}
+
+var (
+	tracer     Tracer
+	tracerLock sync.RWMutex
+)
+
+// SetTracer installs a Tracer for use in all subsequent introspection and DDL
+// execution. Supply nil to disable tracing, which is the default.
+func SetTracer(t Tracer) {
+	tracerLock.Lock()
+	tracer = t
+	tracerLock.Unlock()
+}
+
+// StartSpan begins a span using the Tracer supplied to SetTracer. If no
+// Tracer has been set, ctx is returned as-is along with a no-op Span.
+func StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	tracerLock.RLock()
+	t := tracer
+	tracerLock.RUnlock()
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	return t.StartSpan(ctx, name, attrs)
+}
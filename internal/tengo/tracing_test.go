@@ -0,0 +1,66 @@
+package tengo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent string
+	ended  bool
+	err    error
+}
+
+func (s *recordedSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (rt *recordingTracer) StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	span := &recordedSpan{name: name}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.name
+	}
+	rt.spans = append(rt.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestStartSpan(t *testing.T) {
+	// With no Tracer, the context should be returned unchanged
+	ctx := context.Background()
+	newCtx, span := StartSpan(ctx, "noop", nil)
+	if newCtx != ctx {
+		t.Error("Expected StartSpan without a Tracer to return original context")
+	}
+	span.End(nil)
+
+	rt := &recordingTracer{}
+	SetTracer(rt)
+	defer SetTracer(nil)
+	outerCtx, outer := StartSpan(ctx, "outer", map[string]string{"db.name": "foo"})
+	_, inner := StartSpan(outerCtx, "inner", nil)
+	inner.End(errors.New("boom"))
+	outer.End(nil)
+	if len(rt.spans) != 2 {
+		t.Fatalf("Expected 2 spans to be recorded, instead found %d", len(rt.spans))
+	}
+	if rt.spans[1].parent != "outer" {
+		t.Errorf("Expected inner span to have parent outer, instead found %q", rt.spans[1].parent)
+	}
+	for _, span := range rt.spans {
+		if !span.ended {
+			t.Errorf("Span %s was not ended", span.name)
+		}
+	}
+	if rt.spans[1].err == nil || rt.spans[0].err != nil {
+		t.Error("Span errors not recorded as expected")
+	}
+}
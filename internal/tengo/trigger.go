@@ -0,0 +1,160 @@
+package tengo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Trigger represents a trigger on a table.
+type Trigger struct {
+	Name                string `json:"name"`
+	Table               string `json:"table"`
+	Timing              string `json:"timing"`            // BEFORE or AFTER
+	Event               string `json:"event"`             // INSERT, UPDATE, or DELETE
+	Follows             string `json:"follows,omitempty"` // name of the prior trigger with the same table, timing, and event; blank if first
+	Definer             string `json:"definer"`
+	Body                string `json:"body"`                // Has correct escaping despite I_S mutilating it
+	SQLMode             string `json:"sqlMode"`             // sql_mode in effect at creation time
+	CharSetClient       string `json:"charSetClient"`       // character_set_client in effect at creation time
+	CollationConnection string `json:"collationConnection"` // collation_connection in effect at creation time
+	DatabaseCollation   string `json:"dbCollation"`         // from creation time
+	CreateStatement     string `json:"showCreate"`          // canonical CREATE, including FOLLOWS clause if needed to retain ordering
+}
+
+// ObjectKey returns a value useful for uniquely refering to a Trigger within a
+// single Schema, for example as a map key.
+func (trig *Trigger) ObjectKey() ObjectKey {
+	if trig == nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{
+		Type: ObjectTypeTrigger,
+		Name: trig.Name,
+	}
+}
+
+// Def returns the trigger's CREATE statement as a string.
+func (trig *Trigger) Def() string {
+	return trig.CreateStatement
+}
+
+// Definition generates and returns a canonical CREATE TRIGGER statement based
+// on the Trigger's Go field values. Unlike SHOW CREATE TRIGGER, this includes
+// a FOLLOWS clause for any trigger which is not the first one for its table,
+// timing, and event, so that the relative order of triggers is retained when
+// the statement is executed.
+func (trig *Trigger) Definition(_ Flavor) string {
+	var definer, follows string
+	if trig.Definer != "" {
+		definer = trig.DefinerClause() + " "
+	}
+	if trig.Follows != "" {
+		follows = "FOLLOWS " + EscapeIdentifier(trig.Follows) + " "
+	}
+	return fmt.Sprintf("CREATE %sTRIGGER %s %s %s ON %s FOR EACH ROW %s%s",
+		definer,
+		EscapeIdentifier(trig.Name),
+		trig.Timing,
+		trig.Event,
+		EscapeIdentifier(trig.Table),
+		follows,
+		trig.Body)
+}
+
+// DefinerClause returns the trigger's DEFINER, quoted/escaped in a way
+// consistent with SHOW CREATE.
+func (trig *Trigger) DefinerClause() string {
+	if atPos := strings.LastIndex(trig.Definer, "@"); atPos >= 0 {
+		return fmt.Sprintf("DEFINER=%s@%s", EscapeIdentifier(trig.Definer[0:atPos]), EscapeIdentifier(trig.Definer[atPos+1:]))
+	}
+	return fmt.Sprintf("DEFINER=%s", trig.Definer)
+}
+
+// Equals returns true if two triggers are identical, false otherwise.
+func (trig *Trigger) Equals(other *Trigger) bool {
+	// shortcut if both nil pointers, or both pointing to same underlying struct
+	if trig == other {
+		return true
+	}
+	// if one is nil, but the two pointers aren't equal, then one is non-nil
+	if trig == nil || other == nil {
+		return false
+	}
+
+	// All fields are simple scalars, so we can just use equality check once we
+	// know neither is nil
+	return *trig == *other
+}
+
+// DropStatement returns a SQL statement that, if run, would drop this trigger.
+func (trig *Trigger) DropStatement() string {
+	return "DROP TRIGGER " + EscapeIdentifier(trig.Name)
+}
+
+// sameGroup returns true if trig and other fire on the same table, timing, and
+// event, meaning their relative order is significant.
+func (trig *Trigger) sameGroup(other *Trigger) bool {
+	return trig.Table == other.Table && trig.Timing == other.Timing && trig.Event == other.Event
+}
+
+var reTriggerBody = regexp.MustCompile("(?is)\\sFOR\\s+EACH\\s+ROW\\s+(?:(?:FOLLOWS|PRECEDES)\\s+(?:`(?:[^`]|``)+`|\\w+)\\s+)?(.*)$")
+
+// parseCreateStatement populates Body by parsing the supplied output of SHOW
+// CREATE TRIGGER, and then sets CreateStatement to the canonical definition.
+// Depending on flavor, SHOW CREATE TRIGGER may return the original statement
+// text, which is not necessarily formatted consistently.
+func (trig *Trigger) parseCreateStatement(flavor Flavor, schema, showCreate string) error {
+	matches := reTriggerBody.FindStringSubmatch(showCreate)
+	if matches == nil {
+		return fmt.Errorf("Failed to parse SHOW CREATE TRIGGER %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(trig.Name), showCreate)
+	}
+	trig.Body = matches[1]
+	trig.CreateStatement = trig.Definition(flavor)
+	return nil
+}
+
+// orderTriggerDiffs returns a copy of diffs reordered such that DROPs of
+// triggers being removed entirely come first. Each remaining CREATE comes after
+// the CREATE of any trigger which it follows; the DROP portion of a
+// replacement remains immediately before its corresponding CREATE. Ordering is
+// otherwise deterministic, by name.
+func orderTriggerDiffs(diffs []*TriggerDiff) []*TriggerDiff {
+	result := make([]*TriggerDiff, 0, len(diffs))
+	creates := make(map[ObjectKey]*TriggerDiff)
+	replaceDrops := make(map[ObjectKey]*TriggerDiff)
+	var drops []*TriggerDiff
+	for _, td := range diffs {
+		if td.To != nil {
+			creates[td.ObjectKey()] = td
+		} else if td.ForReplace {
+			replaceDrops[td.ObjectKey()] = td
+		} else {
+			drops = append(drops, td)
+		}
+	}
+	sort.Slice(drops, func(i, j int) bool {
+		return drops[i].From.Name < drops[j].From.Name
+	})
+	result = append(result, drops...)
+
+	deps := make(map[ObjectKey][]ObjectKey, len(creates))
+	for key, td := range creates {
+		deps[key] = nil
+		followsKey := ObjectKey{Type: ObjectTypeTrigger, Name: td.To.Follows}
+		if _, ok := creates[followsKey]; ok && td.To.Follows != "" {
+			deps[key] = append(deps[key], followsKey)
+		}
+	}
+	// Each trigger follows at most one other in the same group, so there cannot
+	// be a cycle
+	ordered, _ := topoSortObjectKeys(deps)
+	for _, key := range ordered {
+		if drop := replaceDrops[key]; drop != nil {
+			result = append(result, drop)
+		}
+		result = append(result, creates[key])
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package tengo
+
+import (
+	"testing"
+)
+
+func TestTriggerParseCreateStatement(t *testing.T) {
+	cases := map[string]string{
+		"CREATE DEFINER=`root`@`%` TRIGGER `t1` BEFORE INSERT ON `actor` FOR EACH ROW SET NEW.last_update = NOW()":                           "SET NEW.last_update = NOW()",
+		"CREATE DEFINER=`root`@`%` TRIGGER t1 BEFORE INSERT ON actor\nFOR EACH ROW\nFOLLOWS `t0` BEGIN\n  SET NEW.last_update = NOW();\nEND": "BEGIN\n  SET NEW.last_update = NOW();\nEND",
+		"create trigger t1 before insert on actor for each row precedes t2 set new.last_update = now()":                                      "set new.last_update = now()",
+		"CREATE DEFINER=`root`@`%` TRIGGER `t1` BEFORE INSERT ON `actor` FOR EACH ROW INSERT INTO log (msg) VALUES ('for each row follows')": "INSERT INTO log (msg) VALUES ('for each row follows')",
+	}
+	for showCreate, expectedBody := range cases {
+		trig := &Trigger{Name: "t1", Table: "actor", Timing: "BEFORE", Event: "INSERT", Follows: "t0", Definer: "root@%"}
+		if err := trig.parseCreateStatement(FlavorUnknown, "product", showCreate); err != nil {
+			t.Errorf("Unexpected error from parseCreateStatement on %q: %v", showCreate, err)
+		} else if trig.Body != expectedBody {
+			t.Errorf("Unexpected body from parseCreateStatement on %q: expected %q, found %q", showCreate, expectedBody, trig.Body)
+		} else if expected := "CREATE DEFINER=`root`@`%` TRIGGER `t1` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `t0` " + expectedBody; trig.CreateStatement != expected {
+			t.Errorf("Unexpected CreateStatement: expected %q, found %q", expected, trig.CreateStatement)
+		}
+	}
+
+	trig := &Trigger{Name: "t1"}
+	if err := trig.parseCreateStatement(FlavorUnknown, "product", "CREATE TRIGGER t1 BEFORE INSERT ON actor SET @x = 1"); err == nil {
+		t.Error("Expected error from parseCreateStatement lacking FOR EACH ROW, but err was nil")
+	}
+}
+
+func TestTriggerDefinition(t *testing.T) {
+	trig := aTrigger("t1", "actor", "", "SET NEW.last_update = NOW()")
+	expected := "CREATE DEFINER=`root`@`%` TRIGGER `t1` BEFORE INSERT ON `actor` FOR EACH ROW SET NEW.last_update = NOW()"
+	if trig.CreateStatement != expected {
+		t.Errorf("Unexpected Definition: expected %q, found %q", expected, trig.CreateStatement)
+	}
+	if stmt := ParseStatementInString(trig.CreateStatement); stmt.Type != StatementTypeCreate || stmt.ObjectKey() != trig.ObjectKey() {
+		t.Errorf("Definition could not be parsed: %+v", stmt)
+	}
+
+	trig = aTrigger("t2", "actor", "t1", "BEGIN\n  SET NEW.last_update = NOW();\nEND")
+	expected = "CREATE DEFINER=`root`@`%` TRIGGER `t2` BEFORE INSERT ON `actor` FOR EACH ROW FOLLOWS `t1` BEGIN\n  SET NEW.last_update = NOW();\nEND"
+	if trig.CreateStatement != expected {
+		t.Errorf("Unexpected Definition: expected %q, found %q", expected, trig.CreateStatement)
+	}
+	if stmt := ParseStatementInString(trig.CreateStatement); stmt.Type != StatementTypeCreate || stmt.ObjectKey() != trig.ObjectKey() || !stmt.Compound {
+		t.Errorf("Definition could not be parsed: %+v", stmt)
+	}
+}
+
+func TestOrderTriggerDiffs(t *testing.T) {
+	t2 := aTrigger("t2", "actor", "t1", "SET @x = 2")
+	t3 := aTrigger("t3", "actor", "t2", "SET @x = 3")
+	a1 := aTrigger("a1", "actor", "t3", "SET @x = 4")
+	oldT2 := aTrigger("t2", "actor", "t1", "SET @x = 20")
+	old := aTrigger("old", "film", "", "SET @x = 5")
+	diffs := []*TriggerDiff{
+		{To: &a1},
+		{From: &oldT2, ForReplace: true},
+		{To: &t3},
+		{From: &old},
+		{To: &t2, ForReplace: true},
+	}
+	ordered := orderTriggerDiffs(diffs)
+	expected := []string{"DROP old", "DROP t2", "CREATE t2", "CREATE t3", "CREATE a1"}
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %d diffs, instead found %d", len(expected), len(ordered))
+	}
+	for n, td := range ordered {
+		if actual := td.DiffType().String() + " " + td.ObjectKey().Name; actual != expected[n] {
+			t.Errorf("Expected diff[%d] to be %s, instead found %s", n, expected[n], actual)
+		}
+	}
+}
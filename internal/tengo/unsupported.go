@@ -0,0 +1,173 @@
+package tengo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UnsupportedDetails describes why a table's SHOW CREATE TABLE does not match
+// the CREATE TABLE statement that this package generates from its
+// introspected representation of the table. Such tables have UnsupportedDDL set
+// to true, and cannot be diff'ed.
+type UnsupportedDetails struct {
+	Reason string // Best-guess human-readable cause of the mismatch
+	Diff   string // Line-level unified diff from expected to actual CREATE TABLE
+}
+
+// UnsupportedDetails returns information about why the table is unsupported
+// for diff operations, or nil if the table is supported. The Reason is a
+// heuristic only; the Diff is intended for inclusion in bug reports.
+func (t *Table) UnsupportedDetails(flavor Flavor) *UnsupportedDetails {
+	if !t.UnsupportedDDL {
+		return nil
+	}
+	expected := t.GeneratedCreateStatement(flavor)
+	reason := guessUnsupportedReason(expected, t.CreateStatement)
+	if t.fixupErr != nil {
+		reason = t.fixupErr.Error()
+	}
+	return &UnsupportedDetails{
+		Reason: reason,
+		Diff:   unsupportedDiff(expected, t.CreateStatement, "expected CREATE", "actual SHOW CREATE"),
+	}
+}
+
+// unsupportedDiff returns a unified diff, without context lines, between the
+// supplied CREATE statements.
+func unsupportedDiff(expected, actual, expectedDesc, actualDesc string) string {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: expectedDesc,
+		ToFile:   actualDesc,
+		Context:  0,
+	}
+	diffText, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return err.Error()
+	}
+	return diffText
+}
+
+// guessUnsupportedReason compares the expected and actual CREATE statements of
+// a table line-by-line, and returns a short description of the most likely
+// cause of the first difference found. Since this package's inability to
+// generate the actual statement means it likely does not understand some of
+// its contents, this is inherently a best guess.
+func guessUnsupportedReason(expected, actual string) string {
+	expectedLines := normalizedCreateLines(expected)
+	actualLines := normalizedCreateLines(actual)
+	removed, added := lineSetDifference(expectedLines, actualLines)
+	if len(removed) == 0 && len(added) == 0 {
+		return "clause ordering differs from the expected ordering"
+	}
+
+	// Pair each line only in the actual statement with the closest line only in
+	// the expected statement, based on its leading identifier or keyword
+	for _, line := range added {
+		switch {
+		case strings.HasPrefix(line, "`"):
+			name := leadingIdentifier(line)
+			for _, exp := range removed {
+				if leadingIdentifier(exp) == name {
+					return describeLineDifference("column "+name, exp, line, true)
+				}
+			}
+			return fmt.Sprintf("unexpected definition for column or clause %s", name)
+		case strings.HasPrefix(line, ")"):
+			for _, exp := range removed {
+				if strings.HasPrefix(exp, ")") {
+					return describeLineDifference("table options", exp, line, false)
+				}
+			}
+			return "unsupported table options: " + strings.TrimSpace(strings.TrimPrefix(line, ")"))
+		case strings.Contains(line, "PARTITION"):
+			return "unsupported partitioning clause"
+		case strings.Contains(line, "CONSTRAINT") || strings.Contains(line, "FOREIGN KEY") || strings.HasPrefix(line, "CHECK"):
+			return "unsupported constraint clause: " + line
+		case strings.Contains(line, "KEY") || strings.Contains(line, "INDEX"):
+			return "unsupported index clause: " + line
+		}
+	}
+	if len(added) > 0 {
+		return "unknown clause: " + added[0]
+	}
+	return "expected clause is missing: " + removed[0]
+}
+
+// describeLineDifference returns a description of how actual varies from
+// expected, which are both single lines of a CREATE TABLE describing the same
+// component. If isColumn is true, the lines are both column definitions, with
+// the column type as the second token.
+func describeLineDifference(component, expected, actual string, isColumn bool) string {
+	expTokens := strings.Fields(expected)
+	actTokens := strings.Fields(actual)
+	if isColumn && len(expTokens) > 1 && len(actTokens) > 1 && expTokens[1] != actTokens[1] {
+		return fmt.Sprintf("unrecognized type for %s: expected %s, found %s", component, expTokens[1], actTokens[1])
+	}
+	missing, extra := lineSetDifference(expTokens, actTokens)
+	if len(extra) == 0 && len(missing) == 0 {
+		return fmt.Sprintf("option ordering differs in %s", component)
+	}
+	if len(extra) > 0 {
+		return fmt.Sprintf("unsupported clause in %s: %s", component, strings.Join(extra, " "))
+	}
+	return fmt.Sprintf("expected clause missing from %s: %s", component, strings.Join(missing, " "))
+}
+
+// normalizedCreateLines splits a CREATE statement into lines, removing
+// surrounding whitespace and trailing commas, which vary based on position.
+func normalizedCreateLines(create string) []string {
+	lines := strings.Split(create, "\n")
+	for n := range lines {
+		lines[n] = strings.TrimSuffix(strings.TrimSpace(lines[n]), ",")
+	}
+	return lines
+}
+
+// lineSetDifference returns the lines only in a, and the lines only in b,
+// ignoring order but respecting multiplicity. Results retain input ordering.
+func lineSetDifference(a, b []string) (onlyA, onlyB []string) {
+	counts := make(map[string]int, len(a))
+	for _, line := range a {
+		counts[line]++
+	}
+	for _, line := range b {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			onlyB = append(onlyB, line)
+		}
+	}
+	for _, line := range a {
+		if counts[line] > 0 {
+			counts[line]--
+			onlyA = append(onlyA, line)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// leadingIdentifier returns the backtick-quoted identifier at the start of
+// line, including its quotes, or the first whitespace-delimited token if line
+// does not begin with a quoted identifier.
+func leadingIdentifier(line string) string {
+	if strings.HasPrefix(line, "`") {
+		for n := 1; n < len(line); n++ {
+			if line[n] == '`' {
+				if n+1 < len(line) && line[n+1] == '`' {
+					n++
+					continue
+				}
+				return line[:n+1]
+			}
+		}
+		return line
+	}
+	if fields := strings.Fields(line); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuessUnsupportedReason(t *testing.T) {
+	expected := "CREATE TABLE `t` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(30) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1"
+	cases := []struct {
+		actual   string
+		contains string
+	}{
+		{strings.Replace(expected, "varchar(30)", "vector(30)", 1), "unrecognized type for column `name`: expected varchar(30), found vector(30)"},
+		{strings.Replace(expected, "DEFAULT NULL", "DEFAULT NULL /*!80000 SECONDARY */", 1), "unsupported clause in column `name`: /*!80000 SECONDARY */"},
+		{strings.Replace(expected, "DEFAULT NULL", "NULL DEFAULT", 1), "option ordering differs in column `name`"},
+		{strings.Replace(expected, "ENGINE=InnoDB", "ENGINE=InnoDB SECONDARY_ENGINE=RAPID", 1), "unsupported clause in table options: SECONDARY_ENGINE=RAPID"},
+		{strings.Replace(expected, "PRIMARY KEY (`id`)", "PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */", 1), "unsupported index clause"},
+		{strings.Replace(expected, "PRIMARY KEY (`id`)", "PRIMARY KEY (`id`),\n  CONSTRAINT `c` CHECK (`id` > 0) NOT ENFORCED", 1), "unsupported constraint clause"},
+		{expected + "\n/*!50100 PARTITION BY HASH (`id`) PARTITIONS 4 */", "unsupported partitioning clause"},
+		{strings.Replace(expected, "  `name` varchar(30) DEFAULT NULL,\n", "", 1), "expected clause is missing: `name` varchar(30) DEFAULT NULL"},
+		{strings.Replace(expected, "  `name` varchar(30) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n", "  PRIMARY KEY (`id`),\n  `name` varchar(30) DEFAULT NULL\n", 1), "clause ordering differs"},
+		{strings.Replace(expected, "PRIMARY KEY (`id`)", "PRIMARY KEY (`id`),\n  PERIOD FOR SYSTEM_TIME (`a`, `b`)", 1), "unknown clause: PERIOD FOR SYSTEM_TIME"},
+	}
+	for n, c := range cases {
+		if reason := guessUnsupportedReason(expected, c.actual); !strings.Contains(reason, c.contains) {
+			t.Errorf("Case %d: expected reason to contain %q, instead found %q", n, c.contains, reason)
+		}
+	}
+}
+
+func TestTableUnsupportedDetails(t *testing.T) {
+	flavor := FlavorMySQL80
+	table := aTable(1)
+	if details := table.UnsupportedDetails(flavor); details != nil {
+		t.Fatalf("Expected nil details for supported table, instead found %+v", details)
+	}
+	table.CreateStatement = strings.Replace(table.CreateStatement, "ENGINE=InnoDB", "ENGINE=InnoDB SECONDARY_ENGINE=RAPID", 1)
+	table.UnsupportedDDL = true
+	details := table.UnsupportedDetails(flavor)
+	if details == nil {
+		t.Fatal("Expected non-nil details for unsupported table")
+	}
+	if !strings.Contains(details.Reason, "SECONDARY_ENGINE=RAPID") {
+		t.Errorf("Unexpected reason: %q", details.Reason)
+	}
+	if !strings.Contains(details.Diff, "+) ENGINE=InnoDB SECONDARY_ENGINE=RAPID") || !strings.Contains(details.Diff, "--- expected CREATE") {
+		t.Errorf("Unexpected diff: %s", details.Diff)
+	}
+}
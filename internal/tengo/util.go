@@ -18,6 +18,13 @@ func EscapeIdentifier(input string) string {
 	return fmt.Sprintf("`%s`", escaped)
 }
 
+// unescapeIdentifier reverses the escaping of backticks performed by
+// EscapeIdentifier, for an identifier which has already had its surrounding
+// backticks removed, such as a regular expression capture group.
+func unescapeIdentifier(input string) string {
+	return strings.Replace(input, "``", "`", -1)
+}
+
 // EscapeValueForCreateTable returns the supplied value (typically obtained from
 // querying an information_schema table) escaped in the same manner as SHOW
 // CREATE TABLE would display it. Examples include default values, table
@@ -73,6 +80,15 @@ func SplitHostOptionalPort(hostaddr string) (string, int, error) {
 	return host, port, nil
 }
 
+// stripQueryHints removes any hints from an information_schema query which are
+// not supported by flavor. Vitess's vtgate does not permit SQL_BUFFER_RESULT.
+func stripQueryHints(query string, flavor Flavor) string {
+	if flavor.HasVariant(VariantVitess) {
+		return strings.Replace(query, "SQL_BUFFER_RESULT", "", 1)
+	}
+	return query
+}
+
 var reParseTablespace = regexp.MustCompile(`[)] /\*!50100 TABLESPACE ` + "`((?:[^`]|``)+)`" + ` \*/ ENGINE=`)
 
 // ParseCreateTablespace parses a TABLESPACE clause out of a CREATE TABLE
@@ -85,6 +101,48 @@ func ParseCreateTablespace(createStmt string) string {
 	return ""
 }
 
+var reParseSecondaryEngine = regexp.MustCompile(` SECONDARY_ENGINE=(\w+)$`)
+
+// ParseCreateSecondaryEngine parses a MySQL SECONDARY_ENGINE table option out
+// of a CREATE TABLE statement.
+func ParseCreateSecondaryEngine(createStmt string) string {
+	// Only examine the table options line, since partition definitions may
+	// follow. SHOW CREATE TABLE places this option after any table comment.
+	optionsLine := createStmt[strings.LastIndex(createStmt, "\n) ")+1:]
+	if pos := strings.IndexByte(optionsLine, '\n'); pos > -1 {
+		optionsLine = optionsLine[0:pos]
+	}
+	if matches := reParseSecondaryEngine.FindStringSubmatch(optionsLine); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+var reParseTiDBTableOption = regexp.MustCompile(`/\*T!\[(placement|ttl)\] (.+?) \*/`)
+
+// ParseCreateTiDBOptions parses TiDB-specific table options out of a CREATE
+// TABLE statement: the name of the table's placement policy, if any, and its
+// TTL options, in the same order as SHOW CREATE TABLE.
+func ParseCreateTiDBOptions(createStmt string) (placementPolicy string, ttlOptions []string) {
+	// Only examine the table options line, since partition definitions may have
+	// their own placement policies
+	optionsLine := createStmt[strings.LastIndex(createStmt, "\n) ")+1:]
+	if pos := strings.IndexByte(optionsLine, '\n'); pos > -1 {
+		optionsLine = optionsLine[0:pos]
+	}
+	for _, matches := range reParseTiDBTableOption.FindAllStringSubmatch(optionsLine, -1) {
+		if matches[1] == "ttl" {
+			ttlOptions = append(ttlOptions, matches[2])
+		} else if strings.HasPrefix(matches[2], "PLACEMENT POLICY=") {
+			placementPolicy = strings.TrimPrefix(matches[2], "PLACEMENT POLICY=")
+			if len(placementPolicy) > 1 && placementPolicy[0] == '`' {
+				placementPolicy = unescapeIdentifier(placementPolicy[1 : len(placementPolicy)-1])
+			}
+		}
+	}
+	return placementPolicy, ttlOptions
+}
+
 var reParseCreateAutoInc = regexp.MustCompile(`[)/] ENGINE=\w+ (AUTO_INCREMENT=(\d+) )DEFAULT CHARSET=`)
 
 // ParseCreateAutoInc parses a CREATE TABLE statement, formatted in the same
@@ -140,6 +198,11 @@ func reformatCreateOptions(input string) string {
 			}
 			continue
 		}
+		// MySQL's secondary engine is tracked separately from other create options,
+		// since it comes after the table comment in SHOW CREATE TABLE
+		if tokens[0] == "SECONDARY_ENGINE" {
+			continue
+		}
 
 		// Double quote wrapper changed to single quotes in SHOW CREATE TABLE
 		if tokens[1][0] == '"' && tokens[1][len(tokens[1])-1] == '"' {
@@ -247,3 +310,33 @@ func longestIncreasingSubsequence(input []int) []int {
 	}
 	return candidateLists[len(candidateLists)-1]
 }
+
+// splitBatches splits input into consecutive sub-slices with at most size
+// elements each. If size is less than 1, a single batch containing all of
+// input is returned.
+func splitBatches(input []string, size int) [][]string {
+	if len(input) == 0 {
+		return nil
+	} else if size < 1 || len(input) <= size {
+		return [][]string{input}
+	}
+	batches := make([][]string, 0, (len(input)+size-1)/size)
+	for len(input) > size {
+		batches = append(batches, input[0:size:size])
+		input = input[size:]
+	}
+	return append(batches, input)
+}
+
+// stringInterner deduplicates equal strings, so that values which repeat
+// heavily across a large schema (column types, collation names, etc) share one
+// backing allocation. It is not safe for concurrent use.
+type stringInterner map[string]string
+
+func (si stringInterner) intern(s string) string {
+	if interned, ok := si[s]; ok {
+		return interned
+	}
+	si[s] = s
+	return s
+}
@@ -2,9 +2,12 @@ package tengo
 
 import (
 	"fmt"
+	"io"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"unsafe"
 )
 
 func TestSplitHostOptionalPort(t *testing.T) {
@@ -67,6 +70,45 @@ func TestParseCreateAutoInc(t *testing.T) {
 	}
 }
 
+func TestParseCreateTiDBOptions(t *testing.T) {
+	stmt := "CREATE TABLE `t` (\n  `id` int(11) NOT NULL,\n  `created_at` datetime DEFAULT NULL,\n  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![placement] PLACEMENT POLICY=`p1` */ /*T![ttl] TTL=`created_at` + INTERVAL 1 DAY */ /*T![ttl] TTL_ENABLE='ON' */\n" +
+		"PARTITION BY RANGE (`id`)\n(PARTITION `p0` VALUES LESS THAN (100) /*T![placement] PLACEMENT POLICY=`p2` */)"
+	policy, ttlOptions := ParseCreateTiDBOptions(stmt)
+	if policy != "p1" {
+		t.Errorf("Expected placement policy p1, instead found %q", policy)
+	}
+	if len(ttlOptions) != 2 || ttlOptions[0] != "TTL=`created_at` + INTERVAL 1 DAY" || ttlOptions[1] != "TTL_ENABLE='ON'" {
+		t.Errorf("Unexpected TTL options: %q", ttlOptions)
+	}
+	if policy, ttlOptions := ParseCreateTiDBOptions(aTable(1).CreateStatement); policy != "" || ttlOptions != nil {
+		t.Errorf("Expected no TiDB options, instead found %q, %q", policy, ttlOptions)
+	}
+}
+
+func TestParseCreateSecondaryEngine(t *testing.T) {
+	stmt := "CREATE TABLE `t` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='not SECONDARY_ENGINE=FOO' SECONDARY_ENGINE=RAPID\n" +
+		"/*!50100 PARTITION BY HASH (`id`)\nPARTITIONS 4 */"
+	if actual := ParseCreateSecondaryEngine(stmt); actual != "RAPID" {
+		t.Errorf("Expected secondary engine RAPID, instead found %q", actual)
+	}
+	if actual := ParseCreateSecondaryEngine(aTable(1).CreateStatement); actual != "" {
+		t.Errorf("Expected no secondary engine, instead found %q", actual)
+	}
+}
+
+func TestStripQueryHints(t *testing.T) {
+	query := "SELECT SQL_BUFFER_RESULT table_name FROM information_schema.tables"
+	if actual := stripQueryHints(query, FlavorMySQL80); actual != query {
+		t.Errorf("Expected query to be unchanged, instead found %q", actual)
+	}
+	vitess := ParseFlavor("vitess:8.0")
+	if actual := stripQueryHints(query, vitess); strings.Contains(actual, "SQL_BUFFER_RESULT") {
+		t.Errorf("Expected SQL_BUFFER_RESULT to be removed, instead found %q", actual)
+	}
+}
+
 func TestReformatCreateOptions(t *testing.T) {
 	cases := map[string]string{
 		"":                                       "",
@@ -76,6 +118,7 @@ func TestReformatCreateOptions(t *testing.T) {
 		"row_format=DYNAMIC stats_auto_recalc=1": "ROW_FORMAT=DYNAMIC STATS_AUTO_RECALC=1",
 		"COMPRESSION=\"zLIB\"":                   "COMPRESSION='zLIB'", // MySQL style page compression
 		"`PAGE_compressed`=1 `page_compression_LEVEL`=9": "`PAGE_compressed`=1 `page_compression_LEVEL`=9", // MariaDB style page compression
+		"row_format=COMPACT SECONDARY_ENGINE=\"RAPID\"":  "ROW_FORMAT=COMPACT",                             // tracked separately in Table.SecondaryEngine
 	}
 	for input, expected := range cases {
 		if actual := reformatCreateOptions(input); actual != expected {
@@ -155,3 +198,83 @@ func TestLongestIncreasingSubsequence(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitBatches(t *testing.T) {
+	input := strings.Split("a b c d e f g", " ")
+	cases := map[int]string{
+		-1: "a b c d e f g",
+		0:  "a b c d e f g",
+		1:  "a|b|c|d|e|f|g",
+		3:  "a b c|d e f|g",
+		7:  "a b c d e f g",
+		10: "a b c d e f g",
+	}
+	for size, expected := range cases {
+		var batchStrs []string
+		for _, batch := range splitBatches(input, size) {
+			batchStrs = append(batchStrs, strings.Join(batch, " "))
+		}
+		if actual := strings.Join(batchStrs, "|"); actual != expected {
+			t.Errorf("Expected splitBatches with size %d to return %q, instead found %q", size, expected, actual)
+		}
+	}
+	if batches := splitBatches([]string{}, 5); batches != nil {
+		t.Errorf("Expected splitBatches on empty input to return nil, instead found %v", batches)
+	}
+}
+
+func TestStringInterner(t *testing.T) {
+	// Returns the address of the string's backing bytes. (unsafe.StringData would
+	// be preferable, but requires Go 1.20.)
+	stringData := func(s string) uintptr {
+		return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	}
+
+	si := make(stringInterner)
+	input1, input2 := strings.Repeat("varchar", 2), strings.Repeat("varchar", 2)
+	if stringData(input1) == stringData(input2) {
+		t.Fatal("Test setup problem: inputs unexpectedly share backing memory")
+	}
+	a := si.intern(input1)
+	b := si.intern(input2)
+	if a != b || len(si) != 1 {
+		t.Errorf("Unexpected interner state: %v", si)
+	}
+	if stringData(a) != stringData(input1) || stringData(b) != stringData(input1) {
+		t.Error("Expected interned strings to share the backing memory of the first input")
+	}
+	if c := si.intern("int"); c != "int" || len(si) != 2 {
+		t.Errorf("Unexpected interner state: %v", si)
+	}
+}
+
+func FuzzEscapeIdentifier(f *testing.F) {
+	for _, seed := range []string{"", "foo", "foo`bar", "``", "`", "emoji 🐬", "new\nline", "tab\there", "semi;colon", "\xff\xfe"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		if len(name) > 1024 {
+			t.Skip() // far beyond MySQL's identifier length limit
+		}
+		escaped := EscapeIdentifier(name)
+		if len(escaped) < 2 || escaped[0] != '`' || escaped[len(escaped)-1] != '`' {
+			t.Fatalf("EscapeIdentifier(%q) returned %q, which is not wrapped in backticks", name, escaped)
+		}
+		if inner := escaped[1 : len(escaped)-1]; unescapeIdentifier(inner) != name {
+			t.Errorf("unescapeIdentifier did not reverse EscapeIdentifier(%q): found %q", name, unescapeIdentifier(inner))
+		}
+		if stripBackticks(escaped) != name {
+			t.Errorf("stripBackticks did not reverse EscapeIdentifier(%q): found %q", name, stripBackticks(escaped))
+		}
+		// An escaped identifier must lex as exactly one token, no matter what
+		// characters it contains
+		lexer := NewLexer(strings.NewReader(escaped), ";", 8192)
+		data, typ, err := lexer.Scan()
+		if err != nil || typ != TokenIdent || string(data) != escaped {
+			t.Errorf("Unexpected lexer result for %q: %q, %v, %v", escaped, data, typ, err)
+		}
+		if _, _, err := lexer.Scan(); err != io.EOF {
+			t.Errorf("Expected lexer to reach EOF after %q, instead found %v", escaped, err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package tengo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// View represents a view, which is a stored SELECT query which may be referred
+// to like a table.
+type View struct {
+	Name                string `json:"name"`
+	Algorithm           string `json:"algorithm"` // UNDEFINED, MERGE, or TEMPTABLE
+	Definer             string `json:"definer"`
+	SecurityType        string `json:"securityType"`          // DEFINER or INVOKER
+	CheckOption         string `json:"checkOption,omitempty"` // CASCADED or LOCAL; blank if none
+	Body                string `json:"body"`                  // SELECT query, formatted as per SHOW CREATE VIEW
+	CharSetClient       string `json:"charSetClient"`         // character_set_client in effect at creation time
+	CollationConnection string `json:"collationConnection"`   // collation_connection in effect at creation time
+	CreateStatement     string `json:"showCreate"`            // complete SHOW CREATE obtained from an instance
+}
+
+// ObjectKey returns a value useful for uniquely refering to a View within a
+// single Schema, for example as a map key.
+func (v *View) ObjectKey() ObjectKey {
+	if v == nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{
+		Type: ObjectTypeView,
+		Name: v.Name,
+	}
+}
+
+// Def returns the view's CREATE statement as a string.
+func (v *View) Def() string {
+	return v.CreateStatement
+}
+
+// Definition generates and returns a canonical CREATE VIEW statement based on
+// the View's Go field values. This is formatted identically to SHOW CREATE
+// VIEW.
+func (v *View) Definition(_ Flavor) string {
+	return v.head() + v.Body + v.checkOptionClause()
+}
+
+// DefinerClause returns the view's DEFINER, quoted/escaped in a way consistent
+// with SHOW CREATE.
+func (v *View) DefinerClause() string {
+	if atPos := strings.LastIndex(v.Definer, "@"); atPos >= 0 {
+		return fmt.Sprintf("DEFINER=%s@%s", EscapeIdentifier(v.Definer[0:atPos]), EscapeIdentifier(v.Definer[atPos+1:]))
+	}
+	return fmt.Sprintf("DEFINER=%s", v.Definer)
+}
+
+// head returns the portion of a CREATE statement prior to the body.
+func (v *View) head() string {
+	var definer string
+	if v.Definer != "" {
+		definer = v.DefinerClause() + " "
+	}
+	return fmt.Sprintf("CREATE ALGORITHM=%s %sSQL SECURITY %s VIEW %s AS ",
+		v.Algorithm,
+		definer,
+		v.SecurityType,
+		EscapeIdentifier(v.Name))
+}
+
+func (v *View) checkOptionClause() string {
+	if v.CheckOption == "" {
+		return ""
+	}
+	return fmt.Sprintf(" WITH %s CHECK OPTION", v.CheckOption)
+}
+
+// Equals returns true if two views are identical, false otherwise.
+func (v *View) Equals(other *View) bool {
+	// shortcut if both nil pointers, or both pointing to same underlying struct
+	if v == other {
+		return true
+	}
+	// if one is nil, but the two pointers aren't equal, then one is non-nil
+	if v == nil || other == nil {
+		return false
+	}
+
+	// All fields are simple scalars, so we can just use equality check once we
+	// know neither is nil
+	return *v == *other
+}
+
+// DropStatement returns a SQL statement that, if run, would drop this view.
+func (v *View) DropStatement() string {
+	return "DROP VIEW " + EscapeIdentifier(v.Name)
+}
+
+// parseCreateStatement populates Algorithm and Body by parsing CreateStatement.
+// information_schema.views lacks the algorithm in MySQL, and its
+// view_definition column qualifies all table names with their schema, so the
+// output of SHOW CREATE VIEW is used instead.
+func (v *View) parseCreateStatement(schema string) error {
+	algo, _, ok := strings.Cut(strings.TrimPrefix(v.CreateStatement, "CREATE ALGORITHM="), " ")
+	if !ok || !strings.HasPrefix(v.CreateStatement, "CREATE ALGORITHM=") {
+		return fmt.Errorf("Failed to parse SHOW CREATE VIEW %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(v.Name), v.CreateStatement)
+	}
+	v.Algorithm = algo
+	nameClause := " VIEW " + EscapeIdentifier(v.Name) + " AS "
+	pos := strings.Index(v.CreateStatement, nameClause)
+	if pos < 0 {
+		return fmt.Errorf("Failed to parse SHOW CREATE VIEW %s.%s: %s", EscapeIdentifier(schema), EscapeIdentifier(v.Name), v.CreateStatement)
+	}
+	v.Body = strings.TrimSuffix(v.CreateStatement[pos+len(nameClause):], v.checkOptionClause())
+	return nil
+}
+
+// orderViewDiffs returns a copy of diffs reordered such that DROPs come first,
+// followed by all other diffs in an order where any view being created or
+// replaced comes after other created or replaced views that its body
+// references. Ordering is otherwise deterministic, by name.
+func orderViewDiffs(diffs []*ViewDiff) []*ViewDiff {
+	result := make([]*ViewDiff, 0, len(diffs))
+	others := make(map[ObjectKey]*ViewDiff)
+	var drops []*ViewDiff
+	for _, vd := range diffs {
+		if vd.To == nil {
+			drops = append(drops, vd)
+		} else {
+			others[vd.ObjectKey()] = vd
+		}
+	}
+	sort.Slice(drops, func(i, j int) bool {
+		return drops[i].From.Name < drops[j].From.Name
+	})
+	result = append(result, drops...)
+
+	deps := make(map[ObjectKey][]ObjectKey, len(others))
+	for key, vd := range others {
+		deps[key] = nil
+		for _, tok := range routineBodyTokens(vd.To.Body) {
+			name, ok := getNameFromToken(tok)
+			refKey := ObjectKey{Type: ObjectTypeView, Name: name}
+			if _, isView := others[refKey]; ok && isView && refKey != key {
+				deps[key] = append(deps[key], refKey)
+			}
+		}
+	}
+	// A view cannot reference itself, directly or indirectly, so there is no
+	// need to check for cycles
+	ordered, _ := topoSortObjectKeys(deps)
+	for _, key := range ordered {
+		result = append(result, others[key])
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package tengo
+
+import (
+	"testing"
+)
+
+func TestViewParseCreateStatement(t *testing.T) {
+	cases := []View{
+		aView("v1", "select `t`.`id` AS `id` from `t`"),
+		{Name: "v`2", Algorithm: "MERGE", Definer: "app@10.%", SecurityType: "INVOKER", CheckOption: "CASCADED", Body: "select `t`.`id` AS `id` from `t` where (`t`.`id` > 1)"},
+		{Name: "v3", Algorithm: "TEMPTABLE", SecurityType: "DEFINER", CheckOption: "LOCAL", Body: "select 1 AS `1`"},
+	}
+	for _, expected := range cases {
+		create := expected.Definition(FlavorUnknown)
+		v := &View{Name: expected.Name, Definer: expected.Definer, SecurityType: expected.SecurityType, CheckOption: expected.CheckOption, CreateStatement: create}
+		if err := v.parseCreateStatement("product"); err != nil {
+			t.Errorf("Unexpected error from parseCreateStatement on %q: %v", create, err)
+		} else if v.Algorithm != expected.Algorithm || v.Body != expected.Body {
+			t.Errorf("Unexpected result from parseCreateStatement on %q: algorithm=%q body=%q", create, v.Algorithm, v.Body)
+		} else if v.Definition(FlavorUnknown) != create {
+			t.Errorf("Definition does not round-trip: expected %q, found %q", create, v.Definition(FlavorUnknown))
+		}
+	}
+
+	v := &View{Name: "v1", CreateStatement: "CREATE VIEW `v1` AS select 1"}
+	if err := v.parseCreateStatement("product"); err == nil {
+		t.Error("Expected error from parseCreateStatement lacking ALGORITHM clause, but err was nil")
+	}
+	v = &View{Name: "v1", CreateStatement: "CREATE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `v2` AS select 1"}
+	if err := v.parseCreateStatement("product"); err == nil {
+		t.Error("Expected error from parseCreateStatement with mismatched name, but err was nil")
+	}
+}
+
+func TestViewDefinerClause(t *testing.T) {
+	cases := map[string]string{
+		"root@%":          "DEFINER=`root`@`%`",
+		"app@10.0.0.1":    "DEFINER=`app`@`10.0.0.1`",
+		"some@user@local": "DEFINER=`some@user`@`local`",
+		"CURRENT_USER":    "DEFINER=CURRENT_USER",
+	}
+	for definer, expected := range cases {
+		v := View{Definer: definer}
+		if actual := v.DefinerClause(); actual != expected {
+			t.Errorf("Expected DefinerClause() to return %q for definer %q, instead found %q", expected, definer, actual)
+		}
+	}
+}
+
+func TestOrderViewDiffs(t *testing.T) {
+	v1 := aView("v1", "select `v2`.`id` AS `id` from `v2`")
+	v2 := aView("v2", "select `v3`.`id` AS `id` from `v3`")
+	v3 := aView("v3", "select `t`.`id` AS `id` from `t`")
+	v4 := aView("v4", "select 'v1' AS `v1`") // string literal should not be treated as a reference
+	old := aView("old", "select 1 AS `1`")
+	oldV2 := aView("v2", "select 2 AS `2`")
+	diffs := []*ViewDiff{
+		{To: &v1},
+		{From: &oldV2, To: &v2},
+		{To: &v4},
+		{From: &old},
+		{To: &v3},
+	}
+	ordered := orderViewDiffs(diffs)
+	expected := []string{"old", "v3", "v2", "v1", "v4"}
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %d diffs, instead found %d", len(expected), len(ordered))
+	}
+	for n, vd := range ordered {
+		if vd.ObjectKey().Name != expected[n] {
+			t.Errorf("Expected diff[%d] to be for %s, instead found %s", n, expected[n], vd.ObjectKey())
+		}
+	}
+}
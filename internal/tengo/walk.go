@@ -0,0 +1,98 @@
+package tengo
+
+import (
+	"errors"
+)
+
+// SkipChildren may be returned by a Visitor's VisitTable callback to indicate
+// that the table's columns, indexes, foreign keys, checks, and partitions
+// should not be visited. It is not returned as an error by Walk.
+var SkipChildren = errors.New("skip children of this object")
+
+// Visitor holds typed callbacks for use with Walk. Any nil callback is simply
+// not called. If a callback returns a non-nil error other than SkipChildren,
+// the walk stops and Walk returns that error.
+type Visitor struct {
+	VisitTable      func(t *Table) error
+	VisitColumn     func(t *Table, col *Column) error
+	VisitIndex      func(t *Table, idx *Index) error // includes the primary key, if any
+	VisitForeignKey func(t *Table, fk *ForeignKey) error
+	VisitCheck      func(t *Table, cc *Check) error
+	VisitPartition  func(t *Table, p *Partition) error
+	VisitRoutine    func(r *Routine) error
+}
+
+// Walk traverses the tables and routines of s, calling the corresponding
+// callbacks of v. Tables are visited in the order they appear in s.Tables,
+// and each table's children are visited immediately after the table itself,
+// in order: columns, primary key, secondary indexes, foreign keys, checks,
+// partitions. Routines are visited after all tables.
+func Walk(s *Schema, v Visitor) error {
+	if s == nil {
+		return nil
+	}
+	for _, t := range s.Tables {
+		if err := walkTable(t, v); err != nil {
+			return err
+		}
+	}
+	if v.VisitRoutine != nil {
+		for _, r := range s.Routines {
+			if err := v.VisitRoutine(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func walkTable(t *Table, v Visitor) error {
+	if v.VisitTable != nil {
+		if err := v.VisitTable(t); err == SkipChildren {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	if v.VisitColumn != nil {
+		for _, col := range t.Columns {
+			if err := v.VisitColumn(t, col); err != nil {
+				return err
+			}
+		}
+	}
+	if v.VisitIndex != nil {
+		if t.PrimaryKey != nil {
+			if err := v.VisitIndex(t, t.PrimaryKey); err != nil {
+				return err
+			}
+		}
+		for _, idx := range t.SecondaryIndexes {
+			if err := v.VisitIndex(t, idx); err != nil {
+				return err
+			}
+		}
+	}
+	if v.VisitForeignKey != nil {
+		for _, fk := range t.ForeignKeys {
+			if err := v.VisitForeignKey(t, fk); err != nil {
+				return err
+			}
+		}
+	}
+	if v.VisitCheck != nil {
+		for _, cc := range t.Checks {
+			if err := v.VisitCheck(t, cc); err != nil {
+				return err
+			}
+		}
+	}
+	if v.VisitPartition != nil && t.Partitioning != nil {
+		for _, p := range t.Partitioning.Partitions {
+			if err := v.VisitPartition(t, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package tengo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	t1 := aTable(1)
+	t2 := anotherTable()
+	r := aProc("latin1_swedish_ci", "")
+	s := aSchema("s1", &t1, &t2)
+	s.Routines = []*Routine{&r}
+
+	var visited []string
+	v := Visitor{
+		VisitTable:  func(t *Table) error { visited = append(visited, "table "+t.Name); return nil },
+		VisitColumn: func(t *Table, col *Column) error { visited = append(visited, "column "+col.Name); return nil },
+		VisitIndex:  func(t *Table, idx *Index) error { visited = append(visited, "index "+idx.Name); return nil },
+		VisitRoutine: func(r *Routine) error {
+			visited = append(visited, "routine "+r.Name)
+			return nil
+		},
+	}
+	if err := Walk(&s, v); err != nil {
+		t.Fatalf("Unexpected error from Walk: %v", err)
+	}
+	var expectCount int
+	for _, table := range s.Tables {
+		expectCount += 1 + len(table.Columns) + len(table.SecondaryIndexes)
+		if table.PrimaryKey != nil {
+			expectCount++
+		}
+	}
+	expectCount += len(s.Routines)
+	if len(visited) != expectCount {
+		t.Errorf("Expected %d visits, instead found %d: %v", expectCount, len(visited), visited)
+	}
+	if visited[0] != "table "+t1.Name || visited[1] != "column "+t1.Columns[0].Name || visited[len(visited)-1] != "routine proc1" {
+		t.Errorf("Unexpected visit order: %v", visited)
+	}
+
+	// SkipChildren should prevent column visits, without error
+	visited = nil
+	v.VisitTable = func(t *Table) error { return SkipChildren }
+	if err := Walk(&s, v); err != nil || fmt.Sprint(visited) != "[routine proc1]" {
+		t.Errorf("Unexpected result with SkipChildren: %v, %v", visited, err)
+	}
+
+	// Other errors should halt the walk and be returned
+	expectErr := errors.New("stop")
+	v.VisitTable = func(t *Table) error { return expectErr }
+	if err := Walk(&s, v); err != expectErr {
+		t.Errorf("Expected Walk to return callback error, instead found %v", err)
+	}
+
+	// Nil schema is a no-op
+	if err := Walk(nil, v); err != nil {
+		t.Errorf("Unexpected error walking nil schema: %v", err)
+	}
+}
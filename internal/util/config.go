@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/skeema/mybase"
@@ -34,12 +35,18 @@ func AddGlobalOptions(cmd *mybase.Command) {
 		mybase.StringOption("password", 'p', "$MYSQL_PWD", "Password for database user; omit value to prompt from TTY").ValueOptional(),
 		mybase.StringOption("host-wrapper", 'H', "", "External bin to shell out to for host lookup; see manual for template vars"),
 		mybase.StringOption("connect-options", 'o', "", "Comma-separated session options to set upon connecting to each database instance"),
+		mybase.StringOption("sql-mode", 0, "", "Session sql_mode to use upon connecting to each database instance, for pushes and workspaces"),
+		mybase.StringOption("session-init", 0, "", "Semicolon-separated SET statements for session variables to apply upon connecting to each database instance"),
 		mybase.StringOption("ignore-schema", 0, "", "Ignore schemas that match regex"),
 		mybase.StringOption("ignore-table", 0, "", "Ignore tables that match regex"),
 		mybase.StringOption("ignore-proc", 0, "", "Ignore stored procedures that match regex"),
 		mybase.StringOption("ignore-func", 0, "", "Ignore functions that match regex"),
+		mybase.StringOption("ignore-view", 0, "", "Ignore views that match regex"),
+		mybase.StringOption("ignore-trigger", 0, "", "Ignore triggers that match regex"),
 		mybase.StringOption("ssl-mode", 0, "", `Specify desired connection security SSL/TLS usage (valid values: "disabled", "preferred", "required")`),
 		mybase.BoolOption("debug", 0, false, "Enable debug logging"),
+		mybase.BoolOption("debug-timing", 0, false, "Log elapsed time of each connection, introspection query, diff, and statement"),
+		mybase.StringOption("introspection-retries", 0, "0", "Detect schema changes made during introspection, retrying up to this many times"),
 		mybase.BoolOption("my-cnf", 0, true, "Parse ~/.my.cnf for configuration"),
 	)
 }
@@ -135,6 +142,18 @@ func ProcessSpecialGlobalOptions(cfg *mybase.Config) error {
 	if cfg.GetBool("debug") {
 		log.SetLevel(log.DebugLevel)
 	}
+	retries, err := cfg.GetInt("introspection-retries")
+	if err != nil {
+		return err
+	} else if retries < 0 {
+		return errors.New("Option introspection-retries cannot be negative")
+	}
+	tengo.SetIntrospectionRetries(retries)
+	if cfg.GetBool("debug-timing") {
+		tengo.SetTimingFunc(func(phase string, elapsed time.Duration) {
+			log.Infof("Timing: %s took %s", phase, elapsed.Round(time.Microsecond))
+		})
+	}
 
 	return nil
 }
@@ -327,6 +346,89 @@ func RealConnectOptions(connectOpts string) (string, error) {
 	return connectOpts, nil
 }
 
+// ParseSessionInit takes a string containing one or more semicolon-separated
+// SET statements (typically obtained from the "session-init" option) and
+// returns a map of session variable names to values. Only session-level system
+// variables may be assigned; GLOBAL or PERSIST scope and user-defined variables
+// are not permitted. Values are returned as-is, in SQL literal form, suitable
+// for use in a DSN.
+func ParseSessionInit(input string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, stmt := range splitOutsideQuotes(input, ';') {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		if len(stmt) < 4 || !strings.EqualFold(stmt[0:4], "SET ") {
+			return nil, fmt.Errorf("session-init may only contain SET statements, but found %q", stmt)
+		}
+		for _, assignment := range splitOutsideQuotes(stmt[4:], ',') {
+			name, value, ok := strings.Cut(assignment, "=")
+			if !ok {
+				return nil, fmt.Errorf("Invalid assignment %q in session-init", strings.TrimSpace(assignment))
+			}
+			name = strings.TrimSuffix(strings.TrimSpace(name), ":") // permit := operator
+			name, err := sessionVariableName(name)
+			if err != nil {
+				return nil, err
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, fmt.Errorf("Variable %s is missing a value in session-init", name)
+			}
+			if _, already := result[name]; already {
+				return nil, fmt.Errorf("Variable %s is set multiple times in session-init", name)
+			}
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+var reSessionVarName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// sessionVariableName strips any session scope qualifier from the supplied
+// variable name in a SET statement. An error is returned if the name refers to
+// a user-defined variable, or to a non-session scope.
+func sessionVariableName(name string) (string, error) {
+	lower := strings.ToLower(name)
+	for _, prefix := range []string{"@@session.", "@@local.", "session ", "local ", "@@"} {
+		if strings.HasPrefix(lower, prefix) {
+			name = strings.TrimSpace(name[len(prefix):])
+			break
+		}
+	}
+	if strings.HasPrefix(name, "@") {
+		return "", fmt.Errorf("session-init may not set user-defined variable %s", name)
+	} else if !reSessionVarName.MatchString(name) {
+		return "", fmt.Errorf("session-init may only set session variables, but found %q", name)
+	}
+	return strings.ToLower(name), nil
+}
+
+// splitOutsideQuotes splits input on each occurrence of delimiter which is not
+// within a single-quoted or double-quoted string. Backslash escapes and
+// doubled quotes within quoted strings are handled.
+func splitOutsideQuotes(input string, delimiter byte) (result []string) {
+	var quote byte
+	var start int
+	for n := 0; n < len(input); n++ {
+		c := input[n]
+		switch {
+		case quote != 0 && c == '\\':
+			n++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0 && c == delimiter:
+			result = append(result, input[start:n])
+			start = n + 1
+		}
+	}
+	return append(result, input[start:])
+}
+
 // This mapping of ignore-options to object types is stored in a slice (rather
 // than a map) to ensure consistent sort order of the result of IgnorePatterns.
 // ignore-schema is intentionally omitted here, as that needs special handling
@@ -338,6 +440,8 @@ var ignoreOptionToTypes = []struct {
 	{"ignore-table", []tengo.ObjectType{tengo.ObjectTypeTable}},
 	{"ignore-proc", []tengo.ObjectType{tengo.ObjectTypeProc}},
 	{"ignore-func", []tengo.ObjectType{tengo.ObjectTypeFunc}},
+	{"ignore-view", []tengo.ObjectType{tengo.ObjectTypeView}},
+	{"ignore-trigger", []tengo.ObjectType{tengo.ObjectTypeTrigger}},
 }
 
 // IgnorePatterns compiles the regexes in the supplied mybase.Config's ignore-*
@@ -277,18 +277,52 @@ func TestRealConnectOptions(t *testing.T) {
 	}
 }
 
+func TestParseSessionInit(t *testing.T) {
+	expected := map[string]map[string]string{
+		"":                               {},
+		"SET innodb_strict_mode=1":       {"innodb_strict_mode": "1"},
+		"set SESSION sql_mode = 'a,b' ;": {"sql_mode": "'a,b'"},
+		"SET @@session.foo := 'x;y', @@bar=2; SET LOCAL Baz=\"q\"": {"foo": "'x;y'", "bar": "2", "baz": `"q"`},
+		`SET a='it\'s', b='it''s'`:                                 {"a": `'it\'s'`, "b": "'it''s'"},
+	}
+	for input, expectVars := range expected {
+		actual, err := ParseSessionInit(input)
+		if err != nil {
+			t.Errorf("Unexpected error from ParseSessionInit(%q): %v", input, err)
+		} else if !reflect.DeepEqual(actual, expectVars) {
+			t.Errorf("Expected ParseSessionInit(%q) to return %v, instead found %v", input, expectVars, actual)
+		}
+	}
+
+	expectError := []string{
+		"SELECT 1",
+		"SET GLOBAL innodb_strict_mode=1",
+		"SET @@global.foo=1",
+		"SET @foo=1",
+		"SET foo",
+		"SET foo=",
+		"SET foo=1, FOO=2",
+		"SET foo=1; SET persist bar=2",
+	}
+	for _, input := range expectError {
+		if _, err := ParseSessionInit(input); err == nil {
+			t.Errorf("Expected error from ParseSessionInit(%q), but err was nil", input)
+		}
+	}
+}
+
 func TestIgnorePatterns(t *testing.T) {
 	cmd := mybase.NewCommand("skeematest", "", "", nil)
 	AddGlobalOptions(cmd)
-	cfg := mybase.ParseFakeCLI(t, cmd, `skeematest --ignore-table='foo' --ignore-proc='.'`)
+	cfg := mybase.ParseFakeCLI(t, cmd, `skeematest --ignore-table='foo' --ignore-proc='.' --ignore-view='^v_' --ignore-trigger='_audit$'`)
 	ignore, err := IgnorePatterns(cfg)
 	if err != nil {
 		t.Fatalf("Unexpected error from IgnorePatterns: %v", err)
 	}
 
 	// Confirm length of result
-	if len(ignore) != 2 {
-		t.Fatalf("Expected IgnorePatterns to return 2 patterns, instead found %d", len(ignore))
+	if len(ignore) != 4 {
+		t.Fatalf("Expected IgnorePatterns to return 4 patterns, instead found %d", len(ignore))
 	}
 
 	// Confirm functionality
@@ -310,6 +344,10 @@ func TestIgnorePatterns(t *testing.T) {
 	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "foobert"}, true)
 	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeProc, Name: "WHATEVER"}, true)
 	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeFunc, Name: "foobar"}, false)
+	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeView, Name: "v_bar"}, true)
+	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTable, Name: "v_bar"}, false)
+	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTrigger, Name: "actor_audit"}, true)
+	assertShouldIgnore(tengo.ObjectKey{Type: tengo.ObjectTypeTrigger, Name: "actor_audit_ins"}, false)
 
 	// Confirm consistent sort order for result
 	ignore2, _ := IgnorePatterns(cfg)
@@ -322,3 +360,21 @@ func TestIgnorePatterns(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessSpecialGlobalOptionsIntrospectionRetries(t *testing.T) {
+	cmdSuite := mybase.NewCommandSuite("skeematest", "", "")
+	AddGlobalOptions(cmdSuite)
+	cmdSuite.AddSubCommand(mybase.NewCommand("diff", "", "", nil))
+	defer tengo.SetIntrospectionRetries(0)
+
+	cfg := mybase.ParseFakeCLI(t, cmdSuite, "skeema diff --introspection-retries=3")
+	if err := ProcessSpecialGlobalOptions(cfg); err != nil {
+		t.Errorf("Unexpected error from ProcessSpecialGlobalOptions: %s", err)
+	}
+	for _, badValue := range []string{"-1", "lots"} {
+		cfg = mybase.ParseFakeCLI(t, cmdSuite, "skeema diff --introspection-retries="+badValue)
+		if err := ProcessSpecialGlobalOptions(cfg); err == nil {
+			t.Errorf("Expected error from ProcessSpecialGlobalOptions with introspection-retries=%s, but err was nil", badValue)
+		}
+	}
+}
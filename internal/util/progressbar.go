@@ -0,0 +1,78 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ProgressBar renders a single-line progress bar, redrawing it in place upon
+// each update. It should only be used when its writer is a terminal.
+type ProgressBar struct {
+	w       io.Writer
+	width   int
+	lastLen int
+	m       sync.Mutex
+}
+
+// NewProgressBar returns a ProgressBar which writes to w, limiting its output
+// to the supplied terminal width. A width below 40 is treated as 80.
+func NewProgressBar(w io.Writer, width int) *ProgressBar {
+	if width < 40 {
+		width = 80
+	}
+	return &ProgressBar{w: w, width: width}
+}
+
+// Update redraws the progress bar to reflect done out of total, followed by
+// the supplied label. The label is truncated if necessary to fit.
+func (pb *ProgressBar) Update(done, total int, label string) {
+	var fraction float64
+	if total > 0 {
+		fraction = float64(done) / float64(total)
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	const barWidth = 20
+	filled := int(fraction * barWidth)
+	line := fmt.Sprintf("[%s%s] %3d%% %s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), int(fraction*100), label)
+	if runes := []rune(line); len(runes) > pb.width-1 {
+		line = string(runes[:pb.width-1])
+	}
+
+	pb.m.Lock()
+	defer pb.m.Unlock()
+	pad := pb.lastLen - len(line)
+	if pad < 0 {
+		pad = 0
+	}
+	fmt.Fprintf(pb.w, "\r%s%s", line, strings.Repeat(" ", pad))
+	pb.lastLen = len(line)
+}
+
+// Clear erases the progress bar from the current line.
+func (pb *ProgressBar) Clear() {
+	pb.m.Lock()
+	defer pb.m.Unlock()
+	if pb.lastLen > 0 {
+		fmt.Fprintf(pb.w, "\r%s\r", strings.Repeat(" ", pb.lastLen))
+		pb.lastLen = 0
+	}
+}
+
+// FormatBytes returns a human-readable representation of a byte count, using
+// binary units.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	var b strings.Builder
+	pb := NewProgressBar(&b, 0)
+	pb.Update(1, 4, "1/4 schemas")
+	if expected := "\r[=====               ]  25% 1/4 schemas"; b.String() != expected {
+		t.Errorf("Unexpected output: %q", b.String())
+	}
+	b.Reset()
+	pb.Update(4, 4, "done")
+	if expected := "\r[====================] 100% done" + strings.Repeat(" ", 7); b.String() != expected {
+		t.Errorf("Unexpected output: %q", b.String())
+	}
+	b.Reset()
+	pb.Clear()
+	if expected := "\r" + strings.Repeat(" ", 32) + "\r"; b.String() != expected {
+		t.Errorf("Unexpected output from Clear: %q", b.String())
+	}
+
+	// Output should be truncated to terminal width
+	pb = NewProgressBar(&b, 40)
+	b.Reset()
+	pb.Update(0, 0, strings.Repeat("x", 100))
+	if len(b.String()) != 40 {
+		t.Errorf("Expected output to be truncated to 40 bytes including carriage return, instead found %d", len(b.String()))
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1024:                   "1.0 KiB",
+		1536:                   "1.5 KiB",
+		5 * 1024 * 1024 * 1024: "5.0 GiB",
+	}
+	for input, expected := range cases {
+		if actual := FormatBytes(input); actual != expected {
+			t.Errorf("FormatBytes(%d): expected %q, found %q", input, expected, actual)
+		}
+	}
+}
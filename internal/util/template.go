@@ -0,0 +1,36 @@
+package util
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are available to all templates rendered by RenderTemplateFile,
+// in addition to the builtin text/template functions.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+}
+
+// RenderTemplateFile parses the Go text/template in the file at path, and
+// executes it against data, writing the output to w.
+func RenderTemplateFile(path string, data interface{}, w io.Writer) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(contents))
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
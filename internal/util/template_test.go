@@ -0,0 +1,38 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.tmpl")
+	contents := "{{upper .Name}}: {{join .Items \", \"}}\n{{indent 2 .Body}}"
+	if err := os.WriteFile(path, []byte(contents), 0666); err != nil {
+		t.Fatalf("Unable to write template: %v", err)
+	}
+	data := struct {
+		Name  string
+		Items []string
+		Body  string
+	}{"foo", []string{"a", "b"}, "line1\nline2"}
+	var b strings.Builder
+	if err := RenderTemplateFile(path, data, &b); err != nil {
+		t.Fatalf("Unexpected error from RenderTemplateFile: %v", err)
+	}
+	if expected := "FOO: a, b\n  line1\n  line2"; b.String() != expected {
+		t.Errorf("Unexpected output from RenderTemplateFile: %q", b.String())
+	}
+
+	if err := os.WriteFile(path, []byte("{{.Name"), 0666); err != nil {
+		t.Fatalf("Unable to write template: %v", err)
+	}
+	if err := RenderTemplateFile(path, data, &b); err == nil {
+		t.Error("Expected error from invalid template, but err was nil")
+	}
+	if err := RenderTemplateFile(path+".missing", data, &b); err == nil {
+		t.Error("Expected error from nonexistent template file, but err was nil")
+	}
+}
@@ -22,6 +22,7 @@ type LocalDocker struct {
 	releaseLock       releaseFunc
 	cleanupAction     CleanupAction
 	defaultConnParams string
+	timeZone          string
 }
 
 var cstore struct {
@@ -59,7 +60,6 @@ func NewLocalDocker(opts Options) (_ *LocalDocker, retErr error) {
 		cleanupAction:     opts.CleanupAction,
 		defaultConnParams: opts.DefaultConnParams,
 	}
-
 	image := opts.Flavor.String()
 	if arch, _ := cstore.dockerClient.ServerArchitecture(); arch == "arm64" && opts.Flavor.IsMySQL() {
 		// MySQL 8 images are available for arm64 on DockerHub, but via
@@ -88,6 +88,13 @@ func NewLocalDocker(opts Options) (_ *LocalDocker, retErr error) {
 		if opts.NameCaseMode == tengo.NameCaseLower {
 			commandArgs = append(commandArgs, "--lower-case-table-names=1")
 		}
+
+		// Similarly, mirror the real inst's explicit_defaults_for_timestamp, which
+		// affects how TIMESTAMP columns are created. (The time zone is handled
+		// separately via a session variable; see ConnectionPool().)
+		if opts.TemporalSettings != nil && !opts.Flavor.Matches(tengo.FlavorMySQL55) {
+			commandArgs = append(commandArgs, "--explicit-defaults-for-timestamp="+boolToOnOff(opts.TemporalSettings.ExplicitDefaultsForTimestamp))
+		}
 		log.Infof("Using container %s (image=%s) for workspace operations", opts.ContainerName, image)
 		ld.d, err = cstore.dockerClient.GetOrCreateInstance(tengo.DockerizedInstanceOptions{
 			Name:              opts.ContainerName,
@@ -105,6 +112,28 @@ func NewLocalDocker(opts Options) (_ *LocalDocker, retErr error) {
 		}
 	}
 
+	// A pre-existing container may have been created with a different
+	// explicit_defaults_for_timestamp; this cannot be changed without restarting it
+	if opts.TemporalSettings != nil {
+		if actual, err := ld.d.TemporalSettings(); err == nil && actual.ExplicitDefaultsForTimestamp != opts.TemporalSettings.ExplicitDefaultsForTimestamp {
+			log.Warnf("Container %s has explicit_defaults_for_timestamp=%s, but the database server has %s. TIMESTAMP columns may display spurious differences. To resolve, destroy the container so that it is recreated with the correct setting.",
+				opts.ContainerName, boolToOnOff(actual.ExplicitDefaultsForTimestamp), boolToOnOff(opts.TemporalSettings.ExplicitDefaultsForTimestamp))
+		}
+
+		// Mirror the real inst's named time zone if the container has time zone
+		// tables loaded, so that DST is handled identically. Otherwise fall back to
+		// its current UTC offset, which is wrong for dates on the other side of a
+		// DST transition.
+		ld.timeZone = opts.TemporalSettings.TimeZoneOffset
+		if tz := opts.TemporalSettings.TimeZone; tz != "" {
+			if ok, err := ld.d.HasTimeZone(tz); ok && err == nil {
+				ld.timeZone = tz
+			} else {
+				log.Debugf("Container %s does not recognize time zone %s; using offset %s instead", opts.ContainerName, tz, ld.timeZone)
+			}
+		}
+	}
+
 	lockName := fmt.Sprintf("skeema.%s", ld.schemaName)
 	if ld.releaseLock, err = getLock(ld.d.Instance, lockName, opts.LockTimeout); err != nil {
 		return nil, fmt.Errorf("Unable to obtain lock on %s: %s", ld.d.Instance, err)
@@ -153,33 +182,56 @@ func (ld *LocalDocker) ConnectionPool(params string) (*sqlx.DB, error) {
 	// different sibling subdirectories with differing configurations).
 	// So, here we must merge the params arg (callsite-dependent) over top of the
 	// LocalDocker params (dir-dependent).
-	var finalParams string
-	if ld.defaultConnParams == "" && params == "" {
+	finalParams, err := mergeConnParams(ld.defaultConnParams, params, ld.timeZone)
+	if err != nil {
+		return nil, err
+	}
+	return ld.d.CachedConnectionPool(ld.schemaName, finalParams)
+}
+
+// mergeConnParams combines dir-dependent default connection params with
+// callsite-dependent params, which take precedence. TLS is always disabled, and
+// if timeZone is non-empty, the session time_zone is set to it unless
+// overridden by params.
+func mergeConnParams(defaultParams, params, timeZone string) (string, error) {
+	if defaultParams == "" && params == "" && timeZone == "" {
 		// By default, disable TLS for connections to the DockerizedInstance, since
 		// we know it's on the local machine
-		finalParams = "tls=false"
-	} else {
-		v, err := url.ParseQuery(ld.defaultConnParams)
-		if err != nil {
-			return nil, err
-		}
+		return "tls=false", nil
+	}
+	v, err := url.ParseQuery(defaultParams)
+	if err != nil {
+		return "", err
+	}
 
-		// Forcibly disable TLS, regardless of what was in ld.defaultConnParams.
-		// This is necessary since ld.defaultConnParams is typically populated using
-		// Dir.InstanceDefaultParams() which sets tls=preferred by default.
-		v.Set("tls", "false")
+	// Forcibly disable TLS, regardless of what was in defaultParams. This is
+	// necessary since defaultParams is typically populated using
+	// Dir.InstanceDefaultParams() which sets tls=preferred by default.
+	v.Set("tls", "false")
 
-		// Apply overrides from params arg
-		overrides, err := url.ParseQuery(params)
-		if err != nil {
-			return nil, err
-		}
-		for name := range overrides {
-			v.Set(name, overrides.Get(name))
-		}
-		finalParams = v.Encode()
+	// Use the real instance's time zone, as determined by NewLocalDocker, rather
+	// than any time zone from defaultParams, since the container may lack time
+	// zone tables
+	if timeZone != "" {
+		v.Set("time_zone", "'"+timeZone+"'")
 	}
-	return ld.d.CachedConnectionPool(ld.schemaName, finalParams)
+
+	// Apply overrides from params arg
+	overrides, err := url.ParseQuery(params)
+	if err != nil {
+		return "", err
+	}
+	for name := range overrides {
+		v.Set(name, overrides.Get(name))
+	}
+	return v.Encode(), nil
+}
+
+func boolToOnOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
 }
 
 // IntrospectSchema introspects and returns the temporary workspace schema.
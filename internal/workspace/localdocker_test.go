@@ -194,3 +194,28 @@ func (s WorkspaceIntegrationSuite) TestLocalDockerConnParams(t *testing.T) {
 		t.Errorf("Unexpected error from cleanup: %s", err)
 	}
 }
+
+func TestMergeConnParams(t *testing.T) {
+	cases := []struct {
+		defaultParams string
+		params        string
+		timeZone      string
+		expected      string
+	}{
+		{"", "", "", "tls=false"},
+		{"tls=preferred&wait_timeout=10", "", "", "tls=false&wait_timeout=10"},
+		{"wait_timeout=10", "wait_timeout=20&foreign_key_checks=0", "", "foreign_key_checks=0&tls=false&wait_timeout=20"},
+		{"", "", "-05:00", "time_zone=%27-05%3A00%27&tls=false"},
+		{"time_zone=%27America%2FNew_York%27", "", "+00:00", "time_zone=%27%2B00%3A00%27&tls=false"},
+		{"", "time_zone=%27%2B01%3A00%27", "+00:00", "time_zone=%27%2B01%3A00%27&tls=false"},
+		{"", "", "America/New_York", "time_zone=%27America%2FNew_York%27&tls=false"},
+	}
+	for n, c := range cases {
+		actual, err := mergeConnParams(c.defaultParams, c.params, c.timeZone)
+		if err != nil {
+			t.Errorf("Case %d: unexpected error: %v", n, err)
+		} else if actual != c.expected {
+			t.Errorf("Case %d: expected %q, found %q", n, c.expected, actual)
+		}
+	}
+}
@@ -87,6 +87,9 @@ func NewTempSchema(opts Options) (_ *TempSchema, retErr error) {
 		if err := ts.inst.DropRoutinesInSchema(ts.schemaName, dropOpts); err != nil {
 			return nil, fmt.Errorf("Cannot drop existing temp schema routines on %s: %s", ts.inst, err)
 		}
+		if err := ts.inst.DropViewsInSchema(ts.schemaName, dropOpts); err != nil {
+			return nil, fmt.Errorf("Cannot drop existing temp schema views on %s: %s", ts.inst, err)
+		}
 		if err := ts.inst.AlterSchema(ts.schemaName, createOpts); err != nil {
 			return nil, fmt.Errorf("Cannot alter existing temp schema charset and collation on %s: %s", ts.inst, err)
 		}
@@ -143,6 +146,9 @@ func (ts *TempSchema) Cleanup(schema *tengo.Schema) error {
 		if err := ts.inst.DropRoutinesInSchema(ts.schemaName, dropOpts); err != nil {
 			return fmt.Errorf("Cannot drop routines in temporary schema on %s: %s", ts.inst, err)
 		}
+		if err := ts.inst.DropViewsInSchema(ts.schemaName, dropOpts); err != nil {
+			return fmt.Errorf("Cannot drop views in temporary schema on %s: %s", ts.inst, err)
+		}
 	} else if err := ts.inst.DropSchema(ts.schemaName, dropOpts); err != nil {
 		return fmt.Errorf("Cannot drop temporary schema on %s: %s", ts.inst, err)
 	}
@@ -86,8 +86,9 @@ type Options struct {
 	DefaultConnParams   string // only TypeLocalDocker
 	RootPassword        string // only TypeLocalDocker
 	NameCaseMode        tengo.NameCaseMode
-	PrefabWorkspace     Workspace     // only TypePrefab
-	LockTimeout         time.Duration // max wait for workspace user-level locking, via GET_LOCK()
+	TemporalSettings    *tengo.TemporalSettings // only TypeLocalDocker; nil if unknown
+	PrefabWorkspace     Workspace               // only TypePrefab
+	LockTimeout         time.Duration           // max wait for workspace user-level locking, via GET_LOCK()
 	Concurrency         int
 	SkipBinlog          bool
 }
@@ -131,6 +132,13 @@ func OptionsForDir(dir *fs.Dir, instance *tengo.Instance) (Options, error) {
 			if !opts.Flavor.Known() {
 				opts.Flavor = instance.Flavor().Family()
 			}
+			// Mirror the real instance's time zone and timestamp behavior, so that
+			// temporal column defaults are displayed identically by the workspace
+			if settings, err := instance.TemporalSettings(); err != nil {
+				log.Debugf("Unable to determine temporal settings of %s: %s", instance, err)
+			} else {
+				opts.TemporalSettings = &settings
+			}
 		}
 		opts.ContainerName = "skeema-" + tengo.ContainerNameForImage(opts.Flavor.String())
 		if cleanup, err := dir.Config.GetEnum("docker-cleanup", "none", "stop", "destroy"); err != nil {
@@ -176,7 +184,7 @@ func AddCommandOptions(cmd *mybase.Command) {
 		mybase.StringOption("temp-schema", 't', "_skeema_tmp", "Name of temporary schema for intermediate operations, created and dropped each run"),
 		mybase.StringOption("temp-schema-binlog", 0, "auto", `Controls whether temp schema DDL operations are replicated (valid values: "on", "off", "auto")`),
 		mybase.StringOption("temp-schema-threads", 0, "5", "Max number of concurrent CREATE/DROP with workspace=temp-schema"),
-		mybase.StringOption("workspace", 'w', "temp-schema", `Specifies where to run intermediate operations (valid values: "temp-schema", "docker")`),
+		mybase.StringOption("workspace", 'w', "temp-schema", `Specifies where to run intermediate operations (valid values: "temp-schema", "docker"); docker mirrors the server's named time zone only if the container has time zone tables, otherwise its current UTC offset, and cannot mirror a SYSTEM time zone's DST rules`),
 		mybase.StringOption("docker-cleanup", 0, "none", `With --workspace=docker, specifies how to clean up containers (valid values: "none", "stop", "destroy")`),
 		mybase.BoolOption("reuse-temp-schema", 0, false, "Do not drop temp-schema when done").Hidden(), // DEPRECATED -- hidden for this reason
 	)
@@ -280,6 +288,7 @@ func (wsSchema *Schema) FailedKeys() (result []tengo.ObjectKey) {
 // Note that if opts.NameCaseMode > tengo.NameCaseAsIs, logicalSchema may be
 // modified in-place to force some identifiers to lowercase.
 func ExecLogicalSchema(logicalSchema *fs.LogicalSchema, opts Options) (_ *Schema, retErr error) {
+	defer tengo.StartTiming("Workspace load of %d CREATE statements", len(logicalSchema.Creates))()
 	if logicalSchema.CharSet != "" {
 		opts.DefaultCharacterSet = logicalSchema.CharSet
 	}
@@ -323,16 +332,28 @@ func ExecLogicalSchema(logicalSchema *fs.LogicalSchema, opts Options) (_ *Schema
 		return nil, fmt.Errorf("Cannot connect to workspace: %w", err)
 	}
 
+	// Views and triggers are held back until all other objects exist, since views
+	// may refer to tables, functions, or other views, and triggers require their
+	// table and any trigger they follow to already exist
+	var createStatements, deferredStatements []*tengo.Statement
+	for _, stmt := range logicalSchema.Creates {
+		if stmt.ObjectType == tengo.ObjectTypeView || stmt.ObjectType == tengo.ObjectTypeTrigger {
+			deferredStatements = append(deferredStatements, stmt)
+		} else {
+			createStatements = append(createStatements, stmt)
+		}
+	}
+
 	// Run CREATEs in parallel, bounded by opts.Concurrency
 	creates := make(chan *tengo.Statement, opts.Concurrency)
 	errs := make(chan error, opts.Concurrency)
 	go func() {
-		for _, stmt := range logicalSchema.Creates {
+		for _, stmt := range createStatements {
 			creates <- stmt
 		}
 		close(creates)
 	}()
-	for n := 0; n < len(logicalSchema.Creates) && n < opts.Concurrency; n++ {
+	for n := 0; n < len(createStatements) && n < opts.Concurrency; n++ {
 		go func() {
 			for stmt := range creates {
 				_, err := db.Exec(stmt.Body())
@@ -350,7 +371,7 @@ func ExecLogicalSchema(logicalSchema *fs.LogicalSchema, opts Options) (_ *Schema
 	// Also retry errors from CREATE TABLE...LIKE being run out-of-order (only once
 	// though; nested chains of CREATE TABLE...LIKE are unsupported)
 	sequentialStatements := []*tengo.Statement{}
-	for n := 0; n < len(logicalSchema.Creates); n++ {
+	for n := 0; n < len(createStatements); n++ {
 		if err := <-errs; err != nil {
 			stmterr := err.(*StatementError)
 			if tengo.IsDatabaseError(stmterr.Err, mysqlerr.ER_LOCK_DEADLOCK, mysqlerr.ER_LOCK_WAIT_TIMEOUT, mysqlerr.ER_NO_SUCH_TABLE) {
@@ -372,6 +393,25 @@ func ExecLogicalSchema(logicalSchema *fs.LogicalSchema, opts Options) (_ *Schema
 		}
 	}
 
+	// Run view and trigger CREATEs sequentially. Since views may refer to other
+	// views, and triggers may follow other triggers, any failures are retried in
+	// additional passes, for as long as each pass makes progress.
+	for len(deferredStatements) > 0 {
+		var failedStatements []*tengo.Statement
+		var failures []*StatementError
+		for _, statement := range deferredStatements {
+			if _, err := db.Exec(statement.Body()); err != nil {
+				failedStatements = append(failedStatements, statement)
+				failures = append(failures, wrapFailure(statement, err))
+			}
+		}
+		if len(failedStatements) == len(deferredStatements) {
+			wsSchema.Failures = append(wsSchema.Failures, failures...)
+			break
+		}
+		deferredStatements = failedStatements
+	}
+
 	wsSchema.Schema, err = ws.IntrospectSchema()
 	return wsSchema, err
 }
@@ -0,0 +1,92 @@
+// Package drift reports whether a live database schema has drifted from its
+// expected definition, in a form suitable for wiring into a service's health
+// endpoint or a periodic job. It depends only on Skeema's public tengo API,
+// not on the CLI or its option files.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/skeema/skeema/pkg/tengo"
+)
+
+// Config specifies the schemas to compare.
+type Config struct {
+	DSN             string                // DSN of the live database server, in go-sql-driver/mysql format
+	Schema          string                // name of the schema on the live server
+	Dir             string                // directory containing the expected *.sql definitions
+	WorkspaceSchema string                // temporary schema for evaluating Dir; defaults to "_skeema_tmp"
+	Options         []tengo.CompareOption // additional options, such as tengo.WithIgnore
+}
+
+// Status is the result of a drift check.
+type Status struct {
+	Drifted    bool      `json:"drifted"`
+	Summary    string    `json:"summary"`
+	Statements []string  `json:"statements,omitempty"` // DDL which would bring the live schema back in line
+	CheckedAt  time.Time `json:"checked_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Check compares the live schema to the expected definitions in cfg.Dir. The
+// temporary workspace schema is created on the same server as the live
+// schema, so the DSN's user requires privileges to create and drop it.
+func Check(cfg Config) (Status, error) {
+	status := Status{CheckedAt: time.Now().UTC()}
+	inst, err := tengo.NewInstance("mysql", cfg.DSN)
+	if err != nil {
+		return status, err
+	}
+	defer inst.CloseAll()
+	wsSchema := cfg.WorkspaceSchema
+	if wsSchema == "" {
+		wsSchema = "_skeema_tmp"
+	}
+	opts := append([]tengo.CompareOption{tengo.WithWorkspace(inst, wsSchema), tengo.WithAllowUnsafe(true)}, cfg.Options...)
+	result, err := tengo.Compare(tengo.InstanceSource(inst, cfg.Schema), tengo.DirSource(cfg.Dir), opts...)
+	if err != nil {
+		return status, err
+	}
+	status.Drifted = !result.Empty()
+	status.Statements = result.Statements
+	status.Summary = summarize(result)
+	return status, nil
+}
+
+func summarize(result *tengo.CompareResult) string {
+	if result.Empty() {
+		return "no drift"
+	}
+	var parts []string
+	if n := len(result.Statements); n == 1 {
+		parts = append(parts, "1 statement required")
+	} else if n > 1 {
+		parts = append(parts, fmt.Sprintf("%d statements required", n))
+	}
+	if n := len(result.Unsupported); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unsupported object(s) differ", n))
+	}
+	return "drift detected: " + strings.Join(parts, ", ")
+}
+
+// Handler returns an http.Handler which runs Check on each request, responding
+// with a JSON Status. The response status code is 200 if there is no drift,
+// or 503 if drift was detected or the check failed.
+func Handler(cfg Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, err := Check(cfg)
+		if err != nil {
+			status.Error = err.Error()
+			status.Summary = "check failed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil || status.Drifted {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
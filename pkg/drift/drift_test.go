@@ -0,0 +1,42 @@
+package drift
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skeema/skeema/pkg/tengo"
+)
+
+func TestSummarize(t *testing.T) {
+	cases := []struct {
+		result   tengo.CompareResult
+		expected string
+	}{
+		{tengo.CompareResult{}, "no drift"},
+		{tengo.CompareResult{Statements: []string{"DROP TABLE `foo`"}}, "drift detected: 1 statement required"},
+		{tengo.CompareResult{Statements: []string{"a", "b"}, Unsupported: []tengo.ObjectKey{{Type: tengo.ObjectTypeTable, Name: "c"}}}, "drift detected: 2 statements required, 1 unsupported object(s) differ"},
+	}
+	for _, c := range cases {
+		if actual := summarize(&c.result); actual != c.expected {
+			t.Errorf("Expected summary %q, instead found %q", c.expected, actual)
+		}
+	}
+}
+
+func TestHandlerError(t *testing.T) {
+	cfg := Config{DSN: "this is not a DSN", Schema: "product", Dir: t.TempDir()}
+	rec := httptest.NewRecorder()
+	Handler(cfg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, instead found %d", rec.Code)
+	}
+	var status Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Unable to decode response: %v", err)
+	}
+	if status.Error == "" || status.CheckedAt.IsZero() {
+		t.Errorf("Unexpected response body: %+v", status)
+	}
+}
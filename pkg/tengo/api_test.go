@@ -0,0 +1,151 @@
+package tengo
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestAPISurface fails whenever the exported surface of this package changes,
+// including fields and methods of the types aliased from the internal tengo
+// package. If the change is intentional, bump APIVersion accordingly and
+// update testdata/api.txt to match.
+func TestAPISurface(t *testing.T) {
+	contents, err := os.ReadFile(filepath.Join("testdata", "api.txt"))
+	if err != nil {
+		t.Fatalf("Unable to read testdata/api.txt: %v", err)
+	}
+	expected := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	actual := append([]string{"APIVersion " + APIVersion}, apiSurface(t)...)
+
+	expectedSet := make(map[string]bool, len(expected))
+	for _, line := range expected {
+		expectedSet[line] = true
+	}
+	actualSet := make(map[string]bool, len(actual))
+	for _, line := range actual {
+		actualSet[line] = true
+		if !expectedSet[line] {
+			t.Errorf("Exported API surface has new or changed entry: %s", line)
+		}
+	}
+	for _, line := range expected {
+		if !actualSet[line] {
+			t.Errorf("Exported API surface no longer has entry: %s", line)
+		}
+	}
+	if t.Failed() {
+		t.Log("If this change is intentional, bump APIVersion and update testdata/api.txt")
+	}
+}
+
+// apiSurface returns a sorted description of every exported identifier
+// declared in this package, along with the exported fields and methods of
+// each aliased type.
+func apiSurface(t *testing.T) []string {
+	t.Helper()
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("Unable to parse package: %v", err)
+	}
+	render := func(node interface{}) string {
+		var buf bytes.Buffer
+		printer.Fprint(&buf, fset, node)
+		return strings.Join(strings.Fields(buf.String()), " ")
+	}
+
+	var result []string
+	for _, file := range pkgs["tengo"].Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.IsExported() && decl.Recv == nil {
+					result = append(result, "func "+decl.Name.Name+strings.TrimPrefix(render(decl.Type), "func"))
+				} else if decl.Name.IsExported() {
+					result = append(result, "method "+render(decl.Recv.List[0].Type)+"."+decl.Name.Name+strings.TrimPrefix(render(decl.Type), "func"))
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() && spec.Assign.IsValid() {
+							result = append(result, "type "+spec.Name.Name+" = "+render(spec.Type))
+						} else if spec.Name.IsExported() {
+							result = append(result, "type "+spec.Name.Name+" "+render(spec.Type))
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.IsExported() {
+								result = append(result, decl.Tok.String()+" "+name.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	aliased := []reflect.Type{
+		reflect.TypeOf((*Instance)(nil)).Elem(),
+		reflect.TypeOf((*Schema)(nil)).Elem(),
+		reflect.TypeOf((*Table)(nil)).Elem(),
+		reflect.TypeOf((*Column)(nil)).Elem(),
+		reflect.TypeOf((*Index)(nil)).Elem(),
+		reflect.TypeOf((*ForeignKey)(nil)).Elem(),
+		reflect.TypeOf((*Check)(nil)).Elem(),
+		reflect.TypeOf((*Partition)(nil)).Elem(),
+		reflect.TypeOf((*Routine)(nil)).Elem(),
+		reflect.TypeOf((*View)(nil)).Elem(),
+		reflect.TypeOf((*Trigger)(nil)).Elem(),
+		reflect.TypeOf((*Flavor)(nil)).Elem(),
+		reflect.TypeOf((*ObjectKey)(nil)).Elem(),
+		reflect.TypeOf((*ObjectType)(nil)).Elem(),
+		reflect.TypeOf((*SchemaDiff)(nil)).Elem(),
+		reflect.TypeOf((*ObjectDiff)(nil)).Elem(),
+		reflect.TypeOf((*StatementModifiers)(nil)).Elem(),
+		reflect.TypeOf((*Visitor)(nil)).Elem(),
+		reflect.TypeOf((*TableBuilder)(nil)).Elem(),
+		reflect.TypeOf((*ColumnOption)(nil)).Elem(),
+		reflect.TypeOf((*Node)(nil)).Elem(),
+		reflect.TypeOf((*Pos)(nil)).Elem(),
+		reflect.TypeOf((*CreateTableNode)(nil)).Elem(),
+		reflect.TypeOf((*ColumnDefNode)(nil)).Elem(),
+		reflect.TypeOf((*IndexDefNode)(nil)).Elem(),
+		reflect.TypeOf((*ForeignKeyDefNode)(nil)).Elem(),
+		reflect.TypeOf((*CheckDefNode)(nil)).Elem(),
+		reflect.TypeOf((*CreateRoutineNode)(nil)).Elem(),
+		reflect.TypeOf((*ParamNode)(nil)).Elem(),
+	}
+	for _, typ := range aliased {
+		result = append(result, fmt.Sprintf("alias %s %s", typ.Name(), typ.Kind()))
+		if typ.Kind() == reflect.Struct {
+			for n := 0; n < typ.NumField(); n++ {
+				if field := typ.Field(n); field.IsExported() {
+					result = append(result, fmt.Sprintf("field %s.%s %s", typ.Name(), field.Name, field.Type))
+				}
+			}
+		}
+		methodSet := typ
+		if typ.Kind() != reflect.Interface {
+			methodSet = reflect.PtrTo(typ)
+		}
+		for n := 0; n < methodSet.NumMethod(); n++ {
+			method := methodSet.Method(n)
+			result = append(result, fmt.Sprintf("method %s.%s %s", typ.Name(), method.Name, method.Type))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,326 @@
+package tengo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skeema/skeema/internal/fs"
+	"github.com/skeema/skeema/internal/snapshot"
+	"github.com/skeema/skeema/internal/tengo"
+	"github.com/skeema/skeema/internal/workspace"
+)
+
+// Source supplies one side of a comparison performed by Compare. Obtain a
+// Source via SchemaSource, InstanceSource, DirSource, or SnapshotSource.
+type Source interface {
+	load(cfg *compareConfig) (*Schema, error)
+	String() string
+}
+
+// CompareOption adjusts the behavior of Compare.
+type CompareOption func(*compareConfig)
+
+type compareConfig struct {
+	mods              tengo.StatementModifiers
+	ignore            []tengo.ObjectPattern
+	workspaceInstance *Instance
+	workspaceSchema   string
+	err               error
+}
+
+// WithAllowUnsafe controls whether Compare generates DDL for destructive
+// changes, such as dropping a table or column. By default such changes are
+// reported in CompareResult.Unsafe instead of CompareResult.Statements.
+func WithAllowUnsafe(allow bool) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.mods.AllowUnsafe = allow
+	}
+}
+
+// WithAlterAlgorithm adds an ALGORITHM clause with the supplied value, for
+// example "INPLACE", to each generated ALTER TABLE.
+func WithAlterAlgorithm(algorithm string) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.mods.AlgorithmClause = strings.ToUpper(algorithm)
+	}
+}
+
+// WithAlterLock adds a LOCK clause with the supplied value, for example
+// "NONE", to each generated ALTER TABLE.
+func WithAlterLock(lock string) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.mods.LockClause = strings.ToUpper(lock)
+	}
+}
+
+// WithFlavor adjusts generated DDL to match the supplied Flavor. If omitted,
+// the flavor of the first InstanceSource is used, if any.
+func WithFlavor(flavor Flavor) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.mods.Flavor = flavor
+	}
+}
+
+// WithIgnore excludes objects of the supplied type whose names match the
+// regular expression pattern. It may be supplied multiple times.
+func WithIgnore(objType ObjectType, pattern string) CompareOption {
+	return func(cfg *compareConfig) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			cfg.err = fmt.Errorf("Invalid ignore pattern for %s: %w", objType, err)
+			return
+		}
+		cfg.ignore = append(cfg.ignore, tengo.ObjectPattern{Type: objType, Pattern: re})
+	}
+}
+
+// WithWorkspace specifies an Instance on which Compare may create a temporary
+// schema, named tempSchema, for evaluating the CREATE statements of a
+// DirSource or SnapshotSource. The temporary schema is dropped afterwards. This
+// option is required when using either of those source types.
+func WithWorkspace(inst *Instance, tempSchema string) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.workspaceInstance = inst
+		cfg.workspaceSchema = tempSchema
+	}
+}
+
+// CompareResult is the structured result of Compare.
+type CompareResult struct {
+	From        *Schema
+	To          *Schema
+	Diff        *SchemaDiff
+	Statements  []string    // DDL to transform From into To, in execution order
+	Unsafe      []ObjectKey // objects with destructive changes omitted from Statements
+	Unsupported []ObjectKey // objects whose changes cannot be expressed as DDL
+}
+
+// Empty returns true if the two sources had no differences.
+func (r *CompareResult) Empty() bool {
+	return len(r.Statements)+len(r.Unsafe)+len(r.Unsupported) == 0
+}
+
+// Compare loads a schema from each of the supplied sources, and returns the
+// differences required to transform from into to. Objects excluded by any
+// WithIgnore option are disregarded on both sides.
+func Compare(from, to Source, opts ...CompareOption) (*CompareResult, error) {
+	cfg := &compareConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	if !cfg.mods.Flavor.Known() {
+		for _, src := range []Source{from, to} {
+			if is, ok := src.(instanceSource); ok {
+				cfg.mods.Flavor = is.inst.Flavor()
+				break
+			}
+		}
+	}
+
+	result := &CompareResult{}
+	var err error
+	if result.From, err = from.load(cfg); err != nil {
+		return nil, fmt.Errorf("Unable to load %s: %w", from, err)
+	}
+	if result.To, err = to.load(cfg); err != nil {
+		return nil, fmt.Errorf("Unable to load %s: %w", to, err)
+	}
+	result.Diff = tengo.NewSchemaDiff(result.From, result.To)
+
+	for _, objDiff := range result.Diff.ObjectDiffs() {
+		if cfg.ignored(objDiff) {
+			continue
+		}
+		stmt, err := objDiff.Statement(cfg.mods)
+		if tengo.IsForbiddenDiff(err) {
+			result.Unsafe = append(result.Unsafe, objDiff.ObjectKey())
+		} else if tengo.IsUnsupportedDiff(err) {
+			result.Unsupported = append(result.Unsupported, objDiff.ObjectKey())
+		} else if err != nil {
+			return nil, err
+		} else if stmt != "" {
+			result.Statements = append(result.Statements, stmt)
+		}
+	}
+	return result, nil
+}
+
+// CompareInstances is a convenience wrapper around Compare, for diffing a
+// schema on one live database server against a schema on another, for example
+// production vs staging. The resulting statements would transform fromSchema on
+// fromInst to match toSchema on toInst, and by default use fromInst's flavor.
+func CompareInstances(fromInst *Instance, fromSchema string, toInst *Instance, toSchema string, opts ...CompareOption) (*CompareResult, error) {
+	return Compare(InstanceSource(fromInst, fromSchema), InstanceSource(toInst, toSchema), opts...)
+}
+
+func (cfg *compareConfig) ignored(obj tengo.ObjectKeyer) bool {
+	for n := range cfg.ignore {
+		if cfg.ignore[n].Match(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaSource returns a Source for an already-obtained Schema. A nil Schema
+// represents a nonexistent schema.
+func SchemaSource(s *Schema) Source {
+	return schemaSource{s}
+}
+
+type schemaSource struct {
+	s *Schema
+}
+
+func (ss schemaSource) load(*compareConfig) (*Schema, error) {
+	return ss.s, nil
+}
+
+func (ss schemaSource) String() string {
+	if ss.s == nil {
+		return "nonexistent schema"
+	}
+	return "schema " + ss.s.Name
+}
+
+// InstanceSource returns a Source for the named schema on a live database
+// server. If the schema does not exist, it is treated as empty.
+func InstanceSource(inst *Instance, schemaName string) Source {
+	return instanceSource{inst: inst, schemaName: schemaName}
+}
+
+type instanceSource struct {
+	inst       *Instance
+	schemaName string
+}
+
+func (is instanceSource) load(*compareConfig) (*Schema, error) {
+	s, err := is.inst.Schema(is.schemaName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (is instanceSource) String() string {
+	return fmt.Sprintf("schema %s on %s", is.schemaName, is.inst)
+}
+
+// DirSource returns a Source for the CREATE statements in the *.sql files of
+// a directory. Subdirectories and .skeema option files are not examined.
+// Compare must be called with WithWorkspace in order to use this Source.
+func DirSource(dirPath string) Source {
+	return dirSource(dirPath)
+}
+
+type dirSource string
+
+func (ds dirSource) load(cfg *compareConfig) (*Schema, error) {
+	filePaths, err := filepath.Glob(filepath.Join(string(ds), "*.sql"))
+	if err != nil {
+		return nil, err
+	} else if len(filePaths) == 0 {
+		if _, err := os.Stat(string(ds)); err != nil {
+			return nil, err
+		}
+	}
+	logicalSchema := fs.NewLogicalSchema()
+	for _, filePath := range filePaths {
+		stmts, err := tengo.ParseStatementsInFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range stmts {
+			if err := logicalSchema.AddStatement(stmt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cfg.execLogicalSchema(logicalSchema)
+}
+
+func (ds dirSource) String() string {
+	return "directory " + string(ds)
+}
+
+// SnapshotSource returns a Source for the named schema in a snapshot file,
+// such as one written by Skeema's snapshot package. Compare must be called
+// with WithWorkspace in order to use this Source.
+func SnapshotSource(filePath, schemaName string) Source {
+	return snapshotSource{filePath: filePath, schemaName: schemaName}
+}
+
+type snapshotSource struct {
+	filePath   string
+	schemaName string
+}
+
+func (ss snapshotSource) load(cfg *compareConfig) (*Schema, error) {
+	snap, err := snapshot.ReadFile(ss.filePath)
+	if err != nil {
+		return nil, err
+	}
+	snapSchema := snap.Schemas[ss.schemaName]
+	if snapSchema == nil {
+		return nil, fmt.Errorf("schema %s not found in snapshot", ss.schemaName)
+	}
+	logicalSchema := fs.NewLogicalSchema()
+	logicalSchema.CharSet = snapSchema.CharSet
+	logicalSchema.Collation = snapSchema.Collation
+	keys := make([]string, 0, len(snapSchema.Objects))
+	for keyStr := range snapSchema.Objects {
+		keys = append(keys, keyStr)
+	}
+	sort.Strings(keys)
+	for _, keyStr := range keys {
+		stmts, err := tengo.ParseStatementsInString(snap.Blobs[snapSchema.Objects[keyStr]])
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range stmts {
+			if err := logicalSchema.AddStatement(stmt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cfg.execLogicalSchema(logicalSchema)
+}
+
+func (ss snapshotSource) String() string {
+	return fmt.Sprintf("schema %s in snapshot %s", ss.schemaName, ss.filePath)
+}
+
+// execLogicalSchema runs the CREATEs in logicalSchema in a temporary schema on
+// the configured workspace instance, and returns the introspected result.
+func (cfg *compareConfig) execLogicalSchema(logicalSchema *fs.LogicalSchema) (*Schema, error) {
+	if cfg.workspaceInstance == nil {
+		return nil, errors.New("WithWorkspace option is required for this source")
+	}
+	opts := workspace.Options{
+		Type:          workspace.TypeTempSchema,
+		CleanupAction: workspace.CleanupActionDrop,
+		Instance:      cfg.workspaceInstance,
+		SchemaName:    cfg.workspaceSchema,
+		NameCaseMode:  cfg.workspaceInstance.NameCaseMode(),
+		LockTimeout:   30 * time.Second,
+		Concurrency:   10,
+	}
+	wsSchema, err := workspace.ExecLogicalSchema(logicalSchema, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(wsSchema.Failures) > 0 {
+		return nil, wsSchema.Failures[0]
+	}
+	return wsSchema.Schema, nil
+}
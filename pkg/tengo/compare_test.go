@@ -0,0 +1,51 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	newTable := func(name string) *Table {
+		return &Table{
+			Name:            name,
+			Engine:          "InnoDB",
+			CharSet:         "utf8mb4",
+			CreateStatement: "CREATE TABLE `" + name + "` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		}
+	}
+	from := &Schema{Name: "foo", Tables: []*Table{newTable("dropme"), newTable("tmp_ignored")}}
+	to := &Schema{Name: "foo", Tables: []*Table{newTable("addme")}}
+
+	result, err := Compare(SchemaSource(from), SchemaSource(to), WithIgnore(ObjectTypeTable, "^tmp_"))
+	if err != nil {
+		t.Fatalf("Unexpected error from Compare: %v", err)
+	}
+	if len(result.Statements) != 1 || !strings.HasPrefix(result.Statements[0], "CREATE TABLE `addme`") {
+		t.Errorf("Unexpected Statements: %v", result.Statements)
+	}
+	if len(result.Unsafe) != 1 || result.Unsafe[0].Name != "dropme" {
+		t.Errorf("Unexpected Unsafe: %v", result.Unsafe)
+	}
+	if result.Empty() {
+		t.Error("Expected result to be non-empty")
+	}
+
+	result, err = Compare(SchemaSource(from), SchemaSource(to), WithAllowUnsafe(true))
+	if err != nil {
+		t.Fatalf("Unexpected error from Compare: %v", err)
+	}
+	if len(result.Statements) != 3 || len(result.Unsafe) != 0 {
+		t.Errorf("Unexpected result with WithAllowUnsafe: %v, %v", result.Statements, result.Unsafe)
+	}
+
+	if result, err = Compare(SchemaSource(to), SchemaSource(to)); err != nil || !result.Empty() {
+		t.Errorf("Expected empty result comparing identical schemas, instead found %+v, %v", result, err)
+	}
+	if _, err := Compare(SchemaSource(from), SchemaSource(to), WithIgnore(ObjectTypeTable, "[")); err == nil {
+		t.Error("Expected error from invalid ignore pattern, but err was nil")
+	}
+	if _, err := Compare(SchemaSource(from), DirSource(t.TempDir())); err == nil {
+		t.Error("Expected error from DirSource without WithWorkspace, but err was nil")
+	}
+}
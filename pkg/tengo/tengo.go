@@ -0,0 +1,233 @@
+// Package tengo is the public API for Skeema's schema introspection and
+// diff'ing library. It exposes a subset of the types and functions in Skeema's
+// internal tengo package, which otherwise cannot be imported by other modules.
+//
+// This package makes no stability guarantee. Most of its types are aliases to
+// internal types, so their fields and methods change whenever the internal
+// package changes, including in minor and patch releases of Skeema. Callers
+// should pin a specific Skeema version. APIVersion is bumped whenever the
+// exported surface changes (including that of the aliased types), with a new
+// major version if an identifier is removed or changed incompatibly, so it may
+// be used to detect such changes when upgrading.
+package tengo
+
+import (
+	"github.com/skeema/skeema/internal/tengo"
+)
+
+// APIVersion is the version of this package's exported surface. See the
+// package documentation for how it relates to compatibility.
+const APIVersion = "1.10.0"
+
+// Core object types. These are aliases, so values may be passed freely to and
+// from any function in this package. The fields and methods listed for some
+// types are the ones most commonly needed, not a compatibility promise.
+type (
+	// Instance represents a single database server. Obtain one via NewInstance.
+	// Commonly used methods: String, Flavor, Schemas, SchemasByName, Schema, HasSchema,
+	// SchemaNames, CanConnect, CloseAll.
+	Instance = tengo.Instance
+
+	// Schema represents a database schema, including its tables, routines,
+	// views, and triggers.
+	// Commonly used methods: Table, TablesByName, Objects, Diff.
+	Schema = tengo.Schema
+
+	// Table represents a single database table.
+	// Commonly used fields: Name, Engine, CharSet, Collation, Comment, Columns,
+	// PrimaryKey, SecondaryIndexes, ForeignKeys, CreateStatement, UnsupportedDDL.
+	Table = tengo.Table
+
+	// Column represents a single column of a table.
+	Column = tengo.Column
+
+	// Index represents a single index of a table.
+	Index = tengo.Index
+
+	// ForeignKey represents a single foreign key constraint of a table.
+	ForeignKey = tengo.ForeignKey
+
+	// Check represents a single check constraint of a table.
+	Check = tengo.Check
+
+	// Partition represents a single partition of a partitioned table.
+	Partition = tengo.Partition
+
+	// Routine represents a stored procedure or function.
+	Routine = tengo.Routine
+
+	// View represents a view.
+	// Commonly used fields: Name, Algorithm, Definer, SecurityType, CheckOption, Body,
+	// CreateStatement.
+	View = tengo.View
+
+	// Trigger represents a trigger on a table.
+	// Commonly used fields: Name, Table, Timing, Event, Follows, Definer, Body,
+	// CreateStatement.
+	Trigger = tengo.Trigger
+
+	// Flavor represents a database server vendor and version.
+	Flavor = tengo.Flavor
+
+	// ObjectKey identifies an object by type and name within a schema.
+	ObjectKey = tengo.ObjectKey
+
+	// ObjectType enumerates the types of objects in a schema.
+	ObjectType = tengo.ObjectType
+
+	// SchemaDiff represents the set of differences between two schemas.
+	// Commonly used methods: ObjectDiffs, String.
+	SchemaDiff = tengo.SchemaDiff
+
+	// ObjectDiff represents the difference of a single object, and can generate
+	// the DDL to apply it via its Statement method.
+	ObjectDiff = tengo.ObjectDiff
+
+	// StatementModifiers adjusts the DDL generated by ObjectDiff.Statement.
+	StatementModifiers = tengo.StatementModifiers
+
+	// Visitor holds typed callbacks for use with Walk.
+	Visitor = tengo.Visitor
+
+	// TableBuilder constructs a Table in code. Obtain one via NewTableBuilder.
+	// Commonly used methods: Engine, CharSet, Comment, Column, PrimaryKey, Index,
+	// UniqueIndex, ForeignKey, Check, Build.
+	TableBuilder = tengo.TableBuilder
+
+	// ColumnOption adjusts a column added by TableBuilder.Column.
+	ColumnOption = tengo.ColumnOption
+)
+
+// Syntax tree types returned by ParseCreate. Each node type has a Pos field
+// indicating its location in the input.
+type (
+	Node              = tengo.Node
+	Pos               = tengo.Pos
+	CreateTableNode   = tengo.CreateTableNode
+	ColumnDefNode     = tengo.ColumnDefNode
+	IndexDefNode      = tengo.IndexDefNode
+	ForeignKeyDefNode = tengo.ForeignKeyDefNode
+	CheckDefNode      = tengo.CheckDefNode
+	CreateRoutineNode = tengo.CreateRoutineNode
+	ParamNode         = tengo.ParamNode
+)
+
+// SkipChildren may be returned by Visitor.VisitTable to skip visiting the
+// table's columns, indexes, and other children.
+var SkipChildren = tengo.SkipChildren
+
+// Object types.
+const (
+	ObjectTypeTable   = tengo.ObjectTypeTable
+	ObjectTypeProc    = tengo.ObjectTypeProc
+	ObjectTypeFunc    = tengo.ObjectTypeFunc
+	ObjectTypeView    = tengo.ObjectTypeView
+	ObjectTypeTrigger = tengo.ObjectTypeTrigger
+)
+
+// NewInstance returns a pointer to a new Instance corresponding to the
+// supplied driver and DSN. Currently only the "mysql" driver is supported.
+// No connection is established until a method requiring one is called.
+func NewInstance(driver, dsn string) (*Instance, error) {
+	return tengo.NewInstance(driver, dsn)
+}
+
+// NewSchemaDiff computes the set of differences between two schemas. Either
+// argument may be nil, representing a nonexistent schema.
+func NewSchemaDiff(from, to *Schema) *SchemaDiff {
+	return tengo.NewSchemaDiff(from, to)
+}
+
+// ParseFlavor returns a Flavor value based on the supplied string in format
+// "vendor:major.minor" or "vendor:major.minor.patch", for example
+// "mysql:8.0" or "mariadb:10.11".
+func ParseFlavor(s string) Flavor {
+	return tengo.ParseFlavor(s)
+}
+
+// IsUnsupportedDiff returns true if err indicates that an ObjectDiff's DDL
+// could not be generated because the object uses unsupported features.
+func IsUnsupportedDiff(err error) bool {
+	return tengo.IsUnsupportedDiff(err)
+}
+
+// IsForbiddenDiff returns true if err indicates that an ObjectDiff's DDL was
+// not generated because it would be destructive and StatementModifiers did
+// not permit unsafe changes.
+func IsForbiddenDiff(err error) bool {
+	return tengo.IsForbiddenDiff(err)
+}
+
+// Walk traverses the tables (including their columns, indexes, foreign keys,
+// checks, and partitions) and routines of s, calling the corresponding
+// callbacks of v.
+func Walk(s *Schema, v Visitor) error {
+	return tengo.Walk(s, v)
+}
+
+// ParseCreate parses a single CREATE TABLE, CREATE PROCEDURE, or CREATE
+// FUNCTION statement, returning either a *CreateTableNode or a
+// *CreateRoutineNode.
+func ParseCreate(sql string) (Node, error) {
+	return tengo.ParseStatementInString(sql).AST()
+}
+
+// NewTableBuilder returns a TableBuilder for a table with the supplied name,
+// defaulting to the InnoDB storage engine and utf8mb4 character set. Call its
+// Build method to validate the table and render its CREATE TABLE statement.
+func NewTableBuilder(name string) *TableBuilder {
+	return tengo.NewTableBuilder(name)
+}
+
+// NotNull marks a column as NOT NULL.
+func NotNull() ColumnOption {
+	return tengo.NotNull()
+}
+
+// AutoIncrement marks a column as AUTO_INCREMENT, which implies NotNull.
+func AutoIncrement() ColumnOption {
+	return tengo.AutoIncrement()
+}
+
+// DefaultExpr sets a column's default to an expression used verbatim.
+func DefaultExpr(expr string) ColumnOption {
+	return tengo.DefaultExpr(expr)
+}
+
+// DefaultString sets a column's default to a string literal, quoting it.
+func DefaultString(value string) ColumnOption {
+	return tengo.DefaultString(value)
+}
+
+// OnUpdate sets a column's ON UPDATE expression.
+func OnUpdate(expr string) ColumnOption {
+	return tengo.OnUpdate(expr)
+}
+
+// Generated makes a column a VIRTUAL or STORED generated column.
+func Generated(expr string, virtual bool) ColumnOption {
+	return tengo.Generated(expr, virtual)
+}
+
+// ColumnCharSet overrides the table's character set and collation for a
+// textual column.
+func ColumnCharSet(charSet, collation string) ColumnOption {
+	return tengo.ColumnCharSet(charSet, collation)
+}
+
+// ColumnComment sets a column's comment.
+func ColumnComment(comment string) ColumnOption {
+	return tengo.ColumnComment(comment)
+}
+
+// Invisible marks a column as invisible.
+func Invisible() ColumnOption {
+	return tengo.Invisible()
+}
+
+// AlterStatement returns an ALTER TABLE statement transforming from into to,
+// or an empty string if the tables are identical. Typically both tables are
+// obtained from TableBuilder.Build with the same flavor.
+func AlterStatement(from, to *Table, mods StatementModifiers) (string, error) {
+	return tengo.AlterStatement(from, to, mods)
+}
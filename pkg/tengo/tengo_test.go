@@ -0,0 +1,74 @@
+package tengo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublicAPI(t *testing.T) {
+	if _, err := NewInstance("postgres", "whatever"); err == nil {
+		t.Error("Expected NewInstance with unsupported driver to return an error")
+	}
+	if inst, err := NewInstance("mysql", "root:pw@tcp(127.0.0.1:3306)/"); err != nil || inst.String() != "127.0.0.1:3306" {
+		t.Errorf("Unexpected result from NewInstance: %v, %v", inst, err)
+	}
+	if flavor := ParseFlavor("mysql:8.0"); !flavor.Known() {
+		t.Errorf("Expected ParseFlavor to return a known flavor, instead found %s", flavor)
+	}
+
+	to := &Schema{
+		Name: "foo",
+		Tables: []*Table{{
+			Name:            "bar",
+			CreateStatement: "CREATE TABLE `bar` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		}},
+	}
+	diff := NewSchemaDiff(&Schema{Name: "foo"}, to)
+	objDiffs := diff.ObjectDiffs()
+	if len(objDiffs) != 1 {
+		t.Fatalf("Expected 1 ObjectDiff, instead found %d", len(objDiffs))
+	}
+	stmt, err := objDiffs[0].Statement(StatementModifiers{})
+	if err != nil || !strings.HasPrefix(stmt, "CREATE TABLE `bar`") {
+		t.Errorf("Unexpected result from Statement: %q, %v", stmt, err)
+	}
+	if key := objDiffs[0].ObjectKey(); key != (ObjectKey{Type: ObjectTypeTable, Name: "bar"}) {
+		t.Errorf("Unexpected ObjectKey: %s", key)
+	}
+}
+
+func TestParseCreate(t *testing.T) {
+	node, err := ParseCreate("CREATE TABLE foo (id int NOT NULL, PRIMARY KEY (id))")
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseCreate: %v", err)
+	}
+	if ct, ok := node.(*CreateTableNode); !ok || ct.Name != "foo" || len(ct.Columns) != 1 || len(ct.Indexes) != 1 {
+		t.Errorf("Unexpected result from ParseCreate: %+v", node)
+	}
+	if _, err := ParseCreate("SELECT 1"); err == nil {
+		t.Error("Expected error from ParseCreate with non-CREATE input, but err was nil")
+	}
+}
+
+func TestTableBuilder(t *testing.T) {
+	flavor := ParseFlavor("mysql:8.0")
+	b := NewTableBuilder("users").
+		Column("id", "bigint unsigned", AutoIncrement()).
+		Column("email", "varchar(100)", NotNull(), ColumnComment("login")).
+		PrimaryKey("id")
+	from, err := b.Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	to, err := b.UniqueIndex("email", "email").Build(flavor)
+	if err != nil {
+		t.Fatalf("Unexpected error from Build: %v", err)
+	}
+	stmt, err := AlterStatement(from, to, StatementModifiers{Flavor: flavor})
+	if err != nil || stmt != "ALTER TABLE `users` ADD UNIQUE KEY `email` (`email`)" {
+		t.Errorf("Unexpected result from AlterStatement: %q, %v", stmt, err)
+	}
+	if _, err := NewTableBuilder("bad").Build(flavor); err == nil {
+		t.Error("Expected error building table without columns, but err was nil")
+	}
+}
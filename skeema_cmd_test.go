@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -471,6 +472,58 @@ func (s SkeemaIntegrationSuite) TestDiffHandler(t *testing.T) {
 	}
 }
 
+func (s SkeemaIntegrationSuite) TestCompareHandler(t *testing.T) {
+	s.handleCommand(t, CodeSuccess, ".", "skeema init --dir mydb -h %s -P %d", s.d.Instance.Host, s.d.Instance.Port)
+
+	// Comparing an environment to itself should yield no differences
+	s.handleCommand(t, CodeSuccess, "mydb", "skeema compare production production")
+
+	// Define a staging environment on the same instance, but with the product dir
+	// mapping to the analytics schema instead
+	cfg := s.handleCommand(t, CodeSuccess, ".", "skeema add-environment --host %s -P %d --dir mydb staging", s.d.Instance.Host, s.d.Instance.Port)
+	file := getOptionFile(t, "mydb/product", cfg)
+	file.SetOptionValue("staging", "schema", "analytics")
+	if err := file.Write(true); err != nil {
+		t.Fatalf("Unable to write %s: %v", file.Path(), err)
+	}
+	s.handleCommand(t, CodeDifferencesFound, "mydb", "skeema compare production staging")
+	s.handleCommand(t, CodeDifferencesFound, "mydb", "skeema compare staging production --json")
+
+	// Confirm that nothing was actually changed
+	s.handleCommand(t, CodeSuccess, ".", "skeema diff")
+}
+
+func (s SkeemaIntegrationSuite) TestCodegenHandler(t *testing.T) {
+	s.handleCommand(t, CodeSuccess, ".", "skeema init --dir mydb -h %s -P %d", s.d.Instance.Host, s.d.Instance.Port)
+
+	// Without codegen-dir configured, nothing is generated
+	s.handleCommand(t, CodeSuccess, "mydb", "skeema codegen")
+	if _, err := os.Stat("mydb/product/models"); !os.IsNotExist(err) {
+		t.Fatalf("Expected models dir to not exist, but stat returned %v", err)
+	}
+	s.handleCommand(t, CodeBadConfig, "mydb", "skeema codegen --codegen-dir=models --codegen-nullable=whatever")
+	s.handleCommand(t, CodeBadConfig, "mydb", "skeema codegen --codegen-dir=models --codegen-tags=db,xml")
+
+	cfg := s.handleCommand(t, CodeSuccess, "mydb", "skeema codegen --codegen-dir=models --codegen-tags=db,gorm")
+	contents := fs.ReadTestFile(t, "mydb/product/models/product.go")
+	if !strings.Contains(contents, "package models\n") || !strings.Contains(contents, "type Posts struct {") {
+		t.Errorf("Unexpected generated code:\n%s", contents)
+	}
+
+	// Pull should regenerate the code once codegen-dir is in the option file
+	file := getOptionFile(t, "mydb/product", cfg)
+	file.SetOptionValue("", "codegen-dir", "models")
+	if err := file.Write(true); err != nil {
+		t.Fatalf("Unable to write %s: %v", file.Path(), err)
+	}
+	s.dbExec(t, "product", "CREATE TABLE widgets (id int unsigned NOT NULL PRIMARY KEY)")
+	s.handleCommand(t, CodeSuccess, "mydb", "skeema pull")
+	contents = fs.ReadTestFile(t, "mydb/product/models/product.go")
+	if !strings.Contains(contents, "type Widgets struct {") {
+		t.Errorf("Expected pull to regenerate code, but new table not found:\n%s", contents)
+	}
+}
+
 func (s SkeemaIntegrationSuite) TestPushHandler(t *testing.T) {
 	s.handleCommand(t, CodeSuccess, ".", "skeema init --dir mydb -h %s -P %d", s.d.Instance.Host, s.d.Instance.Port)
 
@@ -1720,3 +1773,70 @@ func (s SkeemaIntegrationSuite) TestCharsetCollate(t *testing.T) {
 	// Diff should report differences found but not fatal error
 	s.handleCommand(t, CodeDifferencesFound, ".", "skeema diff --skip-lint")
 }
+
+func (s SkeemaIntegrationSuite) TestUndoLastPush(t *testing.T) {
+	s.reinitAndVerifyFiles(t, "", "")
+
+	// Without any previous push, there's nothing to undo
+	s.handleCommand(t, CodeSuccess, ".", "skeema undo-last-push")
+
+	// Snapshots are opt-in, so configure snapshot-dir for the remainder of the
+	// test
+	fs.WriteTestFile(t, "mydb/product/.skeema", fs.ReadTestFile(t, "mydb/product/.skeema")+"snapshot-dir=.skeema-snapshots\n")
+
+	// Push a new column and a new table, and confirm a snapshot was saved
+	contents := fs.ReadTestFile(t, "mydb/product/posts.sql")
+	fs.WriteTestFile(t, "mydb/product/posts.sql", strings.Replace(contents, "PRIMARY KEY", "foo int,\nPRIMARY KEY", 1))
+	fs.WriteTestFile(t, "mydb/product/newtable.sql", "CREATE TABLE newtable (id int PRIMARY KEY);\n")
+	s.handleCommand(t, CodeSuccess, ".", "skeema push")
+	s.assertTableExists(t, "product", "posts", "foo")
+	s.assertTableExists(t, "product", "newtable", "")
+	if matches, _ := filepath.Glob("mydb/product/.skeema-snapshots/*.json.gz"); len(matches) != 1 {
+		t.Fatalf("Expected 1 snapshot file after push, instead found %d", len(matches))
+	}
+
+	// Undo should drop the new table and column, but dropping requires
+	// --allow-unsafe as usual
+	s.handleCommand(t, CodeFatalError, ".", "skeema undo-last-push")
+	s.assertTableExists(t, "product", "newtable", "")
+	s.handleCommand(t, CodeSuccess, ".", "skeema undo-last-push --allow-unsafe")
+	s.assertTableMissing(t, "product", "posts", "foo")
+	s.assertTableMissing(t, "product", "newtable", "")
+	s.handleCommand(t, CodeDifferencesFound, ".", "skeema diff")
+
+	// Undoing again re-applies the original push
+	s.handleCommand(t, CodeSuccess, ".", "skeema undo-last-push")
+	s.assertTableExists(t, "product", "posts", "foo")
+	s.assertTableExists(t, "product", "newtable", "")
+	s.handleCommand(t, CodeSuccess, ".", "skeema diff")
+}
+
+func (s SkeemaIntegrationSuite) TestFixtureMasking(t *testing.T) {
+	s.reinitAndVerifyFiles(t, "", "")
+	s.dbExec(t, "product", "INSERT INTO users (id, name, credits) VALUES (1, 'alice', 25.50), (2, 'bob', 3.00)")
+
+	// Pulling with masking should obscure the values in the CSV
+	opts := "--reference-tables='^users$' --fixture-mask=users.name=hash,users.credits=null"
+	s.handleCommand(t, CodeSuccess, ".", "skeema pull "+opts)
+	contents := fs.ReadTestFile(t, "mydb/product/users.csv")
+	if strings.Contains(contents, "alice") || strings.Contains(contents, "25.50") {
+		t.Fatalf("Expected values to be masked in users.csv, instead found contents:\n%s", contents)
+	}
+
+	// Pushing the masked CSV back to the same instance must not overwrite the
+	// real values of masked columns
+	s.handleCommand(t, CodeSuccess, ".", "skeema push "+opts)
+	db, err := s.d.CachedConnectionPool("product", "")
+	if err != nil {
+		t.Fatalf("Unable to connect to DockerizedInstance: %s", err)
+	}
+	var name, credits string
+	if err := db.QueryRow("SELECT name, credits FROM users WHERE id = 1").Scan(&name, &credits); err != nil {
+		t.Fatalf("Unexpected error querying users: %v", err)
+	} else if name != "alice" || credits != "25.50" {
+		t.Errorf("Expected masked columns to retain original values after push, instead found name=%q credits=%q", name, credits)
+	}
+
+	// Loading is refused if a primary key column is masked
+	s.handleCommand(t, CodeFatalError, ".", "skeema push --reference-tables='^users$' --fixture-mask=users.id=hash")
+}